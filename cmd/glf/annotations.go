@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/igusev/glf/internal/annotations"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/paths"
+	"github.com/igusev/glf/internal/search"
+)
+
+// syncAnnotations fetches the team-shared annotations.yaml (annotations.project) into the cache
+// directory; returns false when no shared file is configured
+// A file that doesn't parse is rejected, so a broken commit doesn't replace the last good copy
+func syncAnnotations(cfg *config.Config, client *gitlab.Client) (bool, error) {
+	if cfg.Annotations.Project == "" {
+		return false, nil
+	}
+	data, err := client.FetchRawFile(cfg.Annotations.Project, cfg.Annotations.GetPath(), cfg.Annotations.Ref)
+	if err != nil {
+		return false, err
+	}
+	if _, err := annotations.Parse(data); err != nil {
+		return false, fmt.Errorf("%s of %s: %w", cfg.Annotations.GetPath(), cfg.Annotations.Project, err)
+	}
+	if err := os.WriteFile(paths.AnnotationsPath(cfg.Cache.Dir), data, 0o600); err != nil {
+		return false, fmt.Errorf("failed to cache annotations: %w", err)
+	}
+	return true, nil
+}
+
+// loadAnnotations loads the project labels for label: filters, the TUI and JSON output:
+// the shared file fetched by sync, then the local annotations file
+// A broken file is reported and skipped; labels never stop a search
+func loadAnnotations(cfg *config.Config) {
+	files := []string{cfg.Annotations.GetFile()}
+	if cfg.Annotations.Project != "" {
+		files = append([]string{paths.AnnotationsPath(cfg.Cache.Dir)}, files...)
+	}
+	a, err := annotations.Load(files...)
+	if err != nil {
+		logger.Warn("Failed to load annotations: %v", err)
+	}
+	search.SetAnnotations(a)
+}
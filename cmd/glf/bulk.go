@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/igusev/glf/internal/clipboard"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/tui"
+)
+
+// clipboardCopier copies clone commands of marked projects (Alt+C in the TUI)
+var clipboardCopier = clipboard.New()
+
+// runBulkAction applies the bulk action chosen in the TUI to the marked projects
+// Every action prints its result on stdout too, so it can be piped or copied by hand
+func runBulkAction(w io.Writer, cfg *config.Config, action tui.BulkAction, projects []model.Project) error {
+	switch action {
+	case tui.BulkOpen, tui.BulkPrintURLs:
+		for _, project := range projects {
+			projectURL, err := projectPageURL(cfg, project.Path, targetName)
+			if err != nil {
+				return withExitCode(exitCodeUsage, err)
+			}
+			if action == tui.BulkOpen {
+				logger.Debug("Opening browser with URL: %s", projectURL)
+				if err := openBrowser(projectURL); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to open browser: %v\n", err)
+				}
+			}
+			fmt.Fprintln(w, projectURL)
+		}
+
+	case tui.BulkCopyClone:
+		commands := cloneCommands(cfg.GitLab.URL, projects)
+		if err := clipboardCopier.Copy(commands); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to copy to the clipboard: %v\n", err)
+		} else {
+			logger.Success("Copied %d clone commands to the clipboard", len(projects))
+		}
+		fmt.Fprint(w, commands)
+	}
+	return nil
+}
+
+// cloneCommands returns a "git clone" line per project (HTTPS clone URLs, derived from the instance URL)
+func cloneCommands(baseURL string, projects []model.Project) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	var b strings.Builder
+	for _, project := range projects {
+		fmt.Fprintf(&b, "git clone %s/%s.git\n", baseURL, strings.Trim(project.Path, "/"))
+	}
+	return b.String()
+}
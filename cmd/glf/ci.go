@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/igusev/glf/internal/logger"
+)
+
+// Exit codes (stable, documented for automation)
+const (
+	exitCodeOK         = 0 // Success
+	exitCodeError      = 1 // Unspecified failure
+	exitCodeUsage      = 2 // Invalid flag combination or an interactive feature requested in --non-interactive mode
+	exitCodeNoResults  = 3 // Query matched no projects
+	exitCodeNoCache    = 4 // Cache is empty or must be rebuilt, and automatic sync is disabled
+	exitCodeSyncFailed = 5 // Explicit or automatic sync failed
+	exitCodeConfig     = 6 // Configuration missing or invalid
+)
+
+// Error codes reported as "code" in JSON errors (stable, documented for automation)
+// They are finer-grained than exit codes: index and search failures both exit with 1
+const (
+	errorCodeError      = "error"       // Unspecified failure
+	errorCodeUsage      = "usage"       // exitCodeUsage
+	errorCodeNoResults  = "no_results"  // exitCodeNoResults
+	errorCodeNoCache    = "no_cache"    // exitCodeNoCache
+	errorCodeSyncFailed = "sync_failed" // exitCodeSyncFailed
+	errorCodeConfig     = "config"      // exitCodeConfig
+	errorCodeIndex      = "index"       // Local index could not be opened or read
+	errorCodeSearch     = "search"      // Search query failed
+)
+
+// exitErrorCodes maps exit codes to the error code reported when no specific one is set
+var exitErrorCodes = map[int]string{
+	exitCodeUsage:      errorCodeUsage,
+	exitCodeNoResults:  errorCodeNoResults,
+	exitCodeNoCache:    errorCodeNoCache,
+	exitCodeSyncFailed: errorCodeSyncFailed,
+	exitCodeConfig:     errorCodeConfig,
+}
+
+// exitError carries a specific process exit code and, optionally, a JSON error code
+// A nil err exits with the code without printing anything (output was already written)
+type exitError struct {
+	code      int
+	errorCode string // Overrides the error code derived from code ("" = derived)
+	err       error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode wraps err so that main exits with code
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// withErrorCode wraps err with a specific JSON error code and exit code
+func withErrorCode(errorCode string, code int, err error) error {
+	return &exitError{code: code, errorCode: errorCode, err: err}
+}
+
+// hintError attaches a suggestion for fixing the error (shown after it, "hint" in JSON)
+type hintError struct {
+	err  error
+	hint string
+}
+
+func (e *hintError) Error() string {
+	return e.err.Error()
+}
+
+func (e *hintError) Unwrap() error {
+	return e.err
+}
+
+// withHint attaches a hint on how to fix err
+func withHint(err error, hint string) error {
+	return &hintError{err: err, hint: hint}
+}
+
+// errorHint returns the hint attached to err ("" if none)
+func errorHint(err error) string {
+	var hintErr *hintError
+	if errors.As(err, &hintErr) {
+		return hintErr.hint
+	}
+	return ""
+}
+
+// errorCodeFor returns the JSON error code for an error returned by the root command
+func errorCodeFor(err error) string {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		if exitErr.errorCode != "" {
+			return exitErr.errorCode
+		}
+		if errorCode, ok := exitErrorCodes[exitErr.code]; ok {
+			return errorCode
+		}
+	}
+	return errorCodeError
+}
+
+// newJSONError builds the JSON error object for an error returned by the root command
+func newJSONError(err error) JSONError {
+	return JSONError{
+		Error:   err.Error(),
+		Code:    errorCodeFor(err),
+		Message: err.Error(),
+		Hint:    errorHint(err),
+		TraceID: logger.TraceID(),
+	}
+}
+
+// jsonErrorsRequested reports whether errors must be printed as JSON (--json or --ci)
+// The command line is checked as well, as flag parsing stops at the first invalid flag
+func jsonErrorsRequested() bool {
+	return jsonOutput || ciMode || slices.Contains(os.Args[1:], "--json") || slices.Contains(os.Args[1:], "--ci")
+}
+
+// exitCodeFor returns the process exit code for an error returned by the root command
+func exitCodeFor(err error) int {
+	if err == nil {
+		return exitCodeOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return exitCodeError
+}
+
+// applyCIMode expands --ci into the individual guarantees it stands for
+// --ci = --no-sync + --non-interactive + --json
+func applyCIMode() {
+	if !ciMode {
+		return
+	}
+	noSync = true
+	nonInteractive = true
+	jsonOutput = true
+	// --go in CI returns the single result it would have opened
+	if autoGo {
+		limitResults = 1
+	}
+}
+
+// readOnlyCache is set when cache.dir is a shared read-only cache (cache.read_only, or not writable)
+var readOnlyCache bool
+
+// autoSyncDisabled reports whether glf must never start a sync on its own
+func autoSyncDisabled() bool {
+	return noSync || offline || readOnlyCache
+}
+
+// errReadOnlyCache is returned by commands that would write to a shared read-only cache
+func errReadOnlyCache(cacheDir string) error {
+	return withExitCode(exitCodeSyncFailed, fmt.Errorf("cache directory %s is read-only (shared cache); sync it where it is writable", cacheDir))
+}
+
+// requireInteractive returns a usage error when an interactive feature is requested in --non-interactive mode
+func requireInteractive(feature string) error {
+	if !nonInteractive {
+		return nil
+	}
+	return withExitCode(exitCodeUsage, fmt.Errorf("%s requires a terminal; not available with --non-interactive/--ci", feature))
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Subcommands are thin wrappers over the mode flags, so "glf sync --full" and "glf --sync --full"
+// take the same path. All flags stay global (persistent) and keep working without a subcommand.
+// With script output (--json, --format, --plain) they are not dispatched: scripts written before
+// the subcommands existed keep searching for "sync", "open", "history" and "config"
+
+// runWith returns a RunE that enables a mode flag and runs the root command
+func runWith(flag *bool) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		*flag = true
+		return runSearch(cmd, args)
+	}
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Synchronize projects cache (same as --sync)",
+	Long: `Fetch projects from GitLab into the local cache.
+Incremental by default; a full sync runs weekly to remove deleted projects.
+
+Examples:
+  glf sync              # Incremental sync
+  glf sync --full       # Full sync
+  glf sync --starred    # Only refresh starred and member projects
+  glf sync --dry-run    # Report what a sync would change
+  glf --sync --json     # Sync statistics as JSON (no subcommands with --json)`,
+	Args: cobra.NoArgs,
+	RunE: runWith(&doSync),
+}
+
+var openCmd = &cobra.Command{
+	Use:   "open [query...]",
+	Short: "Open the first result in browser (same as --go)",
+	Long: `Search projects and open the best match in the browser.
+
+Examples:
+  glf open api          # Open the best match for "api"
+  glf open api -t mrs   # Open its merge requests
+  glf open .            # Open the current Git repository`,
+	RunE: runWith(&autoGo),
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history [query]",
+	Short: "Show selection history with scores (same as --history)",
+	Long: `Show the most selected projects with their history scores.
+
+Examples:
+  glf history                       # Most selected projects
+  glf history --query api           # Selections made for the query "api"
+  glf history --explain group/api   # How a project's score is computed
+  glf history top                   # Most frequent queries
+  glf history remap old/path new/path
+  glf history clear`,
+	RunE: runWith(&showHistory),
+}
+
+var historyTopCmd = &cobra.Command{
+	Use:   "top",
+	Short: "List the most frequently used search queries (same as --top-queries)",
+	Args:  cobra.NoArgs,
+	RunE:  runWith(&topQueries),
+}
+
+var historyRemapCmd = &cobra.Command{
+	Use:   "remap OLD_PATH NEW_PATH",
+	Short: "Move history from a renamed project or group (same as --remap-history)",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runWith(&remapHist),
+}
+
+var historyClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear search history (same as --clear-history)",
+	Args:  cobra.NoArgs,
+	RunE:  runWith(&clearHistory),
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Configure the GitLab connection",
+	Args:  cobra.NoArgs,
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Run interactive configuration wizard (same as --init; --reset starts from scratch)",
+	Args:  cobra.NoArgs,
+	RunE:  runWith(&doInit),
+}
+
+var configLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Sign in with the GitLab OAuth device flow (same as --login)",
+	Args:  cobra.NoArgs,
+	RunE:  runWith(&doLogin),
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the location of config.yaml",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Fprintln(cmd.OutOrStdout(), config.Path())
+	},
+}
+
+// scriptOutputRequested reports whether the command line asks for output meant for scripts
+// (--json, --format or --plain); arguments after "--" are query words
+func scriptOutputRequested(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		name, _, _ := strings.Cut(arg, "=")
+		switch name {
+		case "--json", "--format", "--plain":
+			return true
+		}
+	}
+	return false
+}
+
+// modeCommands returns the subcommands that take over a positional query of the same name
+func modeCommands() []*cobra.Command {
+	return []*cobra.Command{syncCmd, openCmd, historyCmd, configCmd}
+}
+
+// keepQueriesForScripts removes the mode subcommands when script output is requested, so
+// "glf --json sync" searches for "sync" as it did before the subcommands were added
+func keepQueriesForScripts(args []string) {
+	if scriptOutputRequested(args) {
+		rootCmd.RemoveCommand(modeCommands()...)
+	}
+}
+
+func init() {
+	historyCmd.AddCommand(historyTopCmd, historyRemapCmd, historyClearCmd)
+	configCmd.AddCommand(configInitCmd, configLoginCmd, configPathCmd)
+	rootCmd.AddCommand(modeCommands()...)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/igusev/glf/internal/index"
+)
+
+// goCandidates is the number of candidates offered when the best --go match is ambiguous
+const goCandidates = 3
+
+// ambiguousMatch reports whether the best match of a --go query doesn't lead the runner-up
+// by margin (search.go_margin, a fraction of the best score)
+// A pinned best match, a live GitLab result or the exact project path is never ambiguous
+func ambiguousMatch(query string, matches []index.CombinedMatch, margin float64) bool {
+	if margin <= 0 || len(matches) < 2 {
+		return false
+	}
+	best := matches[0]
+	if best.Pinned || best.Remote || strings.EqualFold(strings.Trim(strings.TrimSpace(query), "/"), best.Project.Path) {
+		return false
+	}
+	return best.TotalScore-matches[1].TotalScore < margin*math.Abs(best.TotalScore)
+}
+
+// chooseMatch lists the top candidates of an ambiguous --go query on w and reads the choice
+// Returns the index of the match to open, or -1 to choose in the TUI (Enter or any other answer)
+func chooseMatch(reader *bufio.Reader, w io.Writer, query string, matches []index.CombinedMatch) (int, error) {
+	candidates := matches[:min(goCandidates, len(matches))]
+
+	_, _ = fmt.Fprintf(w, "%q matches several projects about equally well:\n", query)
+	for i, match := range candidates {
+		_, _ = fmt.Fprintf(w, "  %d) %s (score %.1f)\n", i+1, match.Project.Path, match.TotalScore)
+	}
+	_, _ = fmt.Fprintf(w, "Open 1-%d, or press Enter to choose in the TUI: ", len(candidates))
+
+	response, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || response == "") {
+		return 0, fmt.Errorf("failed to read the choice: %w", err)
+	}
+	choice, err := strconv.Atoi(strings.TrimSpace(response))
+	if err != nil || choice < 1 || choice > len(candidates) {
+		return -1, nil
+	}
+	return choice - 1, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/igusev/glf/internal/logger"
+)
+
+// gitlabProbeTimeout bounds the check that a git remote host runs GitLab
+const gitlabProbeTimeout = 3 * time.Second
+
+// setupFromRemote offers the configuration wizard, pre-filled with the GitLab instance the
+// current repository is hosted on, when glf runs without a configuration
+// Returns false when there is nothing to offer (no terminal, no self-hosted GitLab remote)
+// or the user declined
+func setupFromRemote() (bool, error) {
+	if nonInteractive || offline || jsonOutput || formatTemplate != "" || plainOutput || urlsOutput || vimOutput || !stdinIsTerminal() {
+		return false, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false, nil
+	}
+	gitlabURL := discoverGitLabURL(cwd)
+	if gitlabURL == "" {
+		return false, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "glf is not configured yet. This repository is hosted on %s.\nSet up glf for it now? [Y/n]: ", gitlabURL)
+	response, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, nil
+	}
+	response = strings.ToLower(strings.TrimSpace(response))
+	if response != "" && response != "y" && response != responseYes {
+		return false, nil
+	}
+	return true, runConfigWizard(gitlabURL)
+}
+
+// stdinIsTerminal reports whether glf can ask questions on stdin
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// discoverGitLabURL returns the URL of the first self-hosted GitLab instance among the git
+// remotes of dir, or "" when none points at one
+// Public hosts (github.com, gitlab.com, bitbucket.org) are skipped, and other hosts must
+// answer the GitLab API
+func discoverGitLabURL(dir string) string {
+	remotes, err := getGitRemoteURLs(dir)
+	if err != nil {
+		logger.Debug("No git remotes to discover GitLab from: %v", err)
+		return ""
+	}
+	checked := make(map[string]bool)
+	for _, remote := range remotes {
+		baseURL, ok := remoteBaseURL(remote)
+		if !ok || checked[baseURL] {
+			continue
+		}
+		checked[baseURL] = true
+		if isGitLabInstance(baseURL) {
+			return baseURL
+		}
+	}
+	return ""
+}
+
+// getGitRemoteURLs returns the URLs of every git remote of dir, in configuration order
+func getGitRemoteURLs(dir string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cleanDir := filepath.Clean(dir)
+	// #nosec G204 -- Command is hardcoded "git"; cleanDir is sanitized via filepath.Clean
+	cmd := exec.CommandContext(ctx, "git", "-C", cleanDir, "config", "--get-regexp", `^remote\..*\.url$`)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("not a git repository or no remotes configured: %w", err)
+	}
+
+	var remotes []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		// "remote.origin.url git@gitlab.example.com:group/project.git"
+		if _, remoteURL, ok := strings.Cut(line, " "); ok {
+			remotes = append(remotes, strings.TrimSpace(remoteURL))
+		}
+	}
+	return remotes, nil
+}
+
+// remoteBaseURL returns the web URL of the host of a git remote URL
+// SSH remotes map to https on the same host name (their port is the SSH port)
+// Returns false for local remotes and for known public hosts
+func remoteBaseURL(remoteURL string) (string, bool) {
+	var baseURL *url.URL
+	switch {
+	case strings.HasPrefix(remoteURL, "https://") || strings.HasPrefix(remoteURL, "http://"):
+		parsed, err := url.Parse(remoteURL)
+		if err != nil {
+			return "", false
+		}
+		baseURL = &url.URL{Scheme: parsed.Scheme, Host: parsed.Host}
+	case strings.HasPrefix(remoteURL, "ssh://"):
+		parsed, err := url.Parse(remoteURL)
+		if err != nil {
+			return "", false
+		}
+		baseURL = &url.URL{Scheme: "https", Host: parsed.Hostname()}
+	case strings.Contains(remoteURL, "@") && strings.Contains(remoteURL, ":"):
+		// scp-like SSH syntax: git@gitlab.example.com:group/project.git
+		hostPart, _, _ := strings.Cut(remoteURL, ":")
+		_, host, _ := strings.Cut(hostPart, "@")
+		baseURL = &url.URL{Scheme: "https", Host: host}
+	default:
+		return "", false // Local path or file:// remote
+	}
+
+	if baseURL.Hostname() == "" {
+		return "", false
+	}
+	if _, public := publicGitHosts[strings.ToLower(baseURL.Hostname())]; public {
+		return "", false
+	}
+	return baseURL.String(), true
+}
+
+// isGitLabInstance reports whether baseURL answers the GitLab API
+// The version endpoint answers 401 without a token, which is enough to tell GitLab apart
+func isGitLabInstance(baseURL string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), gitlabProbeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/api/v4/version", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logger.Debug("GitLab discovery: %s is not reachable: %v", baseURL, err)
+		return false
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusUnauthorized
+}
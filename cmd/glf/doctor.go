@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/igusev/glf/internal/cache"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/history"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/paths"
+)
+
+// doctorProblem is a problem found by --doctor and the repair offered for it
+type doctorProblem struct {
+	problem string       // What is wrong
+	fix     string       // What the repair does (asked as "<fix>?"); empty if it can't be repaired here
+	repair  func() error // Applies the fix
+}
+
+// doctorCheck inspects one part of the cache: it returns a short status when all is well,
+// or the problem found
+type doctorCheck struct {
+	name string
+	run  func(cfg *config.Config) (string, *doctorProblem)
+}
+
+// doctorChecks run in order, each on the cache as repaired by the checks before it
+var doctorChecks = []doctorCheck{
+	{"Index", checkIndex},
+	{"Sync times", checkSyncTimes},
+	{"History", checkHistory},
+	{"History lock", checkHistoryLock},
+}
+
+// runDoctor handles --doctor: checks the index, sync timestamps, history and lock files, and
+// repairs each problem found after asking (--yes repairs without asking; --non-interactive only reports)
+// Returns an error if problems remain
+func runDoctor(cfg *config.Config, in io.Reader) error {
+	reader := bufio.NewReader(in)
+	fmt.Printf("Checking %s\n\n", cfg.Cache.Dir)
+
+	unfixed := 0
+	for _, check := range doctorChecks {
+		status, problem := check.run(cfg)
+		if problem == nil {
+			fmt.Printf("  ✓ %-14s %s\n", check.name+":", status)
+			continue
+		}
+		fmt.Printf("  ✗ %-14s %s\n", check.name+":", problem.problem)
+
+		if problem.repair == nil {
+			unfixed++
+			continue
+		}
+		confirmed, err := confirmRepair(reader, problem.fix)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			unfixed++
+			continue
+		}
+		if err := problem.repair(); err != nil {
+			fmt.Printf("    ✗ Repair failed: %v\n", err)
+			unfixed++
+			continue
+		}
+		fmt.Println("    ✓ Repaired")
+	}
+
+	fmt.Println()
+	if unfixed > 0 {
+		err := fmt.Errorf("%d problem(s) not repaired", unfixed)
+		if !assumeYes {
+			return withHint(err, "run 'glf --doctor --yes' to repair without prompts")
+		}
+		return err
+	}
+	fmt.Println("✓ No problems left")
+	return nil
+}
+
+// confirmRepair asks whether to apply a repair; --yes confirms every repair, and without a
+// terminal (or with --non-interactive) nothing is repaired
+func confirmRepair(reader *bufio.Reader, fix string) (bool, error) {
+	if assumeYes {
+		fmt.Printf("    %s (--yes)\n", fix)
+		return true, nil
+	}
+	if nonInteractive || !stdinIsTerminal() {
+		fmt.Printf("    Repair: %s\n", fix)
+		return false, nil
+	}
+
+	fmt.Printf("    %s? [y/N]: ", fix)
+	response, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, fmt.Errorf("failed to read response: %w", err)
+	}
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == responseYes, nil
+}
+
+// readOnlyProblem is a problem of a read-only cache, which is repaired where the cache is synced
+func readOnlyProblem(problem string) *doctorProblem {
+	return &doctorProblem{problem: problem + " (read-only cache: repair it where it is synced)"}
+}
+
+// indexedProjects returns the number of projects in the index at indexPath
+// The index must exist (opening a missing one would create it)
+func indexedProjects(indexPath string) (int, error) {
+	descIndex, err := index.NewDescriptionIndex(indexPath)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = descIndex.Close() }()
+
+	count, err := descIndex.Count()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", index.ErrIndexCorrupt, err)
+	}
+	return max(0, int(count)-1), nil // Exclude the version document
+}
+
+// checkIndex opens the index: a damaged index is restored from the previous generation
+// (or removed for the next sync to rebuild), and an outdated schema is migrated
+func checkIndex(cfg *config.Config) (string, *doctorProblem) {
+	indexPath := paths.IndexPath(cfg.Cache.Dir)
+	if !index.Exists(indexPath) {
+		return "not built yet (run 'glf --sync')", nil
+	}
+
+	count, err := indexedProjects(indexPath)
+	switch {
+	case err == nil:
+		return fmt.Sprintf("projects: %d, schema v%d", count, index.IndexVersion), nil
+	case readOnlyCache:
+		return "", readOnlyProblem(err.Error())
+	case errors.Is(err, index.ErrIndexCorrupt):
+		return "", &doctorProblem{
+			problem: fmt.Sprintf("damaged (%v)", err),
+			fix:     "Restore the previous index, or remove it for the next sync to rebuild",
+			repair: func() error {
+				restored, err := index.Repair(indexPath)
+				if err == nil && !restored {
+					fmt.Println("    No previous index: the next sync rebuilds it")
+				}
+				return err
+			},
+		}
+	case errors.Is(err, index.ErrIndexVersionMismatch):
+		return "", &doctorProblem{
+			problem: fmt.Sprintf("outdated schema (%v)", err),
+			fix:     fmt.Sprintf("Migrate it to schema v%d", index.IndexVersion),
+			repair: func() error {
+				descIndex, _, err := index.NewDescriptionIndexWithAutoRecreate(indexPath)
+				if err != nil {
+					return err
+				}
+				return descIndex.Close()
+			},
+		}
+	default:
+		return "", &doctorProblem{problem: err.Error()}
+	}
+}
+
+// checkSyncTimes checks the sync timestamps against the index: an unreadable timestamp, one in
+// the future, or a synced cache without indexed projects make incremental syncs miss projects
+// Resetting them makes the next sync a full sync
+func checkSyncTimes(cfg *config.Config) (string, *doctorProblem) {
+	cacheManager := cache.New(cfg.Cache.Dir)
+	problem := func(format string, args ...any) *doctorProblem {
+		if readOnlyCache {
+			return readOnlyProblem(fmt.Sprintf(format, args...))
+		}
+		return &doctorProblem{
+			problem: fmt.Sprintf(format, args...),
+			fix:     "Reset the sync times (the next sync is a full sync)",
+			repair:  cacheManager.ResetSyncTimes,
+		}
+	}
+
+	lastSync, err := cacheManager.LoadLastSyncTime()
+	if err != nil {
+		return "", problem("%v", err)
+	}
+	if _, err := cacheManager.LoadLastFullSyncTime(); err != nil {
+		return "", problem("%v", err)
+	}
+	if lastSync.IsZero() {
+		return "never synced", nil
+	}
+	if lastSync.After(time.Now().Add(time.Minute)) {
+		return "", problem("last sync %s is in the future (incremental syncs would skip changes)", lastSync.Format("2006-01-02 15:04"))
+	}
+
+	indexPath := paths.IndexPath(cfg.Cache.Dir)
+	projects := 0
+	if index.Exists(indexPath) {
+		if projects, err = indexedProjects(indexPath); err != nil {
+			return "index unreadable, not compared", nil // Reported by the index check
+		}
+	}
+	if projects == 0 {
+		return "", problem("synced %s, but the index has no projects (incremental syncs would not fetch them again)", formatSyncTime(&lastSync))
+	}
+	return "last sync " + formatSyncTime(&lastSync), nil
+}
+
+// checkHistory reads the history file: a damaged one is ignored (and overwritten by the next
+// selection), so resetting it keeps the damaged file aside as history.gob.bak
+func checkHistory(cfg *config.Config) (string, *doctorProblem) {
+	historyPath := paths.HistoryPath(cfg.Cache.GetStateDir())
+	err := history.Verify(historyPath)
+	if errors.Is(err, history.ErrCorrupt) {
+		backupPath := historyPath + ".bak"
+		return "", &doctorProblem{
+			problem: fmt.Sprintf("%s can't be read (selections are ignored)", historyPath),
+			fix:     fmt.Sprintf("Reset the history (keeping the damaged file as %s)", backupPath),
+			repair:  func() error { return os.Rename(historyPath, backupPath) },
+		}
+	}
+	if err != nil {
+		return "", &doctorProblem{problem: err.Error()}
+	}
+
+	hist := history.New(historyPath)
+	if err := <-hist.LoadAsync(); err != nil {
+		return "", &doctorProblem{problem: err.Error()}
+	}
+	total, projects := hist.Stats()
+	return fmt.Sprintf("selections: %d, projects: %d", total, projects), nil
+}
+
+// checkHistoryLock finds a history lock file left by a process that crashed while saving
+func checkHistoryLock(cfg *config.Config) (string, *doctorProblem) {
+	lockPath, stale := history.StaleLock(paths.HistoryPath(cfg.Cache.GetStateDir()))
+	if !stale {
+		return "no stale lock", nil
+	}
+	return "", &doctorProblem{
+		problem: fmt.Sprintf("stale lock file %s (left by a crashed process)", lockPath),
+		fix:     "Remove it",
+		repair:  func() error { return os.Remove(lockPath) },
+	}
+}
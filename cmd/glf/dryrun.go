@@ -0,0 +1,253 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/igusev/glf/internal/cache"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/paths"
+)
+
+// dryRunListLimit is the number of paths listed per category in the --dry-run report
+const dryRunListLimit = 20
+
+type (
+	// JSONSyncPlan represents the --sync --dry-run result in JSON mode
+	JSONSyncPlan struct {
+		Mode            string       `json:"mode"`             // "full" or "incremental"
+		ProjectsFetched int          `json:"projects_fetched"` // Projects returned by GitLab (changed ones for incremental syncs)
+		Indexed         int          `json:"indexed"`          // Projects in the index now
+		New             []string     `json:"new"`              // Projects the sync would add
+		Updated         []string     `json:"updated"`          // Projects whose name, description, topics or flags changed
+		Renamed         []JSONRename `json:"renamed"`          // Projects moved to a new path (same GitLab ID)
+		Removed         []string     `json:"removed"`          // Projects a full sync would remove (always empty for incremental)
+		SchemaOutdated  bool         `json:"schema_outdated"`  // The index has an old schema and would be rebuilt from scratch
+		DurationMs      int64        `json:"duration_ms"`      // Time spent fetching and comparing
+		Errors          []string     `json:"errors"`           // Errors (empty on success)
+	}
+
+	// JSONRename is a project that moved to a new path
+	JSONRename struct {
+		From string `json:"from"` // Path in the index
+		To   string `json:"to"`   // Path on GitLab
+	}
+)
+
+// planSync compares the indexed projects with the fetched ones
+// Removals are only planned for full syncs, which see every project
+func planSync(existing, fetched []model.Project, isFullSync bool) JSONSyncPlan {
+	plan := JSONSyncPlan{
+		ProjectsFetched: len(fetched),
+		Indexed:         len(existing),
+		New:             []string{},
+		Updated:         []string{},
+		Renamed:         []JSONRename{},
+		Removed:         []string{},
+		Errors:          []string{},
+	}
+
+	byPath := make(map[string]model.Project, len(existing))
+	for _, proj := range existing {
+		byPath[proj.Path] = proj
+	}
+	renames := matchRenames(pathsByID(existing), fetched)
+	renamedTo := make(map[string]bool, len(renames))
+	for oldPath, newPath := range renames {
+		plan.Renamed = append(plan.Renamed, JSONRename{From: oldPath, To: newPath})
+		renamedTo[newPath] = true
+	}
+
+	fetchedPaths := make(map[string]bool, len(fetched))
+	for _, proj := range fetched {
+		fetchedPaths[proj.Path] = true
+		old, exists := byPath[proj.Path]
+		switch {
+		case renamedTo[proj.Path]:
+			// Counted as renamed
+		case !exists:
+			plan.New = append(plan.New, proj.Path)
+		case projectChanged(old, proj):
+			plan.Updated = append(plan.Updated, proj.Path)
+		}
+	}
+
+	if isFullSync {
+		for _, proj := range existing {
+			if !fetchedPaths[proj.Path] && renames[proj.Path] == "" {
+				plan.Removed = append(plan.Removed, proj.Path)
+			}
+		}
+	}
+
+	sort.Strings(plan.New)
+	sort.Strings(plan.Updated)
+	sort.Strings(plan.Removed)
+	sort.Slice(plan.Renamed, func(i, j int) bool { return plan.Renamed[i].From < plan.Renamed[j].From })
+	return plan
+}
+
+// projectChanged reports whether a sync would change the indexed fields of a project
+// Insight counts are ignored (--dry-run doesn't fetch them), and so is LastActivityAt:
+// incremental syncs only fetch projects with new activity
+func projectChanged(old, current model.Project) bool {
+	return old.Name != current.Name ||
+		old.Description != current.Description ||
+		old.Starred != current.Starred ||
+		old.Archived != current.Archived ||
+		old.Member != current.Member ||
+		old.DefaultBranch != current.DefaultBranch ||
+		old.Visibility != current.Visibility ||
+		old.ForkedFrom != current.ForkedFrom ||
+		!slices.Equal(old.Topics, current.Topics)
+}
+
+// runSyncDryRun handles --sync --dry-run: fetches projects like a sync would and reports
+// what it would change, without writing the index or the sync timestamps
+func runSyncDryRun(cfg *config.Config) error {
+	logInfo := logger.Info
+	if jsonOutput {
+		logInfo = logger.Debug
+	}
+
+	client, err := newSyncClient(cfg)
+	if err != nil {
+		return withExitCode(exitCodeSyncFailed, fmt.Errorf("GitLab client error: %w", err))
+	}
+
+	plan, err := planSyncWithClient(cfg, client, forceFull, logInfo)
+	if jsonOutput {
+		if err != nil {
+			plan.Errors = append(plan.Errors, err.Error())
+		}
+		if encErr := outputJSON(plan); encErr != nil {
+			return encErr
+		}
+		if err != nil {
+			return withExitCode(exitCodeSyncFailed, nil)
+		}
+		return nil
+	}
+	if err != nil {
+		return withExitCode(exitCodeSyncFailed, err)
+	}
+
+	printSyncPlan(plan)
+	return nil
+}
+
+// planSyncWithClient fetches projects with an injected client and compares them with the index (testable version)
+func planSyncWithClient(cfg *config.Config, client gitlab.GitLabClient, forceFullSync bool, logInfo func(format string, args ...interface{})) (JSONSyncPlan, error) {
+	start := time.Now()
+	plan := JSONSyncPlan{New: []string{}, Updated: []string{}, Renamed: []JSONRename{}, Removed: []string{}, Errors: []string{}}
+
+	if err := client.TestConnection(); err != nil {
+		return plan, fmt.Errorf("connection test failed: %w", err)
+	}
+
+	cacheManager := cache.New(cfg.Cache.Dir)
+	syncMode, since := chooseSyncMode(cacheManager, forceFullSync, cfg.Sync.MembershipOnly, logInfo)
+	if syncMode == syncModeIncremental {
+		useCachedProjectSets(client, cacheManager)
+	}
+
+	existing, schemaOutdated, err := loadIndexedProjects(cfg.Cache.Dir)
+	if err != nil {
+		return plan, err
+	}
+	if schemaOutdated {
+		// The next sync recreates the index and runs a full sync
+		syncMode, since = syncModeFull, nil
+	}
+
+	logInfo("Fetching projects (dry run)...")
+	fetched, err := client.FetchAllProjects(since, cfg.Sync.MembershipOnly)
+	if err != nil {
+		return plan, fmt.Errorf("fetch error: %w", err)
+	}
+
+	plan = planSync(existing, fetched, syncMode == syncModeFull)
+	plan.Mode = syncMode
+	plan.SchemaOutdated = schemaOutdated
+	plan.DurationMs = time.Since(start).Milliseconds()
+	if syncMode == syncModeFull && len(fetched) == 0 {
+		// A real sync never wipes the index because nothing came back
+		plan.Removed = []string{}
+	}
+	return plan, nil
+}
+
+// loadIndexedProjects lists the projects in the index without creating or rebuilding it
+// schemaOutdated reports an index with an old schema (the next sync starts from scratch)
+func loadIndexedProjects(cacheDir string) (projects []model.Project, schemaOutdated bool, err error) {
+	indexPath := paths.IndexPath(cacheDir)
+	if !index.Exists(indexPath) {
+		return nil, false, nil
+	}
+
+	descIndex, err := index.NewDescriptionIndex(indexPath)
+	if errors.Is(err, index.ErrIndexVersionMismatch) {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open index: %w", err)
+	}
+	defer func() {
+		if closeErr := descIndex.Close(); closeErr != nil {
+			logger.Debug("Failed to close index: %v", closeErr)
+		}
+	}()
+
+	projects, err = descIndex.GetAllProjects()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list indexed projects: %w", err)
+	}
+	return projects, false, nil
+}
+
+// printSyncPlan prints the --dry-run report
+func printSyncPlan(plan JSONSyncPlan) {
+	fmt.Printf("Dry run: %s sync, %d projects fetched, %d in the index\n", plan.Mode, plan.ProjectsFetched, plan.Indexed)
+	if plan.SchemaOutdated {
+		fmt.Println("The index has an old schema and would be rebuilt from scratch.")
+	}
+	fmt.Println()
+
+	printPlanPaths("new", plan.New)
+	printPlanPaths("updated", plan.Updated)
+	renamed := make([]string, 0, len(plan.Renamed))
+	for _, r := range plan.Renamed {
+		renamed = append(renamed, r.From+" → "+r.To)
+	}
+	printPlanPaths("renamed", renamed)
+	if plan.Mode == syncModeFull {
+		printPlanPaths("would be removed", plan.Removed)
+	} else {
+		fmt.Println("  Removed projects are only detected by a full sync (--full)")
+	}
+
+	if len(plan.New)+len(plan.Updated)+len(plan.Renamed)+len(plan.Removed) == 0 {
+		fmt.Println("\nThe index is up to date.")
+	} else {
+		fmt.Println("\nRun 'glf --sync' to apply.")
+	}
+}
+
+// printPlanPaths prints a category of the --dry-run report (the first dryRunListLimit paths)
+func printPlanPaths(label string, items []string) {
+	fmt.Printf("  %d %s\n", len(items), label)
+	for i, item := range items {
+		if i == dryRunListLimit {
+			fmt.Printf("      ... and %d more\n", len(items)-dryRunListLimit)
+			break
+		}
+		fmt.Printf("      %s\n", item)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+)
+
+// editorCommand returns the editor command line: the editor config option,
+// then $VISUAL, $EDITOR and finally VS Code's 'code' if it is installed
+func editorCommand(cfg *config.Config, getenv func(string) string, lookPath func(string) (string, error)) ([]string, error) {
+	for _, candidate := range []string{cfg.Editor, getenv("VISUAL"), getenv("EDITOR")} {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			return fields, nil
+		}
+	}
+	if _, err := lookPath("code"); err == nil {
+		return []string{"code"}, nil
+	}
+	return nil, errors.New("no editor found; set editor in the config or $EDITOR")
+}
+
+// runEdit handles --edit: opens the best match for query in an editor
+func runEdit(query string, cfg *config.Config, descIndex *index.DescriptionIndex) error {
+	if query == "" {
+		return withExitCode(exitCodeUsage, errors.New("--edit requires a search query"))
+	}
+	if cfg.WorkspaceDir == "" {
+		return withExitCode(exitCodeUsage, errNoWorkspace)
+	}
+	projectPath, err := firstMatch(query, cfg, descIndex)
+	if err != nil {
+		return err
+	}
+	return editProject(cfg, projectPath)
+}
+
+// editProject opens the local clone of a project in an editor, offering to clone it first
+// The clone path is printed to stdout; --non-interactive only prints it (nothing is cloned or launched)
+func editProject(cfg *config.Config, projectPath string) error {
+	ws, err := newWorkspace(cfg)
+	if err != nil {
+		return err
+	}
+
+	clonePath, cloned := ws.Find(projectPath)
+	if !cloned {
+		clonePath = ws.Path(projectPath)
+	}
+	if nonInteractive {
+		if !cloned {
+			return withExitCode(exitCodeNoResults, fmt.Errorf("%s is not cloned (expected at %s)", projectPath, clonePath))
+		}
+		fmt.Println(clonePath)
+		return nil
+	}
+
+	if !cloned {
+		ok, err := confirmClone(bufio.NewReader(os.Stdin), projectPath, clonePath)
+		if err != nil {
+			return fmt.Errorf("failed to read answer: %w", err)
+		}
+		if !ok {
+			return nil
+		}
+		if err := cloneProject(cfg, projectPath, clonePath); err != nil {
+			return err
+		}
+	}
+
+	editor, err := editorCommand(cfg, os.Getenv, exec.LookPath)
+	if err != nil {
+		return withExitCode(exitCodeUsage, err)
+	}
+	logger.Debug("Opening %s with %v", clonePath, editor)
+	cmd := exec.Command(editor[0], append(editor[1:], clonePath)...) // #nosec G204 -- editor is configured by the user
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor[0], err)
+	}
+
+	fmt.Println(clonePath)
+	return nil
+}
+
+// confirmClone asks whether a project that is not cloned yet should be cloned
+func confirmClone(reader *bufio.Reader, projectPath, clonePath string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s is not cloned. Clone it into %s? [Y/n]: ", projectPath, clonePath)
+
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "" || response == "y" || response == responseYes, nil
+}
+
+// cloneProject clones a project into clonePath with git (SSH URL preferred)
+func cloneProject(cfg *config.Config, projectPath, clonePath string) error {
+	if offline {
+		return withExitCode(exitCodeUsage, fmt.Errorf("cannot clone %s with --offline", projectPath))
+	}
+	client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout())
+	if err != nil {
+		return fmt.Errorf("GitLab client error: %w", err)
+	}
+	cloneURL, err := client.CloneURL(projectPath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(clonePath), 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(clonePath), err)
+	}
+
+	logger.Debug("Cloning %s into %s", cloneURL, clonePath)
+	cmd := exec.Command("git", "clone", "--", cloneURL, clonePath) // #nosec G204 -- URL comes from the GitLab API
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stderr, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git clone failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/target"
+)
+
+// projectRootURL returns the URL of a project's page (url_templates apply, as for every target)
+func projectRootURL(baseURL, projectPath string) string {
+	pageURL, _ := target.URL(baseURL, projectPath, "") // Only target lookups fail
+	return pageURL
+}
+
+// projectPageURL returns the URL a --target page of a project opens
+// "env:<name>" targets are looked up live and open the environment's external URL
+func projectPageURL(cfg *config.Config, projectPath, page string) (string, error) {
+	env, ok := target.Environment(page)
+	if !ok {
+		return target.URL(cfg.GitLab.URL, projectPath, page)
+	}
+	if offline {
+		return "", withExitCode(exitCodeUsage, fmt.Errorf("--target env:%s looks the environment up on GitLab and cannot be used with --offline (try --target environments)", env))
+	}
+
+	client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout())
+	if err != nil {
+		return "", fmt.Errorf("failed to create GitLab client: %w", err)
+	}
+	environments, err := client.ListEnvironments(projectPath)
+	if err != nil {
+		return "", err
+	}
+	return environmentURL(cfg.GitLab.URL, projectPath, env, environments)
+}
+
+// environmentURL returns the external URL of the environment named env (case-insensitive),
+// or its page in GitLab when it sets no external URL
+func environmentURL(baseURL, projectPath, env string, environments []model.Environment) (string, error) {
+	names := make([]string, 0, len(environments))
+	for _, e := range environments {
+		if !strings.EqualFold(e.Name, env) {
+			names = append(names, e.Name)
+			continue
+		}
+		if e.ExternalURL == "" {
+			logger.Warn("Environment %s of %s has no external URL; opening its page", e.Name, projectPath)
+			return fmt.Sprintf("%s/%d", target.EnvironmentsURL(baseURL, projectPath, ""), e.ID), nil
+		}
+		return e.ExternalURL, nil
+	}
+
+	if len(names) == 0 {
+		return "", fmt.Errorf("%s has no environments", projectPath)
+	}
+	return "", fmt.Errorf("%s has no environment %q (available: %s)", projectPath, env, strings.Join(names, ", "))
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// formatEscapes expands the escapes of --format templates, which shells pass through
+// literally in single quotes ('{{.Path}}\t{{.URL}}')
+var formatEscapes = strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n")
+
+// formatFuncs are the helper functions available in --format templates
+var formatFuncs = template.FuncMap{
+	"join": func(sep string, elems []string) string { return strings.Join(elems, sep) },
+}
+
+// parseFormat parses a --format template (Go text/template over JSONProject)
+func parseFormat(text string) (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(formatFuncs).Option("missingkey=error").Parse(formatEscapes.Replace(text))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// outputFormat writes each project through the --format template, one per line
+// A template ending in a newline is not given another one
+func outputFormat(w io.Writer, tmpl *template.Template, projects []JSONProject) error {
+	var line strings.Builder
+	for _, project := range projects {
+		line.Reset()
+		if err := tmpl.Execute(&line, project); err != nil {
+			return fmt.Errorf("--format: %w", err)
+		}
+		if !strings.HasSuffix(line.String(), "\n") {
+			line.WriteString("\n")
+		}
+		if _, err := io.WriteString(w, line.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
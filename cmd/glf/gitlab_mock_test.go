@@ -37,3 +37,25 @@ func (m *mockGitLabClient) GetCurrentUsername() (string, error) {
 	}
 	return "", nil
 }
+
+// mockStreamingClient is a mockGitLabClient that delivers projects page by page (gitlab.ProjectStreamer)
+type mockStreamingClient struct {
+	mockGitLabClient
+	pages   [][]model.Project
+	failErr error // Returned after all pages were delivered, if set
+}
+
+// StreamAllProjects calls onPage for each page and returns all projects (or failErr)
+func (m *mockStreamingClient) StreamAllProjects(since *time.Time, membership bool, onPage func([]model.Project)) ([]model.Project, error) {
+	var all []model.Project
+	for _, page := range m.pages {
+		if onPage != nil {
+			onPage(page)
+		}
+		all = append(all, page...)
+	}
+	if m.failErr != nil {
+		return nil, m.failErr
+	}
+	return all, nil
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/history"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/paths"
+	"github.com/igusev/glf/internal/search"
+	"github.com/igusev/glf/internal/target"
+)
+
+type (
+	// JSONGroupsResult represents the --groups response in JSON mode
+	JSONGroupsResult struct {
+		Query  string      `json:"query"`  // Search query that was executed
+		Groups []JSONGroup `json:"groups"` // Matching groups
+		Total  int         `json:"total"`  // Number of results
+	}
+
+	// JSONGroup represents a single group in JSON output
+	JSONGroup struct {
+		Path        string `json:"path"`        // Full group path (e.g., "company/platform")
+		Name        string `json:"name"`        // Group name
+		Description string `json:"description"` // Group description
+		URL         string `json:"url"`         // Group overview URL (or the --target sub-page)
+	}
+)
+
+// syncGroups refreshes the group index with the groups visible to the user
+// Groups are few, so every sync rebuilds the whole list; returns the number of indexed groups
+func syncGroups(cacheDir string, client *gitlab.Client) (int, error) {
+	groups, err := client.FetchAllGroups()
+	if err != nil {
+		return 0, err
+	}
+
+	groupIndex, _, err := index.NewDescriptionIndexWithAutoRecreate(paths.GroupIndexPath(cacheDir))
+	if err != nil {
+		return 0, fmt.Errorf("failed to open group index: %w", err)
+	}
+	defer func() {
+		if err := groupIndex.Close(); err != nil {
+			logger.Debug("Failed to close group index: %v", err)
+		}
+	}()
+
+	existing, err := groupIndex.GetAllProjects()
+	if err != nil {
+		logger.Debug("Failed to list indexed groups: %v", err)
+	}
+
+	// Groups are keyed by path, but a migrated index keys the groups of older versions by
+	// their ID: drop those before adding the groups again
+	for _, group := range existing {
+		if group.ID != 0 {
+			if err := groupIndex.Delete(group.Path); err != nil {
+				logger.Debug("Failed to delete group %s: %v", group.Path, err)
+			}
+		}
+	}
+
+	current := make(map[string]bool, len(groups))
+	docs := make([]index.DescriptionDocument, 0, len(groups))
+	for _, group := range groups {
+		current[group.Path] = true
+		docs = append(docs, index.NewGroupDocument(group))
+	}
+	if len(docs) > 0 {
+		if err := groupIndex.AddBatch(docs); err != nil {
+			return 0, fmt.Errorf("failed to index groups: %w", err)
+		}
+	}
+
+	// Drop groups the user left or that were deleted
+	for _, group := range existing {
+		if !current[group.Path] {
+			if err := groupIndex.Delete(group.Path); err != nil {
+				logger.Debug("Failed to delete group %s: %v", group.Path, err)
+			}
+		}
+	}
+
+	if err := groupIndex.SaveSnapshot(); err != nil {
+		logger.Debug("Failed to save group snapshot: %v", err)
+	}
+	return len(groups), nil
+}
+
+// ensureGroupIndex syncs first if no groups are cached yet
+// Must run before the project index is opened (the sync needs it exclusively)
+func ensureGroupIndex(cfg *config.Config) error {
+	indexPath := paths.GroupIndexPath(cfg.Cache.Dir)
+	if index.Exists(indexPath) {
+		return nil
+	}
+	if autoSyncDisabled() {
+		return withExitCode(exitCodeNoCache, errors.New("no cached groups; run 'glf --sync' first"))
+	}
+
+	logger.Info("No cached groups - synchronizing from GitLab...")
+	if err := performSyncInternal(cfg, jsonOutput, false); err != nil {
+		return withExitCode(exitCodeSyncFailed, err)
+	}
+	if !index.Exists(indexPath) {
+		return withExitCode(exitCodeSyncFailed, errors.New("groups could not be synchronized (run 'glf --sync -v' for details)"))
+	}
+	return nil
+}
+
+// runGroups handles --groups in JSON, --go and non-interactive modes
+// (the TUI starts in groups mode instead, see runInteractive)
+func runGroups(cfg *config.Config, query string) error {
+	groupIndex, _, err := index.NewDescriptionIndexWithAutoRecreate(paths.GroupIndexPath(cfg.Cache.Dir))
+	if err != nil {
+		return fmt.Errorf("failed to open group index: %w", err)
+	}
+	defer func() {
+		if err := groupIndex.Close(); err != nil {
+			logger.Debug("Failed to close group index: %v", err)
+		}
+	}()
+
+	hist, err := loadHistory(cfg)
+	if err != nil {
+		logger.Debug("Failed to load history: %v", err)
+	}
+	historyScores := map[string]int{}
+	if hist != nil {
+		historyScores = hist.GetAllScoresForQuery(query)
+	}
+
+	matches, err := search.CombinedSearchWithIndex(query, nil, historyScores, cfg.Cache.Dir, groupIndex)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	// Pinned groups come first; listings show them even when the query doesn't match them
+	lookup := pinLookup(groupIndex)
+	if autoGo {
+		lookup = nil // --go opens a group the query matches
+	}
+	matches = search.ApplyPins(matches, cfg.PinnedPaths, lookup)
+	if limitResults > 0 && len(matches) > limitResults {
+		matches = matches[:limitResults]
+	}
+
+	switch {
+	case jsonOutput:
+		result := JSONGroupsResult{
+			Query:  query,
+			Groups: make([]JSONGroup, 0, len(matches)),
+			Total:  len(matches),
+		}
+		for _, match := range matches {
+			groupURL, err := target.GroupURL(cfg.GitLab.URL, match.Project.Path, targetName)
+			if err != nil {
+				return withExitCode(exitCodeUsage, err)
+			}
+			result.Groups = append(result.Groups, JSONGroup{
+				Path:        match.Project.Path,
+				Name:        match.Project.Name,
+				Description: match.Project.Description,
+				URL:         groupURL,
+			})
+		}
+		if err := outputJSON(result); err != nil {
+			return err
+		}
+		if ciMode && len(matches) == 0 {
+			return withExitCode(exitCodeNoResults, nil)
+		}
+		return nil
+
+	case autoGo:
+		if strings.TrimSpace(query) == "" {
+			return withExitCode(exitCodeUsage, fmt.Errorf("-g/--go requires a search query"))
+		}
+		if len(matches) == 0 {
+			return withExitCode(exitCodeNoResults, fmt.Errorf("no groups found matching '%s'", query))
+		}
+		groupURL, err := target.GroupURL(cfg.GitLab.URL, matches[0].Project.Path, targetName)
+		if err != nil {
+			return withExitCode(exitCodeUsage, err)
+		}
+		if hist != nil {
+			hist.SetSource(history.SourceGo)
+			hist.RecordSelectionWithQuery(query, matches[0].Project.Key())
+			if err := hist.Save(); err != nil {
+				logger.Debug("Failed to save history: %v", err)
+			}
+		}
+		logger.Debug("Opening browser with URL: %s", groupURL)
+		if err := openBrowser(groupURL); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to open browser: %v\n", err)
+			logger.Debug("Browser open error: %v", err)
+		}
+		fmt.Println(groupURL)
+		return nil
+
+	default: // nonInteractive
+		for _, match := range matches {
+			groupURL, err := target.GroupURL(cfg.GitLab.URL, match.Project.Path, targetName)
+			if err != nil {
+				return withExitCode(exitCodeUsage, err)
+			}
+			fmt.Printf("%s\t%s\n", match.Project.Path, groupURL)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/logger"
+)
+
+// runInitNonInteractive is --init --yes for provisioning scripts: the URL comes from --url and
+// the token from stdin (--token-stdin), or from the existing configuration. It validates them
+// like the wizard, tests the connection and runs the initial sync (unless --no-sync) without
+// prompting. Unlike the wizard it never launches the TUI
+func runInitNonInteractive(stdin io.Reader) error {
+	configPath := filepath.Clean(config.Path())
+
+	// Existing settings (token backend, cache, exclusions) are kept unless --reset is set
+	existingCfg := &config.Config{}
+	if !resetFlag {
+		if cfg, err := config.Load(); err == nil {
+			existingCfg = cfg
+		}
+		if existingCfg.GitLab.OAuth {
+			existingCfg.GitLab.Token = "" // Never reuse the short-lived --login token
+		}
+	}
+
+	urlInput := initURL
+	if urlInput == "" {
+		urlInput = existingCfg.GitLab.URL
+	}
+	if urlInput == "" {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--init --yes needs the GitLab URL: pass --url"))
+	}
+	gitlabURL, err := parseGitLabURL(urlInput)
+	if err != nil {
+		return withExitCode(exitCodeUsage, fmt.Errorf("invalid URL: %w", err))
+	}
+
+	token := existingCfg.GitLab.Token
+	if tokenStdin {
+		if token, err = readTokenLine(stdin); err != nil {
+			return err
+		}
+	}
+	if token == "" {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--init --yes needs a token: pipe it with --token-stdin"))
+	}
+	if err := validateToken(token); err != nil {
+		// The wizard asks whether to use the token anyway; --yes answers yes
+		logger.Warn("%v (using it anyway)", err)
+	}
+
+	cfg := wizardConfig(gitlabURL, token, existingCfg)
+
+	logger.Info("Connecting to %s...", cfg.GitLab.URL)
+	client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout(), cfg.GitLab.Concurrency)
+	if err != nil {
+		return withExitCode(exitCodeConfig, fmt.Errorf("failed to create GitLab client: %w", err))
+	}
+	if err := client.TestConnection(); err != nil {
+		return withHint(withExitCode(exitCodeConfig, fmt.Errorf("connection failed: %w", err)),
+			"check the URL and that the token has the 'read_api' and 'read_repository' scopes")
+	}
+	logger.Success("Connection successful")
+	if info := fetchTokenInfo(cfg, client); info != nil {
+		if err := checkTokenScopes(cfg, info, logger.Warn); err != nil {
+			return err
+		}
+	}
+
+	if resetFlag {
+		if err := os.Remove(configPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove config: %w", err)
+		}
+	}
+	if err := saveWizardConfig(cfg, configPath); err != nil {
+		return err
+	}
+
+	if noSync {
+		logger.Info("Skipping the initial sync (--no-sync); run 'glf --sync' to build the cache")
+		return nil
+	}
+	logger.Info("Syncing all projects from GitLab...")
+	if err := performSyncInternal(cfg, false, true); err != nil {
+		return withExitCode(exitCodeSyncFailed, fmt.Errorf("initial sync failed: %w", err))
+	}
+	return nil
+}
+
+// readTokenLine reads the token piped with --token-stdin (the first line, trimmed)
+func readTokenLine(stdin io.Reader) (string, error) {
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read the token from stdin: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
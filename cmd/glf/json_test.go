@@ -1,16 +1,28 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/igusev/glf/internal/cache"
 	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
 	"github.com/igusev/glf/internal/history"
 	"github.com/igusev/glf/internal/index"
 	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/paths"
+	"github.com/spf13/cobra"
 )
 
 // TestOutputJSON tests JSON encoding function
@@ -52,9 +64,34 @@ func TestOutputJSON(t *testing.T) {
 
 // TestRunJSONMode_EmptyProjects tests error handling for empty projects
 func TestRunJSONMode_EmptyProjects(t *testing.T) {
-	// This will exit the process, so we need to test it differently
-	// For now, we'll skip this test as outputJSONError calls os.Exit(1)
-	t.Skip("Cannot test outputJSONError directly as it calls os.Exit(1)")
+	tempDir := t.TempDir()
+	configDir := filepath.Join(tempDir, ".config", "glf")
+	cacheDir := filepath.Join(tempDir, "cache")
+	_ = os.MkdirAll(configDir, 0755)
+	_ = os.MkdirAll(cacheDir, 0755)
+	configContent := "gitlab:\n  url: https://gitlab.example.com\n  token: test-token\ncache:\n  dir: " + cacheDir
+	_ = os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0600)
+	t.Setenv("HOME", tempDir)
+
+	jsonOutput = true
+	noSync = true
+	defer func() {
+		jsonOutput = false
+		noSync = false
+	}()
+
+	// The error is returned to main, which prints it as a JSON object
+	err := runSearch(&cobra.Command{}, []string{"api"})
+	if exitCodeFor(err) != exitCodeNoCache {
+		t.Fatalf("Expected exit code %d, got %d (%v)", exitCodeNoCache, exitCodeFor(err), err)
+	}
+	jsonErr := newJSONError(err)
+	if jsonErr.Code != errorCodeNoCache || jsonErr.Hint != "run 'glf --sync' first" {
+		t.Errorf("Expected a no_cache error with a sync hint, got %+v", jsonErr)
+	}
+	if jsonErr.Message != jsonErr.Error || !strings.Contains(jsonErr.Message, "no cached projects") {
+		t.Errorf("Expected message and error to describe the empty cache, got %+v", jsonErr)
+	}
 }
 
 // TestRunJSONMode_WithQuery tests JSON output with search query
@@ -696,6 +733,546 @@ func TestRunJSONMode_EmptyResults(t *testing.T) {
 	if result.Total != 0 {
 		t.Errorf("Expected total 0, got %d", result.Total)
 	}
+	if len(result.Suggestions) != 0 {
+		t.Errorf("Expected no suggestions for an unrelated query, got %v", result.Suggestions)
+	}
+}
+
+// TestRunJSONMode_Suggestions tests the "Did you mean" paths returned when nothing matches
+func TestRunJSONMode_Suggestions(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: cacheDir},
+	}
+	if err := indexDescriptions([]model.Project{
+		{Path: "web/frontend", Name: "frontend", Member: true},
+		{Path: "backend/api", Name: "api", Member: true},
+	}, cacheDir, true, true); err != nil {
+		t.Fatalf("indexDescriptions failed: %v", err)
+	}
+	descIndex, err := index.NewDescriptionIndex(paths.IndexPath(cacheDir))
+	if err != nil {
+		t.Fatalf("Failed to open index: %v", err)
+	}
+	defer descIndex.Close()
+
+	// Two typos are too far for the full-text search with search.fuzziness 1
+	index.SetFuzziness(1)
+	defer index.SetFuzziness(-1)
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err = runJSONMode("grontebd", cfg, descIndex)
+	w.Close()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatalf("runJSONMode failed: %v", err)
+	}
+
+	output, _ := io.ReadAll(r)
+	var result JSONSearchResult
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Failed to parse JSON: %v\n%s", err, output)
+	}
+	if result.Total != 0 || len(result.Suggestions) != 1 || result.Suggestions[0] != "web/frontend" {
+		t.Errorf("Expected no results and the suggestion web/frontend, got %d results, suggestions %v", result.Total, result.Suggestions)
+	}
+}
+
+// TestRunJSONMode_RemoteFallback tests the live GitLab search when nothing matches locally
+func TestRunJSONMode_RemoteFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("search") != "brandnew" {
+			t.Errorf("Expected search=brandnew, got %q", r.URL.Query().Get("search"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": 7, "path_with_namespace": "team/brandnew", "name": "brandnew", "description": "Created a minute ago"}]`))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	cacheDir := filepath.Join(tempDir, "cache")
+	_ = os.MkdirAll(cacheDir, 0755)
+
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: server.URL, Token: "test-token"},
+		Search: config.SearchConfig{RemoteFallback: true},
+		Cache:  config.CacheConfig{Dir: cacheDir},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(cacheDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	if err := descIndex.Add("backend/api", "API Server", "REST API backend", false, false); err != nil {
+		t.Fatalf("Failed to add to index: %v", err)
+	}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err = runJSONMode("brandnew", cfg, descIndex)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	if err != nil {
+		t.Fatalf("runJSONMode failed: %v", err)
+	}
+
+	output, _ := io.ReadAll(r)
+	var result JSONSearchResult
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+
+	if len(result.Results) != 1 {
+		t.Fatalf("Expected 1 remote result, got %d", len(result.Results))
+	}
+	if result.Results[0].Path != "team/brandnew" || !result.Results[0].Remote {
+		t.Errorf("Expected remote result team/brandnew, got %+v", result.Results[0])
+	}
+
+	// Offline mode never falls back to the network
+	offline = true
+	defer func() { offline = false }()
+	if newRemoteSearch(cfg) != nil {
+		t.Error("Expected no remote search in offline mode")
+	}
+}
+
+// TestRunJSONMode_ProjectMetadata tests default branch, visibility and last activity in results
+func TestRunJSONMode_ProjectMetadata(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: cacheDir},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(cacheDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	lastActivity := time.Date(2026, 10, 15, 6, 30, 0, 0, time.UTC)
+	if err := descIndex.AddBatch([]index.DescriptionDocument{
+		{ProjectPath: "backend/api", ProjectName: "API", DefaultBranch: "develop", Visibility: "internal", LastActivityAt: lastActivity.Unix(), ForkedFrom: "upstream/api"},
+		{ProjectPath: "backend/legacy", ProjectName: "Legacy"},
+	}); err != nil {
+		t.Fatalf("Failed to add batch: %v", err)
+	}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err = runJSONMode("", cfg, descIndex)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	if err != nil {
+		t.Fatalf("runJSONMode failed: %v", err)
+	}
+
+	output, _ := io.ReadAll(r)
+	var result JSONSearchResult
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	byPath := make(map[string]JSONProject, len(result.Results))
+	for _, project := range result.Results {
+		byPath[project.Path] = project
+	}
+
+	api := byPath["backend/api"]
+	if api.DefaultBranch != "develop" || api.Visibility != "internal" || api.LastActivityAt == nil || !api.LastActivityAt.Equal(lastActivity) || api.ForkedFrom != "upstream/api" {
+		t.Errorf("Unexpected metadata for backend/api: %+v", api)
+	}
+	// Projects synced without metadata omit the fields
+	if legacy := byPath["backend/legacy"]; legacy.DefaultBranch != "" || legacy.LastActivityAt != nil || legacy.ForkedFrom != "" {
+		t.Errorf("Expected no metadata for backend/legacy, got %+v", legacy)
+	}
+}
+
+// TestRunJSONMode_Sort tests --sort by path, name and last activity
+func TestRunJSONMode_Sort(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: cacheDir},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(cacheDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	now := time.Date(2026, 10, 15, 6, 30, 0, 0, time.UTC)
+	if err := descIndex.AddBatch([]index.DescriptionDocument{
+		{ProjectPath: "backend/api", ProjectName: "Zeta API", LastActivityAt: now.Add(-48 * time.Hour).Unix()},
+		{ProjectPath: "Frontend/app", ProjectName: "app", LastActivityAt: now.Unix()},
+		{ProjectPath: "devops/tools", ProjectName: "Beta tools"},
+	}); err != nil {
+		t.Fatalf("Failed to add batch: %v", err)
+	}
+
+	oldSort := sortBy
+	defer func() { sortBy = oldSort }()
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{sortPath, []string{"backend/api", "devops/tools", "Frontend/app"}},
+		{sortName, []string{"Frontend/app", "devops/tools", "backend/api"}},
+		// Projects without synced activity come last
+		{sortActivity, []string{"Frontend/app", "backend/api", "devops/tools"}},
+	}
+	for _, tt := range tests {
+		sortBy = tt.order
+
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := runJSONMode("", cfg, descIndex)
+		w.Close()
+		os.Stdout = oldStdout
+		if err != nil {
+			t.Fatalf("runJSONMode failed: %v", err)
+		}
+
+		output, _ := io.ReadAll(r)
+		var result JSONSearchResult
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Fatalf("Failed to parse JSON: %v", err)
+		}
+		var got []string
+		for _, project := range result.Results {
+			got = append(got, project.Path)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("--sort %s: got %v, want %v", tt.order, got, tt.want)
+		}
+	}
+
+	if err := validateSort("stars"); err == nil {
+		t.Error("Expected an error for an unknown sort order")
+	}
+}
+
+// TestRunJSONMode_Format tests --format output: one templated line per result
+func TestRunJSONMode_Format(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: cacheDir},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(cacheDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	if err := descIndex.AddBatch([]index.DescriptionDocument{
+		{ProjectPath: "backend/api", ProjectName: "API", DefaultBranch: "main"},
+	}); err != nil {
+		t.Fatalf("Failed to add batch: %v", err)
+	}
+
+	formatTemplate = `{{.Path}}\t{{.URL}}\t{{.DefaultBranch}}`
+	defer func() { formatTemplate = "" }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err = runJSONMode("api", cfg, descIndex)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	if err != nil {
+		t.Fatalf("runJSONMode failed: %v", err)
+	}
+	output, _ := io.ReadAll(r)
+	if want := "backend/api\thttps://gitlab.example.com/backend/api\tmain\n"; string(output) != want {
+		t.Errorf("Expected %q, got %q", want, output)
+	}
+}
+
+// TestOutputFormat tests --format template parsing and execution
+func TestOutputFormat(t *testing.T) {
+	projects := []JSONProject{
+		{Path: "backend/api", Topics: []string{"go", "grpc"}},
+		{Path: "frontend/app"},
+	}
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"escapes", `{{.Path}}\t{{.Starred}}`, "backend/api\tfalse\nfrontend/app\tfalse\n"},
+		{"join", `{{.Path}} {{join "," .Topics}}`, "backend/api go,grpc\nfrontend/app \n"},
+		{"trailing newline kept", "{{.Path}}\\n", "backend/api\nfrontend/app\n"},
+		{"literal backslash", `{{.Path}}\\t`, "backend/api\\t\nfrontend/app\\t\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseFormat(tt.template)
+			if err != nil {
+				t.Fatalf("parseFormat(%q) error = %v", tt.template, err)
+			}
+			var b strings.Builder
+			if err := outputFormat(&b, tmpl, projects); err != nil {
+				t.Fatalf("outputFormat error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, b.String())
+			}
+		})
+	}
+
+	if _, err := parseFormat("{{.Path"); err == nil {
+		t.Error("Expected a parse error for an unterminated action")
+	}
+	tmpl, err := parseFormat("{{.NoSuchField}}")
+	if err != nil {
+		t.Fatalf("parseFormat error = %v", err)
+	}
+	if err := outputFormat(io.Discard, tmpl, projects); err == nil {
+		t.Error("Expected an execution error for an unknown field")
+	}
+}
+
+// TestOutputPlain tests --plain/--print0 records: tabs and line breaks in descriptions are flattened
+func TestOutputPlain(t *testing.T) {
+	projects := []JSONProject{
+		{Path: "backend/api", Description: " REST\tAPI\r\nfor clients "},
+		{Path: "frontend/app"},
+	}
+
+	var b strings.Builder
+	if err := outputPlain(&b, projects, '\n'); err != nil {
+		t.Fatalf("outputPlain error = %v", err)
+	}
+	if want := "backend/api\tREST API for clients\nfrontend/app\t\n"; b.String() != want {
+		t.Errorf("Expected %q, got %q", want, b.String())
+	}
+
+	b.Reset()
+	if err := outputPlain(&b, projects, 0); err != nil {
+		t.Fatalf("outputPlain error = %v", err)
+	}
+	if want := "backend/api\tREST API for clients\x00frontend/app\t\x00"; b.String() != want {
+		t.Errorf("Expected %q, got %q", want, b.String())
+	}
+}
+
+// TestOutputURLs tests --urls: one project or --target page URL per line
+func TestOutputURLs(t *testing.T) {
+	matches := []index.CombinedMatch{
+		{Project: model.Project{Path: "backend/api"}},
+		{Project: model.Project{Path: "frontend/app"}},
+	}
+
+	var b strings.Builder
+	if err := outputURLs(&b, "https://gitlab.example.com/", matches, ""); err != nil {
+		t.Fatalf("outputURLs error = %v", err)
+	}
+	if want := "https://gitlab.example.com/backend/api\nhttps://gitlab.example.com/frontend/app\n"; b.String() != want {
+		t.Errorf("Expected %q, got %q", want, b.String())
+	}
+
+	b.Reset()
+	if err := outputURLs(&b, "https://gitlab.example.com", matches[:1], "pipelines"); err != nil {
+		t.Fatalf("outputURLs error = %v", err)
+	}
+	if want := "https://gitlab.example.com/backend/api/-/pipelines\n"; b.String() != want {
+		t.Errorf("Expected %q, got %q", want, b.String())
+	}
+
+	if err := outputURLs(io.Discard, "https://gitlab.example.com", matches, "no-such-page"); exitCodeFor(err) != exitCodeUsage {
+		t.Errorf("Unknown target: error = %v, want a usage error", err)
+	}
+}
+
+func TestOutputVim(t *testing.T) {
+	matches := []index.CombinedMatch{
+		{Project: model.Project{Path: "backend/billing-api"}, TotalScore: 12.3456},
+		{Project: model.Project{Path: "frontend/app"}, TotalScore: 1},
+	}
+
+	var b strings.Builder
+	if err := outputVim(&b, "api back is:starred", matches); err != nil {
+		t.Fatalf("outputVim error = %v", err)
+	}
+	want := "backend/billing-api\t12.346\t0-4,16-19\nfrontend/app\t1.000\t\n"
+	if b.String() != want {
+		t.Errorf("Expected %q, got %q", want, b.String())
+	}
+}
+
+// TestRunJSONMode_Plain tests --plain: every visible project, hidden ones only with --show-hidden
+func TestRunJSONMode_Plain(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab:        config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:         config.CacheConfig{Dir: cacheDir},
+		ExcludedPaths: []string{"backend/excluded"},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(cacheDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	if err := descIndex.AddBatch([]index.DescriptionDocument{
+		{ProjectPath: "backend/api", ProjectName: "API", Description: "REST API", Member: true},
+		{ProjectPath: "backend/excluded", ProjectName: "Excluded", Member: true},
+		{ProjectPath: "backend/archived", ProjectName: "Archived", Archived: true, Member: true},
+		{ProjectPath: "backend/public", ProjectName: "Public"},
+	}); err != nil {
+		t.Fatalf("Failed to add batch: %v", err)
+	}
+
+	plainOutput = true
+	oldSort := sortBy
+	defer func() {
+		plainOutput = false
+		showHidden = false
+		sortBy = oldSort
+	}()
+	sortBy = sortPath
+
+	run := func() string {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := runJSONMode("", cfg, descIndex)
+		w.Close()
+		os.Stdout = oldStdout
+		if err != nil {
+			t.Fatalf("runJSONMode failed: %v", err)
+		}
+		output, _ := io.ReadAll(r)
+		return string(output)
+	}
+
+	if got, want := run(), "backend/api\tREST API\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	showHidden = true
+	want := "backend/api\tREST API\nbackend/archived\t\nbackend/excluded\t\nbackend/public\t\n"
+	if got := run(); got != want {
+		t.Errorf("With --show-hidden expected %q, got %q", want, got)
+	}
+}
+
+func TestWriteJSONResult(t *testing.T) {
+	cfg := &config.Config{ExcludedPaths: []string{"backend/old"}}
+	activity := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	matches := []index.CombinedMatch{
+		{Project: model.Project{Path: "backend/api", Name: "API", Description: "REST <API> & more", Member: true, LastActivityAt: activity}, TotalScore: 1.5},
+		{Project: model.Project{Path: "backend/old", Name: "Old", Topics: []string{"legacy"}}, TotalScore: 0.25},
+	}
+
+	for _, matches := range [][]index.CombinedMatch{nil, matches} {
+		result := JSONSearchResult{
+			SchemaVersion: jsonSchemaVersion,
+			Query:         `"results": []`,
+			Total:         len(matches),
+			Instance:      "https://gitlab.example.com",
+			Version:       "test",
+		}
+
+		// Streaming produces the document outputJSON encodes in one piece
+		want := result
+		want.Results = make([]JSONProject, len(matches))
+		for i, match := range matches {
+			want.Results[i] = newJSONProject(match, cfg, result.Instance)
+		}
+		var expected bytes.Buffer
+		encoder := json.NewEncoder(&expected)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(want); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+
+		var got bytes.Buffer
+		if err := writeJSONResult(&got, result, matches, cfg); err != nil {
+			t.Fatalf("writeJSONResult failed: %v", err)
+		}
+		if got.String() != expected.String() {
+			t.Errorf("writeJSONResult() with %d matches =\n%s\nwant\n%s", len(matches), got.String(), expected.String())
+		}
+	}
+}
+
+func TestRunJSONMode_All(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: cacheDir},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(cacheDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	docs := make([]index.DescriptionDocument, 250)
+	for i := range docs {
+		docs[i] = index.DescriptionDocument{ProjectPath: fmt.Sprintf("svc/service-%03d", i), ProjectName: fmt.Sprintf("service-%03d", i), Description: "service", Member: true}
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to add batch: %v", err)
+	}
+
+	oldLimit := limitResults
+	defer func() {
+		limitResults = oldLimit
+		allResults = false
+	}()
+	run := func() JSONSearchResult {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		done := make(chan []byte)
+		go func() {
+			output, _ := io.ReadAll(r)
+			done <- output
+		}()
+		err := runJSONMode("service", cfg, descIndex)
+		w.Close()
+		os.Stdout = oldStdout
+		output := <-done
+		if err != nil {
+			t.Fatalf("runJSONMode failed: %v", err)
+		}
+		var result JSONSearchResult
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		return result
+	}
+
+	// --limit 0 returns every candidate of the default ranking
+	limitResults = 0
+	if result := run(); result.Total >= len(docs) {
+		t.Fatalf("Expected the default candidate limit to cut the results, got %d", result.Total)
+	}
+
+	// --all ranks the whole cache
+	allResults = true
+	if result := run(); result.Total != len(docs) || len(result.Results) != len(docs) || result.HasMore {
+		t.Errorf("Expected all %d projects with --all, got total %d, %d results", len(docs), result.Total, len(result.Results))
+	}
 }
 
 // TestRunJSONMode_LargeResultSet tests performance with many projects
@@ -1784,3 +2361,475 @@ func TestRunJSONMode_PerformanceBenchmark(t *testing.T) {
 
 	t.Logf("Average performance: %v per operation (%d iterations)", avgDuration, iterations)
 }
+
+// TestRunStatus_JSON tests the --status size report in JSON mode
+func TestRunStatus_JSON(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+
+	if err := indexDescriptions([]model.Project{
+		{Path: "group/api", Name: "api", Description: "API service"},
+		{Path: "group/web", Name: "web", Description: "Web frontend"},
+	}, tempDir, true, true); err != nil {
+		t.Fatalf("indexDescriptions failed: %v", err)
+	}
+
+	jsonOutput = true
+	defer func() { jsonOutput = false }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := runStatus(cfg)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	if err != nil {
+		t.Fatalf("runStatus failed: %v", err)
+	}
+
+	output, _ := io.ReadAll(r)
+	var status JSONStatus
+	if err := json.Unmarshal(output, &status); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\n%s", err, output)
+	}
+
+	if status.Projects != 2 {
+		t.Errorf("Expected 2 projects, got %d", status.Projects)
+	}
+	if status.IndexVersion != index.IndexVersion {
+		t.Errorf("Expected index version %d, got %d", index.IndexVersion, status.IndexVersion)
+	}
+	if status.IndexBytes == 0 || status.TotalBytes < status.IndexBytes {
+		t.Errorf("Expected non-zero index size within total, got index=%d total=%d", status.IndexBytes, status.TotalBytes)
+	}
+	if status.LastSync != nil {
+		t.Error("Expected last_sync to be omitted before the first sync")
+	}
+}
+
+// TestFormatBytes tests human-readable size formatting
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:                "0 B",
+		1023:             "1023 B",
+		1536:             "1.5 KB",
+		10 * 1024 * 1024: "10.0 MB",
+	}
+	for in, want := range tests {
+		if got := formatBytes(in); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestRunMergeRequests_JSON tests --mrs --json: fetch, dedup across scopes, cache, and filter
+func TestRunMergeRequests_JSON(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/merge_requests" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		mr := `{"id": 501, "iid": 12, "title": "Add retries", "references": {"full": "group/api!12"}, "web_url": "https://gitlab.example.com/group/api/-/merge_requests/12", "updated_at": "2026-01-02T10:00:00Z"}`
+		if r.URL.Query().Get("scope") == "created_by_me" {
+			// The same MR appears in both scopes; the second one only here
+			mr += `, {"id": 502, "iid": 3, "title": "Docs: login flow", "draft": true, "web_url": "https://gitlab.example.com/group/docs/-/merge_requests/3", "updated_at": "2026-01-01T10:00:00Z"}`
+		}
+		w.Write([]byte("[" + mr + "]"))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: server.URL, Token: "test-token", Timeout: 5},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+
+	jsonOutput = true
+	defer func() { jsonOutput = false }()
+
+	run := func(query string) JSONMergeRequestsResult {
+		t.Helper()
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := runMergeRequests(cfg, query)
+
+		w.Close()
+		os.Stdout = oldStdout
+		if err != nil {
+			t.Fatalf("runMergeRequests failed: %v", err)
+		}
+
+		output, _ := io.ReadAll(r)
+		var result JSONMergeRequestsResult
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Fatalf("Failed to parse JSON output: %v\n%s", err, output)
+		}
+		return result
+	}
+
+	result := run("")
+	if result.Total != 2 {
+		t.Fatalf("Expected 2 merge requests, got %d", result.Total)
+	}
+	if result.MergeRequests[0].Project != "group/api" || result.MergeRequests[1].Project != "group/docs" {
+		t.Errorf("Unexpected projects (reference and web URL parsing): %+v", result.MergeRequests)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 API requests (one per scope), got %d", requests)
+	}
+
+	// Second run is served from the cache
+	result = run("lgn")
+	if requests != 2 {
+		t.Errorf("Expected cached merge requests to be reused, got %d API requests", requests)
+	}
+	if result.Total != 1 || result.MergeRequests[0].IID != 3 || !result.MergeRequests[0].Draft {
+		t.Errorf("Expected only the docs MR, got %+v", result.MergeRequests)
+	}
+}
+
+// TestRunMergeRequests_NoCacheOffline tests --mrs --offline without cached merge requests
+func TestRunMergeRequests_NoCacheOffline(t *testing.T) {
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: t.TempDir()}}
+
+	offline = true
+	defer func() { offline = false }()
+
+	err := runMergeRequests(cfg, "")
+	if exitCodeFor(err) != exitCodeNoCache {
+		t.Errorf("Expected exit code %d, got %d (%v)", exitCodeNoCache, exitCodeFor(err), err)
+	}
+}
+
+// TestRunJSONMode_Pagination tests --offset paging and the v2 metadata fields
+func TestRunJSONMode_Pagination(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	_ = os.MkdirAll(cacheDir, 0755)
+
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com/"},
+		Cache:  config.CacheConfig{Dir: cacheDir},
+	}
+
+	syncedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := cache.New(cacheDir).SaveLastSyncTime(syncedAt); err != nil {
+		t.Fatalf("Failed to save sync time: %v", err)
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(cacheDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	for _, path := range []string{"backend/api", "frontend/app", "devops/tools"} {
+		if err := descIndex.Add(path, filepath.Base(path), "", false, false); err != nil {
+			t.Fatalf("Failed to add to index: %v", err)
+		}
+	}
+
+	oldLimit, oldOffset := limitResults, offsetResults
+	defer func() { limitResults, offsetResults = oldLimit, oldOffset }()
+	limitResults = 2
+
+	run := func(offset int) JSONSearchResult {
+		t.Helper()
+		offsetResults = offset
+
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := runJSONMode("", cfg, descIndex)
+		w.Close()
+		os.Stdout = oldStdout
+		if err != nil {
+			t.Fatalf("runJSONMode failed: %v", err)
+		}
+
+		output, _ := io.ReadAll(r)
+		var result JSONSearchResult
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Fatalf("Failed to parse JSON output: %v", err)
+		}
+		return result
+	}
+
+	first := run(0)
+	if len(first.Results) != 2 || !first.HasMore || first.Offset != 0 {
+		t.Errorf("First page: expected 2 results with has_more, got %d (has_more=%v, offset=%d)", len(first.Results), first.HasMore, first.Offset)
+	}
+	if first.SchemaVersion != jsonSchemaVersion {
+		t.Errorf("Expected schema_version %d, got %d", jsonSchemaVersion, first.SchemaVersion)
+	}
+	if first.Instance != "https://gitlab.example.com" {
+		t.Errorf("Expected instance without trailing slash, got %q", first.Instance)
+	}
+	if first.Version != version {
+		t.Errorf("Expected version %q, got %q", version, first.Version)
+	}
+	if first.CacheSyncedAt == nil || !first.CacheSyncedAt.Equal(syncedAt) {
+		t.Errorf("Expected cache_synced_at %v, got %v", syncedAt, first.CacheSyncedAt)
+	}
+
+	second := run(2)
+	if len(second.Results) != 1 || second.HasMore || second.Offset != 2 {
+		t.Errorf("Second page: expected 1 result without has_more, got %d (has_more=%v)", len(second.Results), second.HasMore)
+	}
+	for _, p := range first.Results {
+		if p.Path == second.Results[0].Path {
+			t.Errorf("Expected pages not to overlap, %s is on both", p.Path)
+		}
+	}
+
+	if past := run(10); len(past.Results) != 0 || past.HasMore {
+		t.Errorf("Expected empty page past the end, got %d results (has_more=%v)", len(past.Results), past.HasMore)
+	}
+}
+
+// TestRunGroups_JSON tests syncing groups and searching them with --groups --json
+func TestRunGroups_JSON(t *testing.T) {
+	groupsJSON := `[{"id": 1, "name": "Platform", "full_path": "company/platform", "description": "Platform team"}, {"id": 2, "name": "Billing", "full_path": "company/billing"}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/groups" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(groupsJSON))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: server.URL, Token: "test-token", Timeout: 5},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+	client, err := gitlab.New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if count, err := syncGroups(tempDir, client); err != nil || count != 2 {
+		t.Fatalf("syncGroups: expected 2 groups, got %d (%v)", count, err)
+	}
+
+	// The user left the billing group: the next sync drops it
+	groupsJSON = `[{"id": 1, "name": "Platform", "full_path": "company/platform", "description": "Platform team"}]`
+	if _, err := syncGroups(tempDir, client); err != nil {
+		t.Fatalf("syncGroups failed: %v", err)
+	}
+
+	jsonOutput = true
+	defer func() { jsonOutput = false }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err = runGroups(cfg, "")
+
+	w.Close()
+	os.Stdout = oldStdout
+	if err != nil {
+		t.Fatalf("runGroups failed: %v", err)
+	}
+
+	output, _ := io.ReadAll(r)
+	var result JSONGroupsResult
+	if err := json.Unmarshal(output, &result); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v\n%s", err, output)
+	}
+	if result.Total != 1 || result.Groups[0].Path != "company/platform" {
+		t.Fatalf("Expected only company/platform, got %+v", result.Groups)
+	}
+	if want := server.URL + "/groups/company/platform"; result.Groups[0].URL != want {
+		t.Errorf("Expected URL %s, got %s", want, result.Groups[0].URL)
+	}
+}
+
+// TestEnsureGroupIndex_NoCacheOffline tests --groups --offline without synced groups
+func TestEnsureGroupIndex_NoCacheOffline(t *testing.T) {
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: t.TempDir()}}
+
+	offline = true
+	defer func() { offline = false }()
+
+	err := ensureGroupIndex(cfg)
+	if exitCodeFor(err) != exitCodeNoCache {
+		t.Errorf("Expected exit code %d, got %d (%v)", exitCodeNoCache, exitCodeFor(err), err)
+	}
+}
+
+// TestRunUsers_JSON tests --users --json: users are fetched once, then served from the cache
+func TestRunUsers_JSON(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/users" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": 7, "username": "jdoe", "name": "Jane Doe"}, {"id": 9, "username": "bsmith", "name": "Bob Smith"}]`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: server.URL, Token: "test-token", Timeout: 5, SyncUsers: true},
+		Cache:  config.CacheConfig{Dir: t.TempDir()},
+	}
+
+	jsonOutput = true
+	oldTarget := targetName
+	defer func() { jsonOutput, targetName = false, oldTarget }()
+
+	run := func(query string) JSONUsersResult {
+		t.Helper()
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := runUsers(cfg, query)
+
+		w.Close()
+		os.Stdout = oldStdout
+		if err != nil {
+			t.Fatalf("runUsers failed: %v", err)
+		}
+
+		output, _ := io.ReadAll(r)
+		var result JSONUsersResult
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Fatalf("Failed to parse JSON output: %v\n%s", err, output)
+		}
+		return result
+	}
+
+	result := run("")
+	if result.Total != 2 || requests != 1 {
+		t.Fatalf("Expected 2 users from 1 request, got %d users from %d requests", result.Total, requests)
+	}
+
+	targetName = "mrs"
+	result = run("smith")
+	if requests != 1 {
+		t.Errorf("Expected cached users to be reused, got %d API requests", requests)
+	}
+	if result.Total != 1 || result.Users[0].URL != server.URL+"/dashboard/merge_requests?assignee_username=bsmith" {
+		t.Errorf("Expected the assigned MRs of bsmith, got %+v", result.Users)
+	}
+}
+
+// TestRunUsers_Disabled tests that --users requires gitlab.sync_users
+func TestRunUsers_Disabled(t *testing.T) {
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: t.TempDir()}}
+
+	err := runUsers(cfg, "")
+	if exitCodeFor(err) != exitCodeUsage || !errors.Is(err, errUsersDisabled) {
+		t.Errorf("Expected usage error %v, got %v (exit code %d)", errUsersDisabled, err, exitCodeFor(err))
+	}
+}
+
+// TestQueryServer tests the --serve endpoints against a cached index
+func TestQueryServer(t *testing.T) {
+	cacheDir := t.TempDir()
+	if err := indexDescriptions([]model.Project{
+		{Path: "backend/api", Name: "API", Member: true},
+		{Path: "backend/billing", Name: "Billing", Member: true},
+		{Path: "frontend/web", Name: "Web", Member: true},
+	}, cacheDir, true, false); err != nil {
+		t.Fatalf("Failed to build index: %v", err)
+	}
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: cacheDir},
+	}
+	qs := &queryServer{cfg: cfg}
+	server := httptest.NewServer(qs.handler())
+	defer server.Close()
+	defer qs.closeIndex()
+
+	get := func(path string, v any) int {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		defer resp.Body.Close()
+		if v != nil {
+			if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+				t.Fatalf("GET %s: invalid JSON: %v", path, err)
+			}
+		}
+		return resp.StatusCode
+	}
+
+	var result JSONSearchResult
+	if status := get("/search?q=billing", &result); status != http.StatusOK {
+		t.Fatalf("Expected 200 from /search, got %d", status)
+	}
+	if result.Query != "billing" || len(result.Results) == 0 || result.Results[0].Path != "backend/billing" {
+		t.Errorf("Expected backend/billing first, got %+v", result)
+	}
+	if result.Results[0].URL != "https://gitlab.example.com/backend/billing" || result.Limit != defaultServeLimit {
+		t.Errorf("Expected the --json schema with the default limit, got %+v", result)
+	}
+
+	var jsonErr JSONError
+	if status := get("/search?q=api&limit=-1", &jsonErr); status != http.StatusBadRequest || jsonErr.Code != errorCodeUsage {
+		t.Errorf("Expected a usage error for a negative limit, got %d %+v", status, jsonErr)
+	}
+
+	var projects JSONSearchResult
+	if status := get("/projects", &projects); status != http.StatusOK || projects.Total != 3 {
+		t.Fatalf("Expected 3 projects, got %d %+v", status, projects)
+	}
+	if projects.Results[0].Path != "backend/api" || projects.Results[2].Path != "frontend/web" {
+		t.Errorf("Expected projects sorted by path, got %+v", projects.Results)
+	}
+
+	resp, err := http.Post(server.URL+"/record", "application/json", strings.NewReader(`{"path": "frontend/web", "query": "web"}`))
+	if err != nil {
+		t.Fatalf("POST /record failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204 from /record, got %d", resp.StatusCode)
+	}
+	hist := history.New(paths.HistoryPath(cacheDir))
+	if err := <-hist.LoadAsync(); err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if hist.GetAllScoresForQuery("web")["frontend/web"] == 0 {
+		t.Error("Expected the recorded selection in the history")
+	}
+
+	// Web pages are refused
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/projects", nil)
+	req.Header.Set("Origin", "https://example.com")
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected 403 for a request with an Origin header, got %v, %v", resp, err)
+	} else {
+		resp.Body.Close()
+	}
+}
+
+// TestRunServe_Address tests that --serve only listens on the loopback interface
+func TestRunServe_Address(t *testing.T) {
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: t.TempDir()}}
+	for _, addr := range []string{"0.0.0.0:7345", "7345", "192.168.1.10:7345"} {
+		if err := runServe(cfg, addr); exitCodeFor(err) != exitCodeUsage {
+			t.Errorf("runServe(%q) = %v, want a usage error", addr, err)
+		}
+	}
+	if err := runServe(cfg, ":7345"); exitCodeFor(err) != exitCodeNoCache {
+		t.Errorf("Expected a no-cache error without an index, got %v", err)
+	}
+}
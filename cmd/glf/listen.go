@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/igusev/glf/internal/cache"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/paths"
+)
+
+const (
+	// webhookQueueSize is the number of received events waiting to be applied to the index
+	webhookQueueSize = 256
+	// maxWebhookBody limits the size of a system hook payload
+	maxWebhookBody = 1 << 20
+)
+
+// System hook events applied by --listen (other events are acknowledged and ignored)
+const (
+	eventProjectCreate   = "project_create"
+	eventProjectUpdate   = "project_update"
+	eventProjectDestroy  = "project_destroy"
+	eventProjectRename   = "project_rename"
+	eventProjectTransfer = "project_transfer"
+)
+
+// webhookEvent is the part of a GitLab system hook payload glf uses
+type webhookEvent struct {
+	EventName            string `json:"event_name"`
+	ProjectID            int64  `json:"project_id"`
+	Name                 string `json:"name"`
+	PathWithNamespace    string `json:"path_with_namespace"`
+	OldPathWithNamespace string `json:"old_path_with_namespace"` // project_rename and project_transfer only
+}
+
+// supported reports whether the event changes the project index
+// Paths outside GitLab's path character set are not project paths and are refused
+func (e webhookEvent) supported() bool {
+	switch e.EventName {
+	case eventProjectCreate, eventProjectUpdate, eventProjectDestroy:
+		return validProjectPath(e.PathWithNamespace)
+	case eventProjectRename, eventProjectTransfer:
+		return validProjectPath(e.PathWithNamespace) && validProjectPath(e.OldPathWithNamespace)
+	}
+	return false
+}
+
+// validProjectPath reports whether path is a GitLab project path: "/"-separated segments of
+// letters, digits, "_", "-" and ".", none starting with "-" or made of dots only
+func validProjectPath(path string) bool {
+	if path == "" {
+		return false
+	}
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment == "" || segment[0] == '-' || strings.Trim(segment, ".") == "" {
+			return false
+		}
+		for _, r := range segment {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// webhookHandler receives system hook events and queues the supported ones
+// With a secret, requests must carry it in the X-Gitlab-Token header
+func webhookHandler(secret string, events chan<- webhookEvent) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if secret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Gitlab-Token")), []byte(secret)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		var event webhookEvent
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBody)).Decode(&event); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+		if !event.supported() {
+			logger.Debug("Ignoring %q event", event.EventName)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		select {
+		case events <- event:
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			// GitLab retries failed system hooks
+			http.Error(w, "event queue full", http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// eventApplier applies system hook events to the project index
+type eventApplier struct {
+	cacheDir string
+	stateDir string                                       // History location for rename remapping (cache.state_dir)
+	fetch    func(projectID int64) (model.Project, error) // Full project details from GitLab
+}
+
+// apply updates the index for one event
+// The index is opened per event, so searches and syncs can use it in between
+func (a *eventApplier) apply(event webhookEvent) error {
+	descIndex, err := index.NewDescriptionIndex(paths.IndexPath(a.cacheDir))
+	if err != nil {
+		if errors.Is(err, index.ErrIndexVersionMismatch) {
+			return fmt.Errorf("index schema is outdated, run 'glf --sync': %w", err)
+		}
+		return err
+	}
+	defer func() {
+		if err := descIndex.Close(); err != nil {
+			logger.Debug("Failed to close index: %v", err)
+		}
+	}()
+
+	switch event.EventName {
+	case eventProjectDestroy:
+		if err := a.confirmDestroyed(event); err != nil {
+			return err
+		}
+		if err := descIndex.Delete(event.PathWithNamespace); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", event.PathWithNamespace, err)
+		}
+		logger.Info("Removed %s", event.PathWithNamespace)
+
+	case eventProjectRename, eventProjectTransfer:
+		project, err := a.project(event)
+		if err != nil {
+			return err
+		}
+		if err := descIndex.Delete(event.OldPathWithNamespace); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", event.OldPathWithNamespace, err)
+		}
+		renames, err := index.LoadRenames(paths.RenamesPath(a.cacheDir))
+		if err != nil {
+			logger.Debug("Failed to load renames: %v", err)
+		}
+		renames.Record(event.OldPathWithNamespace, event.PathWithNamespace)
+		if err := renames.Save(paths.RenamesPath(a.cacheDir)); err != nil {
+			logger.Debug("Failed to save renames: %v", err)
+		}
+		project.FormerPaths = renames.FormerPaths(project.Path)
+		if err := descIndex.AddBatch([]index.DescriptionDocument{index.NewDocument(project)}); err != nil {
+			return fmt.Errorf("failed to index %s: %w", event.PathWithNamespace, err)
+		}
+		remapped := remapHistory(a.stateDir, map[string]string{event.OldPathWithNamespace: event.PathWithNamespace})
+		logger.Info("Renamed %s → %s", event.OldPathWithNamespace, event.PathWithNamespace)
+		logger.Debug("Remapped %d history entries recorded by path", remapped)
+
+	default: // project_create, project_update
+		project, err := a.project(event)
+		if err != nil {
+			return err
+		}
+		docs := []index.DescriptionDocument{}
+		if renames, err := index.LoadRenames(paths.RenamesPath(a.cacheDir)); err == nil {
+			project.FormerPaths = renames.FormerPaths(project.Path) // Keep the aliases of renamed projects
+
+			// A project at a renamed project's former path takes that path over
+			if current, ok := renames[project.Path]; ok {
+				delete(renames, project.Path)
+				if err := renames.Save(paths.RenamesPath(a.cacheDir)); err != nil {
+					logger.Debug("Failed to save renames: %v", err)
+				}
+				if renamed, found, err := descIndex.GetProject(current); err == nil && found {
+					renamed.FormerPaths = renames.FormerPaths(current)
+					docs = append(docs, index.NewDocument(renamed))
+				}
+			}
+		}
+		docs = append(docs, index.NewDocument(project))
+		if err := descIndex.AddBatch(docs); err != nil {
+			return fmt.Errorf("failed to index %s: %w", event.PathWithNamespace, err)
+		}
+		logger.Info("Indexed %s (%s)", event.PathWithNamespace, event.EventName)
+	}
+
+	if err := descIndex.SaveSnapshot(); err != nil {
+		logger.Debug("Failed to save project snapshot: %v", err)
+	}
+	return nil
+}
+
+// project fetches the project an event refers to from GitLab (system hook payloads only carry
+// the path and name). The payload is never indexed as given: an event for a project GitLab
+// doesn't know at that path is dropped, and the next sync picks up whatever changed since
+func (a *eventApplier) project(event webhookEvent) (model.Project, error) {
+	if event.ProjectID == 0 {
+		return model.Project{}, errors.New("event without project ID dropped")
+	}
+	project, err := a.fetch(event.ProjectID)
+	if err != nil {
+		return model.Project{}, fmt.Errorf("event dropped: %w", err)
+	}
+	if project.Path != event.PathWithNamespace {
+		return model.Project{}, fmt.Errorf("event dropped: project %d is at %s on GitLab", event.ProjectID, project.Path)
+	}
+	return project, nil
+}
+
+// confirmDestroyed checks with GitLab that the project of a project_destroy event is gone,
+// so an event can't remove a project that still exists
+func (a *eventApplier) confirmDestroyed(event webhookEvent) error {
+	if event.ProjectID == 0 {
+		return errors.New("event without project ID dropped")
+	}
+	project, err := a.fetch(event.ProjectID)
+	switch {
+	case errors.Is(err, gitlab.ErrProjectNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("event dropped: %w", err)
+	default:
+		return fmt.Errorf("event dropped: project %d still exists at %s", event.ProjectID, project.Path)
+	}
+}
+
+// runListen handles --listen: serves a system hook endpoint and applies project events
+// to the index until interrupted
+func runListen(cfg *config.Config, addr string) error {
+	if offline {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--listen cannot be used with --offline"))
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return withExitCode(exitCodeUsage, fmt.Errorf("invalid --listen address %q: %w", addr, err))
+	}
+	if cfg.GitLab.WebhookSecret == "" {
+		// Without a secret anyone who can reach the endpoint could change the index
+		if host != "" && !isLoopbackHost(host) {
+			return withHint(withExitCode(exitCodeConfig, fmt.Errorf("--listen on %s requires gitlab.webhook_secret", host)),
+				fmt.Sprintf("set the system hook's secret token as gitlab.webhook_secret, or listen on localhost:%s", port))
+		}
+		if host == "" {
+			host = "localhost"
+		}
+		addr = net.JoinHostPort(host, port)
+		logger.Warn("gitlab.webhook_secret is not set: only listening on %s", addr)
+	}
+
+	client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout(), cfg.GitLab.Concurrency)
+	if err != nil {
+		return fmt.Errorf("failed to create GitLab client: %w", err)
+	}
+	starred, member, err := cache.New(cfg.Cache.Dir).LoadProjectSets()
+	if err != nil {
+		logger.Debug("Failed to load cached project sets: %v", err)
+	}
+	client.SetCachedProjectSets(starred, member)
+	applier := &eventApplier{cacheDir: cfg.Cache.Dir, stateDir: cfg.Cache.GetStateDir(), fetch: client.GetProject}
+
+	events := make(chan webhookEvent, webhookQueueSize)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range events {
+			if err := applier.apply(event); err != nil {
+				logger.Warn("Failed to apply %s event for %s: %v", event.EventName, event.PathWithNamespace, err)
+			}
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/", webhookHandler(cfg.GitLab.WebhookSecret, events))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.ListenAndServe() }()
+	logger.Info("Listening for GitLab system hook events on %s (Ctrl+C to stop)", addr)
+
+	var listenErr error
+	select {
+	case err := <-serveErr:
+		listenErr = fmt.Errorf("webhook listener failed: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil {
+			logger.Debug("Listener shutdown: %v", shutdownErr)
+		}
+	}
+
+	// Apply what was already accepted before exiting
+	close(events)
+	<-done
+	return listenErr
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/igusev/glf/internal/browser"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/oauth"
+	"github.com/igusev/glf/internal/tokenstore"
+)
+
+// newOAuthClient creates the client of the device flow (overridable in tests)
+var newOAuthClient = oauth.NewClient
+
+// runLogin signs in with the OAuth device authorization flow and stores the credentials
+// in the token backend; later runs refresh the access token automatically
+func runLogin() error {
+	cfg, err := config.LoadWithoutToken()
+	if errors.Is(err, config.ErrConfigNotFound) {
+		return fmt.Errorf("configuration error: %w (set gitlab.url in config.yaml or GLF_GITLAB_URL)", err)
+	}
+	if err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	browserOpener = browser.New(cfg.BrowserCommand)
+
+	if cfg.GitLab.OAuthClientID == "" {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--login needs gitlab.oauth_client_id: create an OAuth application at %s/-/user_settings/applications (not confidential, scope %s) and set its application ID", cfg.GitLab.URL, oauth.DefaultScope))
+	}
+	backend := cfg.GitLab.TokenBackend
+	if tokenstore.IsExternal(backend) && !tokenstore.IsWritable(backend) {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--login cannot store credentials with token_backend %q (use config, keychain, secret-service or wincred)", backend))
+	}
+
+	// Ctrl+C stops waiting for the authorization
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := newOAuthClient(cfg.GitLab.URL, cfg.GitLab.OAuthClientID, cfg.GitLab.GetTimeout())
+	code, err := client.RequestDeviceCode(ctx, oauth.DefaultScope)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔑 Open %s and enter the code: %s\n", code.VerificationURI, code.UserCode)
+	verifyURL := code.VerificationURIComplete
+	if verifyURL == "" {
+		verifyURL = code.VerificationURI
+	}
+	if err := openBrowser(verifyURL); err != nil {
+		logger.Debug("Failed to open browser: %v", err)
+	}
+	logger.Info("Waiting for authorization...")
+
+	creds, err := client.PollToken(ctx, code)
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	if err := config.SaveOAuth(backend, cfg.GitLab.URL, creds); err != nil {
+		return err
+	}
+	if tokenstore.IsWritable(backend) {
+		logger.Success("Credentials saved to %s", backend)
+	} else {
+		logger.Success("Credentials saved to %s", config.OAuthPath())
+	}
+
+	gitlab.SetBearerAuth(true)
+	if glClient, err := gitlab.New(cfg.GitLab.URL, creds.AccessToken, cfg.GitLab.GetTimeout()); err == nil {
+		if username, err := glClient.GetCurrentUsername(); err == nil {
+			logger.Success("Logged in to %s as @%s", cfg.GitLab.URL, username)
+		} else {
+			logger.Warn("Logged in, but the API check failed: %v", err)
+		}
+	}
+
+	// An explicit token wins over the login
+	if cfg.GitLab.Token != "" {
+		logger.Warn("gitlab.token (or GLF_GITLAB_TOKEN) is set and takes precedence; remove it to use the login")
+	}
+	return nil
+}
@@ -4,16 +4,19 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/igusev/glf/internal/browser"
 	"github.com/igusev/glf/internal/cache"
 	"github.com/igusev/glf/internal/config"
 	"github.com/igusev/glf/internal/gitlab"
@@ -21,7 +24,10 @@ import (
 	"github.com/igusev/glf/internal/index"
 	"github.com/igusev/glf/internal/logger"
 	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/paths"
 	"github.com/igusev/glf/internal/search"
+	"github.com/igusev/glf/internal/target"
+	"github.com/igusev/glf/internal/tokenstore"
 	"github.com/igusev/glf/internal/tui"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -38,6 +44,7 @@ var (
 const (
 	syncModeFull        = "full"
 	syncModeIncremental = "incremental"
+	syncModeStarred     = "starred" // --sync --starred
 	responseYes         = "yes"
 )
 
@@ -48,50 +55,153 @@ const (
 	platformWindows = "windows"
 )
 
+// Read-through fallback settings (search.remote_fallback)
+const (
+	remoteSearchTimeout = 10 * time.Second
+	remoteSearchLimit   = 20
+)
+
+// issuesLimit is the number of open issues fetched for the TUI issues mode
+const issuesLimit = 100
+
+// jsonSchemaVersion is the version of the --json search response (bumped on incompatible changes)
+// v2 added schema_version, offset, has_more, cache_synced_at, instance and version
+const jsonSchemaVersion = 2
+
+// searchCandidatePage rounds up full-text candidates fetched for --offset pagination,
+// so consecutive pages within one block are ranked from the same candidate set
+const searchCandidatePage = 100
+
 // JSON output structures for API integrations
 type (
 	// JSONSearchResult represents the complete search response in JSON mode
 	JSONSearchResult struct {
-		Query   string        `json:"query"`   // Search query that was executed
-		Results []JSONProject `json:"results"` // Matching projects
-		Total   int           `json:"total"`   // Total number of results
-		Limit   int           `json:"limit"`   // Maximum results returned
+		SchemaVersion int           `json:"schema_version"`            // Response schema version (jsonSchemaVersion)
+		Query         string        `json:"query"`                     // Search query that was executed
+		Results       []JSONProject `json:"results"`                   // Matching projects
+		Total         int           `json:"total"`                     // Number of results in this page
+		Limit         int           `json:"limit"`                     // Maximum results returned
+		Offset        int           `json:"offset"`                    // Number of results skipped (--offset)
+		HasMore       bool          `json:"has_more"`                  // Whether more results follow this page
+		CacheSyncedAt *time.Time    `json:"cache_synced_at,omitempty"` // Last successful sync (absent if never synced)
+		Suggestions   []string      `json:"suggestions,omitempty"`     // Closest project paths when nothing matched ("Did you mean")
+		Instance      string        `json:"instance"`                  // GitLab instance URL
+		Version       string        `json:"version"`                   // glf version
 	}
 
 	// JSONProject represents a single project in JSON output
 	JSONProject struct {
-		Path        string  `json:"path"`            // Project path (e.g., "group/project")
-		Name        string  `json:"name"`            // Project name
-		Description string  `json:"description"`     // Project description
-		URL         string  `json:"url"`             // Full project URL
-		Starred     bool    `json:"starred"`         // Whether the project is starred by the user
-		Excluded    bool    `json:"excluded"`        // Whether the project is excluded via config
-		Archived    bool    `json:"archived"`        // Whether the project is archived
-		Member      bool    `json:"member"`          // Whether the user is a member of this project
-		Score       float64 `json:"score,omitempty"` // Relevance score (optional, with --scores)
+		Path        string   `json:"path"`                  // Project path (e.g., "group/project")
+		Name        string   `json:"name"`                  // Project name
+		Description string   `json:"description"`           // Project description
+		URL         string   `json:"url"`                   // Full project URL
+		Starred     bool     `json:"starred"`               // Whether the project is starred by the user
+		Excluded    bool     `json:"excluded"`              // Whether the project is excluded via config
+		Archived    bool     `json:"archived"`              // Whether the project is archived
+		Member      bool     `json:"member"`                // Whether the user is a member of this project
+		Topics      []string `json:"topics,omitempty"`      // Project topics
+		OpenMRs     int      `json:"open_mrs,omitempty"`    // Open merge requests (with gitlab.insights)
+		OpenIssues  int      `json:"open_issues,omitempty"` // Open issues (with gitlab.insights)
+
+		DefaultBranch  string     `json:"default_branch,omitempty"`   // Default branch (e.g., "main")
+		Visibility     string     `json:"visibility,omitempty"`       // private, internal or public
+		LastActivityAt *time.Time `json:"last_activity_at,omitempty"` // Last activity on the project
+		ForkedFrom     string     `json:"forked_from,omitempty"`      // Path of the upstream project (forks only)
+		Forks          []string   `json:"forks,omitempty"`            // Forks listed under this project (search.collapse_forks)
+		FormerPaths    []string   `json:"former_paths,omitempty"`     // Paths before renames seen by sync
+		Labels         []string   `json:"labels,omitempty"`           // Labels from annotations.yaml
+
+		Remote bool    `json:"remote,omitempty"` // Found by a live GitLab search, not in the local cache yet
+		Pinned bool    `json:"pinned,omitempty"` // Pinned project (always at the top of results)
+		Score  float64 `json:"score,omitempty"`  // Relevance score (optional, with --scores)
+	}
+
+	// JSONSyncResult represents the --sync result in JSON mode
+	JSONSyncResult struct {
+		ProjectsFetched int      `json:"projects_fetched"` // Projects returned by GitLab (changed ones for incremental syncs)
+		Changed         int      `json:"changed"`          // Projects added, renamed or removed (incremental: every fetched project)
+		Indexed         int      `json:"indexed"`          // Projects written to the search index
+		Mode            string   `json:"mode"`             // "full", "incremental" or "starred"
+		Groups          int      `json:"groups,omitempty"` // Groups indexed for --groups
+		Users           int      `json:"users,omitempty"`  // Users cached for --users (gitlab.sync_users)
+		DurationMs      int64    `json:"duration_ms"`      // Total sync duration in milliseconds
+		Errors          []string `json:"errors"`           // Errors and warnings (empty on a clean sync)
+
+		stats indexStats // Index changes, for the TUI sync toast (not part of the JSON output)
 	}
 
 	// JSONError represents an error response in JSON mode
 	JSONError struct {
-		Error string `json:"error"` // Error message
+		Error   string `json:"error"`              // Error message (same as message, kept for older integrations)
+		Code    string `json:"code"`               // Error kind: usage, no_results, no_cache, sync_failed, config, index, search or error
+		Message string `json:"message"`            // Error message
+		Hint    string `json:"hint,omitempty"`     // How to fix the error, if known
+		TraceID string `json:"trace_id,omitempty"` // Invocation trace ID for support tickets
 	}
 )
 
 var (
-	verbose      bool   // Flag to enable verbose logging
-	showScores   bool   // Flag to show score breakdown (search + history)
-	autoGo       bool   // Flag to automatically select first result and open in browser
-	doSync       bool   // Flag to perform sync instead of search
-	forceFull    bool   // Flag to force full sync (ignore incremental)
-	doInit       bool   // Flag to run interactive configuration wizard
-	resetFlag    bool   // Flag to reset configuration and start from scratch
-	jsonOutput   bool   // Flag to enable JSON output mode for API integrations
-	limitResults int    // Flag to limit number of results in JSON mode
-	showHistory  bool   // Flag to display search history
-	clearHistory bool   // Flag to clear search history
-	showHidden   bool   // Flag to show hidden projects (excluded, archived, non-member) - affects TUI initial state and JSON output
-	jsonRecord   string // Flag to record project selection in history (for JSON integrations like Raycast)
-	queryContext string // Flag to provide query context when recording selection
+	verbose        bool   // Flag to enable verbose logging
+	showScores     bool   // Flag to show score breakdown (search + history)
+	autoGo         bool   // Flag to automatically select first result and open in browser
+	doSync         bool   // Flag to perform sync instead of search
+	forceFull      bool   // Flag to force full sync (ignore incremental)
+	dryRun         bool   // Flag to report what a sync would change without applying it
+	fromSnapshot   string // Flag to seed the cache from a team snapshot (URL or file) before syncing
+	serveAddr      string // Flag to serve the query API for editor plugins on an address (e.g., :7345)
+	doInit         bool   // Flag to run interactive configuration wizard
+	resetFlag      bool   // Flag to reset configuration and start from scratch
+	assumeYes      bool   // Flag to run --init without prompts (answering yes to confirmations)
+	initURL        string // Flag to set the GitLab URL for --init --yes
+	tokenStdin     bool   // Flag to read the token for --init --yes from stdin
+	jsonOutput     bool   // Flag to enable JSON output mode for API integrations
+	limitResults   int    // Flag to limit number of results in JSON mode
+	offsetResults  int    // Flag to skip results in JSON mode (pagination)
+	sortBy         string // Flag to order JSON results by score, path, name or activity
+	showHistory    bool   // Flag to display search history
+	clearHistory   bool   // Flag to clear search history
+	showHidden     bool   // Flag to show hidden projects (excluded, archived, non-member) - affects TUI initial state and JSON output
+	jsonRecord     string // Flag to record project selection in history (for JSON integrations like Raycast)
+	queryContext   string // Flag to provide query context when recording selection
+	targetName     string // Flag to open a project sub-page (e.g., "mrs", "pipelines") instead of the project root
+	pickTarget     bool   // Flag to choose a project sub-page interactively (with "glf .")
+	offline        bool   // Flag to never touch the network (no username fetch, auto-sync, or background sync)
+	pinPath        string // Flag to pin a project (always shown at the top of results)
+	unpinPath      string // Flag to unpin a project
+	unpinQueryText string // Flag to remove the pin of a query (search.pin_after)
+	listPins       bool   // Flag to list pinned projects
+	remapHist      bool   // Flag to move history from an old project/group path to a new one
+	showStatus     bool   // Flag to show cache freshness and on-disk size
+	doDoctor       bool   // Flag to check the index, sync times, history and lock files and repair problems
+	regexMode      bool   // Flag to match project paths with a Go regular expression instead of full-text search
+	noSync         bool   // Flag to never start a sync automatically (empty cache, schema update, stale cache)
+	nonInteractive bool   // Flag to never prompt, launch the TUI, or open a browser
+	ciMode         bool   // Flag for CI usage: --no-sync + --non-interactive + --json with defined exit codes
+	showMRs        bool   // Flag to search the user's open merge requests instead of projects
+	showGroups     bool   // Flag to search groups/subgroups instead of projects
+	showUsers      bool   // Flag to search the instance's users instead of projects
+	doUpdate       bool   // Flag to replace the binary with the latest GitHub release
+	explainPath    string // Flag to explain how a project's history score was computed (with --history)
+	topQueries     bool   // Flag to list the most frequently used search queries
+	historyStats   bool   // Flag to break selections down by source and week (with --history)
+	editMode       bool   // Flag to open the local clone of the first result in an editor
+	cdMode         bool   // Flag to print the local clone path of the selected project (for the gcd shell function)
+	shellInit      string // Flag to print the gcd shell function for the given shell
+	listenAddr     string // Flag to serve a GitLab system hook endpoint that updates the index
+	openFile       bool   // Flag to open a file of the project (the path after "--") instead of the project root
+	filePath       string // File opened with --file (the argument after "--"; empty = asked in the TUI)
+	starredOnly    bool   // Flag to only refresh starred and member projects (with --sync)
+	formatTemplate string // Flag to print each search result through a Go template instead of JSON
+	plainOutput    bool   // Flag to print "path<TAB>description" lines for pickers (fzf, rofi, dmenu)
+	allResults     bool   // Flag to return every match in JSON mode, --format and --plain (no limit, all candidates)
+	print0Output   bool   // Flag to end --plain records with a NUL byte instead of a newline
+	urlsOutput     bool   // Flag to print the URL of each search result, one per line
+	vimOutput      bool   // Flag to print "path<TAB>score<TAB>ranges" lines with matched byte offsets for editor plugins
+	selectOne      bool   // Flag to open the only match of a query without the TUI
+	resumeSession  bool   // Flag to restore the last TUI session (query, filter toggles, selected result)
+	openWith       string // Flag to run the named opener on the selected project instead of opening the browser
+	doLogin        bool   // Flag to sign in with the GitLab OAuth device flow instead of a personal access token
+	newProjectPath string // Flag to create a project at the given path (group/name) and open it
 )
 
 var rootCmd = &cobra.Command{
@@ -102,7 +212,7 @@ It uses a local cache for blazing-fast performance.
 
 Getting Started:
   1. Create config: ~/.config/glf/config.yaml
-  2. Run: glf --sync (to fetch projects)
+  2. Run: glf sync (to fetch projects)
   3. Run: glf (interactive mode) or glf <query> (direct search)
 
 Examples:
@@ -110,10 +220,15 @@ Examples:
   glf backend          # Direct search for "backend"
   glf api ingress      # Multi-word search for "api ingress"
   glf .                # Open current Git repository in browser
-  glf sync             # Search for "sync" (not a command!)
-  glf --sync           # Synchronize projects cache
-  glf --sync --full    # Force full sync
-  glf -g api           # Auto-select first result and open in browser
+  glf . mrs            # Open merge requests of current repository
+  glf . --pick         # Choose a sub-page of current repository
+  glf sync             # Synchronize projects cache (same as --sync)
+  glf sync --full      # Force full sync
+  glf search sync      # Search for "sync" (a command name)
+  glf --json sync      # Also "sync": commands are not run with --json, --format or --plain
+  glf open api         # Open the first result in browser (same as -g api)
+  glf --offline api    # Search the local cache without any network access
+  glf --mrs            # Fuzzy search my open merge requests
 
 Configuration:
   Set your GitLab URL and token in ~/.config/glf/config.yaml or via environment:
@@ -128,53 +243,319 @@ Configuration:
 
 // runSearch handles the default search behavior
 func runSearch(cmd *cobra.Command, args []string) error {
+	applyCIMode()
+
 	// Handle --init flag first (before loading config)
+	if (initURL != "" || tokenStdin) && !(doInit && assumeYes) {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--url and --token-stdin must be used with --init --yes"))
+	}
 	if doInit {
-		return runConfigWizard()
+		if assumeYes {
+			return runInitNonInteractive(os.Stdin)
+		}
+		if err := requireInteractive("--init"); err != nil {
+			return err
+		}
+		return runConfigWizard("")
 	}
 
-	// Load configuration
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("configuration error: %w", err)
+	// Handle --update flag (doesn't need configuration)
+	if doUpdate {
+		return runUpdate()
+	}
+
+	// Handle --shell-init flag (prints the gcd shell function; doesn't need configuration)
+	if shellInit != "" {
+		return runShellInit(shellInit)
+	}
+
+	// Handle --login flag (obtains the token, so the configuration may not have one yet)
+	if doLogin {
+		return runLogin()
 	}
 
-	// Handle --history flag (show history and exit)
+	// Load configuration (--offline keeps an expired OAuth token instead of refreshing it)
+	load := config.Load
+	if offline {
+		load = config.LoadOffline
+	}
+	cfg, err := load()
+	if errors.Is(err, config.ErrConfigNotFound) {
+		// Inside a repository hosted on a self-hosted GitLab, offer the wizard for that instance
+		if offered, err := setupFromRemote(); offered {
+			return err
+		}
+		return withHint(withExitCode(exitCodeConfig, fmt.Errorf("configuration error: %w", err)),
+			"run 'glf --init', or set GLF_GITLAB_URL and GLF_GITLAB_TOKEN")
+	}
+	if err != nil {
+		return withExitCode(exitCodeConfig, fmt.Errorf("configuration error: %w", err))
+	}
+	history.SetDefaultDecay(cfg.History.HalfLifeDays, cfg.History.MaxAgeDays)
+	history.SetDefaultContextRanking(cfg.History.ContextRanking)
+	history.SetDefaultEnabled(cfg.History.Enabled)
+	history.SetDefaultRetention(cfg.History.RetentionDays)
+	search.SetArchivedPenalty(cfg.Scoring.ArchivedPenalty)
+	search.SetForkPenalty(cfg.Scoring.ForkPenalty)
+	loadAnnotations(cfg)
+	if err := history.SetDefaultAlgorithm(cfg.History.Algorithm); err != nil {
+		return withExitCode(exitCodeConfig, fmt.Errorf("configuration error: history.algorithm: %w", err))
+	}
+	browserOpener = browser.New(cfg.BrowserCommand)
+	gitlab.SetBearerAuth(cfg.GitLab.OAuth)
+	gitlab.SetRetryPolicy(cfg.GitLab.MaxRetries, cfg.GitLab.GetRetryBackoff())
+	readOnlyCache = cfg.Cache.IsReadOnly()
+	index.SetReadOnly(readOnlyCache)
+	index.SetFuzziness(cfg.Search.GetFuzziness())
+	target.SetTemplates(cfg.URLTemplates)
+	if readOnlyCache {
+		logger.Debug("Using read-only cache %s (state in %s)", cfg.Cache.Dir, cfg.Cache.GetStateDir())
+	}
+
+	// Handle --history flag (show history or explain a project's score and exit)
 	if showHistory {
+		if explainPath != "" {
+			query := strings.Join(args, " ")
+			if query == "" {
+				query = queryContext
+			}
+			return runExplainHistory(cfg, explainPath, query)
+		}
+		if topQueries {
+			return runTopQueries(cfg)
+		}
+		if historyStats {
+			return runHistoryStats(cfg)
+		}
+		if queryContext != "" {
+			return runShowQueryHistory(cfg, queryContext)
+		}
 		return runShowHistory(cfg)
 	}
+	if topQueries {
+		return runTopQueries(cfg)
+	}
+	if explainPath != "" {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--explain must be used with --history"))
+	}
+	if historyStats {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--stats must be used with --history"))
+	}
+	if openWith != "" {
+		if _, err := findOpener(cfg, openWith); err != nil {
+			return err
+		}
+	}
+	if offsetResults < 0 {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--offset must not be negative"))
+	}
+	if err := validateSort(sortBy); err != nil {
+		return withExitCode(exitCodeUsage, err)
+	}
+
+	// Handle --format: search results go through the template instead of JSON (--ci keeps its other guarantees)
+	if formatTemplate != "" {
+		if ((jsonOutput || autoGo) && !ciMode) || openFile || editMode || cdMode || doSync || showGroups || showMRs || showUsers {
+			return withExitCode(exitCodeUsage, fmt.Errorf("--format cannot be used with --json, --go, --file, --edit, --cd, --sync, --groups, --mrs or --users"))
+		}
+		if _, err := parseFormat(formatTemplate); err != nil {
+			return withExitCode(exitCodeUsage, err)
+		}
+	}
+
+	// Handle --plain/--print0: picker records instead of JSON, every match unless --limit is given
+	if print0Output {
+		plainOutput = true
+	}
+	if plainOutput {
+		if ((jsonOutput || autoGo) && !ciMode) || formatTemplate != "" || openFile || editMode || cdMode || doSync || showGroups || showMRs || showUsers {
+			return withExitCode(exitCodeUsage, fmt.Errorf("--plain and --print0 cannot be used with --json, --go, --format, --file, --edit, --cd, --sync, --groups, --mrs or --users"))
+		}
+		if !cmd.Flags().Changed("limit") {
+			limitResults = 0
+		}
+	}
+
+	// Handle --urls: the URL of every match (or of its --target page) per line, every match unless --limit is given
+	if urlsOutput {
+		if ((jsonOutput || autoGo) && !ciMode) || formatTemplate != "" || plainOutput || openFile || editMode || cdMode || doSync || showGroups || showMRs || showUsers {
+			return withExitCode(exitCodeUsage, fmt.Errorf("--urls cannot be used with --json, --go, --format, --plain, --file, --edit, --cd, --sync, --groups, --mrs or --users"))
+		}
+		if !cmd.Flags().Changed("limit") {
+			limitResults = 0
+		}
+	}
+
+	// Handle --vim: result lines with the matched ranges of each path for editor plugins
+	if vimOutput {
+		if ((jsonOutput || autoGo) && !ciMode) || formatTemplate != "" || plainOutput || urlsOutput || openFile || editMode || cdMode || doSync || showGroups || showMRs || showUsers {
+			return withExitCode(exitCodeUsage, fmt.Errorf("--vim cannot be used with --json, --go, --format, --plain, --urls, --file, --edit, --cd, --sync, --groups, --mrs or --users"))
+		}
+	}
+
+	// Handle --all: every match, ranked over the whole cache and streamed
+	if allResults {
+		if !jsonOutput && formatTemplate == "" && !plainOutput && !urlsOutput && !vimOutput {
+			return withExitCode(exitCodeUsage, fmt.Errorf("--all only applies to --json, --format, --plain, --urls and --vim"))
+		}
+		if cmd.Flags().Changed("limit") {
+			return withExitCode(exitCodeUsage, fmt.Errorf("--all cannot be used with --limit"))
+		}
+		limitResults = 0
+	}
+	// --select-1 opens the only match instead of the TUI, or with --plain/--print0 prints its record
+	if selectOne && (jsonOutput || autoGo || formatTemplate != "" || urlsOutput || vimOutput) {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--select-1 cannot be used with --json, --go, --format, --urls or --vim"))
+	}
+
+	// Handle --file: the project query comes before "--", the file path after it
+	if openFile {
+		if targetName != "" || jsonOutput || editMode || cdMode || showGroups {
+			return withExitCode(exitCodeUsage, fmt.Errorf("--file cannot be used with --target, --json, --edit, --cd or --groups"))
+		}
+		if args, filePath, err = splitFileArgs(args, cmd.ArgsLenAtDash()); err != nil {
+			return withExitCode(exitCodeUsage, err)
+		}
+	}
 
 	// Handle --clear-history flag (clear history and exit)
 	if clearHistory {
 		return runClearHistory(cfg)
 	}
 
+	// Handle --status flag (show cache status and exit)
+	if showStatus {
+		return runStatus(cfg)
+	}
+
+	// Handle --doctor flag (check and repair the cache and exit)
+	if doDoctor {
+		return runDoctor(cfg, os.Stdin)
+	}
+
+	// Handle --remap-history flag (move history to a renamed path and exit)
+	if remapHist {
+		return runRemapHistory(cfg, args)
+	}
+
+	// Handle --new flag (create a project and exit)
+	if newProjectPath != "" {
+		return runNewProject(cfg, newProjectPath, args)
+	}
+
+	// Handle --pin/--unpin/--unpin-query/--pins flags (manage pinned projects and queries and exit)
+	if pinPath != "" || unpinPath != "" || unpinQueryText != "" || listPins {
+		return runPins(cfg)
+	}
+
 	// Handle --json-record flag (record selection in history and exit)
 	if jsonRecord != "" {
 		return runRecordSelection(cfg, jsonRecord, queryContext)
 	}
 
-	// Handle "glf ." - open current Git repository
-	if len(args) == 1 && args[0] == "." {
-		return runOpenCurrent(cfg)
+	// Handle --mrs flag (search my open merge requests; --sync refreshes them)
+	if showMRs {
+		return runMergeRequests(cfg, strings.Join(args, " "))
+	}
+
+	// Handle --users flag (search users; --sync refreshes them)
+	if showUsers {
+		return runUsers(cfg, strings.Join(args, " "))
+	}
+
+	// Handle --groups flag (search groups; the TUI starts in groups mode, see runInteractive)
+	if showGroups {
+		if err := ensureGroupIndex(cfg); err != nil {
+			return err
+		}
+		if jsonOutput || autoGo || nonInteractive {
+			return runGroups(cfg, strings.Join(args, " "))
+		}
+	}
+
+	// Handle "glf ." - open current Git repository (optionally a sub-page: "glf . mrs")
+	if len(args) >= 1 && args[0] == "." {
+		if len(args) > 2 {
+			return fmt.Errorf("'glf .' accepts at most one sub-page (e.g., 'glf . mrs')")
+		}
+		page := targetName
+		if len(args) == 2 {
+			page = args[1]
+		}
+		return runOpenCurrent(cfg, page)
 	}
 
 	// Handle sync mode
+	if dryRun && !doSync {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--dry-run must be used with --sync"))
+	}
+	if starredOnly && !doSync {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--starred must be used with --sync"))
+	}
+	if fromSnapshot != "" && !doSync {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--from-snapshot must be used with --sync"))
+	}
 	if doSync {
-		return performSyncInternal(cfg, false, forceFull)
+		if offline {
+			return withExitCode(exitCodeUsage, fmt.Errorf("--sync cannot be used with --offline"))
+		}
+		if readOnlyCache && !dryRun {
+			return errReadOnlyCache(cfg.Cache.Dir)
+		}
+		if fromSnapshot != "" {
+			return runSyncFromSnapshot(cfg, fromSnapshot)
+		}
+		if starredOnly {
+			if forceFull || dryRun {
+				return withExitCode(exitCodeUsage, fmt.Errorf("--starred cannot be used with --full or --dry-run"))
+			}
+			return runSyncStarred(cfg)
+		}
+		if dryRun {
+			return runSyncDryRun(cfg)
+		}
+		if jsonOutput {
+			return runSyncJSON(cfg)
+		}
+		if err := performSyncInternal(cfg, ciMode, forceFull); err != nil {
+			return withExitCode(exitCodeSyncFailed, err)
+		}
+		return nil
+	}
+
+	// Handle --listen (system hook events keep the index up to date between syncs)
+	if listenAddr != "" {
+		if readOnlyCache {
+			return errReadOnlyCache(cfg.Cache.Dir)
+		}
+		return runListen(cfg, listenAddr)
+	}
+
+	// Handle --serve (local query API for editor plugins)
+	if serveAddr != "" {
+		return runServe(cfg, serveAddr)
 	}
 
 	// Open description index
-	indexPath := filepath.Join(cfg.Cache.Dir, "description.bleve")
+	indexPath := paths.IndexPath(cfg.Cache.Dir)
 
 	descIndex, recreated, err := index.NewDescriptionIndexWithAutoRecreate(indexPath)
+	if errors.Is(err, index.ErrReadOnly) {
+		return withExitCode(exitCodeNoCache, fmt.Errorf("failed to open index: %w", err))
+	}
 	if err != nil {
-		return fmt.Errorf("failed to open index: %w", err)
+		return withHint(withErrorCode(errorCodeIndex, exitCodeError, fmt.Errorf("failed to open index: %w", err)),
+			fmt.Sprintf("remove %s and run 'glf --sync' to rebuild it", indexPath))
 	}
 
-	// If index was recreated due to version mismatch, trigger full sync
+	// If index was recreated (version mismatch) or restored from the previous generation
+	// (damaged index), trigger full sync
 	if recreated {
-		logger.Info("Index schema updated, performing full sync to rebuild cache...")
+		if autoSyncDisabled() {
+			_ = descIndex.Close()
+			return withHint(withExitCode(exitCodeNoCache, fmt.Errorf("index schema updated or index restored, and the cache must be rebuilt")), "run 'glf --sync' first")
+		}
+		logger.Info("Index schema updated or damaged index restored, performing full sync to rebuild cache...")
 		if err := descIndex.Close(); err != nil {
 			logger.Debug("Failed to close index: %v", err)
 		}
@@ -208,6 +589,15 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	}
 
 	// No projects - trigger first-run sync
+	if projectCount <= 1 && autoSyncDisabled() {
+		mode := "--no-sync"
+		if offline {
+			mode = "offline mode"
+		} else if readOnlyCache {
+			mode = "read-only cache"
+		}
+		return withHint(withExitCode(exitCodeNoCache, fmt.Errorf("no cached projects (%s)", mode)), "run 'glf --sync' first")
+	}
 	if projectCount <= 1 {
 		logger.Debug("No projects in index, running sync...")
 		shouldCloseIndex = false
@@ -220,10 +610,7 @@ func runSearch(cmd *cobra.Command, args []string) error {
 			fmt.Println()
 		}
 		if err := performSyncInternal(cfg, jsonOutput, true); err != nil {
-			if jsonOutput {
-				return outputJSONError(fmt.Sprintf("sync failed: %v", err))
-			}
-			return fmt.Errorf("sync failed: %w\n\nYou can try running 'glf --sync' manually", err)
+			return withHint(withExitCode(exitCodeSyncFailed, fmt.Errorf("sync failed: %w", err)), "you can try running 'glf --sync' manually")
 		}
 		if !jsonOutput {
 			fmt.Println()
@@ -242,27 +629,164 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	query := strings.TrimSpace(strings.Join(args, " "))
 
 	// JSON output mode: return results in JSON format (for integrations like Raycast)
-	if jsonOutput {
+	// --format, --plain, --urls and --vim print the same results through a template, as picker
+	// records, as URLs or as lines with matched ranges for editor plugins
+	if jsonOutput || formatTemplate != "" || plainOutput || urlsOutput || vimOutput {
 		return runJSONMode(query, cfg, descIndex)
 	}
 
+	// Edit mode: open the local clone of the first result in an editor
+	if editMode {
+		return runEdit(query, cfg, descIndex)
+	}
+
+	// Cd mode: print the local clone of the first result (without a query, of the TUI selection)
+	if cdMode {
+		if query != "" {
+			return runCd(query, cfg, descIndex)
+		}
+		if _, err := newWorkspace(cfg); err != nil {
+			return err
+		}
+	}
+
 	// Auto-go mode: select first result and open in browser
-	if autoGo {
+	// (--file with both a query and a path needs no selection either)
+	if autoGo || (openFile && query != "" && filePath != "") {
 		if query == "" {
 			return fmt.Errorf("-g/--go requires a search query")
 		}
 		return runAutoGo(query, cfg, descIndex)
 	}
 
+	// --select-1: a query with exactly one match opens it without the TUI
+	if selectOne && query != "" && !openFile {
+		if opened, err := runSelectOne(query, cfg, descIndex); opened || err != nil {
+			return err
+		}
+	}
+
 	// Pass the open index to TUI — it keeps it open for fast per-keystroke search
 	// and manages the lifecycle (closing before sync, reopening after)
+	if err := requireInteractive("interactive mode"); err != nil {
+		return err
+	}
 	shouldCloseIndex = false
 	return runInteractive(query, cfg, descIndex)
 }
 
+// newIssuesFetcher returns the live issue fetcher for the TUI issues mode
+// Returns nil in offline mode (Tab does nothing)
+func newIssuesFetcher(cfg *config.Config) tui.IssuesFunc {
+	if offline {
+		return nil
+	}
+
+	return func(projectPath string) ([]model.Issue, error) {
+		client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout())
+		if err != nil {
+			return nil, err
+		}
+		return client.ListOpenIssues(projectPath, issuesLimit)
+	}
+}
+
+// newReadmeFetcher returns the live README fetcher for the TUI README preview
+// Returns nil in offline mode (Alt+V does nothing)
+func newReadmeFetcher(cfg *config.Config) tui.ReadmeFunc {
+	if offline {
+		return nil
+	}
+
+	return func(projectPath string) (string, error) {
+		client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout())
+		if err != nil {
+			return "", err
+		}
+		return client.FetchReadme(projectPath)
+	}
+}
+
+// newAvatarFetcher returns the live project avatar fetcher for the README preview
+// Returns nil in offline mode (colored initials are shown)
+func newAvatarFetcher(cfg *config.Config) tui.AvatarFunc {
+	if offline {
+		return nil
+	}
+
+	return func(projectPath string) ([]byte, error) {
+		client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout())
+		if err != nil {
+			return nil, err
+		}
+		return client.FetchAvatar(projectPath)
+	}
+}
+
+// newStarToggle returns the live star toggle of the TUI (Ctrl+T)
+// Returns nil in offline mode (Ctrl+T does nothing)
+func newStarToggle(cfg *config.Config) tui.StarFunc {
+	if offline {
+		return nil
+	}
+
+	return func(projectPath string, starred bool) error {
+		client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout())
+		if err != nil {
+			return err
+		}
+		return client.SetStarred(projectPath, starred)
+	}
+}
+
+// newRemoteSearch returns the live GitLab search used when a query has no local results
+// Returns nil when search.remote_fallback is disabled or in offline mode
+func newRemoteSearch(cfg *config.Config) tui.RemoteSearchFunc {
+	if !cfg.Search.RemoteFallback || offline {
+		return nil
+	}
+
+	return func(query string) ([]model.Project, error) {
+		// GitLab only understands plain text: search by the text part, filter locally
+		parsed := search.ParseQuery(query)
+		text := parsed.SearchText()
+		if text == "" {
+			return nil, nil
+		}
+
+		client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, remoteSearchTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		// Reuse cached starred/member sets so remote results get the right flags
+		starred, member, err := cache.New(cfg.Cache.Dir).LoadProjectSets()
+		if err != nil {
+			logger.Debug("Failed to load cached project sets: %v", err)
+		} else {
+			client.SetCachedProjectSets(starred, member)
+		}
+
+		projects, err := client.SearchProjects(text, remoteSearchLimit)
+		if err != nil {
+			return nil, err
+		}
+		filtered := projects[:0]
+		for _, p := range projects {
+			if parsed.Matches(p) {
+				filtered = append(filtered, p)
+			}
+		}
+		return filtered, nil
+	}
+}
+
 // backgroundSyncIfStale triggers a background sync if cache is older than 1 hour
 // The sync runs in a goroutine and does not block the caller
 func backgroundSyncIfStale(cfg *config.Config) {
+	if autoSyncDisabled() || !cfg.Sync.Enabled() {
+		return
+	}
 	cacheManager := cache.New(cfg.Cache.Dir)
 	lastSync, err := cacheManager.LoadLastSyncTime()
 	if err != nil || lastSync.IsZero() {
@@ -281,10 +805,97 @@ func backgroundSyncIfStale(cfg *config.Config) {
 	}()
 }
 
+// jsonSearchOptions are the paging and ordering options of a JSON search: the --limit,
+// --offset, --sort, --all, --plain and --urls flags, or the parameters of /search (--serve)
+type jsonSearchOptions struct {
+	limit   int
+	offset  int
+	sort    string
+	all     bool // Rank every indexed project (--all)
+	visible bool // Only the results the TUI would show (--plain, --urls, --vim)
+	remote  bool // Fall back to a live GitLab search when nothing matches locally
+}
+
 // runJSONMode outputs search results in JSON format for API integrations
 func runJSONMode(query string, cfg *config.Config, descIndex *index.DescriptionIndex) error {
+	opts := jsonSearchOptions{
+		limit:   limitResults,
+		offset:  offsetResults,
+		sort:    sortBy,
+		all:     allResults,
+		visible: plainOutput || urlsOutput || vimOutput,
+		remote:  true,
+	}
+	result, matches, err := searchJSON(query, opts, cfg, descIndex)
+	if err != nil {
+		return err
+	}
+
+	// Trigger background sync if cache is stale (non-blocking)
+	backgroundSyncIfStale(cfg)
+
+	// --plain and --format take converted projects; JSON is streamed from the matches
+	var jsonProjects []JSONProject
+	if plainOutput || formatTemplate != "" {
+		jsonProjects = make([]JSONProject, len(matches))
+		for i, match := range matches {
+			jsonProjects[i] = newJSONProject(match, cfg, result.Instance)
+		}
+	}
+
+	// --select-1 needs nothing more: a query with one match prints that single record
+	if plainOutput {
+		terminator := byte('\n')
+		if print0Output {
+			terminator = 0
+		}
+		if err := outputPlain(os.Stdout, jsonProjects, terminator); err != nil {
+			return err
+		}
+		if hint := search.DidYouMean(result.Suggestions); hint != "" {
+			logger.Info(hint)
+		}
+	} else if urlsOutput {
+		if err := outputURLs(os.Stdout, result.Instance, matches, targetName); err != nil {
+			return err
+		}
+		if hint := search.DidYouMean(result.Suggestions); hint != "" {
+			logger.Info(hint)
+		}
+	} else if vimOutput {
+		if err := outputVim(os.Stdout, query, matches); err != nil {
+			return err
+		}
+		if hint := search.DidYouMean(result.Suggestions); hint != "" {
+			logger.Info(hint)
+		}
+	} else if formatTemplate != "" {
+		tmpl, err := parseFormat(formatTemplate)
+		if err != nil {
+			return withExitCode(exitCodeUsage, err)
+		}
+		if err := outputFormat(os.Stdout, tmpl, jsonProjects); err != nil {
+			return err
+		}
+		if hint := search.DidYouMean(result.Suggestions); hint != "" {
+			logger.Info(hint)
+		}
+	} else if err := writeJSONResult(os.Stdout, result, matches, cfg); err != nil {
+		return err
+	}
+
+	// CI mode: an empty result set is a distinct, silent failure
+	if ciMode && len(matches) == 0 {
+		return withExitCode(exitCodeNoResults, nil)
+	}
+	return nil
+}
+
+// searchJSON runs a search for the JSON output and returns the result (without its results)
+// and the page of matches it holds
+func searchJSON(query string, opts jsonSearchOptions, cfg *config.Config, descIndex *index.DescriptionIndex) (JSONSearchResult, []index.CombinedMatch, error) {
 	// Load history for score boosting (used for both empty and non-empty queries)
-	historyPath := filepath.Join(cfg.Cache.Dir, "history.gob")
+	historyPath := paths.HistoryPath(cfg.Cache.GetStateDir())
 	hist := history.New(historyPath)
 
 	// Load history synchronously
@@ -301,58 +912,94 @@ func runJSONMode(query string, cfg *config.Config, descIndex *index.DescriptionI
 		historyScores = hist.GetAllScores()
 	}
 
-	// Perform search (CombinedSearchWithIndex handles both empty and non-empty queries)
+	// Fetch enough full-text candidates to fill the requested page and detect a next one
+	// (--all ranks every indexed project)
+	minCandidates := 0
+	if opts.offset > 0 && opts.limit > 0 {
+		needed := opts.offset + opts.limit + 1
+		minCandidates = (needed + searchCandidatePage - 1) / searchCandidatePage * searchCandidatePage
+	}
+	if opts.all && query != "" && descIndex != nil {
+		if count, err := descIndex.Count(); err != nil {
+			logger.Debug("Failed to count indexed projects: %v", err)
+		} else {
+			minCandidates = int(count)
+		}
+	}
+
+	// Perform search (handles both empty and non-empty queries)
 	// Pass nil for projects — data is loaded directly from Bleve stored fields
-	matches, err := search.CombinedSearchWithIndex(query, nil, historyScores, cfg.Cache.Dir, descIndex)
+	matches, err := searchIndexSize(query, historyScores, cfg, descIndex, minCandidates)
 	if err != nil {
-		return outputJSONError(fmt.Sprintf("search failed: %v", err))
+		return JSONSearchResult{}, nil, withErrorCode(errorCodeSearch, exitCodeError, fmt.Errorf("search failed: %w", err))
+	}
+
+	// No local results: optionally fall back to a live GitLab search
+	if len(matches) == 0 && query != "" && !regexMode && opts.remote {
+		if remoteSearch := newRemoteSearch(cfg); remoteSearch != nil {
+			projects, err := remoteSearch(query)
+			if err != nil {
+				logger.Debug("Remote search failed: %v", err)
+			} else {
+				matches = search.RemoteMatches(projects)
+			}
+		}
 	}
 
 	// JSON mode: Include ALL projects with status fields (excluded, archived, member)
 	// API consumers (like Raycast) can implement their own filtering based on these fields
 	// The --show-hidden flag is more relevant for TUI where we control display
 
-	// Apply limit
-	if limitResults > 0 && len(matches) > limitResults {
-		matches = matches[:limitResults]
+	// --plain feeds pickers: list what the TUI would show
+	if opts.visible {
+		matches = visibleMatches(matches, cfg)
 	}
 
-	// Convert to JSON format
-	gitlabURL := strings.TrimSuffix(cfg.GitLab.URL, "/")
-	jsonProjects := make([]JSONProject, len(matches))
-	for i, match := range matches {
-		projectPath := strings.TrimPrefix(match.Project.Path, "/")
-		projectURL := fmt.Sprintf("%s/%s", gitlabURL, projectPath)
+	// Reorder by --sort, keeping relevance order among equal keys
+	sortMatches(matches, opts.sort)
 
-		// Check if project is excluded via config
-		isExcluded := cfg != nil && cfg.IsExcluded(match.Project.Path)
-
-		jsonProjects[i] = JSONProject{
-			Path:        match.Project.Path,
-			Name:        match.Project.Name,
-			Description: match.Project.Description,
-			URL:         projectURL,
-			Starred:     match.Project.Starred,
-			Excluded:    isExcluded,
-			Archived:    match.Project.Archived,
-			Member:      match.Project.Member,
-		}
+	// Pinned projects always come first, whether or not they match
+	matches = search.ApplyPins(matches, cfg.PinnedPaths, pinLookup(descIndex))
+	matches = collapseForks(matches, cfg)
 
-		jsonProjects[i].Score = match.TotalScore
+	// Apply offset and limit
+	if opts.offset > len(matches) {
+		matches = nil
+	} else if opts.offset > 0 {
+		matches = matches[opts.offset:]
+	}
+	hasMore := false
+	if opts.limit > 0 && len(matches) > opts.limit {
+		matches = matches[:opts.limit]
+		hasMore = true
 	}
 
 	// Create result
+	result := newJSONSearchResult(query, cfg)
+	result.Total = len(matches)
+	result.Limit = opts.limit
+	result.Offset = opts.offset
+	result.HasMore = hasMore
+	if len(matches) == 0 && opts.offset == 0 && query != "" {
+		result.Suggestions = suggestPaths(query, descIndex)
+	}
+	return result, matches, nil
+}
+
+// newJSONSearchResult returns the JSON result envelope of query, without results or paging
+func newJSONSearchResult(query string, cfg *config.Config) JSONSearchResult {
 	result := JSONSearchResult{
-		Query:   query,
-		Results: jsonProjects,
-		Total:   len(matches),
-		Limit:   limitResults,
+		SchemaVersion: jsonSchemaVersion,
+		Query:         query,
+		Instance:      strings.TrimSuffix(cfg.GitLab.URL, "/"),
+		Version:       version,
 	}
-
-	// Trigger background sync if cache is stale (non-blocking)
-	backgroundSyncIfStale(cfg)
-
-	return outputJSON(result)
+	if lastSync, err := cache.New(cfg.Cache.Dir).LoadLastSyncTime(); err != nil {
+		logger.Debug("Failed to load last sync time: %v", err)
+	} else if !lastSync.IsZero() {
+		result.CacheSyncedAt = &lastSync
+	}
+	return result
 }
 
 // outputJSON outputs a value as JSON to stdout
@@ -365,77 +1012,204 @@ func outputJSON(v interface{}) error {
 	return nil
 }
 
-// outputJSONError outputs an error in JSON format and returns nil
-// (so the program can exit cleanly with JSON output)
-func outputJSONError(message string) error {
-	if err := outputJSON(JSONError{Error: message}); err != nil {
-		// If JSON encoding fails, fall back to stderr
-		fmt.Fprintf(os.Stderr, "Error: %s\n", message)
-	}
-	os.Exit(1)
-	return nil
+// searchIndex runs the CLI search: path regex with --regex, full-text search otherwise
+func searchIndex(query string, historyScores map[string]int, cfg *config.Config, descIndex *index.DescriptionIndex) ([]index.CombinedMatch, error) {
+	return searchIndexSize(query, historyScores, cfg, descIndex, 0)
 }
 
-// runAutoGo automatically selects first result and opens it in browser
-func runAutoGo(query string, cfg *config.Config, descIndex *index.DescriptionIndex) error {
-	// Default sync function that calls performSyncInternal
-	syncFunc := func() error {
-		return performSyncInternal(cfg, true, false)
+// searchIndexSize is like searchIndex but considers at least minCandidates full-text candidates
+func searchIndexSize(query string, historyScores map[string]int, cfg *config.Config, descIndex *index.DescriptionIndex, minCandidates int) ([]index.CombinedMatch, error) {
+	if regexMode {
+		return search.RegexSearchWithIndex(query, nil, historyScores, cfg.Cache.Dir, descIndex)
 	}
-	return runAutoGoWithSync(query, cfg, descIndex, syncFunc)
+	return search.CombinedSearchWithIndexSize(query, nil, historyScores, cfg.Cache.Dir, descIndex, minCandidates)
 }
 
-// runAutoGoWithSync is the testable version that accepts a sync function
-func runAutoGoWithSync(query string, cfg *config.Config, descIndex *index.DescriptionIndex, syncFunc func() error) error {
-	// Load history for score boosting
-	historyPath := filepath.Join(cfg.Cache.Dir, "history.gob")
-	hist := history.New(historyPath)
-
-	// Load history synchronously
-	errCh := hist.LoadAsync()
-	if err := <-errCh; err != nil {
-		logger.Debug("Failed to load history: %v", err)
+// collapseForks lists forks under their upstream result when search.collapse_forks is set
+// Applied after pins, so pinned forks stay listed
+func collapseForks(matches []index.CombinedMatch, cfg *config.Config) []index.CombinedMatch {
+	if !cfg.Search.CollapseForks {
+		return matches
 	}
+	return search.CollapseForks(matches)
+}
 
-	// Get query-specific history scores
-	historyScores := hist.GetAllScoresForQuery(query)
-
-	// Perform search — nil projects, use Bleve stored fields directly
-	matches, err := search.CombinedSearchWithIndex(query, nil, historyScores, cfg.Cache.Dir, descIndex)
+// suggestPaths returns "Did you mean" suggestions for a query without results:
+// cached project paths closest to it by edit distance (none for --regex patterns)
+func suggestPaths(query string, descIndex *index.DescriptionIndex) []string {
+	if regexMode || descIndex == nil {
+		return nil
+	}
+	projects, err := descIndex.GetAllProjects()
 	if err != nil {
-		return fmt.Errorf("search failed: %w", err)
+		logger.Debug("Failed to load projects for suggestions: %v", err)
+		return nil
 	}
+	return search.Suggest(query, projects, search.DefaultSuggestions)
+}
 
-	if len(matches) == 0 {
-		return fmt.Errorf("no projects found for query: %s", query)
+// errNoProjects reports a query without results, printing "Did you mean" suggestions to stderr
+func errNoProjects(query string, descIndex *index.DescriptionIndex) error {
+	if hint := search.DidYouMean(suggestPaths(query, descIndex)); hint != "" {
+		logger.Info(hint)
 	}
+	return fmt.Errorf("no projects found for query: %s", query)
+}
 
-	// Take first result
-	firstProject := matches[0].Project
-
-	// Record selection in history
-	if hist != nil {
-		hist.RecordSelectionWithQuery(query, firstProject.Path)
-		if err := hist.Save(); err != nil {
-			logger.Debug("Failed to save history: %v", err)
-		}
+// splitFileArgs splits the --file arguments at "--" (dash is cmd.ArgsLenAtDash)
+// into the project query and the file path; the path is empty without "--"
+func splitFileArgs(args []string, dash int) ([]string, string, error) {
+	if dash < 0 {
+		return args, "", nil
+	}
+	switch rest := args[dash:]; len(rest) {
+	case 0:
+		return args[:dash], "", nil
+	case 1:
+		return args[:dash], rest[0], nil
+	default:
+		return nil, "", fmt.Errorf("--file accepts one path after '--', got %d", len(rest))
 	}
+}
 
-	// Construct URL
-	gitlabURL := strings.TrimSuffix(cfg.GitLab.URL, "/")
-	projectPath := strings.TrimPrefix(firstProject.Path, "/")
-	projectURL := fmt.Sprintf("%s/%s", gitlabURL, projectPath)
+// runAutoGo automatically selects first result and opens it in browser
+func runAutoGo(query string, cfg *config.Config, descIndex *index.DescriptionIndex) error {
+	return runAutoGoWithSync(query, cfg, descIndex, backgroundSync(cfg))
+}
 
-	// Always open in browser (that's the point of -g/--go)
-	// IMMEDIATE USER FEEDBACK - open browser first
-	logger.Debug("Opening browser with URL: %s", projectURL)
-	if err := openBrowser(projectURL); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to open browser: %v\n", err)
-		logger.Debug("Browser open error: %v", err)
-	} else {
-		logger.Debug("Browser command executed successfully")
+// backgroundSync returns the sync started after --go opened a result (performSyncInternal)
+func backgroundSync(cfg *config.Config) func() error {
+	return func() error {
+		if autoSyncDisabled() || !cfg.Sync.Enabled() {
+			logger.Debug("Automatic sync disabled: skipping background sync")
+			return nil
+		}
+		return performSyncInternal(cfg, true, false)
+	}
+}
+
+// runAutoGoWithSync is the testable version that accepts a sync function
+func runAutoGoWithSync(query string, cfg *config.Config, descIndex *index.DescriptionIndex, syncFunc func() error) error {
+	hist := loadSearchHistory(cfg)
+	matches, err := goMatches(query, hist, cfg, descIndex)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return errNoProjects(query, descIndex)
+	}
+
+	// A query pinned to a project (search.pin_after) opens it without ranking
+	if match, ok := pinnedMatch(query, hist, cfg, matches); ok {
+		return openMatch(query, match, hist, cfg, descIndex, syncFunc)
+	}
+
+	// Nearly tied top results: ask which one to open instead of guessing (scripts, launchers
+	// and keybindings without a terminal on stdin get the best)
+	if ambiguousMatch(query, matches, cfg.Search.GoMargin) {
+		if nonInteractive || !stdinIsTerminal() {
+			logger.Debug("Best match for %q is ambiguous, opening %s", query, matches[0].Project.Path)
+		} else {
+			choice, err := chooseMatch(bufio.NewReader(os.Stdin), os.Stderr, query, matches)
+			if err != nil {
+				return err
+			}
+			if choice < 0 {
+				return runInteractive(query, cfg, descIndex)
+			}
+			return openMatch(query, matches[choice], hist, cfg, descIndex, syncFunc)
+		}
+	}
+	return openMatch(query, matches[0], hist, cfg, descIndex, syncFunc)
+}
+
+// loadSearchHistory loads the history synchronously for score boosting and recording
+// A history that fails to load is only logged: searching works without it
+func loadSearchHistory(cfg *config.Config) *history.History {
+	hist := history.New(paths.HistoryPath(cfg.Cache.GetStateDir()))
+	if err := <-hist.LoadAsync(); err != nil {
+		logger.Debug("Failed to load history: %v", err)
+	}
+	return hist
+}
+
+// goMatches searches the query like --go does: query-specific history scores, the live
+// GitLab fallback when nothing matches locally, and pinned projects first
+func goMatches(query string, hist *history.History, cfg *config.Config, descIndex *index.DescriptionIndex) ([]index.CombinedMatch, error) {
+	// Get query-specific history scores
+	historyScores := hist.GetAllScoresForQuery(query)
+
+	// Perform search — nil projects, use Bleve stored fields directly
+	matches, err := searchIndex(query, historyScores, cfg, descIndex)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	// No local results: optionally fall back to a live GitLab search (not for regex patterns)
+	if len(matches) == 0 && !regexMode {
+		if remoteSearch := newRemoteSearch(cfg); remoteSearch != nil {
+			projects, err := remoteSearch(query)
+			if err != nil {
+				logger.Debug("Remote search failed: %v", err)
+			} else {
+				matches = search.RemoteMatches(projects)
+			}
+		}
 	}
 
+	// Pinned projects win over relevance (among the matches: --go opens a project the query matches)
+	return collapseForks(search.ApplyPins(matches, cfg.PinnedPaths, nil), cfg), nil
+}
+
+// pinLookup returns the search.ApplyPins lookup that adds pinned projects the query
+// doesn't match from descIndex (nil without an index)
+func pinLookup(descIndex *index.DescriptionIndex) func(path string) (model.Project, bool) {
+	if descIndex == nil {
+		return nil
+	}
+	return func(path string) (model.Project, bool) {
+		project, found, err := descIndex.GetProject(path)
+		if err != nil {
+			logger.Debug("Failed to look up pinned project %s: %v", path, err)
+		}
+		return project, found && err == nil
+	}
+}
+
+// openMatch opens a search result without the TUI: records it in the history, opens the
+// browser (or --open-with), prints the URL and starts syncFunc in the background
+func openMatch(query string, match index.CombinedMatch, hist *history.History, cfg *config.Config, descIndex *index.DescriptionIndex, syncFunc func() error) error {
+	project := match.Project
+
+	// Remote results are injected into the index so they are found locally next time
+	if match.Remote && descIndex != nil {
+		if err := descIndex.AddBatch([]index.DescriptionDocument{index.NewDocument(project)}); err != nil {
+			logger.Debug("Failed to add remote result to index: %v", err)
+		}
+	}
+
+	// Construct URL (optionally pointing at a sub-page via --target, or a file via --file)
+	projectURL, err := projectPageURL(cfg, project.Path, targetName)
+	if filePath != "" {
+		projectURL, err = target.BlobURL(cfg.GitLab.URL, project.Path, project.DefaultBranch, filePath)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Record selection in history
+	if hist != nil {
+		hist.SetSource(history.SourceGo)
+		hist.RecordSelectionWithQuery(query, project.Key())
+		if err := hist.Save(); err != nil {
+			logger.Debug("Failed to save history: %v", err)
+		}
+	}
+
+	// Always open in browser (that's the point of -g/--go), or with --open-with
+	// IMMEDIATE USER FEEDBACK - open browser first
+	openResult(cfg, project.Path, false, projectURL)
+
 	// Output URL immediately (don't wait for sync)
 	fmt.Println(projectURL)
 
@@ -454,6 +1228,9 @@ func runAutoGoWithSync(query string, cfg *config.Config, descIndex *index.Descri
 	return nil
 }
 
+// browserOpener launches the browser (browser_command from the config is applied in runSearch)
+var browserOpener = browser.New("")
+
 // openBrowser opens the given URL in the default browser (cross-platform)
 func openBrowser(rawURL string) error {
 	// Validate URL before passing to subprocess
@@ -463,25 +1240,15 @@ func openBrowser(rawURL string) error {
 	}
 	safeURL := parsedURL.String()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	var cmd *exec.Cmd
-
-	// #nosec G204 -- Command binaries are hardcoded; safeURL is validated via url.Parse
-	// and re-serialized (scheme restricted to http/https, no shell metacharacters)
-	switch runtime.GOOS {
-	case platformDarwin:
-		cmd = exec.CommandContext(ctx, "open", safeURL)
-	case platformLinux:
-		cmd = exec.CommandContext(ctx, "xdg-open", safeURL)
-	case platformWindows:
-		cmd = exec.CommandContext(ctx, "cmd", "/c", "start", "", safeURL)
-	default:
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+	// Non-interactive mode never launches a browser; callers still print the URL
+	if nonInteractive {
+		logger.Debug("Non-interactive mode: not opening %s", safeURL)
+		return nil
 	}
 
-	return cmd.Run()
+	// safeURL is validated via url.Parse and re-serialized (scheme restricted to http/https);
+	// launchers receive it as a single argument, never through a shell
+	return browserOpener.Open(safeURL)
 }
 
 // getGitRemoteURL gets the Git remote origin URL for the given directory
@@ -502,17 +1269,17 @@ func getGitRemoteURL(dir string) (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// publicGitHosts maps known public Git hosting services to their web URL
+var publicGitHosts = map[string]string{
+	"github.com":    "https://github.com",
+	"gitlab.com":    "https://gitlab.com",
+	"bitbucket.org": "https://bitbucket.org",
+}
+
 // extractProjectPath extracts the project path from a Git remote URL
 // Returns: projectPath, baseURL, error
 // baseURL is either the configured GitLab URL or the public repository host URL
 func extractProjectPath(remoteURL, gitlabURL string) (string, string, error) {
-	// Known public Git hosting services
-	publicHosts := map[string]string{
-		"github.com":    "https://github.com",
-		"gitlab.com":    "https://gitlab.com",
-		"bitbucket.org": "https://bitbucket.org",
-	}
-
 	gitlabURL = strings.TrimSuffix(gitlabURL, "/")
 	var gitlabHost string
 
@@ -600,7 +1367,7 @@ func extractProjectPath(remoteURL, gitlabURL string) (string, string, error) {
 	}
 
 	// Check if it's a known public repository host
-	if publicBaseURL, isPublic := publicHosts[remoteHostname]; isPublic {
+	if publicBaseURL, isPublic := publicGitHosts[remoteHostname]; isPublic {
 		return projectPath, publicBaseURL, nil
 	}
 
@@ -609,7 +1376,8 @@ func extractProjectPath(remoteURL, gitlabURL string) (string, string, error) {
 }
 
 // runOpenCurrent opens the current directory's Git repository in the browser
-func runOpenCurrent(cfg *config.Config) error {
+// page optionally selects a project sub-page (e.g., "mrs", "pipelines", "settings/ci_cd")
+func runOpenCurrent(cfg *config.Config, page string) error {
 	// Get current working directory
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -633,8 +1401,53 @@ func runOpenCurrent(cfg *config.Config) error {
 	logger.Debug("Extracted project path: %s", projectPath)
 	logger.Debug("Base URL: %s", baseURL)
 
-	// Construct project URL using the base URL from extraction
-	projectURL := fmt.Sprintf("%s/%s", baseURL, projectPath)
+	// Look the project up in the local index (only meaningful for the configured GitLab)
+	isConfiguredGitLab := baseURL == strings.TrimSuffix(cfg.GitLab.URL, "/")
+	var cached model.Project
+	var found bool
+	if isConfiguredGitLab {
+		cached, found = lookupCachedProject(cfg, projectPath)
+	}
+
+	// Sub-pages are GitLab-specific - they don't exist on other public hosts
+	if page == "" && pickTarget {
+		if err := requireInteractive("--pick"); err != nil {
+			return err
+		}
+		if !isConfiguredGitLab {
+			return fmt.Errorf("sub-pages are only available for the configured GitLab instance")
+		}
+		page, err = promptForTarget(bufio.NewReader(os.Stdin), projectPath, cached, found)
+		if err != nil {
+			return err
+		}
+	}
+	if page != "" && !isConfiguredGitLab {
+		return fmt.Errorf("sub-page %q is only available for the configured GitLab instance", page)
+	}
+
+	// Construct project URL using the base URL from extraction (sub-pages imply the configured GitLab)
+	// (--file opens a file on the default branch; uncached projects use HEAD)
+	// url_templates are meant for the configured GitLab, not for other hosts
+	projectURL := strings.TrimSuffix(baseURL, "/") + "/" + projectPath
+	if isConfiguredGitLab {
+		projectURL = projectRootURL(baseURL, projectPath)
+	}
+	if page != "" {
+		projectURL, err = projectPageURL(cfg, projectPath, page)
+	}
+	if openFile {
+		if !isConfiguredGitLab {
+			return fmt.Errorf("--file is only available for the configured GitLab instance")
+		}
+		if filePath == "" {
+			return withExitCode(exitCodeUsage, fmt.Errorf("'glf . --file' requires a path: glf . --file -- <path>"))
+		}
+		projectURL, err = target.BlobURL(baseURL, projectPath, cached.DefaultBranch, filePath)
+	}
+	if err != nil {
+		return err
+	}
 
 	// Open in browser
 	logger.Debug("Opening browser with URL: %s", projectURL)
@@ -651,9 +1464,132 @@ func runOpenCurrent(cfg *config.Config) error {
 	return nil
 }
 
+// lookupCachedProject looks a project up in the local index without triggering a sync
+// Returns false if the index doesn't exist or the project isn't cached
+func lookupCachedProject(cfg *config.Config, projectPath string) (model.Project, bool) {
+	indexPath := paths.IndexPath(cfg.Cache.Dir)
+	if !index.Exists(indexPath) {
+		logger.Debug("No local index, skipping cache lookup for %s", projectPath)
+		return model.Project{}, false
+	}
+
+	descIndex, err := index.NewDescriptionIndex(indexPath)
+	if err != nil {
+		logger.Debug("Failed to open index for cache lookup: %v", err)
+		return model.Project{}, false
+	}
+	defer func() {
+		if err := descIndex.Close(); err != nil {
+			logger.Debug("Failed to close index: %v", err)
+		}
+	}()
+
+	project, found, err := descIndex.GetProject(projectPath)
+	if err != nil {
+		logger.Debug("Cache lookup failed for %s: %v", projectPath, err)
+		return model.Project{}, false
+	}
+	if found {
+		logger.Debug("Found %s in local index (name: %s)", projectPath, project.Name)
+	} else {
+		logger.Debug("Project %s is not in the local index", projectPath)
+	}
+	return project, found
+}
+
+// promptForTarget shows a numbered list of project sub-pages and reads the user's choice
+// Returns an empty string when the project root is chosen
+func promptForTarget(reader *bufio.Reader, projectPath string, cached model.Project, found bool) (string, error) {
+	targets := target.All()
+
+	header := projectPath
+	if found && cached.Name != "" {
+		header = fmt.Sprintf("%s (%s)", cached.Name, projectPath)
+	}
+	fmt.Fprintf(os.Stderr, "Open %s:\n", header)
+	if found && cached.Archived {
+		fmt.Fprintln(os.Stderr, "  (project is archived)")
+	}
+	fmt.Fprintln(os.Stderr, "   0) Project overview")
+	for i, t := range targets {
+		fmt.Fprintf(os.Stderr, "  %2d) %-22s %s\n", i+1, t.Name, t.Description)
+	}
+	fmt.Fprint(os.Stderr, "Choice [0]: ")
+
+	response, err := reader.ReadString('\n')
+	if err != nil && response == "" {
+		return "", fmt.Errorf("failed to read choice: %w", err)
+	}
+	response = strings.TrimSpace(response)
+	if response == "" || response == "0" {
+		return "", nil
+	}
+
+	// Accept either the number or the target name
+	if n, convErr := strconv.Atoi(response); convErr == nil {
+		if n < 1 || n > len(targets) {
+			return "", fmt.Errorf("invalid choice: %d", n)
+		}
+		return targets[n-1].Name, nil
+	}
+	if _, ok := target.Environment(response); ok {
+		return response, nil
+	}
+	if _, err := target.Lookup(response); err != nil {
+		return "", err
+	}
+	return response, nil
+}
+
+// runPins handles --pin, --unpin, --unpin-query and --pins
+func runPins(cfg *config.Config) error {
+	if pinPath != "" {
+		projectPath := strings.Trim(pinPath, "/")
+		if _, found := lookupCachedProject(cfg, projectPath); !found {
+			fmt.Fprintf(os.Stderr, "Warning: %s is not in the local cache (pinned anyway)\n", projectPath)
+		}
+		if err := cfg.AddPin(projectPath); err != nil {
+			return fmt.Errorf("failed to save pin: %w", err)
+		}
+		fmt.Printf("📌 Pinned %s\n", projectPath)
+	}
+
+	if unpinPath != "" {
+		projectPath := strings.Trim(unpinPath, "/")
+		if !cfg.IsPinned(projectPath) {
+			return fmt.Errorf("%s is not pinned", projectPath)
+		}
+		if err := cfg.RemovePin(projectPath); err != nil {
+			return fmt.Errorf("failed to remove pin: %w", err)
+		}
+		fmt.Printf("Unpinned %s\n", projectPath)
+	}
+
+	if unpinQueryText != "" {
+		if err := unpinQuery(cfg, unpinQueryText); err != nil {
+			return err
+		}
+	}
+
+	if listPins {
+		for _, projectPath := range cfg.PinnedPaths {
+			fmt.Printf("📌 %s\n", projectPath)
+		}
+		queries, err := listQueryPins(cfg)
+		if err != nil {
+			return err
+		}
+		if len(cfg.PinnedPaths) == 0 && queries == 0 {
+			fmt.Println("No pinned projects. Pin one with 'glf --pin group/project' or Alt+P in the TUI.")
+		}
+	}
+
+	return nil
+}
+
 // runShowHistory displays search history with scores
 func runShowHistory(cfg *config.Config) error {
-	historyPath := filepath.Join(cfg.Cache.Dir, "history.gob")
+	historyPath := paths.HistoryPath(cfg.Cache.GetStateDir())
 	hist := history.New(historyPath)
 
 	// Load history synchronously
@@ -662,8 +1598,13 @@ func runShowHistory(cfg *config.Config) error {
 		return fmt.Errorf("failed to load history: %w", err)
 	}
 
+	if !hist.Enabled() {
+		logger.Info("History is disabled (history.enabled: false), new selections are not recorded")
+	}
+
 	// Get all history entries sorted by score
 	entries := hist.GetAllEntries()
+	displayPaths := historyDisplayPaths(indexSnapshot(cfg.Cache.Dir))
 
 	if len(entries) == 0 {
 		fmt.Println("No history yet. Use glf to search and select projects.")
@@ -681,6 +1622,9 @@ func runShowHistory(cfg *config.Config) error {
 
 		// Truncate long paths
 		path := entry.ProjectPath
+		if displayPath, ok := displayPaths[path]; ok {
+			path = displayPath
+		}
 		if len(path) > 55 {
 			path = path[:52] + "..."
 		}
@@ -695,9 +1639,235 @@ func runShowHistory(cfg *config.Config) error {
 	return nil
 }
 
+// loadHistory loads the history file synchronously
+func loadHistory(cfg *config.Config) (*history.History, error) {
+	hist := history.New(paths.HistoryPath(cfg.Cache.GetStateDir()))
+	if err := <-hist.LoadAsync(); err != nil {
+		return nil, fmt.Errorf("failed to load history: %w", err)
+	}
+	return hist, nil
+}
+
+// runShowQueryHistory displays the projects selected for a query and their query boost
+func runShowQueryHistory(cfg *config.Config, query string) error {
+	hist, err := loadHistory(cfg)
+	if err != nil {
+		return err
+	}
+
+	normalized := history.NormalizeQueryText(query)
+	entries := hist.GetQueryEntries(query)
+	if len(entries) == 0 {
+		fmt.Printf("No selections recorded for query %q.\n", normalized)
+		return nil
+	}
+
+	displayPaths := historyDisplayPaths(indexSnapshot(cfg.Cache.Dir))
+	fmt.Printf("Selections for query %q (%d projects)\n\n", normalized, len(entries))
+	fmt.Println("Project Path                                              Count  Last Used         Boost")
+	fmt.Println("─────────────────────────────────────────────────────── ────── ───────────────── ─────")
+
+	for _, entry := range entries {
+		path := entry.ProjectPath
+		if displayPath, ok := displayPaths[path]; ok {
+			path = displayPath
+		}
+		if len(path) > 55 {
+			path = path[:52] + "..."
+		}
+		fmt.Printf("%-55s %6d %17s %5d\n", path, entry.Count, entry.LastUsed.Format("2006-01-02 15:04"), entry.Score)
+	}
+
+	fmt.Println("\nBoost is added to the global history score when searching this query.")
+	return nil
+}
+
+// runTopQueries lists the most frequently used queries (up to --limit)
+func runTopQueries(cfg *config.Config) error {
+	hist, err := loadHistory(cfg)
+	if err != nil {
+		return err
+	}
+
+	stats := hist.GetTopQueries(limitResults)
+	if len(stats) == 0 {
+		fmt.Println("No query history yet. Search and select projects to build it.")
+		return nil
+	}
+
+	fmt.Printf("Top Queries (%d)\n\n", len(stats))
+	fmt.Println("Query                                    Selections Projects  Last Used")
+	fmt.Println("──────────────────────────────────────── ────────── ──────── ─────────────────")
+
+	unknown := false
+	for _, stat := range stats {
+		query := stat.Query
+		if query == "" {
+			query = "(unknown)"
+			unknown = true
+		} else if len(query) > 40 {
+			query = query[:37] + "..."
+		}
+		fmt.Printf("%-40s %10d %8d  %s\n", query, stat.Selections, stat.Projects, stat.LastUsed.Format("2006-01-02 15:04"))
+	}
+
+	if unknown {
+		fmt.Println("\n(unknown) queries were recorded by an older glf version that stored only a hash of the query.")
+	}
+	fmt.Println("\nShow selections for a query with: glf --history --query \"<query>\"")
+	return nil
+}
+
+// runHistoryStats breaks the recorded selections down by source (TUI, --go, --json-record,
+// --edit/--cd) and by week (up to --limit weeks, most recent first)
+func runHistoryStats(cfg *config.Config) error {
+	hist, err := loadHistory(cfg)
+	if err != nil {
+		return err
+	}
+
+	sources, weeks := hist.UsageStats()
+	if len(sources) == 0 {
+		fmt.Println("No history yet. Use glf to search and select projects.")
+		return nil
+	}
+
+	total := 0
+	for _, stat := range sources {
+		total += stat.Selections
+	}
+
+	fmt.Printf("Selections by Source (%d total)\n\n", total)
+	fmt.Println("Source       Selections  Share")
+	fmt.Println("──────────── ────────── ──────")
+	for _, stat := range sources {
+		fmt.Printf("%-12s %10d %5.0f%%\n", stat.Source, stat.Selections, float64(stat.Selections)*100/float64(total))
+	}
+
+	if limitResults > 0 && len(weeks) > limitResults {
+		weeks = weeks[:limitResults]
+	}
+	fmt.Printf("\nSelections by Week (%d)\n\n", len(weeks))
+	header := "Week        Total"
+	rule := "────────── ──────"
+	for _, stat := range sources {
+		width := max(len(stat.Source), 6)
+		header += fmt.Sprintf(" %*s", width, stat.Source)
+		rule += " " + strings.Repeat("─", width)
+	}
+	fmt.Println(header)
+	fmt.Println(rule)
+	for _, week := range weeks {
+		line := fmt.Sprintf("%s %6d", week.Week.Format("2006-01-02"), week.Selections)
+		for _, stat := range sources {
+			line += fmt.Sprintf(" %*d", max(len(stat.Source), 6), week.Sources[stat.Source])
+		}
+		fmt.Println(line)
+	}
+
+	for _, stat := range sources {
+		if stat.Source == history.SourceUnknown {
+			fmt.Println("\nunknown selections were recorded by an older glf version that did not store the source.")
+		}
+	}
+	return nil
+}
+
+// runExplainHistory prints how a project's history score was computed:
+// every selection with its age and decay multiplier, the query boost, and the cap
+func runExplainHistory(cfg *config.Config, projectPath, query string) error {
+	hist, err := loadHistory(cfg)
+	if err != nil {
+		return err
+	}
+
+	item := projectPath
+	if key, ok := historyKeys(indexSnapshot(cfg.Cache.Dir))[projectPath]; ok {
+		item = key
+	}
+	exp := hist.Explain(query, item)
+	exp.ProjectPath = projectPath
+	fmt.Printf("History score for %s\n", exp.ProjectPath)
+	if exp.Query != "" {
+		fmt.Printf("Query context: %q\n", exp.Query)
+	}
+	if exp.Algorithm == history.AlgorithmFrecency {
+		fmt.Printf("Frecency: recency buckets, max age %g days\n", exp.MaxAgeDays)
+	} else {
+		fmt.Printf("Decay: half-life %g days, max age %g days\n", exp.HalfLifeDays, exp.MaxAgeDays)
+	}
+	if exp.Context {
+		fmt.Println("Context ranking: on (global selections weighted by time of day and weekday/weekend)")
+	}
+	fmt.Println()
+
+	if len(exp.Contributions) == 0 {
+		fmt.Println("No selections recorded for this project.")
+		return nil
+	}
+
+	fmt.Println("Selected          Age (days) Decay   Weight Context Points  Source")
+	fmt.Println("───────────────── ────────── ─────── ────── ─────── ─────── ──────")
+	for _, c := range exp.Contributions {
+		source := "global"
+		if c.Query {
+			source = "query"
+		}
+		note := ""
+		if c.Multiplier == 0 {
+			note = " (expired)"
+		}
+		fmt.Printf("%17s %10.1f %7.3f %6.1f %7.2f %7.2f  %s%s\n",
+			c.Time.Format("2006-01-02 15:04"), c.AgeDays, c.Multiplier, c.Weight, c.Context, c.Weight*c.Multiplier*c.Context, source, note)
+	}
+
+	fmt.Printf("\nGlobal: %.2f", exp.GlobalScore)
+	if exp.Query != "" {
+		fmt.Printf(" | Query boost: %.2f", exp.QueryScore)
+	}
+	fmt.Printf(" | Raw: %.2f | Score: %d", exp.RawScore, exp.Score)
+	if exp.Capped {
+		fmt.Print(" (capped)")
+	}
+	fmt.Println()
+	fmt.Printf("Selected with %d distinct queries\n", exp.QueryContexts)
+
+	return nil
+}
+
+// runRemapHistory moves history entries from an old project or group path to a new one
+// Used to backfill renames that happened before glf tracked project IDs
+func runRemapHistory(cfg *config.Config, args []string) error {
+	if len(args) != 2 {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--remap-history requires two arguments: OLD_PATH NEW_PATH"))
+	}
+
+	historyPath := paths.HistoryPath(cfg.Cache.GetStateDir())
+	hist := history.New(historyPath)
+
+	// Load history synchronously
+	errCh := hist.LoadAsync()
+	if err := <-errCh; err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+
+	remapped := hist.Remap(args[0], args[1])
+	if remapped == 0 {
+		fmt.Printf("No history entries found for %s\n", args[0])
+		return nil
+	}
+
+	if err := hist.Save(); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+
+	fmt.Printf("✓ Remapped %d history entries: %s → %s\n", remapped, args[0], args[1])
+	return nil
+}
+
 // runClearHistory clears the search history
 func runClearHistory(cfg *config.Config) error {
-	historyPath := filepath.Join(cfg.Cache.Dir, "history.gob")
+	historyPath := paths.HistoryPath(cfg.Cache.GetStateDir())
 	hist := history.New(historyPath)
 
 	// Load history synchronously
@@ -729,8 +1899,18 @@ func runClearHistory(cfg *config.Config) error {
 
 // runRecordSelection records a project selection in the history (for JSON integrations)
 func runRecordSelection(cfg *config.Config, projectPath, query string) error {
-	historyPath := filepath.Join(cfg.Cache.Dir, "history.gob")
+	return recordSelection(cfg, projectPath, query, history.SourceJSONRecord)
+}
+
+// recordSelection records a selection of projectPath made by source in the history
+// (query is the search that found it; empty for none)
+func recordSelection(cfg *config.Config, projectPath, query, source string) error {
+	historyPath := paths.HistoryPath(cfg.Cache.GetStateDir())
 	hist := history.New(historyPath)
+	if !hist.Enabled() {
+		logger.Debug("History is disabled (history.enabled: false), not recording %s", projectPath)
+		return nil
+	}
 
 	// Load history synchronously
 	errCh := hist.LoadAsync()
@@ -738,12 +1918,27 @@ func runRecordSelection(cfg *config.Config, projectPath, query string) error {
 		return fmt.Errorf("failed to load history: %w", err)
 	}
 
+	// A former path of a renamed project is recorded for its current path
+	if renames, err := index.LoadRenames(paths.RenamesPath(cfg.Cache.Dir)); err != nil {
+		logger.Debug("Failed to load renames: %v", err)
+	} else if current := renames.Resolve(projectPath); current != projectPath {
+		logger.Debug("Recording %s for its former path %s", current, projectPath)
+		projectPath = current
+	}
+
+	// Indexed projects are recorded by ID; others by path until a sync indexes them
+	item := projectPath
+	if key, ok := historyKeys(indexSnapshot(cfg.Cache.Dir))[projectPath]; ok {
+		item = key
+	}
+
 	// Record selection with or without query context
+	hist.SetSource(source)
 	if query != "" {
-		hist.RecordSelectionWithQuery(query, projectPath)
+		hist.RecordSelectionWithQuery(query, item)
 		logger.Debug("Recorded selection: %s (query: %s)", projectPath, query)
 	} else {
-		hist.RecordSelection(projectPath)
+		hist.RecordSelection(item)
 		logger.Debug("Recorded selection: %s (no query)", projectPath)
 	}
 
@@ -760,15 +1955,17 @@ func runRecordSelection(cfg *config.Config, projectPath, query string) error {
 func runInteractive(initialQuery string, cfg *config.Config, descIndex *index.DescriptionIndex) error {
 	// Fetch current username for display in header
 	// Try to load from cache first
-	cacheManager := cache.New(cfg.Cache.Dir)
+	cacheManager := cache.New(cfg.Cache.GetStateDir()) // Username and session are per-user state
 	username, err := cacheManager.LoadUsername()
 	if err != nil {
 		logger.Debug("Failed to load cached username: %v", err)
 		username = ""
 	}
 
-	// If no cached username, try to fetch from API with reduced timeout
-	if username == "" {
+	// If no cached username, try to fetch from API with reduced timeout (never in offline mode)
+	if username == "" && offline {
+		logger.Debug("Offline mode: skipping username fetch")
+	} else if username == "" {
 		// Use 10-second timeout for username fetch (faster fail on network issues)
 		shortTimeout := 10 * time.Second
 		client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, shortTimeout)
@@ -793,146 +1990,99 @@ func runInteractive(initialQuery string, cfg *config.Config, descIndex *index.De
 		logger.Debug("Using cached username: @%s", username)
 	}
 
-	// Create sync callback
+	// Create sync callback (the TUI closes its indexes for the sync and reopens them afterwards)
 	syncCallback := func() tea.Cmd {
 		return func() tea.Msg {
-			// Perform sync in background
-			indexPath := filepath.Join(cfg.Cache.Dir, "description.bleve")
-
-			// Create GitLab client
-			client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout(), cfg.GitLab.Concurrency)
+			client, err := newSyncClient(cfg)
 			if err != nil {
-				return tui.SyncCompleteMsg{Err: err}
-			}
-
-			// Check for incremental sync
-			cacheManager := cache.New(cfg.Cache.Dir)
-			lastSyncTime, err := cacheManager.LoadLastSyncTime()
-			lastFullSyncTime, fullSyncErr := cacheManager.LoadLastFullSyncTime()
-			if fullSyncErr != nil {
-				logger.Debug("Failed to load last full sync time: %v", fullSyncErr)
-			}
-
-			var sincePtr *time.Time
-			var syncMode string
-			const fullSyncInterval = 7 * 24 * time.Hour
-
-			// Decide sync mode (same logic as sync command)
-			if err != nil {
-				// Error loading timestamp - fall back to full sync
-				logger.Debug("TUI sync: could not load last sync time: %v, performing full sync", err)
-				syncMode = syncModeFull
-			} else if lastSyncTime.IsZero() {
-				// First sync ever
-				logger.Debug("TUI sync: first sync detected, performing full sync")
-				syncMode = syncModeFull
-			} else if !lastFullSyncTime.IsZero() && time.Since(lastFullSyncTime) > fullSyncInterval {
-				// Last full sync was >7 days ago - auto full sync to remove deleted projects
-				daysSinceFullSync := int(time.Since(lastFullSyncTime).Hours() / 24)
-				logger.Debug("TUI sync: auto full sync (last full sync was %d days ago, removes deleted projects)", daysSinceFullSync)
-				syncMode = syncModeFull
-			} else {
-				// Incremental sync possible
-				sincePtr = &lastSyncTime
-				logger.Debug("TUI sync: incremental (since %v ago)", time.Since(lastSyncTime).Round(time.Second))
-				syncMode = syncModeIncremental
-			}
-
-			// Fetch projects (incremental or full)
-			// Always fetch ALL projects (membership=false) - filtering happens at display time
-			newProjects, err := client.FetchAllProjects(sincePtr, false)
-			if err != nil {
-				return tui.SyncCompleteMsg{Err: err}
-			}
-
-			// Open or create description index
-			descIndex, recreated, err := index.NewDescriptionIndexWithAutoRecreate(indexPath)
-			if err != nil {
-				return tui.SyncCompleteMsg{Err: err}
+				return tui.SyncCompleteMsg{Err: fmt.Errorf("GitLab client error: %w", err)}
 			}
+			return tuiSync(cfg, client)
+		}
+	}
 
-			// If index was recreated due to version mismatch, trigger full sync in TUI context
-			if recreated {
-				logger.Debug("TUI sync: index schema updated, switching to full sync mode")
-				syncMode = syncModeFull
+	// Offline mode and read-only caches: no auto-sync on startup and Ctrl+R does nothing
+	onSync := syncCallback
+	if offline || readOnlyCache {
+		onSync = nil
+	}
 
-				// Re-fetch all projects for full sync
-				// Always fetch ALL projects (membership=false) - filtering happens at display time
-				newProjects, err = client.FetchAllProjects(nil, false)
-				if err != nil {
-					return tui.SyncCompleteMsg{Err: err}
-				}
-				logger.Debug("TUI sync: re-fetched %d projects for full sync after index recreation", len(newProjects))
-			}
-			defer func() {
-				if err := descIndex.Close(); err != nil {
-					logger.Debug("Failed to close index: %v", err)
-				}
-			}()
-
-			// Prepare documents for batch indexing
-			batchDocs := make([]index.DescriptionDocument, 0, len(newProjects))
-			for _, proj := range newProjects {
-				// Index all projects, even those without descriptions
-				batchDocs = append(batchDocs, index.DescriptionDocument{
-					ProjectPath: proj.Path,
-					ProjectName: proj.Name,
-					Description: proj.Description,
-					Starred:     proj.Starred,
-					Archived:    proj.Archived,
-					Member:      proj.Member,
-				})
-			}
+	// With --cd stdout is captured by the shell function, so the TUI draws on stderr
+	programOptions := []tea.ProgramOption{tea.WithAltScreen()}
+	if cdMode {
+		lipgloss.SetDefaultRenderer(lipgloss.NewRenderer(os.Stderr))
+		programOptions = append(programOptions, tea.WithOutput(os.Stderr))
+	}
 
-			// Index all projects in batches
-			if len(batchDocs) > 0 {
-				// Index in batches of 500
-				for i := 0; i < len(batchDocs); i += 500 {
-					end := i + 500
-					if end > len(batchDocs) {
-						end = len(batchDocs)
-					}
-					if err := descIndex.AddBatch(batchDocs[i:end]); err != nil {
-						return tui.SyncCompleteMsg{Err: err}
-					}
-				}
-			}
+	// Create and run the TUI with persistent index for fast search
+	m := tui.New(nil, initialQuery, onSync, cfg.Cache.Dir, cfg, showScores, showHidden, username, version, descIndex)
+	m.SetAutoSync(cfg.Sync.Enabled(), cfg.Sync.GetInterval())
+	m.SetRemoteSearch(newRemoteSearch(cfg))
+	m.SetIssuesFetcher(newIssuesFetcher(cfg))
+	m.SetReadmeFetcher(newReadmeFetcher(cfg))
+	m.SetOpener(newBrowserOpener(cfg))
+	m.SetPageOpener(newPageOpener(cfg))
+	m.SetStarToggle(newStarToggle(cfg))
+	if cfg.Search.CollapseForks {
+		m.SetCollapseForks(true)
+	}
+	m.SetCustomOpeners(newCustomOpeners(cfg))
+	if cfg.TUI.Avatars {
+		protocol, err := tui.ParseImageProtocol(cfg.TUI.ImageProtocol, os.Getenv)
+		if err != nil {
+			logger.Warn("tui.image_protocol: %v; showing initials instead", err)
+		}
+		m.SetAvatars(newAvatarFetcher(cfg), protocol)
+	}
+	if regexMode {
+		m.SetRegexMode(true)
+	}
+	m.SetEditEnabled(cfg.WorkspaceDir != "")
+	m.SetFileMode(openFile, filePath)
 
-			// Save timestamp for successful sync
-			syncCompletedAt := time.Now()
-			if err := cacheManager.SaveLastSyncTime(syncCompletedAt); err != nil {
-				logger.Debug("Failed to save TUI sync timestamp: %v", err)
-			}
+	// Past queries for Up/Down recall on an empty input (tui.query_history)
+	queries, err := cacheManager.LoadQueryHistory()
+	if err != nil {
+		logger.Debug("Failed to load query history: %v", err)
+	}
+	m.SetQueryHistory(queries, cfg.TUI.QueryHistory)
 
-			// Save last full sync time only if this was a full sync
-			if syncMode == syncModeFull {
-				if err := cacheManager.SaveLastFullSyncTime(syncCompletedAt); err != nil {
-					logger.Debug("Failed to save TUI full sync timestamp: %v", err)
-				} else {
-					logger.Debug("TUI full sync timestamp saved: %s", syncCompletedAt.Format(time.RFC3339))
-				}
-			}
+	// Group search (Alt+O); --groups starts in groups mode (groups were synced by ensureGroupIndex)
+	if groupIndexPath := paths.GroupIndexPath(cfg.Cache.Dir); index.Exists(groupIndexPath) {
+		if groupIndex, _, err := index.NewDescriptionIndexWithAutoRecreate(groupIndexPath); err != nil {
+			logger.Debug("Failed to open group index: %v", err)
+		} else {
+			m.SetGroupIndex(groupIndex)
+		}
+	}
+	if showGroups {
+		m.SetGroupsMode(true)
+	}
 
-			// CRITICAL: For incremental sync, we fetched only CHANGED projects
-			// But TUI needs ALL projects, so load complete list from index
-			allProjects, err := descIndex.GetAllProjects()
-			if err != nil {
-				return tui.SyncCompleteMsg{Err: fmt.Errorf("failed to load all projects after sync: %w", err)}
+	// --resume (or tui.resume): restore the last session; a query or --groups on the command line wins
+	if (resumeSession || cfg.TUI.Resume) && initialQuery == "" && !showGroups {
+		if session, err := cacheManager.LoadSession(); err != nil {
+			logger.Debug("Failed to load session: %v", err)
+		} else if session != nil {
+			m.RestoreSession(*session)
+			if regexMode {
+				m.SetRegexMode(true)
 			}
-
-			return tui.SyncCompleteMsg{Projects: allProjects, Err: nil}
 		}
 	}
-
-	// Create and run the TUI with persistent index for fast search
-	m := tui.New(nil, initialQuery, syncCallback, cfg.Cache.Dir, cfg, showScores, showHidden, username, version, descIndex)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	p := tea.NewProgram(m, programOptions...)
 
 	finalModel, err := p.Run()
 
-	// Close the persistent index after TUI exits
+	// Close the persistent index after TUI exits and save the session for --resume
 	if model, ok := finalModel.(tui.Model); ok {
 		model.CloseIndex()
+		if err := cacheManager.SaveSession(model.Session()); err != nil {
+			logger.Debug("Failed to save session: %v", err)
+		}
+		if err := cacheManager.AppendQueryHistory(model.RecordedQueries(), cfg.TUI.QueryHistory); err != nil {
+			logger.Debug("Failed to save query history: %v", err)
+		}
 	}
 
 	if err != nil {
@@ -941,22 +2091,33 @@ func runInteractive(initialQuery string, cfg *config.Config, descIndex *index.De
 
 	// Check if user selected a project
 	if model, ok := finalModel.(tui.Model); ok {
+		if action := model.BulkAction(); action != tui.BulkNone {
+			return runBulkAction(os.Stdout, cfg, action, model.Marked())
+		}
 		selected := model.Selected()
+		if model.EditRequested() {
+			return editProject(cfg, selected)
+		}
+		if cdMode && selected != "" && !model.SelectedIsGroup() && model.SelectedURL() == "" {
+			ws, err := newWorkspace(cfg)
+			if err != nil {
+				return err
+			}
+			return printClonePath(ws, selected)
+		}
 		if selected != "" {
 			// Construct GitLab project URL
-			gitlabURL := strings.TrimSuffix(cfg.GitLab.URL, "/")
-			projectPath := strings.TrimPrefix(selected, "/")
-			projectURL := fmt.Sprintf("%s/%s", gitlabURL, projectPath)
-
-			// Open in browser
-			logger.Debug("Opening browser with URL: %s", projectURL)
-			if err := openBrowser(projectURL); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to open browser: %v\n", err)
-				logger.Debug("Browser open error: %v", err)
-			} else {
-				logger.Debug("Browser command executed successfully")
+			projectURL := model.SelectedURL()
+			if projectURL == "" {
+				projectURL, err = resultURL(cfg, selected, model.SelectedIsGroup())
+				if err != nil {
+					return withExitCode(exitCodeUsage, err)
+				}
 			}
 
+			// Open in browser (or with --open-with)
+			openResult(cfg, selected, model.SelectedIsGroup(), projectURL)
+
 			// Output URL to stdout (for copying or script usage)
 			fmt.Println(projectURL)
 		}
@@ -965,10 +2126,96 @@ func runInteractive(initialQuery string, cfg *config.Config, descIndex *index.De
 	return nil
 }
 
+// resultURL returns the URL a TUI result opens: the project page, or the group page (--target applies)
+func resultURL(cfg *config.Config, path string, group bool) (string, error) {
+	if group {
+		return target.GroupURL(cfg.GitLab.URL, path, targetName)
+	}
+	return target.URL(cfg.GitLab.URL, path, "")
+}
+
+// newBrowserOpener returns the Ctrl+O handler: opens a result in the browser while the TUI keeps running
+func newBrowserOpener(cfg *config.Config) tui.OpenFunc {
+	return func(path string, group bool) error {
+		resultURL, err := resultURL(cfg, path, group)
+		if err != nil {
+			return err
+		}
+		logger.Debug("Opening browser with URL: %s", resultURL)
+		return openBrowser(resultURL)
+	}
+}
+
+// newPageOpener returns the handler of the TUI page keys: opens a sub-page of a result
+// (e.g. its container registry) in the browser while the TUI keeps running
+func newPageOpener(cfg *config.Config) tui.PageOpenFunc {
+	return func(path string, group bool, page string) error {
+		var pageURL string
+		var err error
+		if group {
+			pageURL, err = target.GroupURL(cfg.GitLab.URL, path, page)
+		} else {
+			pageURL, err = projectPageURL(cfg, path, page)
+		}
+		if err != nil {
+			return err
+		}
+		logger.Debug("Opening browser with URL: %s", pageURL)
+		return openBrowser(pageURL)
+	}
+}
+
+// tuiSync runs the TUI's auto-sync and Ctrl+R: the same sync as --sync, reporting the
+// complete project list and the changes for the sync toast
+func tuiSync(cfg *config.Config, client gitlab.GitLabClient) tui.SyncCompleteMsg {
+	result, err := syncWithClient(cfg, client, true, false)
+	if err != nil {
+		return tui.SyncCompleteMsg{Err: err}
+	}
+
+	// Incremental syncs fetch only changed projects, but the TUI needs all of them
+	allProjects, _, err := loadIndexedProjects(cfg.Cache.Dir)
+	if err != nil {
+		return tui.SyncCompleteMsg{Err: fmt.Errorf("failed to load all projects after sync: %w", err)}
+	}
+	loadAnnotations(cfg)
+
+	return tui.SyncCompleteMsg{Projects: allProjects, Added: result.stats.added, Updated: result.stats.updated + result.stats.renamed}
+}
+
 // performSyncInternal performs the actual sync logic
 // silent=true suppresses Info/Success messages (for background sync)
 // forceFullSync=true forces full sync regardless of timestamps
 func performSyncInternal(cfg *config.Config, silent bool, forceFullSync bool) error {
+	_, err := performSync(cfg, silent, forceFullSync)
+	return err
+}
+
+// runSyncJSON handles --sync --json: syncs silently and prints a JSONSyncResult
+// Failures are reported in "errors" and exit with exitCodeSyncFailed
+func runSyncJSON(cfg *config.Config) error {
+	start := time.Now()
+	result, err := performSync(cfg, true, forceFull)
+	if result == nil {
+		result = &JSONSyncResult{Errors: []string{}}
+	}
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	if encErr := outputJSON(result); encErr != nil {
+		return encErr
+	}
+	if err != nil {
+		return withExitCode(exitCodeSyncFailed, nil)
+	}
+	return nil
+}
+
+// performSync is performSyncInternal returning a summary of the sync
+// The summary is nil if the GitLab client could not be created
+func performSync(cfg *config.Config, silent bool, forceFullSync bool) (*JSONSyncResult, error) {
 	logInfo := logger.Info
 	if silent {
 		logInfo = logger.Debug
@@ -976,103 +2223,153 @@ func performSyncInternal(cfg *config.Config, silent bool, forceFullSync bool) er
 
 	// Create GitLab client with timeout
 	logInfo("Connecting to GitLab at %s (timeout: %ds)...", cfg.GitLab.URL, cfg.GitLab.Timeout)
-	client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout(), cfg.GitLab.Concurrency)
+	client, err := newSyncClient(cfg)
 	if err != nil {
 		logger.Error("Failed to create GitLab client")
-		return fmt.Errorf("GitLab client error: %w", err)
+		return nil, fmt.Errorf("GitLab client error: %w", err)
 	}
 
-	return performSyncInternalWithClient(cfg, client, silent, forceFullSync)
+	return syncWithClient(cfg, client, silent, forceFullSync)
+}
+
+// newSyncClient creates the GitLab client for a sync, limited to gitlab.sync_timeout
+func newSyncClient(cfg *config.Config) (*gitlab.Client, error) {
+	client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout(), cfg.GitLab.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+	if timeout := cfg.GitLab.GetSyncTimeout(); timeout > 0 {
+		client.SetDeadline(time.Now().Add(timeout))
+	}
+	// Pages that didn't change since the last sync are answered 304 Not Modified
+	client.SetETagCache(gitlab.LoadETagCache(paths.ETagCachePath(cfg.Cache.Dir)))
+	return client, nil
+}
+
+// saveETagCache keeps the responses of a sync for the conditional requests of the next one
+func saveETagCache(client gitlab.GitLabClient) {
+	concreteClient, ok := client.(*gitlab.Client)
+	if !ok {
+		return
+	}
+	if err := concreteClient.SaveETagCache(); err != nil {
+		logger.Debug("Failed to save ETag cache: %v", err)
+	}
 }
 
 // performSyncInternalWithClient performs sync with an injected GitLab client (testable version)
 func performSyncInternalWithClient(cfg *config.Config, client gitlab.GitLabClient, silent bool, forceFullSync bool) error {
+	_, err := syncWithClient(cfg, client, silent, forceFullSync)
+	return err
+}
+
+// syncWithClient performs sync with an injected GitLab client and returns a summary of it
+// Non-fatal problems (indexing, timestamps) are collected in the summary's Errors
+func syncWithClient(cfg *config.Config, client gitlab.GitLabClient, silent bool, forceFullSync bool) (*JSONSyncResult, error) {
+	result := &JSONSyncResult{Errors: []string{}}
+	syncStart := time.Now()
+	defer func() { result.DurationMs = time.Since(syncStart).Milliseconds() }()
+
 	logInfo := logger.Info
 	logSuccess := logger.Success
+	logWarn := logger.Warn
+	logError := logger.Error
 	if silent {
+		// Background and TUI syncs report errors to their caller (the TUI draws over stderr)
 		logInfo = logger.Debug
 		logSuccess = logger.Debug
+		logWarn = logger.Debug
+		logError = logger.Debug
 	}
 
 	// Test connection
 	logger.Debug("Testing GitLab connection...")
 	if err := client.TestConnection(); err != nil {
-		logger.Error("Connection test failed")
+		logError("Connection test failed")
+		if gitlab.IsUnauthorized(err) {
+			logInfo("GitLab rejected the token (401 Unauthorized) - it may be expired or revoked.")
+			logInfo("Create a new token at: %s", generateTokenURL(cfg.GitLab.URL))
+			logInfo("Then update it with 'glf --init'")
+			return result, fmt.Errorf("connection test failed: %w", err)
+		}
 		logInfo("Please check:")
 		logInfo("  - GitLab URL is correct: %s", cfg.GitLab.URL)
 		logInfo("  - Personal Access Token is valid")
 		logInfo("  - Network connection is available")
-		logInfo("  - GitLab server is accessible")
-		return fmt.Errorf("connection test failed: %w", err)
-	}
-	logSuccess("Connected successfully")
-
-	// Check for incremental sync capability
-	cacheManager := cache.New(cfg.Cache.Dir)
-	lastSyncTime, err := cacheManager.LoadLastSyncTime()
-	lastFullSyncTime, fullSyncErr := cacheManager.LoadLastFullSyncTime()
-	if fullSyncErr != nil {
-		logger.Debug("Failed to load last full sync time: %v", fullSyncErr)
+		logInfo("  - GitLab server is accessible")
+		return result, fmt.Errorf("connection test failed: %w", err)
 	}
+	logSuccess("Connected successfully")
 
-	var projects []model.Project
-	var syncMode string
-	const fullSyncInterval = 7 * 24 * time.Hour // 7 days
+	if concreteClient, ok := client.(*gitlab.Client); ok {
+		// Older instances lack some APIs: say what is disabled instead of failing later
+		if version := concreteClient.ServerVersion(); version != nil {
+			logger.Debug("GitLab %s %s (revision %s)", version.Edition(), version.Version, version.Revision)
+			for _, feature := range concreteClient.UnsupportedFeatures() {
+				logWarn("GitLab %s does not support %s: %s", version.Version, feature, feature.Fallback)
+			}
+		}
 
-	// Decide sync mode: full vs incremental
-	if forceFullSync {
-		// User explicitly requested full sync
-		logInfo("Full sync requested (--full flag)")
-		syncMode = syncModeFull
-	} else if err != nil {
-		// Error loading timestamp - fall back to full sync
-		logger.Debug("Could not load last sync time: %v, performing full sync", err)
-		syncMode = syncModeFull
-	} else if lastSyncTime.IsZero() {
-		// First sync ever
-		logInfo("First sync detected")
-		syncMode = syncModeFull
-	} else if !lastFullSyncTime.IsZero() && time.Since(lastFullSyncTime) > fullSyncInterval {
-		// Last full sync was >7 days ago - auto full sync to remove deleted projects
-		daysSinceFullSync := int(time.Since(lastFullSyncTime).Hours() / 24)
-		logInfo("Auto full sync: last full sync was %d days ago (removes deleted projects)", daysSinceFullSync)
-		syncMode = syncModeFull
-	} else {
-		// Incremental sync possible
-		timeSinceLastSync := time.Since(lastSyncTime)
-		logInfo("Incremental sync: fetching projects changed since %v ago", timeSinceLastSync.Round(time.Second))
-		syncMode = syncModeIncremental
+		// Check the token before expiry turns into 401s and missing scopes into 403s
+		if info := fetchTokenInfo(cfg, concreteClient); info != nil {
+			checkTokenExpiry(cfg, info, silent)
+			if err := checkTokenScopes(cfg, info, logWarn); err != nil {
+				return result, err
+			}
+		}
 	}
 
+	// Decide sync mode: full vs incremental
+	cacheManager := cache.New(cfg.Cache.Dir)
+	syncMode, sincePtr := chooseSyncMode(cacheManager, forceFullSync, cfg.Sync.MembershipOnly, logInfo)
+	var projects []model.Project
+	var err error
+
 	// For incremental sync, reuse cached starred/member sets to avoid extra API calls
 	if syncMode == syncModeIncremental {
-		if concreteClient, ok := client.(*gitlab.Client); ok {
-			cachedStarred, cachedMember, loadErr := cacheManager.LoadProjectSets()
-			if loadErr != nil {
-				logger.Debug("Failed to load cached project sets: %v", loadErr)
-			} else if cachedStarred != nil {
-				logger.Debug("Using cached starred (%d) and member (%d) project sets", len(cachedStarred), len(cachedMember))
-				concreteClient.SetCachedProjectSets(cachedStarred, cachedMember)
-			}
-		}
+		useCachedProjectSets(client, cacheManager)
 	}
 
 	// Fetch projects (full or incremental)
 	logInfo("Fetching projects...")
 	start := time.Now()
 
-	var sincePtr *time.Time
-	if syncMode == syncModeIncremental {
-		sincePtr = &lastSyncTime
+	// Index pages while the remaining ones are still being fetched
+	isFullSync := (syncMode == syncModeFull)
+	indexer, indexerErr := newSyncIndexer(cfg.Cache.Dir, silent, isFullSync)
+	if indexerErr != nil {
+		logger.Debug("Failed to open description index: %v", indexerErr)
+	} else {
+		indexer.stateDir = cfg.Cache.GetStateDir()
 	}
 
-	// Always fetch ALL projects (membership=false) - filtering happens at display time
-	projects, err = client.FetchAllProjects(sincePtr, false)
+	// Fetch all projects (filtering happens at display time), or only member ones with sync.membership_only
+	projects, err = fetchProjectsIndexing(client, sincePtr, cfg.Sync.MembershipOnly, indexer)
 	if err != nil {
-		logger.Error("Failed to fetch projects")
-		return fmt.Errorf("fetch error: %w", err)
+		if indexer != nil {
+			indexer.close() // Keep what was indexed, but remove nothing
+		}
+		logError("Failed to fetch projects")
+		return result, fmt.Errorf("fetch error: %w", err)
 	}
+	saveETagCache(client)
 	elapsed := time.Since(start)
+	result.Mode = syncMode
+	result.ProjectsFetched = len(projects)
+	if syncMode == syncModeIncremental {
+		result.Changed = len(projects)
+	}
+
+	// Enrich member projects with open MR/issue counts (config-gated)
+	if cfg.GitLab.Insights {
+		if concreteClient, ok := client.(*gitlab.Client); ok {
+			logInfo("Fetching open MR and issue counts...")
+			enrichProjectInsights(concreteClient, projects)
+			if indexer != nil {
+				_ = indexer.update(memberProjects(projects)) // Error is returned by finish
+			}
+		}
+	}
 
 	// Save starred/member sets to cache after fetch (for reuse in incremental syncs)
 	if concreteClient, ok := client.(*gitlab.Client); ok {
@@ -1082,26 +2379,73 @@ func performSyncInternalWithClient(cfg *config.Config, client gitlab.GitLabClien
 				logger.Debug("Failed to save project sets cache: %v", saveErr)
 			}
 		}
+
+		// Refresh the group index (--groups); a failure doesn't fail the project sync
+		groupCount, groupErr := syncGroups(cfg.Cache.Dir, concreteClient)
+		if groupErr != nil {
+			logWarn("Failed to sync groups: %v", groupErr)
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to sync groups: %v", groupErr))
+		} else {
+			result.Groups = groupCount
+			logger.Debug("Indexed %d groups", groupCount)
+		}
+
+		// Refresh the cached users (--users, config-gated)
+		if cfg.GitLab.SyncUsers {
+			logInfo("Fetching users...")
+			userCount, userErr := syncUsers(cfg.Cache.Dir, concreteClient)
+			if userErr != nil {
+				logWarn("Failed to sync users: %v", userErr)
+				result.Errors = append(result.Errors, fmt.Sprintf("failed to sync users: %v", userErr))
+			} else {
+				result.Users = userCount
+				logger.Debug("Cached %d users", userCount)
+			}
+		}
+
+		// Refresh the team-shared annotations (annotations.project)
+		if fetched, annotationsErr := syncAnnotations(cfg, concreteClient); annotationsErr != nil {
+			logWarn("Failed to sync annotations: %v", annotationsErr)
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to sync annotations: %v", annotationsErr))
+		} else if fetched {
+			logger.Debug("Fetched annotations from %s", cfg.Annotations.Project)
+		}
 	}
 
 	if syncMode == syncModeIncremental {
 		logSuccess("Fetched %d changed projects in %v", len(projects), elapsed)
 		if len(projects) == 0 {
+			if indexer != nil {
+				indexer.close()
+			}
 			logInfo("No projects changed since last sync")
-			return nil // Early return - nothing to index
+			return result, nil // Early return - nothing to index
 		}
 	} else {
 		logSuccess("Fetched %d projects in %v", len(projects), elapsed)
 		if len(projects) == 0 {
-			logger.Warn("No projects found. Check if your token has sufficient permissions.")
-			return nil
+			if indexer != nil {
+				indexer.close() // Never wipe the index because nothing came back
+			}
+			logWarn("No projects found. Check if your token has sufficient permissions.")
+			result.Errors = append(result.Errors, "no projects found, check if your token has sufficient permissions")
+			return result, nil
 		}
 	}
 
-	// Index project descriptions
-	isFullSync := (syncMode == syncModeFull)
-	if err := indexDescriptions(projects, cfg.Cache.Dir, silent, isFullSync); err != nil {
-		logger.Warn("Description indexing failed: %v", err)
+	// Finish indexing (removes deleted projects on full sync)
+	stats, err := indexStats{}, indexerErr
+	if indexer != nil {
+		stats, err = indexer.finish()
+	}
+	result.Indexed = stats.indexed
+	result.stats = stats
+	if isFullSync {
+		result.Changed = stats.added + stats.renamed + stats.removed
+	}
+	if err != nil {
+		logWarn("Description indexing failed: %v", err)
+		result.Errors = append(result.Errors, fmt.Sprintf("description indexing failed: %v", err))
 		logInfo("Search will work without description content. Run 'glf --sync' again to retry.")
 		// Don't fail the entire sync if indexing fails
 	}
@@ -1111,7 +2455,8 @@ func performSyncInternalWithClient(cfg *config.Config, client gitlab.GitLabClien
 
 	// Always save last sync time (for incremental)
 	if err := cacheManager.SaveLastSyncTime(syncCompletedAt); err != nil {
-		logger.Warn("Failed to save sync timestamp: %v (incremental sync won't work next time)", err)
+		logWarn("Failed to save sync timestamp: %v (incremental sync won't work next time)", err)
+		result.Errors = append(result.Errors, fmt.Sprintf("failed to save sync timestamp: %v", err))
 	} else {
 		logger.Debug("Sync timestamp saved: %s", syncCompletedAt.Format(time.RFC3339))
 	}
@@ -1119,140 +2464,321 @@ func performSyncInternalWithClient(cfg *config.Config, client gitlab.GitLabClien
 	// Save last full sync time only if this was a full sync
 	if syncMode == syncModeFull {
 		if err := cacheManager.SaveLastFullSyncTime(syncCompletedAt); err != nil {
-			logger.Warn("Failed to save full sync timestamp: %v", err)
+			logWarn("Failed to save full sync timestamp: %v", err)
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to save full sync timestamp: %v", err))
 		} else {
 			logger.Debug("Full sync timestamp saved: %s", syncCompletedAt.Format(time.RFC3339))
 		}
+		if err := cacheManager.SaveMembershipOnly(cfg.Sync.MembershipOnly); err != nil {
+			logWarn("Failed to save sync scope: %v", err)
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to save sync scope: %v", err))
+		}
 	}
 
 	if !silent {
 		logInfo("\nRun 'glf' to search projects interactively")
 	}
 
-	return nil
+	return result, nil
 }
 
-// indexDescriptions indexes project descriptions for full-text search
-func indexDescriptions(projects []model.Project, cacheDir string, silent bool, isFullSync bool) error {
+// chooseSyncMode decides between a full and an incremental sync
+// Returns the mode and, for incremental syncs, the time to fetch changes since
+func chooseSyncMode(cacheManager *cache.Cache, forceFullSync, membershipOnly bool, logInfo func(format string, args ...interface{})) (string, *time.Time) {
+	const fullSyncInterval = 7 * 24 * time.Hour // 7 days
+
+	lastSyncTime, err := cacheManager.LoadLastSyncTime()
+	lastFullSyncTime, fullSyncErr := cacheManager.LoadLastFullSyncTime()
+	if fullSyncErr != nil {
+		logger.Debug("Failed to load last full sync time: %v", fullSyncErr)
+	}
+
+	switch {
+	case forceFullSync:
+		// User explicitly requested full sync
+		logInfo("Full sync requested (--full flag)")
+	case err != nil:
+		// Error loading timestamp - fall back to full sync
+		logger.Debug("Could not load last sync time: %v, performing full sync", err)
+	case lastSyncTime.IsZero():
+		// First sync ever
+		logInfo("First sync detected")
+	case cacheManager.MembershipOnly() != membershipOnly:
+		// Incremental syncs would keep the projects no longer fetched, or miss the newly fetched ones
+		logInfo("Full sync: sync.membership_only changed since the last full sync")
+	case !lastFullSyncTime.IsZero() && time.Since(lastFullSyncTime) > fullSyncInterval:
+		// Last full sync was >7 days ago - auto full sync to remove deleted projects
+		daysSinceFullSync := int(time.Since(lastFullSyncTime).Hours() / 24)
+		logInfo("Auto full sync: last full sync was %d days ago (removes deleted projects)", daysSinceFullSync)
+	default:
+		// Incremental sync possible
+		timeSinceLastSync := time.Since(lastSyncTime)
+		logInfo("Incremental sync: fetching projects changed since %v ago", timeSinceLastSync.Round(time.Second))
+		return syncModeIncremental, &lastSyncTime
+	}
+	return syncModeFull, nil
+}
+
+// useCachedProjectSets hands the starred/member sets of the last sync to the client
+// (incremental syncs only fetch changed projects, so the sets are not refetched)
+func useCachedProjectSets(client gitlab.GitLabClient, cacheManager *cache.Cache) {
+	concreteClient, ok := client.(*gitlab.Client)
+	if !ok {
+		return
+	}
+	cachedStarred, cachedMember, loadErr := cacheManager.LoadProjectSets()
+	if loadErr != nil {
+		logger.Debug("Failed to load cached project sets: %v", loadErr)
+	} else if cachedStarred != nil {
+		logger.Debug("Using cached starred (%d) and member (%d) project sets", len(cachedStarred), len(cachedMember))
+		concreteClient.SetCachedProjectSets(cachedStarred, cachedMember)
+	}
+}
+
+// memberProjects returns the projects the user is a member of
+func memberProjects(projects []model.Project) []model.Project {
+	var members []model.Project
+	for _, p := range projects {
+		if p.Member {
+			members = append(members, p)
+		}
+	}
+	return members
+}
+
+// enrichProjectInsights fills OpenMRs/OpenIssues for member projects in place
+// Non-member projects are skipped to keep the number of API calls bounded
+func enrichProjectInsights(client *gitlab.Client, projects []model.Project) {
+	paths := make([]string, 0, len(projects))
+	for _, p := range projects {
+		if p.Member {
+			paths = append(paths, p.Path)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	insights := client.FetchProjectInsights(paths)
+	for i := range projects {
+		if info, ok := insights[projects[i].Path]; ok {
+			projects[i].OpenMRs = info.OpenMRs
+			projects[i].OpenIssues = info.OpenIssues
+		}
+	}
+}
+
+// checkTokenExpiry warns when the configured token expires within cfg.GitLab.TokenExpiryWarnDays
+func checkTokenExpiry(cfg *config.Config, info *gitlab.TokenInfo, silent bool) {
+	if cfg.GitLab.TokenExpiryWarnDays <= 0 {
+		return
+	}
+
+	warning, ok := tokenExpiryWarning(info.ExpiresAt, time.Now(), cfg.GitLab.TokenExpiryWarnDays)
+	if !ok {
+		if info.ExpiresAt != nil {
+			logger.Debug("Token %q expires on %s", info.Name, info.ExpiresAt.Format("2006-01-02"))
+		}
+		return
+	}
+
+	logWarn := logger.Warn
 	logInfo := logger.Info
-	logSuccess := logger.Success
 	if silent {
+		logWarn = logger.Debug
 		logInfo = logger.Debug
-		logSuccess = logger.Debug
 	}
+	logWarn("%s", warning)
+	logInfo("  Create a new token at: %s", generateTokenURL(cfg.GitLab.URL))
+	logInfo("  Then update it with 'glf --init'")
+}
 
-	logInfo("Indexing project descriptions...")
-	start := time.Now()
-
-	// Create or open index
-	indexPath := filepath.Join(cacheDir, "description.bleve")
-	descriptionIndex, recreated, err := index.NewDescriptionIndexWithAutoRecreate(indexPath)
-	if err != nil {
-		return fmt.Errorf("failed to create description index: %w", err)
+// tokenExpiryWarning returns a warning message if expiresAt falls within warnDays of now
+func tokenExpiryWarning(expiresAt *time.Time, now time.Time, warnDays int) (string, bool) {
+	if expiresAt == nil || warnDays <= 0 {
+		return "", false
+	}
+
+	// GitLab expiry dates are whole days; compare calendar days in UTC
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	expiry := time.Date(expiresAt.Year(), expiresAt.Month(), expiresAt.Day(), 0, 0, 0, 0, time.UTC)
+	daysLeft := int(expiry.Sub(today).Hours() / 24)
+
+	switch {
+	case daysLeft < 0:
+		return fmt.Sprintf("GitLab token expired on %s", expiry.Format("2006-01-02")), true
+	case daysLeft == 0:
+		return "GitLab token expires today", true
+	case daysLeft == 1:
+		return "GitLab token expires tomorrow", true
+	case daysLeft <= warnDays:
+		return fmt.Sprintf("GitLab token expires in %d days (%s)", daysLeft, expiry.Format("2006-01-02")), true
+	default:
+		return "", false
 	}
+}
 
-	// If index was recreated, we're already in a full sync context, so just log it
-	if recreated {
-		logger.Debug("Index schema updated during indexing, new index created with current version")
+// detectRenames returns old path -> new path for projects whose ID is already
+// indexed under a different path (renamed or transferred on GitLab)
+func detectRenames(existing, current []model.Project) map[string]string {
+	return matchRenames(pathsByID(existing), current)
+}
+
+// pathsByID maps GitLab project IDs to paths (projects without an ID are skipped)
+func pathsByID(projects []model.Project) map[int64]string {
+	pathByID := make(map[int64]string, len(projects))
+	for _, proj := range projects {
+		if proj.ID != 0 {
+			pathByID[proj.ID] = proj.Path
+		}
 	}
-	defer func() {
-		if err := descriptionIndex.Close(); err != nil {
-			logger.Debug("Failed to close index: %v", err)
+	return pathByID
+}
+
+// matchRenames returns old path -> new path for current projects whose ID maps to another path
+func matchRenames(pathByID map[int64]string, current []model.Project) map[string]string {
+	renames := make(map[string]string)
+	for _, proj := range current {
+		if proj.ID == 0 {
+			continue
 		}
-	}()
+		if oldPath, ok := pathByID[proj.ID]; ok && oldPath != proj.Path {
+			renames[oldPath] = proj.Path
+		}
+	}
+	return renames
+}
 
-	// Get current document count
-	docCount, countErr := descriptionIndex.Count()
-	if countErr != nil {
-		logger.Debug("Failed to get document count: %v", countErr)
-	} else if docCount > 0 {
-		logger.Debug("Existing index has %d documents", docCount)
+// remapHistory moves history entries for renamed projects and returns the number of remapped entries
+func remapHistory(stateDir string, renames map[string]string) int {
+	hist := history.New(paths.HistoryPath(stateDir))
+	if err := <-hist.LoadAsync(); err != nil {
+		logger.Debug("Failed to load history for rename remap: %v", err)
+		return 0
 	}
 
-	// For full sync: remove projects from index that are no longer on GitLab
-	if isFullSync {
-		// Get all projects currently in index
-		existingProjects, err := descriptionIndex.GetAllProjects()
-		if err != nil {
-			logger.Debug("Failed to get existing projects from index: %v", err)
-		} else {
-			// Build a set of current project paths from GitLab
-			currentPaths := make(map[string]bool, len(projects))
-			for _, proj := range projects {
-				currentPaths[proj.Path] = true
-			}
+	var remapped int
+	for oldPath, newPath := range renames {
+		n := hist.Remap(oldPath, newPath)
+		if n > 0 {
+			logger.Debug("Remapped history: %s -> %s (%d entries)", oldPath, newPath, n)
+		}
+		remapped += n
+	}
 
-			// Find and delete projects that are no longer on GitLab
-			var deleted int
-			for _, existingProj := range existingProjects {
-				if !currentPaths[existingProj.Path] {
-					if err := descriptionIndex.Delete(existingProj.Path); err != nil {
-						logger.Debug("Failed to delete project %s: %v", existingProj.Path, err)
-					} else {
-						deleted++
-					}
-				}
-			}
+	if err := hist.Save(); err != nil {
+		logger.Debug("Failed to save remapped history: %v", err)
+	}
+	return remapped
+}
 
-			if deleted > 0 {
-				logInfo("Removed %d deleted projects from index", deleted)
-			}
-		}
+// rekeyHistory moves history recorded by project path (or a former path) to the project's
+// key (see model.Project.Key) and returns the number of moved entries
+// History was keyed by path before project IDs were; this migrates it once, and later only
+// selections recorded by path because the project was not indexed yet
+func rekeyHistory(stateDir string, projects []model.Project) int {
+	keys := historyKeys(projects)
+	if len(keys) == 0 {
+		return 0
 	}
 
-	// Prepare documents for batch indexing
-	var indexed int
-	batchDocs := make([]index.DescriptionDocument, 0, 500)
+	hist := history.New(paths.HistoryPath(stateDir))
+	if err := <-hist.LoadAsync(); err != nil {
+		logger.Debug("Failed to load history for project keys: %v", err)
+		return 0
+	}
+	rekeyed := hist.Rekey(keys)
+	if rekeyed > 0 {
+		if err := hist.Save(); err != nil {
+			logger.Debug("Failed to save rekeyed history: %v", err)
+			return 0
+		}
+	}
+	return rekeyed
+}
 
+// historyKeys maps the path and former paths of projects with an ID to their history key
+func historyKeys(projects []model.Project) map[string]string {
+	keys := make(map[string]string)
 	for _, proj := range projects {
-		// Index all projects, even those without descriptions
-		batchDocs = append(batchDocs, index.DescriptionDocument{
-			ProjectPath: proj.Path,
-			ProjectName: proj.Name,
-			Description: proj.Description,
-			Starred:     proj.Starred,
-			Archived:    proj.Archived,
-			Member:      proj.Member,
-		})
-
-		// Index batch when it reaches 500 docs
-		if len(batchDocs) >= 500 {
-			if err := descriptionIndex.AddBatch(batchDocs); err != nil {
-				logger.Debug("Failed to index batch: %v", err)
-				return fmt.Errorf("failed to index batch: %w", err)
-			}
-			indexed += len(batchDocs)
-			batchDocs = batchDocs[:0] // Clear batch
-
-			// Show progress
-			if indexed%50 == 0 {
-				logger.Debug("Progress: %d/%d (%d%%)", indexed, len(projects), (indexed*100)/len(projects))
+		if proj.ID != 0 {
+			keys[proj.Path] = proj.Key()
+		}
+	}
+	// A former path taken over by another project belongs to that project
+	for _, proj := range projects {
+		if proj.ID == 0 {
+			continue
+		}
+		for _, former := range proj.FormerPaths {
+			if _, taken := keys[former]; !taken {
+				keys[former] = proj.Key()
 			}
 		}
 	}
+	return keys
+}
 
-	// Index remaining documents
-	if len(batchDocs) > 0 {
-		if err := descriptionIndex.AddBatch(batchDocs); err != nil {
-			logger.Debug("Failed to index final batch: %v", err)
-			return fmt.Errorf("failed to index final batch: %w", err)
-		}
-		indexed += len(batchDocs)
+// indexSnapshot returns the projects of the index snapshot in cacheDir (nil without one)
+// Commands that only map between paths and history keys read it instead of opening the
+// index, which a running TUI or sync may hold
+func indexSnapshot(cacheDir string) []model.Project {
+	projects, ok := index.ReadSnapshot(paths.IndexPath(cacheDir))
+	if !ok {
+		logger.Debug("No index snapshot: history keys are not mapped to paths")
 	}
+	return projects
+}
 
-	elapsed := time.Since(start)
-	logSuccess("Description indexing complete in %v", elapsed)
-	logInfo("  Indexed: %d projects", indexed)
+// historyDisplayPaths maps the history keys of projects to their paths, for listing history
+func historyDisplayPaths(projects []model.Project) map[string]string {
+	displayPaths := make(map[string]string, len(projects))
+	for _, proj := range projects {
+		displayPaths[proj.Key()] = proj.Path
+	}
+	return displayPaths
+}
 
-	return nil
+// indexStats counts what indexDescriptionsWithStats changed in the index
+type indexStats struct {
+	indexed int // Documents written
+	added   int // Projects that were not in the index before
+	updated int // Indexed projects whose indexed fields changed (see projectChanged)
+	renamed int // Projects moved to a new path (same GitLab ID)
+	removed int // Projects deleted from the index (full sync only)
+}
+
+// indexDescriptions indexes project descriptions for full-text search
+func indexDescriptions(projects []model.Project, cacheDir string, silent bool, isFullSync bool) error {
+	_, err := indexDescriptionsWithStats(projects, cacheDir, silent, isFullSync)
+	return err
+}
+
+// indexDescriptionsWithStats is indexDescriptions returning counts of the index changes
+func indexDescriptionsWithStats(projects []model.Project, cacheDir string, silent bool, isFullSync bool) (indexStats, error) {
+	if silent {
+		logger.Debug("Indexing project descriptions...")
+	} else {
+		logger.Info("Indexing project descriptions...")
+	}
+
+	ix, err := newSyncIndexer(cacheDir, silent, isFullSync)
+	if err != nil {
+		return indexStats{}, err
+	}
+	if len(projects) > 0 {
+		_ = ix.add(projects) // Error is returned by finish
+	}
+	return ix.finish()
 }
 
 // runConfigWizard runs the interactive configuration wizard
-func runConfigWizard() error {
+// detectedURL (found in the git remotes of the current directory) is offered when no URL is configured
+func runConfigWizard(detectedURL string) error {
 	reader := bufio.NewReader(os.Stdin)
 
 	// Check if config exists
-	configPath := filepath.Clean(filepath.Join(os.Getenv("HOME"), ".config", "glf", "config.yaml"))
+	configPath := filepath.Clean(config.Path())
 	configExists := false
 	if _, err := os.Stat(configPath); err == nil {
 		configExists = true
@@ -1292,11 +2818,18 @@ func runConfigWizard() error {
 		// If no config exists, create empty config for defaults
 		existingCfg = &config.Config{}
 	}
+	if existingCfg.GitLab.OAuth {
+		existingCfg.GitLab.Token = "" // Never offer the short-lived --login token as the default
+	}
 
-	// Step 1: Get and validate GitLab URL
+	// Step 1: Get and validate GitLab URL (the configured one, or the one found in git remotes)
+	defaultURL, detected := existingCfg.GitLab.URL, false
+	if defaultURL == "" && detectedURL != "" {
+		defaultURL, detected = detectedURL, true
+	}
 	var gitlabURL string
 	for {
-		urlInput, err := promptForURL(reader, existingCfg.GitLab.URL)
+		urlInput, err := promptForURL(reader, defaultURL, detected)
 		if err != nil {
 			return err
 		}
@@ -1345,37 +2878,17 @@ func runConfigWizard() error {
 	}
 
 	// Step 4: Create config and test connection (use default timeout)
-	cfg := &config.Config{
-		GitLab: config.GitLabConfig{
-			URL:     gitlabURL,
-			Token:   token,
-			Timeout: 30, // Default timeout
-		},
-		Cache:         existingCfg.Cache,
-		ExcludedPaths: existingCfg.ExcludedPaths,
-	}
+	cfg := wizardConfig(gitlabURL, token, existingCfg)
 
 	if err := testConnectionWithRetry(cfg, reader); err != nil {
 		return err
 	}
 
 	// Step 6: Save configuration
-	configDir := filepath.Clean(filepath.Join(os.Getenv("HOME"), ".config", "glf"))
-	if err := os.MkdirAll(configDir, 0750); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
-
-	data, err := yaml.Marshal(cfg)
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
-
-	if err := os.WriteFile(configPath, data, 0600); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+	if err := saveWizardConfig(cfg, configPath); err != nil {
+		return err
 	}
 
-	fmt.Printf("✓ Configuration saved to %s\n", configPath)
-
 	// Step 7: Automatically perform full sync and launch
 	fmt.Println()
 	fmt.Println("🎉 Configuration Complete!")
@@ -1397,7 +2910,7 @@ func runConfigWizard() error {
 	fmt.Println()
 
 	// Load projects from index
-	indexPath := filepath.Join(cfg.Cache.Dir, "description.bleve")
+	indexPath := paths.IndexPath(cfg.Cache.Dir)
 	descIndex, _, err := index.NewDescriptionIndexWithAutoRecreate(indexPath)
 	if err != nil {
 		fmt.Printf("⚠️  Failed to open index: %v\n", err)
@@ -1410,6 +2923,59 @@ func runConfigWizard() error {
 	return runInteractive("", cfg, descIndex)
 }
 
+// wizardConfig creates the configuration written by --init: the GitLab URL and token with the
+// default timeout, keeping the token backend, cache and exclusions of the existing configuration
+func wizardConfig(gitlabURL, token string, existingCfg *config.Config) *config.Config {
+	return &config.Config{
+		GitLab: config.GitLabConfig{
+			URL:     gitlabURL,
+			Token:   token,
+			Timeout: 30, // Default timeout
+
+			TokenBackend:  existingCfg.GitLab.TokenBackend,
+			TokenCommand:  existingCfg.GitLab.TokenCommand,
+			OAuthClientID: existingCfg.GitLab.OAuthClientID,
+		},
+		Cache:         existingCfg.Cache,
+		ExcludedPaths: existingCfg.ExcludedPaths,
+	}
+}
+
+// saveWizardConfig writes the --init configuration to configPath
+// The token goes to the external token backend instead, if one is configured
+func saveWizardConfig(cfg *config.Config, configPath string) error {
+	if err := config.EnsureConfigDir(); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	// Keep the token out of config.yaml when an external backend is configured
+	fileCfg := *cfg
+	backend := tokenstore.Normalize(cfg.GitLab.TokenBackend, cfg.GitLab.TokenCommand)
+	if tokenstore.IsExternal(backend) {
+		if tokenstore.IsWritable(backend) {
+			if err := tokenstore.Store(backend, cfg.GitLab.URL, cfg.GitLab.Token); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Token saved to %s\n", backend)
+		}
+		fileCfg.GitLab.Token = ""
+	} else {
+		fileCfg.GitLab.TokenBackend = ""
+	}
+
+	data, err := yaml.Marshal(&fileCfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	fmt.Printf("✓ Configuration saved to %s\n", configPath)
+	return nil
+}
+
 // maskToken masks a token for display, showing only first and last 4 characters
 func maskToken(token string) string {
 	if len(token) <= 8 {
@@ -1533,12 +3099,16 @@ func showTokenHelper(gitlabURL string) {
 }
 
 // promptForURL prompts for GitLab URL with better formatting
-func promptForURL(reader *bufio.Reader, existingURL string) (string, error) {
+func promptForURL(reader *bufio.Reader, existingURL string, detected bool) (string, error) {
 	printSection("🌐", "GitLab Instance URL")
 
 	if existingURL != "" {
 		fmt.Println()
-		printMuted(fmt.Sprintf("Current: %s", existingURL))
+		if detected {
+			printMuted(fmt.Sprintf("Detected from git remote: %s", existingURL))
+		} else {
+			printMuted(fmt.Sprintf("Current: %s", existingURL))
+		}
 		fmt.Println()
 		printPrompt("New URL [Enter to keep]: ")
 	} else {
@@ -1606,7 +3176,7 @@ func testConnectionWithRetry(cfg *config.Config, reader *bufio.Reader) error {
 		if err == nil {
 			fmt.Println()
 			printSuccess("Connection successful!")
-			return nil
+			return checkWizardTokenScopes(cfg, client)
 		}
 
 		// Connection failed - show error and options
@@ -1644,6 +3214,28 @@ func testConnectionWithRetry(cfg *config.Config, reader *bufio.Reader) error {
 	}
 }
 
+// checkWizardTokenScopes verifies the scopes of the token entered in the wizard
+// A token without read_api is rejected before the configuration is saved
+func checkWizardTokenScopes(cfg *config.Config, client *gitlab.Client) error {
+	info := fetchTokenInfo(cfg, client)
+	if info == nil {
+		return nil
+	}
+	err := checkTokenScopes(cfg, info, func(format string, args ...interface{}) {
+		fmt.Println()
+		printWarning(fmt.Sprintf(format, args...))
+	})
+	if err != nil {
+		fmt.Println()
+		printError(err.Error())
+		printBullet(fmt.Sprintf("Create a token with the %s scope at: %s", requiredTokenScope, generateTokenURL(cfg.GitLab.URL)))
+		printBullet("Then run 'glf --init' again")
+		return withExitCode(exitCodeConfig, fmt.Errorf("token lacks the %s scope, please run 'glf --init' again with a new token", requiredTokenScope))
+	}
+	printSuccess("Token scopes verified")
+	return nil
+}
+
 func init() {
 	// Set version info
 	rootCmd.Version = fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, buildTime)
@@ -1655,33 +3247,118 @@ func init() {
 	// Add flags
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose logging")
 	rootCmd.PersistentFlags().BoolVar(&showScores, "scores", false, "show score breakdown (search + history)")
+	rootCmd.PersistentFlags().BoolVar(&resumeSession, "resume", false, "restore the last TUI session: query, filter toggles and selected result (see tui.resume)")
+	rootCmd.PersistentFlags().BoolVar(&editMode, "edit", false, "open the local clone of the first result in $EDITOR (clones into workspace_dir if needed)")
+	rootCmd.PersistentFlags().BoolVar(&cdMode, "cd", false, "print the local clone path of the selected project (see --shell-init)")
+	rootCmd.PersistentFlags().StringVar(&shellInit, "shell-init", "", "print the gcd shell function (bash, zsh or fish)")
 	rootCmd.PersistentFlags().BoolVar(&autoGo, "go", false, "auto-select first result and open in browser")
 	rootCmd.PersistentFlags().BoolVarP(&autoGo, "open", "g", false, "alias for --go (for compatibility)")
+	rootCmd.PersistentFlags().StringVar(&openWith, "open-with", "", "run the named opener from the config on the selected project instead of opening the browser")
+	rootCmd.PersistentFlags().BoolVar(&openFile, "file", false, "open a file on the default branch: glf --file <query> -- <path>[:line]")
 	rootCmd.PersistentFlags().BoolVarP(&doSync, "sync", "s", false, "synchronize projects cache")
 	rootCmd.PersistentFlags().BoolVar(&forceFull, "full", false, "force full sync (use with --sync)")
+	rootCmd.PersistentFlags().BoolVar(&starredOnly, "starred", false, "only refresh starred and member projects (use with --sync; fast refresh between full syncs)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "report what a sync would change without applying it (use with --sync)")
+	rootCmd.PersistentFlags().StringVar(&fromSnapshot, "from-snapshot", "", "seed the cache from a team snapshot (.tar.gz of a cache directory, URL or file) before syncing (use with --sync)")
+	rootCmd.PersistentFlags().StringVar(&serveAddr, "serve", "", "serve a local HTTP query API (/search, /record, /projects) for editor plugins on ADDR (e.g. :7345)")
+	rootCmd.PersistentFlags().StringVar(&listenAddr, "listen", "", "serve a GitLab system hook endpoint on ADDR (e.g. :8080) and apply project events to the index")
 	rootCmd.PersistentFlags().BoolVar(&doInit, "init", false, "run interactive configuration wizard")
+	rootCmd.PersistentFlags().BoolVar(&doLogin, "login", false, "sign in through the browser with the GitLab OAuth device flow (needs gitlab.oauth_client_id)")
+	rootCmd.PersistentFlags().StringVar(&newProjectPath, "new", "", "create a project at GROUP/NAME with the new_project defaults, then print its clone URL and open it")
 	rootCmd.PersistentFlags().BoolVar(&resetFlag, "reset", false, "reset configuration and start from scratch (use with --init)")
+	rootCmd.PersistentFlags().BoolVar(&assumeYes, "yes", false, "answer yes to confirmations: configure without prompts (with --init; needs --url and --token-stdin on first setup) or repair without prompts (with --doctor)")
+	rootCmd.PersistentFlags().StringVar(&initURL, "url", "", "GitLab URL to configure (use with --init --yes)")
+	rootCmd.PersistentFlags().BoolVar(&tokenStdin, "token-stdin", false, "read the GitLab token from stdin (use with --init --yes)")
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "output results in JSON format (for integrations)")
-	rootCmd.PersistentFlags().IntVar(&limitResults, "limit", 20, "limit number of results (for JSON mode)")
+	rootCmd.PersistentFlags().BoolVar(&plainOutput, "plain", false, "print a \"path<TAB>description\" line per matching project for pickers like fzf (all matches unless --limit)")
+	rootCmd.PersistentFlags().BoolVar(&print0Output, "print0", false, "like --plain, but end each record with a NUL byte instead of a newline")
+	rootCmd.PersistentFlags().BoolVar(&vimOutput, "vim", false, "print a \"path<TAB>score<TAB>start-end,...\" line per match with the byte offsets of the matched parts of the path (for editor plugins)")
+	rootCmd.PersistentFlags().BoolVar(&selectOne, "select-1", false, "open the result without the TUI when the query matches exactly one project (with --plain/--print0: print its record)")
+	rootCmd.PersistentFlags().BoolVar(&urlsOutput, "urls", false, "print the URL of each matching project, one per line (all matches unless --limit; --target picks the page)")
+	rootCmd.PersistentFlags().StringVar(&formatTemplate, "format", "", "print each result through a Go template over the JSON project fields (e.g. '{{.Path}}\\t{{.URL}}')")
+	rootCmd.PersistentFlags().IntVar(&limitResults, "limit", 20, "limit number of results (for JSON mode and --format)")
+	rootCmd.PersistentFlags().IntVar(&offsetResults, "offset", 0, "skip the first N results (for JSON mode pagination with --limit)")
+	rootCmd.PersistentFlags().BoolVar(&allResults, "all", false, "return every matching project in JSON mode, --format, --plain and --vim (ranks the whole cache)")
+	rootCmd.PersistentFlags().StringVar(&sortBy, "sort", sortScore, "order results in JSON mode and --format by score, path, name or activity (most recent first)")
 	rootCmd.PersistentFlags().BoolVar(&showHistory, "history", false, "show search history with scores")
+	rootCmd.PersistentFlags().StringVar(&explainPath, "explain", "", "explain how a project's history score is computed (use with --history; remaining args or --query give the query context)")
 	rootCmd.PersistentFlags().BoolVar(&clearHistory, "clear-history", false, "clear search history")
+	rootCmd.PersistentFlags().BoolVar(&regexMode, "regex", false, "treat the query as a Go regular expression matched against project paths (toggle with Alt+R in TUI)")
+	rootCmd.PersistentFlags().BoolVar(&showStatus, "status", false, "show cache status: project count, last sync, and on-disk size")
+	rootCmd.PersistentFlags().BoolVar(&doDoctor, "doctor", false, "check the index, sync times, history and lock files, and repair the problems found (asks first; --yes repairs all)")
+	rootCmd.PersistentFlags().BoolVar(&remapHist, "remap-history", false, "move history from OLD_PATH to NEW_PATH (project or group): glf --remap-history old/path new/path")
 	rootCmd.PersistentFlags().BoolVar(&showHidden, "show-hidden", false, "show hidden projects (excluded, archived, non-member) - toggle with Ctrl+H in TUI")
 	rootCmd.PersistentFlags().StringVar(&jsonRecord, "json-record", "", "record project selection in history (project path, for JSON integrations)")
-	rootCmd.PersistentFlags().StringVar(&queryContext, "query", "", "query context for recording selection (optional, used with --json-record)")
+	rootCmd.PersistentFlags().StringVar(&queryContext, "query", "", "query context for recording selection (used with --json-record) or query to show selections for (used with --history)")
+	rootCmd.PersistentFlags().BoolVar(&historyStats, "stats", false, "break selections down by source (tui, go-flag, json-record, clone) and week (use with --history)")
+	rootCmd.PersistentFlags().BoolVar(&topQueries, "top-queries", false, "list the most frequently used search queries (up to --limit)")
+	rootCmd.PersistentFlags().StringVarP(&targetName, "target", "t", "", "open a project sub-page instead of the root (e.g., mrs, pipelines, settings/ci_cd)")
+	rootCmd.PersistentFlags().BoolVar(&pickTarget, "pick", false, "choose a project sub-page interactively (use with 'glf .')")
+	rootCmd.PersistentFlags().StringVar(&pinPath, "pin", "", "pin a project so it always appears at the top of results (e.g., group/project)")
+	rootCmd.PersistentFlags().StringVar(&unpinPath, "unpin", "", "unpin a project")
+	rootCmd.PersistentFlags().StringVar(&unpinQueryText, "unpin-query", "", "remove the pin of a query (see search.pin_after)")
+	rootCmd.PersistentFlags().BoolVar(&listPins, "pins", false, "list pinned projects and queries")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "never touch the network: use cached projects only (no username fetch or sync)")
+	rootCmd.PersistentFlags().BoolVar(&noSync, "no-sync", false, "never sync automatically (empty cache or schema update fails with exit code 4)")
+	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "never prompt, launch the TUI, or open a browser")
+	rootCmd.PersistentFlags().BoolVar(&ciMode, "ci", false, "strict mode for scripts: --no-sync --non-interactive --json with defined exit codes")
+	rootCmd.PersistentFlags().BoolVar(&doUpdate, "update", false, "update glf to the latest release (checksum-verified)")
+	rootCmd.PersistentFlags().BoolVar(&showUsers, "users", false, "search the instance's users and open a profile (--target mrs: assigned MRs); requires gitlab.sync_users")
+	rootCmd.PersistentFlags().BoolVar(&showGroups, "groups", false, "search groups and subgroups instead of projects (opens the group overview); --sync refreshes them")
+	rootCmd.PersistentFlags().BoolVar(&showMRs, "mrs", false, "search my open merge requests (assigned or authored) instead of projects; --sync refreshes them")
 
 	// Set up verbose mode before command execution
 	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
 		logger.SetVerbose(verbose)
 		logger.Debug("Verbose mode enabled")
+		if jsonErrorsRequested() {
+			// Errors are reported as JSON by main, not as cobra usage text
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
 	}
+
+	// Invalid flags are usage errors (reported as JSON too when --json or --ci was given)
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		if jsonErrorsRequested() {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+		return withExitCode(exitCodeUsage, err)
+	})
 }
 
 func main() {
 	// Enable interspersed flags (flags can appear anywhere in the command line)
 	rootCmd.Flags().SetInterspersed(true)
 
+	logger.InitTraceID()
+	defer reportCrash()
+
+	keepQueriesForScripts(os.Args[1:])
 	if err := rootCmd.Execute(); err != nil {
-		logger.Error("%v", err)
-		os.Exit(1)
+		var exitErr *exitError
+		silent := errors.As(err, &exitErr) && exitErr.err == nil
+		switch {
+		case silent:
+		case jsonErrorsRequested():
+			if encErr := outputJSON(newJSONError(err)); encErr != nil {
+				logger.Error("%v", err)
+			}
+		default:
+			logger.Error("%v", err)
+			if hint := errorHint(err); hint != "" {
+				logger.Info("Hint: %s", hint)
+			}
+		}
+		os.Exit(exitCodeFor(err))
+	}
+}
+
+// reportCrash prints the trace ID alongside a panic so crash reports can be matched to debug logs and API requests
+func reportCrash() {
+	if r := recover(); r != nil {
+		fmt.Fprintf(os.Stderr, "glf crashed (trace ID %s, %s). Please include this in your bug report.\n", logger.TraceID(), version)
+		panic(r)
 	}
 }
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/cache"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/search"
+	"github.com/igusev/glf/internal/tui"
+)
+
+// mergeRequestsTTL is how long cached merge requests are used without refetching
+const mergeRequestsTTL = 5 * time.Minute
+
+type (
+	// JSONMergeRequestsResult represents the --mrs response in JSON mode
+	JSONMergeRequestsResult struct {
+		Query         string             `json:"query"`          // Search query that was executed
+		MergeRequests []JSONMergeRequest `json:"merge_requests"` // Matching merge requests
+		Total         int                `json:"total"`          // Total number of results
+		FetchedAt     time.Time          `json:"fetched_at"`     // When the merge requests were fetched from GitLab
+	}
+
+	// JSONMergeRequest represents a single merge request in JSON output
+	JSONMergeRequest struct {
+		Project   string    `json:"project"`          // Target project path
+		IID       int64     `json:"iid"`              // Project-scoped number (!iid)
+		Title     string    `json:"title"`            // Merge request title
+		Author    string    `json:"author"`           // Author username
+		Labels    []string  `json:"labels,omitempty"` // Labels
+		Draft     bool      `json:"draft"`            // Whether the merge request is a draft
+		URL       string    `json:"url"`              // Merge request URL
+		UpdatedAt time.Time `json:"updated_at"`       // Last update time
+	}
+)
+
+// runMergeRequests handles --mrs: fuzzy search over the user's open merge requests
+// Merge requests are cached for mergeRequestsTTL; --sync forces a refetch
+func runMergeRequests(cfg *config.Config, query string) error {
+	if doSync && offline {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--sync cannot be used with --offline"))
+	}
+
+	mrs, fetchedAt, err := loadMergeRequests(cfg)
+	if err != nil {
+		return err
+	}
+
+	matches := search.FilterMergeRequests(mrs, query)
+	if limitResults > 0 && len(matches) > limitResults {
+		matches = matches[:limitResults]
+	}
+
+	switch {
+	case jsonOutput:
+		result := JSONMergeRequestsResult{
+			Query:         query,
+			MergeRequests: make([]JSONMergeRequest, 0, len(matches)),
+			Total:         len(matches),
+			FetchedAt:     fetchedAt,
+		}
+		for _, mr := range matches {
+			result.MergeRequests = append(result.MergeRequests, JSONMergeRequest{
+				Project:   mr.ProjectPath,
+				IID:       mr.IID,
+				Title:     mr.Title,
+				Author:    mr.Author,
+				Labels:    mr.Labels,
+				Draft:     mr.Draft,
+				URL:       mr.WebURL,
+				UpdatedAt: mr.UpdatedAt,
+			})
+		}
+		if err := outputJSON(result); err != nil {
+			return err
+		}
+		if ciMode && len(matches) == 0 {
+			return withExitCode(exitCodeNoResults, nil)
+		}
+		return nil
+
+	case autoGo:
+		if len(matches) == 0 {
+			return withExitCode(exitCodeNoResults, fmt.Errorf("no merge requests found matching '%s'", query))
+		}
+		return openMergeRequest(matches[0].WebURL)
+
+	case nonInteractive:
+		for _, mr := range matches {
+			fmt.Printf("%s\t%s\n", mr.DisplayString(), mr.WebURL)
+		}
+		return nil
+	}
+
+	finalModel, err := tea.NewProgram(tui.NewMergeRequests(mrs, query, version), tea.WithAltScreen()).Run()
+	if err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+	if m, ok := finalModel.(tui.MergeRequestsModel); ok && m.SelectedURL() != "" {
+		return openMergeRequest(m.SelectedURL())
+	}
+	return nil
+}
+
+// loadMergeRequests returns cached merge requests, refetching them when the cache is
+// stale or --sync is given. A failed refetch falls back to the stale cache with a warning.
+func loadMergeRequests(cfg *config.Config) ([]model.MergeRequest, time.Time, error) {
+	cacheManager := cache.New(cfg.Cache.GetStateDir()) // Per-user, so not in a shared cache
+	cached, fetchedAt, err := cacheManager.LoadMergeRequests()
+	if err != nil {
+		logger.Debug("Failed to load cached merge requests: %v", err)
+	}
+
+	fresh := !fetchedAt.IsZero() && time.Since(fetchedAt) < mergeRequestsTTL
+	if (fresh && !doSync) || (!doSync && (noSync || offline)) {
+		if fetchedAt.IsZero() {
+			return nil, fetchedAt, withExitCode(exitCodeNoCache, errors.New("no cached merge requests; run 'glf --mrs --sync' first"))
+		}
+		logger.Debug("Using %d cached merge requests (fetched %v ago)", len(cached), time.Since(fetchedAt).Round(time.Second))
+		return cached, fetchedAt, nil
+	}
+
+	mrs, err := fetchMergeRequests(cfg)
+	if err != nil {
+		if fetchedAt.IsZero() || doSync {
+			return nil, fetchedAt, withExitCode(exitCodeSyncFailed, err)
+		}
+		logger.Warn("Failed to refresh merge requests, showing cached results from %s: %v", fetchedAt.Format("15:04"), err)
+		return cached, fetchedAt, nil
+	}
+
+	fetchedAt = time.Now()
+	if err := cacheManager.SaveMergeRequests(mrs, fetchedAt); err != nil {
+		logger.Debug("Failed to cache merge requests: %v", err)
+	}
+	return mrs, fetchedAt, nil
+}
+
+// fetchMergeRequests fetches the user's open merge requests from GitLab
+func fetchMergeRequests(cfg *config.Config) ([]model.MergeRequest, error) {
+	client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout())
+	if err != nil {
+		return nil, fmt.Errorf("GitLab client error: %w", err)
+	}
+	return client.ListMyMergeRequests()
+}
+
+// openMergeRequest opens a merge request in the browser and prints its URL
+func openMergeRequest(mrURL string) error {
+	logger.Debug("Opening browser with URL: %s", mrURL)
+	if err := openBrowser(mrURL); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to open browser: %v\n", err)
+		logger.Debug("Browser open error: %v", err)
+	}
+	fmt.Println(mrURL)
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/paths"
+)
+
+// JSONNewProjectResult represents the --new response in JSON mode
+type JSONNewProjectResult struct {
+	Project  JSONProject `json:"project"`   // The created project
+	CloneURL string      `json:"clone_url"` // URL to clone it with (SSH if the instance offers it)
+}
+
+// runNewProject handles --new: creates a project with the new_project defaults,
+// adds it to the index, prints its clone URL and opens it in the browser
+func runNewProject(cfg *config.Config, projectPath string, args []string) error {
+	projectPath = strings.Trim(strings.TrimSpace(projectPath), "/")
+	if projectPath == "" || strings.Contains(projectPath, "//") {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--new requires a project path (e.g., group/name)"))
+	}
+	if len(args) > 0 {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--new takes no search query (got %q)", strings.Join(args, " ")))
+	}
+	if offline {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--new cannot be used with --offline"))
+	}
+
+	client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout())
+	if err != nil {
+		return fmt.Errorf("GitLab client error: %w", err)
+	}
+	project, cloneURL, err := client.CreateProject(projectPath, cfg.NewProject.Visibility, cfg.NewProject.InitReadme)
+	if err != nil {
+		return err
+	}
+	addCreatedProject(cfg, project)
+
+	projectURL := projectRootURL(cfg.GitLab.URL, project.Path)
+	if jsonOutput {
+		return outputJSON(JSONNewProjectResult{
+			Project: JSONProject{
+				Path:          project.Path,
+				Name:          project.Name,
+				Description:   project.Description,
+				URL:           projectURL,
+				Member:        project.Member,
+				DefaultBranch: project.DefaultBranch,
+				Visibility:    project.Visibility,
+			},
+			CloneURL: cloneURL,
+		})
+	}
+
+	logger.Success("Created %s (%s)", project.Path, project.Visibility)
+	fmt.Println(cloneURL)
+	if err := openBrowser(projectURL); err != nil {
+		logger.Debug("Failed to open browser: %v", err)
+		fmt.Println(projectURL)
+	}
+	return nil
+}
+
+// addCreatedProject puts a new project into the index, so it is found before the next sync
+// Skipped without an index (the first sync fetches everything) or with a read-only cache
+func addCreatedProject(cfg *config.Config, project model.Project) {
+	indexPath := paths.IndexPath(cfg.Cache.Dir)
+	if readOnlyCache || !index.Exists(indexPath) {
+		return
+	}
+
+	descIndex, err := index.NewDescriptionIndex(indexPath)
+	if err != nil {
+		logger.Debug("Failed to open index: %v", err)
+		return
+	}
+	defer func() {
+		if err := descIndex.Close(); err != nil {
+			logger.Debug("Failed to close index: %v", err)
+		}
+	}()
+
+	if err := descIndex.AddBatch([]index.DescriptionDocument{index.NewDocument(project)}); err != nil {
+		logger.Debug("Failed to add %s to index: %v", project.Path, err)
+		return
+	}
+	if err := descIndex.SaveSnapshot(); err != nil {
+		logger.Debug("Failed to save project snapshot: %v", err)
+	}
+}
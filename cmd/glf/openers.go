@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/opener"
+	"github.com/igusev/glf/internal/tui"
+)
+
+// findOpener returns the opener named name (--open-with)
+func findOpener(cfg *config.Config, name string) (config.OpenerConfig, error) {
+	names := make([]string, 0, len(cfg.Openers))
+	for _, o := range cfg.Openers {
+		if o.Name == name {
+			return o, nil
+		}
+		names = append(names, o.Name)
+	}
+	if len(names) == 0 {
+		return config.OpenerConfig{}, withHint(withExitCode(exitCodeUsage, fmt.Errorf("unknown opener %q", name)),
+			"define openers in the config (see 'openers' in the README)")
+	}
+	return config.OpenerConfig{}, withExitCode(exitCodeUsage, fmt.Errorf("unknown opener %q (available: %s)", name, strings.Join(names, ", ")))
+}
+
+// openerValues returns the placeholder values of a result; pageURL is the page the browser would open
+func openerValues(cfg *config.Config, path string, group bool, pageURL string) opener.Values {
+	if group {
+		return opener.GroupValues(pageURL, path)
+	}
+	values := opener.ProjectValues(cfg.GitLab.URL, path)
+	values["url"] = pageURL
+	return values
+}
+
+// openResult opens a result's page in the browser, or runs the --open-with opener on it
+// Failures are warnings: the URL is printed on stdout either way
+func openResult(cfg *config.Config, path string, group bool, pageURL string) {
+	if openWith == "" {
+		logger.Debug("Opening browser with URL: %s", pageURL)
+		if err := openBrowser(pageURL); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to open browser: %v\n", err)
+			logger.Debug("Browser open error: %v", err)
+		} else {
+			logger.Debug("Browser command executed successfully")
+		}
+		return
+	}
+
+	o, err := findOpener(cfg, openWith)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	if nonInteractive {
+		logger.Debug("Non-interactive mode: not running opener %q on %s", o.Name, path)
+		return
+	}
+	cmd := opener.Command(o.Command, openerValues(cfg, path, group, pageURL))
+	logger.Debug("Running opener %q: %v", o.Name, cmd.Args)
+	// stdout is reserved for the printed URL
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stderr, os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: opener %q failed: %v\n", o.Name, err)
+	}
+}
+
+// newCustomOpeners returns the openers bound to TUI keys; they run while the TUI keeps running,
+// so their output is captured and only shown when they fail
+func newCustomOpeners(cfg *config.Config) []tui.CustomOpener {
+	var openers []tui.CustomOpener
+	for _, o := range cfg.Openers {
+		if o.Key == "" {
+			continue
+		}
+		openers = append(openers, tui.CustomOpener{
+			Key:  o.Key,
+			Name: o.Name,
+			Open: func(path string, group bool) error {
+				pageURL, err := resultURL(cfg, path, group)
+				if err != nil {
+					return err
+				}
+				cmd := opener.Command(o.Command, openerValues(cfg, path, group, pageURL))
+				logger.Debug("Running opener %q: %v", o.Name, cmd.Args)
+				if output, err := cmd.CombinedOutput(); err != nil {
+					if text := strings.TrimSpace(string(output)); text != "" {
+						return errors.New(lastLine(text))
+					}
+					return err
+				}
+				return nil
+			},
+		})
+	}
+	return openers
+}
+
+// lastLine returns the last line of a command's output (usually the error message)
+func lastLine(text string) string {
+	if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+		return text[i+1:]
+	}
+	return text
+}
@@ -0,0 +1,237 @@
+//go:build perf
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/tui"
+)
+
+// Latency budgets for the end-to-end perf harness (run with `make perf`)
+// Budgets can be scaled for slow CI machines with GLF_PERF_BUDGET_FACTOR (e.g., 2.0)
+const (
+	perfDefaultProjects = 20000
+	perfPageSize        = 100
+
+	budgetColdStart      = 300 * time.Millisecond // Open index + count check (what runSearch does before the TUI)
+	budgetFirstKeystroke = 150 * time.Millisecond // Single-character search through the TUI model
+	budgetEmptyRender    = 2 * time.Second        // Empty-query listing of all projects + first View()
+	budgetJSONRoundTrip  = 500 * time.Millisecond // runJSONMode for a typical query, encoded and parsed
+)
+
+// perfBudget returns the budget scaled by GLF_PERF_BUDGET_FACTOR
+func perfBudget(t *testing.T, budget time.Duration) time.Duration {
+	t.Helper()
+	raw := os.Getenv("GLF_PERF_BUDGET_FACTOR")
+	if raw == "" {
+		return budget
+	}
+	factor, err := strconv.ParseFloat(raw, 64)
+	if err != nil || factor <= 0 {
+		t.Fatalf("invalid GLF_PERF_BUDGET_FACTOR %q", raw)
+	}
+	return time.Duration(float64(budget) * factor)
+}
+
+// perfProjectCount returns the number of projects served by the mock GitLab (GLF_PERF_PROJECTS)
+func perfProjectCount(t *testing.T) int {
+	t.Helper()
+	raw := os.Getenv("GLF_PERF_PROJECTS")
+	if raw == "" {
+		return perfDefaultProjects
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		t.Fatalf("invalid GLF_PERF_PROJECTS %q", raw)
+	}
+	return n
+}
+
+// newPerfGitLabServer serves /user and paginated /projects for totalProjects synthetic projects
+// Every 10th project is starred and every 3rd is a member project
+func newPerfGitLabServer(totalProjects int) *httptest.Server {
+	groups := []string{"platform", "backend", "frontend", "infra", "data", "mobile", "security", "tools"}
+	words := []string{"api", "gateway", "service", "worker", "operator", "dashboard", "exporter", "auth", "billing", "search"}
+
+	type project struct {
+		ID                int    `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
+		Name              string `json:"name"`
+		Description       string `json:"description"`
+		Archived          bool   `json:"archived"`
+	}
+
+	all := make([]project, totalProjects)
+	for i := range all {
+		group := groups[i%len(groups)]
+		word := words[(i/len(groups))%len(words)]
+		name := fmt.Sprintf("%s-%s-%d", word, words[i%len(words)], i)
+		all[i] = project{
+			ID:                i + 1,
+			PathWithNamespace: fmt.Sprintf("%s/team-%d/%s", group, i%50, name),
+			Name:              name,
+			Description:       fmt.Sprintf("The %s %s for the %s group", word, words[(i+3)%len(words)], group),
+			Archived:          i%97 == 0,
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/user", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":1,"username":"perf"}`)
+	})
+	mux.HandleFunc("/api/v4/projects", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		subset := all
+		if query.Get("starred") == "true" || query.Get("membership") == "true" {
+			step := 3
+			if query.Get("starred") == "true" {
+				step = 10
+			}
+			subset = make([]project, 0, len(all)/step+1)
+			for i := 0; i < len(all); i += step {
+				subset = append(subset, all[i])
+			}
+		}
+
+		page, _ := strconv.Atoi(query.Get("page"))
+		if page < 1 {
+			page = 1
+		}
+		totalPages := (len(subset) + perfPageSize - 1) / perfPageSize
+		start := (page - 1) * perfPageSize
+		end := start + perfPageSize
+		if start > len(subset) {
+			start = len(subset)
+		}
+		if end > len(subset) {
+			end = len(subset)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Pages", strconv.Itoa(totalPages))
+		w.Header().Set("X-Total", strconv.Itoa(len(subset)))
+		_ = json.NewEncoder(w).Encode(subset[start:end])
+	})
+
+	return httptest.NewServer(mux)
+}
+
+// measure runs fn and fails the test if it exceeds the (scaled) budget
+func measure(t *testing.T, name string, budget time.Duration, fn func()) {
+	t.Helper()
+	budget = perfBudget(t, budget)
+	start := time.Now()
+	fn()
+	elapsed := time.Since(start)
+	t.Logf("%-16s %10v (budget %v)", name, elapsed.Round(time.Microsecond), budget)
+	if elapsed > budget {
+		t.Errorf("%s regressed: %v exceeds budget %v", name, elapsed, budget)
+	}
+}
+
+// TestPerfBudgets syncs a large synthetic instance from a mock GitLab and checks latency budgets
+func TestPerfBudgets(t *testing.T) {
+	totalProjects := perfProjectCount(t)
+	server := newPerfGitLabServer(totalProjects)
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: server.URL, Token: "perf-token", Timeout: 30, Concurrency: 10},
+		Cache:  config.CacheConfig{Dir: cacheDir},
+	}
+
+	// Build the index through the real sync path (not budgeted - network bound in real life)
+	client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout(), cfg.GitLab.Concurrency)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	syncStart := time.Now()
+	if err := performSyncInternalWithClient(cfg, client, true, true); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	t.Logf("%-16s %10v (%d projects, not budgeted)", "sync+index", time.Since(syncStart).Round(time.Millisecond), totalProjects)
+
+	indexPath := filepath.Join(cacheDir, "description.bleve")
+
+	// Cold start: open the index and run the lightweight emptiness check
+	var descIndex *index.DescriptionIndex
+	measure(t, "cold start", budgetColdStart, func() {
+		var err error
+		descIndex, _, err = index.NewDescriptionIndexWithAutoRecreate(indexPath)
+		if err != nil {
+			t.Fatalf("Failed to open index: %v", err)
+		}
+		count, err := descIndex.Count()
+		if err != nil {
+			t.Fatalf("Failed to count documents: %v", err)
+		}
+		if count <= uint64(totalProjects) {
+			t.Fatalf("Expected %d projects in index, got %d", totalProjects, count-1)
+		}
+	})
+	defer func() { _ = descIndex.Close() }()
+
+	// Empty-query render: all projects sorted by history, plus the first frame
+	measure(t, "empty render", budgetEmptyRender, func() {
+		m := tui.New(nil, "", nil, cacheDir, cfg, false, false, "perf", "perf", descIndex)
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+		if view := updated.View(); view == "" {
+			t.Fatal("Empty view rendered")
+		}
+	})
+
+	// First keystroke: a single-character query through the TUI model
+	measure(t, "first keystroke", budgetFirstKeystroke, func() {
+		m := tui.New(nil, "a", nil, cacheDir, cfg, false, false, "perf", "perf", descIndex)
+		_ = m.View()
+	})
+
+	// JSON round trip: run the --json path and parse its output
+	measure(t, "json round trip", budgetJSONRoundTrip, func() {
+		oldStdout := os.Stdout
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("Failed to create pipe: %v", err)
+		}
+		os.Stdout = w
+
+		done := make(chan []byte)
+		go func() {
+			data, _ := io.ReadAll(r)
+			done <- data
+		}()
+
+		runErr := runJSONMode("gateway service", cfg, descIndex)
+		_ = w.Close()
+		os.Stdout = oldStdout
+		output := <-done
+
+		if runErr != nil {
+			t.Fatalf("runJSONMode failed: %v", runErr)
+		}
+		var result JSONSearchResult
+		if err := json.Unmarshal(output, &result); err != nil {
+			t.Fatalf("Failed to parse JSON output: %v", err)
+		}
+		if result.Total == 0 {
+			t.Fatal("Expected JSON results for 'gateway service'")
+		}
+	})
+}
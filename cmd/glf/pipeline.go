@@ -0,0 +1,390 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/paths"
+)
+
+const (
+	// indexBatchSize is the number of documents written to the index at once
+	indexBatchSize = 500
+	// indexPipelineDepth is the number of fetched pages buffered for the indexer
+	// before the fetcher has to wait for it
+	indexPipelineDepth = 16
+)
+
+// syncIndexer applies fetched projects to the description index
+// Projects can be added in several calls (one per fetched page) while the sync is still
+// fetching; finish then removes projects deleted on GitLab (full sync only) and carries
+// history over to renamed projects
+// A full sync writes a new index next to the current one (see index.NewBuild), which finish
+// swaps in; an interrupted full sync leaves the current index untouched
+type syncIndexer struct {
+	index      *index.DescriptionIndex
+	cacheDir   string
+	stateDir   string // History location for rename remapping (cache.state_dir)
+	isFullSync bool
+	building   bool // index is a new build that replaces the current index in finish
+	logInfo    func(format string, args ...interface{})
+	logSuccess func(format string, args ...interface{})
+	start      time.Time
+
+	existing    []model.Project          // Projects in the index before this sync
+	byPath      map[string]model.Project // existing by path (change counting)
+	existingErr error                    // Set if the index could not be listed (skips counting and cleanup)
+	pathByID    map[int64]string         // Indexed path by GitLab project ID (rename detection)
+	known       map[string]bool          // Paths indexed before or during this sync
+	fetched     map[string]bool          // Paths added during this sync
+	renames     map[string]string        // Old path -> new path of renamed/transferred projects
+	renameMap   index.Renames            // Renames of all syncs (paths.RenamesPath): former paths indexed as aliases
+	renameDirty bool                     // renameMap changed and is saved in close
+	realiased   map[string]bool          // Paths of projects that lost a former path to a new project (re-indexed in finish)
+	stats       indexStats
+	err         error // First indexing error; later adds are skipped
+}
+
+// newSyncIndexer opens (or creates) the description index for a sync
+func newSyncIndexer(cacheDir string, silent bool, isFullSync bool) (*syncIndexer, error) {
+	ix := &syncIndexer{
+		cacheDir:   cacheDir,
+		stateDir:   cacheDir,
+		isFullSync: isFullSync,
+		logInfo:    logger.Info,
+		logSuccess: logger.Success,
+		start:      time.Now(),
+		known:      make(map[string]bool),
+		fetched:    make(map[string]bool),
+		renames:    make(map[string]string),
+		realiased:  make(map[string]bool),
+	}
+	if silent {
+		ix.logInfo = logger.Debug
+		ix.logSuccess = logger.Debug
+	}
+
+	indexPath := paths.IndexPath(cacheDir)
+	descriptionIndex, recreated, err := index.NewDescriptionIndexWithAutoRecreate(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create description index: %w", err)
+	}
+	ix.index = descriptionIndex
+
+	// If index was recreated, we're already in a full sync context, so just log it
+	if recreated {
+		logger.Debug("Index schema updated during indexing, new index created with current version")
+	}
+
+	// Get current document count
+	docCount, countErr := descriptionIndex.Count()
+	if countErr != nil {
+		logger.Debug("Failed to get document count: %v", countErr)
+	} else if docCount > 0 {
+		logger.Debug("Existing index has %d documents", docCount)
+	}
+
+	// Former paths of projects renamed in earlier syncs
+	ix.renameMap, err = index.LoadRenames(paths.RenamesPath(cacheDir))
+	if err != nil {
+		logger.Debug("Failed to load renames: %v", err)
+	}
+
+	// Get all projects currently in index (for rename detection and full sync cleanup)
+	ix.existing, ix.existingErr = descriptionIndex.GetAllProjects()
+	if ix.existingErr != nil {
+		logger.Debug("Failed to get existing projects from index: %v", ix.existingErr)
+	}
+	ix.pathByID = pathsByID(ix.existing)
+	ix.byPath = make(map[string]model.Project, len(ix.existing))
+	for _, proj := range ix.existing {
+		ix.known[proj.Path] = true
+		ix.byPath[proj.Path] = proj
+	}
+
+	// Full sync: index into a new build, the current index stays searchable until finish
+	if isFullSync {
+		if err := descriptionIndex.Close(); err != nil {
+			logger.Debug("Failed to close index: %v", err)
+		}
+		ix.index, err = index.NewBuild(indexPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create description index: %w", err)
+		}
+		ix.building = true
+	}
+
+	return ix, nil
+}
+
+// add indexes fetched projects, counting new and changed ones and recording the former paths
+// of renamed ones
+func (ix *syncIndexer) add(projects []model.Project) error {
+	if ix.err != nil {
+		return ix.err
+	}
+
+	// Renamed/transferred projects (same ID, new path): documents are keyed by ID, so the
+	// new document replaces the old one; the old path is kept as an alias
+	for oldPath, newPath := range matchRenames(ix.pathByID, projects) {
+		if _, done := ix.renames[oldPath]; !done {
+			ix.renames[oldPath] = newPath
+			if !ix.fetched[oldPath] { // Unless another project already took the old path over
+				ix.renameMap.Record(oldPath, newPath)
+				ix.renameDirty = true
+			}
+		}
+		ix.known[newPath] = true // Rename targets don't count as added
+	}
+
+	// A path fetched live belongs to that project, not to one renamed away from it
+	for _, proj := range projects {
+		if current, ok := ix.renameMap[proj.Path]; ok {
+			delete(ix.renameMap, proj.Path)
+			ix.renameDirty = true
+			ix.realiased[current] = true
+		}
+	}
+
+	for _, proj := range projects {
+		if ix.existingErr == nil && !ix.known[proj.Path] {
+			ix.stats.added++
+		} else if old, ok := ix.byPath[proj.Path]; ok && !ix.fetched[proj.Path] && projectChanged(old, proj) {
+			ix.stats.updated++
+		}
+		ix.known[proj.Path] = true
+		ix.fetched[proj.Path] = true
+	}
+
+	indexed, err := ix.write(projects)
+	ix.stats.indexed += indexed
+	if err != nil {
+		ix.err = err
+		return err
+	}
+	logger.Debug("Progress: %d projects indexed", ix.stats.indexed)
+	return nil
+}
+
+// update re-indexes projects that were already added (e.g. after enriching them)
+func (ix *syncIndexer) update(projects []model.Project) error {
+	if ix.err != nil {
+		return ix.err
+	}
+	if _, err := ix.write(projects); err != nil {
+		ix.err = err
+		return err
+	}
+	return nil
+}
+
+// write indexes projects in batches and returns how many were written
+func (ix *syncIndexer) write(projects []model.Project) (int, error) {
+	var written int
+	batchDocs := make([]index.DescriptionDocument, 0, min(len(projects), indexBatchSize))
+
+	for _, proj := range projects {
+		// Index all projects, even those without descriptions
+		// Renamed projects are also found by their former paths
+		proj.FormerPaths = ix.renameMap.FormerPaths(proj.Path)
+		batchDocs = append(batchDocs, index.NewDocument(proj))
+		if len(batchDocs) < indexBatchSize {
+			continue
+		}
+		if err := ix.index.AddBatch(batchDocs); err != nil {
+			logger.Debug("Failed to index batch: %v", err)
+			return written, fmt.Errorf("failed to index batch: %w", err)
+		}
+		written += len(batchDocs)
+		batchDocs = batchDocs[:0]
+	}
+
+	// Index remaining documents
+	if len(batchDocs) > 0 {
+		if err := ix.index.AddBatch(batchDocs); err != nil {
+			logger.Debug("Failed to index final batch: %v", err)
+			return written, fmt.Errorf("failed to index final batch: %w", err)
+		}
+		written += len(batchDocs)
+	}
+	return written, nil
+}
+
+// finish completes the sync: on a full sync, projects that were not fetched are removed
+// from the index. Closes the index
+func (ix *syncIndexer) finish() (indexStats, error) {
+	if ix.err != nil {
+		ix.close()
+		return ix.stats, ix.err
+	}
+
+	// For full sync: remove projects from index that are no longer on GitLab
+	// (a new build never contained them, they are only counted)
+	if ix.isFullSync && ix.existingErr == nil {
+		var deleted int
+		for _, existingProj := range ix.existing {
+			if !ix.fetched[existingProj.Path] && ix.renames[existingProj.Path] == "" {
+				if ix.building {
+					deleted++
+				} else if err := ix.index.Delete(existingProj.Path); err != nil {
+					logger.Debug("Failed to delete project %s: %v", existingProj.Path, err)
+				} else {
+					deleted++
+				}
+			}
+		}
+
+		if deleted > 0 {
+			ix.logInfo("Removed %d deleted projects from index", deleted)
+		}
+		ix.stats.removed = deleted
+
+		// Former paths of deleted projects no longer resolve
+		if ix.renameMap.Prune(ix.fetched) > 0 {
+			ix.renameDirty = true
+		}
+	}
+
+	if err := ix.refreshAliases(); err != nil {
+		ix.close()
+		return ix.stats, err
+	}
+
+	// Startup loads the project list from the snapshot instead of scanning the index
+	if err := ix.index.SaveSnapshot(); err != nil {
+		logger.Debug("Failed to save project snapshot: %v", err)
+	}
+	if ix.building {
+		if err := ix.promote(); err != nil {
+			return ix.stats, err
+		}
+	}
+	ix.close()
+
+	elapsed := time.Since(ix.start)
+	ix.logSuccess("Description indexing complete in %v", elapsed)
+	ix.logInfo("  Indexed: %d projects", ix.stats.indexed)
+
+	return ix.stats, nil
+}
+
+// refreshAliases re-indexes the projects that lost a former path during this sync, which may
+// have been indexed with it (earlier in the sync, or by an earlier sync)
+func (ix *syncIndexer) refreshAliases() error {
+	var projects []model.Project
+	for path := range ix.realiased {
+		project, found, err := ix.index.GetProject(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if found {
+			projects = append(projects, project)
+		}
+	}
+	if len(projects) == 0 {
+		return nil
+	}
+	_, err := ix.write(projects) // write sets the current former paths
+	return err
+}
+
+// promote swaps the completed build in for the current index (kept as the previous generation)
+// and reopens it, so close carries history over as for an in-place sync
+func (ix *syncIndexer) promote() error {
+	if err := ix.index.Close(); err != nil {
+		ix.index = nil
+		index.DiscardBuild(paths.IndexPath(ix.cacheDir))
+		return fmt.Errorf("failed to close new index: %w", err)
+	}
+	ix.index = nil
+	indexPath := paths.IndexPath(ix.cacheDir)
+	if err := index.PromoteBuild(indexPath); err != nil {
+		index.DiscardBuild(indexPath)
+		return fmt.Errorf("failed to replace description index: %w", err)
+	}
+	ix.building = false
+
+	descriptionIndex, err := index.NewDescriptionIndex(indexPath)
+	if err != nil {
+		return fmt.Errorf("failed to open new index: %w", err)
+	}
+	ix.index = descriptionIndex
+	return nil
+}
+
+// close carries history over to the renamed projects seen so far and closes the index
+// Called by finish, or directly when the sync is aborted (nothing is removed from the index;
+// an aborted full sync discards its build and keeps the current index)
+func (ix *syncIndexer) close() {
+	if ix.index == nil {
+		return
+	}
+	if ix.building {
+		if err := ix.index.Close(); err != nil {
+			logger.Debug("Failed to close index build: %v", err)
+		}
+		ix.index = nil
+		index.DiscardBuild(paths.IndexPath(ix.cacheDir))
+		return
+	}
+
+	// History recorded by path (before it was keyed by project ID) follows renames and is
+	// moved to the project IDs
+	ix.stats.renamed = len(ix.renames)
+	if len(ix.renames) > 0 {
+		remapped := remapHistory(ix.stateDir, ix.renames)
+		ix.logInfo("Detected %d renamed projects (history follows them by project ID)", len(ix.renames))
+		logger.Debug("Remapped %d history entries recorded by path", remapped)
+	}
+	if projects, err := ix.index.GetAllProjects(); err != nil {
+		logger.Debug("Failed to list projects for history keys: %v", err)
+	} else if rekeyed := rekeyHistory(ix.stateDir, projects); rekeyed > 0 {
+		logger.Debug("Moved %d history entries from project paths to project IDs", rekeyed)
+	}
+	if ix.renameDirty {
+		if err := ix.renameMap.Save(paths.RenamesPath(ix.cacheDir)); err != nil {
+			logger.Debug("Failed to save renames: %v", err)
+		}
+	}
+
+	if err := ix.index.Close(); err != nil {
+		logger.Debug("Failed to close index: %v", err)
+	}
+	ix.index = nil
+}
+
+// fetchProjectsIndexing fetches projects and hands each page to ix as soon as it arrives,
+// so indexing overlaps with downloading the remaining pages
+// Clients that can't stream (see gitlab.ProjectStreamer) are indexed after the fetch.
+// Indexing errors are kept in ix; the returned error is the fetch error
+func fetchProjectsIndexing(client gitlab.GitLabClient, since *time.Time, membership bool, ix *syncIndexer) ([]model.Project, error) {
+	streamer, ok := client.(gitlab.ProjectStreamer)
+	if !ok || ix == nil {
+		projects, err := client.FetchAllProjects(since, membership)
+		if err == nil && ix != nil && len(projects) > 0 {
+			_ = ix.add(projects) // Error is kept in ix
+		}
+		return projects, err
+	}
+
+	pages := make(chan []model.Project, indexPipelineDepth)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for page := range pages {
+			_ = ix.add(page) // Error is kept in ix; keep draining so the fetcher never blocks
+		}
+	}()
+
+	projects, err := streamer.StreamAllProjects(since, membership, func(page []model.Project) {
+		pages <- page
+	})
+	close(pages)
+	<-done
+
+	return projects, err
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/target"
+)
+
+// plainFieldSpaces flattens tabs and line breaks inside --plain fields, which would split
+// a record for fzf, rofi or dmenu
+var plainFieldSpaces = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ", "\x00", "")
+
+// outputPlain writes one "path<TAB>description" record per project for pickers,
+// each ended by terminator ('\n' for --plain, NUL for --print0)
+func outputPlain(w io.Writer, projects []JSONProject, terminator byte) error {
+	out := bufio.NewWriter(w)
+	for _, project := range projects {
+		_, _ = out.WriteString(project.Path)
+		_ = out.WriteByte('\t')
+		_, _ = out.WriteString(plainFieldSpaces.Replace(strings.TrimSpace(project.Description)))
+		_ = out.WriteByte(terminator)
+	}
+	return out.Flush()
+}
+
+// outputURLs writes the URL of each result, one per line (--urls): the project root, or the
+// --target page (env: targets give the environments page, without a live lookup per project)
+func outputURLs(w io.Writer, gitlabURL string, matches []index.CombinedMatch, page string) error {
+	out := bufio.NewWriter(w)
+	for _, match := range matches {
+		pageURL, err := target.URL(gitlabURL, match.Project.Path, page)
+		if err != nil {
+			return withExitCode(exitCodeUsage, err)
+		}
+		_, _ = out.WriteString(pageURL)
+		_ = out.WriteByte('\n')
+	}
+	return out.Flush()
+}
+
+// visibleMatches drops the projects the TUI hides until Ctrl+H (excluded, archived, non-member)
+// unless --show-hidden is set. Live GitLab results are always kept, as in the TUI
+func visibleMatches(matches []index.CombinedMatch, cfg *config.Config) []index.CombinedMatch {
+	if showHidden {
+		return matches
+	}
+	visible := make([]index.CombinedMatch, 0, len(matches))
+	for _, match := range matches {
+		hidden := cfg.IsExcluded(match.Project.Path) || match.Project.Archived || !match.Project.Member
+		if match.Remote || !hidden {
+			visible = append(visible, match)
+		}
+	}
+	return visible
+}
+
+// runSelectOne handles --select-1: a query with exactly one visible match opens it like --go
+// Returns false (without opening anything) when the TUI is needed to choose
+func runSelectOne(query string, cfg *config.Config, descIndex *index.DescriptionIndex) (bool, error) {
+	hist := loadSearchHistory(cfg)
+	matches, err := goMatches(query, hist, cfg, descIndex)
+	if err != nil {
+		return false, err
+	}
+	matches = visibleMatches(matches, cfg)
+	if len(matches) != 1 {
+		return false, nil
+	}
+	return true, openMatch(query, matches[0], hist, cfg, descIndex, backgroundSync(cfg))
+}
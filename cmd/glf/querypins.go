@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/igusev/glf/internal/cache"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/history"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+)
+
+// pinnedMatch returns the match a query is pinned to (search.pin_after): the project chosen
+// for the query pin_after times since its pin was last removed. A query is pinned the first
+// time --go finds it over the threshold, and stays pinned while the project matches it
+func pinnedMatch(query string, hist *history.History, cfg *config.Config, matches []index.CombinedMatch) (index.CombinedMatch, bool) {
+	normalized := history.NormalizeQueryText(query)
+	if cfg.Search.PinAfter <= 0 || normalized == "" || hist == nil {
+		return index.CombinedMatch{}, false
+	}
+
+	store := cache.New(cfg.Cache.GetStateDir())
+	pins, err := store.LoadQueryPins()
+	if err != nil {
+		logger.Debug("Failed to load query pins: %v", err)
+		return index.CombinedMatch{}, false
+	}
+
+	if pin, ok := pins.Pins[normalized]; ok {
+		for _, match := range matches {
+			if match.Project.Key() != pin.Key && match.Project.Path != pin.Path {
+				continue
+			}
+			if match.Project.Path != pin.Path || match.Project.Key() != pin.Key {
+				// Renamed (or re-keyed) since it was pinned
+				pin.Key, pin.Path = match.Project.Key(), match.Project.Path
+				pins.Pins[normalized] = pin
+				saveQueryPins(store, pins)
+			}
+			return match, true
+		}
+		logger.Debug("Query %q is pinned to %s, which doesn't match it anymore", query, pin.Path)
+		return index.CombinedMatch{}, false
+	}
+
+	counts := hist.QuerySelectionCounts(query, pins.Removed[normalized])
+	for _, match := range matches {
+		count := counts[match.Project.Key()]
+		if match.Project.Key() != match.Project.Path {
+			count += counts[match.Project.Path] // Selections recorded before history was keyed by ID
+		}
+		if count < cfg.Search.PinAfter {
+			continue
+		}
+		pins.Pins[normalized] = cache.QueryPin{Key: match.Project.Key(), Path: match.Project.Path, PinnedAt: time.Now()}
+		saveQueryPins(store, pins)
+		logger.Info("📌 Pinned %q to %s (chosen %d times; 'glf --unpin-query %q' removes it)", normalized, match.Project.Path, count, normalized)
+		return match, true
+	}
+	return index.CombinedMatch{}, false
+}
+
+// saveQueryPins saves the pinned queries; failing only costs the pin, so it is logged
+func saveQueryPins(store *cache.Cache, pins cache.QueryPins) {
+	if err := store.SaveQueryPins(pins); err != nil {
+		logger.Debug("Failed to save query pins: %v", err)
+	}
+}
+
+// unpinQuery removes the pin of a query (--unpin-query); the query is pinned again after
+// another pin_after selections of one project
+func unpinQuery(cfg *config.Config, query string) error {
+	normalized := history.NormalizeQueryText(query)
+	store := cache.New(cfg.Cache.GetStateDir())
+	pins, err := store.LoadQueryPins()
+	if err != nil {
+		return err
+	}
+	pin, ok := pins.Pins[normalized]
+	if !ok {
+		return fmt.Errorf("query %q is not pinned", normalized)
+	}
+
+	delete(pins.Pins, normalized)
+	pins.Removed[normalized] = time.Now()
+	if err := store.SaveQueryPins(pins); err != nil {
+		return fmt.Errorf("failed to remove query pin: %w", err)
+	}
+	fmt.Printf("Unpinned query %q (was %s)\n", normalized, pin.Path)
+	return nil
+}
+
+// listQueryPins prints the pinned queries and returns how many there are
+func listQueryPins(cfg *config.Config) (int, error) {
+	pins, err := cache.New(cfg.Cache.GetStateDir()).LoadQueryPins()
+	if err != nil {
+		return 0, err
+	}
+	queries := make([]string, 0, len(pins.Pins))
+	for query := range pins.Pins {
+		queries = append(queries, query)
+	}
+	slices.Sort(queries)
+	for _, query := range queries {
+		fmt.Printf("📌 %q → %s\n", query, pins.Pins[query].Path)
+	}
+	return len(queries), nil
+}
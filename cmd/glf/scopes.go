@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/logger"
+)
+
+// requiredTokenScope is the scope glf needs to list projects, groups, merge requests and users
+const requiredTokenScope = "read_api"
+
+// tokenScopeCheck compares the scopes of a token with what glf needs
+// missing is set when the token can't read the API (neither read_api nor api, which includes it);
+// extra lists the scopes that go beyond reading (api, write_repository, sudo, ...)
+func tokenScopeCheck(scopes []string) (missing bool, extra []string) {
+	missing = !slices.Contains(scopes, requiredTokenScope) && !slices.Contains(scopes, "api")
+	for _, scope := range scopes {
+		if !strings.HasPrefix(scope, "read_") {
+			extra = append(extra, scope)
+		}
+	}
+	return missing, extra
+}
+
+// checkTokenScopes verifies the scopes of the configured token once connected
+// A token without read_api is an error naming the scope (listing projects would otherwise fail
+// later with a bare 403); scopes beyond reading are reported through warn
+func checkTokenScopes(cfg *config.Config, info *gitlab.TokenInfo, warn func(format string, args ...interface{})) error {
+	missing, extra := tokenScopeCheck(info.Scopes)
+	if missing {
+		return withHint(withExitCode(exitCodeConfig, fmt.Errorf("GitLab token %q lacks the %s scope (it has: %s)", info.Name, requiredTokenScope, strings.Join(info.Scopes, ", "))),
+			fmt.Sprintf("create a token with %s at %s, then update it with 'glf --init'", requiredTokenScope, generateTokenURL(cfg.GitLab.URL)))
+	}
+	if len(extra) > 0 {
+		warn("GitLab token %q has more access than glf needs: %s (%s is enough; api is only used by --new and Ctrl+T starring)",
+			info.Name, strings.Join(extra, ", "), requiredTokenScope)
+	}
+	return nil
+}
+
+// fetchTokenInfo returns the details of the configured personal access token, or nil when they
+// are not available: OAuth tokens from --login, and GitLab before 15.5
+func fetchTokenInfo(cfg *config.Config, client *gitlab.Client) *gitlab.TokenInfo {
+	if cfg.GitLab.OAuth {
+		return nil // Scoped by the OAuth application, and refreshed automatically
+	}
+	info, err := client.GetTokenInfo()
+	if err != nil {
+		logger.Debug("Token checks skipped: %v", err)
+		return nil
+	}
+	return info
+}
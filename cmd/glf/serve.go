@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/igusev/glf/internal/cache"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/history"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/paths"
+)
+
+const (
+	// serveIdleTimeout is how long the index stays open without requests (--serve)
+	// Closing it lets syncs of other glf processes write to it
+	serveIdleTimeout = 10 * time.Second
+	// defaultServeLimit is the number of /search results returned without a limit parameter
+	defaultServeLimit = 50
+	// maxRecordBody limits the size of a /record request
+	maxRecordBody = 64 << 10
+)
+
+// recordRequest is the body of a /record request
+type recordRequest struct {
+	Path  string `json:"path"`  // Selected project path
+	Query string `json:"query"` // Search that found it (optional; boosts the project for that query)
+}
+
+// queryServer answers the --serve endpoints from the cache
+// The index is opened on the first request and closed after serveIdleTimeout without requests,
+// or reopened when a sync finished since it was opened
+type queryServer struct {
+	cfg *config.Config
+
+	mu        sync.Mutex
+	descIndex *index.DescriptionIndex
+	syncedAt  time.Time   // Last sync time when the index was opened
+	idle      *time.Timer // Closes the index when no requests arrive
+}
+
+// withIndex runs fn with the open index; requests are served one at a time
+func (s *queryServer) withIndex(fn func(*index.DescriptionIndex) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	syncedAt, err := cache.New(s.cfg.Cache.Dir).LoadLastSyncTime()
+	if err != nil {
+		logger.Debug("Failed to load last sync time: %v", err)
+	}
+	if s.descIndex != nil && !syncedAt.Equal(s.syncedAt) {
+		s.closeIndexLocked() // Synced since: a full sync swapped in a new index
+	}
+	if s.descIndex == nil {
+		descIndex, err := index.NewDescriptionIndex(paths.IndexPath(s.cfg.Cache.Dir))
+		if errors.Is(err, index.ErrIndexVersionMismatch) {
+			return withHint(withExitCode(exitCodeNoCache, fmt.Errorf("index schema updated, and the cache must be rebuilt")), "run 'glf --sync'")
+		}
+		if err != nil {
+			return withErrorCode(errorCodeIndex, exitCodeError, fmt.Errorf("failed to open index: %w", err))
+		}
+		s.descIndex = descIndex
+		s.syncedAt = syncedAt
+	}
+
+	if s.idle == nil {
+		s.idle = time.AfterFunc(serveIdleTimeout, s.closeIndex)
+	} else {
+		s.idle.Reset(serveIdleTimeout)
+	}
+	return fn(s.descIndex)
+}
+
+// closeIndex closes the index until the next request
+func (s *queryServer) closeIndex() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeIndexLocked()
+}
+
+// closeIndexLocked closes the index; the caller must hold s.mu
+func (s *queryServer) closeIndexLocked() {
+	if s.descIndex == nil {
+		return
+	}
+	if err := s.descIndex.Close(); err != nil {
+		logger.Debug("Failed to close index: %v", err)
+	}
+	s.descIndex = nil
+}
+
+// handler returns the HTTP handler of the query API
+func (s *queryServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", s.handleSearch)
+	mux.HandleFunc("GET /projects", s.handleProjects)
+	mux.HandleFunc("POST /record", s.handleRecord)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	return localOnly(mux)
+}
+
+// handleSearch answers /search?q=QUERY[&limit=N&offset=N&sort=KEY] with the --json result
+func (s *queryServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	opts := jsonSearchOptions{limit: defaultServeLimit, sort: sortScore, remote: true}
+	if order := params.Get("sort"); order != "" {
+		opts.sort = order
+	}
+	for name, value := range map[string]*int{"limit": &opts.limit, "offset": &opts.offset} {
+		if raw := params.Get(name); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 0 {
+				writeServeError(w, withExitCode(exitCodeUsage, fmt.Errorf("%s must be a non-negative number", name)))
+				return
+			}
+			*value = n
+		}
+	}
+	if err := validateSort(opts.sort); err != nil {
+		writeServeError(w, withExitCode(exitCodeUsage, err))
+		return
+	}
+
+	query := params.Get("q")
+	err := s.withIndex(func(descIndex *index.DescriptionIndex) error {
+		result, matches, err := searchJSON(query, opts, s.cfg, descIndex)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/json")
+		return writeJSONResult(w, result, matches, s.cfg)
+	})
+	if err != nil {
+		writeServeError(w, err)
+	}
+}
+
+// handleProjects answers /projects with every cached project, sorted by path
+func (s *queryServer) handleProjects(w http.ResponseWriter, r *http.Request) {
+	err := s.withIndex(func(descIndex *index.DescriptionIndex) error {
+		projects, err := descIndex.GetAllProjects()
+		if err != nil {
+			return withErrorCode(errorCodeIndex, exitCodeError, fmt.Errorf("failed to load projects: %w", err))
+		}
+		sort.Slice(projects, func(i, j int) bool { return projects[i].Path < projects[j].Path })
+
+		matches := make([]index.CombinedMatch, len(projects))
+		for i, p := range projects {
+			matches[i] = index.CombinedMatch{Project: p}
+		}
+		result := newJSONSearchResult("", s.cfg)
+		result.Total = len(matches)
+		w.Header().Set("Content-Type", "application/json")
+		return writeJSONResult(w, result, matches, s.cfg)
+	})
+	if err != nil {
+		writeServeError(w, err)
+	}
+}
+
+// handleRecord records a selection made in an editor plugin ({"path": ..., "query": ...})
+func (s *queryServer) handleRecord(w http.ResponseWriter, r *http.Request) {
+	var req recordRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRecordBody)).Decode(&req); err != nil || req.Path == "" {
+		writeServeError(w, withExitCode(exitCodeUsage, errors.New(`expected a JSON body {"path": "group/project", "query": "..."}`)))
+		return
+	}
+	if err := recordSelection(s.cfg, req.Path, req.Query, history.SourceServe); err != nil {
+		writeServeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeServeError writes err as a JSONError with a status matching its error code
+func writeServeError(w http.ResponseWriter, err error) {
+	jsonErr := newJSONError(err)
+	status := http.StatusInternalServerError
+	switch jsonErr.Code {
+	case errorCodeUsage:
+		status = http.StatusBadRequest
+	case errorCodeNoCache:
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(jsonErr)
+}
+
+// localOnly refuses requests made by web pages: browsers send an Origin header with
+// cross-origin requests, and a Host other than the loopback address points to DNS rebinding
+// Editor plugins send neither
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if r.Header.Get("Origin") != "" || !isLoopbackHost(host) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopbackHost reports whether host names the local machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// runServe handles --serve: answers search, record and project list requests of editor
+// plugins on addr until interrupted
+// Only the loopback interface is served: addresses without a host (":7345") listen on localhost
+func runServe(cfg *config.Config, addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return withExitCode(exitCodeUsage, fmt.Errorf("invalid --serve address %q: %w", addr, err))
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	if !isLoopbackHost(host) {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--serve only listens on the loopback interface (e.g. localhost:%s), not %s", port, host))
+	}
+	if !index.Exists(paths.IndexPath(cfg.Cache.Dir)) {
+		return withHint(withExitCode(exitCodeNoCache, errors.New("no cached projects")), "run 'glf --sync' first")
+	}
+	addr = net.JoinHostPort(host, port)
+
+	qs := &queryServer{cfg: cfg}
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           qs.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.ListenAndServe() }()
+	logger.Info("Serving the query API on http://%s (Ctrl+C to stop)", addr)
+
+	select {
+	case err = <-serveErr:
+		err = fmt.Errorf("query API failed: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil {
+			logger.Debug("Query API shutdown: %v", shutdownErr)
+		}
+	}
+	qs.closeIndex()
+	return err
+}
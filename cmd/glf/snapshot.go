@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/igusev/glf/internal/cache"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/paths"
+)
+
+// snapshotDownloadTimeout limits downloading a snapshot (--from-snapshot URL)
+const snapshotDownloadTimeout = 10 * time.Minute
+
+// runSyncFromSnapshot handles --sync --from-snapshot: seeds the cache from a team snapshot,
+// then runs the incremental sync catching up with the changes since the snapshot was taken
+// Starred and member flags of the snapshot belong to whoever built it, so this user's are
+// applied afterwards
+func runSyncFromSnapshot(cfg *config.Config, source string) error {
+	if forceFull || dryRun || starredOnly {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--from-snapshot cannot be used with --full, --dry-run or --starred"))
+	}
+
+	if err := seedFromSnapshot(cfg, source); err != nil {
+		return withExitCode(exitCodeSyncFailed, err)
+	}
+
+	var err error
+	if jsonOutput {
+		err = runSyncJSON(cfg)
+	} else if syncErr := performSyncInternal(cfg, ciMode, false); syncErr != nil {
+		err = withExitCode(exitCodeSyncFailed, syncErr)
+	}
+
+	if updated, flagErr := applyProjectSets(cfg.Cache.Dir); flagErr != nil {
+		logger.Warn("Failed to apply your starred and member projects: %v (the next full sync fixes them)", flagErr)
+	} else if updated > 0 {
+		logger.Debug("Applied starred and member flags to %d projects", updated)
+	}
+	return err
+}
+
+// seedFromSnapshot replaces the cache with the snapshot at source (a URL or a file)
+// The snapshot is extracted next to the cache and its index checked before anything is replaced
+func seedFromSnapshot(cfg *config.Config, source string) error {
+	r, err := openSnapshot(cfg, source)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	cacheManager := cache.New(cfg.Cache.Dir)
+	if err := cacheManager.EnsureDir(); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	staging, err := os.MkdirTemp(cfg.Cache.Dir, ".snapshot-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	logger.Info("Extracting snapshot %s...", source)
+	if err := cache.ExtractSnapshot(r, staging); err != nil {
+		return fmt.Errorf("failed to extract snapshot: %w", err)
+	}
+
+	count, err := checkSnapshotIndex(staging)
+	if err != nil {
+		return err
+	}
+
+	if _, err := cacheManager.InstallSnapshot(staging); err != nil {
+		return fmt.Errorf("failed to install snapshot: %w", err)
+	}
+
+	if lastSync, err := cacheManager.LoadLastSyncTime(); err == nil && !lastSync.IsZero() {
+		logger.Success("Seeded the cache with %d projects (snapshot synced %s)", count, lastSync.Local().Format("2006-01-02 15:04"))
+	} else {
+		logger.Success("Seeded the cache with %d projects (the snapshot has no sync time: a full sync follows)", count)
+	}
+	return nil
+}
+
+// checkSnapshotIndex verifies that the extracted snapshot has an index this glf can read
+// Returns the number of projects in it
+func checkSnapshotIndex(staging string) (int, error) {
+	indexPath := paths.IndexPath(staging)
+	if !index.Exists(indexPath) {
+		return 0, fmt.Errorf("snapshot has no %s", filepath.Base(indexPath))
+	}
+	descIndex, err := index.NewDescriptionIndex(indexPath)
+	if errors.Is(err, index.ErrIndexVersionMismatch) {
+		return 0, withHint(fmt.Errorf("snapshot index was built by a different glf version: %w", err),
+			"rebuild the snapshot with this glf version, or run 'glf --sync' without it")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open snapshot index: %w", err)
+	}
+	defer descIndex.Close()
+
+	projects, err := descIndex.GetAllProjects()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot index: %w", err)
+	}
+	if len(projects) == 0 {
+		return 0, fmt.Errorf("snapshot index has no projects")
+	}
+	return len(projects), nil
+}
+
+// openSnapshot opens a snapshot file, or downloads it when source is an http(s) URL
+// Downloads from the configured GitLab instance carry the token (e.g. job artifacts, packages)
+func openSnapshot(cfg *config.Config, source string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		f, err := os.Open(filepath.Clean(paths.ExpandHome(source)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot: %w", err)
+		}
+		return f, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot URL: %w", err)
+	}
+	if sameHost(source, cfg.GitLab.URL) && cfg.GitLab.Token != "" {
+		if cfg.GitLab.OAuth {
+			req.Header.Set("Authorization", "Bearer "+cfg.GitLab.Token)
+		} else {
+			req.Header.Set("PRIVATE-TOKEN", cfg.GitLab.Token)
+		}
+	}
+
+	logger.Info("Downloading snapshot %s...", source)
+	client := &http.Client{Timeout: snapshotDownloadTimeout, CheckRedirect: dropTokenOffHost}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download snapshot: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download snapshot: %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// dropTokenOffHost removes the token from redirects to another host: GitLab redirects artifact
+// downloads to object storage, and the client only drops Authorization there, not PRIVATE-TOKEN
+func dropTokenOffHost(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		req.Header.Del("PRIVATE-TOKEN")
+		req.Header.Del("Authorization")
+	}
+	return nil
+}
+
+// sameHost reports whether two URLs point to the same host
+func sameHost(a, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	return errA == nil && errB == nil && ua.Host != "" && strings.EqualFold(ua.Host, ub.Host)
+}
+
+// applyProjectSets sets the starred and member flags of every indexed project from the sets
+// cached by the last sync, and returns the number of projects whose flags changed
+// Incremental syncs only flag the projects they fetch; this covers the rest after a snapshot
+func applyProjectSets(cacheDir string) (int, error) {
+	starred, member, err := cache.New(cacheDir).LoadProjectSets()
+	if err != nil {
+		return 0, err
+	}
+	if starred == nil && member == nil {
+		return 0, fmt.Errorf("no starred and member projects cached by the sync")
+	}
+
+	descIndex, err := index.NewDescriptionIndex(paths.IndexPath(cacheDir))
+	if err != nil {
+		return 0, err
+	}
+	defer descIndex.Close()
+
+	projects, err := descIndex.GetAllProjects()
+	if err != nil {
+		return 0, err
+	}
+	var docs []index.DescriptionDocument
+	for _, p := range projects {
+		if p.Starred == starred[p.Path] && p.Member == member[p.Path] {
+			continue
+		}
+		p.Starred = starred[p.Path]
+		p.Member = member[p.Path]
+		docs = append(docs, index.NewDocument(p))
+	}
+	if len(docs) == 0 {
+		return 0, nil
+	}
+	return len(docs), descIndex.AddBatch(docs)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/igusev/glf/internal/index"
+)
+
+// Orders of JSON results (--sort)
+const (
+	sortScore    = "score"    // Relevance, as ranked by the search (default)
+	sortPath     = "path"     // Project path, alphabetically
+	sortName     = "name"     // Project name, alphabetically (ties by path)
+	sortActivity = "activity" // Last activity, most recent first (projects without it last)
+)
+
+// sortOrders lists the valid --sort values
+var sortOrders = []string{sortScore, sortPath, sortName, sortActivity}
+
+// validateSort checks a --sort value
+func validateSort(order string) error {
+	if slices.Contains(sortOrders, order) {
+		return nil
+	}
+	return fmt.Errorf("invalid --sort %q (expected %s)", order, strings.Join(sortOrders, ", "))
+}
+
+// sortMatches reorders search results by order; relevance order is kept among equal keys
+func sortMatches(matches []index.CombinedMatch, order string) {
+	var less func(a, b index.CombinedMatch) bool
+	switch order {
+	case sortPath:
+		less = func(a, b index.CombinedMatch) bool {
+			return strings.ToLower(a.Project.Path) < strings.ToLower(b.Project.Path)
+		}
+	case sortName:
+		less = func(a, b index.CombinedMatch) bool {
+			nameA, nameB := strings.ToLower(a.Project.Name), strings.ToLower(b.Project.Name)
+			if nameA != nameB {
+				return nameA < nameB
+			}
+			return strings.ToLower(a.Project.Path) < strings.ToLower(b.Project.Path)
+		}
+	case sortActivity:
+		less = func(a, b index.CombinedMatch) bool {
+			// Unknown activity (not synced yet) sorts after any known time
+			if a.Project.LastActivityAt.IsZero() || b.Project.LastActivityAt.IsZero() {
+				return !a.Project.LastActivityAt.IsZero() && b.Project.LastActivityAt.IsZero()
+			}
+			return a.Project.LastActivityAt.After(b.Project.LastActivityAt)
+		}
+	default:
+		return
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return less(matches[i], matches[j])
+	})
+}
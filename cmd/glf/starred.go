@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/igusev/glf/internal/cache"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/paths"
+)
+
+// runSyncStarred handles --sync --starred: refreshes the starred and member projects only
+// Prints a JSONSyncResult with --json; failures exit with exitCodeSyncFailed
+func runSyncStarred(cfg *config.Config) error {
+	logInfo := logger.Info
+	if jsonOutput {
+		logInfo = logger.Debug
+	}
+
+	logInfo("Connecting to GitLab at %s (timeout: %ds)...", cfg.GitLab.URL, cfg.GitLab.Timeout)
+	client, err := newSyncClient(cfg)
+	if err != nil {
+		return withExitCode(exitCodeSyncFailed, fmt.Errorf("GitLab client error: %w", err))
+	}
+
+	result, err := syncStarred(cfg, client, jsonOutput)
+	if err == nil {
+		saveETagCache(client)
+	}
+	if jsonOutput {
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		if encErr := outputJSON(result); encErr != nil {
+			return encErr
+		}
+		if err != nil {
+			return withExitCode(exitCodeSyncFailed, nil)
+		}
+		return nil
+	}
+	if err != nil {
+		return withExitCode(exitCodeSyncFailed, err)
+	}
+	return nil
+}
+
+// syncStarred fetches the starred and member projects and updates just their documents in the index
+// Projects unstarred or left since the last sync keep their document with the flag cleared;
+// nothing is removed and the sync timestamps are left alone, so the next regular sync is unaffected
+func syncStarred(cfg *config.Config, client *gitlab.Client, silent bool) (*JSONSyncResult, error) {
+	result := &JSONSyncResult{Mode: syncModeStarred, Errors: []string{}}
+	start := time.Now()
+	defer func() { result.DurationMs = time.Since(start).Milliseconds() }()
+
+	logInfo := logger.Info
+	logSuccess := logger.Success
+	if silent {
+		logInfo = logger.Debug
+		logSuccess = logger.Debug
+	}
+
+	// Without a complete index there is nothing to refresh: the few projects fetched here
+	// would make up the whole index
+	indexPath := paths.IndexPath(cfg.Cache.Dir)
+	if !index.Exists(indexPath) {
+		return result, errors.New("no cached projects; run 'glf --sync' first")
+	}
+	descIndex, recreated, err := index.NewDescriptionIndexWithAutoRecreate(indexPath)
+	if err != nil {
+		return result, fmt.Errorf("failed to open index: %w", err)
+	}
+	defer func() {
+		if err := descIndex.Close(); err != nil {
+			logger.Debug("Failed to close index: %v", err)
+		}
+	}()
+	if recreated {
+		return result, errors.New("index schema updated and the cache must be rebuilt; run 'glf --sync' first")
+	}
+
+	logInfo("Fetching starred and member projects...")
+	projects, err := client.FetchStarredAndMemberProjects()
+	if err != nil {
+		logger.Error("Failed to fetch projects")
+		return result, fmt.Errorf("fetch error: %w", err)
+	}
+	result.ProjectsFetched = len(projects)
+	starred, member := client.LastProjectSets()
+
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, project := range projects {
+		existing, found, err := descIndex.GetProject(project.Path)
+		if err != nil {
+			logger.Debug("Failed to look up %s: %v", project.Path, err)
+		}
+		if found {
+			// Open MR/issue counts are only fetched by regular syncs (gitlab.insights)
+			project.OpenMRs = existing.OpenMRs
+			project.OpenIssues = existing.OpenIssues
+			project.FormerPaths = existing.FormerPaths // Keep renamed projects findable by their former paths
+		}
+		if !found || existing.Starred != project.Starred || existing.Member != project.Member {
+			result.Changed++
+		}
+		docs = append(docs, index.NewDocument(project))
+	}
+
+	// Indexed projects that dropped out of both sets: only their flags change
+	indexed, err := descIndex.GetAllProjects()
+	if err != nil {
+		return result, fmt.Errorf("failed to list indexed projects: %w", err)
+	}
+	for _, existing := range indexed {
+		if (!existing.Starred && !existing.Member) || starred[existing.Path] || member[existing.Path] {
+			continue
+		}
+		existing.Starred, existing.Member = false, false
+		result.Changed++
+		docs = append(docs, index.NewDocument(existing))
+	}
+
+	if err := descIndex.AddBatch(docs); err != nil {
+		return result, fmt.Errorf("failed to index projects: %w", err)
+	}
+	result.Indexed = len(docs)
+	if err := descIndex.SaveSnapshot(); err != nil {
+		logger.Debug("Failed to save index snapshot: %v", err)
+	}
+	if err := cache.New(cfg.Cache.Dir).SaveProjectSets(starred, member); err != nil {
+		logger.Debug("Failed to save project sets cache: %v", err)
+	}
+
+	logSuccess("Refreshed %d starred and member projects in %v (%d changed)", len(projects), time.Since(start).Round(time.Millisecond), result.Changed)
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/igusev/glf/internal/cache"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/paths"
+)
+
+// JSONStatus represents the --status report in JSON mode
+type JSONStatus struct {
+	CacheDir     string     `json:"cache_dir"`
+	StateDir     string     `json:"state_dir"` // History and session location (cache.state_dir, default cache_dir)
+	ReadOnly     bool       `json:"read_only"` // Shared cache that glf never syncs into
+	Projects     int        `json:"projects"`
+	IndexVersion int        `json:"index_version"`
+	IndexBytes   int64      `json:"index_bytes"`
+	HistoryBytes int64      `json:"history_bytes"`
+	TotalBytes   int64      `json:"total_bytes"`
+	LastSync     *time.Time `json:"last_sync,omitempty"`      // Omitted if never synced
+	LastFullSync *time.Time `json:"last_full_sync,omitempty"` // Omitted if never fully synced
+}
+
+// runStatus prints cache freshness and on-disk size
+func runStatus(cfg *config.Config) error {
+	cacheManager := cache.New(cfg.Cache.Dir)
+	indexPath := paths.IndexPath(cfg.Cache.Dir)
+
+	status := JSONStatus{
+		CacheDir:     cfg.Cache.Dir,
+		StateDir:     cfg.Cache.GetStateDir(),
+		ReadOnly:     cfg.Cache.IsReadOnly(),
+		IndexVersion: index.IndexVersion,
+		IndexBytes:   dirSize(indexPath),
+		HistoryBytes: dirSize(paths.HistoryPath(cfg.Cache.GetStateDir())),
+		TotalBytes:   dirSize(cfg.Cache.Dir),
+	}
+
+	if lastSync, err := cacheManager.LoadLastSyncTime(); err == nil && !lastSync.IsZero() {
+		status.LastSync = &lastSync
+	}
+	if lastFullSync, err := cacheManager.LoadLastFullSyncTime(); err == nil && !lastFullSync.IsZero() {
+		status.LastFullSync = &lastFullSync
+	}
+
+	// Opening an index with an old schema would recreate it - only count a current one
+	if index.Exists(indexPath) {
+		descIndex, err := index.NewDescriptionIndex(indexPath)
+		if err != nil {
+			logger.Debug("Failed to open index for status: %v", err)
+		} else {
+			if count, countErr := descIndex.Count(); countErr == nil && count > 0 {
+				status.Projects = int(count) - 1 // Exclude the version document
+			}
+			if err := descIndex.Close(); err != nil {
+				logger.Debug("Failed to close index: %v", err)
+			}
+		}
+	}
+
+	if jsonOutput {
+		return outputJSON(status)
+	}
+
+	fmt.Println("Cache Status")
+	fmt.Println()
+	if status.ReadOnly {
+		fmt.Printf("  Cache dir:       %s (read-only)\n", status.CacheDir)
+	} else {
+		fmt.Printf("  Cache dir:       %s\n", status.CacheDir)
+	}
+	if status.StateDir != status.CacheDir {
+		fmt.Printf("  State dir:       %s\n", status.StateDir)
+	}
+	fmt.Printf("  Projects:        %d\n", status.Projects)
+	fmt.Printf("  Last sync:       %s\n", formatSyncTime(status.LastSync))
+	fmt.Printf("  Last full sync:  %s\n", formatSyncTime(status.LastFullSync))
+	fmt.Println()
+	fmt.Printf("  Index:           %s (schema v%d)\n", formatBytes(status.IndexBytes), status.IndexVersion)
+	fmt.Printf("  History:         %s\n", formatBytes(status.HistoryBytes))
+	fmt.Printf("  Total on disk:   %s\n", formatBytes(status.TotalBytes))
+
+	return nil
+}
+
+// dirSize returns the total size of a file or directory tree (0 if it doesn't exist)
+func dirSize(path string) int64 {
+	var total int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if !d.IsDir() {
+			if info, infoErr := d.Info(); infoErr == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		logger.Debug("Failed to measure %s: %v", path, err)
+	}
+	return total
+}
+
+// formatBytes formats a byte count with a binary unit (e.g., "12.3 MB")
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// formatSyncTime formats a sync timestamp with its age ("never" for nil)
+func formatSyncTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return fmt.Sprintf("%s (%s ago)", t.Format("2006-01-02 15:04"), time.Since(*t).Round(time.Minute))
+}
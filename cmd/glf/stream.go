@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/search"
+)
+
+// streamBufferSize is the write buffer of streamed JSON results
+const streamBufferSize = 64 << 10
+
+// resultsPlaceholder is the empty results array of an indented JSONSearchResult,
+// replaced by the streamed results
+const resultsPlaceholder = "\n  \"results\": []"
+
+// newJSONProject converts a search result to its JSON representation
+func newJSONProject(match index.CombinedMatch, cfg *config.Config, gitlabURL string) JSONProject {
+	project := JSONProject{
+		Path:        match.Project.Path,
+		Name:        match.Project.Name,
+		Description: match.Project.Description,
+		URL:         projectRootURL(gitlabURL, match.Project.Path),
+		Starred:     match.Project.Starred,
+		Excluded:    cfg != nil && cfg.IsExcluded(match.Project.Path),
+		Archived:    match.Project.Archived,
+		Member:      match.Project.Member,
+		Topics:      match.Project.Topics,
+		OpenMRs:     match.Project.OpenMRs,
+		OpenIssues:  match.Project.OpenIssues,
+		Remote:      match.Remote,
+		Pinned:      match.Pinned,
+
+		DefaultBranch: match.Project.DefaultBranch,
+		Visibility:    match.Project.Visibility,
+		ForkedFrom:    match.Project.ForkedFrom,
+		FormerPaths:   match.Project.FormerPaths,
+		Labels:        search.Labels(match.Project.Path),
+		Score:         match.TotalScore,
+	}
+	for _, fork := range match.Forks {
+		project.Forks = append(project.Forks, fork.Project.Path)
+	}
+	if !match.Project.LastActivityAt.IsZero() {
+		lastActivityAt := match.Project.LastActivityAt
+		project.LastActivityAt = &lastActivityAt
+	}
+	return project
+}
+
+// writeJSONResult writes result with matches as its results, in the same document outputJSON
+// produces. Results are encoded one at a time into a buffered writer, so listing the whole
+// cache (--all) never holds every converted project or the full document in memory
+func writeJSONResult(w io.Writer, result JSONSearchResult, matches []index.CombinedMatch, cfg *config.Config) error {
+	result.Results = []JSONProject{}
+	envelope, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	head, tail, found := bytes.Cut(envelope, []byte(resultsPlaceholder))
+	if !found {
+		return fmt.Errorf("failed to encode JSON: results field not found")
+	}
+
+	out := bufio.NewWriterSize(w, streamBufferSize)
+	_, _ = out.Write(head)
+	_, _ = out.WriteString(strings.TrimSuffix(resultsPlaceholder, "]"))
+	for i, match := range matches {
+		encoded, err := json.MarshalIndent(newJSONProject(match, cfg, result.Instance), "    ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		if i > 0 {
+			_ = out.WriteByte(',')
+		}
+		_, _ = out.WriteString("\n    ")
+		_, _ = out.Write(encoded)
+	}
+	if len(matches) > 0 {
+		_, _ = out.WriteString("\n  ")
+	}
+	_ = out.WriteByte(']')
+	_, _ = out.Write(tail)
+	_ = out.WriteByte('\n')
+	return out.Flush()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/update"
+)
+
+// updateTimeout bounds the release check and each download
+const updateTimeout = 2 * time.Minute
+
+// runUpdate handles --update: replace the running binary with the latest GitHub release
+func runUpdate() error {
+	if offline {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--update cannot be used with --offline"))
+	}
+	if version == "dev" {
+		return update.ErrDevBuild
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+		exePath = resolved
+	}
+	// Package managers own their files: replacing them would break the next upgrade
+	if hint := packageManagerUpgrade(exePath); hint != "" {
+		return fmt.Errorf("glf was installed with a package manager; run '%s' instead", hint)
+	}
+
+	updater := update.New(updateTimeout)
+	logger.Info("Checking for updates...")
+	release, err := updater.Latest()
+	if err != nil {
+		return err
+	}
+
+	if !update.IsNewer(version, release.Version) {
+		logger.Success("glf %s is up to date", version)
+		return nil
+	}
+
+	logger.Info("Updating glf %s → %s (%s)...", version, release.Version, updater.ArchiveName(release.Version))
+	if err := updater.Install(release, exePath); err != nil {
+		return err
+	}
+
+	logger.Success("Updated to glf %s (%s)", release.Version, exePath)
+	return nil
+}
+
+// packageManagerUpgrade returns the upgrade command if exePath is managed by Homebrew or Scoop
+func packageManagerUpgrade(exePath string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(exePath, `\`, "/"))
+	switch {
+	case strings.Contains(normalized, "/cellar/"):
+		return "brew upgrade glf"
+	case strings.Contains(normalized, "/scoop/apps/"):
+		return "scoop update glf"
+	}
+	return ""
+}
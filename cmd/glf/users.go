@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/cache"
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/gitlab"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/search"
+	"github.com/igusev/glf/internal/target"
+	"github.com/igusev/glf/internal/tui"
+)
+
+type (
+	// JSONUsersResult represents the --users response in JSON mode
+	JSONUsersResult struct {
+		Query     string     `json:"query"`      // Search query that was executed
+		Users     []JSONUser `json:"users"`      // Matching users
+		Total     int        `json:"total"`      // Number of results
+		FetchedAt time.Time  `json:"fetched_at"` // When the users were fetched from GitLab
+	}
+
+	// JSONUser represents a single user in JSON output
+	JSONUser struct {
+		Username string `json:"username"` // Login name
+		Name     string `json:"name"`     // Display name
+		URL      string `json:"url"`      // Profile URL (or the assigned MRs/issues page with --target)
+	}
+)
+
+// errUsersDisabled is returned by --users when user sync is not enabled in the config
+var errUsersDisabled = errors.New("user search is disabled; set gitlab.sync_users: true in the config and run 'glf --sync'")
+
+// syncUsers refreshes the cached users of the instance; returns the number of users
+func syncUsers(cacheDir string, client *gitlab.Client) (int, error) {
+	users, err := client.FetchAllUsers()
+	if err != nil {
+		return 0, err
+	}
+	if err := cache.New(cacheDir).SaveUsers(users, time.Now()); err != nil {
+		return 0, fmt.Errorf("failed to cache users: %w", err)
+	}
+	return len(users), nil
+}
+
+// runUsers handles --users: fuzzy search over the instance's users, opening a profile
+// (or with --target mrs/issues, the merge requests/issues assigned to the user)
+func runUsers(cfg *config.Config, query string) error {
+	if !cfg.GitLab.SyncUsers {
+		return withExitCode(exitCodeUsage, errUsersDisabled)
+	}
+	if doSync && offline {
+		return withExitCode(exitCodeUsage, fmt.Errorf("--sync cannot be used with --offline"))
+	}
+	// Validate --target before fetching anything
+	if _, err := target.UserURL(cfg.GitLab.URL, "", targetName); err != nil {
+		return withExitCode(exitCodeUsage, err)
+	}
+
+	users, fetchedAt, err := loadUsers(cfg)
+	if err != nil {
+		return err
+	}
+
+	matches := search.FilterUsers(users, query)
+	if limitResults > 0 && len(matches) > limitResults {
+		matches = matches[:limitResults]
+	}
+
+	switch {
+	case jsonOutput:
+		result := JSONUsersResult{
+			Query:     query,
+			Users:     make([]JSONUser, 0, len(matches)),
+			Total:     len(matches),
+			FetchedAt: fetchedAt,
+		}
+		for _, user := range matches {
+			userURL, _ := target.UserURL(cfg.GitLab.URL, user.Username, targetName) // Validated above
+			result.Users = append(result.Users, JSONUser{
+				Username: user.Username,
+				Name:     user.Name,
+				URL:      userURL,
+			})
+		}
+		if err := outputJSON(result); err != nil {
+			return err
+		}
+		if ciMode && len(matches) == 0 {
+			return withExitCode(exitCodeNoResults, nil)
+		}
+		return nil
+
+	case autoGo:
+		if len(matches) == 0 {
+			return withExitCode(exitCodeNoResults, fmt.Errorf("no users found matching '%s'", query))
+		}
+		return openUser(cfg, matches[0].Username, targetName)
+
+	case nonInteractive:
+		for _, user := range matches {
+			userURL, _ := target.UserURL(cfg.GitLab.URL, user.Username, targetName)
+			fmt.Printf("%s\t%s\n", user.DisplayString(), userURL)
+		}
+		return nil
+	}
+
+	finalModel, err := tea.NewProgram(tui.NewUsers(users, query, version), tea.WithAltScreen()).Run()
+	if err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+	if m, ok := finalModel.(tui.UsersModel); ok && m.Selected() != "" {
+		page := targetName
+		if m.SelectedTarget() != "" {
+			page = m.SelectedTarget()
+		}
+		return openUser(cfg, m.Selected(), page)
+	}
+	return nil
+}
+
+// loadUsers returns the cached users, fetching them first if none are cached
+// or --sync is given (users are otherwise refreshed by every sync)
+func loadUsers(cfg *config.Config) ([]model.User, time.Time, error) {
+	cacheManager := cache.New(cfg.Cache.Dir)
+	users, fetchedAt, err := cacheManager.LoadUsers()
+	if err != nil {
+		logger.Debug("Failed to load cached users: %v", err)
+	}
+	if doSync && readOnlyCache {
+		return nil, fetchedAt, errReadOnlyCache(cfg.Cache.Dir)
+	}
+	if !doSync && (!fetchedAt.IsZero() || autoSyncDisabled()) {
+		if fetchedAt.IsZero() {
+			return nil, fetchedAt, withExitCode(exitCodeNoCache, errors.New("no cached users; run 'glf --users --sync' first"))
+		}
+		logger.Debug("Using %d cached users (fetched %v ago)", len(users), time.Since(fetchedAt).Round(time.Second))
+		return users, fetchedAt, nil
+	}
+
+	client, err := gitlab.New(cfg.GitLab.URL, cfg.GitLab.Token, cfg.GitLab.GetTimeout())
+	if err != nil {
+		return nil, fetchedAt, withExitCode(exitCodeSyncFailed, fmt.Errorf("GitLab client error: %w", err))
+	}
+	if !jsonOutput {
+		logger.Info("Fetching users...")
+	}
+	if _, err := syncUsers(cfg.Cache.Dir, client); err != nil {
+		return nil, fetchedAt, withExitCode(exitCodeSyncFailed, err)
+	}
+	return cacheManager.LoadUsers()
+}
+
+// openUser opens a user's page in the browser and prints its URL
+func openUser(cfg *config.Config, username, page string) error {
+	userURL, err := target.UserURL(cfg.GitLab.URL, username, page)
+	if err != nil {
+		return withExitCode(exitCodeUsage, err)
+	}
+	logger.Debug("Opening browser with URL: %s", userURL)
+	if err := openBrowser(userURL); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to open browser: %v\n", err)
+		logger.Debug("Browser open error: %v", err)
+	}
+	fmt.Println(userURL)
+	return nil
+}
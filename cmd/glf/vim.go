@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/search"
+)
+
+// outputVim writes one "path<TAB>score<TAB>ranges" line per match for editor plugins (--vim)
+// ranges lists the parts of the path the TUI highlights for the query, as comma-separated
+// "start-end" byte offsets (0-based, end exclusive); it is empty when the path didn't match
+func outputVim(w io.Writer, query string, matches []index.CombinedMatch) error {
+	terms := search.HighlightTerms(query)
+	out := bufio.NewWriter(w)
+	for _, match := range matches {
+		path := match.Project.Path
+		_, _ = out.WriteString(path)
+		_ = out.WriteByte('\t')
+		_, _ = out.WriteString(strconv.FormatFloat(match.TotalScore, 'f', 3, 64))
+		_ = out.WriteByte('\t')
+		for i, r := range search.MatchRanges(path, terms) {
+			if i > 0 {
+				_ = out.WriteByte(',')
+			}
+			_, _ = out.WriteString(strconv.Itoa(r.Start) + "-" + strconv.Itoa(r.End))
+		}
+		_ = out.WriteByte('\n')
+	}
+	return out.Flush()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/history"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/paths"
+	"github.com/igusev/glf/internal/search"
+	"github.com/igusev/glf/internal/workspace"
+)
+
+// errNoWorkspace is returned by --edit, --cd and Alt+E when workspace_dir is not configured
+var errNoWorkspace = errors.New("no workspace configured; set workspace_dir in the config (e.g. workspace_dir: ~/src)")
+
+// shellFunctions holds the 'gcd' helper printed by --shell-init, per shell
+// The TUI draws on stderr with --cd, so 'gcd' without a query is interactive
+var shellFunctions = map[string]string{
+	"bash": `gcd() {
+  local dir
+  dir="$(command glf --cd "$@")" && [ -n "$dir" ] && cd -- "$dir"
+}
+`,
+	"zsh": `gcd() {
+  local dir
+  dir="$(command glf --cd "$@")" && [[ -n "$dir" ]] && cd -- "$dir"
+}
+`,
+	"fish": `function gcd
+    set -l dir (command glf --cd $argv)
+    and test -n "$dir"
+    and cd -- $dir
+end
+`,
+}
+
+// newWorkspace returns the local clone mapping for workspace_dir
+// Clones outside the layout are matched by their origin remote on the configured GitLab
+func newWorkspace(cfg *config.Config) (*workspace.Workspace, error) {
+	if cfg.WorkspaceDir == "" {
+		return nil, withExitCode(exitCodeUsage, errNoWorkspace)
+	}
+	layout, err := workspace.ParseLayout(cfg.WorkspaceLayout)
+	if err != nil {
+		return nil, withExitCode(exitCodeUsage, fmt.Errorf("workspace_layout: %w", err))
+	}
+
+	gitlabURL := strings.TrimSuffix(cfg.GitLab.URL, "/")
+	resolve := func(remoteURL string) (string, bool) {
+		projectPath, baseURL, err := extractProjectPath(remoteURL, gitlabURL)
+		return projectPath, err == nil && baseURL == gitlabURL
+	}
+	return workspace.New(cfg.WorkspaceDir, layout, resolve), nil
+}
+
+// firstMatch returns the path of the best match for query and records it in history
+// (--edit and --cd act on the first result like --go)
+func firstMatch(query string, cfg *config.Config, descIndex *index.DescriptionIndex) (string, error) {
+	hist := history.New(paths.HistoryPath(cfg.Cache.GetStateDir()))
+	if err := <-hist.LoadAsync(); err != nil {
+		logger.Debug("Failed to load history: %v", err)
+	}
+
+	matches, err := searchIndex(query, hist.GetAllScoresForQuery(query), cfg, descIndex)
+	if err != nil {
+		return "", fmt.Errorf("search failed: %w", err)
+	}
+	if len(matches) == 0 {
+		return "", withExitCode(exitCodeNoResults, errNoProjects(query, descIndex))
+	}
+	matches = collapseForks(search.ApplyPins(matches, cfg.PinnedPaths, nil), cfg) // Pins among the matches, like --go
+	projectPath := matches[0].Project.Path
+
+	hist.SetSource(history.SourceClone)
+	hist.RecordSelectionWithQuery(query, matches[0].Project.Key())
+	if err := hist.Save(); err != nil {
+		logger.Debug("Failed to save history: %v", err)
+	}
+	return projectPath, nil
+}
+
+// runCd handles --cd with a query: prints the local clone of the best match
+func runCd(query string, cfg *config.Config, descIndex *index.DescriptionIndex) error {
+	ws, err := newWorkspace(cfg)
+	if err != nil {
+		return err
+	}
+	projectPath, err := firstMatch(query, cfg, descIndex)
+	if err != nil {
+		return err
+	}
+	return printClonePath(ws, projectPath)
+}
+
+// printClonePath prints the local clone of a project, failing if it is not cloned
+func printClonePath(ws *workspace.Workspace, projectPath string) error {
+	dir, ok := ws.Find(projectPath)
+	if !ok {
+		return withExitCode(exitCodeNoResults, fmt.Errorf("%s is not cloned (expected at %s); 'glf --edit' can clone it", projectPath, ws.Path(projectPath)))
+	}
+	fmt.Println(dir)
+	return nil
+}
+
+// runShellInit handles --shell-init: prints the 'gcd' shell function
+func runShellInit(shell string) error {
+	function, ok := shellFunctions[shell]
+	if !ok {
+		return withExitCode(exitCodeUsage, fmt.Errorf("unsupported shell %q (expected bash, zsh or fish)", shell))
+	}
+	fmt.Print(function)
+	return nil
+}
@@ -0,0 +1,109 @@
+// Package annotations loads team-shared project labels from annotations.yaml
+//
+// The file maps project or group paths to labels:
+//
+//	platform/api: [tier-1, owned-by:payments]
+//	legacy: [deprecated]        # a group path labels every project below it
+//
+// Labels are shown in the TUI and JSON output and filtered with label: queries.
+package annotations
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Annotations maps project and group paths to their labels
+type Annotations map[string][]string
+
+// Parse reads annotations from YAML data
+// Paths are normalized (no leading or trailing slash), empty labels are dropped
+func Parse(data []byte) (Annotations, error) {
+	var raw map[string][]string
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid annotations: %w", err)
+	}
+	a := make(Annotations, len(raw))
+	for path, labels := range raw {
+		a.add(path, labels)
+	}
+	return a, nil
+}
+
+// Load reads and merges the annotation files at paths; later files add labels to earlier ones
+// Missing files and empty paths are skipped, so an unconfigured setup yields an empty map
+func Load(paths ...string) (Annotations, error) {
+	a := make(Annotations)
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Clean(path))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return a, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		parsed, err := Parse(data)
+		if err != nil {
+			return a, fmt.Errorf("%s: %w", path, err)
+		}
+		for p, labels := range parsed {
+			a.add(p, labels)
+		}
+	}
+	return a, nil
+}
+
+// add appends the labels of path, skipping empty and duplicate ones
+func (a Annotations) add(path string, labels []string) {
+	path = strings.Trim(strings.TrimSpace(path), "/")
+	if path == "" {
+		return
+	}
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label != "" && !contains(a[path], label) {
+			a[path] = append(a[path], label)
+		}
+	}
+}
+
+// Labels returns the labels of a project: those of its groups (outermost first), then its own
+// Returns nil for projects without labels
+func (a Annotations) Labels(projectPath string) []string {
+	if len(a) == 0 {
+		return nil
+	}
+	var labels []string
+	segments := strings.Split(strings.Trim(projectPath, "/"), "/")
+	for i := range segments {
+		for _, label := range a[strings.Join(segments[:i+1], "/")] {
+			if !contains(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+	}
+	return labels
+}
+
+// HasLabel reports whether a project has the label (case-insensitive)
+func (a Annotations) HasLabel(projectPath, label string) bool {
+	return contains(a.Labels(projectPath), label)
+}
+
+// contains reports whether labels contains label (case-insensitive)
+func contains(labels []string, label string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
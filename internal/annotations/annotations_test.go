@@ -0,0 +1,84 @@
+package annotations
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	a, err := Parse([]byte(`
+/platform/api/: [tier-1, owned-by:payments, tier-1, ""]
+legacy: [deprecated]
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	want := Annotations{
+		"platform/api": {"tier-1", "owned-by:payments"},
+		"legacy":       {"deprecated"},
+	}
+	if !reflect.DeepEqual(a, want) {
+		t.Errorf("Parse() = %v, want %v", a, want)
+	}
+
+	if _, err := Parse([]byte("platform/api: tier-1: x")); err == nil {
+		t.Error("Expected an error for invalid YAML")
+	}
+}
+
+func TestLabels(t *testing.T) {
+	a := Annotations{
+		"platform":     {"owned-by:platform"},
+		"platform/api": {"tier-1", "owned-by:platform"},
+		"legacy":       {"deprecated"},
+	}
+
+	tests := map[string][]string{
+		"platform/api":         {"owned-by:platform", "tier-1"},
+		"platform/infra/proxy": {"owned-by:platform"},
+		"legacy/billing":       {"deprecated"},
+		"legacy-tools/cli":     nil, // Not below the legacy group
+		"other/app":            nil,
+	}
+	for path, want := range tests {
+		if got := a.Labels(path); !reflect.DeepEqual(got, want) {
+			t.Errorf("Labels(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	if !a.HasLabel("platform/api", "TIER-1") {
+		t.Error("Expected HasLabel to ignore case")
+	}
+	if a.HasLabel("platform/infra/proxy", "tier-1") {
+		t.Error("Expected tier-1 to apply to platform/api only")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "shared.yaml")
+	local := filepath.Join(dir, "local.yaml")
+	if err := os.WriteFile(shared, []byte("platform/api: [tier-1]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(local, []byte("platform/api: [mine]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := Load(shared, filepath.Join(dir, "missing.yaml"), "", local)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got, want := a.Labels("platform/api"), []string{"tier-1", "mine"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Labels() = %v, want %v", got, want)
+	}
+
+	if err := os.WriteFile(local, []byte("[not a map"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(local); err == nil {
+		t.Error("Expected an error for an invalid file")
+	}
+}
@@ -0,0 +1,159 @@
+// Package browser opens URLs in a web browser with a chain of platform fallbacks
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// URLPlaceholder is replaced by the URL in browser_command and $BROWSER entries
+// (the URL is appended when it is missing)
+const URLPlaceholder = "%s"
+
+// openTimeout bounds each launcher: they hand the URL to the browser and exit
+const openTimeout = 5 * time.Second
+
+// Opener opens URLs using the first launcher that works
+type Opener struct {
+	Command  string                                         // browser_command from the config ("" = automatic)
+	GOOS     string                                         // Target OS (runtime.GOOS unless testing)
+	WSL      bool                                           // Running under Windows Subsystem for Linux
+	Getenv   func(string) string                            // Environment lookup (os.Getenv unless testing)
+	LookPath func(string) (string, error)                   // Executable lookup (exec.LookPath unless testing)
+	Run      func(ctx context.Context, argv []string) error // Launcher execution (exec unless testing)
+}
+
+// New creates an opener for the running platform
+// command is the browser_command config option (empty for automatic detection)
+func New(command string) *Opener {
+	return &Opener{
+		Command:  command,
+		GOOS:     runtime.GOOS,
+		WSL:      runtime.GOOS == "linux" && isWSL(),
+		Getenv:   os.Getenv,
+		LookPath: exec.LookPath,
+		Run:      run,
+	}
+}
+
+// Candidates returns the launcher command lines for url, in the order they are tried:
+// browser_command (alone when set), then each $BROWSER entry, then the platform launchers
+// Launchers that are not installed are skipped
+func (o *Opener) Candidates(url string) [][]string {
+	if fields := strings.Fields(o.Command); len(fields) > 0 {
+		return [][]string{withURL(fields, url)}
+	}
+
+	var candidates [][]string
+	// $BROWSER is a colon-separated list of commands (";" on Windows)
+	separator := ":"
+	if o.GOOS == "windows" {
+		separator = ";"
+	}
+	for _, entry := range strings.Split(o.Getenv("BROWSER"), separator) {
+		if fields := strings.Fields(entry); len(fields) > 0 && o.installed(fields[0]) {
+			candidates = append(candidates, withURL(fields, url))
+		}
+	}
+
+	for _, argv := range o.platformLaunchers(url) {
+		if o.installed(argv[0]) {
+			candidates = append(candidates, argv)
+		}
+	}
+	return candidates
+}
+
+// platformLaunchers returns the built-in launchers for the platform, preferred first
+func (o *Opener) platformLaunchers(url string) [][]string {
+	// PowerShell gets the URL as a single-quoted string literal
+	psURL := "'" + strings.ReplaceAll(url, "'", "''") + "'"
+
+	switch {
+	case o.GOOS == "darwin":
+		return [][]string{{"open", url}}
+	case o.GOOS == "windows":
+		// rundll32 takes the URL as a plain argument, so '&' and '^' survive
+		// (unlike 'cmd /c start', which breaks in Git Bash and on query strings)
+		return [][]string{
+			{"rundll32", "url.dll,FileProtocolHandler", url},
+			{"powershell.exe", "-NoProfile", "-NonInteractive", "-Command", "Start-Process " + psURL},
+			{"cmd", "/c", "start", "", url},
+		}
+	case o.WSL:
+		// Prefer the Windows browser: WSL rarely has a Linux one
+		return [][]string{
+			{"wslview", url},
+			{"powershell.exe", "-NoProfile", "-NonInteractive", "-Command", "Start-Process " + psURL},
+			{"xdg-open", url},
+		}
+	default:
+		return [][]string{{"xdg-open", url}, {"sensible-browser", url}}
+	}
+}
+
+// Open opens url with the first launcher that succeeds
+func (o *Opener) Open(url string) error {
+	candidates := o.Candidates(url)
+	if len(candidates) == 0 {
+		return errors.New("no browser launcher found; set browser_command in the config or $BROWSER")
+	}
+
+	var errs []error
+	for _, argv := range candidates {
+		ctx, cancel := context.WithTimeout(context.Background(), openTimeout)
+		err := o.Run(ctx, argv)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", argv[0], err))
+	}
+	return errors.Join(errs...)
+}
+
+// installed reports whether a launcher can be run
+func (o *Opener) installed(name string) bool {
+	_, err := o.LookPath(name)
+	return err == nil
+}
+
+// withURL substitutes the URL placeholder in a command line, appending the URL if there is none
+func withURL(fields []string, url string) []string {
+	argv := make([]string, 0, len(fields)+1)
+	substituted := false
+	for _, field := range fields {
+		if strings.Contains(field, URLPlaceholder) {
+			field = strings.ReplaceAll(field, URLPlaceholder, url)
+			substituted = true
+		}
+		argv = append(argv, field)
+	}
+	if !substituted {
+		argv = append(argv, url)
+	}
+	return argv
+}
+
+// run executes a launcher and waits for it to hand the URL over
+func run(ctx context.Context, argv []string) error {
+	// #nosec G204 -- Launchers come from the built-in list, browser_command or $BROWSER;
+	// the URL is passed as a single argument without a shell
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	return cmd.Run()
+}
+
+// isWSL reports whether the Linux kernel is a WSL one
+func isWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+}
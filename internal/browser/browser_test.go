@@ -0,0 +1,122 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testURL = "https://gitlab.example.com/group/api?scope=all&state=opened"
+
+// newTestOpener returns an opener for goos where only the given launchers are installed
+func newTestOpener(goos string, env map[string]string, installed ...string) *Opener {
+	return &Opener{
+		GOOS:   goos,
+		Getenv: func(key string) string { return env[key] },
+		LookPath: func(name string) (string, error) {
+			for _, bin := range installed {
+				if bin == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		},
+		Run: func(context.Context, []string) error { return nil },
+	}
+}
+
+// launcherNames returns the executables of command lines
+func launcherNames(candidates [][]string) []string {
+	names := make([]string, 0, len(candidates))
+	for _, argv := range candidates {
+		names = append(names, argv[0])
+	}
+	return names
+}
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		name      string
+		opener    *Opener
+		wantNames []string
+	}{
+		{"macOS", newTestOpener("darwin", nil, "open"), []string{"open"}},
+		{"linux", newTestOpener("linux", nil, "xdg-open", "sensible-browser"), []string{"xdg-open", "sensible-browser"}},
+		{"linux without xdg-open", newTestOpener("linux", nil, "sensible-browser"), []string{"sensible-browser"}},
+		{"windows", newTestOpener("windows", nil, "rundll32", "powershell.exe", "cmd"), []string{"rundll32", "powershell.exe", "cmd"}},
+		{"BROWSER first", newTestOpener("linux", map[string]string{"BROWSER": "firefox:missing:chromium --new-window"}, "firefox", "chromium", "xdg-open"), []string{"firefox", "chromium", "xdg-open"}},
+		{"nothing installed", newTestOpener("linux", nil), []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := launcherNames(tt.opener.Candidates(testURL))
+			if !reflect.DeepEqual(got, tt.wantNames) {
+				t.Errorf("Candidates() = %v, want %v", got, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestCandidates_WSL(t *testing.T) {
+	o := newTestOpener("linux", nil, "wslview", "powershell.exe", "xdg-open")
+	o.WSL = true
+	candidates := o.Candidates("https://gitlab.example.com/o'brien/api")
+	if got := launcherNames(candidates); !reflect.DeepEqual(got, []string{"wslview", "powershell.exe", "xdg-open"}) {
+		t.Fatalf("Candidates() = %v", got)
+	}
+	// The URL is a single-quoted PowerShell literal
+	if ps := candidates[1][len(candidates[1])-1]; ps != "Start-Process 'https://gitlab.example.com/o''brien/api'" {
+		t.Errorf("Unexpected PowerShell command %q", ps)
+	}
+}
+
+func TestCandidates_Command(t *testing.T) {
+	// browser_command is used alone, even if it is not found on PATH
+	o := newTestOpener("linux", map[string]string{"BROWSER": "firefox"}, "firefox", "xdg-open")
+	o.Command = "my-browser --tab %s --focus"
+	candidates := o.Candidates(testURL)
+	want := [][]string{{"my-browser", "--tab", testURL, "--focus"}}
+	if !reflect.DeepEqual(candidates, want) {
+		t.Errorf("Candidates() = %v, want %v", candidates, want)
+	}
+
+	o.Command = "my-browser"
+	if got := o.Candidates(testURL); !reflect.DeepEqual(got, [][]string{{"my-browser", testURL}}) {
+		t.Errorf("Expected the URL to be appended, got %v", got)
+	}
+}
+
+func TestOpen_FallsBack(t *testing.T) {
+	o := newTestOpener("windows", nil, "rundll32", "powershell.exe", "cmd")
+	var tried []string
+	o.Run = func(_ context.Context, argv []string) error {
+		tried = append(tried, argv[0])
+		if argv[0] == "rundll32" {
+			return errors.New("exit status 1")
+		}
+		return nil
+	}
+
+	if err := o.Open(testURL); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if !reflect.DeepEqual(tried, []string{"rundll32", "powershell.exe"}) {
+		t.Errorf("Expected rundll32 then powershell.exe, tried %v", tried)
+	}
+}
+
+func TestOpen_AllFail(t *testing.T) {
+	o := newTestOpener("linux", nil, "xdg-open", "sensible-browser")
+	o.Run = func(_ context.Context, argv []string) error { return errors.New("no display") }
+	err := o.Open(testURL)
+	if err == nil || !strings.Contains(err.Error(), "xdg-open: no display") || !strings.Contains(err.Error(), "sensible-browser: no display") {
+		t.Errorf("Expected every launcher error, got %v", err)
+	}
+
+	o = newTestOpener("linux", nil)
+	if err := o.Open(testURL); err == nil || !strings.Contains(err.Error(), "browser_command") {
+		t.Errorf("Expected a hint about browser_command, got %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -15,6 +16,15 @@ import (
 
 const projectsFileName = "projects.txt"
 
+// Sync timestamp files (incremental syncs fetch the changes since the last sync)
+const (
+	lastSyncFileName     = ".last_sync_time"
+	lastFullSyncFileName = ".last_full_sync_time"
+)
+
+// membershipOnlyFileName marks a cache whose last full sync fetched only member projects
+const membershipOnlyFileName = ".membership_only"
+
 // Cache manages the local project cache
 type Cache struct {
 	dir string
@@ -147,7 +157,7 @@ func (c *Cache) SaveLastSyncTime(t time.Time) error {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	timestampPath := filepath.Join(c.dir, ".last_sync_time")
+	timestampPath := filepath.Join(c.dir, lastSyncFileName)
 	data := []byte(t.Format(time.RFC3339))
 
 	if err := os.WriteFile(timestampPath, data, 0600); err != nil {
@@ -160,7 +170,7 @@ func (c *Cache) SaveLastSyncTime(t time.Time) error {
 // LoadLastSyncTime loads the last successful sync timestamp
 // Returns zero time if file doesn't exist (first sync)
 func (c *Cache) LoadLastSyncTime() (time.Time, error) {
-	timestampPath := filepath.Join(c.dir, ".last_sync_time")
+	timestampPath := filepath.Join(c.dir, lastSyncFileName)
 
 	// #nosec G304 -- Path constructed with filepath.Join(userConfigDir, fixedFilename)
 	// User controls config dir in their own config file - not a security issue:
@@ -190,7 +200,7 @@ func (c *Cache) SaveLastFullSyncTime(t time.Time) error {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	timestampPath := filepath.Join(c.dir, ".last_full_sync_time")
+	timestampPath := filepath.Join(c.dir, lastFullSyncFileName)
 	data := []byte(t.Format(time.RFC3339))
 
 	if err := os.WriteFile(timestampPath, data, 0600); err != nil {
@@ -203,7 +213,7 @@ func (c *Cache) SaveLastFullSyncTime(t time.Time) error {
 // LoadLastFullSyncTime loads the last successful full sync timestamp
 // Returns zero time if file doesn't exist (never had full sync)
 func (c *Cache) LoadLastFullSyncTime() (time.Time, error) {
-	timestampPath := filepath.Join(c.dir, ".last_full_sync_time")
+	timestampPath := filepath.Join(c.dir, lastFullSyncFileName)
 
 	// #nosec G304 -- Path constructed with filepath.Join(userConfigDir, fixedFilename)
 	// User controls config dir in their own config file - not a security issue:
@@ -227,6 +237,42 @@ func (c *Cache) LoadLastFullSyncTime() (time.Time, error) {
 	return t, nil
 }
 
+// ResetSyncTimes removes the sync timestamps, so the next sync is a full sync
+func (c *Cache) ResetSyncTimes() error {
+	for _, name := range []string{lastSyncFileName, lastFullSyncFileName} {
+		if err := os.Remove(filepath.Join(c.dir, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove sync timestamp: %w", err)
+		}
+	}
+	return nil
+}
+
+// SaveMembershipOnly records whether the last full sync fetched only member projects
+// (sync.membership_only); incremental syncs must fetch the same projects
+func (c *Cache) SaveMembershipOnly(membershipOnly bool) error {
+	markerPath := filepath.Join(c.dir, membershipOnlyFileName)
+	if !membershipOnly {
+		if err := os.Remove(markerPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove membership marker: %w", err)
+		}
+		return nil
+	}
+
+	if err := c.EnsureDir(); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(markerPath, nil, 0600); err != nil {
+		return fmt.Errorf("failed to save membership marker: %w", err)
+	}
+	return nil
+}
+
+// MembershipOnly reports whether the last full sync fetched only member projects
+func (c *Cache) MembershipOnly() bool {
+	_, err := os.Stat(filepath.Join(c.dir, membershipOnlyFileName))
+	return err == nil
+}
+
 // SaveUsername saves the GitLab username to cache
 func (c *Cache) SaveUsername(username string) error {
 	if err := c.EnsureDir(); err != nil {
@@ -306,3 +352,258 @@ func (c *Cache) LoadProjectSets() (starred, member map[string]bool, err error) {
 
 	return data.Starred, data.Member, nil
 }
+
+// mergeRequestsFileName stores the --mrs cache
+const mergeRequestsFileName = "merge_requests.json"
+
+// SaveMergeRequests saves the user's open merge requests with the time they were fetched
+func (c *Cache) SaveMergeRequests(mrs []model.MergeRequest, fetchedAt time.Time) error {
+	if err := c.EnsureDir(); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data := struct {
+		FetchedAt     time.Time            `json:"fetched_at"`
+		MergeRequests []model.MergeRequest `json:"merge_requests"`
+	}{FetchedAt: fetchedAt, MergeRequests: mrs}
+
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal merge requests: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(c.dir, mergeRequestsFileName), bytes, 0600)
+}
+
+// LoadMergeRequests loads cached merge requests and the time they were fetched
+// Returns nil and a zero time if the cache doesn't exist
+func (c *Cache) LoadMergeRequests() ([]model.MergeRequest, time.Time, error) {
+	path := filepath.Clean(filepath.Join(c.dir, mergeRequestsFileName))
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, time.Time{}, nil
+		}
+		return nil, time.Time{}, fmt.Errorf("failed to read merge requests: %w", err)
+	}
+
+	var data struct {
+		FetchedAt     time.Time            `json:"fetched_at"`
+		MergeRequests []model.MergeRequest `json:"merge_requests"`
+	}
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to unmarshal merge requests: %w", err)
+	}
+
+	return data.MergeRequests, data.FetchedAt, nil
+}
+
+// usersFileName stores the --users cache (refreshed by sync when gitlab.sync_users is enabled)
+const usersFileName = "users.json"
+
+// SaveUsers saves the instance's users with the time they were fetched
+func (c *Cache) SaveUsers(users []model.User, fetchedAt time.Time) error {
+	if err := c.EnsureDir(); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data := struct {
+		FetchedAt time.Time    `json:"fetched_at"`
+		Users     []model.User `json:"users"`
+	}{FetchedAt: fetchedAt, Users: users}
+
+	bytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal users: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(c.dir, usersFileName), bytes, 0600)
+}
+
+// LoadUsers loads cached users and the time they were fetched
+// Returns nil and a zero time if the cache doesn't exist
+func (c *Cache) LoadUsers() ([]model.User, time.Time, error) {
+	path := filepath.Clean(filepath.Join(c.dir, usersFileName))
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, time.Time{}, nil
+		}
+		return nil, time.Time{}, fmt.Errorf("failed to read users: %w", err)
+	}
+
+	var data struct {
+		FetchedAt time.Time    `json:"fetched_at"`
+		Users     []model.User `json:"users"`
+	}
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to unmarshal users: %w", err)
+	}
+
+	return data.Users, data.FetchedAt, nil
+}
+
+// sessionFileName stores the TUI state restored by --resume
+const sessionFileName = "session.json"
+
+// Session is the TUI state saved on exit: query, filter toggles and the result under the cursor
+type Session struct {
+	Query         string    `json:"query"`
+	Selected      string    `json:"selected,omitempty"` // Path of the project (or group) under the cursor
+	GroupsMode    bool      `json:"groups_mode,omitempty"`
+	RegexMode     bool      `json:"regex_mode,omitempty"`
+	ShowHidden    bool      `json:"show_hidden,omitempty"`
+	OnlyArchived  bool      `json:"only_archived,omitempty"`
+	OnlyNonMember bool      `json:"only_non_member,omitempty"`
+	OnlyStarred   bool      `json:"only_starred,omitempty"`
+	SortByMRs     bool      `json:"sort_by_mrs,omitempty"`
+	SavedAt       time.Time `json:"saved_at"`
+}
+
+// SaveSession saves the TUI session for --resume
+func (c *Cache) SaveSession(session Session) error {
+	if err := c.EnsureDir(); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	bytes, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(c.dir, sessionFileName), bytes, 0600)
+}
+
+// LoadSession loads the last saved TUI session
+// Returns nil if no session was saved
+func (c *Cache) LoadSession() (*Session, error) {
+	path := filepath.Clean(filepath.Join(c.dir, sessionFileName))
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(bytes, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+// queryPinsFileName stores the projects queries are pinned to (search.pin_after)
+const queryPinsFileName = "query_pins.json"
+
+// QueryPin is the project a query is pinned to: --go opens it without ranking
+type QueryPin struct {
+	Key      string    `json:"key"`  // History key of the project (see model.Project.Key)
+	Path     string    `json:"path"` // Path of the project when it was last opened
+	PinnedAt time.Time `json:"pinned_at"`
+}
+
+// QueryPins are the pinned queries, by normalized query
+// Removed records when a pin was removed: only selections made afterwards pin the query again
+type QueryPins struct {
+	Pins    map[string]QueryPin  `json:"pins,omitempty"`
+	Removed map[string]time.Time `json:"removed,omitempty"`
+}
+
+// LoadQueryPins loads the pinned queries (empty if none were saved)
+func (c *Cache) LoadQueryPins() (QueryPins, error) {
+	pins := QueryPins{Pins: make(map[string]QueryPin), Removed: make(map[string]time.Time)}
+	path := filepath.Clean(filepath.Join(c.dir, queryPinsFileName))
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return pins, nil
+		}
+		return pins, fmt.Errorf("failed to read query pins: %w", err)
+	}
+
+	if err := json.Unmarshal(bytes, &pins); err != nil {
+		return pins, fmt.Errorf("failed to unmarshal query pins: %w", err)
+	}
+	if pins.Pins == nil {
+		pins.Pins = make(map[string]QueryPin)
+	}
+	if pins.Removed == nil {
+		pins.Removed = make(map[string]time.Time)
+	}
+	return pins, nil
+}
+
+// SaveQueryPins saves the pinned queries
+func (c *Cache) SaveQueryPins(pins QueryPins) error {
+	if err := c.EnsureDir(); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	bytes, err := json.Marshal(pins)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query pins: %w", err)
+	}
+	return os.WriteFile(filepath.Join(c.dir, queryPinsFileName), bytes, 0600)
+}
+
+// queryHistoryFileName stores past TUI search queries (Up/Down recall)
+const queryHistoryFileName = "query_history.json"
+
+// LoadQueryHistory loads past search queries, oldest first
+// Returns nil if none were saved
+func (c *Cache) LoadQueryHistory() ([]string, error) {
+	path := filepath.Clean(filepath.Join(c.dir, queryHistoryFileName))
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read query history: %w", err)
+	}
+
+	var queries []string
+	if err := json.Unmarshal(bytes, &queries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal query history: %w", err)
+	}
+	return queries, nil
+}
+
+// AppendQueryHistory adds queries (oldest first) to the saved query history, keeping the
+// newest limit entries. A query already in the history moves to the end instead of repeating.
+// The file is re-read first, so sessions running side by side keep each other's queries
+func (c *Cache) AppendQueryHistory(queries []string, limit int) error {
+	if len(queries) == 0 || limit <= 0 {
+		return nil
+	}
+	history, err := c.LoadQueryHistory()
+	if err != nil {
+		history = nil // Unreadable history is replaced
+	}
+	for _, query := range queries {
+		history = AppendQuery(history, query)
+	}
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+
+	if err := c.EnsureDir(); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	bytes, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query history: %w", err)
+	}
+	return os.WriteFile(filepath.Join(c.dir, queryHistoryFileName), bytes, 0600)
+}
+
+// AppendQuery adds a query to the end of a query history, removing an earlier copy of it
+// Surrounding whitespace is trimmed; empty queries are not added
+func AppendQuery(history []string, query string) []string {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return history
+	}
+	history = slices.DeleteFunc(history, func(q string) bool { return q == query })
+	return append(history, query)
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"testing"
 	"time"
 
@@ -392,6 +393,32 @@ func TestReadProjects_DescriptionWithNewlines(t *testing.T) {
 	}
 }
 
+func TestResetSyncTimes(t *testing.T) {
+	cache := New(t.TempDir())
+
+	// Nothing to reset yet
+	if err := cache.ResetSyncTimes(); err != nil {
+		t.Fatalf("ResetSyncTimes without timestamps failed: %v", err)
+	}
+
+	now := time.Now()
+	if err := cache.SaveLastSyncTime(now); err != nil {
+		t.Fatalf("SaveLastSyncTime failed: %v", err)
+	}
+	if err := cache.SaveLastFullSyncTime(now); err != nil {
+		t.Fatalf("SaveLastFullSyncTime failed: %v", err)
+	}
+	if err := cache.ResetSyncTimes(); err != nil {
+		t.Fatalf("ResetSyncTimes failed: %v", err)
+	}
+
+	lastSync, _ := cache.LoadLastSyncTime()
+	lastFullSync, _ := cache.LoadLastFullSyncTime()
+	if !lastSync.IsZero() || !lastFullSync.IsZero() {
+		t.Errorf("Expected no sync times after reset, got %v and %v", lastSync, lastFullSync)
+	}
+}
+
 func TestLoadLastSyncTime_CorruptedFile(t *testing.T) {
 	tmpDir, err := os.MkdirTemp("", "glf-cache-test-*")
 	if err != nil {
@@ -887,3 +914,139 @@ func TestLoadUsername_FileIsDirectory(t *testing.T) {
 		t.Errorf("Expected 'failed to read username' in error, got: %v", err)
 	}
 }
+
+func TestSaveLoadMergeRequests(t *testing.T) {
+	c := New(t.TempDir())
+
+	// Missing cache is not an error
+	mrs, fetchedAt, err := c.LoadMergeRequests()
+	if err != nil || mrs != nil || !fetchedAt.IsZero() {
+		t.Fatalf("Expected empty result for missing cache, got %v %v %v", mrs, fetchedAt, err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	saved := []model.MergeRequest{
+		{ID: 501, IID: 12, ProjectPath: "group/api", Title: "Add retries", Labels: []string{"backend"}, WebURL: "https://gitlab.example.com/group/api/-/merge_requests/12"},
+	}
+	if err := c.SaveMergeRequests(saved, now); err != nil {
+		t.Fatalf("SaveMergeRequests failed: %v", err)
+	}
+
+	mrs, fetchedAt, err = c.LoadMergeRequests()
+	if err != nil {
+		t.Fatalf("LoadMergeRequests failed: %v", err)
+	}
+	if !fetchedAt.Equal(now) {
+		t.Errorf("Expected fetched time %v, got %v", now, fetchedAt)
+	}
+	if len(mrs) != 1 || mrs[0].ProjectPath != "group/api" || mrs[0].IID != 12 || len(mrs[0].Labels) != 1 {
+		t.Errorf("Unexpected merge requests: %+v", mrs)
+	}
+}
+
+func TestSaveLoadUsers(t *testing.T) {
+	c := New(t.TempDir())
+
+	// Missing cache is not an error
+	users, fetchedAt, err := c.LoadUsers()
+	if err != nil || users != nil || !fetchedAt.IsZero() {
+		t.Fatalf("Expected empty result for missing cache, got %v %v %v", users, fetchedAt, err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	saved := []model.User{{ID: 7, Username: "jdoe", Name: "Jane Doe", WebURL: "https://gitlab.example.com/jdoe"}}
+	if err := c.SaveUsers(saved, now); err != nil {
+		t.Fatalf("SaveUsers failed: %v", err)
+	}
+
+	users, fetchedAt, err = c.LoadUsers()
+	if err != nil {
+		t.Fatalf("LoadUsers failed: %v", err)
+	}
+	if !fetchedAt.Equal(now) {
+		t.Errorf("Expected fetched time %v, got %v", now, fetchedAt)
+	}
+	if len(users) != 1 || users[0] != saved[0] {
+		t.Errorf("Unexpected users: %+v", users)
+	}
+}
+
+func TestSaveLoadSession(t *testing.T) {
+	c := New(t.TempDir())
+
+	// Missing session is not an error
+	session, err := c.LoadSession()
+	if err != nil || session != nil {
+		t.Fatalf("Expected no session, got %+v %v", session, err)
+	}
+
+	saved := Session{Query: "api", Selected: "group/api", OnlyStarred: true, SortByMRs: true, SavedAt: time.Now().Truncate(time.Second)}
+	if err := c.SaveSession(saved); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	session, err = c.LoadSession()
+	if err != nil {
+		t.Fatalf("LoadSession failed: %v", err)
+	}
+	if session == nil || session.Query != saved.Query || session.Selected != saved.Selected ||
+		!session.OnlyStarred || !session.SortByMRs || session.ShowHidden || !session.SavedAt.Equal(saved.SavedAt) {
+		t.Errorf("Unexpected session: %+v", session)
+	}
+}
+
+func TestSaveLoadQueryPins(t *testing.T) {
+	c := New(t.TempDir())
+
+	// Missing pins are not an error, and the maps are ready to use
+	pins, err := c.LoadQueryPins()
+	if err != nil || len(pins.Pins) != 0 || pins.Removed == nil {
+		t.Fatalf("Expected empty pins, got %+v %v", pins, err)
+	}
+
+	pins.Pins["api"] = QueryPin{Key: "id:42", Path: "group/api", PinnedAt: time.Now().Truncate(time.Second)}
+	if err := c.SaveQueryPins(pins); err != nil {
+		t.Fatalf("SaveQueryPins failed: %v", err)
+	}
+	loaded, err := c.LoadQueryPins()
+	if err != nil {
+		t.Fatalf("LoadQueryPins failed: %v", err)
+	}
+	if pin := loaded.Pins["api"]; pin.Key != "id:42" || pin.Path != "group/api" || !pin.PinnedAt.Equal(pins.Pins["api"].PinnedAt) {
+		t.Errorf("Unexpected pin: %+v", pin)
+	}
+}
+
+func TestAppendQueryHistory(t *testing.T) {
+	c := New(t.TempDir())
+
+	// Missing history is not an error
+	queries, err := c.LoadQueryHistory()
+	if err != nil || queries != nil {
+		t.Fatalf("Expected no query history, got %v %v", queries, err)
+	}
+
+	if err := c.AppendQueryHistory([]string{"api", " web ", "", "infra"}, 10); err != nil {
+		t.Fatalf("AppendQueryHistory failed: %v", err)
+	}
+	// A second session: repeated queries move to the end, the oldest ones fall off
+	if err := c.AppendQueryHistory([]string{"api", "docs"}, 3); err != nil {
+		t.Fatalf("AppendQueryHistory failed: %v", err)
+	}
+
+	queries, err = c.LoadQueryHistory()
+	if err != nil {
+		t.Fatalf("LoadQueryHistory failed: %v", err)
+	}
+	if want := []string{"infra", "api", "docs"}; !slices.Equal(queries, want) {
+		t.Errorf("Expected %v, got %v", want, queries)
+	}
+
+	// A zero limit (tui.query_history: 0) saves nothing
+	if err := c.AppendQueryHistory([]string{"ignored"}, 0); err != nil {
+		t.Fatalf("AppendQueryHistory failed: %v", err)
+	}
+	if queries, _ := c.LoadQueryHistory(); slices.Contains(queries, "ignored") {
+		t.Errorf("Expected nothing saved with a zero limit, got %v", queries)
+	}
+}
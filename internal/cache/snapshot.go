@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/paths"
+)
+
+// maxSnapshotSize limits the unpacked size of a snapshot
+const maxSnapshotSize = 4 << 30
+
+// ErrSnapshotTooLarge is returned by ExtractSnapshot when a snapshot unpacks beyond maxSnapshotSize
+var ErrSnapshotTooLarge = errors.New("snapshot is too large")
+
+// SnapshotEntries returns the top-level cache entries taken from a team snapshot (--from-snapshot):
+// the project and group indexes (with the index's project list snapshot), the project list,
+// renames and the sync timestamps
+// Per-user state (history, starred and member sets, username, session) is never taken from a snapshot
+func SnapshotEntries() []string {
+	return []string{
+		filepath.Base(paths.IndexPath("")),
+		filepath.Base(index.SnapshotPath(paths.IndexPath(""))),
+		filepath.Base(paths.GroupIndexPath("")),
+		filepath.Base(paths.RenamesPath("")),
+		projectsFileName,
+		lastSyncFileName,
+		lastFullSyncFileName,
+	}
+}
+
+// ExtractSnapshot unpacks a snapshot (a tar archive of a cache directory, gzip-compressed or not)
+// into dir, keeping only SnapshotEntries
+// Entries escaping dir, links and other special files are rejected
+func ExtractSnapshot(r io.Reader, dir string) error {
+	br := bufio.NewReader(r)
+	var archive io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("invalid snapshot: %w", err)
+		}
+		defer gz.Close()
+		archive = gz
+	}
+
+	allowed := make(map[string]bool)
+	for _, entry := range SnapshotEntries() {
+		allowed[entry] = true
+	}
+
+	tr := tar.NewReader(archive)
+	var total int64
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid snapshot: %w", err)
+		}
+
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if name == "." {
+			continue
+		}
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid snapshot: entry %s is outside the cache directory", header.Name)
+		}
+		if top := strings.SplitN(name, string(filepath.Separator), 2)[0]; !allowed[top] {
+			logger.Debug("Skipping snapshot entry %s", header.Name)
+			continue
+		}
+
+		target := filepath.Join(dir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0750); err != nil {
+				return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+			}
+		case tar.TypeReg:
+			total += header.Size
+			if total > maxSnapshotSize {
+				return ErrSnapshotTooLarge
+			}
+			if err := extractFile(tr, target, header.Size); err != nil {
+				return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+			}
+		default:
+			return fmt.Errorf("invalid snapshot: entry %s is not a regular file or directory", header.Name)
+		}
+	}
+}
+
+// extractFile writes the current tar entry (size bytes) to target
+func extractFile(r io.Reader, target string, size int64) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Clean(target), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(f, r, size); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// InstallSnapshot moves the entries of a snapshot extracted to stagingDir into the cache,
+// replacing the existing ones, and returns the number of entries installed
+// Cache entries missing from the snapshot are removed too, so nothing stale mixes with it
+func (c *Cache) InstallSnapshot(stagingDir string) (int, error) {
+	if err := c.EnsureDir(); err != nil {
+		return 0, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	installed := 0
+	for _, entry := range SnapshotEntries() {
+		target := filepath.Join(c.dir, entry)
+		if err := os.RemoveAll(target); err != nil {
+			return installed, fmt.Errorf("failed to replace %s: %w", entry, err)
+		}
+		staged := filepath.Join(stagingDir, entry)
+		if _, err := os.Stat(staged); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := os.Rename(staged, target); err != nil {
+			return installed, fmt.Errorf("failed to install %s: %w", entry, err)
+		}
+		installed++
+	}
+	return installed, nil
+}
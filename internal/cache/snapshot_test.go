@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tarEntry is a file or directory of a test snapshot
+type tarEntry struct {
+	name    string
+	content string
+	dir     bool
+}
+
+// buildSnapshot returns a tar archive of entries, gzip-compressed if compress is set
+func buildSnapshot(t *testing.T, entries []tarEntry, compress bool) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var tw *tar.Writer
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		tw = tar.NewWriter(gz)
+	} else {
+		tw = tar.NewWriter(&buf)
+	}
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0600, Size: int64(len(e.content)), Typeflag: tar.TypeReg}
+		if e.dir {
+			header = &tar.Header{Name: e.name, Mode: 0750, Typeflag: tar.TypeDir}
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if !e.dir {
+			if _, err := tw.Write([]byte(e.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestExtractSnapshot(t *testing.T) {
+	entries := []tarEntry{
+		{name: "./description.bleve/", dir: true},
+		{name: "./description.bleve/index_meta.json", content: "{}"},
+		{name: "./projects.txt", content: "group/api|api|\n"},
+		{name: "./.last_sync_time", content: "2026-10-01T10:00:00Z"},
+		{name: "./history.gob", content: "someone else's history"},
+	}
+
+	for _, compress := range []bool{true, false} {
+		dir := t.TempDir()
+		if err := ExtractSnapshot(bytes.NewReader(buildSnapshot(t, entries, compress)), dir); err != nil {
+			t.Fatalf("ExtractSnapshot(compressed %v) failed: %v", compress, err)
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, "description.bleve", "index_meta.json")); err != nil || string(data) != "{}" {
+			t.Errorf("Expected the index to be extracted, got %q, %v", data, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "history.gob")); !os.IsNotExist(err) {
+			t.Error("Expected per-user files to be skipped")
+		}
+	}
+}
+
+func TestExtractSnapshot_Rejects(t *testing.T) {
+	outside := buildSnapshot(t, []tarEntry{{name: "../projects.txt", content: "x"}}, true)
+	if err := ExtractSnapshot(bytes.NewReader(outside), t.TempDir()); err == nil {
+		t.Error("Expected an error for an entry outside the cache directory")
+	}
+
+	var symlink bytes.Buffer
+	tw := tar.NewWriter(&symlink)
+	if err := tw.WriteHeader(&tar.Header{Name: "projects.txt", Linkname: "/etc/passwd", Typeflag: tar.TypeSymlink}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+	if err := ExtractSnapshot(&symlink, t.TempDir()); err == nil {
+		t.Error("Expected an error for a symlink")
+	}
+
+	if err := ExtractSnapshot(bytes.NewReader([]byte("not a snapshot at all")), t.TempDir()); err == nil {
+		t.Error("Expected an error for data that is not a tar archive")
+	}
+}
+
+func TestInstallSnapshot(t *testing.T) {
+	cacheDir := t.TempDir()
+	staging := t.TempDir()
+	c := New(cacheDir)
+
+	// Existing cache: an old index, sets of this user and a group index missing from the snapshot
+	for _, dir := range []string{"description.bleve", "groups.bleve"} {
+		if err := os.MkdirAll(filepath.Join(cacheDir, dir), 0750); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "description.bleve", "old"), []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SaveProjectSets(map[string]bool{"group/api": true}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(staging, "description.bleve"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(staging, ".last_sync_time"), []byte("2026-10-01T10:00:00Z"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	installed, err := c.InstallSnapshot(staging)
+	if err != nil {
+		t.Fatalf("InstallSnapshot failed: %v", err)
+	}
+	if installed != 2 {
+		t.Errorf("Expected 2 installed entries, got %d", installed)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "description.bleve", "old")); !os.IsNotExist(err) {
+		t.Error("Expected the old index to be replaced")
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "groups.bleve")); !os.IsNotExist(err) {
+		t.Error("Expected the stale group index to be removed")
+	}
+	if lastSync, err := c.LoadLastSyncTime(); err != nil || lastSync.IsZero() {
+		t.Errorf("Expected the snapshot's sync time, got %v, %v", lastSync, err)
+	}
+	if starred, _, err := c.LoadProjectSets(); err != nil || !starred["group/api"] {
+		t.Errorf("Expected this user's project sets to be kept, got %v, %v", starred, err)
+	}
+}
@@ -0,0 +1,104 @@
+// Package clipboard copies text to the system clipboard with a chain of platform fallbacks
+package clipboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// copyTimeout bounds each clipboard tool: they read stdin and exit
+const copyTimeout = 5 * time.Second
+
+// Copier copies text using the first clipboard tool that works
+type Copier struct {
+	GOOS     string                                                       // Target OS (runtime.GOOS unless testing)
+	WSL      bool                                                         // Running under Windows Subsystem for Linux
+	Getenv   func(string) string                                          // Environment lookup (os.Getenv unless testing)
+	LookPath func(string) (string, error)                                 // Executable lookup (exec.LookPath unless testing)
+	Run      func(ctx context.Context, argv []string, input string) error // Tool execution with input on stdin (exec unless testing)
+}
+
+// New creates a copier for the running platform
+func New() *Copier {
+	return &Copier{
+		GOOS:     runtime.GOOS,
+		WSL:      runtime.GOOS == "linux" && isWSL(),
+		Getenv:   os.Getenv,
+		LookPath: exec.LookPath,
+		Run:      run,
+	}
+}
+
+// Candidates returns the clipboard tool command lines in the order they are tried
+// Tools that are not installed are skipped
+func (c *Copier) Candidates() [][]string {
+	var tools [][]string
+	switch {
+	case c.GOOS == "darwin":
+		tools = [][]string{{"pbcopy"}}
+	case c.GOOS == "windows":
+		tools = [][]string{{"clip"}}
+	default:
+		if c.WSL {
+			tools = append(tools, []string{"clip.exe"})
+		}
+		if c.Getenv("WAYLAND_DISPLAY") != "" {
+			tools = append(tools, []string{"wl-copy"})
+		}
+		tools = append(tools,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"},
+		)
+	}
+
+	candidates := make([][]string, 0, len(tools))
+	for _, argv := range tools {
+		if _, err := c.LookPath(argv[0]); err == nil {
+			candidates = append(candidates, argv)
+		}
+	}
+	return candidates
+}
+
+// Copy puts text on the clipboard with the first tool that succeeds
+func (c *Copier) Copy(text string) error {
+	candidates := c.Candidates()
+	if len(candidates) == 0 {
+		return errors.New("no clipboard tool found (install wl-copy, xclip or xsel)")
+	}
+
+	var errs []error
+	for _, argv := range candidates {
+		ctx, cancel := context.WithTimeout(context.Background(), copyTimeout)
+		err := c.Run(ctx, argv, text)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", argv[0], err))
+	}
+	return errors.Join(errs...)
+}
+
+// run executes a clipboard tool with input on stdin
+func run(ctx context.Context, argv []string, input string) error {
+	// #nosec G204 -- Tools come from the built-in list
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdin = strings.NewReader(input)
+	return cmd.Run()
+}
+
+// isWSL reports whether the Linux kernel is a WSL one
+func isWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	return err == nil && strings.Contains(strings.ToLower(string(release)), "microsoft")
+}
@@ -0,0 +1,78 @@
+package clipboard
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestCopier returns a copier for goos where only the given tools are installed
+func newTestCopier(goos string, env map[string]string, installed ...string) *Copier {
+	return &Copier{
+		GOOS:   goos,
+		Getenv: func(key string) string { return env[key] },
+		LookPath: func(name string) (string, error) {
+			for _, bin := range installed {
+				if bin == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		},
+		Run: func(context.Context, []string, string) error { return nil },
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	tests := []struct {
+		name      string
+		copier    *Copier
+		wantNames []string
+	}{
+		{"macOS", newTestCopier("darwin", nil, "pbcopy"), []string{"pbcopy"}},
+		{"windows", newTestCopier("windows", nil, "clip"), []string{"clip"}},
+		{"X11", newTestCopier("linux", nil, "wl-copy", "xclip", "xsel"), []string{"xclip", "xsel"}},
+		{"wayland", newTestCopier("linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, "wl-copy", "xsel"), []string{"wl-copy", "xsel"}},
+		{"nothing installed", newTestCopier("linux", nil), []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := []string{}
+			for _, argv := range tt.copier.Candidates() {
+				names = append(names, argv[0])
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("Candidates() = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestCopy(t *testing.T) {
+	c := newTestCopier("linux", nil, "xclip", "xsel")
+	c.WSL = true
+	var tried []string
+	var copied string
+	c.Run = func(_ context.Context, argv []string, input string) error {
+		tried = append(tried, argv[0])
+		if argv[0] == "xclip" {
+			return errors.New("no display")
+		}
+		copied = input
+		return nil
+	}
+
+	if err := c.Copy("git clone a\n"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if !reflect.DeepEqual(tried, []string{"xclip", "xsel"}) || copied != "git clone a\n" {
+		t.Errorf("Expected xclip then xsel to receive the text, tried %v, copied %q", tried, copied)
+	}
+
+	c = newTestCopier("linux", nil)
+	if err := c.Copy("x"); err == nil || !strings.Contains(err.Error(), "no clipboard tool") {
+		t.Errorf("Expected an error without clipboard tools, got %v", err)
+	}
+}
@@ -4,10 +4,20 @@ package config
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/igusev/glf/internal/history"
+	"github.com/igusev/glf/internal/opener"
+	"github.com/igusev/glf/internal/paths"
+	"github.com/igusev/glf/internal/target"
+	"github.com/igusev/glf/internal/tokenstore"
 	"github.com/spf13/viper"
 )
 
@@ -16,46 +26,300 @@ var ErrConfigNotFound = errors.New("configuration not found")
 
 // Config holds the application configuration
 type Config struct {
-	GitLab        GitLabConfig `mapstructure:"gitlab"`
-	Cache         CacheConfig  `mapstructure:"cache"`
-	ExcludedPaths []string     `mapstructure:"excluded_paths"`
+	GitLab          GitLabConfig      `mapstructure:"gitlab"`
+	Cache           CacheConfig       `mapstructure:"cache"`
+	History         HistoryConfig     `mapstructure:"history" yaml:"history,omitempty"`
+	TUI             TUIConfig         `mapstructure:"tui" yaml:"tui,omitempty"`
+	Scoring         ScoringConfig     `mapstructure:"scoring" yaml:"scoring,omitempty"`
+	Search          SearchConfig      `mapstructure:"search" yaml:"search,omitempty"`
+	NewProject      NewProjectConfig  `mapstructure:"new_project" yaml:"new_project,omitempty"`
+	Annotations     AnnotationsConfig `mapstructure:"annotations" yaml:"annotations,omitempty"`
+	Sync            SyncConfig        `mapstructure:"sync" yaml:"sync,omitempty"`
+	ExcludedPaths   []string          `mapstructure:"excluded_paths"`
+	PinnedPaths     []string          `mapstructure:"pinned_paths" yaml:"pinned_paths,omitempty"`         // projects always shown at the top of results (in pin order)
+	WorkspaceDir    string            `mapstructure:"workspace_dir" yaml:"workspace_dir,omitempty"`       // local clones live at workspace_dir/<project path> (--edit, --cd)
+	WorkspaceLayout string            `mapstructure:"workspace_layout" yaml:"workspace_layout,omitempty"` // "nested" (workspace_dir/group/project, default) or "flat" (workspace_dir/project)
+	Editor          string            `mapstructure:"editor" yaml:"editor,omitempty"`                     // editor command for --edit (default $VISUAL, $EDITOR, then code)
+	BrowserCommand  string            `mapstructure:"browser_command" yaml:"browser_command,omitempty"`   // command that opens URLs ("%s" = URL; default: $BROWSER, then platform launchers)
+	Openers         []OpenerConfig    `mapstructure:"openers" yaml:"openers,omitempty"`                   // commands run on a result instead of opening the browser (--open-with, TUI keys)
+	URLTemplates    map[string]string `mapstructure:"url_templates" yaml:"url_templates,omitempty"`       // project URLs by target ("project" = project page; see target.SetTemplates)
+}
+
+// OpenerConfig defines a command run on a search result (see package opener for the placeholders)
+type OpenerConfig struct {
+	Name    string `mapstructure:"name" yaml:"name"`         // name used with --open-with
+	Key     string `mapstructure:"key" yaml:"key,omitempty"` // TUI key running the opener (e.g. "alt+t"; optional)
+	Command string `mapstructure:"command" yaml:"command"`   // shell command template (e.g. "open -a Tower {{url}}")
 }
 
 // GitLabConfig holds GitLab-specific settings
 type GitLabConfig struct {
 	URL         string `mapstructure:"url"`
 	Token       string `mapstructure:"token"`
-	Timeout     int    `mapstructure:"timeout"`     // timeout in seconds
+	Timeout     int    `mapstructure:"timeout"`     // timeout of each API request in seconds
 	Concurrency int    `mapstructure:"concurrency"` // max concurrent API requests (default 10)
+
+	MaxRetries   int     `mapstructure:"max_retries" yaml:"max_retries,omitempty"`     // retries of a failed API request (default 3, 0 disables)
+	RetryBackoff float64 `mapstructure:"retry_backoff" yaml:"retry_backoff,omitempty"` // seconds before the first retry, doubling with jitter (default 1)
+	SyncTimeout  int     `mapstructure:"sync_timeout" yaml:"sync_timeout,omitempty"`   // limit for a whole sync in seconds, retries included (default 0 = none)
+
+	TokenExpiryWarnDays int `mapstructure:"token_expiry_warn_days" yaml:"token_expiry_warn_days,omitempty"` // warn during sync when token expires within N days (default 14, 0 disables)
+
+	TokenBackend string `mapstructure:"token_backend" yaml:"token_backend,omitempty"` // where the token lives: config (default), keychain, secret-service, wincred, command
+	TokenCommand string `mapstructure:"token_command" yaml:"token_command,omitempty"` // external command printing the token (e.g., "pass show gitlab/token")
+
+	OAuthClientID string `mapstructure:"oauth_client_id" yaml:"oauth_client_id,omitempty"` // application ID of the GitLab OAuth application used by --login
+
+	// OAuth is set when the token is an OAuth access token from glf --login (sent as a Bearer token)
+	OAuth bool `mapstructure:"-" yaml:"-"`
+
+	Insights bool `mapstructure:"insights" yaml:"insights,omitempty"` // fetch open MR/issue counts for member projects during sync (extra API calls)
+
+	// RemoteFallback is the former location of search.remote_fallback, still read from older
+	// configs that don't set the new key (Load copies it to Search.RemoteFallback, Save drops it)
+	RemoteFallback bool `mapstructure:"remote_fallback" yaml:"remote_fallback,omitempty"`
+
+	SyncUsers bool `mapstructure:"sync_users" yaml:"sync_users,omitempty"` // fetch the instance's users during sync for --users (extra API calls)
+
+	WebhookSecret string `mapstructure:"webhook_secret" yaml:"webhook_secret,omitempty"` // secret token GitLab sends with system hook events (--listen)
 }
 
 // CacheConfig holds cache-specific settings
 type CacheConfig struct {
-	Dir string `mapstructure:"dir"`
+	Dir      string `mapstructure:"dir"`
+	StateDir string `mapstructure:"state_dir" yaml:"state_dir,omitempty"` // writable dir for history, session and username (default: dir)
+	ReadOnly bool   `mapstructure:"read_only" yaml:"read_only,omitempty"` // dir is a shared cache maintained elsewhere: never sync into it
+
+	// Unwritable is set by Load when dir exists but cannot be written (treated as read_only)
+	Unwritable bool `mapstructure:"-" yaml:"-"`
+}
+
+// HistoryConfig holds history ranking settings
+type HistoryConfig struct {
+	HalfLifeDays float64 `mapstructure:"half_life_days" yaml:"half_life_days,omitempty"` // days for a selection's weight to halve (default 30)
+	MaxAgeDays   float64 `mapstructure:"max_age_days" yaml:"max_age_days,omitempty"`     // selections older than this are forgotten (default 100)
+
+	// ContextRanking weights selections by time of day and weekday/weekend
+	ContextRanking bool `mapstructure:"context_ranking" yaml:"context_ranking,omitempty"`
+
+	// Algorithm weighs selections by age: "decay" (default, uses half_life_days) or "frecency"
+	Algorithm string `mapstructure:"algorithm" yaml:"algorithm,omitempty"`
+
+	// Enabled records selections (default true); false stops tracking entirely
+	Enabled bool `mapstructure:"enabled" yaml:"enabled,omitempty"`
+
+	// RetentionDays purges selections older than this on every save (0 keeps them until max_age_days)
+	RetentionDays float64 `mapstructure:"retention_days" yaml:"retention_days,omitempty"`
+}
+
+// TUIConfig holds interactive finder settings
+type TUIConfig struct {
+	// Avatars shows project avatars in the README preview (colored initials if the
+	// terminal can't draw images)
+	Avatars bool `mapstructure:"avatars" yaml:"avatars,omitempty"`
+	// ImageProtocol forces the graphics protocol: auto (default), kitty, iterm2, sixel or none
+	ImageProtocol string `mapstructure:"image_protocol" yaml:"image_protocol,omitempty"`
+	// Resume restores the last session (query, filter toggles, selected result) on every
+	// interactive start, like --resume
+	Resume bool `mapstructure:"resume" yaml:"resume,omitempty"`
+	// QueryHistory is the number of past search queries kept for Up/Down recall
+	// (default DefaultQueryHistory, 0 disables it)
+	QueryHistory int `mapstructure:"query_history" yaml:"query_history,omitempty"`
+}
+
+// DefaultQueryHistory is the default number of past search queries kept (tui.query_history)
+const DefaultQueryHistory = 100
+
+// ScoringConfig holds search ranking settings
+type ScoringConfig struct {
+	// ArchivedPenalty is subtracted from the score of archived projects, so they rank below
+	// active ones when shown (0 = no penalty)
+	ArchivedPenalty float64 `mapstructure:"archived_penalty" yaml:"archived_penalty,omitempty"`
+
+	// ForkPenalty is subtracted from the score of forks, so the upstream project ranks
+	// above forks with the same name (0 = no penalty)
+	ForkPenalty float64 `mapstructure:"fork_penalty" yaml:"fork_penalty,omitempty"`
+}
+
+// SearchConfig holds query matching settings
+type SearchConfig struct {
+	// Fuzziness is the edit distance tolerated per query word: auto (default: 0 for words of
+	// up to 2 characters, 1 up to 5, 2 for longer ones), or 0, 1 or 2 for every word
+	Fuzziness string `mapstructure:"fuzziness" yaml:"fuzziness,omitempty"`
+
+	RemoteFallback bool `mapstructure:"remote_fallback" yaml:"remote_fallback,omitempty"` // search GitLab live when a query has no local results
+
+	// GoMargin is how far (as a fraction of its score) the best --go match must lead the
+	// runner-up to be opened directly; closer results ask which one to open (0 disables)
+	GoMargin float64 `mapstructure:"go_margin" yaml:"go_margin,omitempty"`
+
+	// PinAfter pins a query to a project once it was selected for that query this many
+	// times: --go then opens it without ranking (0 disables pinning)
+	PinAfter int `mapstructure:"pin_after" yaml:"pin_after,omitempty"`
+
+	// CollapseForks lists forks under their upstream project instead of as separate results
+	// (expanded with Alt+F in the TUI); the user's own forks stay listed
+	CollapseForks bool `mapstructure:"collapse_forks" yaml:"collapse_forks,omitempty"`
+}
+
+// DefaultGoMargin is the default lead of the best --go match (search.go_margin)
+const DefaultGoMargin = 0.1
+
+// FuzzinessAuto is returned by GetFuzziness when the edit distance depends on the word length
+const FuzzinessAuto = -1
+
+// GetFuzziness returns the tolerated edit distance per query word (FuzzinessAuto for auto)
+func (c *SearchConfig) GetFuzziness() int {
+	switch c.Fuzziness {
+	case "0":
+		return 0
+	case "1":
+		return 1
+	case "2":
+		return 2
+	}
+	return FuzzinessAuto
+}
+
+// SyncConfig holds the automatic sync policy
+type SyncConfig struct {
+	// Auto is when glf syncs on its own: on-start (default: when the finder starts, and in the
+	// background when the cache is stale), interval (also every interval while the finder runs)
+	// or off (only explicit syncs: --sync, Ctrl+R, and the first sync of an empty cache)
+	Auto string `mapstructure:"auto" yaml:"auto,omitempty"`
+
+	// Interval between syncs of a running finder with auto: interval (Go duration, default 30m)
+	Interval string `mapstructure:"interval" yaml:"interval,omitempty"`
+
+	// MembershipOnly fetches only the projects the user is a member of instead of every
+	// project visible to them (much faster on large instances)
+	MembershipOnly bool `mapstructure:"membership_only" yaml:"membership_only,omitempty"`
+}
+
+// Automatic sync policies (sync.auto)
+const (
+	SyncAutoOff      = "off"
+	SyncAutoOnStart  = "on-start"
+	SyncAutoInterval = "interval"
+)
+
+// DefaultSyncInterval is the default interval of sync.auto: interval (sync.interval)
+const DefaultSyncInterval = 30 * time.Minute
+
+// minSyncInterval is the shortest sync.interval accepted (a sync costs many API requests)
+const minSyncInterval = time.Minute
+
+// Enabled reports whether glf may sync on its own (sync.auto is not off)
+func (c *SyncConfig) Enabled() bool {
+	return c.Auto != SyncAutoOff
+}
+
+// GetInterval returns the interval between syncs of a running finder (0 unless sync.auto is interval)
+func (c *SyncConfig) GetInterval() time.Duration {
+	if c.Auto != SyncAutoInterval {
+		return 0
+	}
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil || interval <= 0 {
+		return DefaultSyncInterval
+	}
+	return interval
+}
+
+// NewProjectConfig holds the defaults of projects created with glf --new
+type NewProjectConfig struct {
+	Visibility string `mapstructure:"visibility" yaml:"visibility,omitempty"`   // private (default), internal or public
+	InitReadme bool   `mapstructure:"init_readme" yaml:"init_readme,omitempty"` // create an initial README commit (default true)
+}
+
+// AnnotationsConfig locates annotations.yaml, the team-shared project labels (label: filters)
+type AnnotationsConfig struct {
+	File    string `mapstructure:"file" yaml:"file,omitempty"`       // local file (default: annotations.yaml next to config.yaml, if present)
+	Project string `mapstructure:"project" yaml:"project,omitempty"` // GitLab project whose file is fetched during sync (e.g. "platform/handbook")
+	Path    string `mapstructure:"path" yaml:"path,omitempty"`       // file in that project (default annotations.yaml)
+	Ref     string `mapstructure:"ref" yaml:"ref,omitempty"`         // branch or tag of the file (default: the project's default branch)
+}
+
+// DefaultAnnotationsPath is the file fetched from annotations.project when annotations.path is not set
+const DefaultAnnotationsPath = "annotations.yaml"
+
+// GetFile returns the local annotations file (annotations.file, or annotations.yaml in the config directory)
+func (c *AnnotationsConfig) GetFile() string {
+	if c.File != "" {
+		return c.File
+	}
+	return paths.AnnotationsPath(paths.ConfigDir())
+}
+
+// GetPath returns the path of the shared file in annotations.project
+func (c *AnnotationsConfig) GetPath() string {
+	if c.Path != "" {
+		return c.Path
+	}
+	return DefaultAnnotationsPath
 }
 
+// Project visibility levels accepted by new_project.visibility
+var visibilities = []string{"private", "internal", "public"}
+
 // Load loads configuration from file and environment variables
 func Load() (*Config, error) {
-	// Set config file paths
-	configDir := filepath.Join(os.Getenv("HOME"), ".config", "glf")
+	return load(true, false)
+}
+
+// LoadOffline is Load without network access (glf --offline): an expired OAuth access token
+// is kept instead of refreshed, as local-only runs never send it
+func LoadOffline() (*Config, error) {
+	return load(true, true)
+}
+
+// LoadWithoutToken loads the configuration without resolving the token (glf --login obtains one)
+// gitlab.token keeps the value from config.yaml or GLF_GITLAB_TOKEN, if any
+func LoadWithoutToken() (*Config, error) {
+	return load(false, false)
+}
+
+// load reads the configuration; requireToken resolves the token and fails without one,
+// offline skips refreshing an expired OAuth token
+func load(requireToken, offline bool) (*Config, error) {
+	// Set config file paths ($GLF_CONFIG names the file explicitly)
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
-	viper.AddConfigPath(configDir)
-	viper.AddConfigPath(".") // Also check current directory
+	if explicit := os.Getenv(paths.EnvConfigFile); explicit != "" {
+		viper.SetConfigFile(paths.ExpandHome(explicit))
+	} else {
+		viper.AddConfigPath(paths.ConfigDir())
+		viper.AddConfigPath(".") // Also check current directory
+	}
 
-	// Set environment variable prefix
+	// Set environment variable prefix: every option can be set as GLF_<KEY> with dots
+	// replaced by underscores (GLF_GITLAB_URL, GLF_CACHE_DIR), with or without a config file
 	viper.SetEnvPrefix("GLF")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
+	if err := bindEnv("", reflect.TypeOf(Config{})); err != nil {
+		return nil, fmt.Errorf("error binding environment variables: %w", err)
+	}
 
 	// Set defaults
-	cacheDir := filepath.Join(os.Getenv("HOME"), ".cache", "glf")
-	viper.SetDefault("cache.dir", cacheDir)
+	viper.SetDefault("cache.dir", paths.CacheDir())
 	viper.SetDefault("gitlab.timeout", 30)     // Default 30 seconds timeout
 	viper.SetDefault("gitlab.concurrency", 10) // Default 10 concurrent API requests
+	viper.SetDefault("gitlab.token_expiry_warn_days", 14)
+	viper.SetDefault("gitlab.max_retries", 3)
+	viper.SetDefault("gitlab.retry_backoff", 1.0)
+	viper.SetDefault("history.half_life_days", history.DefaultHalfLifeDays)
+	viper.SetDefault("history.max_age_days", history.DefaultMaxAgeDays)
+	viper.SetDefault("history.context_ranking", false)
+	viper.SetDefault("history.enabled", true)
+	viper.SetDefault("tui.query_history", DefaultQueryHistory)
+	viper.SetDefault("search.go_margin", DefaultGoMargin)
+	viper.SetDefault("new_project.visibility", "private")
+	viper.SetDefault("new_project.init_readme", true)
 
 	// Try to read config file (it's okay if it doesn't exist)
 	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok && !errors.Is(err, fs.ErrNotExist) {
 			return nil, fmt.Errorf("error reading config file: %w", err)
 		}
 	}
@@ -65,24 +329,56 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
-	// Expand tilde in cache dir path
+	// Expand tilde in cache dir and workspace paths
 	if cfg.Cache.Dir != "" {
 		cfg.Cache.Dir = expandPath(cfg.Cache.Dir)
+		cfg.Cache.Unwritable = !dirWritable(cfg.Cache.Dir)
+	}
+	if cfg.Cache.StateDir != "" {
+		cfg.Cache.StateDir = expandPath(cfg.Cache.StateDir)
+	}
+	if cfg.WorkspaceDir != "" {
+		cfg.WorkspaceDir = expandPath(cfg.WorkspaceDir)
+	}
+	if cfg.Annotations.File != "" {
+		cfg.Annotations.File = expandPath(cfg.Annotations.File)
 	}
+	cfg.Annotations.Project = strings.Trim(strings.TrimSpace(cfg.Annotations.Project), "/")
 
 	// Validate required fields
 	if cfg.GitLab.URL == "" {
 		return nil, ErrConfigNotFound
 	}
-	if cfg.GitLab.Token == "" {
-		return nil, ErrConfigNotFound
-	}
 
 	// Validate timeout
 	if cfg.GitLab.Timeout <= 0 {
 		cfg.GitLab.Timeout = 30
 	}
 
+	// Validate retry policy
+	if cfg.GitLab.MaxRetries < 0 {
+		cfg.GitLab.MaxRetries = 3
+	}
+	if cfg.GitLab.RetryBackoff <= 0 {
+		cfg.GitLab.RetryBackoff = 1
+	}
+	if cfg.GitLab.SyncTimeout < 0 {
+		cfg.GitLab.SyncTimeout = 0
+	}
+
+	// Resolve the token from the configured backend (an explicit token or GLF_GITLAB_TOKEN wins)
+	cfg.GitLab.TokenBackend = tokenstore.Normalize(cfg.GitLab.TokenBackend, cfg.GitLab.TokenCommand)
+	if requireToken {
+		if cfg.GitLab.Token == "" {
+			if err := resolveToken(&cfg, !offline); err != nil {
+				return nil, err
+			}
+		}
+		if cfg.GitLab.Token == "" {
+			return nil, ErrConfigNotFound
+		}
+	}
+
 	// Validate concurrency
 	if cfg.GitLab.Concurrency <= 0 {
 		cfg.GitLab.Concurrency = 10
@@ -90,35 +386,232 @@ func Load() (*Config, error) {
 		cfg.GitLab.Concurrency = 50
 	}
 
+	// Validate token expiry warning window
+	if cfg.GitLab.TokenExpiryWarnDays < 0 {
+		cfg.GitLab.TokenExpiryWarnDays = 0
+	}
+
+	// Validate history decay settings
+	if cfg.History.HalfLifeDays <= 0 {
+		cfg.History.HalfLifeDays = history.DefaultHalfLifeDays
+	}
+	if cfg.History.MaxAgeDays <= 0 {
+		cfg.History.MaxAgeDays = history.DefaultMaxAgeDays
+	}
+	if cfg.History.RetentionDays < 0 {
+		cfg.History.RetentionDays = 0
+	}
+
+	// Validate query history size
+	if cfg.TUI.QueryHistory < 0 {
+		cfg.TUI.QueryHistory = DefaultQueryHistory
+	}
+
+	// Validate archived penalty
+	if cfg.Scoring.ArchivedPenalty < 0 {
+		cfg.Scoring.ArchivedPenalty = 0
+	}
+	if cfg.Scoring.ForkPenalty < 0 {
+		cfg.Scoring.ForkPenalty = 0
+	}
+
+	// Validate search fuzziness
+	cfg.Search.Fuzziness = strings.ToLower(strings.TrimSpace(cfg.Search.Fuzziness))
+	if !slices.Contains([]string{"", "auto", "0", "1", "2"}, cfg.Search.Fuzziness) {
+		return nil, fmt.Errorf("search.fuzziness must be auto, 0, 1 or 2, got %q", cfg.Search.Fuzziness)
+	}
+	if cfg.Search.Fuzziness == "" {
+		cfg.Search.Fuzziness = "auto"
+	}
+
+	if cfg.Search.GoMargin < 0 {
+		cfg.Search.GoMargin = 0
+	}
+	if cfg.Search.PinAfter < 0 {
+		cfg.Search.PinAfter = 0
+	}
+
+	// Validate the automatic sync policy
+	cfg.Sync.Auto = strings.ToLower(strings.TrimSpace(cfg.Sync.Auto))
+	if cfg.Sync.Auto == "" {
+		cfg.Sync.Auto = SyncAutoOnStart
+	}
+	if !slices.Contains([]string{SyncAutoOff, SyncAutoOnStart, SyncAutoInterval}, cfg.Sync.Auto) {
+		return nil, fmt.Errorf("sync.auto must be off, on-start or interval, got %q", cfg.Sync.Auto)
+	}
+	cfg.Sync.Interval = strings.TrimSpace(cfg.Sync.Interval)
+	if cfg.Sync.Interval != "" {
+		interval, err := time.ParseDuration(cfg.Sync.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("sync.interval must be a duration like 30m or 2h, got %q", cfg.Sync.Interval)
+		}
+		if interval < minSyncInterval {
+			return nil, fmt.Errorf("sync.interval must be at least %s, got %s", minSyncInterval, interval)
+		}
+	}
+
+	// gitlab.remote_fallback moved to search.remote_fallback, which wins when both are set
+	if cfg.GitLab.RemoteFallback && !viper.IsSet("search.remote_fallback") {
+		cfg.Search.RemoteFallback = true
+	}
+
+	// Validate new project visibility
+	cfg.NewProject.Visibility = strings.ToLower(strings.TrimSpace(cfg.NewProject.Visibility))
+	if cfg.NewProject.Visibility != "" && !slices.Contains(visibilities, cfg.NewProject.Visibility) {
+		return nil, fmt.Errorf("new_project.visibility must be one of %s, got %q", strings.Join(visibilities, ", "), cfg.NewProject.Visibility)
+	}
+
+	// Validate openers
+	if err := validateOpeners(cfg.Openers); err != nil {
+		return nil, err
+	}
+
+	// Validate URL templates
+	if err := target.ValidateTemplates(cfg.URLTemplates); err != nil {
+		return nil, fmt.Errorf("url_templates: %w", err)
+	}
+
 	return &cfg, nil
 }
 
+// validateOpeners checks names, keys and command templates of the openers
+// Keys are normalized to lower case and need a modifier (or are function keys), so they can't
+// shadow typing in the search prompt
+func validateOpeners(openers []OpenerConfig) error {
+	names := make(map[string]bool, len(openers))
+	keys := make(map[string]bool, len(openers))
+	for i := range openers {
+		o := &openers[i]
+		o.Name = strings.TrimSpace(o.Name)
+		if o.Name == "" {
+			return fmt.Errorf("openers[%d]: name is required", i)
+		}
+		if names[o.Name] {
+			return fmt.Errorf("openers: duplicate name %q", o.Name)
+		}
+		names[o.Name] = true
+		if err := opener.Validate(o.Command); err != nil {
+			return fmt.Errorf("opener %q: %w", o.Name, err)
+		}
+
+		o.Key = strings.ToLower(strings.TrimSpace(o.Key))
+		if o.Key == "" {
+			continue
+		}
+		if !openerKeyPattern.MatchString(o.Key) {
+			return fmt.Errorf("opener %q: key %q needs a modifier (e.g. alt+t, ctrl+t) or a function key (f1-f12)", o.Name, o.Key)
+		}
+		if keys[o.Key] {
+			return fmt.Errorf("openers: key %q is used twice", o.Key)
+		}
+		keys[o.Key] = true
+	}
+	return nil
+}
+
+// openerKeyPattern matches the keys openers can be bound to (bubbletea key names)
+var openerKeyPattern = regexp.MustCompile(`^((ctrl|alt)\+\S+|alt\+ctrl\+\S+|f([1-9]|1[0-2]))$`)
+
+// bindEnv registers every config key of t with viper so GLF_* variables are seen by Unmarshal
+// (AutomaticEnv alone only applies to keys that also appear in the file or the defaults)
+func bindEnv(prefix string, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			if err := bindEnv(key+".", field.Type); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := viper.BindEnv(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envToken is the environment variable that overrides gitlab.token
+const envToken = "GLF_GITLAB_TOKEN"
+
+// storedToken returns the token written in the config file (empty if there is none)
+func storedToken() string {
+	v := viper.New()
+	v.SetConfigFile(Path())
+	if err := v.ReadInConfig(); err != nil {
+		return ""
+	}
+	return v.GetString("gitlab.token")
+}
+
+// IsReadOnly reports whether the cache is shared and read-only (cache.read_only, or not writable)
+func (c *CacheConfig) IsReadOnly() bool {
+	return c.ReadOnly || c.Unwritable
+}
+
+// GetStateDir returns the directory for per-user state: history, TUI session, username and merge requests
+// Defaults to the cache directory; with a read-only cache and no state_dir, the default cache location
+// is used when it differs from the shared one
+func (c *CacheConfig) GetStateDir() string {
+	if c.StateDir != "" {
+		return c.StateDir
+	}
+	if c.IsReadOnly() {
+		if dir := paths.CacheDir(); dir != c.Dir {
+			return dir
+		}
+	}
+	return c.Dir
+}
+
+// dirWritable reports whether files can be created in dir (a missing dir is created by the first sync)
+func dirWritable(dir string) bool {
+	probe, err := os.CreateTemp(dir, ".glf-write-test-*")
+	if err != nil {
+		return errors.Is(err, fs.ErrNotExist)
+	}
+	_ = probe.Close()
+	_ = os.Remove(probe.Name())
+	return true
+}
+
 // GetTimeout returns the GitLab API timeout as time.Duration
 func (c *GitLabConfig) GetTimeout() time.Duration {
 	return time.Duration(c.Timeout) * time.Second
 }
 
+// GetRetryBackoff returns the wait before the first retry as time.Duration
+func (c *GitLabConfig) GetRetryBackoff() time.Duration {
+	return time.Duration(c.RetryBackoff * float64(time.Second))
+}
+
+// GetSyncTimeout returns the limit for a whole sync as time.Duration (0 = none)
+func (c *GitLabConfig) GetSyncTimeout() time.Duration {
+	return time.Duration(c.SyncTimeout) * time.Second
+}
+
 // expandPath expands ~ to home directory in paths
 func expandPath(path string) string {
-	if len(path) > 0 && path[0] == '~' {
-		home := os.Getenv("HOME")
-		if len(path) == 1 {
-			return home
-		}
-		return filepath.Join(home, path[1:])
-	}
-	return path
+	return paths.ExpandHome(path)
+}
+
+// Path returns the location of config.yaml (see internal/paths for the resolution order)
+func Path() string {
+	return paths.ConfigFile()
 }
 
 // EnsureConfigDir ensures the config directory exists
 func EnsureConfigDir() error {
-	configDir := filepath.Clean(filepath.Join(os.Getenv("HOME"), ".config", "glf"))
-	return os.MkdirAll(configDir, 0750)
+	return os.MkdirAll(filepath.Clean(filepath.Dir(Path())), 0750)
 }
 
 // ExampleConfigPath returns the path where the example config should be created
 func ExampleConfigPath() string {
-	return filepath.Join(os.Getenv("HOME"), ".config", "glf", "config.yaml.example")
+	return Path() + ".example"
 }
 
 // IsExcluded checks if a project path matches any excluded pattern
@@ -167,6 +660,42 @@ func (c *Config) RemoveExclusion(pattern string) error {
 	return c.Save()
 }
 
+// IsPinned checks if a project path is pinned
+func (c *Config) IsPinned(projectPath string) bool {
+	for _, pinned := range c.PinnedPaths {
+		if pinned == projectPath {
+			return true
+		}
+	}
+	return false
+}
+
+// AddPin pins a project path if it isn't pinned already
+func (c *Config) AddPin(projectPath string) error {
+	if c.IsPinned(projectPath) {
+		return nil // Already pinned
+	}
+
+	c.PinnedPaths = append(c.PinnedPaths, projectPath)
+	return c.Save()
+}
+
+// RemovePin unpins a project path
+func (c *Config) RemovePin(projectPath string) error {
+	if !c.IsPinned(projectPath) {
+		return nil // Not pinned
+	}
+
+	newPinned := make([]string, 0, len(c.PinnedPaths))
+	for _, p := range c.PinnedPaths {
+		if p != projectPath {
+			newPinned = append(newPinned, p)
+		}
+	}
+	c.PinnedPaths = newPinned
+	return c.Save()
+}
+
 // RemoveExclusionForPath removes any exclusion pattern that matches the given path
 func (c *Config) RemoveExclusionForPath(projectPath string) error {
 	newExcluded := make([]string, 0, len(c.ExcludedPaths))
@@ -203,8 +732,7 @@ func (c *Config) RemoveExclusionForPath(projectPath string) error {
 
 // Save saves the current configuration to file
 func (c *Config) Save() error {
-	configDir := filepath.Join(os.Getenv("HOME"), ".config", "glf")
-	configPath := filepath.Join(configDir, "config.yaml")
+	configPath := Path()
 
 	// Ensure config dir exists
 	if err := EnsureConfigDir(); err != nil {
@@ -213,14 +741,155 @@ func (c *Config) Save() error {
 
 	// Set all config values in viper
 	viper.Set("gitlab.url", c.GitLab.URL)
-	viper.Set("gitlab.token", c.GitLab.Token)
+	// Tokens from an external backend are never written back to config.yaml, an OAuth access token
+	// stays with its credentials, and a token from GLF_GITLAB_TOKEN leaves the file's token as it is
+	if tokenstore.IsExternal(tokenstore.Normalize(c.GitLab.TokenBackend, c.GitLab.TokenCommand)) {
+		viper.Set("gitlab.token", "")
+	} else if c.GitLab.OAuth {
+		viper.Set("gitlab.token", storedToken())
+	} else if env := os.Getenv(envToken); env != "" && env == c.GitLab.Token {
+		viper.Set("gitlab.token", storedToken())
+	} else {
+		viper.Set("gitlab.token", c.GitLab.Token)
+	}
 	viper.Set("gitlab.timeout", c.GitLab.Timeout)
 	viper.Set("gitlab.concurrency", c.GitLab.Concurrency)
+	if c.GitLab.MaxRetries > 0 && c.GitLab.MaxRetries != 3 {
+		viper.Set("gitlab.max_retries", c.GitLab.MaxRetries)
+	}
+	if c.GitLab.RetryBackoff > 0 && c.GitLab.RetryBackoff != 1 {
+		viper.Set("gitlab.retry_backoff", c.GitLab.RetryBackoff)
+	}
+	if c.GitLab.SyncTimeout > 0 {
+		viper.Set("gitlab.sync_timeout", c.GitLab.SyncTimeout)
+	}
+	viper.Set("gitlab.token_expiry_warn_days", c.GitLab.TokenExpiryWarnDays)
+	if c.GitLab.TokenBackend != "" && c.GitLab.TokenBackend != tokenstore.BackendConfig {
+		viper.Set("gitlab.token_backend", c.GitLab.TokenBackend)
+	}
+	if c.GitLab.TokenCommand != "" {
+		viper.Set("gitlab.token_command", c.GitLab.TokenCommand)
+	}
+	if c.GitLab.OAuthClientID != "" {
+		viper.Set("gitlab.oauth_client_id", c.GitLab.OAuthClientID)
+	}
+	if c.GitLab.Insights {
+		viper.Set("gitlab.insights", true)
+	}
+	if c.GitLab.SyncUsers {
+		viper.Set("gitlab.sync_users", true)
+	}
+	if c.GitLab.WebhookSecret != "" {
+		viper.Set("gitlab.webhook_secret", c.GitLab.WebhookSecret)
+	}
 	viper.Set("cache.dir", c.Cache.Dir)
+	if c.Cache.StateDir != "" {
+		viper.Set("cache.state_dir", c.Cache.StateDir)
+	}
+	if c.Cache.ReadOnly {
+		viper.Set("cache.read_only", true)
+	}
+	if c.History.HalfLifeDays > 0 && c.History.HalfLifeDays != history.DefaultHalfLifeDays {
+		viper.Set("history.half_life_days", c.History.HalfLifeDays)
+	}
+	if c.History.MaxAgeDays > 0 && c.History.MaxAgeDays != history.DefaultMaxAgeDays {
+		viper.Set("history.max_age_days", c.History.MaxAgeDays)
+	}
+	if c.History.ContextRanking {
+		viper.Set("history.context_ranking", true)
+	}
+	if c.History.Algorithm != "" && c.History.Algorithm != history.AlgorithmDecay {
+		viper.Set("history.algorithm", c.History.Algorithm)
+	}
+	if c.History.RetentionDays > 0 {
+		viper.Set("history.retention_days", c.History.RetentionDays)
+	}
+	if c.Scoring.ArchivedPenalty > 0 {
+		viper.Set("scoring.archived_penalty", c.Scoring.ArchivedPenalty)
+	}
+	if c.Scoring.ForkPenalty > 0 {
+		viper.Set("scoring.fork_penalty", c.Scoring.ForkPenalty)
+	}
+	if c.Search.Fuzziness != "" && c.Search.Fuzziness != "auto" {
+		viper.Set("search.fuzziness", c.Search.Fuzziness)
+	}
+	if c.Search.GoMargin != DefaultGoMargin {
+		viper.Set("search.go_margin", c.Search.GoMargin)
+	}
+	if c.Search.PinAfter > 0 {
+		viper.Set("search.pin_after", c.Search.PinAfter)
+	}
+	if c.Search.RemoteFallback {
+		viper.Set("search.remote_fallback", true)
+	}
+	if c.Search.CollapseForks {
+		viper.Set("search.collapse_forks", true)
+	}
+	if c.TUI.Avatars {
+		viper.Set("tui.avatars", true)
+	}
+	if c.TUI.ImageProtocol != "" {
+		viper.Set("tui.image_protocol", c.TUI.ImageProtocol)
+	}
+	if c.TUI.Resume {
+		viper.Set("tui.resume", true)
+	}
+	if c.TUI.QueryHistory > 0 && c.TUI.QueryHistory != DefaultQueryHistory {
+		viper.Set("tui.query_history", c.TUI.QueryHistory)
+	}
 	viper.Set("excluded_paths", c.ExcludedPaths)
+	viper.Set("pinned_paths", c.PinnedPaths)
+	if c.WorkspaceDir != "" {
+		viper.Set("workspace_dir", c.WorkspaceDir)
+	}
+	if c.WorkspaceLayout != "" {
+		viper.Set("workspace_layout", c.WorkspaceLayout)
+	}
+	if c.Editor != "" {
+		viper.Set("editor", c.Editor)
+	}
+	if c.BrowserCommand != "" {
+		viper.Set("browser_command", c.BrowserCommand)
+	}
+	if len(c.Openers) > 0 {
+		viper.Set("openers", c.Openers)
+	}
+	if len(c.URLTemplates) > 0 {
+		viper.Set("url_templates", c.URLTemplates)
+	}
+	if c.Annotations.File != "" {
+		viper.Set("annotations.file", c.Annotations.File)
+	}
+	if c.Annotations.Project != "" {
+		viper.Set("annotations.project", c.Annotations.Project)
+	}
+	if c.Annotations.Path != "" {
+		viper.Set("annotations.path", c.Annotations.Path)
+	}
+	if c.Annotations.Ref != "" {
+		viper.Set("annotations.ref", c.Annotations.Ref)
+	}
+	if c.Sync.Auto != "" && c.Sync.Auto != SyncAutoOnStart {
+		viper.Set("sync.auto", c.Sync.Auto)
+	}
+	if c.Sync.Interval != "" {
+		viper.Set("sync.interval", c.Sync.Interval)
+	}
+	if c.Sync.MembershipOnly {
+		viper.Set("sync.membership_only", true)
+	}
 
-	// Write to file
-	if err := viper.WriteConfigAs(configPath); err != nil {
+	// Write to file, without gitlab.remote_fallback: Load already moved it to search.remote_fallback,
+	// and viper cannot unset a key read from the file
+	settings := viper.AllSettings()
+	if gitlab, ok := settings["gitlab"].(map[string]any); ok {
+		delete(gitlab, "remote_fallback")
+	}
+	out := viper.New()
+	if err := out.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := out.WriteConfigAs(configPath); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -245,17 +914,168 @@ gitlab:
   # Required scopes: read_api, read_repository
   token: "your-gitlab-token-here"
 
-  # API timeout in seconds (optional, defaults to 30)
+  # Keep the token out of this file (optional, defaults to "config")
+  # keychain       - macOS Keychain (security add-generic-password -s glf -a <url> -w <token>)
+  # secret-service - Linux Secret Service (secret-tool store --label glf service glf account <url>)
+  # wincred        - Windows Credential Manager (resource "glf", user name = <url>)
+  # command        - run token_command and use its output
+  # token_backend: keychain
+  # token_command: "pass show gitlab/token"
+
+  # Sign in with glf --login instead of a Personal Access Token (GitLab 17.2+)
+  # Application ID of an OAuth application (User Settings > Applications: not confidential,
+  # scope read_api); the tokens are kept by token_backend, or in oauth.json next to this file
+  # oauth_client_id: "your-application-id"
+
+  # Timeout of each API request in seconds (optional, defaults to 30)
   timeout: 30
 
+  # Retries of a failed API request: timeouts and dropped connections while reading,
+  # rate limiting (429) and server errors (5xx) (optional, defaults to 3, 0 disables)
+  # Each retry waits about twice as long as the previous one, starting at retry_backoff
+  # seconds (optional, defaults to 1). Raise both on a flaky VPN
+  max_retries: 3
+  retry_backoff: 1
+
+  # Give up a sync after this many seconds, retries included (optional, defaults to 0 = no limit)
+  # sync_timeout: 900
+
   # Max concurrent API requests (optional, defaults to 10, max 50)
   # Increase for fast GitLab instances with many projects
   concurrency: 10
 
+  # Warn during sync when the token expires within this many days
+  # (optional, defaults to 14, set to 0 to disable)
+  token_expiry_warn_days: 14
+
+  # Fetch open merge request and issue counts for member projects during sync
+  # Shown as !MRs #issues counters in the TUI (optional, defaults to false)
+  # Costs one extra GraphQL request per 20 changed member projects
+  insights: false
+
+  # Fetch the instance's active users during sync for 'glf --users' (optional, defaults to false)
+  # Costs one API call per 100 users
+  sync_users: false
+
+  # Secret token of the system hook that 'glf --listen' receives events from (optional)
+  # Requests without a matching X-Gitlab-Token header are rejected; without it --listen only
+  # listens on localhost
+  # webhook_secret: ""
+
 cache:
   # Cache directory (optional, defaults to ~/.cache/glf)
   dir: "~/.cache/glf"
 
+  # Shared team cache: point dir at a read-only index (NFS mount, container image) that is
+  # synced elsewhere. glf never syncs into it; history and session go to state_dir
+  # (optional, defaults to dir, or to ~/.cache/glf when dir is read-only)
+  # A dir that cannot be written is treated as read-only automatically
+  # read_only: true
+  # state_dir: "~/.local/state/glf"
+
+history:
+  # Days for a selection's weight in ranking to decay to 50% (optional, defaults to 30)
+  # Lower values favor recent habits, higher values favor long-term ones
+  half_life_days: 30
+
+  # Selections older than this many days are forgotten (optional, defaults to 100)
+  max_age_days: 100
+
+  # Favor projects you usually open at this time of day (optional, defaults to false)
+  # Selections made in the same part of the day (night, morning, afternoon, evening)
+  # and on the same kind of day (weekday or weekend) as now count more
+  context_ranking: false
+
+  # How a selection's weight depends on its age (optional, defaults to decay)
+  # decay: smooth exponential decay with half_life_days
+  # frecency: Firefox-style buckets (last 4 days 1.0, 2 weeks 0.7, month 0.5, 3 months 0.3, older 0.1)
+  algorithm: decay
+
+  # Record which projects you open (optional, defaults to true)
+  # false stops tracking entirely: nothing is written to the history file, while
+  # existing entries still rank until cleared with 'glf history clear'
+  enabled: true
+
+  # Purge selections older than this many days on every save (optional, defaults to 0)
+  # 0 keeps them until max_age_days. Use it to keep less history on disk than max_age_days
+  retention_days: 0
+
+scoring:
+  # Subtracted from the score of archived projects, so they rank below active ones
+  # when shown (Ctrl+H in the TUI, always in --json) (optional, defaults to 0)
+  # History scores are capped at 30, so 100 always ranks archived projects last
+  archived_penalty: 0
+
+  # Subtracted from the score of forks, so searching a service name ranks the upstream
+  # project above forks with the same name (optional, defaults to 0)
+  # Forks are those synced with a forked_from_project; 1 is usually enough
+  fork_penalty: 0
+
+search:
+  # Typos tolerated per query word, as an edit distance (optional, defaults to auto)
+  # auto: none for words of up to 2 characters, 1 up to 5 characters, 2 for longer words
+  # (serach-service still finds search-service); 0 turns typo tolerance off
+  fuzziness: auto
+
+  # Search GitLab live when a query has no local results (optional, defaults to false)
+  # Finds projects created since the last sync; selecting one adds it to the index
+  # (gitlab.remote_fallback in older configs is still read when this is not set)
+  remote_fallback: false
+
+  # How far the best -g/--go match must lead the runner-up, as a fraction of its score
+  # (optional, defaults to 0.1); closer results list the top 3 and ask which one to open
+  # (or choose in the TUI). 0 always opens the best match; --non-interactive never asks
+  go_margin: 0.1
+
+  # Pin a query to a project once you chose it for that query this many times (optional,
+  # 0 disables): -g/--go then opens it without ranking. 'glf --pins' lists the pinned
+  # queries and --unpin-query removes one
+  pin_after: 0
+
+  # List forks under their upstream project instead of as separate results (optional,
+  # defaults to false); Alt+F expands them in the TUI and JSON lists them in "forks".
+  # Your own forks (projects you are a member of) and pinned forks stay listed
+  collapse_forks: false
+
+tui:
+  # Show project avatars in the README preview (Alt+V) (optional, defaults to false)
+  # Drawn with the kitty, iTerm2 or sixel image protocol; other terminals get colored initials
+  avatars: false
+
+  # Image protocol: auto (detect from the terminal), kitty, iterm2, sixel or none (optional)
+  image_protocol: auto
+
+  # Restore the last query, filter toggles and selected result on every start,
+  # like --resume (optional, defaults to false)
+  resume: false
+
+  # Past search queries kept for recall with Up/Down on an empty search input
+  # (optional, defaults to 100; 0 disables the query history)
+  query_history: 100
+
+sync:
+  # When glf syncs on its own (optional, defaults to on-start)
+  # on-start: when the finder starts, and in the background when the cache is over an hour old
+  # interval: also every sync.interval while the finder runs, to keep a long session fresh
+  # off: only explicit syncs (--sync, Ctrl+R) and the first sync of an empty cache,
+  #      e.g. on metered connections
+  auto: on-start
+
+  # Time between syncs with auto: interval (optional, defaults to 30m; at least 1m)
+  interval: 30m
+
+  # Fetch only the projects you are a member of (optional, defaults to false)
+  # Much faster on large instances; other projects are then not searchable
+  membership_only: false
+
+new_project:
+  # Defaults of projects created with 'glf --new group/name'
+  # Visibility: private, internal or public (optional, defaults to private)
+  visibility: private
+
+  # Create the project with a README, so it can be cloned right away (optional, defaults to true)
+  init_readme: true
+
 # Excluded project paths (supports wildcards)
 # Use Ctrl+X in TUI to add current project
 # Use Ctrl+H to toggle showing excluded projects
@@ -264,6 +1084,53 @@ excluded_paths:
   # - "legacy/*"
   # - "namespace/specific-project"
 
+# Pinned projects (exact paths) are always shown at the top of results
+# Use Alt+P in TUI or 'glf --pin group/project' to add
+pinned_paths:
+  # - "platform/api-gateway"
+
+# Directory with local clones: 'glf --cd' prints a clone's path, 'glf --edit' and
+# Alt+E open it in your editor (offering to clone it first)
+# Clones elsewhere in the workspace are found by their origin remote
+# workspace_dir: "~/src"
+# workspace_layout: nested  # nested: workspace_dir/group/project, flat: workspace_dir/project
+
+# Editor command for --edit (optional, defaults to $VISUAL, $EDITOR, then VS Code's 'code')
+# editor: "code -n"
+
+# Command that opens URLs (optional; "%s" is replaced by the URL, otherwise it is appended)
+# Defaults to $BROWSER, then open (macOS), xdg-open (Linux), wslview or PowerShell (WSL),
+# rundll32 or PowerShell (Windows)
+# browser_command: "firefox --new-tab %s"
+
+# Openers: commands run on a result instead of opening the browser (optional)
+# Run one with --open-with <name>, or press its key in the finder (the TUI keeps running)
+# Placeholders: {{url}}, {{ssh_url}}, {{http_url}}, {{path}}, {{name}}, {{namespace}}
+# openers:
+#   - name: tmux
+#     key: alt+t
+#     command: "tmux new-window 'git clone {{ssh_url}}'"
+#   - name: tower
+#     key: alt+w
+#     command: "open -a Tower {{url}}"
+
+# URL templates: override the project URLs glf opens and prints, per --target (optional)
+# "project" is the project page; {{url}} is the URL GitLab would open, and the opener
+# placeholders are available too
+# url_templates:
+#   project: "{{url}}/-/tree/main"
+#   mrs: "https://sso.example.com/login?next={{url}}"
+
+# Project labels from annotations.yaml, shown in the finder and filtered with label:tier-1
+# The file maps project or group paths to labels:
+#   platform/api: [tier-1, owned-by:payments]
+#   legacy: [deprecated]
+# annotations:
+#   file: "~/.config/glf/annotations.yaml"  # local file (this is the default location)
+#   project: "platform/handbook"            # fetch a team-shared file from GitLab during sync
+#   path: "glf/annotations.yaml"            # file in that project (defaults to annotations.yaml)
+#   ref: main                               # branch or tag (defaults to the default branch)
+
 # Environment variables can also be used:
 # GLF_GITLAB_URL=https://gitlab.example.com
 # GLF_GITLAB_TOKEN=your-token-here
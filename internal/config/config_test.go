@@ -1,12 +1,17 @@
 package config
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/igusev/glf/internal/oauth"
+	"github.com/igusev/glf/internal/tokenstore"
 	"github.com/spf13/viper"
 )
 
@@ -226,6 +231,53 @@ func TestAddExclusion(t *testing.T) {
 	}
 }
 
+func TestPins(t *testing.T) {
+	tmpHome := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", originalHome)
+	os.Setenv("HOME", tmpHome)
+
+	cfg := &Config{
+		GitLab: GitLabConfig{URL: "https://gitlab.test.com", Token: "test-token", Timeout: 30},
+		Cache:  CacheConfig{Dir: filepath.Join(tmpHome, ".cache", "glf")},
+	}
+
+	if err := cfg.AddPin("team/api"); err != nil {
+		t.Fatalf("AddPin failed: %v", err)
+	}
+	if err := cfg.AddPin("team/web"); err != nil {
+		t.Fatalf("AddPin failed: %v", err)
+	}
+	if err := cfg.AddPin("team/api"); err != nil {
+		t.Fatalf("AddPin duplicate failed: %v", err)
+	}
+
+	if len(cfg.PinnedPaths) != 2 || cfg.PinnedPaths[0] != "team/api" || cfg.PinnedPaths[1] != "team/web" {
+		t.Errorf("PinnedPaths = %v, want [team/api team/web]", cfg.PinnedPaths)
+	}
+	if !cfg.IsPinned("team/web") || cfg.IsPinned("team/other") {
+		t.Error("IsPinned returned unexpected result")
+	}
+
+	// Pins survive a reload
+	viper.Reset()
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.PinnedPaths) != 2 {
+		t.Errorf("Loaded PinnedPaths = %v, want 2 entries", loaded.PinnedPaths)
+	}
+
+	if err := cfg.RemovePin("team/api"); err != nil {
+		t.Fatalf("RemovePin failed: %v", err)
+	}
+	if cfg.IsPinned("team/api") || len(cfg.PinnedPaths) != 1 {
+		t.Errorf("PinnedPaths after RemovePin = %v", cfg.PinnedPaths)
+	}
+}
+
 func TestRemoveExclusion(t *testing.T) {
 	tmpHome, err := os.MkdirTemp("", "glf-config-test-*")
 	if err != nil {
@@ -451,6 +503,252 @@ func TestLoadDefaults(t *testing.T) {
 	if cfg.Cache.Dir != expectedCacheDir {
 		t.Errorf("Default cache dir = %q, want %q", cfg.Cache.Dir, expectedCacheDir)
 	}
+
+	if cfg.GitLab.TokenExpiryWarnDays != 14 {
+		t.Errorf("Default token expiry warning = %d, want 14", cfg.GitLab.TokenExpiryWarnDays)
+	}
+
+	if cfg.History.HalfLifeDays != 30 || cfg.History.MaxAgeDays != 100 {
+		t.Errorf("Default history decay = %g/%g, want 30/100", cfg.History.HalfLifeDays, cfg.History.MaxAgeDays)
+	}
+}
+
+func TestLoadHistoryDecay(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+
+	configContent := `gitlab:
+  url: "https://gitlab.test.com"
+  token: "test-token"
+history:
+  half_life_days: 7.5
+  max_age_days: -1
+  context_ranking: true
+  algorithm: frecency
+`
+	os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644)
+
+	viper.Reset()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.History.HalfLifeDays != 7.5 {
+		t.Errorf("HalfLifeDays = %g, want 7.5", cfg.History.HalfLifeDays)
+	}
+	// Invalid max age falls back to the default
+	if cfg.History.MaxAgeDays != 100 {
+		t.Errorf("Invalid max_age_days should fallback to 100, got %g", cfg.History.MaxAgeDays)
+	}
+	if !cfg.History.ContextRanking {
+		t.Error("Expected context_ranking to be enabled")
+	}
+	if cfg.History.Algorithm != "frecency" {
+		t.Errorf("Algorithm = %q, want frecency", cfg.History.Algorithm)
+	}
+}
+
+func TestLoadRetryPolicy(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	baseConfig := `gitlab:
+  url: "https://gitlab.test.com"
+  token: "test-token"
+`
+	os.WriteFile(configPath, []byte(baseConfig), 0644)
+
+	viper.Reset()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.GitLab.MaxRetries != 3 || cfg.GitLab.GetRetryBackoff() != time.Second || cfg.GitLab.GetSyncTimeout() != 0 {
+		t.Errorf("Expected 3 retries, 1s backoff and no sync timeout by default, got %d, %v, %v",
+			cfg.GitLab.MaxRetries, cfg.GitLab.GetRetryBackoff(), cfg.GitLab.GetSyncTimeout())
+	}
+
+	os.WriteFile(configPath, []byte(baseConfig+`  max_retries: 0
+  retry_backoff: 2.5
+  sync_timeout: 600
+`), 0644)
+
+	viper.Reset()
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.GitLab.MaxRetries != 0 {
+		t.Errorf("MaxRetries = %d, want 0 (retries disabled)", cfg.GitLab.MaxRetries)
+	}
+	if got := cfg.GitLab.GetRetryBackoff(); got != 2500*time.Millisecond {
+		t.Errorf("GetRetryBackoff() = %v, want 2.5s", got)
+	}
+	if got := cfg.GitLab.GetSyncTimeout(); got != 10*time.Minute {
+		t.Errorf("GetSyncTimeout() = %v, want 10m", got)
+	}
+
+	// Invalid values fall back to the defaults
+	os.WriteFile(configPath, []byte(baseConfig+`  max_retries: -1
+  retry_backoff: -2
+  sync_timeout: -5
+`), 0644)
+
+	viper.Reset()
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.GitLab.MaxRetries != 3 || cfg.GitLab.RetryBackoff != 1 || cfg.GitLab.SyncTimeout != 0 {
+		t.Errorf("Expected invalid values to fall back to defaults, got %+v", cfg.GitLab)
+	}
+}
+
+func TestLoadHistoryPrivacy(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	baseConfig := `gitlab:
+  url: "https://gitlab.test.com"
+  token: "test-token"
+`
+	os.WriteFile(configPath, []byte(baseConfig), 0644)
+
+	viper.Reset()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.History.Enabled {
+		t.Error("Expected history to be enabled by default")
+	}
+	if cfg.History.RetentionDays != 0 {
+		t.Errorf("RetentionDays = %g, want 0 by default", cfg.History.RetentionDays)
+	}
+
+	os.WriteFile(configPath, []byte(baseConfig+`history:
+  enabled: false
+  retention_days: 14
+`), 0644)
+
+	viper.Reset()
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.History.Enabled {
+		t.Error("Expected history to be disabled")
+	}
+	if cfg.History.RetentionDays != 14 {
+		t.Errorf("RetentionDays = %g, want 14", cfg.History.RetentionDays)
+	}
+
+	// Negative retention keeps selections until max_age_days
+	os.WriteFile(configPath, []byte(baseConfig+`history:
+  retention_days: -3
+`), 0644)
+
+	viper.Reset()
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.History.RetentionDays != 0 {
+		t.Errorf("Negative retention_days should fallback to 0, got %g", cfg.History.RetentionDays)
+	}
+}
+
+func TestLoadTUI(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+
+	configContent := `gitlab:
+  url: "https://gitlab.test.com"
+  token: "test-token"
+tui:
+  avatars: true
+  image_protocol: sixel
+  resume: true
+`
+	os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644)
+
+	viper.Reset()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if !cfg.TUI.Avatars || cfg.TUI.ImageProtocol != "sixel" || !cfg.TUI.Resume {
+		t.Errorf("TUI config = %+v, want avatars with sixel and resume", cfg.TUI)
+	}
+}
+
+func TestLoadTokenCommand(t *testing.T) {
+	tmpHome := t.TempDir()
+
+	originalHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", originalHome)
+	os.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+
+	configContent := `gitlab:
+  url: "https://gitlab.test.com"
+  token_command: "echo command-$((1+1))"
+`
+	configPath := filepath.Join(configDir, "config.yaml")
+	os.WriteFile(configPath, []byte(configContent), 0644)
+
+	viper.Reset()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.GitLab.Token != "command-2" {
+		t.Errorf("Token = %q, want %q", cfg.GitLab.Token, "command-2")
+	}
+	if cfg.GitLab.TokenBackend != "command" {
+		t.Errorf("TokenBackend = %q, want %q", cfg.GitLab.TokenBackend, "command")
+	}
+
+	// Saving must not write the resolved token to config.yaml
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if strings.Contains(string(data), "command-2") {
+		t.Errorf("Saved config leaks resolved token:\n%s", data)
+	}
+	if !strings.Contains(string(data), "token_command") {
+		t.Errorf("Saved config lost token_command:\n%s", data)
+	}
+
+	// A failing command is reported instead of falling back to the setup wizard
+	os.WriteFile(configPath, []byte("gitlab:\n  url: \"https://gitlab.test.com\"\n  token_command: \"exit 1\"\n"), 0644)
+	viper.Reset()
+	if _, err := Load(); err == nil || err == ErrConfigNotFound {
+		t.Errorf("Expected token command error, got: %v", err)
+	}
 }
 
 func TestLoadMissingRequired(t *testing.T) {
@@ -506,16 +804,74 @@ func TestLoadMissingRequired(t *testing.T) {
 }
 
 func TestLoadEnvOverride(t *testing.T) {
-	t.Skip("Viper's AutomaticEnv() requires explicit BindEnv() for nested keys - skipping env override test")
+	viper.Reset()
+	defer viper.Reset()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("gitlab:\n  url: https://gitlab.file.example\n  token: file-token\n  timeout: 10\ncache:\n  dir: "+filepath.Join(tmpDir, "file-cache")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GLF_CONFIG", configPath)
+	t.Setenv("GLF_GITLAB_TOKEN", "env-token")
+	t.Setenv("GLF_GITLAB_INSIGHTS", "true")
+	t.Setenv("GLF_CACHE_DIR", filepath.Join(tmpDir, "env-cache"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.GitLab.URL != "https://gitlab.file.example" || cfg.GitLab.Timeout != 10 {
+		t.Errorf("Expected URL and timeout from the file, got %q, %d", cfg.GitLab.URL, cfg.GitLab.Timeout)
+	}
+	if cfg.GitLab.Token != "env-token" || !cfg.GitLab.Insights {
+		t.Errorf("Expected token and insights from the environment, got %q, %v", cfg.GitLab.Token, cfg.GitLab.Insights)
+	}
+	if want := filepath.Join(tmpDir, "env-cache"); cfg.Cache.Dir != want {
+		t.Errorf("Expected cache dir %q, got %q", want, cfg.Cache.Dir)
+	}
+
+	// Saving keeps the file's token instead of persisting the environment one
+	if err := cfg.AddExclusion("group/legacy"); err != nil {
+		t.Fatalf("AddExclusion failed: %v", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "env-token") || !strings.Contains(string(data), "file-token") {
+		t.Errorf("Expected the file token to be kept, got:\n%s", data)
+	}
+}
+
+// TestLoad_EnvOnly tests that glf is configured by GLF_* variables alone (CI runners)
+func TestLoad_EnvOnly(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	tmpDir := t.TempDir()
+	t.Setenv("GLF_CONFIG", filepath.Join(tmpDir, "missing.yaml"))
+	t.Setenv("GLF_GITLAB_URL", "https://gitlab.ci.example")
+	t.Setenv("GLF_GITLAB_TOKEN", "ci-token")
+	t.Setenv("GLF_CACHE_DIR", filepath.Join(tmpDir, "cache"))
+	t.Setenv("GLF_EXCLUDED_PATHS", "archive/old,sandbox")
 
-	// Note: Environment variable override for nested keys in viper requires:
-	// viper.BindEnv("gitlab.url", "GLF_GITLAB_URL")
-	// viper.BindEnv("gitlab.token", "GLF_GITLAB_TOKEN")
-	// etc.
-	//
-	// Since config.go only uses AutomaticEnv() without explicit binding,
-	// environment variables won't override file config for nested keys.
-	// This is a known viper limitation/design choice.
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.GitLab.URL != "https://gitlab.ci.example" || cfg.GitLab.Token != "ci-token" {
+		t.Errorf("Expected URL and token from the environment, got %q, %q", cfg.GitLab.URL, cfg.GitLab.Token)
+	}
+	if want := filepath.Join(tmpDir, "cache"); cfg.Cache.Dir != want {
+		t.Errorf("Expected cache dir %q, got %q", want, cfg.Cache.Dir)
+	}
+	if cfg.GitLab.Timeout != 30 || cfg.GitLab.Concurrency != 10 {
+		t.Errorf("Expected default timeout and concurrency, got %d, %d", cfg.GitLab.Timeout, cfg.GitLab.Concurrency)
+	}
+	if len(cfg.ExcludedPaths) != 2 || cfg.ExcludedPaths[1] != "sandbox" {
+		t.Errorf("Expected excluded paths from the environment, got %v", cfg.ExcludedPaths)
+	}
 }
 
 func TestEnsureConfigDir(t *testing.T) {
@@ -701,6 +1057,7 @@ func TestLoadExpandTildePath(t *testing.T) {
   token: "test-token"
 cache:
   dir: "~/.cache/glf"
+workspace_dir: "~/src"
 `
 	configPath := filepath.Join(configDir, "config.yaml")
 	os.WriteFile(configPath, []byte(configContent), 0644)
@@ -717,6 +1074,9 @@ cache:
 	if cfg.Cache.Dir != expectedCacheDir {
 		t.Errorf("Cache dir = %q, want %q (tilde should be expanded)", cfg.Cache.Dir, expectedCacheDir)
 	}
+	if want := filepath.Join(tmpHome, "src"); cfg.WorkspaceDir != want {
+		t.Errorf("Workspace dir = %q, want %q (tilde should be expanded)", cfg.WorkspaceDir, want)
+	}
 }
 
 func TestSave_WriteConfigError(t *testing.T) {
@@ -981,3 +1341,636 @@ func TestCreateExampleConfig_EnsureConfigDirError(t *testing.T) {
 		t.Error("CreateExampleConfig should fail when EnsureConfigDir cannot create directory")
 	}
 }
+
+// Tests set HOME to a temp dir and expect the default ~/.config/glf layout,
+// so location overrides from the developer's environment must not leak in
+func TestMain(m *testing.M) {
+	for _, key := range []string{"GLF_CONFIG", "GLF_CONFIG_DIR", "GLF_CACHE_DIR", "XDG_CONFIG_HOME", "XDG_CACHE_HOME"} {
+		os.Unsetenv(key)
+	}
+	os.Exit(m.Run())
+}
+
+// TestLoad_ConfigFileEnv tests that GLF_CONFIG selects the config file and
+// XDG_CACHE_HOME moves the default cache directory
+func TestLoad_ConfigFileEnv(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "work.yaml")
+	if err := os.WriteFile(configPath, []byte("gitlab:\n  url: https://gitlab.work.example\n  token: tok\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GLF_CONFIG", configPath)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(tmpDir, "xdg-cache"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.GitLab.URL != "https://gitlab.work.example" {
+		t.Errorf("Expected URL from GLF_CONFIG, got %q", cfg.GitLab.URL)
+	}
+	if want := filepath.Join(tmpDir, "xdg-cache", "glf"); cfg.Cache.Dir != want {
+		t.Errorf("Expected cache dir %q, got %q", want, cfg.Cache.Dir)
+	}
+	if Path() != configPath {
+		t.Errorf("Expected Path() %q, got %q", configPath, Path())
+	}
+}
+
+// TestLoad_ConfigFileEnvMissing tests that a missing GLF_CONFIG file means "not configured"
+func TestLoad_ConfigFileEnvMissing(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	t.Setenv("GLF_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, err := Load(); err != ErrConfigNotFound {
+		t.Errorf("Expected ErrConfigNotFound, got %v", err)
+	}
+}
+
+func TestLoadOAuth(t *testing.T) {
+	refreshed, requests := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_ = r.ParseForm()
+		if r.URL.Path != "/oauth/token" || r.Form.Get("refresh_token") != "refresh-1" || r.Form.Get("client_id") != "app" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		refreshed++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"access-2","refresh_token":"refresh-2","expires_in":7200}`))
+	}))
+	defer server.Close()
+
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+	os.WriteFile(configPath, []byte("gitlab:\n  url: \""+server.URL+"\"\n  oauth_client_id: app\n"), 0644)
+
+	// Without a login there is no token
+	viper.Reset()
+	if _, err := Load(); err != ErrConfigNotFound {
+		t.Fatalf("Load() error = %v, want ErrConfigNotFound", err)
+	}
+	viper.Reset()
+	if cfg, err := LoadWithoutToken(); err != nil || cfg.GitLab.OAuthClientID != "app" {
+		t.Fatalf("LoadWithoutToken() = %+v, %v", cfg, err)
+	}
+
+	// An expired access token is refreshed and the rotated credentials are stored
+	expired := &oauth.Credentials{ClientID: "app", AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresAt: time.Now().Add(-time.Hour)}
+	if err := SaveOAuth(tokenstore.BackendConfig, server.URL, expired); err != nil {
+		t.Fatalf("SaveOAuth() error = %v", err)
+	}
+	viper.Reset()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.GitLab.Token != "access-2" || !cfg.GitLab.OAuth || refreshed != 1 {
+		t.Errorf("Token = %q, OAuth = %v, refreshes = %d; want access-2, true, 1", cfg.GitLab.Token, cfg.GitLab.OAuth, refreshed)
+	}
+	data, _ := os.ReadFile(OAuthPath())
+	if creds, ok := oauth.Decode(string(data)); !ok || creds.RefreshToken != "refresh-2" {
+		t.Errorf("Stored credentials = %s, want the rotated refresh token", data)
+	}
+
+	// A fresh token is used as is, and Save keeps it out of config.yaml
+	viper.Reset()
+	if cfg, err = Load(); err != nil || cfg.GitLab.Token != "access-2" || refreshed != 1 {
+		t.Errorf("Load() = %q, %v with %d refreshes; want access-2 without another refresh", cfg.GitLab.Token, err, refreshed)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if data, _ := os.ReadFile(configPath); strings.Contains(string(data), "access-2") || !strings.Contains(string(data), "oauth_client_id: app") {
+		t.Errorf("Saved config:\n%s\nwant oauth_client_id without the access token", data)
+	}
+
+	// Offline, an expired access token is used as is, without any request
+	seen := requests
+	expired = &oauth.Credentials{ClientID: "app", AccessToken: "access-2", RefreshToken: "refresh-1", ExpiresAt: time.Now().Add(-time.Hour)}
+	if err := SaveOAuth(tokenstore.BackendConfig, server.URL, expired); err != nil {
+		t.Fatalf("SaveOAuth() error = %v", err)
+	}
+	viper.Reset()
+	if cfg, err = LoadOffline(); err != nil || cfg.GitLab.Token != "access-2" || !cfg.GitLab.OAuth || requests != seen {
+		t.Errorf("LoadOffline() = %q, %v with %d requests; want the cached access-2 without a request", cfg.GitLab.Token, err, requests-seen)
+	}
+	if data, _ := os.ReadFile(OAuthPath()); !strings.Contains(string(data), "refresh-1") {
+		t.Errorf("Stored credentials = %s, want them unchanged offline", data)
+	}
+}
+
+func TestLoadReadOnlyCache(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("GLF_CACHE_DIR", "")
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+	sharedDir := filepath.Join(tmpHome, "shared")
+	os.MkdirAll(sharedDir, 0755)
+
+	write := func(extra string) *Config {
+		t.Helper()
+		content := "gitlab:\n  url: https://gitlab.test.com\n  token: t\ncache:\n  dir: " + sharedDir + "\n" + extra
+		os.WriteFile(configPath, []byte(content), 0644)
+		viper.Reset()
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		return cfg
+	}
+
+	// A writable cache keeps its state next to the index
+	cfg := write("")
+	if cfg.Cache.IsReadOnly() || cfg.Cache.GetStateDir() != sharedDir {
+		t.Errorf("Writable cache: read-only = %v, state dir = %q", cfg.Cache.IsReadOnly(), cfg.Cache.GetStateDir())
+	}
+	if entries, _ := os.ReadDir(sharedDir); len(entries) != 0 {
+		t.Errorf("Expected the writability check to leave no files, found %d", len(entries))
+	}
+
+	// A read-only cache moves state to the default cache location, or to state_dir
+	cfg = write("  read_only: true\n")
+	if want := filepath.Join(tmpHome, ".cache", "glf"); !cfg.Cache.IsReadOnly() || cfg.Cache.GetStateDir() != want {
+		t.Errorf("Read-only cache: read-only = %v, state dir = %q, want %q", cfg.Cache.IsReadOnly(), cfg.Cache.GetStateDir(), want)
+	}
+	cfg = write("  read_only: true\n  state_dir: ~/state\n")
+	if want := filepath.Join(tmpHome, "state"); cfg.Cache.GetStateDir() != want {
+		t.Errorf("state_dir = %q, want %q", cfg.Cache.GetStateDir(), want)
+	}
+
+	// Both settings survive Save
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), "read_only: true") || !strings.Contains(string(data), "state_dir:") {
+		t.Errorf("Saved config lost the shared cache settings:\n%s", data)
+	}
+}
+
+func TestLoadNewProject(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	load := func(extra string) (*Config, error) {
+		t.Helper()
+		content := "gitlab:\n  url: https://gitlab.test.com\n  token: t\n" + extra
+		os.WriteFile(configPath, []byte(content), 0644)
+		viper.Reset()
+		return Load()
+	}
+
+	// Private projects with a README by default
+	cfg, err := load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.NewProject.Visibility != "private" || !cfg.NewProject.InitReadme {
+		t.Errorf("Defaults = %+v, want private with README", cfg.NewProject)
+	}
+
+	cfg, err = load("new_project:\n  visibility: Internal\n  init_readme: false\n")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.NewProject.Visibility != "internal" || cfg.NewProject.InitReadme {
+		t.Errorf("NewProject = %+v, want internal without README", cfg.NewProject)
+	}
+
+	if _, err := load("new_project:\n  visibility: secret\n"); err == nil || !strings.Contains(err.Error(), "new_project.visibility") {
+		t.Errorf("Expected an invalid visibility error, got %v", err)
+	}
+}
+
+func TestLoadScoring(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	for _, tt := range []struct {
+		value string
+		want  float64
+	}{
+		{"100", 100},
+		{"-3", 0}, // Negative penalties are ignored
+	} {
+		content := "gitlab:\n  url: https://gitlab.test.com\n  token: t\nscoring:\n  archived_penalty: " + tt.value + "\n  fork_penalty: " + tt.value + "\n"
+		os.WriteFile(configPath, []byte(content), 0644)
+		viper.Reset()
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Scoring.ArchivedPenalty != tt.want {
+			t.Errorf("archived_penalty %s = %v, want %v", tt.value, cfg.Scoring.ArchivedPenalty, tt.want)
+		}
+		if cfg.Scoring.ForkPenalty != tt.want {
+			t.Errorf("fork_penalty %s = %v, want %v", tt.value, cfg.Scoring.ForkPenalty, tt.want)
+		}
+	}
+}
+
+func TestLoadOpeners(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	baseConfig := `gitlab:
+  url: "https://gitlab.test.com"
+  token: "test-token"
+openers:
+`
+	os.WriteFile(configPath, []byte(baseConfig+`  - name: tmux
+    key: Alt+T
+    command: "tmux new-window 'git clone {{ssh_url}}'"
+  - name: tower
+    command: "open -a Tower {{url}}"
+`), 0644)
+
+	viper.Reset()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Openers) != 2 {
+		t.Fatalf("Expected 2 openers, got %+v", cfg.Openers)
+	}
+	if o := cfg.Openers[0]; o.Name != "tmux" || o.Key != "alt+t" || o.Command != "tmux new-window 'git clone {{ssh_url}}'" {
+		t.Errorf("Unexpected opener %+v (keys are lower-cased)", o)
+	}
+	if cfg.Openers[1].Key != "" {
+		t.Errorf("Expected the second opener to have no key, got %q", cfg.Openers[1].Key)
+	}
+
+	invalid := map[string]string{
+		"unknown placeholder":  "  - name: a\n    command: \"git clone {{ssh}}\"\n",
+		"key without modifier": "  - name: a\n    key: t\n    command: \"echo {{url}}\"\n",
+		"duplicate name":       "  - name: a\n    command: \"echo\"\n  - name: a\n    command: \"echo\"\n",
+		"duplicate key":        "  - name: a\n    key: f2\n    command: \"echo\"\n  - name: b\n    key: f2\n    command: \"echo\"\n",
+		"missing name":         "  - command: \"echo\"\n",
+	}
+	for name, openers := range invalid {
+		os.WriteFile(configPath, []byte(baseConfig+openers), 0644)
+		viper.Reset()
+		if _, err := Load(); err == nil {
+			t.Errorf("%s: expected Load to fail", name)
+		}
+	}
+}
+
+func TestLoadURLTemplates(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	baseConfig := `gitlab:
+  url: "https://gitlab.test.com"
+  token: "test-token"
+url_templates:
+`
+	os.WriteFile(configPath, []byte(baseConfig+`  project: "{{url}}/-/tree/main"
+  mrs: "https://sso.test.com/?next={{url}}"
+`), 0644)
+
+	viper.Reset()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.URLTemplates) != 2 || cfg.URLTemplates["project"] != "{{url}}/-/tree/main" {
+		t.Errorf("Unexpected url_templates %v", cfg.URLTemplates)
+	}
+
+	for name, templates := range map[string]string{
+		"unknown target":      "  nonexistent: \"{{url}}\"\n",
+		"unknown placeholder": "  project: \"{{branch}}\"\n",
+	} {
+		os.WriteFile(configPath, []byte(baseConfig+templates), 0644)
+		viper.Reset()
+		if _, err := Load(); err == nil {
+			t.Errorf("%s: expected Load to fail", name)
+		}
+	}
+}
+
+func TestLoadSearchFuzziness(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	baseConfig := `gitlab:
+  url: "https://gitlab.test.com"
+  token: "test-token"
+`
+	tests := []struct {
+		search  string
+		want    int
+		wantErr bool
+	}{
+		{"", FuzzinessAuto, false},
+		{"search:\n  fuzziness: AUTO\n", FuzzinessAuto, false},
+		{"search:\n  fuzziness: 0\n", 0, false},
+		{"search:\n  fuzziness: 2\n", 2, false},
+		{"search:\n  fuzziness: 3\n", 0, true},
+		{"search:\n  fuzziness: high\n", 0, true},
+	}
+	for _, tt := range tests {
+		os.WriteFile(configPath, []byte(baseConfig+tt.search), 0644)
+		viper.Reset()
+		cfg, err := Load()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.search)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: Load failed: %v", tt.search, err)
+		}
+		if got := cfg.Search.GetFuzziness(); got != tt.want {
+			t.Errorf("%q: GetFuzziness() = %d, want %d", tt.search, got, tt.want)
+		}
+	}
+}
+
+func TestLoadSearchRemoteFallback(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"gitlab:\n  url: https://gitlab.test.com\n  token: t\n", false},
+		{"gitlab:\n  url: https://gitlab.test.com\n  token: t\nsearch:\n  remote_fallback: true\n", true},
+		// Former location, still read
+		{"gitlab:\n  url: https://gitlab.test.com\n  token: t\n  remote_fallback: true\n", true},
+		// ...unless the new key is set
+		{"gitlab:\n  url: https://gitlab.test.com\n  token: t\n  remote_fallback: true\nsearch:\n  remote_fallback: false\n", false},
+	}
+	for _, tt := range tests {
+		os.WriteFile(configPath, []byte(tt.content), 0644)
+		viper.Reset()
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Search.RemoteFallback != tt.want {
+			t.Errorf("%q: RemoteFallback = %v, want %v", tt.content, cfg.Search.RemoteFallback, tt.want)
+		}
+	}
+
+	// Save moves the former location to the new one
+	os.WriteFile(configPath, []byte("gitlab:\n  url: https://gitlab.test.com\n  token: t\n  remote_fallback: true\n"), 0644)
+	viper.Reset()
+	legacy, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := legacy.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	viper.Reset()
+	if _, err := Load(); err != nil || viper.IsSet("gitlab.remote_fallback") || !viper.GetBool("search.remote_fallback") {
+		t.Errorf("Expected gitlab.remote_fallback saved as search.remote_fallback, got %v", err)
+	}
+
+	// Save writes the new location
+	viper.Reset()
+	cfg := &Config{GitLab: GitLabConfig{URL: "https://gitlab.test.com", Token: "t"}, Search: SearchConfig{RemoteFallback: true, CollapseForks: true}}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, _ := os.ReadFile(configPath)
+	if !strings.Contains(string(data), "remote_fallback: true") {
+		t.Errorf("Expected search.remote_fallback in the saved config:\n%s", data)
+	}
+	viper.Reset()
+	if loaded, err := Load(); err != nil || !loaded.Search.RemoteFallback || !loaded.Search.CollapseForks {
+		t.Errorf("Expected the saved fallback and collapse_forks to load, got %v", err)
+	}
+}
+
+func TestLoadSearchGoMargin(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	tests := []struct {
+		content string
+		want    float64
+	}{
+		{"gitlab:\n  url: https://gitlab.test.com\n  token: t\n", DefaultGoMargin},
+		{"gitlab:\n  url: https://gitlab.test.com\n  token: t\nsearch:\n  go_margin: 0.25\n", 0.25},
+		{"gitlab:\n  url: https://gitlab.test.com\n  token: t\nsearch:\n  go_margin: 0\n", 0},
+		{"gitlab:\n  url: https://gitlab.test.com\n  token: t\nsearch:\n  go_margin: -1\n", 0},
+	}
+	for _, tt := range tests {
+		os.WriteFile(configPath, []byte(tt.content), 0644)
+		viper.Reset()
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Search.GoMargin != tt.want {
+			t.Errorf("%q: GoMargin = %v, want %v", tt.content, cfg.Search.GoMargin, tt.want)
+		}
+	}
+
+	// A disabled check survives a save
+	viper.Reset()
+	cfg := &Config{GitLab: GitLabConfig{URL: "https://gitlab.test.com", Token: "t"}, Search: SearchConfig{GoMargin: 0}}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	viper.Reset()
+	if loaded, err := Load(); err != nil || loaded.Search.GoMargin != 0 {
+		t.Errorf("Expected the saved margin 0 to load, got %+v, %v", loaded.Search, err)
+	}
+}
+
+func TestLoadQueryHistory(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	baseConfig := `gitlab:
+  url: "https://gitlab.test.com"
+  token: "test-token"
+`
+	tests := []struct {
+		tui  string
+		want int
+	}{
+		{"", DefaultQueryHistory},
+		{"tui:\n  query_history: 0\n", 0},
+		{"tui:\n  query_history: 500\n", 500},
+		{"tui:\n  query_history: -1\n", DefaultQueryHistory},
+	}
+	for _, tt := range tests {
+		os.WriteFile(configPath, []byte(baseConfig+tt.tui), 0644)
+		viper.Reset()
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("%q: Load failed: %v", tt.tui, err)
+		}
+		if cfg.TUI.QueryHistory != tt.want {
+			t.Errorf("%q: QueryHistory = %d, want %d", tt.tui, cfg.TUI.QueryHistory, tt.want)
+		}
+	}
+}
+
+func TestLoadAnnotations(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+
+	// Defaults: annotations.yaml next to config.yaml, nothing fetched
+	os.WriteFile(configPath, []byte("gitlab:\n  url: https://gitlab.test.com\n  token: t\n"), 0644)
+	viper.Reset()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got, want := cfg.Annotations.GetFile(), filepath.Join(configDir, "annotations.yaml"); got != want {
+		t.Errorf("GetFile() = %q, want %q", got, want)
+	}
+	if cfg.Annotations.Project != "" || cfg.Annotations.GetPath() != DefaultAnnotationsPath {
+		t.Errorf("Unexpected annotation defaults: %+v", cfg.Annotations)
+	}
+
+	os.WriteFile(configPath, []byte(`gitlab:
+  url: https://gitlab.test.com
+  token: t
+annotations:
+  file: ~/team/labels.yaml
+  project: /platform/handbook/
+  path: glf/annotations.yaml
+  ref: main
+`), 0644)
+	viper.Reset()
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := AnnotationsConfig{
+		File:    filepath.Join(tmpHome, "team", "labels.yaml"),
+		Project: "platform/handbook",
+		Path:    "glf/annotations.yaml",
+		Ref:     "main",
+	}
+	if cfg.Annotations != want {
+		t.Errorf("Annotations = %+v, want %+v", cfg.Annotations, want)
+	}
+
+	// The settings survive a save
+	viper.Reset()
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	viper.Reset()
+	if loaded, err := Load(); err != nil || loaded.Annotations != want {
+		t.Errorf("Expected the saved annotations to load, got %+v, %v", loaded.Annotations, err)
+	}
+}
+
+func TestLoadSync(t *testing.T) {
+	tmpHome := t.TempDir()
+	t.Setenv("HOME", tmpHome)
+
+	configDir := filepath.Join(tmpHome, ".config", "glf")
+	os.MkdirAll(configDir, 0755)
+	configPath := filepath.Join(configDir, "config.yaml")
+	load := func(sync string) (*Config, error) {
+		t.Helper()
+		os.WriteFile(configPath, []byte("gitlab:\n  url: https://gitlab.test.com\n  token: t\n"+sync), 0644)
+		viper.Reset()
+		return Load()
+	}
+
+	// Defaults: sync on start, no interval
+	cfg, err := load("")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Sync.Auto != SyncAutoOnStart || !cfg.Sync.Enabled() || cfg.Sync.GetInterval() != 0 {
+		t.Errorf("Unexpected sync defaults: %+v", cfg.Sync)
+	}
+
+	cfg, err = load("sync:\n  auto: Interval\n")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Sync.Auto != SyncAutoInterval || cfg.Sync.GetInterval() != DefaultSyncInterval {
+		t.Errorf("Expected the default interval, got %+v (%s)", cfg.Sync, cfg.Sync.GetInterval())
+	}
+
+	cfg, err = load("sync:\n  auto: interval\n  interval: 2h\n")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Sync.GetInterval() != 2*time.Hour {
+		t.Errorf("GetInterval() = %s, want 2h", cfg.Sync.GetInterval())
+	}
+
+	cfg, err = load("sync:\n  auto: off\n  interval: 2h\n")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Sync.Enabled() || cfg.Sync.GetInterval() != 0 {
+		t.Errorf("Expected no automatic sync, got %+v", cfg.Sync)
+	}
+
+	cfg, err = load("sync:\n  membership_only: true\n")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Sync.MembershipOnly {
+		t.Errorf("Expected membership_only, got %+v", cfg.Sync)
+	}
+
+	for _, invalid := range []string{"sync:\n  auto: hourly\n", "sync:\n  interval: soon\n", "sync:\n  interval: 10s\n"} {
+		if _, err := load(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/igusev/glf/internal/oauth"
+	"github.com/igusev/glf/internal/tokenstore"
+)
+
+// oauthFileName holds the glf --login credentials when the token backend is config
+const oauthFileName = "oauth.json"
+
+// OAuthPath returns the file with the glf --login credentials for the config token backend
+func OAuthPath() string {
+	return filepath.Join(filepath.Dir(Path()), oauthFileName)
+}
+
+// SaveOAuth stores the credentials of glf --login for account (the GitLab URL)
+// Secret stores keep them as a JSON document; the config backend writes oauth.json next to config.yaml
+func SaveOAuth(backend, account string, creds *oauth.Credentials) error {
+	data, err := creds.Encode()
+	if err != nil {
+		return err
+	}
+	switch {
+	case tokenstore.IsWritable(backend):
+		return tokenstore.Store(backend, account, data)
+	case !tokenstore.IsExternal(backend):
+		if err := EnsureConfigDir(); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
+		if err := os.WriteFile(OAuthPath(), []byte(data), 0600); err != nil {
+			return fmt.Errorf("failed to write OAuth credentials: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("token backend %q cannot store OAuth credentials (use config, keychain, secret-service or wincred)", backend)
+	}
+}
+
+// readCredentials returns the token from the external backend, or the stored OAuth credentials
+// Both are empty when the config backend has no oauth.json
+func readCredentials(cfg *Config) (token string, creds *oauth.Credentials, err error) {
+	if tokenstore.IsExternal(cfg.GitLab.TokenBackend) {
+		secret, err := tokenstore.Get(cfg.GitLab.TokenBackend, cfg.GitLab.URL, cfg.GitLab.TokenCommand)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to resolve GitLab token: %w", err)
+		}
+		if creds, ok := oauth.Decode(secret); ok {
+			return "", creds, nil
+		}
+		return secret, nil, nil
+	}
+
+	data, err := os.ReadFile(OAuthPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read OAuth credentials: %w", err)
+	}
+	creds, ok := oauth.Decode(string(data))
+	if !ok {
+		return "", nil, fmt.Errorf("invalid OAuth credentials in %s (run 'glf --login' again)", OAuthPath())
+	}
+	return "", creds, nil
+}
+
+// resolveToken sets the token from the configured backend or from the credentials of glf --login
+// An expiring OAuth access token is refreshed and the new credentials are stored back;
+// without refresh (offline) it is used as is
+func resolveToken(cfg *Config, refresh bool) error {
+	token, creds, err := readCredentials(cfg)
+	if err != nil || creds == nil {
+		cfg.GitLab.Token = token
+		return err
+	}
+
+	if refresh && creds.Expired(time.Now()) {
+		client := oauth.NewClient(cfg.GitLab.URL, creds.ClientID, cfg.GitLab.GetTimeout())
+		refreshed, err := client.Refresh(context.Background(), creds.RefreshToken)
+		if err != nil {
+			// Refresh tokens are single-use: another glf process may have refreshed first
+			if _, latest, readErr := readCredentials(cfg); readErr == nil && latest != nil && !latest.Expired(time.Now()) {
+				refreshed, err = latest, nil
+			}
+		} else {
+			err = SaveOAuth(cfg.GitLab.TokenBackend, cfg.GitLab.URL, refreshed)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to refresh the OAuth token: %w (run 'glf --login' again)", err)
+		}
+		creds = refreshed
+	}
+
+	cfg.GitLab.Token = creds.AccessToken
+	cfg.GitLab.OAuth = true
+	return nil
+}
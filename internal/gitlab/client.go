@@ -1,8 +1,15 @@
 package gitlab
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -22,6 +29,12 @@ type GitLabClient interface {
 	GetCurrentUsername() (string, error)
 }
 
+// ProjectStreamer is implemented by clients that can hand out fetched projects page by page,
+// so callers can process (e.g. index) them while the remaining pages are still downloading
+type ProjectStreamer interface {
+	StreamAllProjects(since *time.Time, membership bool, onPage func([]model.Project)) ([]model.Project, error)
+}
+
 // Client wraps the GitLab API client and implements GitLabClient interface
 type Client struct {
 	client      *gitlab.Client
@@ -29,21 +42,45 @@ type Client struct {
 	// Cached project sets — if set, FetchAllProjects skips API calls for these
 	cachedStarred map[string]bool
 	cachedMember  map[string]bool
+	// Server version detected by TestConnection (nil = unknown, all features assumed)
+	version *ServerVersion
+	// Transport enforcing the deadline set by SetDeadline
+	transport *deadlineTransport
+	// Transport making GET requests conditional (see SetETagCache)
+	etags *etagTransport
+}
+
+// bearerAuth sends the token as an OAuth Bearer token instead of PRIVATE-TOKEN
+var bearerAuth bool
+
+// SetBearerAuth selects OAuth Bearer authentication for clients created by New
+// (the token is an OAuth access token from glf --login)
+func SetBearerAuth(enabled bool) {
+	bearerAuth = enabled
 }
 
 // New creates a new GitLab client with timeout and concurrency settings
+// The timeout applies to each request attempt; failed requests are retried
+// according to the retry policy (see SetRetryPolicy)
 func New(url, token string, timeout time.Duration, concurrency ...int) (*Client, error) {
 	// Create HTTP client with timeout
+	etags := &etagTransport{base: &traceTransport{base: http.DefaultTransport}}
+	transport := &deadlineTransport{base: etags}
 	httpClient := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: transport,
 	}
 
 	// Create GitLab client with custom HTTP client
-	client, err := gitlab.NewClient(
-		token,
+	newClient := gitlab.NewClient
+	if bearerAuth {
+		newClient = gitlab.NewOAuthClient
+	}
+	options := append([]gitlab.ClientOptionFunc{
 		gitlab.WithBaseURL(url),
 		gitlab.WithHTTPClient(httpClient),
-	)
+	}, retryOptions()...)
+	client, err := newClient(token, options...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
 	}
@@ -53,7 +90,7 @@ func New(url, token string, timeout time.Duration, concurrency ...int) (*Client,
 		maxConc = concurrency[0]
 	}
 
-	return &Client{client: client, concurrency: maxConc}, nil
+	return &Client{client: client, concurrency: maxConc, transport: transport, etags: etags}, nil
 }
 
 // SetCachedProjectSets provides pre-loaded starred/member sets to avoid API calls
@@ -73,6 +110,13 @@ func (c *Client) LastProjectSets() (starred, member map[string]bool) {
 // If membership is true, only fetches projects where the user is a member
 // Returns a slice of Project structs containing path, name, starred, and archived information
 func (c *Client) FetchAllProjects(since *time.Time, membership bool) ([]model.Project, error) {
+	return c.StreamAllProjects(since, membership, nil)
+}
+
+// StreamAllProjects is FetchAllProjects calling onPage with each page as soon as it arrives
+// Pages are delivered one at a time but not necessarily in order; the returned slice is ordered.
+// onPage may be nil. On error some pages may already have been delivered
+func (c *Client) StreamAllProjects(since *time.Time, membership bool, onPage func([]model.Project)) ([]model.Project, error) {
 	// Step 0: Fetch or reuse cached starred/member project sets — in parallel when both are needed
 	var starredProjects map[string]bool
 	var memberProjects map[string]bool
@@ -141,14 +185,19 @@ func (c *Client) FetchAllProjects(since *time.Time, membership bool) ([]model.Pr
 			PerPage: 100, // Maximum allowed per page
 			Page:    1,
 		},
+		// Full project view: the simple view leaves out forked_from_project, archived and visibility
 		Membership: gitlab.Ptr(membership), // Filter by membership based on parameter
-		Simple:     gitlab.Ptr(true),       // Return only limited fields for performance
 	}
 
 	// Add incremental sync filter if timestamp provided
 	if since != nil && !since.IsZero() {
 		opt.LastActivityAfter = since
-		logger.Debug("Incremental sync: fetching projects changed after %s", since.Format(time.RFC3339))
+		if c.hasETagCache() {
+			// The same URLs for every sync within the hour: pages that didn't change since are
+			// answered 304 from the ETag cache (refetching an hour of activity costs nothing)
+			opt.LastActivityAfter = gitlab.Ptr(since.Truncate(time.Hour))
+		}
+		logger.Debug("Incremental sync: fetching projects changed after %s", opt.LastActivityAfter.Format(time.RFC3339))
 	} else {
 		logger.Debug("Full sync: fetching all projects")
 	}
@@ -172,16 +221,12 @@ func (c *Client) FetchAllProjects(since *time.Time, membership bool) ([]model.Pr
 			// - If membership=true, all returned projects are member projects
 			// - If membership=false, check the memberProjects map
 			isMember := membership || memberProjects[project.PathWithNamespace]
-			result = append(result, model.Project{
-				Path:        project.PathWithNamespace,
-				Name:        project.Name,
-				Description: project.Description,
-				Starred:     starredProjects[project.PathWithNamespace],
-				Archived:    project.Archived,
-				Member:      isMember,
-			})
+			result = append(result, newProject(project, starredProjects[project.PathWithNamespace], isMember))
 		}
 		logger.Debug("Single page, fetched %d projects", len(result))
+		if onPage != nil && len(result) > 0 {
+			onPage(result)
+		}
 		return result, nil
 	}
 
@@ -216,14 +261,7 @@ func (c *Client) FetchAllProjects(since *time.Time, membership bool) ([]model.Pr
 		// - If membership=true, all returned projects are member projects
 		// - If membership=false, check the memberProjects map
 		isMember := membership || memberProjects[project.PathWithNamespace]
-		firstPageProjs = append(firstPageProjs, model.Project{
-			Path:        project.PathWithNamespace,
-			Name:        project.Name,
-			Description: project.Description,
-			Starred:     starredProjects[project.PathWithNamespace],
-			Archived:    project.Archived,
-			Member:      isMember,
-		})
+		firstPageProjs = append(firstPageProjs, newProject(project, starredProjects[project.PathWithNamespace], isMember))
 	}
 	results <- pageResult{page: 1, projects: firstPageProjs, err: nil}
 	atomic.AddInt32(&completedPages, 1)
@@ -245,7 +283,6 @@ func (c *Client) FetchAllProjects(since *time.Time, membership bool) ([]model.Pr
 					Page:    int64(pageNum),
 				},
 				Membership:        gitlab.Ptr(membership), // Preserve membership filter
-				Simple:            gitlab.Ptr(true),       // Return only limited fields
 				LastActivityAfter: opt.LastActivityAfter,  // Preserve incremental filter
 			}
 
@@ -263,14 +300,7 @@ func (c *Client) FetchAllProjects(since *time.Time, membership bool) ([]model.Pr
 				// - If membership=true, all returned projects are member projects
 				// - If membership=false, check the memberProjects map
 				isMember := membership || memberProjects[project.PathWithNamespace]
-				projs = append(projs, model.Project{
-					Path:        project.PathWithNamespace,
-					Name:        project.Name,
-					Description: project.Description,
-					Starred:     starredProjects[project.PathWithNamespace],
-					Archived:    project.Archived,
-					Member:      isMember,
-				})
+				projs = append(projs, newProject(project, starredProjects[project.PathWithNamespace], isMember))
 			}
 
 			results <- pageResult{page: pageNum, projects: projs, err: nil}
@@ -294,6 +324,9 @@ func (c *Client) FetchAllProjects(since *time.Time, membership bool) ([]model.Pr
 			return nil, fmt.Errorf("failed to fetch page %d: %w", result.page, result.err)
 		}
 		pageMap[result.page] = result.projects
+		if onPage != nil && len(result.projects) > 0 {
+			onPage(result.projects)
+		}
 	}
 
 	// Step 4: Combine results in correct order
@@ -308,12 +341,53 @@ func (c *Client) FetchAllProjects(since *time.Time, membership bool) ([]model.Pr
 	return allProjects, nil
 }
 
+// newProject converts a GitLab project; starred and member come from the user's project sets
+func newProject(project *gitlab.Project, starred, member bool) model.Project {
+	var lastActivityAt time.Time
+	if project.LastActivityAt != nil {
+		lastActivityAt = project.LastActivityAt.UTC()
+	}
+	var forkedFrom string
+	if project.ForkedFromProject != nil {
+		forkedFrom = project.ForkedFromProject.PathWithNamespace
+	}
+	return model.Project{
+		ID:             project.ID,
+		Path:           project.PathWithNamespace,
+		Name:           project.Name,
+		Description:    project.Description,
+		Starred:        starred,
+		Archived:       project.Archived,
+		Topics:         projectTopics(project),
+		Member:         member,
+		DefaultBranch:  project.DefaultBranch,
+		Visibility:     string(project.Visibility),
+		LastActivityAt: lastActivityAt,
+		ForkedFrom:     forkedFrom,
+	}
+}
+
+// projectTopics returns the project topics, falling back to the tag_list
+// attribute that instances older than GitLab 14.0 return instead
+func projectTopics(project *gitlab.Project) []string {
+	if len(project.Topics) > 0 {
+		return project.Topics
+	}
+	return project.TagList //nolint:staticcheck // Deprecated field is the only source on GitLab < 14.0
+}
+
 // TestConnection tests the connection to GitLab by fetching current user
+// and detects the server version (see Supports)
 func (c *Client) TestConnection() error {
 	_, _, err := c.client.Users.CurrentUser()
 	if err != nil {
 		return fmt.Errorf("failed to connect to GitLab: %w", err)
 	}
+
+	// Probe the server version so version-dependent features degrade gracefully
+	if _, err := c.DetectVersion(); err != nil {
+		logger.Debug("%v (assuming a current version)", err)
+	}
 	return nil
 }
 
@@ -326,6 +400,636 @@ func (c *Client) GetCurrentUsername() (string, error) {
 	return user.Username, nil
 }
 
+// TokenInfo describes the personal access token used by the client
+type TokenInfo struct {
+	Name      string     // Token name as shown in GitLab
+	Scopes    []string   // Granted scopes (e.g., "read_api")
+	ExpiresAt *time.Time // Expiration date (nil if the token never expires)
+	Active    bool       // Whether the token is active (not revoked or expired)
+}
+
+// GetTokenInfo fetches information about the token in use via /personal_access_tokens/self
+// Requires GitLab 15.5+; older instances return an error
+func (c *Client) GetTokenInfo() (*TokenInfo, error) {
+	if !c.Supports(FeatureTokenInfo) {
+		return nil, fmt.Errorf("%s is not available on GitLab %s", FeatureTokenInfo, c.version.Version)
+	}
+	token, _, err := c.client.PersonalAccessTokens.GetSinglePersonalAccessToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token info: %w", err)
+	}
+
+	info := &TokenInfo{
+		Name:   token.Name,
+		Scopes: token.Scopes,
+		Active: token.Active,
+	}
+	if token.ExpiresAt != nil {
+		expiresAt := time.Time(*token.ExpiresAt)
+		info.ExpiresAt = &expiresAt
+	}
+	return info, nil
+}
+
+// IsUnauthorized reports whether err is a GitLab 401 response (invalid, revoked, or expired token)
+func IsUnauthorized(err error) bool {
+	var errResp *gitlab.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		return errResp.Response.StatusCode == http.StatusUnauthorized
+	}
+	return false
+}
+
+// SearchProjects runs a live project search on GitLab (GET /projects?search=...)
+// Used as a read-through fallback when the local index has no results.
+// Starred/Member flags are filled from the cached project sets when available.
+func (c *Client) SearchProjects(query string, limit int) ([]model.Project, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	opt := &gitlab.ListProjectsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: int64(limit),
+			Page:    1,
+		},
+		Search:           gitlab.Ptr(query),
+		SearchNamespaces: gitlab.Ptr(true), // Match "group/project" style queries too
+		Simple:           gitlab.Ptr(true),
+		OrderBy:          gitlab.Ptr("last_activity_at"),
+	}
+
+	projects, _, err := c.client.Projects.ListProjects(opt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search projects: %w", err)
+	}
+
+	result := make([]model.Project, 0, len(projects))
+	for _, project := range projects {
+		result = append(result, newProject(project, c.cachedStarred[project.PathWithNamespace], c.cachedMember[project.PathWithNamespace]))
+	}
+
+	logger.Debug("Remote search for %q returned %d projects", query, len(result))
+	return result, nil
+}
+
+// ListOpenIssues fetches the most recently updated open issues of a project (first page only)
+func (c *Client) ListOpenIssues(projectPath string, limit int) ([]model.Issue, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	issues, _, err := c.client.Issues.ListProjectIssues(projectPath, &gitlab.ListProjectIssuesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: int64(limit), Page: 1},
+		State:       gitlab.Ptr("opened"),
+		OrderBy:     gitlab.Ptr("updated_at"),
+		Sort:        gitlab.Ptr("desc"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues of %s: %w", projectPath, err)
+	}
+
+	result := make([]model.Issue, 0, len(issues))
+	for _, issue := range issues {
+		var author string
+		if issue.Author != nil {
+			author = issue.Author.Username
+		}
+		result = append(result, model.Issue{
+			IID:    issue.IID,
+			Title:  issue.Title,
+			Author: author,
+			Labels: issue.Labels,
+			WebURL: issue.WebURL,
+		})
+	}
+
+	logger.Debug("Fetched %d open issues of %s", len(result), projectPath)
+	return result, nil
+}
+
+// ListEnvironments fetches the environments of a project (first page of 100, available ones first)
+func (c *Client) ListEnvironments(projectPath string) ([]model.Environment, error) {
+	environments, _, err := c.client.Environments.ListEnvironments(projectPath, &gitlab.ListEnvironmentsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100, Page: 1},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments of %s: %w", projectPath, err)
+	}
+
+	result := make([]model.Environment, 0, len(environments))
+	for _, env := range environments {
+		result = append(result, model.Environment{
+			ID:          env.ID,
+			Name:        env.Name,
+			State:       env.State,
+			ExternalURL: env.ExternalURL,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].State == "available" && result[j].State != "available"
+	})
+
+	logger.Debug("Fetched %d environments of %s", len(result), projectPath)
+	return result, nil
+}
+
+// FetchAllGroups fetches the groups and subgroups visible to the user
+// Pages are fetched sequentially: even large instances have far fewer groups than projects
+func (c *Client) FetchAllGroups() ([]model.Group, error) {
+	opt := &gitlab.ListGroupsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100, Page: 1},
+		OrderBy:     gitlab.Ptr("path"),
+	}
+
+	var result []model.Group
+	for {
+		groups, resp, err := c.client.Groups.ListGroups(opt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list groups (page %d): %w", opt.Page, err)
+		}
+		for _, group := range groups {
+			result = append(result, model.Group{
+				ID:          group.ID,
+				Path:        group.FullPath,
+				Name:        group.Name,
+				Description: group.Description,
+			})
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	logger.Debug("Fetched %d groups", len(result))
+	return result, nil
+}
+
+// FetchAllUsers fetches the active human users of the instance
+// Pages are fetched sequentially; called during sync only when gitlab.sync_users is enabled
+func (c *Client) FetchAllUsers() ([]model.User, error) {
+	opt := &gitlab.ListUsersOptions{
+		ListOptions:     gitlab.ListOptions{PerPage: 100, Page: 1},
+		Active:          gitlab.Ptr(true),
+		Humans:          gitlab.Ptr(true),
+		ExcludeInternal: gitlab.Ptr(true),
+	}
+
+	var result []model.User
+	for {
+		users, resp, err := c.client.Users.ListUsers(opt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list users (page %d): %w", opt.Page, err)
+		}
+		for _, user := range users {
+			result = append(result, model.User{
+				ID:       user.ID,
+				Username: user.Username,
+				Name:     user.Name,
+				WebURL:   user.WebURL,
+			})
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	logger.Debug("Fetched %d users", len(result))
+	return result, nil
+}
+
+// ErrProjectNotFound is returned by GetProject for a project that doesn't exist (or isn't visible)
+var ErrProjectNotFound = errors.New("project not found")
+
+// GetProject fetches a single project by ID (e.g. after a system hook event)
+// Starred/member flags come from the cached project sets (see SetCachedProjectSets)
+func (c *Client) GetProject(projectID int64) (model.Project, error) {
+	project, resp, err := c.client.Projects.GetProject(projectID, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return model.Project{}, fmt.Errorf("project %d: %w", projectID, ErrProjectNotFound)
+		}
+		return model.Project{}, fmt.Errorf("failed to get project %d: %w", projectID, err)
+	}
+	return newProject(project, c.cachedStarred[project.PathWithNamespace], c.cachedMember[project.PathWithNamespace]), nil
+}
+
+// CloneURL returns the URL to clone a project with: SSH if the instance offers it, HTTPS otherwise
+func (c *Client) CloneURL(projectPath string) (string, error) {
+	project, _, err := c.client.Projects.GetProject(projectPath, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get project %s: %w", projectPath, err)
+	}
+	return cloneURL(project), nil
+}
+
+// cloneURL prefers the SSH URL of a project, falling back to HTTPS
+func cloneURL(project *gitlab.Project) string {
+	if project.SSHURLToRepo != "" {
+		return project.SSHURLToRepo
+	}
+	return project.HTTPURLToRepo
+}
+
+// CreateProject creates a project at projectPath ("group/subgroup/name", or just "name" in the
+// user's personal namespace) and returns it with its clone URL (SSH if offered, HTTPS otherwise)
+// visibility is private, internal or public (empty = instance default)
+func (c *Client) CreateProject(projectPath, visibility string, initReadme bool) (model.Project, string, error) {
+	namespace, name := "", projectPath
+	if i := strings.LastIndex(projectPath, "/"); i >= 0 {
+		namespace, name = projectPath[:i], projectPath[i+1:]
+	}
+
+	opt := &gitlab.CreateProjectOptions{
+		Name:                 gitlab.Ptr(name),
+		Path:                 gitlab.Ptr(name),
+		InitializeWithReadme: gitlab.Ptr(initReadme),
+	}
+	if visibility != "" {
+		opt.Visibility = gitlab.Ptr(gitlab.VisibilityValue(visibility))
+	}
+	if namespace != "" {
+		ns, _, err := c.client.Namespaces.GetNamespace(namespace)
+		if err != nil {
+			return model.Project{}, "", fmt.Errorf("failed to find group %s: %w", namespace, err)
+		}
+		opt.NamespaceID = gitlab.Ptr(ns.ID)
+	}
+
+	project, _, err := c.client.Projects.CreateProject(opt)
+	if err != nil {
+		return model.Project{}, "", fmt.Errorf("failed to create project %s: %w", projectPath, err)
+	}
+	// The creator owns the new project
+	return newProject(project, false, true), cloneURL(project), nil
+}
+
+// ErrNoAvatar is returned by FetchAvatar when a project has no avatar
+var ErrNoAvatar = errors.New("project has no avatar")
+
+// maxAvatarSize limits the avatar image downloaded for the TUI preview
+const maxAvatarSize = 1 << 20
+
+// FetchAvatar downloads the avatar image of a project (PNG, JPEG or GIF as uploaded)
+func (c *Client) FetchAvatar(projectPath string) ([]byte, error) {
+	avatar, resp, err := c.client.Projects.DownloadAvatar(projectPath)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, ErrNoAvatar
+		}
+		return nil, fmt.Errorf("failed to download avatar of %s: %w", projectPath, err)
+	}
+	if avatar.Len() > maxAvatarSize {
+		return nil, fmt.Errorf("avatar of %s is too large (%d bytes)", projectPath, avatar.Len())
+	}
+	return io.ReadAll(avatar)
+}
+
+// SetStarred stars or unstars a project for the current user
+// Starring a starred project (or unstarring one that isn't) succeeds without changes
+func (c *Client) SetStarred(projectPath string, starred bool) error {
+	var resp *gitlab.Response
+	var err error
+	if starred {
+		_, resp, err = c.client.Projects.StarProject(projectPath)
+	} else {
+		_, resp, err = c.client.Projects.UnstarProject(projectPath)
+	}
+	// GitLab answers 304 without a body when nothing changes, which fails to decode
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotModified) {
+		action := "star"
+		if !starred {
+			action = "unstar"
+		}
+		return fmt.Errorf("failed to %s %s: %w", action, projectPath, err)
+	}
+
+	// Keep the cached starred set in line for later fetches
+	if c.cachedStarred != nil {
+		if starred {
+			c.cachedStarred[projectPath] = true
+		} else {
+			delete(c.cachedStarred, projectPath)
+		}
+	}
+	logger.Debug("Set starred=%v on %s", starred, projectPath)
+	return nil
+}
+
+// ErrNoReadme is returned by FetchReadme when a project has no README
+var ErrNoReadme = errors.New("project has no README")
+
+// maxReadmeSize limits the README content kept for the TUI preview
+const maxReadmeSize = 256 << 10
+
+// FetchReadme fetches the README of a project's default branch as raw Markdown
+// Content beyond maxReadmeSize is cut off
+func (c *Client) FetchReadme(projectPath string) (string, error) {
+	project, _, err := c.client.Projects.GetProject(projectPath, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get project %s: %w", projectPath, err)
+	}
+	if project.ReadmeURL == "" || project.DefaultBranch == "" {
+		return "", ErrNoReadme
+	}
+
+	fileName := readmeFileName(project.ReadmeURL, project.DefaultBranch)
+	content, _, err := c.client.RepositoryFiles.GetRawFile(projectPath, fileName, &gitlab.GetRawFileOptions{
+		Ref: gitlab.Ptr(project.DefaultBranch),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s of %s: %w", fileName, projectPath, err)
+	}
+	if len(content) > maxReadmeSize {
+		content = content[:maxReadmeSize]
+	}
+
+	logger.Debug("Fetched %s of %s (%d bytes)", fileName, projectPath, len(content))
+	return string(content), nil
+}
+
+// readmeFileName extracts the README path from a project's readme_url
+// (".../-/blob/<branch>/docs/README.md" → "docs/README.md"), defaulting to README.md
+func readmeFileName(readmeURL, defaultBranch string) string {
+	marker := "/-/blob/" + defaultBranch + "/"
+	idx := strings.Index(readmeURL, marker)
+	if idx < 0 {
+		return "README.md"
+	}
+	fileName := readmeURL[idx+len(marker):]
+	if unescaped, err := url.PathUnescape(fileName); err == nil {
+		fileName = unescaped
+	}
+	return fileName
+}
+
+// maxRawFileSize limits the size of repository files fetched with FetchRawFile
+const maxRawFileSize = 1 << 20
+
+// FetchRawFile fetches a file of a project's repository at ref (the default branch if empty)
+func (c *Client) FetchRawFile(projectPath, filePath, ref string) ([]byte, error) {
+	opt := &gitlab.GetRawFileOptions{}
+	if ref != "" {
+		opt.Ref = gitlab.Ptr(ref)
+	}
+	content, _, err := c.client.RepositoryFiles.GetRawFile(projectPath, filePath, opt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s of %s: %w", filePath, projectPath, err)
+	}
+	if len(content) > maxRawFileSize {
+		return nil, fmt.Errorf("%s of %s is too large (%d bytes)", filePath, projectPath, len(content))
+	}
+	return content, nil
+}
+
+// ListMyMergeRequests fetches open merge requests assigned to or created by the current user
+// across the instance (first page of each scope, most recently updated first)
+func (c *Client) ListMyMergeRequests() ([]model.MergeRequest, error) {
+	seen := make(map[int64]bool)
+	var result []model.MergeRequest
+
+	for _, scope := range []string{"assigned_to_me", "created_by_me"} {
+		mrs, _, err := c.client.MergeRequests.ListMergeRequests(&gitlab.ListMergeRequestsOptions{
+			ListOptions: gitlab.ListOptions{PerPage: 100, Page: 1},
+			State:       gitlab.Ptr("opened"),
+			Scope:       gitlab.Ptr(scope),
+			OrderBy:     gitlab.Ptr("updated_at"),
+			Sort:        gitlab.Ptr("desc"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merge requests (%s): %w", scope, err)
+		}
+
+		for _, mr := range mrs {
+			if seen[mr.ID] {
+				continue
+			}
+			seen[mr.ID] = true
+
+			item := model.MergeRequest{
+				ID:          mr.ID,
+				IID:         mr.IID,
+				ProjectPath: mergeRequestProjectPath(mr),
+				Title:       mr.Title,
+				Labels:      mr.Labels,
+				Draft:       mr.Draft,
+				WebURL:      mr.WebURL,
+			}
+			if mr.Author != nil {
+				item.Author = mr.Author.Username
+			}
+			if mr.UpdatedAt != nil {
+				item.UpdatedAt = *mr.UpdatedAt
+			}
+			result = append(result, item)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].UpdatedAt.After(result[j].UpdatedAt)
+	})
+
+	logger.Debug("Fetched %d open merge requests", len(result))
+	return result, nil
+}
+
+// mergeRequestProjectPath extracts the project path from the full reference ("group/project!12")
+// or, for older GitLab versions without references, from the web URL
+func mergeRequestProjectPath(mr *gitlab.BasicMergeRequest) string {
+	if mr.References != nil && mr.References.Full != "" {
+		path, _, _ := strings.Cut(mr.References.Full, "!")
+		return path
+	}
+	if idx := strings.Index(mr.WebURL, "/-/merge_requests/"); idx >= 0 {
+		if u, err := url.Parse(mr.WebURL[:idx]); err == nil {
+			return strings.TrimPrefix(u.Path, "/")
+		}
+	}
+	return ""
+}
+
+// ProjectInsights holds lightweight activity counters for a project
+type ProjectInsights struct {
+	OpenMRs    int // Number of open merge requests
+	OpenIssues int // Number of open issues
+}
+
+// insightsBatchSize is how many projects one GraphQL insights query asks for, small enough
+// to stay under GitLab's query complexity limit
+const insightsBatchSize = 20
+
+// insightsQuery counts open merge requests and issues of up to insightsBatchSize projects
+const insightsQuery = `query($paths: [String!], $first: Int) {
+  projects(fullPaths: $paths, first: $first) {
+    nodes {
+      fullPath
+      mergeRequests(state: opened) { count }
+      issues(state: opened) { count }
+    }
+  }
+}`
+
+// FetchProjectInsights fetches open MR and open issue counts for the given projects
+// Projects are batched into GraphQL queries of insightsBatchSize, one request per batch, run in
+// parallel (bounded by the client concurrency). Projects of failed batches are omitted.
+func (c *Client) FetchProjectInsights(paths []string) map[string]ProjectInsights {
+	result := make(map[string]ProjectInsights, len(paths))
+	if len(paths) == 0 {
+		return result
+	}
+
+	type insightsResult struct {
+		batch    []string
+		insights map[string]ProjectInsights
+		err      error
+	}
+
+	batches := slices.Collect(slices.Chunk(paths, insightsBatchSize))
+	results := make(chan insightsResult, len(batches))
+	semaphore := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+
+	for _, batch := range batches {
+		wg.Add(1)
+		go func(batch []string) {
+			defer wg.Done()
+
+			// Acquire semaphore
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			insights, err := c.fetchInsightsBatch(batch)
+			results <- insightsResult{batch: batch, insights: insights, err: err}
+		}(batch)
+	}
+
+	// Close results channel after all goroutines finish
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var failed int
+	for res := range results {
+		if res.err != nil {
+			failed += len(res.batch)
+			logger.Debug("Warning: failed to fetch insights for %d projects: %v", len(res.batch), res.err)
+			continue
+		}
+		maps.Copy(result, res.insights)
+	}
+
+	logger.Debug("Fetched insights for %d projects in %d requests (%d failed)", len(result), len(batches), failed)
+	return result
+}
+
+// fetchInsightsBatch runs insightsQuery for one batch of project paths
+// Projects the token cannot see are missing from the response and from the result
+func (c *Client) fetchInsightsBatch(paths []string) (map[string]ProjectInsights, error) {
+	var response struct {
+		Data struct {
+			Projects struct {
+				Nodes []struct {
+					FullPath      string `json:"fullPath"`
+					MergeRequests struct {
+						Count int `json:"count"`
+					} `json:"mergeRequests"`
+					Issues struct {
+						Count int `json:"count"`
+					} `json:"issues"`
+				} `json:"nodes"`
+			} `json:"projects"`
+		} `json:"data"`
+		gitlab.GenericGraphQLErrors
+	}
+	query := gitlab.GraphQLQuery{Query: insightsQuery, Variables: map[string]any{"paths": paths, "first": len(paths)}}
+	if _, err := c.client.GraphQL.Do(query, &response); err != nil {
+		return nil, err
+	}
+	// GraphQL reports query errors (complexity, unknown fields on older GitLab) with status 200
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("GraphQL error: %s", response.Errors[0].Message)
+	}
+
+	insights := make(map[string]ProjectInsights, len(response.Data.Projects.Nodes))
+	for _, node := range response.Data.Projects.Nodes {
+		insights[node.FullPath] = ProjectInsights{
+			OpenMRs:    node.MergeRequests.Count,
+			OpenIssues: node.Issues.Count,
+		}
+	}
+	return insights, nil
+}
+
+// FetchStarredAndMemberProjects fetches only the projects the current user starred or is a member of,
+// with their starred/member flags set, and remembers both sets (see LastProjectSets)
+// Two small listings instead of every project of the instance (--sync --starred)
+func (c *Client) FetchStarredAndMemberProjects() ([]model.Project, error) {
+	var starred, member []*gitlab.Project
+	var starredErr, memberErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		starred, starredErr = c.listAllProjects(&gitlab.ListProjectsOptions{Starred: gitlab.Ptr(true)})
+	}()
+	go func() {
+		defer wg.Done()
+		member, memberErr = c.listAllProjects(&gitlab.ListProjectsOptions{Membership: gitlab.Ptr(true)})
+	}()
+	wg.Wait()
+	if starredErr != nil {
+		return nil, fmt.Errorf("failed to fetch starred projects: %w", starredErr)
+	}
+	if memberErr != nil {
+		return nil, fmt.Errorf("failed to fetch member projects: %w", memberErr)
+	}
+
+	c.cachedStarred = make(map[string]bool, len(starred))
+	for _, project := range starred {
+		c.cachedStarred[project.PathWithNamespace] = true
+	}
+	c.cachedMember = make(map[string]bool, len(member))
+	for _, project := range member {
+		c.cachedMember[project.PathWithNamespace] = true
+	}
+
+	seen := make(map[string]bool, len(starred)+len(member))
+	result := make([]model.Project, 0, len(starred)+len(member))
+	for _, project := range append(starred, member...) {
+		if seen[project.PathWithNamespace] {
+			continue
+		}
+		seen[project.PathWithNamespace] = true
+		result = append(result, newProject(project, c.cachedStarred[project.PathWithNamespace], c.cachedMember[project.PathWithNamespace]))
+	}
+	logger.Debug("Fetched %d starred and %d member projects", len(starred), len(member))
+	return result, nil
+}
+
+// listAllProjects fetches every page of a project listing, one page at a time
+// Meant for short listings (starred, membership); see StreamAllProjects for all projects
+func (c *Client) listAllProjects(opt *gitlab.ListProjectsOptions) ([]*gitlab.Project, error) {
+	opt.Simple = gitlab.Ptr(true)
+	opt.PerPage = 100
+	opt.Page = 1
+
+	var result []*gitlab.Project
+	for {
+		projects, resp, err := c.client.Projects.ListProjects(opt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, projects...)
+		if resp.NextPage == 0 {
+			return result, nil
+		}
+		opt.Page = resp.NextPage
+	}
+}
+
 // FetchStarredProjects fetches all projects starred by the current user
 // Returns a map of project PathWithNamespace → true for O(1) lookup
 func (c *Client) FetchStarredProjects() (map[string]bool, error) {
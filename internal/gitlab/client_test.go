@@ -2,12 +2,20 @@ package gitlab
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/model"
 )
 
 func TestNew(t *testing.T) {
@@ -174,6 +182,46 @@ func TestFetchAllProjects_MultiplePages(t *testing.T) {
 	}
 }
 
+func TestStreamAllProjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageNum, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if pageNum == 0 {
+			pageNum = 1
+		}
+
+		w.Header().Set("X-Total-Pages", "3")
+		w.Header().Set("X-Total", "3")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"id": pageNum, "path_with_namespace": fmt.Sprintf("group/p%d", pageNum), "name": fmt.Sprintf("P%d", pageNum)},
+		})
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	streamed := make(map[string]bool)
+	projects, err := client.StreamAllProjects(nil, true, func(page []model.Project) {
+		for _, p := range page {
+			streamed[p.Path] = true
+		}
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(projects) != 3 || len(streamed) != 3 {
+		t.Fatalf("Expected 3 projects returned and streamed, got %d and %d", len(projects), len(streamed))
+	}
+	for _, p := range projects {
+		if !streamed[p.Path] {
+			t.Errorf("Project %s was returned but not streamed", p.Path)
+		}
+	}
+}
 func TestFetchAllProjects_IncrementalSync(t *testing.T) {
 	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 	var capturedSince string
@@ -493,3 +541,760 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGetTokenInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/personal_access_tokens/self" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":         42,
+			"name":       "glf-cli-token",
+			"scopes":     []string{"read_api", "read_repository"},
+			"active":     true,
+			"expires_at": "2026-11-01",
+		})
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	info, err := client.GetTokenInfo()
+	if err != nil {
+		t.Fatalf("GetTokenInfo failed: %v", err)
+	}
+
+	if info.Name != "glf-cli-token" {
+		t.Errorf("Expected name 'glf-cli-token', got '%s'", info.Name)
+	}
+	if !info.Active {
+		t.Error("Expected token to be active")
+	}
+	if len(info.Scopes) != 2 || info.Scopes[0] != "read_api" {
+		t.Errorf("Unexpected scopes: %v", info.Scopes)
+	}
+	if info.ExpiresAt == nil {
+		t.Fatal("Expected expiry date")
+	}
+	if got := info.ExpiresAt.Format("2006-01-02"); got != "2026-11-01" {
+		t.Errorf("Expected expiry 2026-11-01, got %s", got)
+	}
+}
+
+func TestGetTokenInfo_NoExpiry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1, "name": "forever", "active": true, "expires_at": null}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	info, err := client.GetTokenInfo()
+	if err != nil {
+		t.Fatalf("GetTokenInfo failed: %v", err)
+	}
+	if info.ExpiresAt != nil {
+		t.Errorf("Expected nil expiry, got %v", info.ExpiresAt)
+	}
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message": "401 Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "expired-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	err = client.TestConnection()
+	if err == nil {
+		t.Fatal("Expected error for 401 response")
+	}
+	if !IsUnauthorized(err) {
+		t.Errorf("Expected IsUnauthorized to be true for: %v", err)
+	}
+
+	if IsUnauthorized(fmt.Errorf("some other error")) {
+		t.Error("Expected IsUnauthorized to be false for a plain error")
+	}
+	if IsUnauthorized(nil) {
+		t.Error("Expected IsUnauthorized to be false for nil")
+	}
+}
+
+func TestFetchProjectInsights(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Method != http.MethodPost || r.URL.Path != "/api/graphql" {
+			t.Errorf("Expected POST /api/graphql, got %s %s", r.Method, r.URL.Path)
+		}
+		var query struct {
+			Variables struct {
+				Paths []string `json:"paths"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+			t.Errorf("Failed to decode query: %v", err)
+		}
+		if len(query.Variables.Paths) > insightsBatchSize {
+			t.Errorf("Expected at most %d paths per query, got %d", insightsBatchSize, len(query.Variables.Paths))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if slices.Contains(query.Variables.Paths, "team/broken") {
+			w.Write([]byte(`{"errors": [{"message": "Query has complexity of 300, which exceeds max complexity of 250"}]}`))
+			return
+		}
+		var nodes []string
+		for _, path := range query.Variables.Paths {
+			if path == "team/hidden" {
+				continue // not visible to the token
+			}
+			nodes = append(nodes, fmt.Sprintf(`{"fullPath": %q, "mergeRequests": {"count": 3}, "issues": {"count": 7}}`, path))
+		}
+		fmt.Fprintf(w, `{"data": {"projects": {"nodes": [%s]}}}`, strings.Join(nodes, ","))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second, 2)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	paths := []string{"team/api", "team/hidden"}
+	for i := range insightsBatchSize {
+		paths = append(paths, fmt.Sprintf("team/service-%d", i))
+	}
+	insights := client.FetchProjectInsights(paths)
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected %d projects fetched in 2 requests, got %d", len(paths), got)
+	}
+	got, ok := insights["team/api"]
+	if !ok {
+		t.Fatal("Expected insights for team/api")
+	}
+	if got.OpenMRs != 3 || got.OpenIssues != 7 {
+		t.Errorf("Expected 3 MRs / 7 issues, got %d / %d", got.OpenMRs, got.OpenIssues)
+	}
+	if len(insights) != len(paths)-1 {
+		t.Errorf("Expected insights for every visible project, got %d of %d", len(insights), len(paths))
+	}
+	if _, ok := insights["team/hidden"]; ok {
+		t.Error("Expected a project missing from the response to be omitted")
+	}
+
+	if failed := client.FetchProjectInsights([]string{"team/broken", "team/api"}); len(failed) != 0 {
+		t.Errorf("Expected the projects of a failed query omitted, got %v", failed)
+	}
+
+	if len(client.FetchProjectInsights(nil)) != 0 {
+		t.Error("Expected empty result for no projects")
+	}
+}
+
+func TestSearchProjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("search") != "fresh" {
+			t.Errorf("Expected search=fresh, got %q", query.Get("search"))
+		}
+		if query.Get("per_page") != "5" {
+			t.Errorf("Expected per_page=5, got %q", query.Get("per_page"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"id": 1, "path_with_namespace": "team/fresh-api", "name": "fresh-api", "description": "New"},
+			{"id": 2, "path_with_namespace": "other/fresh-web", "name": "fresh-web", "archived": true}
+		]`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.SetCachedProjectSets(map[string]bool{"team/fresh-api": true}, map[string]bool{"team/fresh-api": true})
+
+	projects, err := client.SearchProjects("fresh", 5)
+	if err != nil {
+		t.Fatalf("SearchProjects failed: %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 projects, got %d", len(projects))
+	}
+	if !projects[0].Starred || !projects[0].Member {
+		t.Errorf("Expected flags from cached sets, got %+v", projects[0])
+	}
+	if projects[1].Member || !projects[1].Archived {
+		t.Errorf("Unexpected flags for non-member project: %+v", projects[1])
+	}
+}
+
+func TestClient_SendsRequestID(t *testing.T) {
+	logger.SetTraceID("trace-42")
+	defer logger.SetTraceID("")
+
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(RequestIDHeader)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1, "username": "testuser"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.GetCurrentUsername(); err != nil {
+		t.Fatalf("GetCurrentUsername failed: %v", err)
+	}
+	if got != "trace-42" {
+		t.Errorf("Expected %s header %q, got %q", RequestIDHeader, "trace-42", got)
+	}
+}
+
+func TestListOpenIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fapi/issues" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("state") != "opened" {
+			t.Errorf("Expected state=opened, got %q", r.URL.Query().Get("state"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"id": 107, "iid": 7, "title": "Fix login redirect", "labels": ["bug"], "author": {"username": "alice"}, "web_url": "https://gitlab.example.com/group/api/-/issues/7"},
+			{"id": 103, "iid": 3, "title": "Add rate limiting", "web_url": "https://gitlab.example.com/group/api/-/issues/3"}
+		]`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	issues, err := client.ListOpenIssues("group/api", 50)
+	if err != nil {
+		t.Fatalf("ListOpenIssues failed: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d", len(issues))
+	}
+	if issues[0].IID != 7 || issues[0].Author != "alice" || len(issues[0].Labels) != 1 || issues[0].WebURL == "" {
+		t.Errorf("Unexpected first issue: %+v", issues[0])
+	}
+	if issues[1].Author != "" {
+		t.Errorf("Expected empty author, got %q", issues[1].Author)
+	}
+
+	if _, err := client.ListOpenIssues("group/missing", 50); err == nil {
+		t.Error("Expected error for unknown project")
+	}
+}
+
+func TestFetchReadme(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fapi":
+			w.Write([]byte(`{"id": 1, "default_branch": "release/v2", "readme_url": "https://gitlab.example.com/group/api/-/blob/release/v2/docs/READ%20ME.md"}`))
+		case "/api/v4/projects/group%2Fapi/repository/files/docs%2FREAD%20ME%2Emd/raw":
+			if ref := r.URL.Query().Get("ref"); ref != "release/v2" {
+				t.Errorf("Expected ref=release/v2, got %q", ref)
+			}
+			w.Write([]byte("# API\n\nDocs"))
+		case "/api/v4/projects/group%2Fempty":
+			w.Write([]byte(`{"id": 2, "default_branch": "main", "readme_url": null}`))
+		default:
+			t.Logf("Unexpected path %s", r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	content, err := client.FetchReadme("group/api")
+	if err != nil {
+		t.Fatalf("FetchReadme failed: %v", err)
+	}
+	if content != "# API\n\nDocs" {
+		t.Errorf("Unexpected README content %q", content)
+	}
+
+	if _, err := client.FetchReadme("group/empty"); !errors.Is(err, ErrNoReadme) {
+		t.Errorf("Expected ErrNoReadme, got %v", err)
+	}
+}
+
+func TestFetchRawFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/platform%2Fhandbook/repository/files/glf%2Fannotations%2Eyaml/raw" {
+			t.Logf("Unexpected path %s", r.URL.EscapedPath())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if ref := r.URL.Query().Get("ref"); ref != "" && ref != "main" {
+			t.Errorf("Unexpected ref %q", ref)
+		}
+		w.Write([]byte("platform/api: [tier-1]\n"))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	for _, ref := range []string{"", "main"} {
+		content, err := client.FetchRawFile("platform/handbook", "glf/annotations.yaml", ref)
+		if err != nil {
+			t.Fatalf("FetchRawFile(ref=%q) failed: %v", ref, err)
+		}
+		if string(content) != "platform/api: [tier-1]\n" {
+			t.Errorf("Unexpected content %q", content)
+		}
+	}
+
+	if _, err := client.FetchRawFile("platform/handbook", "missing.yaml", ""); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestListEnvironments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fapi/environments" {
+			t.Errorf("Unexpected path %s", r.URL.EscapedPath())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"id": 1, "name": "review/old", "state": "stopped"},
+			{"id": 2, "name": "production", "state": "available", "external_url": "https://api.example.com"}
+		]`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	environments, err := client.ListEnvironments("group/api")
+	if err != nil {
+		t.Fatalf("ListEnvironments failed: %v", err)
+	}
+	want := []model.Environment{
+		{ID: 2, Name: "production", State: "available", ExternalURL: "https://api.example.com"},
+		{ID: 1, Name: "review/old", State: "stopped"},
+	}
+	if !reflect.DeepEqual(environments, want) {
+		t.Errorf("ListEnvironments() = %+v, want %+v (available first)", environments, want)
+	}
+}
+
+func TestReadmeFileName(t *testing.T) {
+	tests := []struct {
+		url, branch, want string
+	}{
+		{"https://gitlab.example.com/g/p/-/blob/main/README.md", "main", "README.md"},
+		{"https://gitlab.example.com/g/p/-/blob/feature/x/docs/README.rst", "feature/x", "docs/README.rst"},
+		{"https://gitlab.example.com/g/p/-/blob/main/READ%20ME.md", "main", "READ ME.md"},
+		{"https://gitlab.example.com/g/p/unexpected", "main", "README.md"},
+	}
+	for _, tt := range tests {
+		if got := readmeFileName(tt.url, tt.branch); got != tt.want {
+			t.Errorf("readmeFileName(%q, %q) = %q, want %q", tt.url, tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestFetchAllGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/groups" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("X-Next-Page", "2")
+			w.Write([]byte(`[{"id": 1, "name": "Platform", "full_path": "company/platform", "description": "Platform team"}]`))
+		case "2":
+			w.Write([]byte(`[{"id": 2, "name": "API", "full_path": "company/platform/api"}]`))
+		default:
+			t.Errorf("Unexpected page %q", r.URL.Query().Get("page"))
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	groups, err := client.FetchAllGroups()
+	if err != nil {
+		t.Fatalf("FetchAllGroups failed: %v", err)
+	}
+	want := []model.Group{
+		{ID: 1, Path: "company/platform", Name: "Platform", Description: "Platform team"},
+		{ID: 2, Path: "company/platform/api", Name: "API"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("Expected %d groups, got %d: %+v", len(want), len(groups), groups)
+	}
+	for i := range want {
+		if groups[i] != want[i] {
+			t.Errorf("Group %d: expected %+v, got %+v", i, want[i], groups[i])
+		}
+	}
+}
+
+func TestFetchAllUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/users" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("active") != "true" || r.URL.Query().Get("exclude_internal") != "true" {
+			t.Errorf("Expected active, non-internal users, got query %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("X-Next-Page", "2")
+			w.Write([]byte(`[{"id": 7, "username": "jdoe", "name": "Jane Doe", "web_url": "https://gitlab.example.com/jdoe"}]`))
+			return
+		}
+		w.Write([]byte(`[{"id": 9, "username": "bob", "name": "Bob", "web_url": "https://gitlab.example.com/bob"}]`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	users, err := client.FetchAllUsers()
+	if err != nil {
+		t.Fatalf("FetchAllUsers failed: %v", err)
+	}
+	if len(users) != 2 || users[0].Username != "jdoe" || users[0].Name != "Jane Doe" || users[1].ID != 9 {
+		t.Errorf("Unexpected users: %+v", users)
+	}
+}
+
+func TestFetchAvatar(t *testing.T) {
+	pngData := []byte("\x89PNG\r\n\x1a\nfake")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fapi/avatar":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(pngData)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "404 Avatar Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	data, err := client.FetchAvatar("group/api")
+	if err != nil {
+		t.Fatalf("FetchAvatar failed: %v", err)
+	}
+	if string(data) != string(pngData) {
+		t.Errorf("Unexpected avatar data %q", data)
+	}
+
+	if _, err := client.FetchAvatar("group/plain"); !errors.Is(err, ErrNoAvatar) {
+		t.Errorf("Expected ErrNoAvatar, got %v", err)
+	}
+}
+
+func TestGetProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/v4/projects/42" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "404 Project Not Found"}`))
+			return
+		}
+		w.Write([]byte(`{"id": 42, "name": "api", "path_with_namespace": "group/api", "description": "REST API", "archived": true, "topics": ["go"], "default_branch": "main", "visibility": "internal", "last_activity_at": "2026-10-15T08:30:00.000+02:00", "forked_from_project": {"id": 7, "path_with_namespace": "upstream/api"}}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.SetCachedProjectSets(map[string]bool{"group/api": true}, nil)
+
+	project, err := client.GetProject(42)
+	if err != nil {
+		t.Fatalf("GetProject failed: %v", err)
+	}
+	if project.ID != 42 || project.Path != "group/api" || project.Name != "api" || project.Description != "REST API" {
+		t.Errorf("Unexpected project %+v", project)
+	}
+	if !project.Archived || !project.Starred || project.Member {
+		t.Errorf("Expected archived, starred, non-member project, got %+v", project)
+	}
+	if len(project.Topics) != 1 || project.Topics[0] != "go" {
+		t.Errorf("Expected topics [go], got %v", project.Topics)
+	}
+	wantActivity := time.Date(2026, 10, 15, 6, 30, 0, 0, time.UTC)
+	if project.DefaultBranch != "main" || project.Visibility != "internal" || !project.LastActivityAt.Equal(wantActivity) {
+		t.Errorf("Unexpected metadata %q/%q/%v", project.DefaultBranch, project.Visibility, project.LastActivityAt)
+	}
+	if project.ForkedFrom != "upstream/api" || !project.IsFork() {
+		t.Errorf("Expected a fork of upstream/api, got %q", project.ForkedFrom)
+	}
+
+	if _, err := client.GetProject(7); err == nil {
+		t.Error("Expected error for missing project")
+	}
+}
+
+func TestCloneURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fapi":
+			w.Write([]byte(`{"id": 1, "path_with_namespace": "group/api", "ssh_url_to_repo": "git@gitlab.example.com:group/api.git", "http_url_to_repo": "https://gitlab.example.com/group/api.git"}`))
+		case "/api/v4/projects/group%2Fweb":
+			w.Write([]byte(`{"id": 2, "path_with_namespace": "group/web", "http_url_to_repo": "https://gitlab.example.com/group/web.git"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "404 Project Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"group/api", "git@gitlab.example.com:group/api.git"},
+		{"group/web", "https://gitlab.example.com/group/web.git"},
+	}
+	for _, tt := range tests {
+		got, err := client.CloneURL(tt.path)
+		if err != nil {
+			t.Fatalf("CloneURL(%q) failed: %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("CloneURL(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+
+	if _, err := client.CloneURL("group/missing"); err == nil {
+		t.Error("Expected error for missing project")
+	}
+}
+
+func TestFetchStarredAndMemberProjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		switch {
+		case query.Get("starred") == "true":
+			w.Write([]byte(`[{"id": 1, "name": "api", "path_with_namespace": "group/api"}, {"id": 3, "name": "docs", "path_with_namespace": "other/docs"}]`))
+		case query.Get("membership") == "true" && query.Get("page") == "2":
+			w.Write([]byte(`[{"id": 2, "name": "web", "path_with_namespace": "group/web", "default_branch": "main"}]`))
+		case query.Get("membership") == "true":
+			w.Header().Set("X-Next-Page", "2")
+			w.Write([]byte(`[{"id": 1, "name": "api", "path_with_namespace": "group/api"}]`))
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	projects, err := client.FetchStarredAndMemberProjects()
+	if err != nil {
+		t.Fatalf("FetchStarredAndMemberProjects failed: %v", err)
+	}
+	byPath := make(map[string]model.Project, len(projects))
+	for _, p := range projects {
+		byPath[p.Path] = p
+	}
+	if len(projects) != 3 {
+		t.Fatalf("Expected 3 distinct projects, got %+v", projects)
+	}
+	if p := byPath["group/api"]; !p.Starred || !p.Member {
+		t.Errorf("Expected group/api starred and member, got %+v", p)
+	}
+	if p := byPath["other/docs"]; !p.Starred || p.Member {
+		t.Errorf("Expected other/docs starred only, got %+v", p)
+	}
+	if p := byPath["group/web"]; p.Starred || !p.Member || p.DefaultBranch != "main" {
+		t.Errorf("Expected group/web member only (from page 2), got %+v", p)
+	}
+
+	starred, member := client.LastProjectSets()
+	if len(starred) != 2 || len(member) != 2 {
+		t.Errorf("Expected 2 starred and 2 member paths, got %v / %v", starred, member)
+	}
+}
+
+func TestFetchStarredAndMemberProjects_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if _, err := client.FetchStarredAndMemberProjects(); err == nil {
+		t.Error("Expected error when GitLab fails")
+	}
+}
+
+func TestCreateProject(t *testing.T) {
+	var created map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.EscapedPath() == "/api/v4/namespaces/team%2Fbackend":
+			w.Write([]byte(`{"id": 12, "full_path": "team/backend"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects":
+			created = nil
+			json.NewDecoder(r.Body).Decode(&created)
+			path, _ := created["path"].(string)
+			visibility, _ := created["visibility"].(string)
+			if visibility == "" {
+				visibility = "private" // Instance default
+			}
+			prefix := "team/backend/"
+			if _, ok := created["namespace_id"]; !ok {
+				prefix = "jdoe/"
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"id": 99, "name": %q, "path_with_namespace": %q, "visibility": %q, "default_branch": "main", "ssh_url_to_repo": "git@gitlab.example.com:%s%s.git"}`,
+				path, prefix+path, visibility, prefix, path)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "404 Namespace Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	project, cloneURL, err := client.CreateProject("team/backend/billing", "internal", true)
+	if err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	if created["namespace_id"] != float64(12) || created["visibility"] != "internal" || created["initialize_with_readme"] != true {
+		t.Errorf("Unexpected create request %v", created)
+	}
+	if project.ID != 99 || project.Path != "team/backend/billing" || !project.Member || project.Visibility != "internal" {
+		t.Errorf("Unexpected project %+v", project)
+	}
+	if cloneURL != "git@gitlab.example.com:team/backend/billing.git" {
+		t.Errorf("Clone URL = %q", cloneURL)
+	}
+
+	// A bare name goes to the personal namespace
+	project, _, err = client.CreateProject("scratch", "", false)
+	if err != nil {
+		t.Fatalf("CreateProject(scratch) failed: %v", err)
+	}
+	if _, ok := created["namespace_id"]; ok || project.Path != "jdoe/scratch" {
+		t.Errorf("Expected a personal project without namespace_id, got %v / %+v", created, project)
+	}
+
+	if _, _, err := client.CreateProject("missing/api", "", false); err == nil || !strings.Contains(err.Error(), "failed to find group missing") {
+		t.Errorf("Expected a missing group error, got %v", err)
+	}
+}
+
+func TestSetStarred(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fapi/star":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1, "path_with_namespace": "group/api"}`))
+		case "/api/v4/projects/group%2Fapi/unstar":
+			w.WriteHeader(http.StatusNotModified) // Not starred
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.SetCachedProjectSets(map[string]bool{}, map[string]bool{})
+
+	if err := client.SetStarred("group/api", true); err != nil {
+		t.Fatalf("SetStarred(true) failed: %v", err)
+	}
+	if !client.cachedStarred["group/api"] {
+		t.Error("Expected the cached starred set to include group/api")
+	}
+	if err := client.SetStarred("group/api", false); err != nil {
+		t.Fatalf("SetStarred(false) failed: %v", err)
+	}
+	if client.cachedStarred["group/api"] {
+		t.Error("Expected group/api to leave the cached starred set")
+	}
+	if err := client.SetStarred("group/missing", true); err == nil {
+		t.Error("Expected an error for a missing project")
+	}
+
+	want := []string{"POST /api/v4/projects/group%2Fapi/star", "POST /api/v4/projects/group%2Fapi/unstar", "POST /api/v4/projects/group%2Fmissing/star"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("Requests = %v, want %v", requests, want)
+	}
+}
@@ -0,0 +1,244 @@
+package gitlab
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/igusev/glf/internal/logger"
+)
+
+// etagMaxAge drops cached responses not requested for this long (a full sync, which requests
+// every page, runs at least weekly)
+const etagMaxAge = 14 * 24 * time.Hour
+
+// etagEntry is a cached GET response
+type etagEntry struct {
+	ETag   string
+	Header http.Header
+	Body   []byte    // gzip-compressed
+	Used   time.Time // Last request of the URL
+}
+
+// ETagCache keeps the ETag and body of GET responses per URL (e.g. each page of the project list)
+// Requests of a cached URL send If-None-Match, and an unchanged response (304 Not Modified)
+// is answered from the cache: it costs the server almost nothing and doesn't count against
+// rate limits. The cache is saved in the cache directory, so the next sync reuses it
+type ETagCache struct {
+	path string
+
+	mu       sync.Mutex
+	entries  map[string]etagEntry
+	requests int // Conditional requests sent since loading
+	hits     int // Of which answered 304
+}
+
+// LoadETagCache loads the ETag cache saved at path; a missing or unreadable file yields an empty cache
+func LoadETagCache(path string) *ETagCache {
+	cache := &ETagCache{path: path, entries: make(map[string]etagEntry)}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Debug("Failed to open ETag cache: %v", err)
+		}
+		return cache
+	}
+	defer func() { _ = file.Close() }()
+
+	var entries map[string]etagEntry
+	if err := gob.NewDecoder(file).Decode(&entries); err != nil {
+		logger.Debug("Ignoring unreadable ETag cache: %v", err)
+		return cache
+	}
+	if entries != nil {
+		cache.entries = entries
+	}
+	return cache
+}
+
+// Save writes the cache to its file, without the responses not requested for etagMaxAge
+func (c *ETagCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for url, entry := range c.entries {
+		if time.Since(entry.Used) > etagMaxAge {
+			delete(c.entries, url)
+		}
+	}
+	if c.requests > 0 {
+		logger.Debug("ETag cache: %d of %d conditional requests not modified", c.hits, c.requests)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0750); err != nil {
+		return fmt.Errorf("failed to create ETag cache directory: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(c.entries); err != nil {
+		return fmt.Errorf("failed to encode ETag cache: %w", err)
+	}
+	tempPath := c.path + ".tmp"
+	if err := os.WriteFile(tempPath, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write ETag cache: %w", err)
+	}
+	if err := os.Rename(tempPath, c.path); err != nil {
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to save ETag cache: %w", err)
+	}
+	return nil
+}
+
+// lookup returns the cached response of url
+func (c *ETagCache) lookup(url string) (etagEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[url]
+	return entry, ok
+}
+
+// store caches the response of url
+func (c *ETagCache) store(url string, entry etagEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[url] = entry
+}
+
+// count records a conditional request and whether it was answered 304, and marks the
+// cached response of url as used
+func (c *ETagCache) count(url string, notModified bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests++
+	if notModified {
+		c.hits++
+		if entry, ok := c.entries[url]; ok {
+			entry.Used = time.Now()
+			c.entries[url] = entry
+		}
+	}
+}
+
+// etagTransport makes GET requests conditional on the response cached for their URL
+type etagTransport struct {
+	base http.RoundTripper
+
+	mu    sync.RWMutex
+	cache *ETagCache // nil = no caching
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	cache := t.cache
+	t.mu.RUnlock()
+	if cache == nil || req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	url := req.URL.String()
+	cached, ok := cache.lookup(url)
+	if ok {
+		// RoundTrippers must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		cache.count(url, resp.StatusCode == http.StatusNotModified)
+	}
+
+	switch {
+	case ok && resp.StatusCode == http.StatusNotModified:
+		body, err := gunzip(cached.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cached response: %w", err)
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		// Answer as the server did the first time (pagination headers included)
+		replay := *resp
+		replay.StatusCode = http.StatusOK
+		replay.Status = "200 OK"
+		replay.Header = cached.Header.Clone()
+		replay.Header.Set("Content-Length", strconv.Itoa(len(body)))
+		replay.ContentLength = int64(len(body))
+		replay.Body = io.NopCloser(bytes.NewReader(body))
+		return &replay, nil
+
+	case resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if compressed, err := gzipBytes(body); err == nil {
+			cache.store(url, etagEntry{ETag: resp.Header.Get("ETag"), Header: resp.Header.Clone(), Body: compressed, Used: time.Now()})
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return resp, nil
+	}
+	return resp, nil
+}
+
+// SetETagCache makes the client's GET requests conditional on the responses in cache
+// (nil disables it); save the cache after the requests to reuse it next time
+func (c *Client) SetETagCache(cache *ETagCache) {
+	c.etags.mu.Lock()
+	defer c.etags.mu.Unlock()
+	c.etags.cache = cache
+}
+
+// SaveETagCache saves the client's ETag cache (nothing without one)
+func (c *Client) SaveETagCache() error {
+	c.etags.mu.RLock()
+	cache := c.etags.cache
+	c.etags.mu.RUnlock()
+	if cache == nil {
+		return nil
+	}
+	return cache.Save()
+}
+
+// hasETagCache reports whether the client uses an ETag cache
+func (c *Client) hasETagCache() bool {
+	c.etags.mu.RLock()
+	defer c.etags.mu.RUnlock()
+	return c.etags.cache != nil
+}
+
+// gzipBytes compresses data
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gunzip decompresses data compressed by gzipBytes
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return io.ReadAll(reader)
+}
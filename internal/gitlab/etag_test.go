@@ -0,0 +1,110 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestETagCache_NotModifiedPages(t *testing.T) {
+	pages := map[int][]map[string]interface{}{
+		1: {{"id": 1, "path_with_namespace": "group/p1", "name": "P1"}},
+		2: {{"id": 2, "path_with_namespace": "group/p2", "name": "P2"}},
+	}
+	var notModified, full atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		etag := fmt.Sprintf(`W/"page-%d"`, page)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("X-Total-Pages", "2")
+		w.Header().Set("X-Total", "2")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(pages[page])
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "etags.gob")
+	fetch := func() []string {
+		t.Helper()
+		client, err := New(server.URL, "test-token", 5*time.Second)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		client.SetCachedProjectSets(map[string]bool{}, map[string]bool{})
+		client.SetETagCache(LoadETagCache(cachePath))
+		projects, err := client.FetchAllProjects(nil, true)
+		if err != nil {
+			t.Fatalf("FetchAllProjects failed: %v", err)
+		}
+		if err := client.SaveETagCache(); err != nil {
+			t.Fatalf("SaveETagCache failed: %v", err)
+		}
+		var paths []string
+		for _, p := range projects {
+			paths = append(paths, p.Path)
+		}
+		return paths
+	}
+
+	want := []string{"group/p1", "group/p2"}
+	if got := fetch(); !slices.Equal(got, want) {
+		t.Fatalf("First fetch = %v, want %v", got, want)
+	}
+	if full.Load() != 2 || notModified.Load() != 0 {
+		t.Fatalf("First fetch: %d full and %d 304 responses, want 2 and 0", full.Load(), notModified.Load())
+	}
+
+	// The next client (the next sync) gets 304s and the same projects, pagination included
+	if got := fetch(); !slices.Equal(got, want) {
+		t.Errorf("Cached fetch = %v, want %v", got, want)
+	}
+	if full.Load() != 2 || notModified.Load() != 2 {
+		t.Errorf("Cached fetch: %d full and %d 304 responses, want 2 and 2", full.Load(), notModified.Load())
+	}
+}
+
+func TestETagCache_IncrementalSince(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Query().Get("last_activity_after"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client.SetCachedProjectSets(map[string]bool{}, map[string]bool{})
+	client.SetETagCache(LoadETagCache(filepath.Join(t.TempDir(), "etags.gob")))
+
+	// Syncs within the same hour request the same URL
+	for _, since := range []time.Time{
+		time.Date(2025, 3, 1, 10, 5, 0, 0, time.UTC),
+		time.Date(2025, 3, 1, 10, 40, 0, 0, time.UTC),
+	} {
+		if _, err := client.FetchAllProjects(&since, false); err != nil {
+			t.Fatalf("FetchAllProjects failed: %v", err)
+		}
+	}
+	if len(got) != 2 || got[0] != got[1] || got[0] != "2025-03-01T10:00:00Z" {
+		t.Errorf("last_activity_after = %v, want 2025-03-01T10:00:00Z twice", got)
+	}
+}
@@ -0,0 +1,169 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/igusev/glf/internal/logger"
+	gitlab "gitlab.com/gitlab-org/api/client-go"
+)
+
+const (
+	// DefaultMaxRetries is the default number of retries of a failed API request (gitlab.max_retries)
+	DefaultMaxRetries = 3
+	// DefaultRetryBackoff is the default wait before the first retry (gitlab.retry_backoff)
+	// Each further retry waits twice as long, with jitter
+	DefaultRetryBackoff = time.Second
+	// maxRetryWait caps the wait between two attempts
+	maxRetryWait = 30 * time.Second
+)
+
+// ErrDeadlineExceeded is returned for API requests made after the client's deadline (see SetDeadline)
+var ErrDeadlineExceeded = errors.New("GitLab API time limit exceeded")
+
+// Retry policy applied to clients created by New (see SetRetryPolicy)
+var (
+	maxRetries   = DefaultMaxRetries
+	retryBackoff = DefaultRetryBackoff
+)
+
+// SetRetryPolicy sets how often and how patiently clients created afterwards retry failed requests
+// A negative maxRetries or non-positive backoff keeps the built-in default; 0 retries disables retrying
+func SetRetryPolicy(retries int, backoff time.Duration) {
+	maxRetries = DefaultMaxRetries
+	if retries >= 0 {
+		maxRetries = retries
+	}
+	retryBackoff = DefaultRetryBackoff
+	if backoff > 0 {
+		retryBackoff = backoff
+	}
+}
+
+// retryOptions configures the API client with the retry policy
+func retryOptions() []gitlab.ClientOptionFunc {
+	return []gitlab.ClientOptionFunc{
+		gitlab.WithCustomRetryMax(maxRetries),
+		gitlab.WithCustomRetryWaitMinMax(retryBackoff, maxRetryWait),
+		gitlab.WithCustomRetry(checkRetry),
+		gitlab.WithCustomBackoff(retryWait),
+	}
+}
+
+// checkRetry decides whether a failed attempt is retried
+// Besides rate limiting and server errors, GET requests are retried on any network error,
+// including timeouts and dropped connections (reading is safe to repeat). Other methods
+// are retried only when the connection could not be established
+func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	if err != nil {
+		if errors.Is(err, ErrDeadlineExceeded) {
+			return false, err
+		}
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) && (urlErr.Op == "Get" || urlErr.Op == "Head") {
+			logger.Debug("Retrying after network error: %v", err)
+			return true, nil
+		}
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			logger.Debug("Retrying after connection error: %v", err)
+			return true, nil
+		}
+		return false, nil
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == 0 ||
+		(resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented) {
+		logger.Debug("Retrying after HTTP %d", resp.StatusCode)
+		return true, nil
+	}
+	return false, nil
+}
+
+// retryWait returns the wait before retry attemptNum (0-based): exponential backoff starting
+// at minWait with jitter (between half and all of the exponential step), capped at maxWait
+// A Retry-After header of a rate-limited or unavailable server is honored when longer
+func retryWait(minWait, maxWait time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	step := minWait << min(attemptNum, 16)
+	if step <= 0 || step > maxWait {
+		step = maxWait
+	}
+	wait := step/2 + rand.N(step/2+1)
+
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			if retryAfter := time.Duration(seconds) * time.Second; retryAfter > wait {
+				wait = retryAfter
+			}
+		}
+	}
+	return min(wait, maxWait)
+}
+
+// deadlineTransport fails requests once the client's deadline has passed, and cuts off
+// requests still running at the deadline (bounds a whole sync regardless of retries)
+type deadlineTransport struct {
+	base     http.RoundTripper
+	deadline atomic.Int64 // Unix nanoseconds, 0 = no deadline
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *deadlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	deadlineNanos := t.deadline.Load()
+	if deadlineNanos == 0 {
+		return t.base.RoundTrip(req)
+	}
+	deadline := time.Unix(0, deadlineNanos)
+	if !time.Now().Before(deadline) {
+		return nil, ErrDeadlineExceeded
+	}
+
+	ctx, cancel := context.WithDeadline(req.Context(), deadline)
+	resp, err := t.base.RoundTrip(req.Clone(ctx))
+	if err != nil {
+		cancel()
+		if ctx.Err() != nil && req.Context().Err() == nil {
+			return nil, fmt.Errorf("%w: %w", ErrDeadlineExceeded, err)
+		}
+		return nil, err
+	}
+	// The deadline also covers reading the body; release it once the body is closed
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases a request's context when its response body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// SetDeadline makes API requests fail with ErrDeadlineExceeded after deadline, so a sync
+// on a slow or flaky network gives up after a fixed time instead of retrying on and on
+// The zero time removes the deadline
+func (c *Client) SetDeadline(deadline time.Time) {
+	if deadline.IsZero() {
+		c.transport.deadline.Store(0)
+		return
+	}
+	c.transport.deadline.Store(deadline.UnixNano())
+}
@@ -0,0 +1,143 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// userServer serves /api/v4/user, failing the first failures requests with fail
+func userServer(t *testing.T, failures int32, fail func(w http.ResponseWriter)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			fail(w)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": 1, "username": "alice"})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestRetry_ServerErrors(t *testing.T) {
+	t.Cleanup(func() { SetRetryPolicy(-1, 0) })
+	badGateway := func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) }
+
+	SetRetryPolicy(2, time.Millisecond)
+	server, requests := userServer(t, 2, badGateway)
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if username, err := client.GetCurrentUsername(); err != nil || username != "alice" {
+		t.Fatalf("GetCurrentUsername() = %q, %v; want alice after two retries", username, err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+
+	// Out of retries
+	SetRetryPolicy(1, time.Millisecond)
+	server, requests = userServer(t, 2, badGateway)
+	client, _ = New(server.URL, "test-token", 5*time.Second)
+	if _, err := client.GetCurrentUsername(); err == nil {
+		t.Error("Expected an error once retries are used up")
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected 2 attempts with max_retries 1, got %d", got)
+	}
+
+	// Client errors are not retried
+	SetRetryPolicy(3, time.Millisecond)
+	server, requests = userServer(t, 1, func(w http.ResponseWriter) { w.WriteHeader(http.StatusUnauthorized) })
+	client, _ = New(server.URL, "test-token", 5*time.Second)
+	if _, err := client.GetCurrentUsername(); err == nil {
+		t.Error("Expected 401 to fail")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected a single attempt for 401, got %d", got)
+	}
+}
+
+func TestRetry_Timeout(t *testing.T) {
+	t.Cleanup(func() { SetRetryPolicy(-1, 0) })
+	SetRetryPolicy(2, time.Millisecond)
+
+	// The first attempt hangs past the per-request timeout, like a request lost on a flaky VPN
+	server, requests := userServer(t, 1, func(w http.ResponseWriter) { time.Sleep(300 * time.Millisecond) })
+	client, err := New(server.URL, "test-token", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if username, err := client.GetCurrentUsername(); err != nil || username != "alice" {
+		t.Fatalf("GetCurrentUsername() = %q, %v; want alice after a retry", username, err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestSetDeadline(t *testing.T) {
+	server, requests := userServer(t, 0, nil)
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	client.SetDeadline(time.Now().Add(-time.Second))
+	if _, err := client.GetCurrentUsername(); !errors.Is(err, ErrDeadlineExceeded) {
+		t.Errorf("Expected ErrDeadlineExceeded after the deadline, got %v", err)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("Expected no request after the deadline, got %d", got)
+	}
+
+	client.SetDeadline(time.Now().Add(time.Minute))
+	if _, err := client.GetCurrentUsername(); err != nil {
+		t.Errorf("Expected requests before the deadline to succeed, got %v", err)
+	}
+	client.SetDeadline(time.Time{})
+	if _, err := client.GetCurrentUsername(); err != nil {
+		t.Errorf("Expected requests without a deadline to succeed, got %v", err)
+	}
+}
+
+func TestRetryWait(t *testing.T) {
+	for attempt := 0; attempt < 4; attempt++ {
+		step := time.Second << attempt
+		for i := 0; i < 20; i++ {
+			if wait := retryWait(time.Second, maxRetryWait, attempt, nil); wait < step/2 || wait > step {
+				t.Fatalf("attempt %d: wait %v outside [%v, %v]", attempt, wait, step/2, step)
+			}
+		}
+	}
+	if wait := retryWait(time.Second, maxRetryWait, 20, nil); wait > maxRetryWait {
+		t.Errorf("Expected the wait to be capped at %v, got %v", maxRetryWait, wait)
+	}
+
+	// Retry-After of a rate-limited server wins when longer
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"5"}}}
+	if wait := retryWait(time.Second, maxRetryWait, 0, resp); wait != 5*time.Second {
+		t.Errorf("Expected Retry-After to be honored, got %v", wait)
+	}
+}
+
+func TestSetRetryPolicy(t *testing.T) {
+	t.Cleanup(func() { SetRetryPolicy(-1, 0) })
+
+	SetRetryPolicy(0, 2*time.Second)
+	if maxRetries != 0 || retryBackoff != 2*time.Second {
+		t.Errorf("Expected 0 retries with a 2s backoff, got %d, %v", maxRetries, retryBackoff)
+	}
+	SetRetryPolicy(-1, 0)
+	if maxRetries != DefaultMaxRetries || retryBackoff != DefaultRetryBackoff {
+		t.Errorf("Expected defaults, got %d, %v", maxRetries, retryBackoff)
+	}
+}
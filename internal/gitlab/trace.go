@@ -0,0 +1,34 @@
+package gitlab
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/igusev/glf/internal/logger"
+)
+
+// RequestIDHeader carries the glf trace ID so server-side logs can be correlated with a user's debug log
+const RequestIDHeader = "X-Request-Id"
+
+// traceTransport tags every API request with the invocation's trace ID and logs its timing
+type traceTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if id := logger.TraceID(); id != "" && req.Header.Get(RequestIDHeader) == "" {
+		// RoundTrippers must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set(RequestIDHeader, id)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		logger.Debug("API %s %s failed after %v: %v", req.Method, req.URL.Path, time.Since(start).Round(time.Millisecond), err)
+		return nil, err
+	}
+	logger.Debug("API %s %s -> %d (%v)", req.Method, req.URL.Path, resp.StatusCode, time.Since(start).Round(time.Millisecond))
+	return resp, nil
+}
@@ -0,0 +1,105 @@
+package gitlab
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ServerVersion describes the GitLab instance reported by GET /version
+type ServerVersion struct {
+	Version    string // Full version string (e.g., "16.11.2-ee")
+	Revision   string // Git revision of the GitLab build
+	Major      int    // Major version (0 if unparseable)
+	Minor      int    // Minor version
+	Enterprise bool   // Whether this is GitLab EE (version suffix "-ee")
+}
+
+// Feature is an API capability that only exists in newer GitLab versions
+type Feature struct {
+	Name     string // Human-readable name used in warnings
+	Major    int    // Minimum major version
+	Minor    int    // Minimum minor version
+	Fallback string // What glf does without it
+}
+
+// FeatureTokenInfo is GET /personal_access_tokens/self, added in GitLab 15.5
+// (project topics, which replaced tag_list in 14.0, fall back to tag_list without a version check)
+var FeatureTokenInfo = Feature{Name: "token expiry check", Major: 15, Minor: 5, Fallback: "token expiry warnings are disabled"}
+
+// versionedFeatures lists all features checked after connecting
+var versionedFeatures = []Feature{FeatureTokenInfo}
+
+// String formats the minimum version of a feature
+func (f Feature) String() string {
+	return fmt.Sprintf("%s (GitLab %d.%d+)", f.Name, f.Major, f.Minor)
+}
+
+// parseServerVersion parses a GitLab version string such as "16.11.2-ee"
+func parseServerVersion(version, revision string) *ServerVersion {
+	v := &ServerVersion{Version: version, Revision: revision}
+	v.Enterprise = strings.HasSuffix(version, "-ee")
+
+	core, _, _ := strings.Cut(version, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) >= 2 {
+		major, majorErr := strconv.Atoi(parts[0])
+		minor, minorErr := strconv.Atoi(parts[1])
+		if majorErr == nil && minorErr == nil {
+			v.Major, v.Minor = major, minor
+		}
+	}
+	return v
+}
+
+// Edition returns "EE" or "CE"
+func (v *ServerVersion) Edition() string {
+	if v.Enterprise {
+		return "EE"
+	}
+	return "CE"
+}
+
+// Supports reports whether the version has the feature
+// Unknown versions (nil or unparseable) are assumed to support everything
+func (v *ServerVersion) Supports(f Feature) bool {
+	if v == nil || v.Major == 0 {
+		return true
+	}
+	if v.Major != f.Major {
+		return v.Major > f.Major
+	}
+	return v.Minor >= f.Minor
+}
+
+// DetectVersion probes GET /version and remembers the result for Supports checks
+// Failures are not fatal: the version stays unknown and all features stay enabled
+func (c *Client) DetectVersion() (*ServerVersion, error) {
+	version, _, err := c.client.Version.GetVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect GitLab version: %w", err)
+	}
+	c.version = parseServerVersion(version.Version, version.Revision)
+	return c.version, nil
+}
+
+// ServerVersion returns the version detected by TestConnection (nil if unknown)
+func (c *Client) ServerVersion() *ServerVersion {
+	return c.version
+}
+
+// Supports reports whether the connected instance has the feature (true if the version is unknown)
+func (c *Client) Supports(f Feature) bool {
+	return c.version.Supports(f)
+}
+
+// UnsupportedFeatures returns the features the connected instance lacks
+func (c *Client) UnsupportedFeatures() []Feature {
+	var missing []Feature
+	for _, f := range versionedFeatures {
+		if !c.Supports(f) {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
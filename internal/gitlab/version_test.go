@@ -0,0 +1,153 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	tests := []struct {
+		version    string
+		major      int
+		minor      int
+		enterprise bool
+	}{
+		{"16.11.2-ee", 16, 11, true},
+		{"14.0.0", 14, 0, false},
+		{"13.12.15-ce.0", 13, 12, false},
+		{"garbage", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		v := parseServerVersion(tt.version, "abc123")
+		if v.Major != tt.major || v.Minor != tt.minor || v.Enterprise != tt.enterprise {
+			t.Errorf("parseServerVersion(%q) = %d.%d ee=%v, want %d.%d ee=%v",
+				tt.version, v.Major, v.Minor, v.Enterprise, tt.major, tt.minor, tt.enterprise)
+		}
+	}
+}
+
+func TestServerVersion_Supports(t *testing.T) {
+	feature := Feature{Name: "test", Major: 15, Minor: 5}
+
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"15.5.0", true},
+		{"15.10.1-ee", true},
+		{"16.0.0", true},
+		{"15.4.3", false},
+		{"14.10.0", false},
+		{"unknown", true}, // Unparseable versions keep everything enabled
+	}
+	for _, tt := range tests {
+		if got := parseServerVersion(tt.version, "").Supports(feature); got != tt.want {
+			t.Errorf("Supports(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+
+	var unknown *ServerVersion
+	if !unknown.Supports(feature) {
+		t.Error("Expected nil version to support all features")
+	}
+}
+
+func TestTestConnection_DetectsVersion(t *testing.T) {
+	var tokenInfoCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v4/user":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 1, "username": "testuser"})
+		case "/api/v4/version":
+			json.NewEncoder(w).Encode(map[string]interface{}{"version": "13.12.15", "revision": "d1a2b3c"})
+		case "/api/v4/personal_access_tokens/self":
+			tokenInfoCalls++
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if err := client.TestConnection(); err != nil {
+		t.Fatalf("TestConnection failed: %v", err)
+	}
+
+	version := client.ServerVersion()
+	if version == nil || version.Major != 13 || version.Edition() != "CE" {
+		t.Fatalf("Expected GitLab CE 13.x, got %+v", version)
+	}
+	if missing := client.UnsupportedFeatures(); len(missing) != 1 || missing[0].Name != FeatureTokenInfo.Name {
+		t.Errorf("Expected token info to be unsupported, got %v", missing)
+	}
+
+	// Unsupported endpoints are not called at all
+	if _, err := client.GetTokenInfo(); err == nil {
+		t.Error("Expected GetTokenInfo to fail on GitLab 13.12")
+	}
+	if tokenInfoCalls != 0 {
+		t.Errorf("Expected no request to /personal_access_tokens/self, got %d", tokenInfoCalls)
+	}
+}
+
+func TestTestConnection_VersionProbeFailure(t *testing.T) {
+	// /version failing must not fail the connection test
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/user" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1, "username": "testuser"}`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if err := client.TestConnection(); err != nil {
+		t.Fatalf("TestConnection failed: %v", err)
+	}
+	if client.ServerVersion() != nil {
+		t.Error("Expected unknown version after failed probe")
+	}
+	if len(client.UnsupportedFeatures()) != 0 {
+		t.Error("Expected all features enabled for an unknown version")
+	}
+}
+
+func TestProjectTopics_TagListFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Pages", "1")
+		if r.URL.Query().Get("starred") == "true" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		// GitLab < 14.0 returns tag_list instead of topics
+		w.Write([]byte(`[{"id": 1, "path_with_namespace": "group/legacy", "name": "legacy", "tag_list": ["golang"]}]`))
+	}))
+	defer server.Close()
+
+	client, err := New(server.URL, "test-token", 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	projects, err := client.FetchAllProjects(nil, true)
+	if err != nil {
+		t.Fatalf("FetchAllProjects failed: %v", err)
+	}
+	if len(projects) != 1 || len(projects[0].Topics) != 1 || projects[0].Topics[0] != "golang" {
+		t.Errorf("Expected topics from tag_list, got %+v", projects)
+	}
+}
@@ -0,0 +1,100 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Algorithm names accepted by history.algorithm
+const (
+	AlgorithmDecay    = "decay"    // Exponential decay with a configurable half-life (default)
+	AlgorithmFrecency = "frecency" // Mozilla-style recency buckets
+)
+
+// Algorithm weighs a single selection by its age
+// A project's history score is the sum of the weights of its selections
+// (query-specific selections are additionally multiplied by queryBoost)
+type Algorithm interface {
+	// Name returns the history.algorithm value that selects the algorithm
+	Name() string
+	// Weight returns the weight of a selection made ageDays ago (0 = ignored)
+	Weight(ageDays float64) float64
+}
+
+// Decay weighs selections by exponential decay: e^(-λt) with λ = ln(2) / half-life
+type Decay struct {
+	HalfLifeDays float64 // Days for a selection's weight to decay to 50%
+	MaxAgeDays   float64 // Selections older than this weigh 0
+}
+
+// Name returns AlgorithmDecay
+func (d Decay) Name() string {
+	return AlgorithmDecay
+}
+
+// Weight returns the exponential decay multiplier for the given age
+func (d Decay) Weight(ageDays float64) float64 {
+	return decayMultiplier(ageDays, d.HalfLifeDays, d.MaxAgeDays)
+}
+
+// frecencyBucket is an age range with a fixed weight
+type frecencyBucket struct {
+	maxAgeDays float64 // Upper bound of the bucket (inclusive)
+	weight     float64 // Weight of selections in the bucket
+}
+
+// frecencyBuckets are Firefox's visit recency buckets (100/70/50/30/10 points), scaled so a
+// selection from the last 4 days weighs 1.0 like a fresh selection under decay
+var frecencyBuckets = []frecencyBucket{
+	{maxAgeDays: 4, weight: 1.0},
+	{maxAgeDays: 14, weight: 0.7},
+	{maxAgeDays: 31, weight: 0.5},
+	{maxAgeDays: 90, weight: 0.3},
+}
+
+// frecencyOldWeight is the weight of selections older than the last bucket
+const frecencyOldWeight = 0.1
+
+// Frecency weighs selections by recency bucket (Mozilla "frecency")
+// Unlike decay the weight is a step function: every selection from this week counts fully,
+// and old habits keep a small weight until MaxAgeDays instead of fading out smoothly
+type Frecency struct {
+	MaxAgeDays float64 // Selections older than this weigh 0
+}
+
+// Name returns AlgorithmFrecency
+func (f Frecency) Name() string {
+	return AlgorithmFrecency
+}
+
+// Weight returns the bucket weight for the given age
+func (f Frecency) Weight(ageDays float64) float64 {
+	if ageDays > f.MaxAgeDays {
+		return 0.0
+	}
+	for _, bucket := range frecencyBuckets {
+		if ageDays <= bucket.maxAgeDays {
+			return bucket.weight
+		}
+	}
+	return frecencyOldWeight
+}
+
+// NewAlgorithm returns the named algorithm ("" = decay)
+// halfLifeDays is only used by decay; non-positive values use the built-in defaults
+func NewAlgorithm(name string, halfLifeDays, maxAgeDays float64) (Algorithm, error) {
+	if halfLifeDays <= 0 {
+		halfLifeDays = DefaultHalfLifeDays
+	}
+	if maxAgeDays <= 0 {
+		maxAgeDays = DefaultMaxAgeDays
+	}
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", AlgorithmDecay:
+		return Decay{HalfLifeDays: halfLifeDays, MaxAgeDays: maxAgeDays}, nil
+	case AlgorithmFrecency:
+		return Frecency{MaxAgeDays: maxAgeDays}, nil
+	}
+	return nil, fmt.Errorf("unknown history algorithm %q (expected %s or %s)", name, AlgorithmDecay, AlgorithmFrecency)
+}
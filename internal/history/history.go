@@ -3,6 +3,7 @@ package history
 
 import (
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"math"
@@ -16,14 +17,83 @@ import (
 )
 
 const (
-	// halfLifeDays is the number of days for score to decay to 50%
-	halfLifeDays = 30.0
-	// maxAgeDays is the maximum age for history entries (older entries are ignored/cleaned)
-	maxAgeDays = 100.0
-	// decayLambda is the decay constant: ln(2) / half_life
-	decayLambda = 0.693147 / halfLifeDays // ≈ 0.0231
+	// DefaultHalfLifeDays is the default number of days for score to decay to 50% (history.half_life_days)
+	DefaultHalfLifeDays = 30.0
+	// DefaultMaxAgeDays is the default maximum age for history entries (history.max_age_days)
+	// Older entries are ignored and cleaned up on load
+	DefaultMaxAgeDays = 100.0
+	// queryBoost is the weight of a selection made with the same query (vs 1.0 for any selection)
+	queryBoost = 2.5
+	// maxHistoryScore caps history scores to prevent extreme dominance
+	maxHistoryScore = 30
 )
 
+// Context ranking weights (see SetDefaultContextRanking)
+// The day is split into four 6-hour slots: night, morning, afternoon, evening
+const (
+	contextSameSlotWeight     = 1.5 // Selection made in the current time-of-day slot
+	contextAdjacentSlotWeight = 1.0 // Selection made in the slot before or after
+	contextOppositeSlotWeight = 0.5 // Selection made at the opposite time of day
+	contextOtherDayWeight     = 0.5 // Extra factor when weekday/weekend differs from now
+)
+
+// Decay settings applied to histories created by New (see SetDefaultDecay)
+var (
+	defaultHalfLifeDays = DefaultHalfLifeDays
+	defaultMaxAgeDays   = DefaultMaxAgeDays
+
+	defaultContextRanking = false
+
+	defaultAlgorithm = AlgorithmDecay
+
+	defaultEnabled       = true
+	defaultRetentionDays = 0.0
+)
+
+// SetDefaultDecay sets the decay half-life and maximum age used by histories created afterwards
+// Non-positive values keep the built-in defaults
+func SetDefaultDecay(halfLifeDays, maxAgeDays float64) {
+	defaultHalfLifeDays = DefaultHalfLifeDays
+	if halfLifeDays > 0 {
+		defaultHalfLifeDays = halfLifeDays
+	}
+	defaultMaxAgeDays = DefaultMaxAgeDays
+	if maxAgeDays > 0 {
+		defaultMaxAgeDays = maxAgeDays
+	}
+}
+
+// SetDefaultAlgorithm selects the scoring algorithm (AlgorithmDecay or AlgorithmFrecency)
+// used by histories created afterwards; "" restores decay
+func SetDefaultAlgorithm(name string) error {
+	algorithm, err := NewAlgorithm(name, defaultHalfLifeDays, defaultMaxAgeDays)
+	if err != nil {
+		return err
+	}
+	defaultAlgorithm = algorithm.Name()
+	return nil
+}
+
+// SetDefaultContextRanking enables time-of-day ranking for histories created afterwards
+// Global selections made at the same time of day and on the same kind of day
+// (weekday or weekend) as now weigh more than others
+func SetDefaultContextRanking(enabled bool) {
+	defaultContextRanking = enabled
+}
+
+// SetDefaultEnabled turns selection tracking on or off for histories created afterwards
+// A disabled history records nothing; existing entries still rank and can be cleared
+func SetDefaultEnabled(enabled bool) {
+	defaultEnabled = enabled
+}
+
+// SetDefaultRetention sets the number of days selections are kept for histories created
+// afterwards: older ones are purged on every save. Non-positive values keep selections
+// until the maximum age
+func SetDefaultRetention(days float64) {
+	defaultRetentionDays = max(0, days)
+}
+
 // SelectionInfo tracks information about a selected item
 type SelectionInfo struct {
 	Timestamps []time.Time // All selection timestamps (for accurate decay calculation)
@@ -33,6 +103,9 @@ type SelectionInfo struct {
 type historyData struct {
 	Selections      map[string]SelectionInfo
 	QuerySelections map[string]map[string]SelectionInfo
+	QueryTexts      map[string]string // queryHash -> normalized query (absent in files written by older versions)
+	Sources         map[int64]string  // Global selection timestamp (UnixNano) -> source (absent in files written by older versions)
+	ClearedAt       time.Time         // Last Clear; merging drops entries selected before it (zero in files written by older versions)
 }
 
 // History manages selection frequency tracking
@@ -40,11 +113,27 @@ type History struct {
 	mu              sync.RWMutex
 	selections      map[string]SelectionInfo            // Global history: projectPath -> info
 	querySelections map[string]map[string]SelectionInfo // Query-specific: queryHash -> projectPath -> info
+	queryTexts      map[string]string                   // Normalized query text by queryHash (for display)
+	sources         map[int64]string                    // Source of global selections by timestamp (UnixNano)
+	source          string                              // Source tagged on new selections (see SetSource)
 	filePath        string
 	dirty           bool // Indicates if there are unsaved changes
 
 	cachedGlobalScores   map[string]float64 // Cached global decay scores
 	globalScoresCachedAt time.Time          // When global scores were last computed
+
+	halfLifeDays float64 // Days for a selection's weight to decay to 50%
+	maxAgeDays   float64 // Selections older than this are ignored and cleaned up
+
+	contextRanking bool // Weight global selections by time of day and day of week
+
+	algorithm Algorithm // Weighs each selection by its age (decay or frecency)
+
+	enabled       bool    // Record selections (false: RecordSelection* are no-ops)
+	retentionDays float64 // Purge selections older than this on every save (0: keep until maxAgeDays)
+
+	clearedAt time.Time   // Last Clear, here or in the file (older entries are dropped when merging)
+	remaps    []pathRemap // Remaps since the last save (replayed on the file's entries)
 }
 
 // New creates a new History instance with the given file path
@@ -52,9 +141,46 @@ func New(filePath string) *History {
 	return &History{
 		selections:      make(map[string]SelectionInfo),
 		querySelections: make(map[string]map[string]SelectionInfo),
+		queryTexts:      make(map[string]string),
+		sources:         make(map[int64]string),
 		filePath:        filePath,
 		dirty:           false,
+		halfLifeDays:    defaultHalfLifeDays,
+		maxAgeDays:      defaultMaxAgeDays,
+		contextRanking:  defaultContextRanking,
+		algorithm:       newDefaultAlgorithm(),
+		enabled:         defaultEnabled,
+		retentionDays:   defaultRetentionDays,
+	}
+}
+
+// Enabled reports whether selections are recorded (see SetDefaultEnabled)
+func (h *History) Enabled() bool {
+	return h.enabled
+}
+
+// newDefaultAlgorithm returns the default algorithm with the default decay settings
+func newDefaultAlgorithm() Algorithm {
+	algorithm, err := NewAlgorithm(defaultAlgorithm, defaultHalfLifeDays, defaultMaxAgeDays)
+	if err != nil {
+		// defaultAlgorithm is validated by SetDefaultAlgorithm
+		return Decay{HalfLifeDays: defaultHalfLifeDays, MaxAgeDays: defaultMaxAgeDays}
 	}
+	return algorithm
+}
+
+// SetAlgorithm replaces the scoring algorithm (e.g. to compare algorithms on the same history)
+// The maximum age used for cleanup is kept
+func (h *History) SetAlgorithm(algorithm Algorithm) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.algorithm = algorithm
+	h.cachedGlobalScores = nil
+}
+
+// Algorithm returns the scoring algorithm in use
+func (h *History) Algorithm() Algorithm {
+	return h.algorithm
 }
 
 // oldSelectionInfo is the previous format for migration
@@ -82,110 +208,24 @@ func (h *History) LoadAsync() <-chan error {
 	go func() {
 		defer close(errCh)
 
-		// Clean path to prevent directory traversal
-		cleanPath := filepath.Clean(h.filePath)
-		file, err := os.Open(cleanPath)
+		data, upgraded, err := readHistoryFile(h.filePath)
 		if err != nil {
-			if os.IsNotExist(err) {
-				// First run - no history file yet, not an error
-				errCh <- nil
-				return
-			}
-			errCh <- fmt.Errorf("failed to open history file: %w", err)
+			errCh <- err
 			return
 		}
-		defer func() {
-			if err := file.Close(); err != nil {
-				// Ignore close error in async load
-				_ = err
-			}
-		}()
-
-		decoder := gob.NewDecoder(file)
 
 		h.mu.Lock()
-		defer h.mu.Unlock()
-
-		// Try to decode new format first
-		var data historyData
-		if err := decoder.Decode(&data); err != nil {
-			// Failed - might be old format, try decoding
-			if _, seekErr := file.Seek(0, 0); seekErr != nil {
-				// Can't seek - corrupt file, start fresh
-				h.selections = make(map[string]SelectionInfo)
-				h.querySelections = make(map[string]map[string]SelectionInfo)
-				h.dirty = true
-				errCh <- nil
-				return
-			}
-			decoder = gob.NewDecoder(file)
-
-			// Try old historyData format
-			type oldHistoryData struct {
-				Selections      map[string]oldSelectionInfo
-				QuerySelections map[string]map[string]oldSelectionInfo
-			}
-
-			var oldData oldHistoryData
-			if err := decoder.Decode(&oldData); err != nil {
-				// Try even older format (just map)
-				if _, seekErr := file.Seek(0, 0); seekErr != nil {
-					// Can't seek - corrupt file, start fresh
-					h.selections = make(map[string]SelectionInfo)
-					h.querySelections = make(map[string]map[string]SelectionInfo)
-					h.dirty = true
-					errCh <- nil
-					return
-				}
-				decoder = gob.NewDecoder(file)
-
-				var veryOldSelections map[string]oldSelectionInfo
-				if err := decoder.Decode(&veryOldSelections); err != nil {
-					// All formats failed - corrupt file, start fresh
-					h.selections = make(map[string]SelectionInfo)
-					h.querySelections = make(map[string]map[string]SelectionInfo)
-					h.dirty = true
-					errCh <- nil
-					return
-				}
-
-				// Migrate very old format to new
-				h.selections = make(map[string]SelectionInfo)
-				for item, oldInfo := range veryOldSelections {
-					h.selections[item] = migrateOldSelection(oldInfo)
-				}
-				h.querySelections = make(map[string]map[string]SelectionInfo)
-			} else {
-				// Migrate old historyData format to new
-				h.selections = make(map[string]SelectionInfo)
-				for item, oldInfo := range oldData.Selections {
-					h.selections[item] = migrateOldSelection(oldInfo)
-				}
-				h.querySelections = make(map[string]map[string]SelectionInfo)
-				for queryHash, oldQuerySelections := range oldData.QuerySelections {
-					h.querySelections[queryHash] = make(map[string]SelectionInfo)
-					for item, oldInfo := range oldQuerySelections {
-						h.querySelections[queryHash][item] = migrateOldSelection(oldInfo)
-					}
-				}
-			}
-			h.dirty = true // Mark dirty to trigger save with new format
-		} else {
-			// New format loaded successfully
-			h.selections = data.Selections
-			if data.QuerySelections != nil {
-				h.querySelections = data.QuerySelections
-			} else {
-				h.querySelections = make(map[string]map[string]SelectionInfo)
-			}
-			h.dirty = false
-		}
+		h.selections = data.Selections
+		h.querySelections = data.QuerySelections
+		h.queryTexts = data.QueryTexts
+		h.sources = data.Sources
+		h.clearedAt = data.ClearedAt
+		h.dirty = upgraded // Save migrated or corrupt files in the current format
+		h.mu.Unlock()
 
-		// Cleanup old entries (older than maxAgeDays)
+		// Cleanup old entries (older than h.maxAgeDays or the retention period)
 		// This is done in the loading goroutine to avoid blocking
-		h.mu.Unlock()
 		removed := h.CleanupOldEntries()
-		h.mu.Lock()
 
 		if removed > 0 {
 			// Save after cleanup to persist the changes
@@ -204,27 +244,195 @@ func (h *History) LoadAsync() <-chan error {
 	return errCh
 }
 
+// ErrCorrupt indicates a history file that none of the known formats can read
+var ErrCorrupt = errors.New("history file is damaged")
+
+// Verify reads the history file at path and returns ErrCorrupt if it can't be decoded
+// (loading such a file starts with empty history, and the next save overwrites it)
+// A missing file is not an error
+func Verify(path string) error {
+	_, _, err := decodeHistoryFile(path)
+	return err
+}
+
+// readHistoryFile reads the history file at path, migrating older formats
+// A missing file (first run) yields empty history; upgraded reports a file in an older
+// format or a corrupt one (read as empty), which the next save rewrites
+func readHistoryFile(path string) (data historyData, upgraded bool, err error) {
+	data, upgraded, err = decodeHistoryFile(path)
+	if errors.Is(err, ErrCorrupt) {
+		return data, true, nil
+	}
+	return data, upgraded, err
+}
+
+// decodeHistoryFile is readHistoryFile, but returns ErrCorrupt (with empty history) for a corrupt file
+func decodeHistoryFile(path string) (data historyData, upgraded bool, err error) {
+	data = historyData{
+		Selections:      make(map[string]SelectionInfo),
+		QuerySelections: make(map[string]map[string]SelectionInfo),
+		QueryTexts:      make(map[string]string),
+		Sources:         make(map[int64]string),
+	}
+
+	// Clean path to prevent directory traversal
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			// First run - no history file yet, not an error
+			return data, false, nil
+		}
+		return data, false, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			// Ignore close error on read
+			_ = err
+		}
+	}()
+
+	// Try to decode new format first
+	var current historyData
+	if err := gob.NewDecoder(file).Decode(&current); err == nil {
+		if current.Selections != nil {
+			data.Selections = current.Selections
+		}
+		if current.QuerySelections != nil {
+			data.QuerySelections = current.QuerySelections
+		}
+		if current.QueryTexts != nil {
+			data.QueryTexts = current.QueryTexts
+		}
+		if current.Sources != nil {
+			data.Sources = current.Sources
+		}
+		data.ClearedAt = current.ClearedAt
+		return data, false, nil
+	}
+
+	// Failed - might be old historyData format
+	type oldHistoryData struct {
+		Selections      map[string]oldSelectionInfo
+		QuerySelections map[string]map[string]oldSelectionInfo
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		// Can't seek - corrupt file, start fresh
+		return data, true, ErrCorrupt
+	}
+	var oldData oldHistoryData
+	if err := gob.NewDecoder(file).Decode(&oldData); err == nil {
+		for item, oldInfo := range oldData.Selections {
+			data.Selections[item] = migrateOldSelection(oldInfo)
+		}
+		for queryHash, oldQuerySelections := range oldData.QuerySelections {
+			data.QuerySelections[queryHash] = make(map[string]SelectionInfo)
+			for item, oldInfo := range oldQuerySelections {
+				data.QuerySelections[queryHash][item] = migrateOldSelection(oldInfo)
+			}
+		}
+		return data, true, nil
+	}
+
+	// Try even older format (just map)
+	if _, err := file.Seek(0, 0); err != nil {
+		return data, true, ErrCorrupt
+	}
+	var veryOldSelections map[string]oldSelectionInfo
+	if err := gob.NewDecoder(file).Decode(&veryOldSelections); err != nil {
+		// All formats failed - corrupt file, start fresh
+		return data, true, ErrCorrupt
+	}
+	for item, oldInfo := range veryOldSelections {
+		data.Selections[item] = migrateOldSelection(oldInfo)
+	}
+	return data, true, nil
+}
+
 // RecordSelection records a selection of the given item
+// Does nothing when tracking is disabled
 func (h *History) RecordSelection(item string) {
+	if !h.enabled {
+		return
+	}
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	now := time.Now()
 	info := h.selections[item]
-	info.Timestamps = append(info.Timestamps, time.Now())
+	info.Timestamps = append(info.Timestamps, now)
 	h.selections[item] = info
+	h.tagLocked(now)
 	h.dirty = true
 	h.cachedGlobalScores = nil
 }
 
 // calculateDecayMultiplier returns the exponential decay multiplier for the given age
+// using the built-in half-life and maximum age
+func calculateDecayMultiplier(daysSinceLastUse float64) float64 {
+	return decayMultiplier(daysSinceLastUse, DefaultHalfLifeDays, DefaultMaxAgeDays)
+}
+
+// decayMultiplier returns the exponential decay multiplier for the given age
 // Uses formula: e^(-λt) where λ = ln(2) / half_life
 // Returns 0 for entries older than maxAgeDays
-func calculateDecayMultiplier(daysSinceLastUse float64) float64 {
+func decayMultiplier(daysSinceLastUse, halfLifeDays, maxAgeDays float64) float64 {
 	if daysSinceLastUse > maxAgeDays {
 		return 0.0 // Ignore very old entries
 	}
 	// Exponential decay: e^(-λt)
-	return math.Exp(-decayLambda * daysSinceLastUse)
+	return math.Exp(-math.Ln2 / halfLifeDays * daysSinceLastUse)
+}
+
+// decay returns the weight of a selection of the given age with this history's algorithm
+func (h *History) decay(daysSinceLastUse float64) float64 {
+	return h.algorithm.Weight(daysSinceLastUse)
+}
+
+// timeSlot returns the 6-hour slot of the day (0 = night, 1 = morning, 2 = afternoon, 3 = evening)
+func timeSlot(t time.Time) int {
+	return t.Hour() / 6
+}
+
+// isWeekend reports whether t falls on a Saturday or Sunday
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// contextMultiplier returns how well a selection made at timestamp matches the time context of now
+// Both times are compared in now's location
+func contextMultiplier(timestamp, now time.Time) float64 {
+	timestamp = timestamp.In(now.Location())
+
+	distance := timeSlot(timestamp) - timeSlot(now)
+	if distance < 0 {
+		distance = -distance
+	}
+	if distance > 2 {
+		distance = 4 - distance // Slots wrap around midnight
+	}
+
+	var weight float64
+	switch distance {
+	case 0:
+		weight = contextSameSlotWeight
+	case 1:
+		weight = contextAdjacentSlotWeight
+	default:
+		weight = contextOppositeSlotWeight
+	}
+
+	if isWeekend(timestamp) != isWeekend(now) {
+		weight *= contextOtherDayWeight
+	}
+	return weight
+}
+
+// contextWeight returns the time-of-day weight of a global selection (1.0 unless context ranking is enabled)
+func (h *History) contextWeight(timestamp, now time.Time) float64 {
+	if !h.contextRanking {
+		return 1.0
+	}
+	return contextMultiplier(timestamp, now)
 }
 
 // GetScore returns the frequency score for an item with exponential decay
@@ -245,14 +453,13 @@ func (h *History) GetScore(item string) int {
 	// Sum decay-adjusted scores for each timestamp
 	for _, timestamp := range info.Timestamps {
 		daysSinceUse := now.Sub(timestamp).Hours() / 24
-		decayMultiplier := calculateDecayMultiplier(daysSinceUse)
+		decayMultiplier := h.decay(daysSinceUse)
 		if decayMultiplier > 0 {
-			score += 1.0 * decayMultiplier
+			score += h.contextWeight(timestamp, now) * decayMultiplier
 		}
 	}
 
 	// Cap at 30 to prevent extreme dominance
-	const maxHistoryScore = 30
 	if score > maxHistoryScore {
 		score = maxHistoryScore
 	}
@@ -274,9 +481,9 @@ func (h *History) GetAllScores() map[string]int {
 		// Sum decay-adjusted scores for each timestamp
 		for _, timestamp := range info.Timestamps {
 			daysSinceUse := now.Sub(timestamp).Hours() / 24
-			decayMultiplier := calculateDecayMultiplier(daysSinceUse)
+			decayMultiplier := h.decay(daysSinceUse)
 			if decayMultiplier > 0 {
-				score += 1.0 * decayMultiplier
+				score += h.contextWeight(timestamp, now) * decayMultiplier
 			}
 		}
 
@@ -286,7 +493,6 @@ func (h *History) GetAllScores() map[string]int {
 		}
 
 		// Cap at 30 to prevent extreme dominance
-		const maxHistoryScore = 30
 		if score > maxHistoryScore {
 			score = maxHistoryScore
 		}
@@ -298,7 +504,14 @@ func (h *History) GetAllScores() map[string]int {
 }
 
 // Save saves the history to disk
+// Selections saved meanwhile by other processes (e.g. --json-record while the TUI runs)
+// are merged in under a lock file, so concurrent writers never lose each other's entries.
+// With a retention period, selections older than it are purged first
 func (h *History) Save() error {
+	if h.retentionDays > 0 {
+		h.CleanupOldEntries()
+	}
+
 	h.mu.RLock()
 	if !h.dirty {
 		h.mu.RUnlock()
@@ -315,6 +528,23 @@ func (h *History) Save() error {
 		return fmt.Errorf("failed to create history directory: %w", err)
 	}
 
+	unlock, err := lockFile(cleanPath + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock history file: %w", err)
+	}
+	defer unlock()
+
+	// Read what other processes saved since this history was loaded
+	saved, _, err := readHistoryFile(cleanPath)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.mergeLocked(saved)
+	h.cleanupLocked()
+
 	// Create temporary file for atomic write
 	tempPath := cleanPath + ".tmp"
 	// #nosec G304 -- Path constructed with filepath.Clean(configPath) + ".tmp"
@@ -330,15 +560,14 @@ func (h *History) Save() error {
 
 	encoder := gob.NewEncoder(file)
 
-	h.mu.RLock()
 	data := historyData{
 		Selections:      h.selections,
 		QuerySelections: h.querySelections,
+		QueryTexts:      h.queryTexts,
+		Sources:         h.sources,
+		ClearedAt:       h.clearedAt,
 	}
-	err = encoder.Encode(data)
-	h.mu.RUnlock()
-
-	if err != nil {
+	if err := encoder.Encode(data); err != nil {
 		if closeErr := file.Close(); closeErr != nil {
 			// Ignore close error on error path
 			_ = closeErr
@@ -367,9 +596,9 @@ func (h *History) Save() error {
 		return fmt.Errorf("failed to rename temp file: %w", err)
 	}
 
-	h.mu.Lock()
+	// The file now reflects the clears and remaps
 	h.dirty = false
-	h.mu.Unlock()
+	h.remaps = nil
 
 	return nil
 }
@@ -394,15 +623,115 @@ func (h *History) Clear() {
 
 	h.selections = make(map[string]SelectionInfo)
 	h.querySelections = make(map[string]map[string]SelectionInfo)
+	h.queryTexts = make(map[string]string)
+	h.sources = make(map[int64]string)
 	h.dirty = true
+	h.cachedGlobalScores = nil
+	h.clearedAt = time.Now()
+}
+
+// Remap moves history from oldPath to newPath (global and query-specific)
+// Entries below oldPath (oldPath + "/...") are moved as well, so a renamed group
+// carries the history of all its projects. Timestamps are merged if newPath already
+// has history. Returns the number of remapped entries
+func (h *History) Remap(oldPath, newPath string) int {
+	oldPath = strings.Trim(oldPath, "/")
+	newPath = strings.Trim(newPath, "/")
+	if oldPath == "" || newPath == "" || oldPath == newPath {
+		return 0
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	remapped := remapSelections(h.selections, oldPath, newPath)
+	for _, querySelections := range h.querySelections {
+		remapped += remapSelections(querySelections, oldPath, newPath)
+	}
+
+	if remapped > 0 {
+		h.dirty = true
+		h.cachedGlobalScores = nil
+	}
+	// Entries saved meanwhile by other processes are remapped when saving
+	h.remaps = append(h.remaps, pathRemap{oldPath: oldPath, newPath: newPath})
+	return remapped
+}
+
+// remapSelections renames oldPath (and paths below it) to newPath within one selection map
+func remapSelections(selections map[string]SelectionInfo, oldPath, newPath string) int {
+	moves := make(map[string]string)
+	for item := range selections {
+		switch {
+		case item == oldPath:
+			moves[item] = newPath
+		case strings.HasPrefix(item, oldPath+"/"):
+			moves[item] = newPath + strings.TrimPrefix(item, oldPath)
+		}
+	}
+	return moveSelections(selections, moves)
+}
+
+// Rekey moves the history of each item in keys to its new key (global and query-specific),
+// e.g. from a project path to the project ID key. Unlike Remap, items are matched exactly.
+// Timestamps are merged if the new key already has history. Returns the number of moved entries
+func (h *History) Rekey(keys map[string]string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	rekeyed := rekeySelections(h.selections, keys)
+	for _, querySelections := range h.querySelections {
+		rekeyed += rekeySelections(querySelections, keys)
+	}
+	if rekeyed == 0 {
+		return 0
+	}
+
+	h.dirty = true
+	h.cachedGlobalScores = nil
+	// Entries saved meanwhile by other processes are rekeyed when saving
+	h.remaps = append(h.remaps, pathRemap{keys: keys})
+	return rekeyed
+}
+
+// rekeySelections moves the items of selections found in keys to their new key
+func rekeySelections(selections map[string]SelectionInfo, keys map[string]string) int {
+	moves := make(map[string]string)
+	for item := range selections {
+		if key, ok := keys[item]; ok && key != item {
+			moves[item] = key
+		}
+	}
+	return moveSelections(selections, moves)
+}
+
+// moveSelections moves the timestamps of each item in moves to its target within selections
+func moveSelections(selections map[string]SelectionInfo, moves map[string]string) int {
+	for from, to := range moves {
+		info := selections[from]
+		delete(selections, from)
+		merged := append(selections[to].Timestamps, info.Timestamps...)
+		sort.Slice(merged, func(i, j int) bool { return merged[i].Before(merged[j]) })
+		selections[to] = SelectionInfo{Timestamps: merged}
+	}
+	return len(moves)
 }
 
-// CleanupOldEntries removes history entries older than maxAgeDays
+// CleanupOldEntries removes history entries older than the maximum age
+// (or the retention period, if shorter)
 // This helps keep the history file size manageable and removes stale data
 func (h *History) CleanupOldEntries() int {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	return h.cleanupLocked()
+}
 
+// cleanupLocked is CleanupOldEntries for callers holding h.mu
+func (h *History) cleanupLocked() int {
+	maxAgeDays := h.maxAgeDays
+	if h.retentionDays > 0 && h.retentionDays < maxAgeDays {
+		maxAgeDays = h.retentionDays
+	}
 	now := time.Now()
 	removed := 0
 
@@ -453,28 +782,39 @@ func (h *History) CleanupOldEntries() int {
 		// Remove empty query hashes
 		if len(querySelections) == 0 {
 			delete(h.querySelections, queryHash)
+			delete(h.queryTexts, queryHash)
 		}
 	}
 
 	if removed > 0 {
 		h.dirty = true
 	}
+	h.pruneSourcesLocked(now, maxAgeDays)
 
 	return removed
 }
 
 // normalizeQuery normalizes a query string for consistent history tracking
+// Returns the hash used as the query-specific history key
 func normalizeQuery(query string) string {
-	normalized := strings.ToLower(strings.TrimSpace(query))
-	normalized = strings.Join(strings.Fields(normalized), " ")
-
 	h := fnv.New64a()
-	_, _ = h.Write([]byte(normalized))
+	_, _ = h.Write([]byte(NormalizeQueryText(query)))
 	return strconv.FormatUint(h.Sum64(), 36)
 }
 
+// NormalizeQueryText returns the query as it is matched against history:
+// lowercased, with surrounding and repeated whitespace removed
+func NormalizeQueryText(query string) string {
+	normalized := strings.ToLower(strings.TrimSpace(query))
+	return strings.Join(strings.Fields(normalized), " ")
+}
+
 // RecordSelectionWithQuery records a selection with query context
+// Does nothing when tracking is disabled
 func (h *History) RecordSelectionWithQuery(query, item string) {
+	if !h.enabled {
+		return
+	}
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
@@ -484,10 +824,12 @@ func (h *History) RecordSelectionWithQuery(query, item string) {
 	globalInfo := h.selections[item]
 	globalInfo.Timestamps = append(globalInfo.Timestamps, now)
 	h.selections[item] = globalInfo
+	h.tagLocked(now)
 
 	// Update query-specific history
 	if query != "" {
 		queryHash := normalizeQuery(query)
+		h.queryTexts[queryHash] = NormalizeQueryText(query)
 
 		if h.querySelections[queryHash] == nil {
 			h.querySelections[queryHash] = make(map[string]SelectionInfo)
@@ -515,9 +857,9 @@ func (h *History) GetScoreForQuery(query, item string) int {
 	if info, exists := h.selections[item]; exists {
 		for _, timestamp := range info.Timestamps {
 			daysSinceUse := now.Sub(timestamp).Hours() / 24
-			decayMultiplier := calculateDecayMultiplier(daysSinceUse)
+			decayMultiplier := h.decay(daysSinceUse)
 			if decayMultiplier > 0 {
-				totalScore += 1.0 * decayMultiplier
+				totalScore += h.contextWeight(timestamp, now) * decayMultiplier
 			}
 		}
 	}
@@ -529,9 +871,9 @@ func (h *History) GetScoreForQuery(query, item string) int {
 			if info, exists := querySelections[item]; exists {
 				for _, timestamp := range info.Timestamps {
 					daysSinceUse := now.Sub(timestamp).Hours() / 24
-					decayMultiplier := calculateDecayMultiplier(daysSinceUse)
+					decayMultiplier := h.decay(daysSinceUse)
 					if decayMultiplier > 0 {
-						totalScore += 2.5 * decayMultiplier
+						totalScore += queryBoost * decayMultiplier
 					}
 				}
 			}
@@ -539,7 +881,6 @@ func (h *History) GetScoreForQuery(query, item string) int {
 	}
 
 	// Cap at 30 to prevent extreme dominance
-	const maxHistoryScore = 30
 	if totalScore > maxHistoryScore {
 		totalScore = maxHistoryScore
 	}
@@ -561,9 +902,9 @@ func (h *History) GetAllScoresForQuery(query string) map[string]int {
 			var score float64
 			for _, timestamp := range info.Timestamps {
 				daysSinceUse := now.Sub(timestamp).Hours() / 24
-				decayMultiplier := calculateDecayMultiplier(daysSinceUse)
+				decayMultiplier := h.decay(daysSinceUse)
 				if decayMultiplier > 0 {
-					score += decayMultiplier
+					score += h.contextWeight(timestamp, now) * decayMultiplier
 				}
 			}
 			if score > 0 {
@@ -585,16 +926,15 @@ func (h *History) GetAllScoresForQuery(query string) map[string]int {
 			for item, info := range querySelections {
 				for _, timestamp := range info.Timestamps {
 					daysSinceUse := now.Sub(timestamp).Hours() / 24
-					decayMultiplier := calculateDecayMultiplier(daysSinceUse)
+					decayMultiplier := h.decay(daysSinceUse)
 					if decayMultiplier > 0 {
-						scores[item] += 2.5 * decayMultiplier
+						scores[item] += queryBoost * decayMultiplier
 					}
 				}
 			}
 		}
 	}
 
-	const maxHistoryScore = 30
 	intScores := make(map[string]int, len(scores))
 	for item, score := range scores {
 		if score > maxHistoryScore {
@@ -631,9 +971,9 @@ func (h *History) GetAllEntries() []Entry {
 		score := 0.0
 		for _, timestamp := range info.Timestamps {
 			daysSinceUse := now.Sub(timestamp).Hours() / 24
-			decayMultiplier := calculateDecayMultiplier(daysSinceUse)
+			decayMultiplier := h.decay(daysSinceUse)
 			if decayMultiplier > 0 {
-				score += 1.0 * decayMultiplier
+				score += h.contextWeight(timestamp, now) * decayMultiplier
 			}
 		}
 
@@ -642,8 +982,7 @@ func (h *History) GetAllEntries() []Entry {
 			continue
 		}
 
-		// Cap at 30
-		const maxHistoryScore = 30
+		// Cap at 30 to prevent extreme dominance
 		if score > maxHistoryScore {
 			score = maxHistoryScore
 		}
@@ -670,3 +1009,207 @@ func (h *History) GetAllEntries() []Entry {
 
 	return entries
 }
+
+// Contribution is one selection's share of a project's score
+type Contribution struct {
+	Time       time.Time // When the project was selected
+	AgeDays    float64   // Age of the selection in days
+	Multiplier float64   // Age weight from the history algorithm (0 if older than the maximum age)
+	Weight     float64   // 1.0 for any selection, queryBoost for a selection made with the query
+	Context    float64   // Time-of-day weight (1.0 unless context ranking is enabled)
+	Query      bool      // Whether the selection was made with the explained query
+}
+
+// Explanation describes how a project's history score was computed
+type Explanation struct {
+	ProjectPath   string
+	Query         string         // Query context (empty for the global score)
+	Algorithm     string         // Scoring algorithm in use (AlgorithmDecay or AlgorithmFrecency)
+	HalfLifeDays  float64        // Decay half-life in use
+	MaxAgeDays    float64        // Maximum selection age in use
+	Context       bool           // Whether context ranking (time of day) is enabled
+	Contributions []Contribution // Global selections first, then query selections; each group newest first
+	GlobalScore   float64        // Sum of global contributions
+	QueryScore    float64        // Sum of query-specific contributions
+	QueryContexts int            // Number of distinct queries the project was selected with
+	RawScore      float64        // GlobalScore + QueryScore before capping
+	Score         int            // Final score as used for ranking
+	Capped        bool           // Whether RawScore exceeded the cap of 30
+}
+
+// Explain returns the breakdown of a project's score for the given query context
+// (the same computation as GetScoreForQuery)
+func (h *History) Explain(query, item string) Explanation {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	exp := Explanation{
+		ProjectPath:  item,
+		Query:        strings.TrimSpace(query),
+		Algorithm:    h.algorithm.Name(),
+		HalfLifeDays: h.halfLifeDays,
+		MaxAgeDays:   h.maxAgeDays,
+		Context:      h.contextRanking,
+	}
+	now := time.Now()
+
+	contributions := func(timestamps []time.Time, weight float64, isQuery bool) float64 {
+		sorted := append([]time.Time(nil), timestamps...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].After(sorted[j]) })
+
+		total := 0.0
+		for _, timestamp := range sorted {
+			daysSinceUse := now.Sub(timestamp).Hours() / 24
+			multiplier := h.decay(daysSinceUse)
+			context := 1.0
+			if !isQuery {
+				context = h.contextWeight(timestamp, now)
+			}
+			exp.Contributions = append(exp.Contributions, Contribution{
+				Time:       timestamp,
+				AgeDays:    daysSinceUse,
+				Multiplier: multiplier,
+				Weight:     weight,
+				Context:    context,
+				Query:      isQuery,
+			})
+			total += weight * multiplier * context
+		}
+		return total
+	}
+
+	if info, exists := h.selections[item]; exists {
+		exp.GlobalScore = contributions(info.Timestamps, 1.0, false)
+	}
+
+	if exp.Query != "" {
+		if info, exists := h.querySelections[normalizeQuery(exp.Query)][item]; exists {
+			exp.QueryScore = contributions(info.Timestamps, queryBoost, true)
+		}
+	}
+
+	for _, querySelections := range h.querySelections {
+		if _, exists := querySelections[item]; exists {
+			exp.QueryContexts++
+		}
+	}
+
+	exp.RawScore = exp.GlobalScore + exp.QueryScore
+	capped := exp.RawScore
+	if capped > maxHistoryScore {
+		capped = maxHistoryScore
+		exp.Capped = true
+	}
+	exp.Score = int(capped)
+
+	return exp
+}
+
+// QuerySelectionCounts returns how often each item was selected for a query (normalized
+// like ranking does), counting only selections after since (all for the zero time)
+func (h *History) QuerySelectionCounts(query string, since time.Time) map[string]int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for item, info := range h.querySelections[normalizeQuery(query)] {
+		for _, timestamp := range info.Timestamps {
+			if timestamp.After(since) {
+				counts[item]++
+			}
+		}
+	}
+	return counts
+}
+
+// GetQueryEntries returns the projects selected for a query (normalized like ranking does),
+// sorted by query boost (highest first). Score is the boost added on top of the global score
+func (h *History) GetQueryEntries(query string) []Entry {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	querySelections := h.querySelections[normalizeQuery(query)]
+	entries := make([]Entry, 0, len(querySelections))
+	now := time.Now()
+
+	for item, info := range querySelections {
+		if len(info.Timestamps) == 0 {
+			continue
+		}
+
+		score := 0.0
+		lastUsed := info.Timestamps[0]
+		for _, timestamp := range info.Timestamps {
+			score += queryBoost * h.decay(now.Sub(timestamp).Hours()/24)
+			if timestamp.After(lastUsed) {
+				lastUsed = timestamp
+			}
+		}
+		if score > maxHistoryScore {
+			score = maxHistoryScore
+		}
+
+		entries = append(entries, Entry{
+			ProjectPath: item,
+			Count:       len(info.Timestamps),
+			LastUsed:    lastUsed,
+			Score:       int(score),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].LastUsed.After(entries[j].LastUsed)
+	})
+
+	return entries
+}
+
+// QueryStat summarizes the selections made with one query
+type QueryStat struct {
+	Query      string    // Normalized query (empty if recorded by a version that stored only hashes)
+	Selections int       // Number of selections made with the query
+	Projects   int       // Number of distinct projects selected with the query
+	LastUsed   time.Time // Most recent selection
+}
+
+// GetTopQueries returns the most frequently used queries (most selections first)
+// A limit of 0 or less returns all queries
+func (h *History) GetTopQueries(limit int) []QueryStat {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	stats := make([]QueryStat, 0, len(h.querySelections))
+	for queryHash, querySelections := range h.querySelections {
+		stat := QueryStat{Query: h.queryTexts[queryHash]}
+		for _, info := range querySelections {
+			if len(info.Timestamps) == 0 {
+				continue
+			}
+			stat.Projects++
+			stat.Selections += len(info.Timestamps)
+			for _, timestamp := range info.Timestamps {
+				if timestamp.After(stat.LastUsed) {
+					stat.LastUsed = timestamp
+				}
+			}
+		}
+		if stat.Selections > 0 {
+			stats = append(stats, stat)
+		}
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Selections != stats[j].Selections {
+			return stats[i].Selections > stats[j].Selections
+		}
+		return stats[i].LastUsed.After(stats[j].LastUsed)
+	})
+
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats
+}
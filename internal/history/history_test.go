@@ -2,8 +2,11 @@ package history
 
 import (
 	"encoding/gob"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"testing"
 	"time"
@@ -727,6 +730,31 @@ func TestHistory_LoadAsync_CorruptedFile(t *testing.T) {
 	}
 }
 
+func TestVerify(t *testing.T) {
+	tempDir := t.TempDir()
+	historyPath := filepath.Join(tempDir, "history.gob")
+
+	if err := Verify(historyPath); err != nil {
+		t.Errorf("Missing file should verify, got %v", err)
+	}
+
+	h := New(historyPath)
+	h.RecordSelection("group/project")
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := Verify(historyPath); err != nil {
+		t.Errorf("Saved file should verify, got %v", err)
+	}
+
+	if err := os.WriteFile(historyPath, []byte("not a valid gob file"), 0600); err != nil {
+		t.Fatalf("Failed to create corrupted file: %v", err)
+	}
+	if err := Verify(historyPath); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Corrupted file: expected ErrCorrupt, got %v", err)
+	}
+}
+
 func TestHistory_LoadAsync_PermissionDenied(t *testing.T) {
 	// Skip on Windows where chmod doesn't work the same way
 	if runtime.GOOS == "windows" {
@@ -1024,3 +1052,714 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestHistory_Remap(t *testing.T) {
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+
+	h.RecordSelectionWithQuery("api", "old-group/api")
+	h.RecordSelectionWithQuery("api", "old-group/api")
+	h.RecordSelection("old-group/sub/worker")
+	h.RecordSelection("old-group-other/web") // Shares the prefix text but not the path segment
+	h.RecordSelection("new-group/api")       // Already has history - timestamps are merged
+
+	if n := h.Remap("old-group", "new-group"); n != 3 {
+		t.Errorf("Expected 3 remapped entries (2 global + 1 query), got %d", n)
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if _, exists := h.selections["old-group/api"]; exists {
+		t.Error("Expected old path to be removed from global history")
+	}
+	if got := len(h.selections["new-group/api"].Timestamps); got != 3 {
+		t.Errorf("Expected 3 merged timestamps for new-group/api, got %d", got)
+	}
+	if _, exists := h.selections["new-group/sub/worker"]; !exists {
+		t.Error("Expected nested project to move with the group")
+	}
+	if _, exists := h.selections["old-group-other/web"]; !exists {
+		t.Error("Expected unrelated path with a common prefix to be left alone")
+	}
+	if got := len(h.querySelections[normalizeQuery("api")]["new-group/api"].Timestamps); got != 2 {
+		t.Errorf("Expected query-specific history to move, got %d timestamps", got)
+	}
+	if !h.dirty {
+		t.Error("Expected history to be dirty after remap")
+	}
+}
+
+func TestHistory_Rekey(t *testing.T) {
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+
+	h.RecordSelectionWithQuery("api", "group/api")
+	h.RecordSelection("group/api/docs") // Below the path, but another project
+	h.RecordSelection("id:1")           // Already recorded by ID - timestamps are merged
+
+	if n := h.Rekey(map[string]string{"group/api": "id:1", "group/web": "id:2"}); n != 2 {
+		t.Errorf("Expected 2 rekeyed entries (1 global + 1 query), got %d", n)
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if _, exists := h.selections["group/api"]; exists {
+		t.Error("Expected the path to be removed from global history")
+	}
+	if got := len(h.selections["id:1"].Timestamps); got != 2 {
+		t.Errorf("Expected 2 merged timestamps for id:1, got %d", got)
+	}
+	if _, exists := h.selections["group/api/docs"]; !exists {
+		t.Error("Expected Rekey to match paths exactly")
+	}
+	if got := len(h.querySelections[normalizeQuery("api")]["id:1"].Timestamps); got != 1 {
+		t.Errorf("Expected query-specific history to move, got %d timestamps", got)
+	}
+}
+
+func TestHistory_Remap_NoOp(t *testing.T) {
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+	h.RecordSelection("group/api")
+	h.dirty = false
+
+	for _, tc := range [][2]string{{"group/api", "group/api"}, {"", "x"}, {"missing", "other"}} {
+		if n := h.Remap(tc[0], tc[1]); n != 0 {
+			t.Errorf("Remap(%q, %q) = %d, want 0", tc[0], tc[1], n)
+		}
+	}
+	if h.dirty {
+		t.Error("Expected history to stay clean when nothing was remapped")
+	}
+}
+
+func TestSetDefaultDecay(t *testing.T) {
+	t.Cleanup(func() { SetDefaultDecay(0, 0) })
+
+	SetDefaultDecay(7, 14)
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+	if got := h.decay(7); got < 0.49 || got > 0.51 {
+		t.Errorf("Expected ~0.5 at a 7-day half-life, got %f", got)
+	}
+	if got := h.decay(15); got != 0 {
+		t.Errorf("Expected 0 beyond a 14-day max age, got %f", got)
+	}
+
+	h.mu.Lock()
+	h.selections["group/api"] = makeSelectionInfo(3, time.Now().Add(-20*24*time.Hour))
+	h.mu.Unlock()
+	if removed := h.CleanupOldEntries(); removed != 3 {
+		t.Errorf("Expected 3 timestamps removed with a 14-day max age, got %d", removed)
+	}
+
+	// Non-positive values restore the built-in defaults
+	SetDefaultDecay(0, -1)
+	h = New(filepath.Join(t.TempDir(), "history.gob"))
+	if h.halfLifeDays != DefaultHalfLifeDays || h.maxAgeDays != DefaultMaxAgeDays {
+		t.Errorf("Expected defaults, got half-life %g, max age %g", h.halfLifeDays, h.maxAgeDays)
+	}
+}
+
+func TestContextMultiplier(t *testing.T) {
+	// 2024-01-01 is a Monday, 2024-01-06 a Saturday
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		want      float64
+	}{
+		{"same slot", time.Date(2023, 12, 28, 10, 30, 0, 0, time.UTC), contextSameSlotWeight},
+		{"adjacent slot", time.Date(2023, 12, 28, 14, 0, 0, 0, time.UTC), contextAdjacentSlotWeight},
+		{"adjacent across midnight", time.Date(2023, 12, 28, 2, 0, 0, 0, time.UTC), contextAdjacentSlotWeight},
+		{"opposite slot", time.Date(2023, 12, 28, 20, 0, 0, 0, time.UTC), contextOppositeSlotWeight},
+		{"same slot on weekend", time.Date(2024, 1, 6, 9, 0, 0, 0, time.UTC), contextSameSlotWeight * contextOtherDayWeight},
+		{"other location", time.Date(2023, 12, 28, 16, 0, 0, 0, time.FixedZone("UTC+8", 8*3600)), contextSameSlotWeight},
+	}
+	for _, tt := range tests {
+		if got := contextMultiplier(tt.timestamp, now); got != tt.want {
+			t.Errorf("%s: contextMultiplier = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHistory_ContextRanking(t *testing.T) {
+	t.Cleanup(func() { SetDefaultContextRanking(false) })
+
+	weekAgo := time.Now().Add(-7 * 24 * time.Hour)
+	record := func(h *History) {
+		h.mu.Lock()
+		h.selections["infra/dashboards"] = makeSelectionInfo(3, weekAgo)              // Same time of day
+		h.selections["product/web"] = makeSelectionInfo(4, weekAgo.Add(12*time.Hour)) // Opposite time of day
+		h.mu.Unlock()
+	}
+
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+	record(h)
+	if scores := h.GetAllScores(); scores["product/web"] <= scores["infra/dashboards"] {
+		t.Errorf("Without context ranking, more selections should win: %v", scores)
+	}
+
+	SetDefaultContextRanking(true)
+	h = New(filepath.Join(t.TempDir(), "history.gob"))
+	record(h)
+	scores := h.GetAllScores()
+	if scores["infra/dashboards"] <= scores["product/web"] {
+		t.Errorf("With context ranking, selections at this time of day should win: %v", scores)
+	}
+	if got := h.GetAllScoresForQuery(""); got["infra/dashboards"] != scores["infra/dashboards"] {
+		t.Errorf("GetAllScoresForQuery should match GetAllScores, got %v vs %v", got, scores)
+	}
+
+	exp := h.Explain("", "infra/dashboards")
+	if !exp.Context || exp.Contributions[0].Context != contextSameSlotWeight {
+		t.Errorf("Expected context weight %g in explanation, got %+v", contextSameSlotWeight, exp.Contributions[0])
+	}
+}
+
+func TestHistory_Explain(t *testing.T) {
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+	h.mu.Lock()
+	h.selections["group/api"] = SelectionInfo{Timestamps: []time.Time{
+		time.Now().Add(-30 * 24 * time.Hour),
+		time.Now(),
+		time.Now().Add(-150 * 24 * time.Hour), // Beyond max age
+	}}
+	h.querySelections[normalizeQuery("api")] = map[string]SelectionInfo{
+		"group/api": {Timestamps: []time.Time{time.Now()}},
+	}
+	h.querySelections[normalizeQuery("gateway")] = map[string]SelectionInfo{
+		"group/api": {Timestamps: []time.Time{time.Now()}},
+	}
+	h.mu.Unlock()
+
+	exp := h.Explain(" API ", "group/api")
+
+	if exp.Query != "API" {
+		t.Errorf("Expected trimmed query, got %q", exp.Query)
+	}
+	if len(exp.Contributions) != 4 {
+		t.Fatalf("Expected 4 contributions (3 global + 1 query), got %d", len(exp.Contributions))
+	}
+	if !exp.Contributions[0].Time.After(exp.Contributions[1].Time) {
+		t.Error("Expected global contributions newest first")
+	}
+	if exp.Contributions[2].Multiplier != 0 {
+		t.Errorf("Expected expired selection to contribute 0, got %f", exp.Contributions[2].Multiplier)
+	}
+	if c := exp.Contributions[3]; !c.Query || c.Weight != queryBoost {
+		t.Errorf("Expected query contribution with weight %g, got %+v", queryBoost, c)
+	}
+	if exp.GlobalScore < 1.49 || exp.GlobalScore > 1.51 {
+		t.Errorf("Expected global score ~1.5, got %f", exp.GlobalScore)
+	}
+	if exp.QueryScore < 2.49 || exp.QueryScore > 2.51 {
+		t.Errorf("Expected query score ~2.5, got %f", exp.QueryScore)
+	}
+	if exp.QueryContexts != 2 {
+		t.Errorf("Expected 2 query contexts, got %d", exp.QueryContexts)
+	}
+	if want := h.GetScoreForQuery("api", "group/api"); exp.Score != want {
+		t.Errorf("Expected Explain score to match GetScoreForQuery (%d), got %d", want, exp.Score)
+	}
+	if exp.Capped {
+		t.Error("Expected score below the cap")
+	}
+}
+
+func TestHistory_Explain_Capped(t *testing.T) {
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+	h.mu.Lock()
+	h.selections["group/api"] = makeSelectionInfo(40, time.Now())
+	h.mu.Unlock()
+
+	exp := h.Explain("", "group/api")
+	if !exp.Capped || exp.Score != maxHistoryScore {
+		t.Errorf("Expected capped score %d, got %d (capped=%v)", maxHistoryScore, exp.Score, exp.Capped)
+	}
+	if exp.RawScore < 39 {
+		t.Errorf("Expected raw score ~40, got %f", exp.RawScore)
+	}
+}
+
+func TestHistory_GetQueryEntries(t *testing.T) {
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+	h.RecordSelectionWithQuery("Backend", "group/api")
+	h.RecordSelectionWithQuery("backend", "group/api")
+	h.RecordSelectionWithQuery("  backend ", "group/worker")
+	h.RecordSelectionWithQuery("frontend", "group/web")
+
+	entries := h.GetQueryEntries("BACKEND")
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 projects for normalized query, got %d", len(entries))
+	}
+	if entries[0].ProjectPath != "group/api" || entries[0].Count != 2 {
+		t.Errorf("Expected group/api with 2 selections first, got %+v", entries[0])
+	}
+	// 2 × 2.5 with a tiny amount of decay, truncated
+	if entries[0].Score != 4 {
+		t.Errorf("Expected boost 4, got %d", entries[0].Score)
+	}
+
+	if entries := h.GetQueryEntries("unknown"); len(entries) != 0 {
+		t.Errorf("Expected no entries for unused query, got %d", len(entries))
+	}
+}
+
+func TestHistory_QuerySelectionCounts(t *testing.T) {
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+	h.RecordSelectionWithQuery("api", "group/api")
+	h.RecordSelectionWithQuery("API", "group/api")
+	h.RecordSelectionWithQuery("api", "group/gateway")
+
+	counts := h.QuerySelectionCounts(" Api", time.Time{})
+	if counts["group/api"] != 2 || counts["group/gateway"] != 1 {
+		t.Errorf("Unexpected counts %v", counts)
+	}
+	if counts := h.QuerySelectionCounts("api", time.Now().Add(time.Hour)); len(counts) != 0 {
+		t.Errorf("Expected no selections after since, got %v", counts)
+	}
+}
+
+func TestHistory_GetTopQueries(t *testing.T) {
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+	h.RecordSelectionWithQuery("api", "group/api")
+	h.RecordSelectionWithQuery("api", "group/gateway")
+	h.RecordSelectionWithQuery("API ", "group/api")
+	h.RecordSelectionWithQuery("web", "group/web")
+	h.RecordSelection("group/other") // No query: not listed
+
+	// Query recorded by an older version (hash only)
+	h.mu.Lock()
+	h.querySelections["legacyhash"] = map[string]SelectionInfo{"group/old": makeSelectionInfo(1, time.Now().Add(-time.Hour))}
+	h.mu.Unlock()
+
+	stats := h.GetTopQueries(0)
+	if len(stats) != 3 {
+		t.Fatalf("Expected 3 queries, got %d", len(stats))
+	}
+	if stats[0].Query != "api" || stats[0].Selections != 3 || stats[0].Projects != 2 {
+		t.Errorf("Expected api with 3 selections over 2 projects first, got %+v", stats[0])
+	}
+	if stats[1].Query != "web" {
+		t.Errorf("Expected most recent single-selection query next, got %+v", stats[1])
+	}
+	if stats[2].Query != "" {
+		t.Errorf("Expected hash-only query to have empty text, got %q", stats[2].Query)
+	}
+
+	if stats := h.GetTopQueries(1); len(stats) != 1 {
+		t.Errorf("Expected limit to apply, got %d", len(stats))
+	}
+}
+
+func TestHistory_QueryTextsPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.gob")
+	h := New(path)
+	h.RecordSelectionWithQuery("Data  Pipeline", "group/etl")
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := New(path)
+	if err := <-loaded.LoadAsync(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	stats := loaded.GetTopQueries(0)
+	if len(stats) != 1 || stats[0].Query != "data pipeline" {
+		t.Errorf("Expected normalized query text to persist, got %+v", stats)
+	}
+
+	loaded.Clear()
+	if stats := loaded.GetTopQueries(0); len(stats) != 0 {
+		t.Errorf("Expected no queries after Clear, got %+v", stats)
+	}
+}
+
+func TestFrecencyWeight(t *testing.T) {
+	f := Frecency{MaxAgeDays: DefaultMaxAgeDays}
+	tests := []struct {
+		days float64
+		want float64
+	}{
+		{0, 1.0},
+		{4, 1.0},
+		{5, 0.7},
+		{14, 0.7},
+		{20, 0.5},
+		{60, 0.3},
+		{95, 0.1},
+		{101, 0},
+	}
+	for _, tt := range tests {
+		if got := f.Weight(tt.days); got != tt.want {
+			t.Errorf("Weight(%g) = %g, want %g", tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestNewAlgorithm(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", AlgorithmDecay, false},
+		{"decay", AlgorithmDecay, false},
+		{" Frecency ", AlgorithmFrecency, false},
+		{"lru", "", true},
+	}
+	for _, tt := range tests {
+		algorithm, err := NewAlgorithm(tt.name, 0, 0)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("NewAlgorithm(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err == nil && algorithm.Name() != tt.want {
+			t.Errorf("NewAlgorithm(%q) = %s, want %s", tt.name, algorithm.Name(), tt.want)
+		}
+	}
+}
+
+func TestSetDefaultAlgorithm(t *testing.T) {
+	t.Cleanup(func() { _ = SetDefaultAlgorithm("") })
+
+	if err := SetDefaultAlgorithm("frecency"); err != nil {
+		t.Fatalf("SetDefaultAlgorithm failed: %v", err)
+	}
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+	if h.Algorithm().Name() != AlgorithmFrecency {
+		t.Errorf("Expected frecency, got %s", h.Algorithm().Name())
+	}
+	if exp := h.Explain("", "group/api"); exp.Algorithm != AlgorithmFrecency {
+		t.Errorf("Expected explanation to name frecency, got %q", exp.Algorithm)
+	}
+
+	// Unknown names are rejected and keep the previous default
+	if err := SetDefaultAlgorithm("lru"); err == nil {
+		t.Error("Expected error for unknown algorithm")
+	}
+	if h := New(filepath.Join(t.TempDir(), "history.gob")); h.Algorithm().Name() != AlgorithmFrecency {
+		t.Errorf("Expected frecency to stay the default, got %s", h.Algorithm().Name())
+	}
+}
+
+// TestAlgorithmsCompared ranks the same history with both algorithms:
+// a few very recent selections vs. more selections from two weeks ago
+func TestAlgorithmsCompared(t *testing.T) {
+	now := time.Now()
+	h := New(filepath.Join(t.TempDir(), "history.gob"))
+	h.mu.Lock()
+	h.selections["group/recent"] = makeSelectionInfo(2, now.Add(-84*time.Hour))      // 3.5 days ago
+	h.selections["group/frequent"] = makeSelectionInfo(3, now.Add(-15*24*time.Hour)) // 15 days ago
+	h.mu.Unlock()
+
+	tests := []struct {
+		algorithm Algorithm
+		winner    string
+	}{
+		// Decay: 2×0.92 = 1.8 vs 3×0.71 = 2.1
+		{Decay{HalfLifeDays: DefaultHalfLifeDays, MaxAgeDays: DefaultMaxAgeDays}, "group/frequent"},
+		// Frecency: 2×1.0 = 2.0 vs 3×0.5 = 1.5
+		{Frecency{MaxAgeDays: DefaultMaxAgeDays}, "group/recent"},
+	}
+	for _, tt := range tests {
+		h.SetAlgorithm(tt.algorithm)
+		scores := h.GetAllScores()
+		loser := "group/recent"
+		if tt.winner == loser {
+			loser = "group/frequent"
+		}
+		if scores[tt.winner] <= scores[loser] {
+			t.Errorf("%s: expected %s to outrank %s, got scores %v", tt.algorithm.Name(), tt.winner, loser, scores)
+		}
+	}
+}
+
+func TestHistory_Disabled(t *testing.T) {
+	t.Cleanup(func() { SetDefaultEnabled(true) })
+
+	historyPath := filepath.Join(t.TempDir(), "history.gob")
+	SetDefaultEnabled(false)
+	h := New(historyPath)
+	if h.Enabled() {
+		t.Fatal("Expected history to be disabled")
+	}
+
+	h.RecordSelection("group/api")
+	h.RecordSelectionWithQuery("api", "group/api")
+	if total, unique := h.Stats(); total != 0 || unique != 0 {
+		t.Errorf("Expected no selections recorded, got %d (%d projects)", total, unique)
+	}
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := os.Stat(historyPath); !os.IsNotExist(err) {
+		t.Errorf("Expected no history file to be written, got %v", err)
+	}
+
+	SetDefaultEnabled(true)
+	if h := New(historyPath); !h.Enabled() {
+		t.Error("Expected history to be enabled again")
+	}
+}
+
+func TestHistory_RetentionOnSave(t *testing.T) {
+	t.Cleanup(func() { SetDefaultRetention(0) })
+
+	historyPath := filepath.Join(t.TempDir(), "history.gob")
+	SetDefaultRetention(7)
+	h := New(historyPath)
+
+	h.mu.Lock()
+	h.selections["group/old"] = makeSelectionInfo(2, time.Now().Add(-10*24*time.Hour))
+	h.querySelections[normalizeQuery("old")] = map[string]SelectionInfo{
+		"group/old": makeSelectionInfo(1, time.Now().Add(-10*24*time.Hour)),
+	}
+	h.mu.Unlock()
+	h.RecordSelectionWithQuery("api", "group/api")
+
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := New(historyPath)
+	if err := <-loaded.LoadAsync(); err != nil {
+		t.Fatalf("LoadAsync failed: %v", err)
+	}
+	if score := loaded.GetScore("group/old"); score != 0 {
+		t.Errorf("Expected selections older than 7 days to be purged, got score %d", score)
+	}
+	if score := loaded.GetScoreForQuery("old", "group/old"); score != 0 {
+		t.Errorf("Expected query selections older than 7 days to be purged, got score %d", score)
+	}
+	if total, unique := loaded.Stats(); total != 1 || unique != 1 {
+		t.Errorf("Expected only the recent selection to be kept, got %d (%d projects)", total, unique)
+	}
+
+	// Negative values keep selections until the maximum age
+	SetDefaultRetention(-1)
+	if h := New(historyPath); h.retentionDays != 0 {
+		t.Errorf("Expected no retention, got %g days", h.retentionDays)
+	}
+}
+
+// loadHistory loads the history file at path into a new History
+func loadHistory(t *testing.T, path string) *History {
+	t.Helper()
+	h := New(path)
+	if err := <-h.LoadAsync(); err != nil {
+		t.Fatalf("LoadAsync failed: %v", err)
+	}
+	return h
+}
+
+func TestHistory_Save_MergesConcurrentWriters(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.gob")
+
+	seed := New(historyPath)
+	seed.RecordSelection("group/seed")
+	if err := seed.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// The TUI and --json-record load the same file, then save one after the other
+	tui := loadHistory(t, historyPath)
+	record := loadHistory(t, historyPath)
+	tui.RecordSelectionWithQuery("api", "group/api")
+	record.RecordSelectionWithQuery("web", "group/web")
+	if err := record.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := tui.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := loadHistory(t, historyPath)
+	if total, unique := loaded.Stats(); total != 3 || unique != 3 {
+		t.Errorf("Expected the selections of both writers, got %d selections of %d projects", total, unique)
+	}
+	if _, ok := loaded.querySelections[normalizeQuery("web")]["group/web"]; !ok {
+		t.Error("Expected query-specific history of the other writer to be kept")
+	}
+	if got := loaded.GetQueryEntries("web"); len(got) != 1 {
+		t.Errorf("Expected the query text of the other writer to be kept, got %v", got)
+	}
+
+	// The writer that saved last also sees the other writer's selections
+	if total, _ := tui.Stats(); total != 3 {
+		t.Errorf("Expected the saving history to include merged selections, got %d", total)
+	}
+}
+
+func TestHistory_Save_Parallel(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.gob")
+
+	const writers = 8
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		go func(i int) {
+			h := New(historyPath)
+			if err := <-h.LoadAsync(); err != nil {
+				errs <- err
+				return
+			}
+			h.RecordSelection(fmt.Sprintf("group/project-%d", i))
+			errs <- h.Save()
+		}(i)
+	}
+	for i := 0; i < writers; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	if _, unique := loadHistory(t, historyPath).Stats(); unique != writers {
+		t.Errorf("Expected %d projects from parallel writers, got %d", writers, unique)
+	}
+	if _, err := os.Stat(historyPath + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("Expected the lock file to be removed, got %v", err)
+	}
+}
+
+func TestHistory_Save_ClearAndRemapApplyToFile(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.gob")
+
+	// Another process saves a selection this history never loaded
+	h := loadHistory(t, historyPath)
+	other := loadHistory(t, historyPath)
+	other.RecordSelection("old-group/api")
+	if err := other.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	h.Remap("old-group", "new-group")
+	h.RecordSelection("group/web")
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded := loadHistory(t, historyPath)
+	_, hasOld := loaded.selections["old-group/api"]
+	_, hasNew := loaded.selections["new-group/api"]
+	if hasOld || !hasNew {
+		t.Error("Expected the remap to apply to entries saved by another process")
+	}
+
+	// Clearing drops what other processes saved before, not what they record afterwards
+	other = loadHistory(t, historyPath)
+	h.Clear()
+	time.Sleep(time.Millisecond)
+	other.RecordSelection("group/after-clear")
+	if err := other.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if total, unique := loadHistory(t, historyPath).Stats(); total != 1 || unique != 1 {
+		t.Errorf("Expected only the selection made after clearing, got %d selections of %d projects", total, unique)
+	}
+
+	// A process that loaded the history before the clear and saves after it doesn't restore it
+	stale := New(historyPath)
+	stale.selections["group/before-clear"] = SelectionInfo{Timestamps: []time.Time{time.Now().Add(-time.Hour)}}
+	stale.querySelections[normalizeQuery("api")] = map[string]SelectionInfo{"group/before-clear": {Timestamps: []time.Time{time.Now().Add(-time.Hour)}}}
+	stale.RecordSelection("group/stale-writer")
+	if err := stale.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded = loadHistory(t, historyPath)
+	if _, ok := loaded.selections["group/before-clear"]; ok {
+		t.Error("Expected entries older than the saved clear to be dropped")
+	}
+	if len(loaded.querySelections) != 0 {
+		t.Errorf("Expected query entries older than the saved clear to be dropped, got %v", loaded.querySelections)
+	}
+	if _, ok := loaded.selections["group/stale-writer"]; !ok {
+		t.Error("Expected the selection made after the clear to be kept")
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "history.gob.lock")
+
+	unlock, err := lockFile(lockPath)
+	if err != nil {
+		t.Fatalf("lockFile failed: %v", err)
+	}
+	unlock()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("Expected unlock to remove the lock file, got %v", err)
+	}
+
+	// A lock left by a crashed writer is taken over
+	if err := os.WriteFile(lockPath, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-2 * lockStaleAfter)
+	if err := os.Chtimes(lockPath, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err = lockFile(lockPath)
+	if err != nil {
+		t.Fatalf("Expected a stale lock to be taken over, got %v", err)
+	}
+	unlock()
+}
+
+func TestHistory_UsageStats(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.gob")
+
+	// A selection recorded by an older version has no source
+	seed := New(historyPath)
+	seed.RecordSelection("group/old")
+	if err := seed.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// The TUI and --json-record save the same file concurrently
+	tui := loadHistory(t, historyPath)
+	tui.SetSource(SourceTUI)
+	record := loadHistory(t, historyPath)
+	record.SetSource(SourceJSONRecord)
+	tui.RecordSelectionWithQuery("api", "group/api")
+	tui.RecordSelection("group/web")
+	record.RecordSelection("group/api")
+	if err := record.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := tui.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := loadHistory(t, historyPath)
+	sources, weeks := loaded.UsageStats()
+	want := []SourceStat{{SourceTUI, 2}, {SourceJSONRecord, 1}, {SourceUnknown, 1}}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("Expected %+v, got %+v", want, sources)
+	}
+	if len(weeks) != 1 || weeks[0].Selections != 4 || weeks[0].Sources[SourceTUI] != 2 {
+		t.Fatalf("Expected all selections in the current week, got %+v", weeks)
+	}
+	if monday := weeks[0].Week; monday.Weekday() != time.Monday || monday.Hour() != 0 || time.Since(monday) > 7*24*time.Hour {
+		t.Errorf("Expected the week to start on the last Monday, got %v", monday)
+	}
+
+	loaded.Clear()
+	if sources, weeks := loaded.UsageStats(); len(sources) != 0 || len(weeks) != 0 {
+		t.Errorf("Expected no stats after Clear, got %+v %+v", sources, weeks)
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		day  time.Time
+		want time.Time
+	}{
+		{time.Date(2026, 10, 18, 23, 30, 0, 0, time.Local), time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)}, // Sunday
+		{time.Date(2026, 10, 12, 8, 0, 0, 0, time.Local), time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)},   // Monday
+		{time.Date(2026, 11, 4, 12, 0, 0, 0, time.Local), time.Date(2026, 11, 2, 0, 0, 0, 0, time.Local)},    // Wednesday
+	}
+	for _, tt := range tests {
+		if got := weekStart(tt.day); !got.Equal(tt.want) {
+			t.Errorf("weekStart(%v) = %v, want %v", tt.day, got, tt.want)
+		}
+	}
+}
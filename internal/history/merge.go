@@ -0,0 +1,185 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Lock file settings: frontends (TUI, --json-record, Raycast) save the same history file,
+// so Save holds a lock file while it reads, merges and rewrites it
+const (
+	lockTimeout       = 2 * time.Second       // Give up waiting for another writer after this
+	lockStaleAfter    = 10 * time.Second      // A lock this old was left by a crashed writer
+	lockRetryInterval = 10 * time.Millisecond // Delay between attempts to take the lock
+)
+
+// pathRemap is a Remap (or, with keys, a Rekey) to replay on the file's history when saving
+type pathRemap struct {
+	oldPath, newPath string
+	keys             map[string]string
+}
+
+// lockFile takes the lock file at path, waiting for another writer to release it
+// Returns the function releasing the lock
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		// #nosec G304 -- Path is the history file path with a fixed ".lock" suffix
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			if closeErr := file.Close(); closeErr != nil {
+				// The lock is the file's existence, not its content
+				_ = closeErr
+			}
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		// A writer that crashed while saving leaves its lock behind
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > lockStaleAfter {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("history file is locked by another process (%s)", path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// StaleLock returns the lock file of the history file at path and whether a writer that
+// crashed while saving left it behind (the next save removes such a lock)
+func StaleLock(path string) (string, bool) {
+	lockPath := filepath.Clean(path) + ".lock"
+	info, err := os.Stat(lockPath)
+	return lockPath, err == nil && time.Since(info.ModTime()) > lockStaleAfter
+}
+
+// mergeLocked merges the history saved by other processes (read from the file) into h,
+// so selections recorded concurrently by another frontend are kept
+// Clears and remaps made since the last save are applied to the file's history first; a clear
+// saved by another process drops the entries this history still holds from before it.
+// The caller must hold h.mu
+func (h *History) mergeLocked(saved historyData) {
+	if h.selections == nil {
+		h.selections = make(map[string]SelectionInfo)
+	}
+	if h.querySelections == nil {
+		h.querySelections = make(map[string]map[string]SelectionInfo)
+	}
+	if h.queryTexts == nil {
+		h.queryTexts = make(map[string]string)
+	}
+	if h.sources == nil {
+		h.sources = make(map[int64]string)
+	}
+
+	if saved.ClearedAt.After(h.clearedAt) {
+		h.clearedAt = saved.ClearedAt
+		dropBefore(h.selections, h.clearedAt)
+		for queryHash, querySelections := range h.querySelections {
+			dropBefore(querySelections, h.clearedAt)
+			if len(querySelections) == 0 {
+				delete(h.querySelections, queryHash)
+				delete(h.queryTexts, queryHash)
+			}
+		}
+		for nanos := range h.sources {
+			if !time.Unix(0, nanos).After(h.clearedAt) {
+				delete(h.sources, nanos)
+			}
+		}
+	}
+	if !h.clearedAt.IsZero() {
+		dropBefore(saved.Selections, h.clearedAt)
+		for _, querySelections := range saved.QuerySelections {
+			dropBefore(querySelections, h.clearedAt)
+		}
+	}
+	for _, remap := range h.remaps {
+		if remap.keys != nil {
+			rekeySelections(saved.Selections, remap.keys)
+		} else {
+			remapSelections(saved.Selections, remap.oldPath, remap.newPath)
+		}
+		for _, querySelections := range saved.QuerySelections {
+			if remap.keys != nil {
+				rekeySelections(querySelections, remap.keys)
+			} else {
+				remapSelections(querySelections, remap.oldPath, remap.newPath)
+			}
+		}
+	}
+
+	mergeSelections(h.selections, saved.Selections)
+	for queryHash, querySelections := range saved.QuerySelections {
+		if len(querySelections) == 0 {
+			continue
+		}
+		if h.querySelections[queryHash] == nil {
+			h.querySelections[queryHash] = make(map[string]SelectionInfo)
+		}
+		mergeSelections(h.querySelections[queryHash], querySelections)
+		if _, ok := h.queryTexts[queryHash]; !ok && saved.QueryTexts[queryHash] != "" {
+			h.queryTexts[queryHash] = saved.QueryTexts[queryHash]
+		}
+	}
+	for nanos, source := range saved.Sources {
+		if _, ok := h.sources[nanos]; !ok && (h.clearedAt.IsZero() || time.Unix(0, nanos).After(h.clearedAt)) {
+			h.sources[nanos] = source
+		}
+	}
+	h.cachedGlobalScores = nil
+}
+
+// mergeSelections adds the timestamps of saved to selections
+// Timestamps present in both (loaded earlier from the same file) are kept once
+func mergeSelections(selections, saved map[string]SelectionInfo) {
+	for item, savedInfo := range saved {
+		info, ok := selections[item]
+		if !ok {
+			selections[item] = SelectionInfo{Timestamps: append([]time.Time(nil), savedInfo.Timestamps...)}
+			continue
+		}
+
+		seen := make(map[int64]bool, len(info.Timestamps))
+		for _, timestamp := range info.Timestamps {
+			seen[timestamp.UnixNano()] = true
+		}
+		merged := info.Timestamps
+		for _, timestamp := range savedInfo.Timestamps {
+			if !seen[timestamp.UnixNano()] {
+				seen[timestamp.UnixNano()] = true
+				merged = append(merged, timestamp)
+			}
+		}
+		if len(merged) > len(info.Timestamps) {
+			sort.Slice(merged, func(i, j int) bool { return merged[i].Before(merged[j]) })
+			selections[item] = SelectionInfo{Timestamps: merged}
+		}
+	}
+}
+
+// dropBefore removes the timestamps not after t (the history was cleared at t)
+func dropBefore(selections map[string]SelectionInfo, t time.Time) {
+	for item, info := range selections {
+		kept := make([]time.Time, 0, len(info.Timestamps))
+		for _, timestamp := range info.Timestamps {
+			if timestamp.After(t) {
+				kept = append(kept, timestamp)
+			}
+		}
+		if len(kept) == 0 {
+			delete(selections, item)
+		} else {
+			selections[item] = SelectionInfo{Timestamps: kept}
+		}
+	}
+}
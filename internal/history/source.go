@@ -0,0 +1,114 @@
+package history
+
+import (
+	"sort"
+	"time"
+)
+
+// Sources of a selection: the frontend that recorded it (see SetSource)
+const (
+	SourceTUI        = "tui"         // Enter, Ctrl+O and the other open keys in the TUI
+	SourceGo         = "go-flag"     // -g/--go (and --select-1) opening the best match
+	SourceJSONRecord = "json-record" // --json-record from integrations like Raycast
+	SourceClone      = "clone"       // --edit and --cd working on the local clone
+	SourceServe      = "serve"       // /record of the --serve query API (editor plugins)
+	// SourceUnknown is reported for selections recorded without a source (older versions)
+	SourceUnknown = "unknown"
+)
+
+// SetSource sets the source tagged on the selections recorded from now on
+// An empty source records untagged selections
+func (h *History) SetSource(source string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.source = source
+}
+
+// tagLocked records the source of the global selection made at timestamp
+// The caller must hold h.mu
+func (h *History) tagLocked(timestamp time.Time) {
+	if h.source == "" {
+		return
+	}
+	if h.sources == nil {
+		h.sources = make(map[int64]string)
+	}
+	h.sources[timestamp.UnixNano()] = h.source
+}
+
+// pruneSourcesLocked drops the sources of selections older than maxAgeDays (cleaned up with them)
+// The caller must hold h.mu
+func (h *History) pruneSourcesLocked(now time.Time, maxAgeDays float64) {
+	for nanos := range h.sources {
+		if now.Sub(time.Unix(0, nanos)).Hours()/24 > maxAgeDays {
+			delete(h.sources, nanos)
+		}
+	}
+}
+
+// SourceStat counts the selections recorded by one source
+type SourceStat struct {
+	Source     string // Source name (SourceUnknown for untagged selections)
+	Selections int    // Number of selections
+}
+
+// WeekStat counts the selections made in one week
+type WeekStat struct {
+	Week       time.Time      // Monday 00:00 (local time) starting the week
+	Selections int            // Number of selections in the week
+	Sources    map[string]int // Selections by source
+}
+
+// UsageStats returns the selections by source (most selections first) and by week
+// (most recent first), for --history --stats
+func (h *History) UsageStats() ([]SourceStat, []WeekStat) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	bySource := make(map[string]int)
+	byWeek := make(map[time.Time]*WeekStat)
+	for _, info := range h.selections {
+		for _, timestamp := range info.Timestamps {
+			source := h.sources[timestamp.UnixNano()]
+			if source == "" {
+				source = SourceUnknown
+			}
+			bySource[source]++
+
+			week := weekStart(timestamp)
+			stat := byWeek[week]
+			if stat == nil {
+				stat = &WeekStat{Week: week, Sources: make(map[string]int)}
+				byWeek[week] = stat
+			}
+			stat.Selections++
+			stat.Sources[source]++
+		}
+	}
+
+	sources := make([]SourceStat, 0, len(bySource))
+	for source, selections := range bySource {
+		sources = append(sources, SourceStat{Source: source, Selections: selections})
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Selections != sources[j].Selections {
+			return sources[i].Selections > sources[j].Selections
+		}
+		return sources[i].Source < sources[j].Source
+	})
+
+	weeks := make([]WeekStat, 0, len(byWeek))
+	for _, stat := range byWeek {
+		weeks = append(weeks, *stat)
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].Week.After(weeks[j].Week) })
+
+	return sources, weeks
+}
+
+// weekStart returns the Monday 00:00 (local time) starting the week of t
+func weekStart(t time.Time) time.Time {
+	t = t.Local()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.Local)
+}
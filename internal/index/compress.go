@@ -0,0 +1,42 @@
+package index
+
+import (
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdMagic starts every zstd frame; it is not valid UTF-8, so it never starts a plain description
+const zstdMagic = "\x28\xb5\x2f\xfd"
+
+// Shared coders: EncodeAll and DecodeAll are safe for concurrent use
+var (
+	bodyEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	bodyDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+)
+
+// compressBody returns the stored form of a description: zstd-compressed, or the text itself
+// when compression would not make it smaller (short descriptions)
+func compressBody(description string) string {
+	if description == "" {
+		return ""
+	}
+	compressed := bodyEncoder.EncodeAll([]byte(description), nil)
+	if len(compressed) >= len(description) {
+		return description
+	}
+	return string(compressed)
+}
+
+// decompressBody returns the description stored by compressBody
+// A body that fails to decompress is dropped rather than shown as binary
+func decompressBody(body string) string {
+	if !strings.HasPrefix(body, zstdMagic) {
+		return body
+	}
+	description, err := bodyDecoder.DecodeAll([]byte(body), nil)
+	if err != nil {
+		return ""
+	}
+	return string(description)
+}
@@ -0,0 +1,56 @@
+package index
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompressBody(t *testing.T) {
+	long := strings.Repeat("Payment gateway for the checkout service. ", 20)
+	for _, description := range []string{"", "Short", "Описание шлюза", long} {
+		body := compressBody(description)
+		if got := decompressBody(body); got != description {
+			t.Errorf("decompressBody(compressBody(%q)) = %q", description, got)
+		}
+	}
+
+	if body := compressBody(long); len(body) >= len(long) || !strings.HasPrefix(body, zstdMagic) {
+		t.Errorf("Expected a long description stored compressed, got %d of %d bytes", len(body), len(long))
+	}
+	if body := compressBody("Short"); body != "Short" {
+		t.Errorf("Expected a short description stored as is, got %q", body)
+	}
+	if got := decompressBody(zstdMagic + "garbage"); got != "" {
+		t.Errorf("Expected a damaged body dropped, got %q", got)
+	}
+}
+
+func TestDescriptionIndex_CompressedDescription(t *testing.T) {
+	di, err := NewDescriptionIndex(filepath.Join(t.TempDir(), "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer func() { _ = di.Close() }()
+
+	long := strings.Repeat("filler words here ", 12) + "the ingress controller routes traffic " + strings.Repeat("more text ", 20)
+	if err := di.AddBatch([]DescriptionDocument{{ProjectID: 1, ProjectPath: "infra/edge", ProjectName: "edge", Description: long}}); err != nil {
+		t.Fatalf("AddBatch failed: %v", err)
+	}
+
+	matches, err := di.Search("ingress", 10)
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("Search = %v, %v; want one match", matches, err)
+	}
+	if matches[0].Project.Description != long {
+		t.Errorf("Expected the description decompressed, got %q", matches[0].Project.Description)
+	}
+	if !strings.Contains(matches[0].Snippet, "ingress controller") {
+		t.Errorf("Expected the snippet cut around the match, got %q", matches[0].Snippet)
+	}
+
+	project, found, err := di.GetProject("infra/edge")
+	if err != nil || !found || project.Description != long {
+		t.Errorf("GetProject = %q, %v, %v; want the decompressed description", project.Description, found, err)
+	}
+}
@@ -2,37 +2,98 @@
 package index
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"math"
 	"os"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
 	"github.com/blevesearch/bleve/v2/analysis/analyzer/simple"
 	"github.com/blevesearch/bleve/v2/analysis/analyzer/standard"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/regexp"
 	"github.com/blevesearch/bleve/v2/mapping"
 	"github.com/blevesearch/bleve/v2/search"
 	"github.com/blevesearch/bleve/v2/search/query"
 	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/translit"
 )
 
 const (
 	// IndexVersion is the current version of the index schema
 	// Increment this when making breaking changes to the index structure
-	IndexVersion = 5 // Version 5: Path fields use simple analyzer
+	IndexVersion = 16 // Version 16: descriptions stored zstd-compressed (DescriptionBody)
 
 	// Version metadata document ID (reserved, never used for actual projects)
 	versionDocID = "__index_version__"
+
+	// pathSegmentAnalyzer splits paths at slashes only, so "platform/api-gateway"
+	// is indexed as the segments "platform" and "api-gateway"
+	pathSegmentAnalyzer = "path_segment"
 )
 
+// pathExactField indexes the whole project path as a single term (see pathQuery)
+const pathExactField = "PathExact"
+
+// storedFields lists the stored document fields needed to rebuild a model.Project
+// Description is stored by schemas before version 16 only (read when migrating them)
+var storedFields = []string{"ProjectID", "ProjectPath", "ProjectName", "DescriptionBody", "Description", "Starred", "Archived", "Topics", "Member", "OpenMRs", "OpenIssues", "DefaultBranch", "Visibility", "LastActivityAt", "ForkedFrom", "FormerPaths"}
+
 // ErrIndexVersionMismatch indicates the index schema version is incompatible
 var ErrIndexVersionMismatch = errors.New("index version mismatch")
 
+// ErrReadOnly indicates that a read-only cache cannot provide the index (missing or outdated)
+var ErrReadOnly = errors.New("read-only cache")
+
+// readOnly opens indexes without write access and never creates or recreates them (shared caches)
+var readOnly bool
+
+// SetReadOnly makes the index functions treat the cache directory as read-only
+func SetReadOnly(enabled bool) {
+	readOnly = enabled
+}
+
+// fuzziness is the edit distance tolerated per query token (negative = depends on its length)
+var fuzziness = -1
+
+// SetFuzziness sets the edit distance tolerated per query token (search.fuzziness)
+// 0 to 2 apply to every token (bleve supports up to 2); a negative value picks it by token length
+func SetFuzziness(distance int) {
+	fuzziness = min(distance, 2)
+}
+
+// fuzzinessFor returns the edit distance tolerated for a query token
+// Automatic fuzziness spares short tokens, where one edit already matches unrelated terms
+func fuzzinessFor(token string) int {
+	if fuzziness >= 0 {
+		return fuzziness
+	}
+	switch length := utf8.RuneCountInString(token); {
+	case length <= 2:
+		return 0
+	case length <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
 // DescriptionIndex manages the bleve index for project descriptions
 type DescriptionIndex struct {
-	index bleve.Index
-	path  string
+	index    bleve.Index
+	path     string
+	readOnly bool // Opened from a read-only cache: writes fail with ErrReadOnly
+
+	snapshotStale bool // Index modified since the last SaveSnapshot (snapshot removed)
 }
 
 // versionDocument stores the index schema version
@@ -48,6 +109,10 @@ func NewDescriptionIndex(indexPath string) (*DescriptionIndex, error) {
 
 	// Check if index already exists
 	if _, statErr := os.Stat(indexPath); os.IsNotExist(statErr) {
+		if readOnly {
+			return nil, fmt.Errorf("%w: no index at %s", ErrReadOnly, indexPath)
+		}
+
 		// Create new index with custom mapping
 		indexMapping := buildIndexMapping()
 		index, err = bleve.New(indexPath, indexMapping)
@@ -62,9 +127,16 @@ func NewDescriptionIndex(indexPath string) (*DescriptionIndex, error) {
 			return nil, fmt.Errorf("failed to store index version: %w", err)
 		}
 	} else {
-		// Open existing index
-		index, err = bleve.Open(indexPath)
+		// Open existing index (read-only indexes skip the write lock)
+		if readOnly {
+			index, err = bleve.OpenUsing(indexPath, map[string]interface{}{"read_only": true})
+		} else {
+			index, err = bleve.Open(indexPath)
+		}
 		if err != nil {
+			if !errors.Is(err, fs.ErrPermission) {
+				err = fmt.Errorf("%w: %w", ErrIndexCorrupt, err)
+			}
 			return nil, fmt.Errorf("failed to open index: %w", err)
 		}
 
@@ -98,13 +170,14 @@ func NewDescriptionIndex(indexPath string) (*DescriptionIndex, error) {
 	}
 
 	return &DescriptionIndex{
-		index: index,
-		path:  indexPath,
+		index:    index,
+		path:     indexPath,
+		readOnly: readOnly,
 	}, nil
 }
 
 // buildFieldQuery creates a query for a specific field with multi-token support
-// Combines MatchQuery (fuzzy, distance=1) + PrefixQuery for flexible matching
+// Combines MatchQuery (fuzzy, see fuzzinessFor) + PrefixQuery for flexible matching
 // For single token: returns DisjunctionQuery(MatchQuery OR PrefixQuery)
 // For multiple tokens: returns ConjunctionQuery(AND) of DisjunctionQuery for each token
 func buildFieldQuery(tokens []string, field string, boost float64) query.Query {
@@ -115,7 +188,7 @@ func buildFieldQuery(tokens []string, field string, boost float64) query.Query {
 	if len(tokens) == 1 {
 		matchQ := bleve.NewMatchQuery(tokens[0])
 		matchQ.SetField(field)
-		matchQ.SetFuzziness(1)
+		matchQ.SetFuzziness(fuzzinessFor(tokens[0]))
 
 		prefixQ := bleve.NewPrefixQuery(tokens[0])
 		prefixQ.SetField(field)
@@ -129,7 +202,7 @@ func buildFieldQuery(tokens []string, field string, boost float64) query.Query {
 	for i, token := range tokens {
 		matchQ := bleve.NewMatchQuery(token)
 		matchQ.SetField(field)
-		matchQ.SetFuzziness(1)
+		matchQ.SetFuzziness(fuzzinessFor(token))
 
 		prefixQ := bleve.NewPrefixQuery(token)
 		prefixQ.SetField(field)
@@ -142,6 +215,57 @@ func buildFieldQuery(tokens []string, field string, boost float64) query.Query {
 	return conjunctionQuery
 }
 
+// buildSearchQuery builds the full-text query used by Search and ExplainScore
+// Returns the query and the lowercased query tokens
+func buildSearchQuery(queryText string) (query.Query, []string) {
+	// Normalize query (lowercase for case-insensitive search)
+	queryLower := strings.ToLower(queryText)
+
+	// Split query into tokens for multi-word support
+	tokens := strings.Fields(queryLower)
+
+	// Build field queries with multi-token support
+	// ProjectName: highest priority (10x boost)
+	nameQuery := buildFieldQuery(tokens, "ProjectName", 10.0)
+
+	// ProjectPath: medium priority (5x boost)
+	pathQuery := buildFieldQuery(tokens, "ProjectPath", 5.0)
+
+	// PathSegments: tokens starting whole path segments ("plat api" → platform/api-gateway)
+	// rank above tokens matched inside words (8x boost)
+	segmentQuery := buildSegmentQuery(tokens, 8.0)
+
+	// Description: lowest priority (1x boost)
+	descQuery := buildFieldQuery(tokens, "Description", 1.0)
+
+	// Fallback: full-query MatchQuery on Description (standard analyzer handles tokenization differently)
+	descriptionMatch := bleve.NewMatchQuery(queryText)
+	descriptionMatch.SetField("Description")
+	descriptionMatch.SetBoost(1.0)
+
+	// Combine with OR logic (disjunction)
+	return bleve.NewDisjunctionQuery(nameQuery, pathQuery, segmentQuery, descQuery, descriptionMatch), tokens
+}
+
+// buildSegmentQuery matches every token as the prefix of a path segment
+// Unlike ProjectPath, segments keep their punctuation, so "api-gate" matches "api-gateway"
+func buildSegmentQuery(tokens []string, boost float64) query.Query {
+	if len(tokens) == 0 {
+		return bleve.NewMatchNoneQuery()
+	}
+
+	tokenQueries := make([]query.Query, len(tokens))
+	for i, token := range tokens {
+		prefixQ := bleve.NewPrefixQuery(token)
+		prefixQ.SetField("PathSegments")
+		tokenQueries[i] = prefixQ
+	}
+
+	conjunctionQuery := bleve.NewConjunctionQuery(tokenQueries...)
+	conjunctionQuery.SetBoost(boost)
+	return conjunctionQuery
+}
+
 // buildIndexMapping creates the index mapping for description documents
 func buildIndexMapping() mapping.IndexMapping {
 	indexMapping := bleve.NewIndexMapping()
@@ -149,6 +273,21 @@ func buildIndexMapping() mapping.IndexMapping {
 	// Use standard analyzer (supports stemming and stop words)
 	indexMapping.DefaultAnalyzer = standard.Name
 
+	// Path segments: one lowercased token per slash-separated segment
+	if err := indexMapping.AddCustomTokenizer(pathSegmentAnalyzer, map[string]interface{}{
+		"type":   regexp.Name,
+		"regexp": `[^/]+`,
+	}); err != nil {
+		panic(fmt.Sprintf("path segment tokenizer: %v", err)) // Static configuration
+	}
+	if err := indexMapping.AddCustomAnalyzer(pathSegmentAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     pathSegmentAnalyzer,
+		"token_filters": []string{lowercase.Name},
+	}); err != nil {
+		panic(fmt.Sprintf("path segment analyzer: %v", err)) // Static configuration
+	}
+
 	// Document mapping for project descriptions
 	descMapping := bleve.NewDocumentMapping()
 
@@ -157,23 +296,59 @@ func buildIndexMapping() mapping.IndexMapping {
 	pathFieldMapping.Analyzer = simple.Name
 	pathFieldMapping.Store = true
 	pathFieldMapping.Index = true
-	descMapping.AddFieldMappingsAt("ProjectPath", pathFieldMapping)
+	pathFieldMapping.IncludeTermVectors = false // Only descriptions are highlighted
+
+	// PathSegments: the path again, split at slashes only (indexed, not stored)
+	segmentsFieldMapping := bleve.NewTextFieldMapping()
+	segmentsFieldMapping.Name = "PathSegments"
+	segmentsFieldMapping.Analyzer = pathSegmentAnalyzer
+	segmentsFieldMapping.Store = false
+	segmentsFieldMapping.Index = true
+	segmentsFieldMapping.IncludeTermVectors = false
+
+	// PathExact: the whole path as one term, to look documents up by path (indexed, not stored)
+	exactFieldMapping := bleve.NewTextFieldMapping()
+	exactFieldMapping.Name = pathExactField
+	exactFieldMapping.Analyzer = keyword.Name
+	exactFieldMapping.Store = false
+	exactFieldMapping.Index = true
+	exactFieldMapping.IncludeTermVectors = false
+	descMapping.AddFieldMappingsAt("ProjectPath", pathFieldMapping, segmentsFieldMapping, exactFieldMapping)
 
 	// ProjectName: simple analyzer preserves exact tokens without stemming
 	nameFieldMapping := bleve.NewTextFieldMapping()
 	nameFieldMapping.Analyzer = simple.Name
 	nameFieldMapping.Store = true
 	nameFieldMapping.Index = true
+	nameFieldMapping.IncludeTermVectors = false // Only descriptions are highlighted
 	descMapping.AddFieldMappingsAt("ProjectName", nameFieldMapping)
 
-	// Description: text field with full-text search
+	// Transliteration: Latin spelling of Cyrillic paths, names and descriptions (indexed, not stored)
+	translitFieldMapping := bleve.NewTextFieldMapping()
+	translitFieldMapping.Analyzer = simple.Name
+	translitFieldMapping.Store = false
+	translitFieldMapping.Index = true
+	translitFieldMapping.IncludeTermVectors = false
+	descMapping.AddFieldMappingsAt("Transliteration", translitFieldMapping)
+
+	// Description: text field with full-text search (indexed, stored compressed as DescriptionBody)
 	descriptionFieldMapping := bleve.NewTextFieldMapping()
 	descriptionFieldMapping.Analyzer = standard.Name
-	descriptionFieldMapping.Store = true // Store for snippet extraction
+	descriptionFieldMapping.Store = false
 	descriptionFieldMapping.Index = true
-	descriptionFieldMapping.IncludeTermVectors = true // For better snippet highlighting
+	// No term vectors: snippets are cut around the first query token instead of from
+	// highlight fragments (term vectors were the largest part of the index)
+	descriptionFieldMapping.IncludeTermVectors = false
 	descMapping.AddFieldMappingsAt("Description", descriptionFieldMapping)
 
+	// DescriptionBody: the description for results and snippets, zstd-compressed (not searchable, just stored)
+	bodyFieldMapping := bleve.NewTextFieldMapping()
+	bodyFieldMapping.Analyzer = keyword.Name // Cheapest analysis of a field that is not indexed
+	bodyFieldMapping.Store = true
+	bodyFieldMapping.Index = false // Searched through Description
+	bodyFieldMapping.IncludeTermVectors = false
+	descMapping.AddFieldMappingsAt("DescriptionBody", bodyFieldMapping)
+
 	// Starred: boolean field (not searchable, just stored)
 	starredFieldMapping := bleve.NewBooleanFieldMapping()
 	starredFieldMapping.Store = true
@@ -186,12 +361,65 @@ func buildIndexMapping() mapping.IndexMapping {
 	archivedFieldMapping.Index = false // No need to search by this
 	descMapping.AddFieldMappingsAt("Archived", archivedFieldMapping)
 
+	// Topics: keyword-like text field (stored for topic: filters)
+	topicsFieldMapping := bleve.NewTextFieldMapping()
+	topicsFieldMapping.Analyzer = simple.Name
+	topicsFieldMapping.Store = true
+	topicsFieldMapping.Index = true
+	topicsFieldMapping.IncludeTermVectors = false
+	descMapping.AddFieldMappingsAt("Topics", topicsFieldMapping)
+
 	// Member: boolean field (not searchable, just stored)
 	memberFieldMapping := bleve.NewBooleanFieldMapping()
 	memberFieldMapping.Store = true
 	memberFieldMapping.Index = false // No need to search by this
 	descMapping.AddFieldMappingsAt("Member", memberFieldMapping)
 
+	// ProjectID: numeric GitLab ID (not searchable, just stored)
+	idFieldMapping := bleve.NewNumericFieldMapping()
+	idFieldMapping.Store = true
+	idFieldMapping.Index = false // No need to search by this
+	descMapping.AddFieldMappingsAt("ProjectID", idFieldMapping)
+
+	// OpenMRs/OpenIssues: numeric insight counters (not searchable, just stored)
+	for _, field := range []string{"OpenMRs", "OpenIssues"} {
+		counterFieldMapping := bleve.NewNumericFieldMapping()
+		counterFieldMapping.Store = true
+		counterFieldMapping.Index = false // No need to search by this
+		descMapping.AddFieldMappingsAt(field, counterFieldMapping)
+	}
+
+	// DefaultBranch/Visibility/ForkedFrom: metadata strings (not searchable, just stored)
+	for _, field := range []string{"DefaultBranch", "Visibility", "ForkedFrom"} {
+		metadataFieldMapping := bleve.NewTextFieldMapping()
+		metadataFieldMapping.Store = true
+		metadataFieldMapping.Index = false // No need to search by this
+		descMapping.AddFieldMappingsAt(field, metadataFieldMapping)
+	}
+
+	// FormerPaths: paths of renamed projects before the rename, searched like the path
+	formerPathsFieldMapping := bleve.NewTextFieldMapping()
+	formerPathsFieldMapping.Analyzer = simple.Name
+	formerPathsFieldMapping.Store = true
+	formerPathsFieldMapping.Index = true
+	formerPathsFieldMapping.IncludeTermVectors = false
+	descMapping.AddFieldMappingsAt("FormerPaths", formerPathsFieldMapping)
+
+	// LastActivityAt: Unix seconds (not searchable, just stored)
+	activityFieldMapping := bleve.NewNumericFieldMapping()
+	activityFieldMapping.Store = true
+	activityFieldMapping.Index = false // No need to search by this
+	descMapping.AddFieldMappingsAt("LastActivityAt", activityFieldMapping)
+
+	// Nothing sorts or facets on fields and every query names its field:
+	// skip doc values and the composite _all field (together about half of the index size)
+	for _, property := range descMapping.Properties {
+		for _, fieldMapping := range property.Fields {
+			fieldMapping.DocValues = false
+			fieldMapping.IncludeInAll = false
+		}
+	}
+
 	indexMapping.DefaultMapping = descMapping
 
 	return indexMapping
@@ -206,16 +434,29 @@ func (di *DescriptionIndex) Add(projectPath, projectName, description string, st
 		Starred:     starred,
 		Archived:    archived,
 	}
+	doc.Transliteration = doc.transliteration()
+	doc.DescriptionBody = compressBody(doc.Description)
 
-	return di.index.Index(projectPath, doc)
+	if di.readOnly {
+		return fmt.Errorf("%w: cannot index %s", ErrReadOnly, projectPath)
+	}
+	di.invalidateSnapshot()
+	return di.index.Index(doc.key(), doc)
 }
 
 // AddBatch indexes multiple description documents in a batch
 func (di *DescriptionIndex) AddBatch(docs []DescriptionDocument) error {
+	// Bleve blocks on writes to an index opened read-only, so they are refused here
+	if di.readOnly {
+		return fmt.Errorf("%w: cannot index %d documents", ErrReadOnly, len(docs))
+	}
+	di.invalidateSnapshot()
 	batch := di.index.NewBatch()
 
 	for _, doc := range docs {
-		if err := batch.Index(doc.ProjectPath, doc); err != nil {
+		doc.Transliteration = doc.transliteration()
+		doc.DescriptionBody = compressBody(doc.Description)
+		if err := batch.Index(doc.key(), doc); err != nil {
 			return fmt.Errorf("failed to add document %s to batch: %w", doc.ProjectPath, err)
 		}
 	}
@@ -227,86 +468,35 @@ func (di *DescriptionIndex) AddBatch(docs []DescriptionDocument) error {
 // Uses field boosting: ProjectName (5x), ProjectPath (2x), Description (1x)
 // Supports multi-word queries with AND logic (all words must be present)
 func (di *DescriptionIndex) Search(query string, maxResults int) ([]DescriptionMatch, error) {
+	return di.SearchContext(context.Background(), query, maxResults)
+}
+
+// SearchContext is like Search but stops early when ctx is canceled (returns ctx.Err())
+func (di *DescriptionIndex) SearchContext(ctx context.Context, query string, maxResults int) ([]DescriptionMatch, error) {
 	if query == "" {
 		return []DescriptionMatch{}, nil
 	}
 
-	// Normalize query (lowercase for case-insensitive search)
-	queryLower := strings.ToLower(query)
-
-	// Split query into tokens for multi-word support
-	tokens := strings.Fields(queryLower)
-
-	// Build field queries with multi-token support
-	// ProjectName: highest priority (10x boost)
-	nameQuery := buildFieldQuery(tokens, "ProjectName", 10.0)
-
-	// ProjectPath: medium priority (5x boost)
-	pathQuery := buildFieldQuery(tokens, "ProjectPath", 5.0)
-
-	// Description: lowest priority (1x boost)
-	descQuery := buildFieldQuery(tokens, "Description", 1.0)
-
-	// Fallback: full-query MatchQuery on Description (standard analyzer handles tokenization differently)
-	descriptionMatch := bleve.NewMatchQuery(query)
-	descriptionMatch.SetField("Description")
-	descriptionMatch.SetBoost(1.0)
-
-	// Combine with OR logic (disjunction)
-	boolQuery := bleve.NewDisjunctionQuery(nameQuery, pathQuery, descQuery, descriptionMatch)
-
-	searchRequest := bleve.NewSearchRequestOptions(boolQuery, maxResults, 0, false)
-
-	// Request snippets for context
-	searchRequest.Highlight = bleve.NewHighlight()
-	searchRequest.Fields = []string{"ProjectPath", "ProjectName", "Description", "Starred", "Archived", "Member"}
-
-	// Execute search
-	searchResults, err := di.index.Search(searchRequest)
+	// Execute search (with the other Cyrillic/Latin spellings of the query, see searchQueries)
+	hits, tokens, err := di.searchSpellings(ctx, query, maxResults, storedFields, false)
 	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
+		return nil, err
 	}
 
-	// Convert results to DescriptionMatch
-	matches := make([]DescriptionMatch, 0, len(searchResults.Hits))
-	for _, hit := range searchResults.Hits {
-		projectPath, ok := hit.Fields["ProjectPath"].(string)
-		if !ok {
-			projectPath = ""
-		}
-		projectName, ok := hit.Fields["ProjectName"].(string)
-		if !ok {
-			projectName = ""
-		}
-		description, ok := hit.Fields["Description"].(string)
-		if !ok {
-			description = ""
-		}
-		starred, ok := hit.Fields["Starred"].(bool)
-		if !ok {
-			starred = false
-		}
-		archived, ok := hit.Fields["Archived"].(bool)
-		if !ok {
-			archived = false
-		}
-		member, ok := hit.Fields["Member"].(bool)
-		if !ok {
-			member = false
-		}
+	// Snippets are cut around the first token of the description in any spelling of the query
+	snippetTokens := slices.Clip(tokens)
+	for _, variant := range translit.Variants(query) {
+		snippetTokens = append(snippetTokens, strings.Fields(variant)...)
+	}
 
-		// Extract snippet from highlight or description
-		snippet := extractSnippet(hit)
+	// Convert results to DescriptionMatch
+	matches := make([]DescriptionMatch, 0, len(hits))
+	for _, hit := range hits {
+		// Extract snippet around the first matching token of the description
+		snippet := extractSnippet(hit, snippetTokens...)
 
 		match := DescriptionMatch{
-			Project: model.Project{
-				Path:        projectPath,
-				Name:        projectName,
-				Description: description,
-				Starred:     starred,
-				Archived:    archived,
-				Member:      member,
-			},
+			Project: projectFromHit(hit),
 			Score:   hit.Score,
 			Snippet: snippet,
 		}
@@ -316,8 +506,86 @@ func (di *DescriptionIndex) Search(query string, maxResults int) ([]DescriptionM
 	return matches, nil
 }
 
+// projectFromHit rebuilds a project from the stored fields of a search hit
+func projectFromHit(hit *search.DocumentMatch) model.Project {
+	projectID, _ := hit.Fields["ProjectID"].(float64)
+	projectPath, _ := hit.Fields["ProjectPath"].(string)
+	projectName, _ := hit.Fields["ProjectName"].(string)
+	description := storedDescription(hit)
+	starred, _ := hit.Fields["Starred"].(bool)
+	archived, _ := hit.Fields["Archived"].(bool)
+	member, _ := hit.Fields["Member"].(bool)
+	topics := stringsField(hit.Fields["Topics"])
+	openMRs, _ := hit.Fields["OpenMRs"].(float64)
+	openIssues, _ := hit.Fields["OpenIssues"].(float64)
+	defaultBranch, _ := hit.Fields["DefaultBranch"].(string)
+	visibility, _ := hit.Fields["Visibility"].(string)
+	lastActivity, _ := hit.Fields["LastActivityAt"].(float64)
+	forkedFrom, _ := hit.Fields["ForkedFrom"].(string)
+	formerPaths := stringsField(hit.Fields["FormerPaths"])
+	var lastActivityAt time.Time
+	if lastActivity > 0 {
+		lastActivityAt = time.Unix(int64(lastActivity), 0).UTC()
+	}
+
+	return model.Project{
+		ID:          int64(projectID),
+		Path:        projectPath,
+		Name:        projectName,
+		Description: description,
+		Starred:     starred,
+		Archived:    archived,
+		Topics:      topics,
+		Member:      member,
+		OpenMRs:     int(openMRs),
+		OpenIssues:  int(openIssues),
+
+		DefaultBranch:  defaultBranch,
+		Visibility:     visibility,
+		LastActivityAt: lastActivityAt,
+		ForkedFrom:     forkedFrom,
+		FormerPaths:    formerPaths,
+	}
+}
+
+// storedDescription returns the description of a search hit, stored compressed as
+// DescriptionBody (schemas before version 16 stored it uncompressed as Description)
+func storedDescription(hit *search.DocumentMatch) string {
+	if body, ok := hit.Fields["DescriptionBody"].(string); ok {
+		return decompressBody(body)
+	}
+	description, _ := hit.Fields["Description"].(string)
+	return description
+}
+
+// stringsField converts a stored multi-value field (a single string or a slice) to []string
+func stringsField(v interface{}) []string {
+	switch val := v.(type) {
+	case string:
+		return []string{val}
+	case []interface{}:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			if str, ok := item.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// snippetLength is the maximum snippet length taken from a description
+const snippetLength = 150
+
+// snippetContext is how many characters before the first match a snippet window starts
+const snippetContext = 40
+
 // extractSnippet extracts a relevant snippet from search hit
-func extractSnippet(hit *search.DocumentMatch) string {
+// Uses highlight fragments when present; otherwise the description is truncated,
+// or cut around the first of the given tokens when it appears past the first snippetLength characters
+func extractSnippet(hit *search.DocumentMatch, tokens ...string) string {
 	// Try to get highlighted fragments first
 	if len(hit.Fragments) > 0 && len(hit.Fragments["Description"]) > 0 {
 		// Join first few fragments
@@ -331,9 +599,12 @@ func extractSnippet(hit *search.DocumentMatch) string {
 	}
 
 	// Fallback: truncate description
-	if description, ok := hit.Fields["Description"].(string); ok {
-		if len(description) > 150 {
-			return description[:150] + "..."
+	if description := storedDescription(hit); description != "" {
+		if window, ok := matchWindow(description, tokens); ok {
+			return window
+		}
+		if len(description) > snippetLength {
+			return description[:snippetLength] + "..."
 		}
 		return description
 	}
@@ -341,6 +612,54 @@ func extractSnippet(hit *search.DocumentMatch) string {
 	return ""
 }
 
+// matchWindow returns a snippet of description centered on the earliest token occurrence
+// ok is false when no token occurs or the match already fits in the plain truncated snippet
+func matchWindow(description string, tokens []string) (string, bool) {
+	if len(tokens) == 0 {
+		return "", false
+	}
+
+	// unicode.ToLower maps rune to rune, so rune offsets line up with the original
+	runes := []rune(description)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	lowerStr := string(lower)
+
+	first, firstLen := -1, 0
+	for _, token := range tokens {
+		if idx := strings.Index(lowerStr, strings.ToLower(token)); idx >= 0 {
+			pos := utf8.RuneCountInString(lowerStr[:idx])
+			if first < 0 || pos < first {
+				first, firstLen = pos, utf8.RuneCountInString(token)
+			}
+		}
+	}
+	if first < 0 || first+firstLen <= snippetLength {
+		return "", false
+	}
+
+	// Start a little before the match, at a word boundary
+	start := first - snippetContext
+	for i := start; i < first; i++ {
+		if unicode.IsSpace(runes[i]) {
+			start = i + 1
+			break
+		}
+	}
+	end := start + snippetLength
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	window := "..." + string(runes[start:end])
+	if end < len(runes) {
+		window += "..."
+	}
+	return window, true
+}
+
 // stripHTMLTags removes HTML tags from a string
 func stripHTMLTags(s string) string {
 	// Simple regex-free approach: remove everything between < and >
@@ -358,9 +677,51 @@ func stripHTMLTags(s string) string {
 	return result.String()
 }
 
-// Delete removes a document from the index
+// Delete removes the project at projectPath from the index (nothing if it is not indexed)
 func (di *DescriptionIndex) Delete(projectPath string) error {
-	return di.index.Delete(projectPath)
+	if di.readOnly {
+		return fmt.Errorf("%w: cannot delete %s", ErrReadOnly, projectPath)
+	}
+
+	// Documents are keyed by project ID, so the path is looked up first
+	searchResults, err := di.index.Search(bleve.NewSearchRequest(pathQuery(projectPath)))
+	if err != nil {
+		return fmt.Errorf("lookup failed: %w", err)
+	}
+	if len(searchResults.Hits) == 0 {
+		return nil
+	}
+
+	di.invalidateSnapshot()
+	batch := di.index.NewBatch()
+	for _, hit := range searchResults.Hits {
+		batch.Delete(hit.ID)
+	}
+	return di.index.Batch(batch)
+}
+
+// pathQuery matches the document of the project at projectPath
+func pathQuery(projectPath string) query.Query {
+	termQuery := bleve.NewTermQuery(projectPath)
+	termQuery.SetField(pathExactField)
+	return termQuery
+}
+
+// GetProject looks up a single project by its path
+// Returns false if the project is not in the index
+func (di *DescriptionIndex) GetProject(projectPath string) (model.Project, bool, error) {
+	searchRequest := bleve.NewSearchRequest(pathQuery(projectPath))
+	searchRequest.Fields = storedFields
+
+	searchResults, err := di.index.Search(searchRequest)
+	if err != nil {
+		return model.Project{}, false, fmt.Errorf("lookup failed: %w", err)
+	}
+	if len(searchResults.Hits) == 0 {
+		return model.Project{}, false, nil
+	}
+
+	return projectFromHit(searchResults.Hits[0]), true, nil
 }
 
 // Count returns the number of indexed documents
@@ -380,16 +741,43 @@ func Exists(indexPath string) bool {
 }
 
 // NewDescriptionIndexWithAutoRecreate creates or opens a description index
-// Automatically recreates the index if version mismatch is detected
+// On a version mismatch the index is migrated in place from its stored fields; only
+// if that fails is it recreated empty (recreated = true, the caller runs a full sync).
+// A missing or damaged index is replaced by the previous generation kept by the last
+// full sync, or recreated empty without one; either way recreated is true as well
 func NewDescriptionIndexWithAutoRecreate(indexPath string) (*DescriptionIndex, bool, error) {
+	// A full sync interrupted between its renames leaves only the previous generation
+	restored := !readOnly && !Exists(indexPath) && restorePrevious(indexPath)
+
 	descIndex, err := NewDescriptionIndex(indexPath)
+	if errors.Is(err, ErrIndexCorrupt) && !readOnly {
+		if !restorePrevious(indexPath) {
+			if err := os.RemoveAll(indexPath); err != nil {
+				return nil, false, fmt.Errorf("failed to remove damaged index: %w", err)
+			}
+			_ = os.Remove(SnapshotPath(indexPath))
+		}
+		restored = true
+		descIndex, err = NewDescriptionIndex(indexPath)
+	}
 	if err != nil {
-		// Check if this is a version mismatch error
+		// Check if this is a version mismatch error (a read-only cache is rebuilt by whoever syncs it)
+		if errors.Is(err, ErrIndexVersionMismatch) && readOnly {
+			return nil, false, fmt.Errorf("%w: %w; it must be rebuilt where it is synced", ErrReadOnly, err)
+		}
 		if errors.Is(err, ErrIndexVersionMismatch) {
+			// Re-index the stored projects with the current schema
+			if migrateIndex(indexPath) == nil {
+				if descIndex, err := NewDescriptionIndex(indexPath); err == nil {
+					return descIndex, restored, nil
+				}
+			}
+
 			// Delete old index
 			if err := os.RemoveAll(indexPath); err != nil {
 				return nil, false, fmt.Errorf("failed to remove old index: %w", err)
 			}
+			_ = os.Remove(SnapshotPath(indexPath))
 
 			// Create new index with current version
 			descIndex, err = NewDescriptionIndex(indexPath)
@@ -406,12 +794,35 @@ func NewDescriptionIndexWithAutoRecreate(indexPath string) (*DescriptionIndex, b
 	}
 
 	// Successfully opened existing index
-	return descIndex, false, nil
+	return descIndex, restored, nil
 }
 
 // GetAllProjects retrieves all projects from the index
-// Returns all indexed projects (no pagination)
+// Returns all indexed projects (no pagination). Loads the snapshot written by
+// SaveSnapshot when it is current, and otherwise scans the index and refreshes the snapshot
 func (di *DescriptionIndex) GetAllProjects() ([]model.Project, error) {
+	count, err := di.Count()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document count: %w", err)
+	}
+	if projects, ok := di.loadSnapshot(count); ok {
+		return projects, nil
+	}
+
+	projects, err := di.scanAllProjects()
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh the snapshot unless this index has unsaved changes (e.g. mid-sync) or is read-only
+	if !di.snapshotStale && !di.readOnly && count > 1 {
+		_ = di.storeSnapshot(projects, count) // Best effort: the cache directory may be read-only
+	}
+	return projects, nil
+}
+
+// scanAllProjects reads all projects from the index itself (slow for big indexes)
+func (di *DescriptionIndex) scanAllProjects() ([]model.Project, error) {
 	// Use match_all query to get everything
 	query := bleve.NewMatchAllQuery()
 
@@ -431,7 +842,7 @@ func (di *DescriptionIndex) GetAllProjects() ([]model.Project, error) {
 		size = int(count)
 	}
 	searchRequest := bleve.NewSearchRequestOptions(query, size, 0, false)
-	searchRequest.Fields = []string{"ProjectPath", "ProjectName", "Description", "Starred", "Archived", "Member"}
+	searchRequest.Fields = storedFields
 
 	// Execute search
 	searchResults, err := di.index.Search(searchRequest)
@@ -447,39 +858,7 @@ func (di *DescriptionIndex) GetAllProjects() ([]model.Project, error) {
 			continue
 		}
 
-		projectPath, ok := hit.Fields["ProjectPath"].(string)
-		if !ok {
-			projectPath = ""
-		}
-		projectName, ok := hit.Fields["ProjectName"].(string)
-		if !ok {
-			projectName = ""
-		}
-		description, ok := hit.Fields["Description"].(string)
-		if !ok {
-			description = ""
-		}
-		starred, ok := hit.Fields["Starred"].(bool)
-		if !ok {
-			starred = false
-		}
-		archived, ok := hit.Fields["Archived"].(bool)
-		if !ok {
-			archived = false
-		}
-		member, ok := hit.Fields["Member"].(bool)
-		if !ok {
-			member = false
-		}
-
-		projects = append(projects, model.Project{
-			Path:        projectPath,
-			Name:        projectName,
-			Description: description,
-			Starred:     starred,
-			Archived:    archived,
-			Member:      member,
-		})
+		projects = append(projects, projectFromHit(hit))
 	}
 
 	return projects, nil
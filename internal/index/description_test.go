@@ -1,9 +1,13 @@
 package index
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/blevesearch/bleve/v2/search"
 	"github.com/igusev/glf/internal/model"
@@ -391,6 +395,51 @@ func TestDescriptionIndex_Search_FuzzyMatching(t *testing.T) {
 	}
 }
 
+func TestDescriptionIndex_Search_Fuzziness(t *testing.T) {
+	tempDir := t.TempDir()
+	di, err := NewDescriptionIndex(filepath.Join(tempDir, "test.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer di.Close()
+	defer SetFuzziness(-1)
+
+	if err := di.AddBatch([]DescriptionDocument{
+		{ProjectPath: "platform/search-service", ProjectName: "search-service"},
+		{ProjectPath: "platform/billing-service", ProjectName: "billing-service"},
+	}); err != nil {
+		t.Fatalf("AddBatch failed: %v", err)
+	}
+
+	tests := []struct {
+		fuzziness int
+		query     string
+		want      string // Expected first result ("" = none)
+	}{
+		{-1, "serach-service", "platform/search-service"}, // Swapped letters
+		{-1, "seerxh-service", "platform/search-service"}, // Two typos
+		{2, "seerxh", "platform/search-service"},
+		{1, "seerxh", ""},
+		{1, "searh", "platform/search-service"},
+		{0, "searh", ""},
+		{-1, "sx", ""}, // Short tokens stay exact with auto
+	}
+	for _, tt := range tests {
+		SetFuzziness(tt.fuzziness)
+		matches, err := di.Search(tt.query, 10)
+		if err != nil {
+			t.Fatalf("Search(%q) error = %v", tt.query, err)
+		}
+		got := ""
+		if len(matches) > 0 {
+			got = matches[0].Project.Path
+		}
+		if got != tt.want {
+			t.Errorf("fuzziness %d: Search(%q) first result = %q, want %q", tt.fuzziness, tt.query, got, tt.want)
+		}
+	}
+}
+
 func TestDescriptionIndex_Search_PrefixMatching(t *testing.T) {
 	tempDir := t.TempDir()
 	indexPath := filepath.Join(tempDir, "test.bleve")
@@ -610,6 +659,62 @@ func TestDescriptionIndex_GetAllProjects(t *testing.T) {
 	}
 }
 
+func TestDescriptionIndex_GetProject(t *testing.T) {
+	tempDir := t.TempDir()
+	indexPath := filepath.Join(tempDir, "test.bleve")
+
+	di, err := NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer di.Close()
+
+	err = di.AddBatch([]DescriptionDocument{
+		{ProjectPath: "org/api", ProjectName: "API", Description: "REST API", Starred: true, Member: true, OpenMRs: 4, OpenIssues: 12,
+			DefaultBranch: "main", Visibility: "private", LastActivityAt: time.Date(2026, 10, 15, 6, 30, 0, 0, time.UTC).Unix(), ForkedFrom: "upstream/api"},
+		{ProjectPath: "org/web", ProjectName: "Web", Description: "Frontend", Archived: true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to add batch: %v", err)
+	}
+
+	project, found, err := di.GetProject("org/api")
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if !found {
+		t.Fatal("Expected org/api to be found")
+	}
+	if project.Name != "API" || project.Description != "REST API" || !project.Starred || !project.Member || project.Archived {
+		t.Errorf("Unexpected project data: %+v", project)
+	}
+	if project.OpenMRs != 4 || project.OpenIssues != 12 {
+		t.Errorf("Expected insight counters 4/12, got %d/%d", project.OpenMRs, project.OpenIssues)
+	}
+	if project.DefaultBranch != "main" || project.Visibility != "private" || !project.LastActivityAt.Equal(time.Date(2026, 10, 15, 6, 30, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected metadata %q/%q/%v", project.DefaultBranch, project.Visibility, project.LastActivityAt)
+	}
+	if project.ForkedFrom != "upstream/api" {
+		t.Errorf("Expected a fork of upstream/api, got %q", project.ForkedFrom)
+	}
+
+	project, found, err = di.GetProject("org/web")
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if !found || !project.Archived {
+		t.Errorf("Expected archived org/web, got found=%v project=%+v", found, project)
+	}
+
+	_, found, err = di.GetProject("org/missing")
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if found {
+		t.Error("Expected org/missing not to be found")
+	}
+}
+
 func TestDescriptionIndex_Search_FieldBoosting(t *testing.T) {
 	tempDir := t.TempDir()
 	indexPath := filepath.Join(tempDir, "test.bleve")
@@ -933,3 +1038,71 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestExtractSnippet_MatchWindow(t *testing.T) {
+	long := strings.Repeat("filler words here ", 12) + "the ingress controller routes traffic " + strings.Repeat("more text ", 20)
+
+	hit := &search.DocumentMatch{
+		Fragments: map[string][]string{},
+		Fields:    map[string]interface{}{"Description": long},
+	}
+
+	snippet := extractSnippet(hit, "INGRESS")
+	if !strings.HasPrefix(snippet, "...") || !strings.HasSuffix(snippet, "...") {
+		t.Errorf("Expected snippet cut on both sides, got %q", snippet)
+	}
+	if !strings.Contains(snippet, "ingress controller") {
+		t.Errorf("Expected snippet to contain the match, got %q", snippet)
+	}
+
+	// Match within the first snippetLength characters: plain truncation
+	if got := extractSnippet(hit, "filler"); got != long[:snippetLength]+"..." {
+		t.Errorf("Expected plain truncation for an early match, got %q", got)
+	}
+
+	// Multi-byte text is cut on rune boundaries
+	cyrillic := strings.Repeat("описание ", 20) + "шлюз платежей"
+	hit.Fields["Description"] = cyrillic
+	if got := extractSnippet(hit, "шлюз"); !utf8.ValidString(got) || !strings.Contains(got, "шлюз платежей") {
+		t.Errorf("Expected valid UTF-8 snippet containing the match, got %q", got)
+	}
+}
+
+func TestDescriptionIndex_ReadOnly(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "description.bleve")
+	di, err := NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("NewDescriptionIndex() error = %v", err)
+	}
+	if err := di.Add("group/api", "api", "REST API", false, false); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := di.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+
+	di, recreated, err := NewDescriptionIndexWithAutoRecreate(indexPath)
+	if err != nil || recreated {
+		t.Fatalf("Opening a read-only index: recreated = %v, error = %v", recreated, err)
+	}
+	defer func() { _ = di.Close() }()
+	matches, err := di.Search("api", 10)
+	if err != nil || len(matches) != 1 {
+		t.Errorf("Search() = %v, %v; want the indexed project", matches, err)
+	}
+	if err := di.Add("group/web", "web", "", false, false); err == nil {
+		t.Error("Expected writing to a read-only index to fail")
+	}
+
+	// A missing index is never created
+	missing := filepath.Join(t.TempDir(), "description.bleve")
+	if _, _, err := NewDescriptionIndexWithAutoRecreate(missing); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected ErrReadOnly for a missing index, got %v", err)
+	}
+	if Exists(missing) {
+		t.Error("Expected no index to be created in a read-only cache")
+	}
+}
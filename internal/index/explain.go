@@ -0,0 +1,140 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2/search"
+)
+
+// explainMaxResults is the number of hits searched for the explained project
+const explainMaxResults = 1000
+
+// FieldScore is the part of a search score contributed by one indexed field
+type FieldScore struct {
+	Field string      // Index field (ProjectName, ProjectPath, Description)
+	Score float64     // Share of the search score
+	Terms []TermScore // Matched index terms, highest score first
+}
+
+// TermScore is the part of a search score contributed by one matched index term
+// Fuzzy and prefix queries match terms that differ from the query tokens
+type TermScore struct {
+	Term  string
+	Score float64
+}
+
+// ExplainScore breaks the search score of a project for query down by field and matched term
+// The shares add up to the score Search returns for the project
+// Returns an error if the project is not among the first explainMaxResults hits
+func (di *DescriptionIndex) ExplainScore(query, projectPath string) ([]FieldScore, error) {
+	if query == "" {
+		return nil, nil
+	}
+
+	hits, _, err := di.searchSpellings(context.Background(), query, explainMaxResults, []string{"ProjectPath"}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hit := range hits {
+		if hitPath, _ := hit.Fields["ProjectPath"].(string); hitPath != projectPath {
+			continue
+		}
+		byField := make(map[string]map[string]float64)
+		attributeScore(hit.Expl, hit.Score, byField)
+		return fieldScores(byField), nil
+	}
+	return nil, fmt.Errorf("%s does not match %q", projectPath, query)
+}
+
+// attributeScore distributes value over the term weights of an explanation tree
+// Sums and products (coord factors, boosts) pass their value down in proportion to the
+// values of their scoring children, so the leaves add up to the score of the root
+func attributeScore(expl *search.Explanation, value float64, byField map[string]map[string]float64) {
+	if expl == nil {
+		return
+	}
+	if field, term, ok := parseTermWeight(expl.Message); ok {
+		if byField[field] == nil {
+			byField[field] = make(map[string]float64)
+		}
+		byField[field][term] += value
+		return
+	}
+
+	var scoring []*search.Explanation
+	var total float64
+	for _, child := range expl.Children {
+		if child != nil && isScoringExplanation(child) {
+			scoring = append(scoring, child)
+			total += child.Value
+		}
+	}
+	for _, child := range scoring {
+		share := value / float64(len(scoring))
+		if total > 0 {
+			share = value * child.Value / total
+		}
+		attributeScore(child, share, byField)
+	}
+}
+
+// isScoringExplanation reports whether an explanation node carries matches
+// (as opposed to factors like coord, boost or queryNorm)
+func isScoringExplanation(expl *search.Explanation) bool {
+	if _, _, ok := parseTermWeight(expl.Message); ok {
+		return true
+	}
+	return len(expl.Children) > 0 && (expl.Message == "sum of:" || expl.Message == "product of:")
+}
+
+// parseTermWeight extracts the field and term from a term scorer explanation:
+// "weight(Field:term^1.000000 in doc), product of:" or "fieldWeight(Field:term in doc), ..."
+func parseTermWeight(message string) (field, term string, ok bool) {
+	rest, found := strings.CutPrefix(message, "weight(")
+	if !found {
+		rest, found = strings.CutPrefix(message, "fieldWeight(")
+	}
+	if !found {
+		return "", "", false
+	}
+	end := strings.Index(rest, " in ")
+	if end < 0 {
+		return "", "", false
+	}
+	rest = rest[:end]
+	if caret := strings.LastIndex(rest, "^"); caret >= 0 {
+		rest = rest[:caret]
+	}
+	field, term, ok = strings.Cut(rest, ":")
+	return field, term, ok && field != ""
+}
+
+// fieldScores converts per-field term scores to a list sorted by score (highest first)
+func fieldScores(byField map[string]map[string]float64) []FieldScore {
+	fields := make([]FieldScore, 0, len(byField))
+	for field, terms := range byField {
+		fs := FieldScore{Field: field}
+		for term, score := range terms {
+			fs.Score += score
+			fs.Terms = append(fs.Terms, TermScore{Term: term, Score: score})
+		}
+		sort.Slice(fs.Terms, func(i, j int) bool {
+			if fs.Terms[i].Score != fs.Terms[j].Score {
+				return fs.Terms[i].Score > fs.Terms[j].Score
+			}
+			return fs.Terms[i].Term < fs.Terms[j].Term
+		})
+		fields = append(fields, fs)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].Score != fields[j].Score {
+			return fields[i].Score > fields[j].Score
+		}
+		return fields[i].Field < fields[j].Field
+	})
+	return fields
+}
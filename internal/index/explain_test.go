@@ -0,0 +1,95 @@
+package index
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestDescriptionIndex_ExplainScore(t *testing.T) {
+	di, err := NewDescriptionIndex(filepath.Join(t.TempDir(), "test.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer di.Close()
+
+	if err := di.AddBatch([]DescriptionDocument{
+		{ProjectPath: "backend/service", ProjectName: "API Service", Description: "REST API implementation"},
+		{ProjectPath: "backend/handler", ProjectName: "Request Handler", Description: "Handles API requests"},
+		{ProjectPath: "frontend/web", ProjectName: "Web", Description: "Website"},
+	}); err != nil {
+		t.Fatalf("Failed to add batch: %v", err)
+	}
+
+	matches, err := di.Search("api", 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	scores := make(map[string]float64, len(matches))
+	for _, match := range matches {
+		scores[match.Project.Path] = match.Score
+	}
+
+	fields, err := di.ExplainScore("api", "backend/service")
+	if err != nil {
+		t.Fatalf("ExplainScore() error = %v", err)
+	}
+	if len(fields) == 0 || fields[0].Field != "ProjectName" {
+		t.Fatalf("Expected the name match to contribute most, got %+v", fields)
+	}
+	var total float64
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		seen[field.Field] = true
+		var termTotal float64
+		for _, term := range field.Terms {
+			termTotal += term.Score
+		}
+		if math.Abs(termTotal-field.Score) > 1e-9 {
+			t.Errorf("%s: term scores add up to %f, field score is %f", field.Field, termTotal, field.Score)
+		}
+		total += field.Score
+	}
+	if !seen["Description"] {
+		t.Errorf("Expected a Description contribution, got %+v", fields)
+	}
+	if math.Abs(total-scores["backend/service"]) > 1e-9 {
+		t.Errorf("Field scores add up to %f, search score is %f", total, scores["backend/service"])
+	}
+
+	// Fuzzy matches are reported with the index term they matched
+	fields, err = di.ExplainScore("handlr", "backend/handler")
+	if err != nil {
+		t.Fatalf("ExplainScore() error = %v", err)
+	}
+	if len(fields) == 0 || fields[0].Terms[0].Term != "handler" {
+		t.Errorf("Expected the fuzzy match on 'handler', got %+v", fields)
+	}
+
+	if _, err := di.ExplainScore("api", "frontend/web"); err == nil {
+		t.Error("Expected error for a project that does not match")
+	}
+	if fields, err := di.ExplainScore("", "backend/service"); err != nil || fields != nil {
+		t.Errorf("Expected no breakdown for an empty query, got %+v, %v", fields, err)
+	}
+}
+
+func TestParseTermWeight(t *testing.T) {
+	tests := []struct {
+		message string
+		field   string
+		term    string
+		ok      bool
+	}{
+		{"weight(ProjectName:api^10.000000 in backend/service), product of:", "ProjectName", "api", true},
+		{"fieldWeight(Description:rest in backend/service), as per tfidf model, product of:", "Description", "rest", true},
+		{"sum of:", "", "", false},
+		{"coord(1/4)", "", "", false},
+	}
+	for _, tt := range tests {
+		field, term, ok := parseTermWeight(tt.message)
+		if field != tt.field || term != tt.term || ok != tt.ok {
+			t.Errorf("parseTermWeight(%q) = %q, %q, %v; want %q, %q, %v", tt.message, field, term, ok, tt.field, tt.term, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Index generations: a full sync builds the next index next to the current one and swaps
+// it in only when complete, keeping the replaced index as the previous generation.
+// An interrupted sync therefore never leaves a half-written index in place, and an index
+// damaged anyway (disk full, crash during an incremental sync) falls back to the previous one
+const (
+	buildSuffix    = ".new"  // Index being built by a full sync
+	previousSuffix = ".prev" // Index replaced by the last full sync
+)
+
+// ErrIndexCorrupt indicates that an existing index could not be opened
+var ErrIndexCorrupt = errors.New("index is damaged")
+
+// NewBuild creates an empty index for a full sync next to indexPath
+// Close it and call PromoteBuild when complete, or DiscardBuild to abort
+func NewBuild(indexPath string) (*DescriptionIndex, error) {
+	if readOnly {
+		return nil, fmt.Errorf("%w: cannot rebuild %s", ErrReadOnly, indexPath)
+	}
+	DiscardBuild(indexPath) // Left over by an interrupted sync
+	return NewDescriptionIndex(indexPath + buildSuffix)
+}
+
+// DiscardBuild removes the index built for indexPath by an unfinished full sync
+func DiscardBuild(indexPath string) {
+	_ = os.RemoveAll(indexPath + buildSuffix)
+	_ = os.Remove(SnapshotPath(indexPath + buildSuffix))
+}
+
+// PromoteBuild replaces the index at indexPath with its completed (closed) build
+// The replaced index becomes the previous generation; the one before it is removed
+func PromoteBuild(indexPath string) error {
+	buildPath := indexPath + buildSuffix
+	previousPath := indexPath + previousSuffix
+	if !Exists(buildPath) {
+		return fmt.Errorf("no index build at %s", buildPath)
+	}
+
+	if err := os.RemoveAll(previousPath); err != nil {
+		return fmt.Errorf("failed to remove previous index: %w", err)
+	}
+	_ = os.Remove(SnapshotPath(previousPath))
+
+	if Exists(indexPath) {
+		if err := os.Rename(indexPath, previousPath); err != nil {
+			return fmt.Errorf("failed to keep current index: %w", err)
+		}
+		_ = os.Rename(SnapshotPath(indexPath), SnapshotPath(previousPath))
+	}
+	if err := os.Rename(buildPath, indexPath); err != nil {
+		// Put the current index back
+		if Exists(previousPath) {
+			_ = os.Rename(previousPath, indexPath)
+			_ = os.Rename(SnapshotPath(previousPath), SnapshotPath(indexPath))
+		}
+		return fmt.Errorf("failed to replace index: %w", err)
+	}
+	_ = os.Rename(SnapshotPath(buildPath), SnapshotPath(indexPath))
+	return nil
+}
+
+// Repair replaces the damaged index at indexPath with the previous generation, or removes it
+// when there is none (the next sync rebuilds it); restored reports whether the previous one was kept
+func Repair(indexPath string) (restored bool, err error) {
+	if readOnly {
+		return false, fmt.Errorf("%w: cannot repair %s", ErrReadOnly, indexPath)
+	}
+	if restorePrevious(indexPath) {
+		return true, nil
+	}
+	if err := os.RemoveAll(indexPath); err != nil {
+		return false, fmt.Errorf("failed to remove damaged index: %w", err)
+	}
+	_ = os.Remove(SnapshotPath(indexPath))
+	return false, nil
+}
+
+// restorePrevious replaces the index at indexPath (damaged or missing) with the previous generation
+// Returns false if there is no previous generation
+func restorePrevious(indexPath string) bool {
+	previousPath := indexPath + previousSuffix
+	if !Exists(previousPath) {
+		return false
+	}
+	if err := os.RemoveAll(indexPath); err != nil {
+		return false
+	}
+	_ = os.Remove(SnapshotPath(indexPath))
+	if err := os.Rename(previousPath, indexPath); err != nil {
+		return false
+	}
+	_ = os.Rename(SnapshotPath(previousPath), SnapshotPath(indexPath))
+	return true
+}
@@ -0,0 +1,211 @@
+package index
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeIndex creates a closed index holding the given project paths, with its snapshot
+func writeIndex(t *testing.T, di *DescriptionIndex, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		if err := di.Add(path, filepath.Base(path), "", false, false); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if err := di.SaveSnapshot(); err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+	if err := di.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+// indexedPaths opens the index at indexPath and returns its project paths
+func indexedPaths(t *testing.T, indexPath string) map[string]bool {
+	t.Helper()
+	di, err := NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", indexPath, err)
+	}
+	defer func() { _ = di.Close() }()
+	projects, err := di.GetAllProjects()
+	if err != nil {
+		t.Fatalf("GetAllProjects failed: %v", err)
+	}
+	got := make(map[string]bool, len(projects))
+	for _, p := range projects {
+		got[p.Path] = true
+	}
+	return got
+}
+
+// damageIndex overwrites the index metadata, as an interrupted write might leave it
+func damageIndex(t *testing.T, indexPath string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(indexPath, "index_meta.json"), []byte("{partial"), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPromoteBuild(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "description.bleve")
+	current, err := NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	writeIndex(t, current, "org/old")
+
+	build, err := NewBuild(indexPath)
+	if err != nil {
+		t.Fatalf("NewBuild failed: %v", err)
+	}
+	writeIndex(t, build, "org/api", "org/web")
+
+	// The current index is untouched until the build is promoted
+	if got := indexedPaths(t, indexPath); len(got) != 1 || !got["org/old"] {
+		t.Errorf("Expected the current index before promotion, got %v", got)
+	}
+
+	if err := PromoteBuild(indexPath); err != nil {
+		t.Fatalf("PromoteBuild failed: %v", err)
+	}
+	if got := indexedPaths(t, indexPath); len(got) != 2 || !got["org/api"] || !got["org/web"] {
+		t.Errorf("Expected the build as the current index, got %v", got)
+	}
+	if got := indexedPaths(t, indexPath+previousSuffix); len(got) != 1 || !got["org/old"] {
+		t.Errorf("Expected the replaced index as the previous generation, got %v", got)
+	}
+	if Exists(indexPath+buildSuffix) || Exists(SnapshotPath(indexPath+buildSuffix)) {
+		t.Error("Expected the build to be moved into place")
+	}
+	if !Exists(SnapshotPath(indexPath)) {
+		t.Error("Expected the build's snapshot to move with it")
+	}
+
+	if err := PromoteBuild(indexPath); err == nil {
+		t.Error("Expected an error without a build")
+	}
+}
+
+func TestDiscardBuild(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "description.bleve")
+	build, err := NewBuild(indexPath)
+	if err != nil {
+		t.Fatalf("NewBuild failed: %v", err)
+	}
+	writeIndex(t, build, "org/api")
+
+	DiscardBuild(indexPath)
+	if Exists(indexPath+buildSuffix) || Exists(SnapshotPath(indexPath+buildSuffix)) {
+		t.Error("Expected the build and its snapshot to be removed")
+	}
+	if Exists(indexPath) {
+		t.Error("Expected no current index")
+	}
+}
+
+func TestAutoRecreate_DamagedIndexFallsBack(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "description.bleve")
+	current, err := NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	writeIndex(t, current, "org/old")
+	build, err := NewBuild(indexPath)
+	if err != nil {
+		t.Fatalf("NewBuild failed: %v", err)
+	}
+	writeIndex(t, build, "org/api")
+	if err := PromoteBuild(indexPath); err != nil {
+		t.Fatalf("PromoteBuild failed: %v", err)
+	}
+
+	damageIndex(t, indexPath)
+	if _, err := NewDescriptionIndex(indexPath); !errors.Is(err, ErrIndexCorrupt) {
+		t.Fatalf("Expected ErrIndexCorrupt, got %v", err)
+	}
+
+	di, recreated, err := NewDescriptionIndexWithAutoRecreate(indexPath)
+	if err != nil {
+		t.Fatalf("Expected the previous generation to open, got %v", err)
+	}
+	if !recreated {
+		t.Error("Expected recreated so that the caller syncs the older index")
+	}
+	_ = di.Close()
+	if got := indexedPaths(t, indexPath); len(got) != 1 || !got["org/old"] {
+		t.Errorf("Expected the previous generation, got %v", got)
+	}
+	if Exists(indexPath + previousSuffix) {
+		t.Error("Expected the previous generation to be moved into place")
+	}
+}
+
+func TestAutoRecreate_DamagedIndexWithoutPrevious(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "description.bleve")
+	current, err := NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	writeIndex(t, current, "org/api")
+	damageIndex(t, indexPath)
+
+	di, recreated, err := NewDescriptionIndexWithAutoRecreate(indexPath)
+	if err != nil {
+		t.Fatalf("Expected an empty index, got %v", err)
+	}
+	defer func() { _ = di.Close() }()
+	if !recreated {
+		t.Error("Expected recreated for a damaged index")
+	}
+	if count, _ := di.Count(); count != 1 {
+		t.Errorf("Expected only the version document, got %d documents", count)
+	}
+}
+
+func TestAutoRecreate_InterruptedSwap(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "description.bleve")
+	previous, err := NewDescriptionIndex(indexPath + previousSuffix)
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	writeIndex(t, previous, "org/old")
+
+	// The sync stopped after moving the current index aside
+	di, recreated, err := NewDescriptionIndexWithAutoRecreate(indexPath)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	_ = di.Close()
+	if !recreated {
+		t.Error("Expected recreated after restoring the previous generation")
+	}
+	if got := indexedPaths(t, indexPath); !got["org/old"] {
+		t.Errorf("Expected the previous generation, got %v", got)
+	}
+}
+
+func TestAutoRecreate_DamagedReadOnly(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "description.bleve")
+	current, err := NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	writeIndex(t, current, "org/api")
+	damageIndex(t, indexPath)
+
+	SetReadOnly(true)
+	defer SetReadOnly(false)
+	if _, _, err := NewDescriptionIndexWithAutoRecreate(indexPath); !errors.Is(err, ErrIndexCorrupt) {
+		t.Errorf("Expected a read-only cache to report the damage, got %v", err)
+	}
+	if !Exists(indexPath) {
+		t.Error("Expected a read-only cache to be left alone")
+	}
+	if _, err := NewBuild(indexPath); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Expected NewBuild to fail on a read-only cache, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package index
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/igusev/glf/internal/model"
+)
+
+// migrateSuffix is appended to the index path to name the index being built by migrateIndex
+const migrateSuffix = ".migrate"
+
+// migrateBatchSize is the number of documents re-indexed per batch during a migration
+const migrateBatchSize = 1000
+
+// migrateIndex rebuilds an index with an outdated schema from its own stored fields,
+// so a schema bump does not require downloading all projects again
+// The new index is built next to the old one and replaces it only when complete.
+// Fields the old schema did not store stay empty until the next full sync, and documents
+// of path-keyed schemas are re-keyed by project ID
+func migrateIndex(indexPath string) error {
+	projects, err := readStoredProjects(indexPath)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := indexPath + migrateSuffix
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return fmt.Errorf("failed to remove stale migration: %w", err)
+	}
+	migrated, err := NewDescriptionIndex(tmpPath)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(projects); start += migrateBatchSize {
+		end := min(start+migrateBatchSize, len(projects))
+		docs := make([]DescriptionDocument, 0, end-start)
+		for _, project := range projects[start:end] {
+			docs = append(docs, NewDocument(project))
+		}
+		if err := migrated.AddBatch(docs); err != nil {
+			_ = migrated.Close()
+			_ = os.RemoveAll(tmpPath)
+			return fmt.Errorf("failed to re-index projects: %w", err)
+		}
+	}
+	if err := migrated.Close(); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return fmt.Errorf("failed to close migrated index: %w", err)
+	}
+
+	// Swap the indexes; the snapshot belongs to the old schema
+	if err := os.RemoveAll(indexPath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return fmt.Errorf("failed to remove old index: %w", err)
+	}
+	_ = os.Remove(SnapshotPath(indexPath))
+	if err := os.Rename(tmpPath, indexPath); err != nil {
+		_ = os.RemoveAll(tmpPath)
+		return fmt.Errorf("failed to replace old index: %w", err)
+	}
+	return nil
+}
+
+// readStoredProjects reads all projects stored in an index of any schema version
+// Fields the schema did not store (or stored with another type) are left empty; schemas
+// before version 15 keyed documents by project path, so documents without it are still usable
+func readStoredProjects(indexPath string) ([]model.Project, error) {
+	old, err := bleve.Open(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open old index: %w", err)
+	}
+	defer func() { _ = old.Close() }()
+
+	count, err := old.DocCount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document count: %w", err)
+	}
+	size := math.MaxInt
+	if count <= uint64(math.MaxInt) {
+		size = int(count)
+	}
+	searchRequest := bleve.NewSearchRequestOptions(bleve.NewMatchAllQuery(), size, 0, false)
+	searchRequest.Fields = storedFields
+	searchResults, err := old.Search(searchRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read old index: %w", err)
+	}
+
+	projects := make([]model.Project, 0, len(searchResults.Hits))
+	for _, hit := range searchResults.Hits {
+		if hit.ID == versionDocID {
+			continue
+		}
+		project := projectFromHit(hit)
+		if project.Path == "" && !model.IsIDKey(hit.ID) {
+			project.Path = hit.ID
+		}
+		if project.Name == "" {
+			project.Name = path.Base(project.Path)
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
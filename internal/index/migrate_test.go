@@ -0,0 +1,165 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeOldIndex creates an index with two projects and marks it with the given schema version
+// (0 removes the version document, like indexes created before versioning)
+func writeOldIndex(t *testing.T, indexPath string, version int) {
+	t.Helper()
+	di, err := NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	if err := di.AddBatch([]DescriptionDocument{
+		{ProjectID: 1, ProjectPath: "org/api", ProjectName: "api", Description: "REST gateway", Topics: []string{"go"}, Member: true},
+		{ProjectID: 2, ProjectPath: "org/web", ProjectName: "web", Starred: true, Archived: true},
+	}); err != nil {
+		t.Fatalf("AddBatch failed: %v", err)
+	}
+	if version == 0 {
+		err = di.index.Delete(versionDocID)
+	} else {
+		err = di.index.Index(versionDocID, versionDocument{Version: version})
+	}
+	if err != nil {
+		t.Fatalf("Failed to set index version: %v", err)
+	}
+	if err := di.SaveSnapshot(); err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+	if err := di.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+func TestMigrateIndex(t *testing.T) {
+	for _, version := range []int{0, IndexVersion - 1, IndexVersion + 1} {
+		indexPath := filepath.Join(t.TempDir(), "description.bleve")
+		writeOldIndex(t, indexPath, version)
+
+		di, recreated, err := NewDescriptionIndexWithAutoRecreate(indexPath)
+		if err != nil {
+			t.Fatalf("version %d: open failed: %v", version, err)
+		}
+		if recreated {
+			t.Errorf("version %d: expected the index to be migrated, not recreated", version)
+		}
+
+		projects, err := di.GetAllProjects()
+		if err != nil {
+			t.Fatalf("version %d: GetAllProjects failed: %v", version, err)
+		}
+		if len(projects) != 2 {
+			t.Fatalf("version %d: expected 2 migrated projects, got %d", version, len(projects))
+		}
+		api, found, err := di.GetProject("org/api")
+		if err != nil || !found {
+			t.Fatalf("version %d: GetProject(org/api) = %v, %v", version, found, err)
+		}
+		if api.ID != 1 || api.Description != "REST gateway" || !api.Member || len(api.Topics) != 1 {
+			t.Errorf("version %d: stored fields not migrated: %+v", version, api)
+		}
+		if web, _, _ := di.GetProject("org/web"); !web.Starred || !web.Archived {
+			t.Errorf("version %d: flags not migrated: %+v", version, web)
+		}
+		if matches, err := di.Search("gateway", 10); err != nil || len(matches) != 1 {
+			t.Errorf("version %d: migrated index not searchable: %v, %v", version, matches, err)
+		}
+		if err := di.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+
+		// The migrated index has the current version and no leftovers
+		di, err = NewDescriptionIndex(indexPath)
+		if err != nil {
+			t.Fatalf("version %d: migrated index has an outdated version: %v", version, err)
+		}
+		_ = di.Close()
+		if Exists(indexPath + migrateSuffix) {
+			t.Errorf("version %d: migration directory left behind", version)
+		}
+	}
+}
+
+func TestMigrateIndex_InterruptedMigration(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "description.bleve")
+	writeOldIndex(t, indexPath, IndexVersion-1)
+
+	// Leftover of a migration that was interrupted before the swap
+	if err := os.MkdirAll(indexPath+migrateSuffix, 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(indexPath+migrateSuffix, "index_meta.json"), []byte("partial"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	di, recreated, err := NewDescriptionIndexWithAutoRecreate(indexPath)
+	if err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	defer func() { _ = di.Close() }()
+	if recreated {
+		t.Error("Expected the index to be migrated, not recreated")
+	}
+	if projects, err := di.GetAllProjects(); err != nil || len(projects) != 2 {
+		t.Errorf("Expected 2 migrated projects, got %d (%v)", len(projects), err)
+	}
+}
+
+func TestMigrateIndex_PathKeyedDocuments(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "description.bleve")
+	di, err := NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	// Schemas before version 15 keyed documents by path
+	for _, doc := range []DescriptionDocument{
+		{ProjectID: 1, ProjectPath: "org/api", ProjectName: "api"},
+		{ProjectID: 2, ProjectPath: "org/web", ProjectName: "web"},
+	} {
+		if err := di.index.Index(doc.ProjectPath, doc); err != nil {
+			t.Fatalf("Index failed: %v", err)
+		}
+	}
+	if err := di.index.Index(versionDocID, versionDocument{Version: 14}); err != nil {
+		t.Fatalf("Failed to set index version: %v", err)
+	}
+	if err := di.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	di, _, err = NewDescriptionIndexWithAutoRecreate(indexPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer func() { _ = di.Close() }()
+
+	// A rename after the migration replaces the project's document
+	if err := di.AddBatch([]DescriptionDocument{{ProjectID: 1, ProjectPath: "platform/api", ProjectName: "api"}}); err != nil {
+		t.Fatalf("AddBatch failed: %v", err)
+	}
+	projects, err := di.GetAllProjects()
+	if err != nil {
+		t.Fatalf("GetAllProjects failed: %v", err)
+	}
+	if len(projects) != 2 {
+		t.Errorf("Expected 2 projects after the rename, got %+v", projects)
+	}
+	if _, found, _ := di.GetProject("org/api"); found {
+		t.Error("Former path still indexed after the rename")
+	}
+	if api, found, _ := di.GetProject("platform/api"); !found || api.ID != 1 {
+		t.Errorf("GetProject(platform/api) = %+v, %v", api, found)
+	}
+
+	if err := di.Delete("org/web"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, found, _ := di.GetProject("org/web"); found {
+		t.Error("Project still indexed after Delete by path")
+	}
+}
@@ -0,0 +1,109 @@
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Renames maps former project paths to current ones (old path → new path), recorded by sync
+// when a project keeps its GitLab ID under a new path. Chains are kept flat: after a → b and
+// b → c, both a and b map to c
+type Renames map[string]string
+
+// LoadRenames reads the rename map at path (see paths.RenamesPath)
+// A missing file yields an empty map
+func LoadRenames(path string) (Renames, error) {
+	renames := make(Renames)
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return renames, nil
+	}
+	if err != nil {
+		return renames, fmt.Errorf("failed to read renames: %w", err)
+	}
+	if err := json.Unmarshal(data, &renames); err != nil {
+		return make(Renames), fmt.Errorf("failed to parse renames: %w", err)
+	}
+	return renames, nil
+}
+
+// Save atomically writes the rename map to path
+func (r Renames) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode renames: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create renames: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write renames: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write renames: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to save renames: %w", err)
+	}
+	return nil
+}
+
+// Record adds a rename: oldPath and every path that led to it now map to newPath
+// A project renamed back to a former path makes that path current again
+func (r Renames) Record(oldPath, newPath string) {
+	oldPath, newPath = strings.Trim(oldPath, "/"), strings.Trim(newPath, "/")
+	if oldPath == "" || newPath == "" || oldPath == newPath {
+		return
+	}
+	delete(r, newPath)
+	for former, current := range r {
+		if current == oldPath {
+			r[former] = newPath
+		}
+	}
+	r[oldPath] = newPath
+}
+
+// Resolve returns the current path of a former project path (path itself if it was never renamed)
+func (r Renames) Resolve(path string) string {
+	if current, ok := r[strings.Trim(path, "/")]; ok {
+		return current
+	}
+	return path
+}
+
+// FormerPaths returns the former paths of a project, sorted (nil if it was never renamed)
+func (r Renames) FormerPaths(path string) []string {
+	var former []string
+	for old, current := range r {
+		if current == path {
+			former = append(former, old)
+		}
+	}
+	sort.Strings(former)
+	return former
+}
+
+// Prune drops the renames of projects that no longer exist (current paths not in live)
+// Returns the number of dropped entries
+func (r Renames) Prune(live map[string]bool) int {
+	dropped := 0
+	for old, current := range r {
+		if !live[current] {
+			delete(r, old)
+			dropped++
+		}
+	}
+	return dropped
+}
@@ -0,0 +1,65 @@
+package index
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRenames_Record(t *testing.T) {
+	r := make(Renames)
+	r.Record("a/one", "b/one")
+	r.Record("b/one", "c/one")
+
+	want := Renames{"a/one": "c/one", "b/one": "c/one"}
+	if !reflect.DeepEqual(r, want) {
+		t.Fatalf("Record() chain = %v, want %v", r, want)
+	}
+	if got := r.Resolve("a/one"); got != "c/one" {
+		t.Errorf("Resolve(a/one) = %q, want c/one", got)
+	}
+	if got := r.Resolve("x/other"); got != "x/other" {
+		t.Errorf("Resolve(x/other) = %q, want x/other", got)
+	}
+	if got := r.FormerPaths("c/one"); !reflect.DeepEqual(got, []string{"a/one", "b/one"}) {
+		t.Errorf("FormerPaths(c/one) = %v, want [a/one b/one]", got)
+	}
+
+	// Renamed back: the former path is current again
+	r.Record("c/one", "a/one")
+	want = Renames{"b/one": "a/one", "c/one": "a/one"}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("Record() back = %v, want %v", r, want)
+	}
+}
+
+func TestRenames_Prune(t *testing.T) {
+	r := Renames{"a/one": "b/one", "a/two": "b/two"}
+	if dropped := r.Prune(map[string]bool{"b/one": true}); dropped != 1 {
+		t.Errorf("Prune() dropped %d, want 1", dropped)
+	}
+	if want := (Renames{"a/one": "b/one"}); !reflect.DeepEqual(r, want) {
+		t.Errorf("Prune() left %v, want %v", r, want)
+	}
+}
+
+func TestRenames_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "renames.json")
+
+	missing, err := LoadRenames(path)
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("LoadRenames(missing) = %v, %v, want an empty map", missing, err)
+	}
+
+	r := Renames{"a/one": "b/one"}
+	if err := r.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := LoadRenames(path)
+	if err != nil {
+		t.Fatalf("LoadRenames failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, r) {
+		t.Errorf("LoadRenames() = %v, want %v", loaded, r)
+	}
+}
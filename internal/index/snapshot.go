@@ -0,0 +1,134 @@
+package index
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/igusev/glf/internal/model"
+)
+
+// snapshotVersion is the snapshot file format version
+// Increment this when model.Project changes (IndexVersion bumps invalidate snapshots too)
+const snapshotVersion = 1
+
+// snapshotSuffix is appended to the index path to name the project list snapshot
+const snapshotSuffix = ".snapshot"
+
+// projectSnapshot is the on-disk copy of all indexed projects
+// It is valid only while the index still has DocCount documents
+type projectSnapshot struct {
+	Version      int
+	IndexVersion int
+	DocCount     uint64
+	Projects     []model.Project
+}
+
+// SnapshotPath returns the project list snapshot location for an index path
+func SnapshotPath(indexPath string) string {
+	return indexPath + snapshotSuffix
+}
+
+// SaveSnapshot writes the current project list to the snapshot file, so the next
+// GetAllProjects (also in another process) loads it without scanning the index
+// Called after a sync; the write is atomic (temp file + rename)
+func (di *DescriptionIndex) SaveSnapshot() error {
+	projects, err := di.scanAllProjects()
+	if err != nil {
+		return err
+	}
+	count, err := di.Count()
+	if err != nil {
+		return fmt.Errorf("failed to get document count: %w", err)
+	}
+	if err := di.storeSnapshot(projects, count); err != nil {
+		return err
+	}
+
+	di.snapshotStale = false
+	return nil
+}
+
+// storeSnapshot writes projects as the snapshot of an index with count documents
+func (di *DescriptionIndex) storeSnapshot(projects []model.Project, count uint64) error {
+	return writeSnapshot(SnapshotPath(di.path), projectSnapshot{
+		Version:      snapshotVersion,
+		IndexVersion: IndexVersion,
+		DocCount:     count,
+		Projects:     projects,
+	})
+}
+
+// loadSnapshot returns the snapshot's projects if it matches the index (count documents)
+func (di *DescriptionIndex) loadSnapshot(count uint64) ([]model.Project, bool) {
+	if di.snapshotStale {
+		return nil, false
+	}
+
+	snapshot, ok := readSnapshot(SnapshotPath(di.path))
+	if !ok || snapshot.DocCount != count {
+		return nil, false
+	}
+	return snapshot.Projects, true
+}
+
+// ReadSnapshot returns the projects of the snapshot saved next to the index at indexPath
+// without opening the index (which another process may hold open)
+// The snapshot may miss changes made since the last sync; false if there is none
+func ReadSnapshot(indexPath string) ([]model.Project, bool) {
+	snapshot, ok := readSnapshot(SnapshotPath(indexPath))
+	return snapshot.Projects, ok
+}
+
+// readSnapshot reads the snapshot file at path (false if missing, unreadable or outdated)
+func readSnapshot(path string) (projectSnapshot, bool) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return projectSnapshot{}, false
+	}
+	defer func() { _ = file.Close() }()
+
+	var snapshot projectSnapshot
+	if err := gob.NewDecoder(file).Decode(&snapshot); err != nil {
+		return projectSnapshot{}, false
+	}
+	if snapshot.Version != snapshotVersion || snapshot.IndexVersion != IndexVersion {
+		return projectSnapshot{}, false
+	}
+	return snapshot, true
+}
+
+// invalidateSnapshot removes the snapshot before the index is modified
+// The file is removed once; SaveSnapshot writes a fresh one
+func (di *DescriptionIndex) invalidateSnapshot() {
+	if di.snapshotStale {
+		return
+	}
+	di.snapshotStale = true
+	_ = os.Remove(SnapshotPath(di.path)) // A leftover snapshot still fails the document count check
+}
+
+// writeSnapshot atomically writes a snapshot to path
+func writeSnapshot(path string, snapshot projectSnapshot) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if err := gob.NewEncoder(tmp).Encode(snapshot); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/igusev/glf/internal/model"
+)
+
+func TestSnapshot(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "test.bleve")
+
+	di, err := NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	if err := di.AddBatch([]DescriptionDocument{
+		{ProjectID: 1, ProjectPath: "org/p1", ProjectName: "p1", Topics: []string{"go"}},
+		{ProjectID: 2, ProjectPath: "org/p2", ProjectName: "p2", Starred: true},
+	}); err != nil {
+		t.Fatalf("Failed to add batch: %v", err)
+	}
+	if err := di.SaveSnapshot(); err != nil {
+		t.Fatalf("SaveSnapshot() error = %v", err)
+	}
+	di.Close()
+
+	di, err = NewDescriptionIndex(indexPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen index: %v", err)
+	}
+	defer di.Close()
+
+	projects, err := di.GetAllProjects()
+	if err != nil {
+		t.Fatalf("GetAllProjects() error = %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 projects from snapshot, got %d", len(projects))
+	}
+
+	// A current snapshot is used without scanning the index
+	count, _ := di.Count()
+	marker := []model.Project{{Path: "from/snapshot"}}
+	if err := di.storeSnapshot(marker, count); err != nil {
+		t.Fatalf("storeSnapshot() error = %v", err)
+	}
+	if projects, _ := di.GetAllProjects(); len(projects) != 1 || projects[0].Path != "from/snapshot" {
+		t.Errorf("Expected projects from the snapshot, got %v", projects)
+	}
+
+	// A snapshot for another document count is ignored
+	if err := di.storeSnapshot(marker, count+1); err != nil {
+		t.Fatalf("storeSnapshot() error = %v", err)
+	}
+	if projects, _ := di.GetAllProjects(); len(projects) != 2 {
+		t.Errorf("Expected stale snapshot to be ignored, got %v", projects)
+	}
+
+	// Modifying the index removes the snapshot, and scans don't recreate it until SaveSnapshot
+	if err := di.storeSnapshot(marker, count); err != nil {
+		t.Fatalf("storeSnapshot() error = %v", err)
+	}
+	if err := di.Delete("org/p1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(SnapshotPath(indexPath)); !os.IsNotExist(err) {
+		t.Errorf("Expected snapshot to be removed on change, got %v", err)
+	}
+	if projects, _ := di.GetAllProjects(); len(projects) != 1 || projects[0].Path != "org/p2" {
+		t.Errorf("Expected only org/p2 after delete, got %v", projects)
+	}
+	if _, err := os.Stat(SnapshotPath(indexPath)); !os.IsNotExist(err) {
+		t.Error("Expected no snapshot while the index has unsaved changes")
+	}
+}
@@ -0,0 +1,104 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/igusev/glf/internal/translit"
+)
+
+// spellingWeight scales the scores of matches found through another spelling of the query
+// (the other keyboard layout or a transliteration), so matches of the query as typed win ties
+const spellingWeight = 0.8
+
+// formerPathWeight scales the scores of matches on the former path of a renamed project,
+// so a project currently at the path wins over one that used to be there
+const formerPathWeight = 0.9
+
+// weightedQuery is one of the searches run for a query, with the factor applied to its scores
+type weightedQuery struct {
+	query  query.Query
+	weight float64
+}
+
+// searchQueries returns the searches run for queryText, and its lowercased tokens
+// Besides the query as typed, indexes with Cyrillic text are searched for the Latin spelling
+// of their documents ("proekt" → "Проект") and for the query typed on the other keyboard layout
+// ("ghjtrn" → "проект"); Cyrillic queries are also retyped and transliterated ("фзш" → "api")
+// Each spelling is a separate search: a disjunction would scale every score by its share of
+// matching clauses, and most of them never match
+func (di *DescriptionIndex) searchQueries(queryText string) ([]weightedQuery, []string) {
+	mainQuery, tokens := buildSearchQuery(queryText)
+	queries := []weightedQuery{{query: mainQuery, weight: 1}}
+
+	// Renamed projects are still found by their former paths
+	if di.hasField("FormerPaths") {
+		queries = append(queries, weightedQuery{query: buildFieldQuery(tokens, "FormerPaths", 5.0), weight: formerPathWeight})
+	}
+
+	cyrillicIndex := di.hasField("Transliteration")
+	if cyrillicIndex {
+		queries = append(queries, weightedQuery{query: buildFieldQuery(tokens, "Transliteration", 1.0), weight: spellingWeight})
+	}
+	if cyrillicIndex || translit.HasCyrillic(queryText) {
+		for _, variant := range translit.Variants(queryText) {
+			variantQuery, _ := buildSearchQuery(variant)
+			queries = append(queries, weightedQuery{query: variantQuery, weight: spellingWeight})
+		}
+	}
+	return queries, tokens
+}
+
+// hasField reports whether any indexed document has terms in field
+// (Transliteration: Cyrillic text, FormerPaths: a renamed project)
+func (di *DescriptionIndex) hasField(field string) bool {
+	dict, err := di.index.FieldDict(field)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = dict.Close() }()
+	entry, err := dict.Next()
+	return err == nil && entry != nil
+}
+
+// searchSpellings runs the searches for queryText and merges their hits: each document keeps
+// its best weighted score, and the maxResults best documents are returned, highest score first
+func (di *DescriptionIndex) searchSpellings(ctx context.Context, queryText string, maxResults int, fields []string, explain bool) (search.DocumentMatchCollection, []string, error) {
+	queries, tokens := di.searchQueries(queryText)
+
+	var hits search.DocumentMatchCollection
+	best := make(map[string]int)
+	for _, wq := range queries {
+		searchRequest := bleve.NewSearchRequestOptions(wq.query, maxResults, 0, explain)
+		searchRequest.Fields = fields
+		searchResults, err := di.index.SearchInContext(ctx, searchRequest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("search failed: %w", err)
+		}
+		for _, hit := range searchResults.Hits {
+			hit.Score *= wq.weight
+			if i, seen := best[hit.ID]; seen {
+				if hit.Score > hits[i].Score {
+					hits[i] = hit
+				}
+				continue
+			}
+			best[hit.ID] = len(hits)
+			hits = append(hits, hit)
+		}
+	}
+
+	if len(queries) > 1 {
+		sort.SliceStable(hits, func(i, j int) bool {
+			return hits[i].Score > hits[j].Score
+		})
+		if len(hits) > maxResults {
+			hits = hits[:maxResults]
+		}
+	}
+	return hits, tokens, nil
+}
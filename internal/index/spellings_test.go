@@ -0,0 +1,94 @@
+package index
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSearch_Spellings(t *testing.T) {
+	di, err := NewDescriptionIndex(filepath.Join(t.TempDir(), "test.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer di.Close()
+
+	if err := di.AddBatch([]DescriptionDocument{
+		{ProjectPath: "platform/api", ProjectName: "api", Description: "Public REST API"},
+		{ProjectPath: "team/proekt", ProjectName: "Проект", Description: "Учёт заказов"},
+		{ProjectPath: "team/billing", ProjectName: "billing", Description: "Платежный шлюз"},
+	}); err != nil {
+		t.Fatalf("AddBatch failed: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"фзш", "platform/api"},        // "api" typed on the Russian layout
+		{"proekt", "team/proekt"},      // Transliterated name
+		{"ghjtrn", "team/proekt"},      // "проект" typed on the US layout
+		{"platezhnyy", "team/billing"}, // Transliterated description
+		{"проект", "team/proekt"},
+		{"api", "platform/api"},
+	}
+	for _, tt := range tests {
+		matches, err := di.Search(tt.query, 10)
+		if err != nil {
+			t.Fatalf("Search(%q) error = %v", tt.query, err)
+		}
+		if len(matches) == 0 || matches[0].Project.Path != tt.want {
+			t.Errorf("Search(%q) = %+v, want %s first", tt.query, matches, tt.want)
+		}
+	}
+
+	// Matches through another spelling can be explained too
+	fields, err := di.ExplainScore("фзш", "platform/api")
+	if err != nil || len(fields) == 0 {
+		t.Errorf("ExplainScore(фзш) = %v, %v", fields, err)
+	}
+}
+
+func TestSearch_SpellingsLatinIndex(t *testing.T) {
+	di, err := NewDescriptionIndex(filepath.Join(t.TempDir(), "test.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer di.Close()
+
+	if err := di.AddBatch([]DescriptionDocument{{ProjectPath: "platform/api", ProjectName: "api"}}); err != nil {
+		t.Fatalf("AddBatch failed: %v", err)
+	}
+	if di.hasField("Transliteration") {
+		t.Error("Expected no transliterations in a Latin-only index")
+	}
+	// Latin queries are searched as typed only, so their scores are unchanged
+	if queries, _ := di.searchQueries("api"); len(queries) != 1 {
+		t.Errorf("Expected a single search for a Latin query, got %d", len(queries))
+	}
+	if matches, err := di.Search("фзш", 10); err != nil || len(matches) != 1 {
+		t.Errorf("Expected фзш to find platform/api, got %+v (%v)", matches, err)
+	}
+}
+
+func TestSearch_SpellingSnippet(t *testing.T) {
+	di, err := NewDescriptionIndex(filepath.Join(t.TempDir(), "test.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer di.Close()
+
+	description := strings.Repeat("описание сервиса ", 12) + "маршрутизатор платежей"
+	if err := di.AddBatch([]DescriptionDocument{{ProjectPath: "team/gateway", ProjectName: "gateway", Description: description}}); err != nil {
+		t.Fatalf("AddBatch failed: %v", err)
+	}
+
+	// "маршрутизатор" typed on the US layout: the snippet is cut around the Cyrillic match
+	matches, err := di.Search("vfhihenbpfnjh", 10)
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("Search(vfhihenbpfnjh) = %+v, %v; want team/gateway", matches, err)
+	}
+	if snippet := matches[0].Snippet; !strings.HasPrefix(snippet, "...") || !strings.Contains(snippet, "маршрутизатор") {
+		t.Errorf("Expected the snippet around маршрутизатор, got %q", snippet)
+	}
+}
@@ -1,15 +1,92 @@
 package index
 
-import "github.com/igusev/glf/internal/model"
+import (
+	"time"
+
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/translit"
+)
 
 // DescriptionDocument represents an indexed project description
 type DescriptionDocument struct {
-	ProjectPath string // e.g., "backend/api/auth"
-	ProjectName string // e.g., "login-service"
-	Description string // Project description
-	Starred     bool   // Whether the project is starred by the user
-	Archived    bool   // Whether the project is archived
-	Member      bool   // Whether the user is a member of this project
+	ProjectID   int64    // GitLab project ID (the document key; 0 if unknown)
+	ProjectPath string   // e.g., "backend/api/auth"
+	ProjectName string   // e.g., "login-service"
+	Description string   // Project description
+	Starred     bool     // Whether the project is starred by the user
+	Archived    bool     // Whether the project is archived
+	Topics      []string // Project topics
+	Member      bool     // Whether the user is a member of this project
+	OpenMRs     int      // Number of open merge requests (insights)
+	OpenIssues  int      // Number of open issues (insights)
+
+	DefaultBranch  string // Default branch
+	Visibility     string // private, internal or public
+	LastActivityAt int64  // Last activity as Unix seconds (0 = unknown)
+	ForkedFrom     string // Path of the upstream project (empty if not a fork)
+
+	FormerPaths []string // Paths before renames and transfers (searched as aliases)
+
+	Transliteration string // Latin spelling of Cyrillic path, name and description (set when indexing)
+	DescriptionBody string // Stored form of Description, zstd-compressed when that is smaller (set when indexing)
+}
+
+// key returns the document ID of d: the project's key (see model.Project.Key), so a renamed
+// or transferred project replaces its document instead of adding a second one
+func (d DescriptionDocument) key() string {
+	return model.ProjectKey(d.ProjectID, d.ProjectPath)
+}
+
+// transliteration returns the Latin spelling of a document's Cyrillic text ("" if there is none)
+func (d DescriptionDocument) transliteration() string {
+	text := d.ProjectPath + " " + d.ProjectName + " " + d.Description
+	if !translit.HasCyrillic(text) {
+		return ""
+	}
+	return translit.ToLatin(text)
+}
+
+// NewDocument builds the index document for a project
+func NewDocument(p model.Project) DescriptionDocument {
+	return DescriptionDocument{
+		ProjectID:   p.ID,
+		ProjectPath: p.Path,
+		ProjectName: p.Name,
+		Description: p.Description,
+		Starred:     p.Starred,
+		Archived:    p.Archived,
+		Topics:      p.Topics,
+		Member:      p.Member,
+		OpenMRs:     p.OpenMRs,
+		OpenIssues:  p.OpenIssues,
+
+		DefaultBranch:  p.DefaultBranch,
+		Visibility:     p.Visibility,
+		LastActivityAt: unixSeconds(p.LastActivityAt),
+		ForkedFrom:     p.ForkedFrom,
+
+		FormerPaths: p.FormerPaths,
+	}
+}
+
+// unixSeconds converts a time to Unix seconds (0 for the zero time)
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// NewGroupDocument builds the index document for a group (groups live in their own index)
+// Groups are never hidden, so they are indexed as member documents. They are keyed by path:
+// group IDs would collide with project IDs in the history
+func NewGroupDocument(g model.Group) DescriptionDocument {
+	return DescriptionDocument{
+		ProjectPath: g.Path,
+		ProjectName: g.Name,
+		Description: g.Description,
+		Member:      true,
+	}
 }
 
 // DescriptionMatch represents a search result from description index
@@ -31,11 +108,19 @@ const (
 
 // CombinedMatch represents a unified search result with score breakdown
 type CombinedMatch struct {
-	Project      model.Project
-	Snippet      string      // Description snippet if found there
-	SearchScore  float64     // Bleve relevance score
-	TotalScore   float64     // Combined score (SearchScore + HistoryScore + StarredBonus)
-	HistoryScore int         // History boost (with exponential decay)
-	StarredBonus int         // Bonus for starred projects (+50 for starred)
-	Source       MatchSource // Bitflags: can be MatchSourceName | MatchSourceDescription
+	Project         model.Project
+	Snippet         string      // Description snippet if found there
+	SearchScore     float64     // Bleve relevance score
+	PathBonus       float64     // Bonus for query tokens starting distinct path segments
+	TotalScore      float64     // Combined score (SearchScore + PathBonus + HistoryScore + StarredBonus - ArchivedPenalty - ForkPenalty)
+	HistoryScore    int         // History boost (with exponential decay)
+	StarredBonus    int         // Bonus for starred projects (+50 for starred)
+	ArchivedPenalty float64     // Subtracted for archived projects (scoring.archived_penalty)
+	ForkPenalty     float64     // Subtracted for forks (scoring.fork_penalty)
+	Source          MatchSource // Bitflags: can be MatchSourceName | MatchSourceDescription
+	Remote          bool        // Found by a live GitLab search (not in the local index yet)
+	Pinned          bool        // Project is pinned (kept at the top of results)
+	Subgroup        bool        // Subgroup row of a "group/" listing (Project holds the subgroup path)
+
+	Forks []CombinedMatch // Forks collapsed under this result, in rank order (search.collapse_forks)
 }
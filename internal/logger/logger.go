@@ -20,9 +20,14 @@ func IsVerbose() bool {
 
 // Debug prints debug messages only when verbose mode is enabled
 func Debug(format string, args ...interface{}) {
-	if verbose {
-		_, _ = fmt.Fprintf(os.Stderr, "[DEBUG] "+format+"\n", args...)
+	if !verbose {
+		return
 	}
+	if traceID != "" {
+		_, _ = fmt.Fprintf(os.Stderr, "[DEBUG] [%s] %s\n", traceID, fmt.Sprintf(format, args...))
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "[DEBUG] "+format+"\n", args...)
 }
 
 // Info prints informational messages
@@ -158,3 +158,42 @@ func TestMultipleArgs(t *testing.T) {
 		t.Errorf("Multiple args not formatted correctly: got %q, want substring %q", output, expected)
 	}
 }
+
+func TestDebug_TraceID(t *testing.T) {
+	old := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	SetVerbose(true)
+	SetTraceID("abc123")
+	Debug("100%% done")
+	SetTraceID("")
+	SetVerbose(false)
+
+	w.Close()
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	os.Stderr = old
+
+	if got := buf.String(); got != "[DEBUG] [abc123] 100% done\n" {
+		t.Errorf("Unexpected debug output: %q", got)
+	}
+}
+
+func TestInitTraceID(t *testing.T) {
+	defer SetTraceID("")
+
+	t.Setenv(TraceEnvVar, "from-env")
+	if id := InitTraceID(); id != "from-env" || TraceID() != "from-env" {
+		t.Errorf("Expected trace ID from %s, got %q", TraceEnvVar, id)
+	}
+
+	t.Setenv(TraceEnvVar, "")
+	id := InitTraceID()
+	if len(id) != 16 {
+		t.Errorf("Expected generated 16-char trace ID, got %q", id)
+	}
+	if other := NewTraceID(); other == id {
+		t.Error("Expected distinct trace IDs")
+	}
+}
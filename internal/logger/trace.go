@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+)
+
+// TraceEnvVar lets a caller (script, wrapper, support engineer) supply the trace ID to propagate
+const TraceEnvVar = "GLF_TRACE_ID"
+
+var traceID string
+
+// InitTraceID sets the trace ID for this invocation from GLF_TRACE_ID, or generates a new one
+func InitTraceID() string {
+	if id := os.Getenv(TraceEnvVar); id != "" {
+		traceID = id
+	} else {
+		traceID = NewTraceID()
+	}
+	return traceID
+}
+
+// NewTraceID returns a random 16-character hex ID
+func NewTraceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "0000000000000000"
+	}
+	return hex.EncodeToString(b)
+}
+
+// SetTraceID sets the trace ID included in debug logs (empty disables it)
+func SetTraceID(id string) {
+	traceID = id
+}
+
+// TraceID returns the trace ID of this invocation (empty if not initialized)
+func TraceID() string {
+	return traceID
+}
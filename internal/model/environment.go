@@ -0,0 +1,9 @@
+package model
+
+// Environment represents a deployment environment of a project (fetched live, never cached)
+type Environment struct {
+	ID          int64  // Environment ID
+	Name        string // Environment name (e.g., "production", "review/feature-x")
+	State       string // "available", "stopping" or "stopped"
+	ExternalURL string // URL of the deployed service (empty if the environment sets none)
+}
@@ -0,0 +1,9 @@
+package model
+
+// Group represents a GitLab group or subgroup
+type Group struct {
+	ID          int64  // GitLab group ID
+	Path        string // Full path including parent groups (e.g., "company/platform")
+	Name        string // Group name (e.g., "Platform")
+	Description string // Group description (may be empty)
+}
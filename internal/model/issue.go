@@ -0,0 +1,25 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Issue represents an open issue of a project (fetched live, never cached)
+type Issue struct {
+	IID    int64    // Project-scoped issue number (e.g., 42 for "#42")
+	Title  string   // Issue title
+	Author string   // Author username
+	Labels []string // Issue labels
+	WebURL string   // Issue URL in the GitLab web UI
+}
+
+// SearchableString returns a combined string for filtering: "#iid title labels author"
+func (i Issue) SearchableString() string {
+	return fmt.Sprintf("#%d %s %s %s", i.IID, i.Title, strings.Join(i.Labels, " "), i.Author)
+}
+
+// DisplayString returns the issue in GitLab notation: "#42 Fix login redirect"
+func (i Issue) DisplayString() string {
+	return fmt.Sprintf("#%d %s", i.IID, i.Title)
+}
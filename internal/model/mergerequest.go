@@ -0,0 +1,30 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MergeRequest represents an open merge request assigned to or authored by the user
+type MergeRequest struct {
+	ID          int64     // Instance-wide merge request ID
+	IID         int64     // Project-scoped merge request number (e.g., 12 for "!12")
+	ProjectPath string    // PathWithNamespace of the target project
+	Title       string    // Merge request title
+	Author      string    // Author username
+	Labels      []string  // Merge request labels
+	Draft       bool      // Whether the merge request is a draft
+	WebURL      string    // Merge request URL in the GitLab web UI
+	UpdatedAt   time.Time // Last update time
+}
+
+// SearchableString returns a combined string for filtering: "path!iid title labels author"
+func (mr MergeRequest) SearchableString() string {
+	return fmt.Sprintf("%s!%d %s %s %s", mr.ProjectPath, mr.IID, mr.Title, strings.Join(mr.Labels, " "), mr.Author)
+}
+
+// DisplayString returns the merge request in GitLab notation: "group/project!12 Add retries"
+func (mr MergeRequest) DisplayString() string {
+	return fmt.Sprintf("%s!%d %s", mr.ProjectPath, mr.IID, mr.Title)
+}
@@ -1,16 +1,57 @@
 // Package model defines core data structures for GitLab projects
 package model
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// keyPrefix starts the keys of projects known by ID (':' never occurs in GitLab paths)
+const keyPrefix = "id:"
 
 // Project represents a GitLab project with its path, name and description
 type Project struct {
-	Path        string // PathWithNamespace (e.g., "company/group/subgroup/project-name")
-	Name        string // Project name (e.g., "project-name")
-	Description string // Project description (may be empty)
-	Starred     bool   // Whether the project is starred by the user
-	Archived    bool   // Whether the project is archived
-	Member      bool   // Whether the user is a member of this project
+	ID          int64    // GitLab project ID (stable across renames and transfers; 0 if unknown)
+	Path        string   // PathWithNamespace (e.g., "company/group/subgroup/project-name")
+	Name        string   // Project name (e.g., "project-name")
+	Description string   // Project description (may be empty)
+	Starred     bool     // Whether the project is starred by the user
+	Archived    bool     // Whether the project is archived
+	Topics      []string // Project topics (e.g., "golang", "kubernetes")
+	Member      bool     // Whether the user is a member of this project
+	OpenMRs     int      // Number of open merge requests (0 unless insights are enabled)
+	OpenIssues  int      // Number of open issues (0 unless insights are enabled)
+
+	DefaultBranch  string    // Default branch (e.g., "main"; empty for empty repositories or if unknown)
+	Visibility     string    // "private", "internal" or "public" (empty if unknown)
+	LastActivityAt time.Time // Last activity on the project (zero if unknown)
+	ForkedFrom     string    // Path of the project this one was forked from (empty if not a fork)
+	FormerPaths    []string  // Paths the project had before renames or transfers seen by sync
+}
+
+// IsFork reports whether the project is a fork of another project
+func (p Project) IsFork() bool {
+	return p.ForkedFrom != ""
+}
+
+// Key identifies the project in the index and the history: "id:<ID>", which survives renames
+// and transfers, or the path if the ID is unknown
+func (p Project) Key() string {
+	return ProjectKey(p.ID, p.Path)
+}
+
+// ProjectKey returns the key of the project with the given ID and path (see Project.Key)
+func ProjectKey(id int64, path string) string {
+	if id == 0 {
+		return path
+	}
+	return keyPrefix + strconv.FormatInt(id, 10)
+}
+
+// IsIDKey reports whether key is the ID key of a project rather than a path
+func IsIDKey(key string) bool {
+	return strings.HasPrefix(key, keyPrefix)
 }
 
 // SearchableString returns a combined string for fuzzy searching
@@ -35,3 +76,50 @@ func (p Project) DisplayString() string {
 	// Fallback: just return name if single part (no namespace)
 	return p.Name
 }
+
+// Activity is a coarse bucket of a project's last activity (see Project.Activity)
+type Activity int
+
+// Activity buckets
+const (
+	ActivityUnknown Activity = iota // Last activity not synced
+	ActivityActive                  // Active within ActiveWithin
+	ActivityQuiet                   // Active within QuietWithin
+	ActivityStale                   // No activity for longer than QuietWithin
+)
+
+// Activity bucket boundaries
+const (
+	ActiveWithin = 30 * 24 * time.Hour  // A project active in the last 30 days is active
+	QuietWithin  = 365 * 24 * time.Hour // A project without activity for a year is stale
+)
+
+// String returns the bucket name used by is: filters ("active", "quiet", "stale"; empty if unknown)
+func (a Activity) String() string {
+	switch a {
+	case ActivityActive:
+		return "active"
+	case ActivityQuiet:
+		return "quiet"
+	case ActivityStale:
+		return "stale"
+	default:
+		return ""
+	}
+}
+
+// Activity returns the project's activity bucket at now, from the last activity recorded by sync
+func (p Project) Activity(now time.Time) Activity {
+	if p.LastActivityAt.IsZero() {
+		return ActivityUnknown
+	}
+	idle := now.Sub(p.LastActivityAt)
+	switch {
+	case idle <= ActiveWithin:
+		return ActivityActive
+	case idle <= QuietWithin:
+		return ActivityQuiet
+	default:
+		return ActivityStale
+	}
+}
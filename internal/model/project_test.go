@@ -1,6 +1,9 @@
 package model
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestProject_SearchableString(t *testing.T) {
 	tests := []struct {
@@ -204,3 +207,50 @@ func TestProject_DisplayString_Consistency(t *testing.T) {
 		t.Errorf("DisplayString() not consistent: first=%q, second=%q", result1, result2)
 	}
 }
+
+func TestProject_Activity(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		lastActivity time.Time
+		expected     Activity
+	}{
+		{"unknown", time.Time{}, ActivityUnknown},
+		{"today", now.Add(-time.Hour), ActivityActive},
+		{"30 days", now.Add(-ActiveWithin), ActivityActive},
+		{"two months", now.AddDate(0, -2, 0), ActivityQuiet},
+		{"one year", now.Add(-QuietWithin), ActivityQuiet},
+		{"two years", now.AddDate(-2, 0, 0), ActivityStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Project{Path: "group/api", LastActivityAt: tt.lastActivity}
+			if got := p.Activity(now); got != tt.expected {
+				t.Errorf("Activity() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+	if ActivityUnknown.String() != "" || ActivityStale.String() != "stale" {
+		t.Error("Unexpected activity names")
+	}
+}
+
+func TestProject_Key(t *testing.T) {
+	tests := []struct {
+		project Project
+		want    string
+	}{
+		{Project{ID: 42, Path: "group/project"}, "id:42"},
+		{Project{ID: 42, Path: "other/renamed"}, "id:42"},
+		{Project{Path: "group/project"}, "group/project"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.project.Key(); got != tt.want {
+			t.Errorf("Key() of %+v = %q, want %q", tt.project, got, tt.want)
+		}
+		if IsIDKey(tt.project.Key()) != (tt.project.ID != 0) {
+			t.Errorf("IsIDKey(%q) = %v", tt.project.Key(), !(tt.project.ID != 0))
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+import "fmt"
+
+// User represents an active user of the GitLab instance (--users)
+type User struct {
+	ID       int64  // GitLab user ID
+	Username string // Login name (e.g., "jdoe"), also the profile path
+	Name     string // Display name (e.g., "Jane Doe")
+	WebURL   string // Profile URL in the GitLab web UI
+}
+
+// SearchableString returns a combined string for filtering: "username name"
+func (u User) SearchableString() string {
+	return u.Username + " " + u.Name
+}
+
+// DisplayString returns the user as shown in lists: "@jdoe Jane Doe"
+func (u User) DisplayString() string {
+	return fmt.Sprintf("@%s %s", u.Username, u.Name)
+}
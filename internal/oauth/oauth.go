@@ -0,0 +1,261 @@
+// Package oauth implements the GitLab OAuth 2.0 device authorization grant (glf --login)
+// and refreshes the resulting access tokens
+//
+// The flow needs a non-confidential OAuth application on the GitLab instance (GitLab 17.2+);
+// its application ID is the client ID
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultScope is the scope glf requests: read access to the API is all it needs
+const DefaultScope = "read_api"
+
+// deviceGrantType is the grant type of the device access token request (RFC 8628)
+const deviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// refreshMargin refreshes access tokens slightly before they expire
+const refreshMargin = time.Minute
+
+// defaultInterval is the polling interval when the server does not send one (RFC 8628 section 3.2)
+const defaultInterval = 5 * time.Second
+
+// Errors returned while waiting for the user to authorize the device
+var (
+	ErrAccessDenied = errors.New("authorization was denied")
+	ErrExpired      = errors.New("device code expired before authorization")
+)
+
+// DeviceCode is the response of the device authorization request
+type DeviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`                 // Code the user enters on the verification page
+	VerificationURI         string `json:"verification_uri"`          // Page where the user enters the code
+	VerificationURIComplete string `json:"verification_uri_complete"` // Verification page with the code filled in (optional)
+	ExpiresIn               int    `json:"expires_in"`                // Seconds until the device code expires
+	Interval                int    `json:"interval"`                  // Minimum seconds between token polls
+}
+
+// Credentials are the tokens issued to glf, stored as JSON by the token backend
+type Credentials struct {
+	ClientID     string    `json:"client_id"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"` // Zero when the token does not expire
+}
+
+// Expired reports whether the access token has expired or is about to
+func (c *Credentials) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && now.Add(refreshMargin).After(c.ExpiresAt)
+}
+
+// Encode returns the credentials as the JSON document kept by the token backend
+func (c *Credentials) Encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode OAuth credentials: %w", err)
+	}
+	return string(data), nil
+}
+
+// Decode parses a secret read from the token backend
+// ok is false when the secret is not OAuth credentials (e.g., a personal access token)
+func Decode(secret string) (creds *Credentials, ok bool) {
+	secret = strings.TrimSpace(secret)
+	if !strings.HasPrefix(secret, "{") {
+		return nil, false
+	}
+	var c Credentials
+	if err := json.Unmarshal([]byte(secret), &c); err != nil || c.AccessToken == "" {
+		return nil, false
+	}
+	return &c, true
+}
+
+// Client talks to the OAuth endpoints of a GitLab instance
+type Client struct {
+	BaseURL    string                                           // GitLab instance URL
+	ClientID   string                                           // OAuth application ID
+	HTTPClient *http.Client                                     // HTTP client for the token requests
+	Now        func() time.Time                                 // Clock (time.Now unless testing)
+	Sleep      func(ctx context.Context, d time.Duration) error // Wait between polls (overridable in tests)
+}
+
+// NewClient creates an OAuth client for the instance at baseURL
+func NewClient(baseURL, clientID string, timeout time.Duration) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		ClientID:   clientID,
+		HTTPClient: &http.Client{Timeout: timeout},
+		Now:        time.Now,
+		Sleep:      sleep,
+	}
+}
+
+// tokenResponse is the body of a token endpoint response, successful or not
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// RequestDeviceCode starts the device flow; show the user code and verification URI to the user
+func (c *Client) RequestDeviceCode(ctx context.Context, scope string) (*DeviceCode, error) {
+	form := url.Values{"client_id": {c.ClientID}, "scope": {scope}}
+	var code DeviceCode
+	var failure tokenResponse
+	status, err := c.post(ctx, "/oauth/authorize_device", form, &code, &failure)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %w", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("device authorization request failed: %s", describe(status, failure))
+	}
+	if code.DeviceCode == "" || code.UserCode == "" || code.VerificationURI == "" {
+		return nil, errors.New("device authorization request failed: incomplete response")
+	}
+	return &code, nil
+}
+
+// PollToken waits until the user authorizes the device and returns the issued credentials
+// Polls honor the server's interval and slow_down responses; cancel ctx to give up early
+func (c *Client) PollToken(ctx context.Context, code *DeviceCode) (*Credentials, error) {
+	interval := time.Duration(code.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	var deadline time.Time
+	if code.ExpiresIn > 0 {
+		deadline = c.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	}
+
+	form := url.Values{
+		"grant_type":  {deviceGrantType},
+		"device_code": {code.DeviceCode},
+		"client_id":   {c.ClientID},
+	}
+	for {
+		if err := c.Sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+		if !deadline.IsZero() && c.Now().After(deadline) {
+			return nil, ErrExpired
+		}
+
+		var resp tokenResponse
+		status, err := c.post(ctx, "/oauth/token", form, &resp, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("token request failed: %w", err)
+		}
+		if status == http.StatusOK && resp.AccessToken != "" {
+			return c.credentials(resp), nil
+		}
+		switch resp.Error {
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += defaultInterval
+			continue
+		case "access_denied":
+			return nil, ErrAccessDenied
+		case "expired_token":
+			return nil, ErrExpired
+		default:
+			return nil, fmt.Errorf("token request failed: %s", describe(status, resp))
+		}
+	}
+}
+
+// Refresh exchanges a refresh token for new credentials
+// GitLab rotates refresh tokens: the old one stops working once the new one is issued
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*Credentials, error) {
+	if refreshToken == "" {
+		return nil, errors.New("token refresh failed: no refresh token")
+	}
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {c.ClientID},
+	}
+	var resp tokenResponse
+	status, err := c.post(ctx, "/oauth/token", form, &resp, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("token refresh failed: %w", err)
+	}
+	if status != http.StatusOK || resp.AccessToken == "" {
+		return nil, fmt.Errorf("token refresh failed: %s", describe(status, resp))
+	}
+	return c.credentials(resp), nil
+}
+
+// credentials converts a successful token response
+func (c *Client) credentials(resp tokenResponse) *Credentials {
+	creds := &Credentials{
+		ClientID:     c.ClientID,
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+	}
+	if resp.ExpiresIn > 0 {
+		creds.ExpiresAt = c.Now().Add(time.Duration(resp.ExpiresIn) * time.Second).UTC()
+	}
+	return creds
+}
+
+// post sends a form to an OAuth endpoint and decodes the JSON body into ok (200) or failure (other statuses)
+func (c *Client) post(ctx context.Context, path string, form url.Values, ok, failure any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	target := failure
+	if resp.StatusCode == http.StatusOK {
+		target = ok
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil && resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, fmt.Errorf("invalid response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// describe formats an error response of the OAuth endpoints
+func describe(status int, resp tokenResponse) string {
+	switch {
+	case resp.ErrorDescription != "":
+		return fmt.Sprintf("%s (%s)", resp.Error, resp.ErrorDescription)
+	case resp.Error != "":
+		return resp.Error
+	default:
+		return fmt.Sprintf("HTTP %d", status)
+	}
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,160 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client for server with a fixed clock and no waiting between polls
+func newTestClient(server *httptest.Server, now time.Time) (*Client, *[]time.Duration) {
+	var waits []time.Duration
+	c := NewClient(server.URL+"/", "app-id", 5*time.Second)
+	c.Now = func() time.Time { return now }
+	c.Sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	return c, &waits
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestDeviceFlow(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("client_id") != "app-id" {
+			t.Errorf("Missing client_id in %s request", r.URL.Path)
+		}
+		switch r.URL.Path {
+		case "/oauth/authorize_device":
+			if r.Form.Get("scope") != DefaultScope {
+				t.Errorf("scope = %q, want %q", r.Form.Get("scope"), DefaultScope)
+			}
+			writeJSON(w, http.StatusOK, map[string]any{
+				"device_code": "dev", "user_code": "ABCD-1234",
+				"verification_uri": "https://gitlab.test/oauth/device", "expires_in": 300, "interval": 2,
+			})
+		case "/oauth/token":
+			if r.Form.Get("grant_type") != deviceGrantType || r.Form.Get("device_code") != "dev" {
+				t.Errorf("Unexpected token request: %v", r.Form)
+			}
+			polls++
+			switch polls {
+			case 1:
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "authorization_pending"})
+			case 2:
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "slow_down"})
+			default:
+				writeJSON(w, http.StatusOK, map[string]any{"access_token": "access", "refresh_token": "refresh", "expires_in": 7200})
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	c, waits := newTestClient(server, now)
+	code, err := c.RequestDeviceCode(context.Background(), DefaultScope)
+	if err != nil {
+		t.Fatalf("RequestDeviceCode() error = %v", err)
+	}
+	if code.UserCode != "ABCD-1234" {
+		t.Errorf("UserCode = %q, want ABCD-1234", code.UserCode)
+	}
+
+	creds, err := c.PollToken(context.Background(), code)
+	if err != nil {
+		t.Fatalf("PollToken() error = %v", err)
+	}
+	want := Credentials{ClientID: "app-id", AccessToken: "access", RefreshToken: "refresh", ExpiresAt: now.Add(2 * time.Hour)}
+	if *creds != want {
+		t.Errorf("PollToken() = %+v, want %+v", *creds, want)
+	}
+	// slow_down adds 5 seconds to the interval
+	if len(*waits) != 3 || (*waits)[0] != 2*time.Second || (*waits)[2] != 7*time.Second {
+		t.Errorf("Poll waits = %v, want [2s 2s 7s]", *waits)
+	}
+}
+
+func TestPollToken_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		errCode string
+		want    error
+	}{
+		{"denied", "access_denied", ErrAccessDenied},
+		{"expired", "expired_token", ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": tt.errCode})
+			}))
+			defer server.Close()
+
+			c, _ := newTestClient(server, time.Now())
+			if _, err := c.PollToken(context.Background(), &DeviceCode{DeviceCode: "dev"}); !errors.Is(err, tt.want) {
+				t.Errorf("PollToken() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "old" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "revoked"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": "new-access", "refresh_token": "new-refresh", "expires_in": 60})
+	}))
+	defer server.Close()
+
+	now := time.Now().UTC()
+	c, _ := newTestClient(server, now)
+	creds, err := c.Refresh(context.Background(), "old")
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if creds.AccessToken != "new-access" || creds.RefreshToken != "new-refresh" || !creds.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Refresh() = %+v", creds)
+	}
+
+	if _, err := c.Refresh(context.Background(), "stale"); err == nil || err.Error() != "token refresh failed: invalid_grant (revoked)" {
+		t.Errorf("Refresh(stale) error = %v", err)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	creds := &Credentials{ClientID: "app", AccessToken: "a", RefreshToken: "r", ExpiresAt: now.Add(30 * time.Second)}
+	if !creds.Expired(now) {
+		t.Error("Expected a token expiring within the refresh margin to count as expired")
+	}
+	if (&Credentials{AccessToken: "a"}).Expired(now) {
+		t.Error("Expected a token without expiry to never expire")
+	}
+
+	secret, err := creds.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	decoded, ok := Decode(secret + "\n")
+	if !ok || *decoded != *creds {
+		t.Errorf("Decode(Encode()) = %+v, %v; want %+v", decoded, ok, creds)
+	}
+	if _, ok := Decode("glpat-abcdef"); ok {
+		t.Error("Expected a personal access token not to decode as OAuth credentials")
+	}
+}
@@ -0,0 +1,183 @@
+// Package opener runs user-defined commands ("openers") on a search result,
+// e.g. cloning it in a new tmux window or opening it in a Git client
+package opener
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Placeholders are the template variables available in opener commands
+//
+//	{{url}}       web page of the project or group
+//	{{ssh_url}}   SSH clone URL (git@host:group/project.git; empty for groups)
+//	{{http_url}}  HTTPS clone URL (empty for groups)
+//	{{path}}      full path (group/subgroup/project)
+//	{{name}}      last path segment
+//	{{namespace}} path without the last segment
+var Placeholders = []string{"url", "ssh_url", "http_url", "path", "name", "namespace"}
+
+// placeholderPattern matches a template variable, allowing spaces inside the braces
+var placeholderPattern = regexp.MustCompile(`{{\s*([A-Za-z_]+)\s*}}`)
+
+// Values holds the placeholder values of one result
+type Values map[string]string
+
+// ProjectValues returns the placeholder values of a project of the instance at baseURL
+func ProjectValues(baseURL, projectPath string) Values {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	projectPath = strings.Trim(projectPath, "/")
+	values := pathValues(projectPath)
+	values["url"] = baseURL + "/" + projectPath
+	values["http_url"] = baseURL + "/" + projectPath + ".git"
+	if parsed, err := url.Parse(baseURL); err == nil && parsed.Hostname() != "" {
+		values["ssh_url"] = "git@" + parsed.Hostname() + ":" + projectPath + ".git"
+	}
+	return values
+}
+
+// GroupValues returns the placeholder values of a group whose page is groupURL
+// Groups can't be cloned, so the clone URLs are empty
+func GroupValues(groupURL, groupPath string) Values {
+	values := pathValues(strings.Trim(groupPath, "/"))
+	values["url"] = groupURL
+	return values
+}
+
+// pathValues returns the values derived from the path alone
+func pathValues(fullPath string) Values {
+	values := Values{"path": fullPath, "name": path.Base(fullPath), "ssh_url": "", "http_url": ""}
+	if namespace := path.Dir(fullPath); namespace != "." {
+		values["namespace"] = namespace
+	} else {
+		values["namespace"] = ""
+	}
+	return values
+}
+
+// Validate checks that a command template only uses known placeholders
+func Validate(command string) error {
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("command is empty")
+	}
+	for _, match := range placeholderPattern.FindAllStringSubmatch(command, -1) {
+		if !slices.Contains(Placeholders, match[1]) {
+			return fmt.Errorf("unknown placeholder {{%s}} (available: %s)", match[1], strings.Join(Placeholders, ", "))
+		}
+	}
+	return nil
+}
+
+// Expand substitutes the placeholders of a template as they are (for URL templates)
+// Commands use Command, which quotes the values for the shell
+func Expand(command string, values Values) string {
+	return placeholderPattern.ReplaceAllStringFunc(command, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// Command returns the shell command running an expanded template
+// Templates are shell command lines (quotes, pipes and && work), run by sh or cmd on Windows
+// Values are quoted for where they appear in the line, so a path or a URL rewritten by
+// url_templates is always one argument and never runs as a command
+func Command(command string, values Values) *exec.Cmd {
+	windows := runtime.GOOS == "windows"
+	expanded := expandShell(command, values, windows)
+	// #nosec G204 -- The command is configured by the user; substituted values are quoted
+	if windows {
+		return exec.Command("cmd", "/C", expanded)
+	}
+	return exec.Command("sh", "-c", expanded)
+}
+
+// shellQuoting is where a placeholder appears in a command line
+type shellQuoting int
+
+const (
+	unquoted shellQuoting = iota
+	singleQuoted
+	doubleQuoted
+)
+
+// toggle returns the quoting after a quote character that opens or closes to
+func (q shellQuoting) toggle(to shellQuoting) shellQuoting {
+	if q == to {
+		return unquoted
+	}
+	return to
+}
+
+// shellSafe matches values a shell takes literally outside quotes
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9@%+=:,./_-]*$`)
+
+// expandShell substitutes the placeholders of a command line, each value quoted for the
+// quoting it appears in: sh quotes unquoted values with '...', escapes ' in '...' and
+// \ $ ` " in "..."; cmd quotes unquoted values with "..." and drops " from values
+func expandShell(command string, values Values, windows bool) string {
+	var b strings.Builder
+	quoting := unquoted
+	escaped := false
+	last := 0
+	scan := func(text string) {
+		for _, r := range text {
+			switch {
+			case windows:
+				if r == '"' {
+					quoting = quoting.toggle(doubleQuoted)
+				}
+			case escaped:
+				escaped = false
+			case quoting == singleQuoted:
+				if r == '\'' {
+					quoting = unquoted
+				}
+			case r == '\\':
+				escaped = true // Outside single quotes a backslash escapes the next character
+			case r == '"':
+				quoting = quoting.toggle(doubleQuoted)
+			case r == '\'' && quoting == unquoted:
+				quoting = singleQuoted
+			}
+		}
+		b.WriteString(text)
+	}
+
+	for _, loc := range placeholderPattern.FindAllStringSubmatchIndex(command, -1) {
+		scan(command[last:loc[0]])
+		last = loc[1]
+		value, ok := values[command[loc[2]:loc[3]]]
+		if !ok {
+			b.WriteString(command[loc[0]:loc[1]])
+			continue
+		}
+		switch {
+		case windows && quoting == unquoted && strings.ContainsAny(value, " \t&|<>^()%!\""):
+			b.WriteString(`"` + strings.ReplaceAll(value, `"`, "") + `"`)
+		case windows:
+			b.WriteString(strings.ReplaceAll(value, `"`, ""))
+		case quoting == singleQuoted:
+			b.WriteString(strings.ReplaceAll(value, "'", `'\''`))
+		case quoting == doubleQuoted:
+			b.WriteString(shellDoubleQuote.Replace(value))
+		case shellSafe.MatchString(value):
+			b.WriteString(value)
+		default:
+			b.WriteString("'" + strings.ReplaceAll(value, "'", `'\''`) + "'")
+		}
+	}
+	scan(command[last:])
+	return b.String()
+}
+
+// shellDoubleQuote escapes the characters sh interprets inside "..."
+var shellDoubleQuote = strings.NewReplacer(`\`, `\\`, `$`, `\$`, "`", "\\`", `"`, `\"`)
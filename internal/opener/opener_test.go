@@ -0,0 +1,119 @@
+package opener
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestProjectValues(t *testing.T) {
+	got := ProjectValues("https://gitlab.example.com/", "/group/sub/api")
+	want := Values{
+		"url":       "https://gitlab.example.com/group/sub/api",
+		"ssh_url":   "git@gitlab.example.com:group/sub/api.git",
+		"http_url":  "https://gitlab.example.com/group/sub/api.git",
+		"path":      "group/sub/api",
+		"name":      "api",
+		"namespace": "group/sub",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProjectValues() = %v, want %v", got, want)
+	}
+
+	if got := ProjectValues("https://gitlab.example.com", "api")["namespace"]; got != "" {
+		t.Errorf("Expected an empty namespace for a top-level path, got %q", got)
+	}
+}
+
+func TestGroupValues(t *testing.T) {
+	got := GroupValues("https://gitlab.example.com/groups/org/platform", "org/platform")
+	if got["url"] != "https://gitlab.example.com/groups/org/platform" || got["name"] != "platform" || got["namespace"] != "org" {
+		t.Errorf("Unexpected group values %v", got)
+	}
+	if got["ssh_url"] != "" || got["http_url"] != "" {
+		t.Errorf("Expected no clone URLs for a group, got %v", got)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	values := ProjectValues("https://gitlab.example.com", "group/api")
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"tmux new-window 'git clone {{ssh_url}}'", "tmux new-window 'git clone git@gitlab.example.com:group/api.git'"},
+		{"open -a Tower {{ url }}", "open -a Tower https://gitlab.example.com/group/api"},
+		{"echo {{namespace}}/{{name}}", "echo group/api"},
+		{"echo {{unknown}}", "echo {{unknown}}"},
+	}
+	for _, tt := range tests {
+		if got := Expand(tt.command, values); got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestExpandShell(t *testing.T) {
+	values := Values{"path": "group/api", "url": "https://sso.example.com/login?next=a&b=$(id)", "name": "it's"}
+	tests := []struct {
+		command string
+		windows bool
+		want    string
+	}{
+		// Plain paths and URLs are substituted as they are, quoted or not
+		{"tmux new-window 'git clone {{path}}'", false, "tmux new-window 'git clone group/api'"},
+		{"cd {{path}} && ls", false, "cd group/api && ls"},
+		// Other values are quoted for their context
+		{"open {{url}}", false, `open 'https://sso.example.com/login?next=a&b=$(id)'`},
+		{`open "{{url}}"`, false, `open "https://sso.example.com/login?next=a&b=\$(id)"`},
+		{"echo '{{name}}'", false, `echo 'it'\''s'`},
+		{`echo "it's" {{name}}`, false, `echo "it's" 'it'\''s'`},
+		{`echo \' {{name}}`, false, `echo \' 'it'\''s'`},
+		{"echo {{unknown}}", false, "echo {{unknown}}"},
+		{"start {{url}}", true, `start "https://sso.example.com/login?next=a&b=$(id)"`},
+		{`start "" "{{url}}"`, true, `start "" "https://sso.example.com/login?next=a&b=$(id)"`},
+	}
+	for _, tt := range tests {
+		if got := expandShell(tt.command, values, tt.windows); got != tt.want {
+			t.Errorf("expandShell(%q, windows=%v) = %q, want %q", tt.command, tt.windows, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("open -a Tower {{url}} {{ path }}"); err != nil {
+		t.Errorf("Expected a valid template, got %v", err)
+	}
+	if err := Validate("git clone {{ssh}}"); err == nil || !strings.Contains(err.Error(), "{{ssh}}") {
+		t.Errorf("Expected an unknown placeholder error, got %v", err)
+	}
+	if err := Validate("  "); err == nil {
+		t.Error("Expected an error for an empty command")
+	}
+}
+
+func TestCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out, err := Command("echo {{name}} && echo {{namespace}}", ProjectValues("https://gitlab.example.com", "group/api")).Output()
+	if err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+	if got := string(out); got != "api\ngroup\n" {
+		t.Errorf("Unexpected output %q", got)
+	}
+
+	// Values never run as commands, whatever the template's quoting
+	values := Values{"url": "x;echo injected`echo injected`$(echo injected)'\"", "path": "p"}
+	for _, command := range []string{"printf %s {{url}}", `printf %s "{{url}}"`, "printf %s '{{url}}'"} {
+		out, err := Command(command, values).Output()
+		if err != nil {
+			t.Fatalf("Command(%q) failed: %v", command, err)
+		}
+		if got := string(out); got != values["url"] {
+			t.Errorf("Command(%q) printed %q, want %q", command, got, values["url"])
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package paths resolves glf's configuration and cache locations
+//
+// Resolution order (first match wins):
+//
+//	config dir:  $GLF_CONFIG_DIR, $XDG_CONFIG_HOME/glf, %APPDATA%\glf (Windows), ~/.config/glf
+//	config file: $GLF_CONFIG, <config dir>/config.yaml
+//	cache dir:   $GLF_CACHE_DIR, $XDG_CACHE_HOME/glf, %LOCALAPPDATA%\glf (Windows), ~/.cache/glf
+//
+// cache.dir in config.yaml still overrides the cache default.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Environment variables that override the default locations
+const (
+	EnvConfigFile = "GLF_CONFIG"     // Full path of config.yaml
+	EnvConfigDir  = "GLF_CONFIG_DIR" // Directory containing config.yaml
+	EnvCacheDir   = "GLF_CACHE_DIR"  // Cache directory (index, history, timestamps)
+)
+
+// File names inside the config and cache directories
+const (
+	configFileName = "config.yaml"
+	indexDirName   = "description.bleve"
+	groupIndexName = "groups.bleve"
+	historyName    = "history.gob"
+	renamesName    = "renames.json"
+	annotationName = "annotations.yaml"
+	etagCacheName  = "etags.gob"
+)
+
+// goos is runtime.GOOS, overridable in tests
+var goos = runtime.GOOS
+
+// Home returns the user's home directory ($HOME, falling back to the OS lookup)
+func Home() string {
+	if home := os.Getenv("HOME"); home != "" {
+		return home
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return home
+}
+
+// ConfigDir returns the directory containing config.yaml
+func ConfigDir() string {
+	if dir := os.Getenv(EnvConfigDir); dir != "" {
+		return ExpandHome(dir)
+	}
+	if file := os.Getenv(EnvConfigFile); file != "" {
+		return filepath.Dir(ExpandHome(file))
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "glf")
+	}
+	if goos == "windows" {
+		if dir := os.Getenv("APPDATA"); dir != "" {
+			return filepath.Join(dir, "glf")
+		}
+	}
+	return filepath.Join(Home(), ".config", "glf")
+}
+
+// ConfigFile returns the path of config.yaml
+func ConfigFile() string {
+	if file := os.Getenv(EnvConfigFile); file != "" {
+		return ExpandHome(file)
+	}
+	return filepath.Join(ConfigDir(), configFileName)
+}
+
+// CacheDir returns the default cache directory
+func CacheDir() string {
+	if dir := os.Getenv(EnvCacheDir); dir != "" {
+		return ExpandHome(dir)
+	}
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "glf")
+	}
+	if goos == "windows" {
+		if dir := os.Getenv("LOCALAPPDATA"); dir != "" {
+			return filepath.Join(dir, "glf")
+		}
+	}
+	return filepath.Join(Home(), ".cache", "glf")
+}
+
+// IndexPath returns the description index location inside a cache directory
+func IndexPath(cacheDir string) string {
+	return filepath.Join(cacheDir, indexDirName)
+}
+
+// GroupIndexPath returns the group index location inside a cache directory
+func GroupIndexPath(cacheDir string) string {
+	return filepath.Join(cacheDir, groupIndexName)
+}
+
+// HistoryPath returns the selection history file inside a cache directory
+func HistoryPath(cacheDir string) string {
+	return filepath.Join(cacheDir, historyName)
+}
+
+// RenamesPath returns the file of former project paths (old path → new path) inside a cache directory
+func RenamesPath(cacheDir string) string {
+	return filepath.Join(cacheDir, renamesName)
+}
+
+// AnnotationsPath returns the annotations file (project labels) inside a config or cache directory
+// The config directory holds the local file, the cache directory the copy fetched by sync
+func AnnotationsPath(dir string) string {
+	return filepath.Join(dir, annotationName)
+}
+
+// ETagCachePath returns the cache of conditional API responses inside a cache directory
+func ETagCachePath(cacheDir string) string {
+	return filepath.Join(cacheDir, etagCacheName)
+}
+
+// ExpandHome expands a leading ~ to the home directory
+func ExpandHome(path string) string {
+	if len(path) > 0 && path[0] == '~' {
+		home := Home()
+		if len(path) == 1 {
+			return home
+		}
+		return filepath.Join(home, path[1:])
+	}
+	return path
+}
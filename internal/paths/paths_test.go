@@ -0,0 +1,111 @@
+package paths
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// clearEnv unsets every variable that influences path resolution
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{EnvConfigFile, EnvConfigDir, EnvCacheDir, "XDG_CONFIG_HOME", "XDG_CACHE_HOME", "APPDATA", "LOCALAPPDATA"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("HOME", "/home/me")
+}
+
+func TestDefaults(t *testing.T) {
+	clearEnv(t)
+
+	if got, want := ConfigDir(), filepath.Join("/home/me", ".config", "glf"); got != want {
+		t.Errorf("ConfigDir() = %q, want %q", got, want)
+	}
+	if got, want := ConfigFile(), filepath.Join("/home/me", ".config", "glf", "config.yaml"); got != want {
+		t.Errorf("ConfigFile() = %q, want %q", got, want)
+	}
+	if got, want := CacheDir(), filepath.Join("/home/me", ".cache", "glf"); got != want {
+		t.Errorf("CacheDir() = %q, want %q", got, want)
+	}
+}
+
+func TestXDG(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+
+	if got, want := ConfigDir(), filepath.Join("/xdg/config", "glf"); got != want {
+		t.Errorf("ConfigDir() = %q, want %q", got, want)
+	}
+	if got, want := CacheDir(), filepath.Join("/xdg/cache", "glf"); got != want {
+		t.Errorf("CacheDir() = %q, want %q", got, want)
+	}
+}
+
+func TestGLFOverrides(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	t.Setenv(EnvConfigDir, "~/glf-config")
+	t.Setenv(EnvCacheDir, "/tmp/glf-cache")
+
+	if got, want := ConfigDir(), filepath.Join("/home/me", "glf-config"); got != want {
+		t.Errorf("ConfigDir() = %q, want %q", got, want)
+	}
+	if got, want := ConfigFile(), filepath.Join("/home/me", "glf-config", "config.yaml"); got != want {
+		t.Errorf("ConfigFile() = %q, want %q", got, want)
+	}
+	if got := CacheDir(); got != "/tmp/glf-cache" {
+		t.Errorf("CacheDir() = %q, want /tmp/glf-cache", got)
+	}
+
+	// GLF_CONFIG names the file; its directory becomes the config dir unless GLF_CONFIG_DIR is set
+	t.Setenv(EnvConfigDir, "")
+	t.Setenv(EnvConfigFile, "/etc/glf/team.yaml")
+	if got := ConfigFile(); got != "/etc/glf/team.yaml" {
+		t.Errorf("ConfigFile() = %q, want /etc/glf/team.yaml", got)
+	}
+	if got := ConfigDir(); got != "/etc/glf" {
+		t.Errorf("ConfigDir() = %q, want /etc/glf", got)
+	}
+}
+
+func TestWindows(t *testing.T) {
+	clearEnv(t)
+	oldGOOS := goos
+	goos = "windows"
+	defer func() { goos = oldGOOS }()
+
+	t.Setenv("APPDATA", `C:\Users\me\AppData\Roaming`)
+	t.Setenv("LOCALAPPDATA", `C:\Users\me\AppData\Local`)
+
+	if got, want := ConfigDir(), filepath.Join(`C:\Users\me\AppData\Roaming`, "glf"); got != want {
+		t.Errorf("ConfigDir() = %q, want %q", got, want)
+	}
+	if got, want := CacheDir(), filepath.Join(`C:\Users\me\AppData\Local`, "glf"); got != want {
+		t.Errorf("CacheDir() = %q, want %q", got, want)
+	}
+
+	// XDG variables still win when set explicitly
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	if got, want := ConfigDir(), filepath.Join("/xdg/config", "glf"); got != want {
+		t.Errorf("ConfigDir() = %q, want %q", got, want)
+	}
+}
+
+func TestCacheFiles(t *testing.T) {
+	if got, want := IndexPath("/c"), filepath.Join("/c", "description.bleve"); got != want {
+		t.Errorf("IndexPath() = %q, want %q", got, want)
+	}
+	if got, want := GroupIndexPath("/c"), filepath.Join("/c", "groups.bleve"); got != want {
+		t.Errorf("GroupIndexPath() = %q, want %q", got, want)
+	}
+	if got, want := HistoryPath("/c"), filepath.Join("/c", "history.gob"); got != want {
+		t.Errorf("HistoryPath() = %q, want %q", got, want)
+	}
+	if got, want := RenamesPath("/c"), filepath.Join("/c", "renames.json"); got != want {
+		t.Errorf("RenamesPath() = %q, want %q", got, want)
+	}
+	if got, want := AnnotationsPath("/c"), filepath.Join("/c", "annotations.yaml"); got != want {
+		t.Errorf("AnnotationsPath() = %q, want %q", got, want)
+	}
+}
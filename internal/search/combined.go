@@ -2,14 +2,50 @@
 package search
 
 import (
+	"context"
 	"fmt"
-	"path/filepath"
+	"regexp"
 	"sort"
 
 	"github.com/igusev/glf/internal/index"
 	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/paths"
 )
 
+// archivedPenalty is subtracted from the total score of archived projects (scoring.archived_penalty)
+var archivedPenalty float64
+
+// SetArchivedPenalty sets the score penalty of archived projects, so they rank below active
+// ones when shown instead of mixing with them (0 disables it, negative values count as 0)
+func SetArchivedPenalty(penalty float64) {
+	archivedPenalty = max(0, penalty)
+}
+
+// forkPenalty is subtracted from the total score of forks (scoring.fork_penalty)
+var forkPenalty float64
+
+// SetForkPenalty sets the score penalty of forks, so the upstream project ranks above forks
+// with the same name (0 disables it, negative values count as 0)
+func SetForkPenalty(penalty float64) {
+	forkPenalty = max(0, penalty)
+}
+
+// projectPenalty returns the score penalty of a project (archivedPenalty for archived projects)
+func projectPenalty(p model.Project) float64 {
+	if p.Archived {
+		return archivedPenalty
+	}
+	return 0
+}
+
+// projectForkPenalty returns the fork penalty of a project (forkPenalty for forks)
+func projectForkPenalty(p model.Project) float64 {
+	if p.IsFork() {
+		return forkPenalty
+	}
+	return 0
+}
+
 // calculateRelevanceMultiplier returns a multiplier [0.0, 1.0] based on search relevance
 // This prevents history/starred bonuses from overwhelming irrelevant search results
 //
@@ -76,6 +112,12 @@ func calculateRelevanceMultiplier(searchScore float64) float64 {
 	}
 }
 
+// RelevanceMultiplier returns the factor applied to history and starred bonuses
+// for a match with the given search score (see calculateRelevanceMultiplier)
+func RelevanceMultiplier(searchScore float64) float64 {
+	return calculateRelevanceMultiplier(searchScore)
+}
+
 // CombinedSearch performs unified search using Bleve across project names, paths, and descriptions
 // For empty queries, returns all projects sorted by history
 // If descIndex is provided, it will be used; otherwise a new index will be opened
@@ -83,17 +125,62 @@ func CombinedSearch(query string, projects []model.Project, historyScores map[st
 	return CombinedSearchWithIndex(query, projects, historyScores, cacheDir, nil)
 }
 
+// Candidate limits for the full-text search
+const (
+	maxTextResults     = 100  // Plain queries
+	maxFilteredResults = 1000 // Queries with filters (candidates are narrowed down after the search)
+)
+
 // CombinedSearchWithIndex is like CombinedSearch but accepts an already-open index
 // If projects is nil, project data is taken directly from Bleve stored fields
 // (avoids the need to load all projects into memory for non-empty queries)
+// Queries may use the filter syntax described in Query (name:, group:, -term, is:starred, ...)
 func CombinedSearchWithIndex(query string, projects []model.Project, historyScores map[string]int, cacheDir string, descIndex *index.DescriptionIndex) ([]index.CombinedMatch, error) {
+	return CombinedSearchWithIndexSize(query, projects, historyScores, cacheDir, descIndex, 0)
+}
+
+// CombinedSearchWithIndexSize is like CombinedSearchWithIndex but considers at least
+// minCandidates full-text candidates (used to paginate past the default candidate limit)
+func CombinedSearchWithIndexSize(query string, projects []model.Project, historyScores map[string]int, cacheDir string, descIndex *index.DescriptionIndex, minCandidates int) ([]index.CombinedMatch, error) {
+	return combinedSearchWithFilters(context.Background(), query, projects, historyScores, cacheDir, descIndex, minCandidates)
+}
+
+// CombinedSearchContext is like CombinedSearchWithIndex but stops the full-text search early
+// when ctx is canceled (the error then wraps ctx.Err())
+func CombinedSearchContext(ctx context.Context, query string, projects []model.Project, historyScores map[string]int, cacheDir string, descIndex *index.DescriptionIndex) ([]index.CombinedMatch, error) {
+	return combinedSearchWithFilters(ctx, query, projects, historyScores, cacheDir, descIndex, 0)
+}
+
+// combinedSearchWithFilters runs combinedSearch for the text part of query and applies its filters
+func combinedSearchWithFilters(ctx context.Context, query string, projects []model.Project, historyScores map[string]int, cacheDir string, descIndex *index.DescriptionIndex, minCandidates int) ([]index.CombinedMatch, error) {
+	parsed := ParseQuery(query)
+	if !parsed.HasFilters() {
+		return combinedSearch(ctx, query, projects, historyScores, cacheDir, descIndex, max(maxTextResults, minCandidates))
+	}
+
+	// Rank by the text part, then keep only projects satisfying every filter
+	matches, err := combinedSearch(ctx, parsed.SearchText(), projects, historyScores, cacheDir, descIndex, max(maxFilteredResults, minCandidates))
+	if err != nil {
+		return nil, err
+	}
+	filtered := matches[:0]
+	for _, match := range matches {
+		if parsed.Matches(match.Project) {
+			filtered = append(filtered, match)
+		}
+	}
+	return filtered, nil
+}
+
+// combinedSearch runs the full-text search (or lists all projects for an empty query) and applies history boosts
+func combinedSearch(ctx context.Context, query string, projects []model.Project, historyScores map[string]int, cacheDir string, descIndex *index.DescriptionIndex, maxResults int) ([]index.CombinedMatch, error) {
 	if query == "" {
 		// Empty query: return all projects sorted by history
 		// If projects not provided, lazy-load from index
 		if projects == nil {
 			if descIndex == nil {
 				// No index provided, open it ourselves
-				indexPath := filepath.Join(cacheDir, "description.bleve")
+				indexPath := paths.IndexPath(cacheDir)
 				if !index.Exists(indexPath) {
 					return nil, fmt.Errorf("search index not found, run 'glf sync' to build it")
 				}
@@ -121,7 +208,7 @@ func CombinedSearchWithIndex(query string, projects []model.Project, historyScor
 	var needClose bool
 	if descIndex == nil {
 		// No index provided, open it ourselves
-		indexPath := filepath.Join(cacheDir, "description.bleve")
+		indexPath := paths.IndexPath(cacheDir)
 		if !index.Exists(indexPath) {
 			// Index doesn't exist yet - return empty results
 			// User should run 'glf sync' to build it
@@ -149,7 +236,7 @@ func CombinedSearchWithIndex(query string, projects []model.Project, historyScor
 	}
 
 	// Search across all fields (ProjectName, ProjectPath, Description) with boosting
-	bleveMatches, err := descIndex.Search(query, 100)
+	bleveMatches, err := descIndex.SearchContext(ctx, query, maxResults)
 	if err != nil {
 		// Search failed
 		return nil, fmt.Errorf("search failed: %w", err)
@@ -166,6 +253,7 @@ func CombinedSearchWithIndex(query string, projects []model.Project, historyScor
 	}
 
 	// Convert Bleve matches to CombinedMatch with history boost
+	spellings := pathSpellings(query)
 	results := make([]index.CombinedMatch, 0, len(bleveMatches))
 	for _, match := range bleveMatches {
 		var fullProject model.Project
@@ -181,83 +269,130 @@ func CombinedSearchWithIndex(query string, projects []model.Project, historyScor
 			fullProject = match.Project
 		}
 
-		// Get history boost for this project
-		historyScore := 0
-		if score, exists := historyScores[fullProject.Path]; exists {
-			historyScore = score
-		}
-
-		// Calculate starred bonus
-		starredBonus := 0
-		if fullProject.Starred {
-			starredBonus += 3
-		}
-
-		// Apply context-dependent scaling based on search relevance
-		// This prevents history/starred from dominating when search relevance is low
-		relevanceMultiplier := calculateRelevanceMultiplier(match.Score)
-		adjustedHistoryScore := float64(historyScore) * relevanceMultiplier
-		adjustedStarredBonus := float64(starredBonus) * relevanceMultiplier
-
-		// Calculate total score (search + context-adjusted history + starred)
+		// History and starred bonuses are scaled by search relevance, so they can't
+		// dominate when relevance is low
 		// Example: searchScore=0.012 (too low) -> multiplier=0.0 -> no history/starred boost
 		//          searchScore=0.5 (moderate) -> multiplier≈0.34 -> partial boost
 		//          searchScore=1.2 (good) -> multiplier≈0.92 -> strong boost
 		//          searchScore=1.4+ (high) -> multiplier=1.0 -> full boost
-		totalScore := match.Score + adjustedHistoryScore + adjustedStarredBonus
-
-		results = append(results, index.CombinedMatch{
-			Project:      fullProject,
-			SearchScore:  match.Score,
-			HistoryScore: historyScore,
-			StarredBonus: starredBonus,
-			TotalScore:   totalScore,
-			// Bleve searches all fields, so consider it as both name and description match
-			Source:  index.MatchSourceName | index.MatchSourceDescription,
-			Snippet: match.Snippet,
-		})
+		// Tokens starting distinct path segments beat scattered matches of the same tokens
+		pathBonus := pathSegmentBoost * bestPathSegmentScore(spellings, fullProject.Path)
+		result := rankMatch(fullProject, historyScores, match.Score, pathBonus, calculateRelevanceMultiplier(match.Score))
+		// Bleve searches all fields, so consider it as both name and description match
+		result.Source = index.MatchSourceName | index.MatchSourceDescription
+		result.Snippet = match.Snippet
+		results = append(results, result)
 	}
 
 	// Sort by total score (search + history), highest first
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].TotalScore > results[j].TotalScore
-	})
+	sortByScore(results)
 
 	return results, nil
 }
 
+// RegexSearchWithIndex matches project paths against a Go regular expression
+// instead of full-text search (e.g., "^platform/.*-operator$")
+// Matches are ordered by history like an empty query; an empty pattern matches everything
+func RegexSearchWithIndex(pattern string, projects []model.Project, historyScores map[string]int, cacheDir string, descIndex *index.DescriptionIndex) ([]index.CombinedMatch, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+
+	// Load every project: the empty-query path does this (from the index if needed)
+	all, err := combinedSearch(context.Background(), "", projects, historyScores, cacheDir, descIndex, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := all[:0]
+	for _, match := range all {
+		if re.MatchString(match.Project.Path) {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
 // allProjectsSortedByHistory returns all projects sorted by history scores
 // Used for empty queries to show recently/frequently used projects first
 func allProjectsSortedByHistory(projects []model.Project, historyScores map[string]int) []index.CombinedMatch {
 	results := make([]index.CombinedMatch, len(projects))
 
 	for i, p := range projects {
-		historyScore := 0
-		if score, exists := historyScores[p.Path]; exists {
-			historyScore = score
-		}
+		// No search for empty query: history and starred bonuses count in full
+		results[i] = rankMatch(p, historyScores, 0, 0, 1)
+		results[i].Source = index.MatchSourceName
+		results[i].Snippet = p.Description // Show full description for empty query
+	}
+
+	// Sort by total score (history only for empty query) descending
+	sortByScore(results)
+
+	return results
+}
 
-		// Calculate starred bonus
-		starredBonus := 0
-		if p.Starred {
-			starredBonus += 3
+// RemoteMatches converts live GitLab search results into matches marked as remote
+// Remote results keep the API order (GitLab sorts by last activity) and carry no scores
+func RemoteMatches(projects []model.Project) []index.CombinedMatch {
+	matches := make([]index.CombinedMatch, 0, len(projects))
+	for _, p := range projects {
+		matches = append(matches, index.CombinedMatch{
+			Project: p,
+			Source:  index.MatchSourceName,
+			Snippet: p.Description,
+			Remote:  true,
+		})
+	}
+	return matches
+}
+
+// ApplyPins moves pinned projects to the top of the results, in pin order, and marks them
+// Pinned projects the query doesn't match are added from lookup, regardless of relevance;
+// with a nil lookup pins only reorder matches
+func ApplyPins(matches []index.CombinedMatch, pinned []string, lookup func(path string) (model.Project, bool)) []index.CombinedMatch {
+	if len(pinned) == 0 || (len(matches) == 0 && lookup == nil) {
+		return matches
+	}
+
+	pinOrder := make(map[string]int, len(pinned))
+	for i, path := range pinned {
+		if _, exists := pinOrder[path]; !exists {
+			pinOrder[path] = i
 		}
+	}
 
-		results[i] = index.CombinedMatch{
-			Project:      p,
-			SearchScore:  0.0, // No search for empty query
-			HistoryScore: historyScore,
-			StarredBonus: starredBonus,
-			TotalScore:   float64(historyScore) + float64(starredBonus),
-			Source:       index.MatchSourceName,
-			Snippet:      p.Description, // Show full description for empty query
+	result := make([]index.CombinedMatch, 0, len(matches))
+	rest := make([]index.CombinedMatch, 0, len(matches))
+	for _, match := range matches {
+		if _, ok := pinOrder[match.Project.Path]; ok {
+			match.Pinned = true
+			result = append(result, match)
+		} else {
+			rest = append(rest, match)
 		}
 	}
+	if lookup != nil {
+		found := make(map[string]bool, len(result))
+		for _, match := range result {
+			found[match.Project.Path] = true
+		}
+		for path := range pinOrder {
+			if found[path] {
+				continue
+			}
+			if project, ok := lookup(path); ok {
+				result = append(result, index.CombinedMatch{Project: project, Pinned: true})
+			}
+		}
+	}
+	if len(result) == 0 {
+		return matches
+	}
 
-	// Sort by total score (history only for empty query) descending
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].TotalScore > results[j].TotalScore
+	sort.SliceStable(result, func(i, j int) bool {
+		return pinOrder[result[i].Project.Path] < pinOrder[result[j].Project.Path]
 	})
 
-	return results
+	return append(result, rest...)
 }
@@ -566,13 +566,111 @@ func TestCombinedSearchWithIndex_HistoryBoostIntegration(t *testing.T) {
 	}
 
 	// Verify TotalScore includes history
-	expectedTotal := results[0].SearchScore + float64(results[0].HistoryScore)
+	expectedTotal := results[0].SearchScore + results[0].PathBonus + float64(results[0].HistoryScore)
 	if results[0].TotalScore != expectedTotal {
-		t.Errorf("TotalScore = %f, want %f (SearchScore + HistoryScore)",
+		t.Errorf("TotalScore = %f, want %f (SearchScore + PathBonus + HistoryScore)",
 			results[0].TotalScore, expectedTotal)
 	}
 }
 
+func TestArchivedPenalty(t *testing.T) {
+	tmpDir := t.TempDir()
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tmpDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create test index: %v", err)
+	}
+	defer descIndex.Close()
+
+	projects := []model.Project{
+		{Path: "api/legacy", Name: "Legacy API", Description: "API service", Archived: true},
+		{Path: "api/current", Name: "Current API", Description: "API service"},
+	}
+	docs := make([]index.DescriptionDocument, len(projects))
+	for i, p := range projects {
+		docs[i] = index.NewDocument(p)
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to add test docs: %v", err)
+	}
+
+	// The archived project is used a lot, so it ranks first without a penalty
+	historyScores := map[string]int{"api/legacy": 20}
+
+	SetArchivedPenalty(100)
+	defer SetArchivedPenalty(0)
+
+	for _, query := range []string{"api", ""} {
+		results, err := CombinedSearchWithIndex(query, nil, historyScores, tmpDir, descIndex)
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", query, err)
+		}
+		if len(results) != 2 || results[0].Project.Path != "api/current" {
+			t.Fatalf("Search(%q): expected api/current first, got %+v", query, results)
+		}
+		legacy := results[1]
+		if legacy.ArchivedPenalty != 100 || legacy.TotalScore >= 0 {
+			t.Errorf("Search(%q): archived match has penalty %.0f, total %.2f", query, legacy.ArchivedPenalty, legacy.TotalScore)
+		}
+		if results[0].ArchivedPenalty != 0 {
+			t.Errorf("Search(%q): expected no penalty for an active project", query)
+		}
+	}
+
+	// Negative penalties are ignored
+	SetArchivedPenalty(-5)
+	if results, _ := CombinedSearchWithIndex("", nil, historyScores, tmpDir, descIndex); results[0].Project.Path != "api/legacy" {
+		t.Errorf("Expected history to rank api/legacy first without a penalty, got %s", results[0].Project.Path)
+	}
+}
+
+func TestForkPenalty(t *testing.T) {
+	tmpDir := t.TempDir()
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tmpDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create test index: %v", err)
+	}
+	defer descIndex.Close()
+
+	projects := []model.Project{
+		{Path: "alice/billing", Name: "billing", Description: "Billing service", ForkedFrom: "platform/billing"},
+		{Path: "platform/billing", Name: "billing", Description: "Billing service"},
+	}
+	docs := make([]index.DescriptionDocument, len(projects))
+	for i, p := range projects {
+		docs[i] = index.NewDocument(p)
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to add test docs: %v", err)
+	}
+
+	// The fork is the one used lately, so it ranks first without a penalty
+	historyScores := map[string]int{"alice/billing": 5}
+
+	SetForkPenalty(10)
+	defer SetForkPenalty(0)
+
+	for _, query := range []string{"billing", ""} {
+		results, err := CombinedSearchWithIndex(query, nil, historyScores, tmpDir, descIndex)
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", query, err)
+		}
+		if len(results) != 2 || results[0].Project.Path != "platform/billing" {
+			t.Fatalf("Search(%q): expected the upstream first, got %+v", query, results)
+		}
+		if results[1].ForkPenalty != 10 || results[1].Project.ForkedFrom != "platform/billing" {
+			t.Errorf("Search(%q): fork has penalty %.0f, upstream %q", query, results[1].ForkPenalty, results[1].Project.ForkedFrom)
+		}
+		if results[0].ForkPenalty != 0 {
+			t.Errorf("Search(%q): expected no penalty for the upstream", query)
+		}
+	}
+
+	SetForkPenalty(0)
+	if results, _ := CombinedSearchWithIndex("", nil, historyScores, tmpDir, descIndex); results[0].Project.Path != "alice/billing" {
+		t.Errorf("Expected history to rank the fork first without a penalty, got %s", results[0].Project.Path)
+	}
+}
+
 func TestCombinedSearchWithIndex_SnippetGeneration(t *testing.T) {
 	// Test that snippets are generated for matches
 	tmpDir, err := os.MkdirTemp("", "glf-search-snippet-*")
@@ -644,8 +742,10 @@ func TestCombinedSearchWithIndex_IndexOpenError(t *testing.T) {
 
 	historyScores := map[string]int{}
 
-	// Should error when trying to open corrupted index
+	// Should error when trying to open corrupted index (a read-only cache is never repaired)
+	index.SetReadOnly(true)
 	_, err = CombinedSearchWithIndex("test", projects, historyScores, tmpDir, nil)
+	index.SetReadOnly(false)
 	if err == nil {
 		t.Error("Expected error when index is corrupted")
 	}
@@ -654,6 +754,11 @@ func TestCombinedSearchWithIndex_IndexOpenError(t *testing.T) {
 	if err != nil && !contains(err.Error(), "failed to open search index") {
 		t.Errorf("Unexpected error message: %v", err)
 	}
+
+	// A writable cache replaces the damaged index with an empty one (the next sync fills it)
+	if _, err := CombinedSearchWithIndex("test", projects, historyScores, tmpDir, nil); err != nil {
+		t.Errorf("Expected the damaged index to be recreated, got %v", err)
+	}
 }
 
 func TestCombinedSearchWithIndex_SearchError(t *testing.T) {
@@ -1351,3 +1456,79 @@ func TestRelevanceMultiplier_HistoryAndStarredInteraction(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyPins(t *testing.T) {
+	matches := []index.CombinedMatch{
+		{Project: model.Project{Path: "a/one"}, TotalScore: 9},
+		{Project: model.Project{Path: "b/two"}, TotalScore: 8},
+		{Project: model.Project{Path: "c/three"}, TotalScore: 7},
+		{Project: model.Project{Path: "d/four"}, TotalScore: 6},
+	}
+
+	result := ApplyPins(matches, []string{"d/four", "missing/project", "b/two"}, nil)
+
+	want := []string{"d/four", "b/two", "a/one", "c/three"}
+	if len(result) != len(want) {
+		t.Fatalf("Expected %d results, got %d", len(want), len(result))
+	}
+	for i, path := range want {
+		if result[i].Project.Path != path {
+			t.Errorf("result[%d] = %s, want %s", i, result[i].Project.Path, path)
+		}
+	}
+	if !result[0].Pinned || !result[1].Pinned || result[2].Pinned {
+		t.Error("Expected only pinned projects to be marked as pinned")
+	}
+
+	// No pins: unchanged
+	if got := ApplyPins(matches, nil, nil); got[0].Project.Path != "a/one" {
+		t.Errorf("Expected unchanged order without pins, got %s first", got[0].Project.Path)
+	}
+
+	// Pinned projects the query doesn't match are added from the lookup
+	lookup := func(path string) (model.Project, bool) {
+		return model.Project{Path: path}, path == "e/five"
+	}
+	result = ApplyPins(matches, []string{"e/five", "missing/project", "b/two"}, lookup)
+	want = []string{"e/five", "b/two", "a/one", "c/three", "d/four"}
+	if len(result) != len(want) {
+		t.Fatalf("Expected %d results with the lookup, got %d", len(want), len(result))
+	}
+	for i, path := range want {
+		if result[i].Project.Path != path {
+			t.Errorf("result[%d] = %s, want %s", i, result[i].Project.Path, path)
+		}
+	}
+	if !result[0].Pinned {
+		t.Error("Expected the added project to be marked as pinned")
+	}
+	if got := ApplyPins(nil, []string{"e/five"}, lookup); len(got) != 1 || got[0].Project.Path != "e/five" {
+		t.Errorf("Expected the pinned project without any match, got %v", got)
+	}
+}
+
+func TestRegexSearchWithIndex(t *testing.T) {
+	projects := []model.Project{
+		{Path: "platform/cert-operator", Name: "cert-operator"},
+		{Path: "platform/dns-operator", Name: "dns-operator"},
+		{Path: "platform/operator-docs", Name: "operator-docs"},
+		{Path: "infra/db-operator", Name: "db-operator"},
+	}
+	historyScores := map[string]int{"platform/dns-operator": 5}
+
+	results, err := RegexSearchWithIndex("^platform/.*-operator$", projects, historyScores, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("RegexSearchWithIndex failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(results))
+	}
+	// Ordered by history like an empty query
+	if results[0].Project.Path != "platform/dns-operator" || results[1].Project.Path != "platform/cert-operator" {
+		t.Errorf("Unexpected order: %s, %s", results[0].Project.Path, results[1].Project.Path)
+	}
+
+	if _, err := RegexSearchWithIndex("platform/(", projects, historyScores, t.TempDir(), nil); err == nil {
+		t.Error("Expected error for invalid regex")
+	}
+}
@@ -0,0 +1,78 @@
+package search
+
+import (
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/model"
+)
+
+// CollapseForks moves forks out of the results into the Forks of their upstream project
+// (search.collapse_forks), so forks of a common service don't flood the results
+// Forks of forks collapse under the upstream found furthest up the chain. Forks whose upstream
+// is not in the results collapse under the best-ranked fork of the same upstream instead
+// The user's own forks (member projects) and pinned forks stay listed
+func CollapseForks(matches []index.CombinedMatch) []index.CombinedMatch {
+	byPath := make(map[string]int, len(matches))
+	for i, match := range matches {
+		byPath[match.Project.Path] = i
+	}
+
+	// Results a match collapses under are keyed by the path of its root upstream
+	roots := make([]string, len(matches))
+	heads := make(map[string]int)
+	for i, match := range matches {
+		root := forkRoot(match.Project, matches, byPath)
+		roots[i] = root
+		if _, ok := heads[root]; ok {
+			continue
+		}
+		if j, ok := byPath[root]; ok {
+			heads[root] = j
+		} else if collapsible(match) {
+			heads[root] = i
+		}
+	}
+
+	result := make([]index.CombinedMatch, 0, len(matches))
+	headPos := make(map[string]int, len(heads))
+	var forks map[string][]index.CombinedMatch
+	for i, match := range matches {
+		head, ok := heads[roots[i]]
+		if !ok || head == i || !collapsible(match) {
+			if ok && head == i {
+				headPos[roots[i]] = len(result)
+			}
+			result = append(result, match)
+			continue
+		}
+		if forks == nil {
+			forks = make(map[string][]index.CombinedMatch)
+		}
+		forks[roots[i]] = append(forks[roots[i]], match)
+	}
+
+	for root, pos := range headPos {
+		result[pos].Forks = forks[root]
+	}
+	return result
+}
+
+// forkRoot returns the path a project's forks collapse under: its upstream, followed through
+// forks of forks while the upstream is in the results (its own path if it is not a fork)
+func forkRoot(p model.Project, matches []index.CombinedMatch, byPath map[string]int) string {
+	for range matches {
+		if !p.IsFork() {
+			return p.Path
+		}
+		j, ok := byPath[p.ForkedFrom]
+		if !ok {
+			return p.ForkedFrom
+		}
+		p = matches[j].Project
+	}
+	return p.Path // Fork cycle (inconsistent metadata): stop anywhere
+}
+
+// collapsible reports whether a match may be collapsed under another result
+func collapsible(match index.CombinedMatch) bool {
+	return match.Project.IsFork() && !match.Project.Member && !match.Pinned && !match.Remote
+}
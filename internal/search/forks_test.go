@@ -0,0 +1,64 @@
+package search
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/model"
+)
+
+// forkPaths returns the paths of a result's collapsed forks
+func forkPaths(match index.CombinedMatch) []string {
+	paths := make([]string, len(match.Forks))
+	for i, fork := range match.Forks {
+		paths[i] = fork.Project.Path
+	}
+	return paths
+}
+
+func TestCollapseForks(t *testing.T) {
+	matches := []index.CombinedMatch{
+		{Project: model.Project{Path: "alice/auth", ForkedFrom: "platform/auth"}},
+		{Project: model.Project{Path: "platform/auth"}},
+		{Project: model.Project{Path: "me/auth", ForkedFrom: "platform/auth", Member: true}},
+		{Project: model.Project{Path: "bob/auth", ForkedFrom: "alice/auth"}},
+		{Project: model.Project{Path: "team/billing", ForkedFrom: "finance/billing"}},
+		{Project: model.Project{Path: "carol/billing", ForkedFrom: "finance/billing"}},
+		{Project: model.Project{Path: "dave/billing", ForkedFrom: "finance/billing"}, Pinned: true},
+	}
+
+	result := CollapseForks(matches)
+
+	var got []string
+	for _, match := range result {
+		got = append(got, match.Project.Path)
+	}
+	want := []string{"platform/auth", "me/auth", "team/billing", "dave/billing"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("CollapseForks() = %v, want %v", got, want)
+	}
+
+	// Forks of forks collapse under the upstream, in rank order
+	if forks := forkPaths(result[0]); !slices.Equal(forks, []string{"alice/auth", "bob/auth"}) {
+		t.Errorf("platform/auth forks = %v, want alice/auth and bob/auth", forks)
+	}
+	// Without the upstream in the results, the best-ranked fork takes the others
+	if forks := forkPaths(result[2]); !slices.Equal(forks, []string{"carol/billing"}) {
+		t.Errorf("team/billing forks = %v, want carol/billing", forks)
+	}
+	if len(result[1].Forks) != 0 || len(result[3].Forks) != 0 {
+		t.Error("Expected member and pinned forks to stay listed without forks")
+	}
+}
+
+func TestCollapseForks_NoForks(t *testing.T) {
+	matches := []index.CombinedMatch{
+		{Project: model.Project{Path: "group/api"}},
+		{Project: model.Project{Path: "group/web"}},
+	}
+	result := CollapseForks(matches)
+	if len(result) != 2 || result[0].Forks != nil || result[1].Forks != nil {
+		t.Errorf("Expected results without forks to be unchanged, got %+v", result)
+	}
+}
@@ -0,0 +1,88 @@
+package search
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/igusev/glf/internal/translit"
+)
+
+// MatchRange is a matched part of a text, as byte offsets: text[Start:End]
+type MatchRange struct {
+	Start int
+	End   int
+}
+
+// HighlightTerms returns the words of a query highlighted in results: the free text and
+// the name:/desc: values (other filters and exclusions are not highlighted), also in the
+// other spellings the search matches (retyped on the other keyboard layout, transliterated)
+func HighlightTerms(query string) []string {
+	text := ParseQuery(query).SearchText()
+	terms := strings.Fields(text)
+	for _, variant := range translit.Variants(text) {
+		for _, term := range strings.Fields(variant) {
+			if !slices.Contains(terms, term) {
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
+
+// MatchRanges returns the parts of text matching terms: the first case-insensitive occurrence
+// of every term, sorted and with overlapping ranges merged
+// The TUI highlights these ranges, and --vim reports them for editor plugins
+func MatchRanges(text string, terms []string) []MatchRange {
+	var ranges []MatchRange
+	for _, term := range terms {
+		if start, end, ok := indexFold(text, term); ok {
+			ranges = append(ranges, MatchRange{Start: start, End: end})
+		}
+	}
+	if len(ranges) < 2 {
+		return ranges
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End {
+			last.End = max(last.End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// indexFold finds the first case-insensitive occurrence of substr in s and returns its byte
+// offsets in s (which may differ from len(substr) for letters whose cases differ in length)
+func indexFold(s, substr string) (start, end int, ok bool) {
+	if substr == "" {
+		return 0, 0, false
+	}
+	for i := range s {
+		if n := prefixFold(s[i:], substr); n > 0 {
+			return i, i + n, true
+		}
+	}
+	return 0, 0, false
+}
+
+// prefixFold returns the number of bytes of s matching substr case-insensitively, or 0
+// if s doesn't start with substr
+func prefixFold(s, substr string) int {
+	n := 0
+	for _, want := range substr {
+		got, size := utf8.DecodeRuneInString(s[n:])
+		if size == 0 || unicode.ToLower(got) != unicode.ToLower(want) {
+			return 0
+		}
+		n += size
+	}
+	return n
+}
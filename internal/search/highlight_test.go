@@ -0,0 +1,48 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchRanges(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+		want  []MatchRange
+	}{
+		{"single term", "backend/billing-api", []string{"billing"}, []MatchRange{{8, 15}}},
+		{"case insensitive", "Backend/API", []string{"api"}, []MatchRange{{8, 11}}},
+		{"every term, sorted", "backend/billing-api", []string{"api", "back"}, []MatchRange{{0, 4}, {16, 19}}},
+		{"overlapping terms merged", "backend/billing", []string{"back", "kend"}, []MatchRange{{0, 7}}},
+		{"cyrillic byte offsets", "команда/Платежи", []string{"платеж"}, []MatchRange{{15, 27}}},
+		{"no match", "backend/api", []string{"xyz"}, nil},
+		{"no terms", "backend/api", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchRanges(tt.text, tt.terms); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchRanges(%q, %q) = %v, want %v", tt.text, tt.terms, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Filters and exclusions are not highlighted; the other keyboard layout is
+		{"billing name:api -legacy is:starred group:backend", []string{"billing", "api", "ишддштп", "фзш"}},
+		// Cyrillic queries also match their Latin layout and transliteration
+		{"фзш", []string{"фзш", "api", "fzsh"}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := HighlightTerms(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("HighlightTerms(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/igusev/glf/internal/model"
+)
+
+// FilterIssues fuzzy-filters issues by number, title, labels and author
+func FilterIssues(issues []model.Issue, query string) []model.Issue {
+	return fuzzyFilter(issues, query, model.Issue.SearchableString)
+}
+
+// FilterMergeRequests fuzzy-filters merge requests by project path, number, title, labels and author
+func FilterMergeRequests(mrs []model.MergeRequest, query string) []model.MergeRequest {
+	return fuzzyFilter(mrs, query, model.MergeRequest.SearchableString)
+}
+
+// FilterUsers fuzzy-filters users by username and display name
+func FilterUsers(users []model.User, query string) []model.User {
+	return fuzzyFilter(users, strings.TrimPrefix(query, "@"), model.User.SearchableString)
+}
+
+// fuzzyFilter keeps the items whose text matches every query token
+// A token matches as a substring or, failing that, as an in-order subsequence
+// ("lgnrd" matches "login redirect"). Substring matches rank first; ties keep
+// the input order (most recently updated first).
+func fuzzyFilter[T any](items []T, query string, text func(T) string) []T {
+	tokens := strings.Fields(strings.ToLower(query))
+	if len(tokens) == 0 {
+		return items
+	}
+
+	type scored struct {
+		item  T
+		score int
+	}
+	var matches []scored
+	for _, item := range items {
+		lower := strings.ToLower(text(item))
+		score, ok := 0, true
+		for _, token := range tokens {
+			switch {
+			case strings.Contains(lower, token):
+				score += 2
+			case isSubsequence(lower, token):
+				score++
+			default:
+				ok = false
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			matches = append(matches, scored{item: item, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	result := make([]T, len(matches))
+	for i, m := range matches {
+		result[i] = m.item
+	}
+	return result
+}
+
+// isSubsequence reports whether all runes of pattern appear in text in order
+func isSubsequence(text, pattern string) bool {
+	remaining := []rune(pattern)
+	for _, r := range text {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
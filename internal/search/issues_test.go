@@ -0,0 +1,82 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/igusev/glf/internal/model"
+)
+
+func TestFilterIssues(t *testing.T) {
+	issues := []model.Issue{
+		{IID: 12, Title: "Login redirect loops", Author: "bob"},
+		{IID: 7, Title: "Rate limiting for API", Labels: []string{"backend"}},
+		{IID: 3, Title: "Docs: login flow", Labels: []string{"documentation"}},
+	}
+
+	tests := []struct {
+		query string
+		want  []int64
+	}{
+		{"", []int64{12, 7, 3}},
+		{"login", []int64{12, 3}},
+		{"#7", []int64{7}},
+		{"backend", []int64{7}},
+		{"bob", []int64{12}},
+		{"lgn rdr", []int64{12}},     // Subsequence match
+		{"login lgnflw", []int64{3}}, // All tokens must match
+		{"nothing", nil},
+	}
+	for _, tt := range tests {
+		got := FilterIssues(issues, tt.query)
+		if len(got) != len(tt.want) {
+			t.Errorf("FilterIssues(%q) returned %d issues, want %d", tt.query, len(got), len(tt.want))
+			continue
+		}
+		for i, issue := range got {
+			if issue.IID != tt.want[i] {
+				t.Errorf("FilterIssues(%q)[%d] = #%d, want #%d", tt.query, i, issue.IID, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestFilterIssues_SubstringFirst(t *testing.T) {
+	issues := []model.Issue{
+		{IID: 1, Title: "cache eviction"},  // "cev" only as subsequence
+		{IID: 2, Title: "add cev support"}, // "cev" as substring
+	}
+	got := FilterIssues(issues, "cev")
+	if len(got) != 2 || got[0].IID != 2 {
+		t.Errorf("Expected substring match first, got %+v", got)
+	}
+}
+
+func TestFilterUsers(t *testing.T) {
+	users := []model.User{
+		{Username: "jdoe", Name: "Jane Doe"},
+		{Username: "bsmith", Name: "Bob Smith"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"jdoe", "bsmith"}},
+		{"@jdoe", []string{"jdoe"}}, // Leading @ is ignored
+		{"smith", []string{"bsmith"}},
+		{"jane doe", []string{"jdoe"}},
+		{"nobody", nil},
+	}
+	for _, tt := range tests {
+		got := FilterUsers(users, tt.query)
+		if len(got) != len(tt.want) {
+			t.Errorf("FilterUsers(%q) returned %d users, want %d", tt.query, len(got), len(tt.want))
+			continue
+		}
+		for i, user := range got {
+			if user.Username != tt.want[i] {
+				t.Errorf("FilterUsers(%q)[%d] = %s, want %s", tt.query, i, user.Username, tt.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package search
+
+import "github.com/igusev/glf/internal/annotations"
+
+// projectAnnotations holds the labels of annotations.yaml (set by SetAnnotations)
+var projectAnnotations annotations.Annotations
+
+// SetAnnotations sets the project labels used by label: filters and shown by Labels
+func SetAnnotations(a annotations.Annotations) {
+	projectAnnotations = a
+}
+
+// Labels returns the labels of a project from annotations.yaml (nil if it has none)
+func Labels(projectPath string) []string {
+	return projectAnnotations.Labels(projectPath)
+}
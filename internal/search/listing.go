@@ -0,0 +1,81 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/model"
+)
+
+// GroupListingPrefix returns the group of a directory listing query: a single path ending
+// with "/" ("backend/payments/"), without the slash. Returns "" for other queries
+func GroupListingPrefix(query string) string {
+	query = strings.TrimSpace(query)
+	if !strings.HasSuffix(query, "/") || strings.ContainsAny(query, " \t:") || strings.HasPrefix(query, "-") {
+		return ""
+	}
+	return strings.Trim(query, "/")
+}
+
+// GroupListing lists the immediate subgroups and projects of group, like a directory:
+// subgroups first, then projects, each sorted by path
+// Subgroup rows carry the subgroup path, a name ending with "/" and the number of projects
+// below them; they count as member (or archived) when any (or every) project below them is
+// The group path matches case-insensitively; ok is false when no project lies under it
+func GroupListing(group string, projects []model.Project) (matches []index.CombinedMatch, ok bool) {
+	group = strings.Trim(group, "/")
+	if group == "" {
+		return nil, false
+	}
+
+	subgroups := make(map[string]*model.Project)
+	counts := make(map[string]int)
+	var direct []index.CombinedMatch
+	for _, p := range projects {
+		if len(p.Path) <= len(group)+1 || p.Path[len(group)] != '/' || !strings.EqualFold(p.Path[:len(group)], group) {
+			continue
+		}
+		rest := p.Path[len(group)+1:]
+		name, _, nested := strings.Cut(rest, "/")
+		if !nested {
+			direct = append(direct, index.CombinedMatch{Project: p})
+			continue
+		}
+
+		path := p.Path[:len(group)+1+len(name)]
+		sub, seen := subgroups[path]
+		if !seen {
+			sub = &model.Project{Path: path, Name: name + "/", Archived: true}
+			subgroups[path] = sub
+		}
+		counts[path]++
+		sub.Member = sub.Member || p.Member
+		sub.Archived = sub.Archived && p.Archived
+	}
+	if len(subgroups) == 0 && len(direct) == 0 {
+		return nil, false
+	}
+
+	matches = make([]index.CombinedMatch, 0, len(subgroups)+len(direct))
+	for path, sub := range subgroups {
+		sub.Description = pluralProjects(counts[path])
+		matches = append(matches, index.CombinedMatch{Project: *sub, Subgroup: true})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return strings.ToLower(matches[i].Project.Path) < strings.ToLower(matches[j].Project.Path)
+	})
+	sort.Slice(direct, func(i, j int) bool {
+		return strings.ToLower(direct[i].Project.Path) < strings.ToLower(direct[j].Project.Path)
+	})
+	return append(matches, direct...), true
+}
+
+// pluralProjects returns "1 project" or "N projects"
+func pluralProjects(n int) string {
+	if n == 1 {
+		return "1 project"
+	}
+	return fmt.Sprintf("%d projects", n)
+}
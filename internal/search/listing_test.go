@@ -0,0 +1,66 @@
+package search
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/igusev/glf/internal/model"
+)
+
+func TestGroupListingPrefix(t *testing.T) {
+	tests := map[string]string{
+		"backend/":         "backend",
+		" backend/api/ ":   "backend/api",
+		"backend":          "",
+		"backend/api":      "",
+		"group:backend/":   "",
+		"billing backend/": "",
+		"-backend/":        "",
+		"":                 "",
+	}
+	for query, want := range tests {
+		if got := GroupListingPrefix(query); got != want {
+			t.Errorf("GroupListingPrefix(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestGroupListing(t *testing.T) {
+	projects := []model.Project{
+		{Path: "backend/web", Name: "web"},
+		{Path: "backend/payments/billing", Name: "billing", Member: true},
+		{Path: "backend/payments/ledger", Name: "ledger"},
+		{Path: "backend/api", Name: "api", Member: true},
+		{Path: "backend/legacy/old", Name: "old", Archived: true},
+		{Path: "backend-tools/cli", Name: "cli"},
+		{Path: "frontend/app", Name: "app"},
+	}
+
+	matches, ok := GroupListing("Backend", projects)
+	if !ok {
+		t.Fatal("Expected a listing of backend")
+	}
+	var got []string
+	for _, match := range matches {
+		got = append(got, match.Project.Path)
+	}
+	want := []string{"backend/legacy", "backend/payments", "backend/api", "backend/web"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("GroupListing() = %v, want %v", got, want)
+	}
+
+	payments := matches[1]
+	if !payments.Subgroup || payments.Project.Name != "payments/" || payments.Project.Description != "2 projects" || !payments.Project.Member {
+		t.Errorf("Unexpected subgroup row %+v", payments)
+	}
+	if legacy := matches[0].Project; !legacy.Archived || legacy.Member {
+		t.Errorf("Expected a subgroup of archived projects to count as archived non-member, got %+v", legacy)
+	}
+	if matches[2].Subgroup {
+		t.Error("Expected projects not to be subgroup rows")
+	}
+
+	if _, ok := GroupListing("back", projects); ok {
+		t.Error("Expected no listing for a partial group name")
+	}
+}
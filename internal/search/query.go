@@ -0,0 +1,244 @@
+package search
+
+import (
+	"strings"
+	"time"
+
+	"github.com/igusev/glf/internal/model"
+)
+
+// Query is a search query split into free text and structured filters
+//
+// Syntax (tokens are separated by spaces, prefixes are case-insensitive):
+//
+//	api ingress      free text, searched across name, path and description
+//	name:api         project name contains "api"
+//	desc:ingress     description contains "ingress"
+//	group:platform   project lives in a group named "platform" (any level, or a full path like platform/infra)
+//	topic:golang     project has the topic "golang"
+//	label:tier-1     project has the label "tier-1" in annotations.yaml (values may contain ':', e.g. label:owned-by:payments)
+//	is:starred       only starred projects (also is:archived, is:member, is:fork)
+//	is:active        active in the last 30 days (also is:quiet, is:stale; see model.Activity)
+//	-is:archived     exclude archived projects (shorthand: -archived, -starred, -member, -stale, ...)
+//	-group:infra     negate any field filter
+//	-legacy          exclude projects whose name, path or description contains "legacy"
+type Query struct {
+	Text string // Free text part (passed to the full-text index)
+
+	Names  []string // name: filters
+	Descs  []string // desc: filters
+	Groups []string // group: filters
+	Topics []string // topic: filters
+	Labels []string // label: filters
+
+	ExcludeTerms  []string // -term exclusions
+	ExcludeNames  []string // -name: exclusions
+	ExcludeDescs  []string // -desc: exclusions
+	ExcludeGroups []string // -group: exclusions
+	ExcludeTopics []string // -topic: exclusions
+	ExcludeLabels []string // -label: exclusions
+
+	Is    map[string]bool // Required project states (is:starred, is:archived, is:member, is:active, ...)
+	IsNot map[string]bool // Excluded project states (-is:archived, -archived)
+}
+
+// projectStates are the values accepted by is: filters
+var projectStates = map[string]func(model.Project) bool{
+	"starred":  func(p model.Project) bool { return p.Starred },
+	"archived": func(p model.Project) bool { return p.Archived },
+	"member":   func(p model.Project) bool { return p.Member },
+	"fork":     model.Project.IsFork,
+	"active":   hasActivity(model.ActivityActive),
+	"quiet":    hasActivity(model.ActivityQuiet),
+	"stale":    hasActivity(model.ActivityStale),
+}
+
+// hasActivity returns an is: filter matching projects in the given activity bucket
+// Projects whose last activity is unknown match none of them
+func hasActivity(activity model.Activity) func(model.Project) bool {
+	return func(p model.Project) bool { return p.Activity(time.Now()) == activity }
+}
+
+// ParseQuery splits a raw query into free text and structured filters
+// Unknown prefixes (e.g., "foo:bar") are kept as free text
+func ParseQuery(raw string) Query {
+	q := Query{Is: map[string]bool{}, IsNot: map[string]bool{}}
+	var text []string
+
+	for _, token := range strings.Fields(raw) {
+		negated := false
+		body := token
+		if len(body) > 1 && body[0] == '-' {
+			negated = true
+			body = body[1:]
+		}
+
+		key, value, hasPrefix := strings.Cut(body, ":")
+		key = strings.ToLower(key)
+		value = strings.ToLower(value)
+
+		if !hasPrefix || value == "" {
+			lower := strings.ToLower(body)
+			switch {
+			case negated && projectStates[lower] != nil:
+				q.IsNot[lower] = true
+			case negated:
+				q.ExcludeTerms = append(q.ExcludeTerms, lower)
+			default:
+				text = append(text, token)
+			}
+			continue
+		}
+
+		switch key {
+		case "name":
+			appendFilter(&q.Names, &q.ExcludeNames, value, negated)
+		case "desc", "description":
+			appendFilter(&q.Descs, &q.ExcludeDescs, value, negated)
+		case "group":
+			appendFilter(&q.Groups, &q.ExcludeGroups, strings.Trim(value, "/"), negated)
+		case "topic":
+			appendFilter(&q.Topics, &q.ExcludeTopics, value, negated)
+		case "label":
+			appendFilter(&q.Labels, &q.ExcludeLabels, value, negated)
+		case "is":
+			if projectStates[value] == nil {
+				text = append(text, token)
+				continue
+			}
+			if negated {
+				q.IsNot[value] = true
+			} else {
+				q.Is[value] = true
+			}
+		default:
+			text = append(text, token)
+		}
+	}
+
+	q.Text = strings.Join(text, " ")
+	return q
+}
+
+// appendFilter adds value to the include or exclude list
+func appendFilter(include, exclude *[]string, value string, negated bool) {
+	if negated {
+		*exclude = append(*exclude, value)
+	} else {
+		*include = append(*include, value)
+	}
+}
+
+// HasFilters reports whether the query uses any structured syntax
+func (q Query) HasFilters() bool {
+	return len(q.Names)+len(q.Descs)+len(q.Groups)+len(q.Topics)+len(q.Labels)+
+		len(q.ExcludeTerms)+len(q.ExcludeNames)+len(q.ExcludeDescs)+len(q.ExcludeGroups)+len(q.ExcludeTopics)+len(q.ExcludeLabels)+
+		len(q.Is)+len(q.IsNot) > 0
+}
+
+// SearchText returns the text used for full-text ranking: free text plus name:/desc: values
+func (q Query) SearchText() string {
+	parts := make([]string, 0, 1+len(q.Names)+len(q.Descs))
+	if q.Text != "" {
+		parts = append(parts, q.Text)
+	}
+	parts = append(parts, q.Names...)
+	parts = append(parts, q.Descs...)
+	return strings.Join(parts, " ")
+}
+
+// Matches reports whether the project satisfies every structured filter
+// Free text is not checked here (it is handled by the full-text index)
+func (q Query) Matches(p model.Project) bool {
+	name := strings.ToLower(p.Name)
+	desc := strings.ToLower(p.Description)
+	path := strings.ToLower(p.Path)
+
+	for _, v := range q.Names {
+		if !strings.Contains(name, v) {
+			return false
+		}
+	}
+	for _, v := range q.ExcludeNames {
+		if strings.Contains(name, v) {
+			return false
+		}
+	}
+	for _, v := range q.Descs {
+		if !strings.Contains(desc, v) {
+			return false
+		}
+	}
+	for _, v := range q.ExcludeDescs {
+		if strings.Contains(desc, v) {
+			return false
+		}
+	}
+	for _, v := range q.Groups {
+		if !inGroup(path, v) {
+			return false
+		}
+	}
+	for _, v := range q.ExcludeGroups {
+		if inGroup(path, v) {
+			return false
+		}
+	}
+	for _, v := range q.Topics {
+		if !hasTopic(p.Topics, v) {
+			return false
+		}
+	}
+	for _, v := range q.ExcludeTopics {
+		if hasTopic(p.Topics, v) {
+			return false
+		}
+	}
+	for _, v := range q.Labels {
+		if !projectAnnotations.HasLabel(p.Path, v) {
+			return false
+		}
+	}
+	for _, v := range q.ExcludeLabels {
+		if projectAnnotations.HasLabel(p.Path, v) {
+			return false
+		}
+	}
+	for _, v := range q.ExcludeTerms {
+		if strings.Contains(name, v) || strings.Contains(path, v) || strings.Contains(desc, v) {
+			return false
+		}
+	}
+	for state := range q.Is {
+		if !projectStates[state](p) {
+			return false
+		}
+	}
+	for state := range q.IsNot {
+		if projectStates[state](p) {
+			return false
+		}
+	}
+	return true
+}
+
+// inGroup reports whether the project path lives below a group with the given name or path
+// "platform" matches "platform/api" and "company/platform/infra/api"; "platform/infra" matches nested paths only
+func inGroup(projectPath, group string) bool {
+	idx := strings.LastIndex(projectPath, "/")
+	if idx < 0 || group == "" {
+		return false
+	}
+	namespace := "/" + projectPath[:idx] + "/"
+	return strings.Contains(namespace, "/"+group+"/")
+}
+
+// hasTopic reports whether topics contains topic (case-insensitive)
+func hasTopic(topics []string, topic string) bool {
+	for _, t := range topics {
+		if strings.EqualFold(t, topic) {
+			return true
+		}
+	}
+	return false
+}
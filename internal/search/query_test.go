@@ -0,0 +1,194 @@
+package search
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/igusev/glf/internal/annotations"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/model"
+)
+
+func TestParseQuery(t *testing.T) {
+	q := ParseQuery("api Name:Auth desc:ingress group:platform -group:infra topic:golang is:starred -archived -legacy foo:bar")
+
+	if q.Text != "api foo:bar" {
+		t.Errorf("Text = %q, want %q", q.Text, "api foo:bar")
+	}
+	if !reflect.DeepEqual(q.Names, []string{"auth"}) {
+		t.Errorf("Names = %v, want [auth]", q.Names)
+	}
+	if !reflect.DeepEqual(q.Descs, []string{"ingress"}) {
+		t.Errorf("Descs = %v, want [ingress]", q.Descs)
+	}
+	if !reflect.DeepEqual(q.Groups, []string{"platform"}) || !reflect.DeepEqual(q.ExcludeGroups, []string{"infra"}) {
+		t.Errorf("Groups = %v, ExcludeGroups = %v", q.Groups, q.ExcludeGroups)
+	}
+	if !reflect.DeepEqual(q.Topics, []string{"golang"}) {
+		t.Errorf("Topics = %v, want [golang]", q.Topics)
+	}
+	if !q.Is["starred"] || !q.IsNot["archived"] {
+		t.Errorf("Is = %v, IsNot = %v", q.Is, q.IsNot)
+	}
+	if !reflect.DeepEqual(q.ExcludeTerms, []string{"legacy"}) {
+		t.Errorf("ExcludeTerms = %v, want [legacy]", q.ExcludeTerms)
+	}
+	if got := q.SearchText(); got != "api foo:bar auth ingress" {
+		t.Errorf("SearchText() = %q", got)
+	}
+}
+
+func TestParseQuery_PlainText(t *testing.T) {
+	for _, raw := range []string{"api gateway", "", "-", "is:unknown", "name:"} {
+		if q := ParseQuery(raw); q.HasFilters() {
+			t.Errorf("ParseQuery(%q).HasFilters() = true, want false", raw)
+		}
+	}
+}
+
+func TestQueryMatches(t *testing.T) {
+	project := model.Project{
+		Path:        "company/platform/api-gateway",
+		Name:        "api-gateway",
+		Description: "Ingress for public APIs",
+		Topics:      []string{"Golang", "kubernetes"},
+		Starred:     true,
+		Member:      true,
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"name:api", true},
+		{"name:web", false},
+		{"-name:api", false},
+		{"desc:ingress", true},
+		{"group:platform", true},
+		{"group:company/platform", true},
+		{"group:plat", false}, // Whole group names only
+		{"group:api-gateway", false},
+		{"-group:infra", true},
+		{"topic:golang", true},
+		{"topic:rust", false},
+		{"-topic:kubernetes", false},
+		{"is:starred", true},
+		{"is:archived", false},
+		{"-archived", true},
+		{"-member", false},
+		{"-public", false}, // Excluded term found in description
+		{"-legacy", true},
+		{"is:active", false}, // Last activity unknown
+		{"-stale", true},
+		{"is:fork", false},
+		{"-is:fork", true},
+	}
+	for _, tt := range tests {
+		if got := ParseQuery(tt.query).Matches(project); got != tt.want {
+			t.Errorf("ParseQuery(%q).Matches() = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestQueryMatches_Activity(t *testing.T) {
+	active := model.Project{Path: "group/api", LastActivityAt: time.Now().Add(-time.Hour)}
+	quiet := model.Project{Path: "group/web", LastActivityAt: time.Now().AddDate(0, -3, 0)}
+	stale := model.Project{Path: "forks/api", LastActivityAt: time.Now().AddDate(-3, 0, 0)}
+
+	tests := []struct {
+		query string
+		want  []bool // active, quiet, stale
+	}{
+		{"is:active", []bool{true, false, false}},
+		{"is:quiet", []bool{false, true, false}},
+		{"is:stale", []bool{false, false, true}},
+		{"-stale", []bool{true, true, false}},
+		{"-is:active", []bool{false, true, true}},
+	}
+	for _, tt := range tests {
+		q := ParseQuery(tt.query)
+		for i, p := range []model.Project{active, quiet, stale} {
+			if got := q.Matches(p); got != tt.want[i] {
+				t.Errorf("ParseQuery(%q).Matches(%s) = %v, want %v", tt.query, p.Path, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestCombinedSearchWithIndex_Filters(t *testing.T) {
+	tmpDir := t.TempDir()
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tmpDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create test index: %v", err)
+	}
+	defer descIndex.Close()
+
+	projects := []model.Project{
+		{Path: "platform/api", Name: "api", Description: "Public API", Member: true},
+		{Path: "infra/api", Name: "api", Description: "Internal API", Member: true, Archived: true},
+		{Path: "platform/web", Name: "web", Description: "Frontend", Member: true, Starred: true, Topics: []string{"react"}},
+	}
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, p := range projects {
+		docs = append(docs, index.NewDocument(p))
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to add test docs: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"name:api -group:infra", []string{"platform/api"}},
+		{"api -archived", []string{"platform/api"}},
+		{"is:starred", []string{"platform/web"}},
+		{"topic:react", []string{"platform/web"}},
+		{"group:platform -name:web", []string{"platform/api"}},
+	}
+	for _, tt := range tests {
+		// nil projects: project data (including topics) comes from Bleve stored fields
+		results, err := CombinedSearchWithIndex(tt.query, nil, map[string]int{}, tmpDir, descIndex)
+		if err != nil {
+			t.Fatalf("CombinedSearchWithIndex(%q) failed: %v", tt.query, err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Project.Path)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CombinedSearchWithIndex(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestQueryMatches_Labels(t *testing.T) {
+	SetAnnotations(annotations.Annotations{
+		"platform":     {"owned-by:platform"},
+		"platform/api": {"tier-1"},
+	})
+	t.Cleanup(func() { SetAnnotations(nil) })
+
+	api := model.Project{Path: "platform/api"}
+	proxy := model.Project{Path: "platform/proxy"}
+
+	tests := []struct {
+		query   string
+		project model.Project
+		want    bool
+	}{
+		{"label:tier-1", api, true},
+		{"label:TIER-1", api, true},
+		{"label:tier-1", proxy, false},
+		{"label:owned-by:platform", proxy, true}, // Inherited from the group
+		{"-label:tier-1", api, false},
+		{"-label:tier-1", proxy, true},
+	}
+	for _, tt := range tests {
+		if got := ParseQuery(tt.query).Matches(tt.project); got != tt.want {
+			t.Errorf("ParseQuery(%q).Matches(%s) = %v, want %v", tt.query, tt.project.Path, got, tt.want)
+		}
+	}
+}
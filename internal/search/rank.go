@@ -0,0 +1,50 @@
+package search
+
+import (
+	"sort"
+
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/model"
+)
+
+// starredProjectBonus is added to the score of starred projects (scaled like the history score)
+const starredProjectBonus = 3
+
+// rankMatch scores a project: search relevance plus path bonus, plus the history and starred
+// bonuses scaled by multiplier, minus the archived and fork penalties (never scaled)
+// Every search path (full-text, empty query, regex) ranks projects through this function
+func rankMatch(p model.Project, historyScores map[string]int, searchScore, pathBonus, multiplier float64) index.CombinedMatch {
+	historyScore, ok := historyScores[p.Key()]
+	if !ok {
+		historyScore = historyScores[p.Path] // Recorded before history was keyed by project ID
+	}
+
+	starredBonus := 0
+	if p.Starred {
+		starredBonus = starredProjectBonus
+	}
+
+	penalty := projectPenalty(p)
+	forkedPenalty := projectForkPenalty(p)
+	totalScore := searchScore + pathBonus +
+		float64(historyScore)*multiplier + float64(starredBonus)*multiplier -
+		penalty - forkedPenalty
+
+	return index.CombinedMatch{
+		Project:         p,
+		SearchScore:     searchScore,
+		PathBonus:       pathBonus,
+		HistoryScore:    historyScore,
+		StarredBonus:    starredBonus,
+		ArchivedPenalty: penalty,
+		ForkPenalty:     forkedPenalty,
+		TotalScore:      totalScore,
+	}
+}
+
+// sortByScore orders matches by total score, highest first
+func sortByScore(matches []index.CombinedMatch) {
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].TotalScore > matches[j].TotalScore
+	})
+}
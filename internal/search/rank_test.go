@@ -0,0 +1,58 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/model"
+)
+
+func TestRankMatch(t *testing.T) {
+	SetArchivedPenalty(10)
+	SetForkPenalty(0)
+	t.Cleanup(func() { SetArchivedPenalty(0) })
+
+	history := map[string]int{"group/api": 4}
+
+	m := rankMatch(model.Project{Path: "group/api", Starred: true}, history, 1.0, 0.5, 0.5)
+	if m.HistoryScore != 4 || m.StarredBonus != starredProjectBonus {
+		t.Errorf("HistoryScore = %d, StarredBonus = %d, want 4 and %d", m.HistoryScore, m.StarredBonus, starredProjectBonus)
+	}
+	// 1.0 + 0.5 + (4 + 3) * 0.5
+	if m.TotalScore != 5.0 {
+		t.Errorf("TotalScore = %v, want 5", m.TotalScore)
+	}
+
+	// The archived penalty is not scaled by relevance
+	m = rankMatch(model.Project{Path: "group/old", Archived: true}, history, 1.0, 0, 0)
+	if m.ArchivedPenalty != 10 || m.TotalScore != -9.0 {
+		t.Errorf("ArchivedPenalty = %v, TotalScore = %v, want 10 and -9", m.ArchivedPenalty, m.TotalScore)
+	}
+}
+
+func TestRankMatch_HistoryKey(t *testing.T) {
+	history := map[string]int{"id:7": 5, "group/legacy": 2}
+
+	// Selections are found by project ID after a rename
+	if m := rankMatch(model.Project{ID: 7, Path: "platform/api"}, history, 0, 0, 1); m.HistoryScore != 5 {
+		t.Errorf("HistoryScore by ID = %d, want 5", m.HistoryScore)
+	}
+	// Selections recorded by path before the history was keyed by ID still count
+	if m := rankMatch(model.Project{ID: 8, Path: "group/legacy"}, history, 0, 0, 1); m.HistoryScore != 2 {
+		t.Errorf("HistoryScore by path = %d, want 2", m.HistoryScore)
+	}
+}
+
+func TestSortByScore(t *testing.T) {
+	matches := []index.CombinedMatch{
+		{Project: model.Project{Path: "a"}, TotalScore: 1},
+		{Project: model.Project{Path: "b"}, TotalScore: 3},
+		{Project: model.Project{Path: "c"}, TotalScore: 2},
+	}
+	sortByScore(matches)
+	for i, want := range []string{"b", "c", "a"} {
+		if matches[i].Project.Path != want {
+			t.Errorf("matches[%d] = %s, want %s", i, matches[i].Project.Path, want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package search
+
+import (
+	"strings"
+
+	"github.com/igusev/glf/internal/translit"
+)
+
+// pathSegmentBoost is the bonus of a query whose tokens each start a distinct path segment,
+// in path order ("plat api" → platform/api-gateway); scattered matches get a fraction of it
+const pathSegmentBoost = 0.5
+
+// Weights of a token matched against a path segment
+const (
+	segmentPrefixWeight = 1.0  // Token starts the segment ("api" in "api-gateway")
+	wordPrefixWeight    = 0.75 // Token starts a word inside the segment ("gate" in "api-gateway")
+	outOfOrderFactor    = 0.5  // Token matches a segment before the previous token's segment
+)
+
+// PathSegmentScore rates how well query tokens line up with the segments of a project path,
+// from 0 (no token starts a segment or word) to 1 (every token starts its own segment, in order)
+// Each segment is matched by one token at most, so "api api" does not count "api-gateway" twice
+func PathSegmentScore(tokens []string, projectPath string) float64 {
+	if len(tokens) == 0 {
+		return 0
+	}
+	segments := strings.Split(strings.ToLower(strings.Trim(projectPath, "/")), "/")
+	used := make([]bool, len(segments))
+
+	var total float64
+	next := 0 // Segments before next precede the previous token's segment
+	for _, token := range tokens {
+		token = strings.ToLower(token)
+		best, weight := bestSegment(token, segments, used, next, len(segments))
+		if best < 0 {
+			best, weight = bestSegment(token, segments, used, 0, next)
+			weight *= outOfOrderFactor
+		}
+		if best < 0 {
+			continue
+		}
+		used[best] = true
+		total += weight
+		next = max(next, best+1)
+	}
+	return total / float64(len(tokens))
+}
+
+// pathSpellings returns the tokens of a query and of its Cyrillic/Latin variants
+// (the index searches them too, so "фзш" earns the bonus of "api")
+func pathSpellings(query string) [][]string {
+	spellings := [][]string{strings.Fields(strings.ToLower(query))}
+	for _, variant := range translit.Variants(query) {
+		spellings = append(spellings, strings.Fields(variant))
+	}
+	return spellings
+}
+
+// bestPathSegmentScore returns the highest PathSegmentScore of the spellings of a query
+func bestPathSegmentScore(spellings [][]string, projectPath string) float64 {
+	var best float64
+	for _, tokens := range spellings {
+		best = max(best, PathSegmentScore(tokens, projectPath))
+	}
+	return best
+}
+
+// bestSegment returns the first unused segment in [from, to) matched best by token, and its weight
+// Returns -1 if no segment matches
+func bestSegment(token string, segments []string, used []bool, from, to int) (int, float64) {
+	best, bestWeight := -1, 0.0
+	for i := from; i < to; i++ {
+		if used[i] {
+			continue
+		}
+		if weight := segmentWeight(token, segments[i]); weight > bestWeight {
+			best, bestWeight = i, weight
+			if weight == segmentPrefixWeight {
+				break
+			}
+		}
+	}
+	return best, bestWeight
+}
+
+// segmentWeight rates a token against one path segment
+func segmentWeight(token, segment string) float64 {
+	if strings.HasPrefix(segment, token) {
+		return segmentPrefixWeight
+	}
+	for _, word := range strings.FieldsFunc(segment, isWordSeparator) {
+		if strings.HasPrefix(word, token) {
+			return wordPrefixWeight
+		}
+	}
+	return 0
+}
+
+// isWordSeparator reports whether r separates words inside a path segment
+func isWordSeparator(r rune) bool {
+	return r == '-' || r == '_' || r == '.'
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/model"
+)
+
+func TestPathSegmentScore(t *testing.T) {
+	tests := []struct {
+		tokens []string
+		path   string
+		want   float64
+	}{
+		{[]string{"plat", "api"}, "platform/api-gateway", 1},
+		{[]string{"PLAT", "Api"}, "Platform/API-Gateway", 1},
+		{[]string{"plat", "gate"}, "platform/api-gateway", (segmentPrefixWeight + wordPrefixWeight) / 2},
+		{[]string{"plat", "api"}, "api/platform-tools", (segmentPrefixWeight + segmentPrefixWeight*outOfOrderFactor) / 2},
+		{[]string{"api", "api"}, "platform/api-gateway", 0.5},             // One segment per token
+		{[]string{"lat", "pi"}, "platform/api-gateway", 0},                // Scattered characters
+		{[]string{"plat", "api"}, "misc/platform-api-docs", 0.5},          // Both tokens in one segment
+		{[]string{"backend", "auth"}, "company/backend/services/auth", 1}, // Skipped segments are fine
+		{nil, "platform/api-gateway", 0},
+	}
+	for _, tt := range tests {
+		if got := PathSegmentScore(tt.tokens, tt.path); got != tt.want {
+			t.Errorf("PathSegmentScore(%q, %q) = %v, want %v", tt.tokens, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPathSegmentRanking(t *testing.T) {
+	tmpDir := t.TempDir()
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tmpDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create test index: %v", err)
+	}
+	defer descIndex.Close()
+
+	projects := []model.Project{
+		{Path: "api/platform-tools", Name: "platform-tools"},
+		{Path: "platform/api-gateway", Name: "api-gateway"},
+		{Path: "tools/api-platform-bridge", Name: "api-platform-bridge"},
+	}
+	docs := make([]index.DescriptionDocument, len(projects))
+	for i, p := range projects {
+		docs[i] = index.NewDocument(p)
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to add test docs: %v", err)
+	}
+
+	results, err := CombinedSearchWithIndex("plat api", nil, nil, tmpDir, descIndex)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) == 0 || results[0].Project.Path != "platform/api-gateway" {
+		t.Fatalf("Expected platform/api-gateway first, got %+v", results)
+	}
+	if results[0].PathBonus != pathSegmentBoost {
+		t.Errorf("Expected the full path segment bonus, got %v", results[0].PathBonus)
+	}
+
+	// Tokens with punctuation match whole segments
+	results, err = CombinedSearchWithIndex("api-gate", nil, nil, tmpDir, descIndex)
+	if err != nil || len(results) == 0 || results[0].Project.Path != "platform/api-gateway" {
+		t.Errorf("Expected api-gate to find platform/api-gateway first, got %+v (%v)", results, err)
+	}
+}
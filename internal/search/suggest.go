@@ -0,0 +1,106 @@
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/igusev/glf/internal/model"
+)
+
+// DefaultSuggestions is the number of "Did you mean" suggestions shown for a query without results
+const DefaultSuggestions = 3
+
+// Suggest returns up to limit project paths that are closest to a query without results
+// by edit distance, for "Did you mean" hints. The text part of the query is compared with
+// the full path, each path segment and the project name; filters are ignored. Paths that
+// are too far from the query to be a typo are left out, so the result may be empty
+func Suggest(query string, projects []model.Project, limit int) []string {
+	text := strings.ToLower(ParseQuery(query).SearchText())
+	if text == "" || limit <= 0 {
+		return nil
+	}
+	maxDistance := suggestMaxDistance(text)
+
+	type candidate struct {
+		path     string
+		distance int
+	}
+	var candidates []candidate
+	for _, project := range projects {
+		if d := suggestDistance(text, project, maxDistance); d <= maxDistance {
+			candidates = append(candidates, candidate{path: project.Path, distance: d})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].path < candidates[j].path
+	})
+	if len(candidates) == 0 {
+		return nil
+	}
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	paths := make([]string, len(candidates))
+	for i, c := range candidates {
+		paths[i] = c.path
+	}
+	return paths
+}
+
+// suggestMaxDistance allows one edit per three characters of the query (at least one)
+func suggestMaxDistance(text string) int {
+	return max(1, len([]rune(text))/3)
+}
+
+// suggestDistance returns the smallest edit distance between text and the path, its segments
+// and the name of project (maxDistance+1 when none is within maxDistance)
+func suggestDistance(text string, project model.Project, maxDistance int) int {
+	path := strings.ToLower(project.Path)
+	candidates := append(strings.Split(path, "/"), path, strings.ToLower(project.Name))
+
+	best := maxDistance + 1
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		if d := levenshtein(text, c); d < best {
+			best = d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b (insertions, deletions and substitutions)
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// DidYouMean formats suggestions as a hint ("" without suggestions)
+func DidYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return "Did you mean: " + strings.Join(suggestions, ", ") + "?"
+}
@@ -0,0 +1,66 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/igusev/glf/internal/model"
+)
+
+func TestSuggest(t *testing.T) {
+	projects := []model.Project{
+		{Path: "platform/api-gateway", Name: "API Gateway"},
+		{Path: "platform/billing", Name: "billing"},
+		{Path: "web/frontend", Name: "frontend"},
+		{Path: "tools/builder", Name: "builder"},
+		{Path: "archive/billing", Name: "billing"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"biling", []string{"archive/billing", "platform/billing"}}, // Missing letter; ties sorted by path
+		{"frotnend", []string{"web/frontend"}},                      // Transposition (two edits)
+		{"web/frontent", []string{"web/frontend"}},                  // Full path
+		{"api gateway", []string{"platform/api-gateway"}},           // Project name
+		{"buildr is:starred", []string{"tools/builder"}},            // Filters are ignored
+		{"zzz", nil}, // Nothing close enough
+		{"", nil},    // Empty query
+	}
+	for _, tt := range tests {
+		if got := Suggest(tt.query, projects, DefaultSuggestions); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	if got := Suggest("biling", projects, 1); !reflect.DeepEqual(got, []string{"archive/billing"}) {
+		t.Errorf("Expected the limit to apply, got %v", got)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"api", "api", 0},
+		{"сервис", "сервиз", 1}, // Runes, not bytes
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDidYouMean(t *testing.T) {
+	if got := DidYouMean([]string{"a/b", "c/d"}); got != "Did you mean: a/b, c/d?" {
+		t.Errorf("DidYouMean() = %q", got)
+	}
+	if got := DidYouMean(nil); got != "" {
+		t.Errorf("DidYouMean(nil) = %q, want empty", got)
+	}
+}
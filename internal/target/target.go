@@ -0,0 +1,304 @@
+// Package target maps named project sub-pages (merge requests, pipelines, registries, settings) to GitLab URLs
+package target
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/igusev/glf/internal/opener"
+)
+
+// Target describes a GitLab project sub-page that can be opened directly
+type Target struct {
+	Name        string // Short name used on the command line (e.g., "mrs")
+	Suffix      string // URL suffix appended to the project URL (e.g., "-/merge_requests")
+	GroupSuffix string // URL suffix appended to the group URL (empty if groups have no such page)
+	Description string // Human-readable description for pickers and help
+}
+
+// builtinTargets lists the known sub-pages in picker order
+var builtinTargets = []Target{
+	{Name: "mrs", Suffix: "-/merge_requests", GroupSuffix: "-/merge_requests", Description: "Merge requests"},
+	{Name: "issues", Suffix: "-/issues", GroupSuffix: "-/issues", Description: "Issues"},
+	{Name: "pipelines", Suffix: "-/pipelines", Description: "CI/CD pipelines"},
+	{Name: "jobs", Suffix: "-/jobs", Description: "CI/CD jobs"},
+	{Name: "branches", Suffix: "-/branches", Description: "Branches"},
+	{Name: "tags", Suffix: "-/tags", Description: "Tags"},
+	{Name: "commits", Suffix: "-/commits", Description: "Commit history"},
+	{Name: "wiki", Suffix: "-/wikis", GroupSuffix: "-/wikis", Description: "Wiki"},
+	{Name: "releases", Suffix: "-/releases", Description: "Releases"},
+	{Name: "environments", Suffix: "-/environments", Description: "Environments and deployments"},
+	{Name: "registry", Suffix: "container_registry", GroupSuffix: "-/container_registries", Description: "Container registry"},
+	{Name: "packages", Suffix: "-/packages", GroupSuffix: "-/packages", Description: "Package registry"},
+	{Name: "settings", Suffix: "edit", GroupSuffix: "-/edit", Description: "General settings"},
+	{Name: "settings/ci_cd", Suffix: "-/settings/ci_cd", GroupSuffix: "-/settings/ci_cd", Description: "CI/CD settings"},
+	{Name: "settings/repository", Suffix: "-/settings/repository", GroupSuffix: "-/settings/repository", Description: "Repository settings"},
+	{Name: "members", Suffix: "-/project_members", GroupSuffix: "-/group_members", Description: "Project members"},
+}
+
+// aliases maps alternative spellings to canonical target names
+var aliases = map[string]string{
+	"mr":                 "mrs",
+	"merge_requests":     "mrs",
+	"merge-requests":     "mrs",
+	"pipeline":           "pipelines",
+	"ci":                 "pipelines",
+	"issue":              "issues",
+	"branch":             "branches",
+	"tag":                "tags",
+	"commit":             "commits",
+	"wikis":              "wiki",
+	"release":            "releases",
+	"env":                "environments",
+	"envs":               "environments",
+	"environment":        "environments",
+	"deployments":        "environments",
+	"container_registry": "registry",
+	"container-registry": "registry",
+	"images":             "registry",
+	"package":            "packages",
+	"ci_cd":              "settings/ci_cd",
+	"settings/ci":        "settings/ci_cd",
+	"repository":         "settings/repository",
+}
+
+// environmentPrefix starts a target naming one environment ("env:production")
+const environmentPrefix = "env:"
+
+// Environment returns the environment named by an "env:<name>" target
+// Such targets open the environment's external URL, which needs a live GitLab lookup
+func Environment(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if len(name) < len(environmentPrefix) || !strings.EqualFold(name[:len(environmentPrefix)], environmentPrefix) {
+		return "", false
+	}
+	env := strings.TrimSpace(name[len(environmentPrefix):])
+	return env, env != ""
+}
+
+// All returns the built-in targets in picker order
+func All() []Target {
+	result := make([]Target, len(builtinTargets))
+	copy(result, builtinTargets)
+	return result
+}
+
+// Names returns the sorted list of canonical target names
+func Names() []string {
+	names := make([]string, 0, len(builtinTargets))
+	for _, t := range builtinTargets {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup resolves a target name (or alias) to a Target
+// Names are case-insensitive and surrounding slashes are ignored
+func Lookup(name string) (Target, error) {
+	normalized := strings.Trim(strings.ToLower(strings.TrimSpace(name)), "/")
+	if canonical, ok := aliases[normalized]; ok {
+		normalized = canonical
+	}
+
+	for _, t := range builtinTargets {
+		if t.Name == normalized {
+			return t, nil
+		}
+	}
+
+	return Target{}, fmt.Errorf("unknown target %q (available: %s)", name, strings.Join(Names(), ", "))
+}
+
+// ProjectTemplate is the url_templates key of the project page (no target)
+const ProjectTemplate = "project"
+
+// templates holds the url_templates by canonical target name (see SetTemplates)
+var templates map[string]string
+
+// SetTemplates sets the url_templates that override the URLs built by URL: keys are target
+// names (or aliases) and ProjectTemplate, values use the opener placeholders, with {{url}}
+// being the URL GitLab would open. Templates must pass ValidateTemplates
+func SetTemplates(byTarget map[string]string) {
+	normalized := make(map[string]string, len(byTarget))
+	for name, template := range byTarget {
+		if key, err := templateKey(name); err == nil {
+			normalized[key] = template
+		}
+	}
+	templates = normalized
+}
+
+// ValidateTemplates checks the target names and placeholders of url_templates
+func ValidateTemplates(byTarget map[string]string) error {
+	for name, template := range byTarget {
+		if _, err := templateKey(name); err != nil {
+			return err
+		}
+		if err := opener.Validate(template); err != nil {
+			return fmt.Errorf("template %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// templateKey returns the canonical name of a url_templates key
+func templateKey(name string) (string, error) {
+	if strings.EqualFold(strings.TrimSpace(name), ProjectTemplate) {
+		return ProjectTemplate, nil
+	}
+	t, err := Lookup(name)
+	if err != nil {
+		return "", fmt.Errorf("unknown target %q (available: %s, %s)", name, ProjectTemplate, strings.Join(Names(), ", "))
+	}
+	return t.Name, nil
+}
+
+// URL builds the URL of the given target for a project
+// An empty target name returns the project root URL; "env:<name>" returns the environments
+// page filtered by name (see Environment for opening the external URL)
+// Every project page URL is built here, so url_templates (see SetTemplates) apply to all of them
+func URL(baseURL, projectPath, name string) (string, error) {
+	projectURL := strings.TrimSuffix(baseURL, "/") + "/" + strings.Trim(projectPath, "/")
+	if name == "" {
+		return expandTemplate(ProjectTemplate, baseURL, projectPath, projectURL), nil
+	}
+	if env, ok := Environment(name); ok {
+		return expandTemplate("environments", baseURL, projectPath, EnvironmentsURL(baseURL, projectPath, env)), nil
+	}
+
+	t, err := Lookup(name)
+	if err != nil {
+		return "", err
+	}
+	return expandTemplate(t.Name, baseURL, projectPath, projectURL+"/"+t.Suffix), nil
+}
+
+// expandTemplate applies the url_templates entry of a target to pageURL (unchanged without one)
+func expandTemplate(key, baseURL, projectPath, pageURL string) string {
+	template, ok := templates[key]
+	if !ok {
+		return pageURL
+	}
+	values := opener.ProjectValues(baseURL, projectPath)
+	values["url"] = pageURL
+	return opener.Expand(template, values)
+}
+
+// EnvironmentsURL builds the URL of a project's environments page, filtered by env (all if empty)
+// It is what "env:<name>" targets open without a live lookup of the external URL
+func EnvironmentsURL(baseURL, projectPath, env string) string {
+	environmentsURL := strings.TrimSuffix(baseURL, "/") + "/" + strings.Trim(projectPath, "/") + "/-/environments"
+	if env == "" {
+		return environmentsURL
+	}
+	return environmentsURL + "?search=" + url.QueryEscape(env)
+}
+
+// userTargets maps the targets available for users to their dashboard pages
+// (filtered by assignee); other targets have no per-user page
+var userTargets = map[string]string{
+	"mrs":    "dashboard/merge_requests?assignee_username=",
+	"issues": "dashboard/issues?assignee_username=",
+}
+
+// UserURL builds the URL of the given target for a user
+// An empty target name returns the profile page; "mrs" and "issues" open the
+// merge requests and issues assigned to the user
+func UserURL(baseURL, username, name string) (string, error) {
+	base := strings.TrimSuffix(baseURL, "/") + "/"
+	username = strings.TrimPrefix(username, "@")
+	if name == "" {
+		return base + url.PathEscape(username), nil
+	}
+	t, err := Lookup(name)
+	if err != nil {
+		return "", err
+	}
+	page, ok := userTargets[t.Name]
+	if !ok {
+		return "", fmt.Errorf("target %q is not available for users (available: mrs, issues)", t.Name)
+	}
+	return base + page + url.QueryEscape(username), nil
+}
+
+// GroupURL builds the URL of the given target for a group
+// An empty target name returns the group overview page
+func GroupURL(baseURL, groupPath, name string) (string, error) {
+	groupURL := strings.TrimSuffix(baseURL, "/") + "/groups/" + strings.Trim(groupPath, "/")
+	if name == "" {
+		return groupURL, nil
+	}
+	t, err := Lookup(name)
+	if err != nil {
+		return "", err
+	}
+	if t.GroupSuffix == "" {
+		return "", fmt.Errorf("target %q is not available for groups", t.Name)
+	}
+	return groupURL + "/" + t.GroupSuffix, nil
+}
+
+// BlobURL builds the URL of a file in a project at the given ref (branch, tag or commit)
+// An empty ref opens the default branch (HEAD). A trailing ":N", ":N-M" or "#LN" selects lines
+func BlobURL(baseURL, projectPath, ref, filePath string) (string, error) {
+	filePath, lines := splitLines(strings.TrimSpace(filePath))
+	filePath = strings.Trim(strings.TrimPrefix(filePath, "./"), "/")
+	if filePath == "" {
+		return "", fmt.Errorf("file path must not be empty")
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	// GitLab resolves refs with slashes (release/1.0) itself, so slashes stay unescaped
+	blobURL := strings.TrimSuffix(baseURL, "/") + "/" + strings.Trim(projectPath, "/") +
+		"/-/blob/" + escapeSegments(ref) + "/" + escapeSegments(filePath)
+	if lines != "" {
+		blobURL += "#L" + lines
+	}
+	return blobURL, nil
+}
+
+// splitLines separates a line selection ("path:12", "path:12-20" or "path#L12") from a file path
+func splitLines(filePath string) (path, lines string) {
+	if i := strings.LastIndex(filePath, "#L"); i >= 0 {
+		return filePath[:i], filePath[i+2:]
+	}
+	i := strings.LastIndex(filePath, ":")
+	if i < 0 {
+		return filePath, ""
+	}
+	start, end, _ := strings.Cut(filePath[i+1:], "-")
+	if !isDigits(start) || (end != "" && !isDigits(end)) {
+		return filePath, ""
+	}
+	if end != "" {
+		return filePath[:i], start + "-" + end
+	}
+	return filePath[:i], start
+}
+
+// escapeSegments escapes each segment of a slash-separated path
+func escapeSegments(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
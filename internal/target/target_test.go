@@ -0,0 +1,329 @@
+package target
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantName   string
+		wantSuffix string
+		wantErr    bool
+	}{
+		{name: "canonical name", input: "mrs", wantName: "mrs", wantSuffix: "-/merge_requests"},
+		{name: "alias", input: "mr", wantName: "mrs", wantSuffix: "-/merge_requests"},
+		{name: "case insensitive", input: "Pipelines", wantName: "pipelines", wantSuffix: "-/pipelines"},
+		{name: "nested settings page", input: "settings/ci_cd", wantName: "settings/ci_cd", wantSuffix: "-/settings/ci_cd"},
+		{name: "surrounding slashes", input: "/issues/", wantName: "issues", wantSuffix: "-/issues"},
+		{name: "registry alias", input: "container_registry", wantName: "registry", wantSuffix: "container_registry"},
+		{name: "packages alias", input: "package", wantName: "packages", wantSuffix: "-/packages"},
+		{name: "releases alias", input: "release", wantName: "releases", wantSuffix: "-/releases"},
+		{name: "environments alias", input: "env", wantName: "environments", wantSuffix: "-/environments"},
+		{name: "unknown target", input: "nonexistent", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Lookup(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error for %q, got %+v", tt.input, got)
+				}
+				if !strings.Contains(err.Error(), "available:") {
+					t.Errorf("Expected error to list available targets, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", got.Name, tt.wantName)
+			}
+			if got.Suffix != tt.wantSuffix {
+				t.Errorf("Suffix = %q, want %q", got.Suffix, tt.wantSuffix)
+			}
+		})
+	}
+}
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		baseURL     string
+		projectPath string
+		target      string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "project root",
+			baseURL:     "https://gitlab.example.com",
+			projectPath: "group/project",
+			want:        "https://gitlab.example.com/group/project",
+		},
+		{
+			name:        "trailing and leading slashes",
+			baseURL:     "https://gitlab.example.com/",
+			projectPath: "/group/project",
+			want:        "https://gitlab.example.com/group/project",
+		},
+		{
+			name:        "merge requests",
+			baseURL:     "https://gitlab.example.com",
+			projectPath: "group/project",
+			target:      "mrs",
+			want:        "https://gitlab.example.com/group/project/-/merge_requests",
+		},
+		{
+			name:        "ci/cd settings",
+			baseURL:     "https://gitlab.example.com",
+			projectPath: "group/sub/project",
+			target:      "settings/ci_cd",
+			want:        "https://gitlab.example.com/group/sub/project/-/settings/ci_cd",
+		},
+		{
+			name:        "container registry",
+			baseURL:     "https://gitlab.example.com",
+			projectPath: "group/project",
+			target:      "registry",
+			want:        "https://gitlab.example.com/group/project/container_registry",
+		},
+		{
+			name:        "package registry",
+			baseURL:     "https://gitlab.example.com",
+			projectPath: "group/project",
+			target:      "packages",
+			want:        "https://gitlab.example.com/group/project/-/packages",
+		},
+		{
+			name:        "releases",
+			baseURL:     "https://gitlab.example.com",
+			projectPath: "group/project",
+			target:      "releases",
+			want:        "https://gitlab.example.com/group/project/-/releases",
+		},
+		{
+			name:        "environments",
+			baseURL:     "https://gitlab.example.com",
+			projectPath: "group/project",
+			target:      "environments",
+			want:        "https://gitlab.example.com/group/project/-/environments",
+		},
+		{
+			name:        "named environment without a live lookup",
+			baseURL:     "https://gitlab.example.com",
+			projectPath: "group/project",
+			target:      "env:review/feature x",
+			want:        "https://gitlab.example.com/group/project/-/environments?search=review%2Ffeature+x",
+		},
+		{
+			name:        "unknown target",
+			baseURL:     "https://gitlab.example.com",
+			projectPath: "group/project",
+			target:      "bogus",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := URL(tt.baseURL, tt.projectPath, tt.target)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("URL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURL_Templates(t *testing.T) {
+	SetTemplates(map[string]string{
+		"project": "{{url}}/-/tree/main",
+		"MR":      "https://sso.example.com/login?next={{url}}",
+	})
+	t.Cleanup(func() { SetTemplates(nil) })
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"", "https://gitlab.example.com/group/api/-/tree/main"},
+		{"mrs", "https://sso.example.com/login?next=https://gitlab.example.com/group/api/-/merge_requests"},
+		{"issues", "https://gitlab.example.com/group/api/-/issues"}, // No template
+	}
+	for _, tt := range tests {
+		got, err := URL("https://gitlab.example.com/", "group/api", tt.target)
+		if err != nil {
+			t.Fatalf("URL(%q) failed: %v", tt.target, err)
+		}
+		if got != tt.want {
+			t.Errorf("URL(%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTemplates(t *testing.T) {
+	if err := ValidateTemplates(map[string]string{"project": "{{url}}", "ci": "https://proxy/{{path}}"}); err != nil {
+		t.Errorf("Expected valid templates, got %v", err)
+	}
+	if err := ValidateTemplates(map[string]string{"nonexistent": "{{url}}"}); err == nil {
+		t.Error("Expected an error for an unknown target")
+	}
+	if err := ValidateTemplates(map[string]string{"project": "{{branch}}"}); err == nil {
+		t.Error("Expected an error for an unknown placeholder")
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"env:production", "production", true},
+		{"ENV: review/x ", "review/x", true},
+		{"env:", "", false},
+		{"env:  ", "", false},
+		{"env", "", false},
+		{"environments", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Environment(tt.input)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Environment(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestGroupURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{name: "overview", want: "https://gitlab.example.com/groups/company/platform"},
+		{name: "ci/cd settings", target: "ci_cd", want: "https://gitlab.example.com/groups/company/platform/-/settings/ci_cd"},
+		{name: "members", target: "members", want: "https://gitlab.example.com/groups/company/platform/-/group_members"},
+		{name: "container registries", target: "registry", want: "https://gitlab.example.com/groups/company/platform/-/container_registries"},
+		{name: "packages", target: "packages", want: "https://gitlab.example.com/groups/company/platform/-/packages"},
+		{name: "project-only page", target: "pipelines", wantErr: true},
+		{name: "releases are per project", target: "releases", wantErr: true},
+		{name: "unknown target", target: "bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GroupURL("https://gitlab.example.com/", "/company/platform", tt.target)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GroupURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		target   string
+		want     string
+		wantErr  bool
+	}{
+		{name: "profile", username: "jdoe", want: "https://gitlab.example.com/jdoe"},
+		{name: "at prefix", username: "@jdoe", want: "https://gitlab.example.com/jdoe"},
+		{name: "assigned mrs", username: "jdoe", target: "mr", want: "https://gitlab.example.com/dashboard/merge_requests?assignee_username=jdoe"},
+		{name: "assigned issues", username: "j.doe", target: "issues", want: "https://gitlab.example.com/dashboard/issues?assignee_username=j.doe"},
+		{name: "project-only page", username: "jdoe", target: "pipelines", wantErr: true},
+		{name: "unknown target", username: "jdoe", target: "bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UserURL("https://gitlab.example.com/", tt.username, tt.target)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("UserURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamesSorted(t *testing.T) {
+	names := Names()
+	if len(names) != len(All()) {
+		t.Fatalf("Names() returned %d entries, All() returned %d", len(names), len(All()))
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Errorf("Names() not sorted: %q before %q", names[i-1], names[i])
+		}
+	}
+}
+
+func TestBlobURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		filePath string
+		want     string
+		wantErr  bool
+	}{
+		{name: "default branch", ref: "main", filePath: "src/main.go", want: "https://gitlab.example.com/company/api/-/blob/main/src/main.go"},
+		{name: "unknown default branch", filePath: "README.md", want: "https://gitlab.example.com/company/api/-/blob/HEAD/README.md"},
+		{name: "leading ./ and /", ref: "main", filePath: "./docs/", want: "https://gitlab.example.com/company/api/-/blob/main/docs"},
+		{name: "escaped segments", ref: "release/1.0", filePath: "docs/my notes#1.md", want: "https://gitlab.example.com/company/api/-/blob/release/1.0/docs/my%20notes%231.md"},
+		{name: "line", ref: "main", filePath: "main.go:42", want: "https://gitlab.example.com/company/api/-/blob/main/main.go#L42"},
+		{name: "line range", ref: "main", filePath: "main.go:10-20", want: "https://gitlab.example.com/company/api/-/blob/main/main.go#L10-20"},
+		{name: "line anchor", ref: "main", filePath: "main.go#L7", want: "https://gitlab.example.com/company/api/-/blob/main/main.go#L7"},
+		{name: "colon in name", ref: "main", filePath: "a:b.txt", want: "https://gitlab.example.com/company/api/-/blob/main/a:b.txt"},
+		{name: "empty path", ref: "main", filePath: " / ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BlobURL("https://gitlab.example.com/", "/company/api", tt.ref, tt.filePath)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BlobURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
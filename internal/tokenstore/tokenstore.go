@@ -0,0 +1,178 @@
+// Package tokenstore retrieves and stores the GitLab token outside the config file
+// (macOS Keychain, Linux Secret Service, Windows Credential Manager, or an external command)
+package tokenstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Backend names accepted in the gitlab.token_backend config entry
+const (
+	BackendConfig        = "config"         // Token stored in plain text in config.yaml (default)
+	BackendKeychain      = "keychain"       // macOS Keychain via the security CLI
+	BackendSecretService = "secret-service" // Linux Secret Service (GNOME Keyring, KWallet) via secret-tool
+	BackendWinCred       = "wincred"        // Windows Credential Manager via PowerShell PasswordVault
+	BackendCommand       = "command"        // External command printing the token (gitlab.token_command)
+)
+
+// serviceName identifies glf entries in the system secret stores
+const serviceName = "glf"
+
+// commandTimeout bounds how long a secret-store helper may run
+const commandTimeout = 10 * time.Second
+
+// ErrNotFound is returned when the secret store has no token for the account
+var ErrNotFound = errors.New("token not found in secret store")
+
+// runCommand executes a helper binary and returns its stdout (overridable in tests)
+var runCommand = func(stdin string, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	// #nosec G204 -- Helper binaries are fixed per backend; token_command comes from the user's own config
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg != "" {
+			return "", fmt.Errorf("%s: %w (%s)", name, err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return stdout.String(), nil
+}
+
+// Normalize returns the canonical backend name for the given config values
+// A token_command without an explicit backend implies the command backend
+func Normalize(backend, command string) string {
+	backend = strings.ToLower(strings.TrimSpace(backend))
+	switch backend {
+	case "", BackendConfig:
+		if strings.TrimSpace(command) != "" {
+			return BackendCommand
+		}
+		return BackendConfig
+	case "macos", "osxkeychain":
+		return BackendKeychain
+	case "secretservice", "libsecret", "gnome-keyring":
+		return BackendSecretService
+	case "windows", "credential-manager":
+		return BackendWinCred
+	default:
+		return backend
+	}
+}
+
+// IsExternal reports whether the backend keeps the token outside config.yaml
+func IsExternal(backend string) bool {
+	return backend != BackendConfig
+}
+
+// IsWritable reports whether Store supports the backend
+func IsWritable(backend string) bool {
+	switch backend {
+	case BackendKeychain, BackendSecretService, BackendWinCred:
+		return true
+	default:
+		return false
+	}
+}
+
+// Get retrieves the token for account (the GitLab URL) from the given backend
+func Get(backend, account, command string) (string, error) {
+	var out string
+	var err error
+
+	switch backend {
+	case BackendKeychain:
+		out, err = runCommand("", "security", "find-generic-password", "-s", serviceName, "-a", account, "-w")
+	case BackendSecretService:
+		out, err = runCommand("", "secret-tool", "lookup", "service", serviceName, "account", account)
+	case BackendWinCred:
+		out, err = runCommand("", "powershell", "-NoProfile", "-NonInteractive", "-Command", winCredScript(account, ""))
+	case BackendCommand:
+		if strings.TrimSpace(command) == "" {
+			return "", fmt.Errorf("token_backend %q requires gitlab.token_command", BackendCommand)
+		}
+		out, err = runShell(command)
+	case BackendConfig:
+		return "", fmt.Errorf("token backend %q is read from config.yaml directly", backend)
+	default:
+		return "", fmt.Errorf("unknown token backend %q (expected keychain, secret-service, wincred, or command)", backend)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read token from %s: %w", backend, err)
+	}
+
+	token := strings.TrimSpace(out)
+	if token == "" {
+		return "", fmt.Errorf("%w (%s, account %s)", ErrNotFound, backend, account)
+	}
+	return token, nil
+}
+
+// Store saves the token for account (the GitLab URL) in the given backend
+func Store(backend, account, token string) error {
+	var err error
+
+	switch backend {
+	case BackendKeychain:
+		// security -i reads the command from stdin, so the token doesn't show in the process list
+		// (-U updates an existing entry instead of failing)
+		_, err = runCommand(fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n",
+			securityQuote(serviceName), securityQuote(account), securityQuote(token)), "security", "-i")
+	case BackendSecretService:
+		// secret-tool reads the secret from stdin
+		_, err = runCommand(token, "secret-tool", "store", "--label", "glf GitLab token", "service", serviceName, "account", account)
+	case BackendWinCred:
+		_, err = runCommand(token, "powershell", "-NoProfile", "-NonInteractive", "-Command", winCredScript(account, "store"))
+	default:
+		return fmt.Errorf("token backend %q does not support storing tokens", backend)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store token in %s: %w", backend, err)
+	}
+	return nil
+}
+
+// runShell runs a user-provided command line through the platform shell
+func runShell(command string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return runCommand("", "cmd", "/c", command)
+	}
+	return runCommand("", "sh", "-c", command)
+}
+
+// winCredScript builds the PowerShell snippet for the Windows PasswordVault
+// mode "" retrieves the password; mode "store" reads it from stdin and saves it
+func winCredScript(account, mode string) string {
+	// Single quotes are escaped by doubling them inside PowerShell literals
+	quoted := "'" + strings.ReplaceAll(account, "'", "''") + "'"
+	load := "[void][Windows.Security.Credentials.PasswordVault,Windows.Security.Credentials,ContentType=WindowsRuntime];" +
+		"$v = New-Object Windows.Security.Credentials.PasswordVault;"
+	if mode == "store" {
+		return load +
+			"$t = [Console]::In.ReadToEnd().Trim();" +
+			"$v.Add((New-Object Windows.Security.Credentials.PasswordCredential('" + serviceName + "', " + quoted + ", $t)))"
+	}
+	return load +
+		"$c = $v.Retrieve('" + serviceName + "', " + quoted + "); $c.RetrievePassword(); $c.Password"
+}
+
+// securityQuote quotes an argument of a command line read by "security -i"
+func securityQuote(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
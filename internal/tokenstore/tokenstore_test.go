@@ -0,0 +1,121 @@
+package tokenstore
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stubCommand replaces runCommand for the duration of the test and records invocations
+func stubCommand(t *testing.T, output string, err error) *[]string {
+	t.Helper()
+	var calls []string
+	original := runCommand
+	runCommand = func(stdin string, name string, args ...string) (string, error) {
+		calls = append(calls, strings.TrimSpace(name+" "+strings.Join(args, " ")+" <"+stdin))
+		return output, err
+	}
+	t.Cleanup(func() { runCommand = original })
+	return &calls
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		backend string
+		command string
+		want    string
+	}{
+		{"", "", BackendConfig},
+		{"config", "", BackendConfig},
+		{"", "pass show gitlab/token", BackendCommand},
+		{"Keychain", "", BackendKeychain},
+		{"macos", "", BackendKeychain},
+		{"libsecret", "", BackendSecretService},
+		{"credential-manager", "", BackendWinCred},
+		{"keychain", "pass show x", BackendKeychain},
+		{"vault", "", "vault"},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.backend, tt.command); got != tt.want {
+			t.Errorf("Normalize(%q, %q) = %q, want %q", tt.backend, tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	calls := stubCommand(t, "secret-token\n", nil)
+
+	token, err := Get(BackendKeychain, "https://gitlab.example.com", "")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if token != "secret-token" {
+		t.Errorf("token = %q, want %q", token, "secret-token")
+	}
+	if len(*calls) != 1 || !strings.HasPrefix((*calls)[0], "security find-generic-password -s glf -a https://gitlab.example.com") {
+		t.Errorf("unexpected invocation: %v", *calls)
+	}
+
+	if _, err := Get(BackendSecretService, "https://gitlab.example.com", ""); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !strings.HasPrefix((*calls)[1], "secret-tool lookup service glf account https://gitlab.example.com") {
+		t.Errorf("unexpected invocation: %v", (*calls)[1])
+	}
+}
+
+func TestGet_Errors(t *testing.T) {
+	stubCommand(t, "  \n", nil)
+	if _, err := Get(BackendKeychain, "https://gitlab.example.com", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for empty output, got %v", err)
+	}
+
+	if _, err := Get(BackendCommand, "https://gitlab.example.com", ""); err == nil {
+		t.Error("expected error for command backend without token_command")
+	}
+
+	if _, err := Get("vault", "https://gitlab.example.com", ""); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+
+	stubCommand(t, "", errors.New("exit status 44"))
+	if _, err := Get(BackendSecretService, "https://gitlab.example.com", ""); err == nil {
+		t.Error("expected error when helper fails")
+	}
+}
+
+func TestGet_Command(t *testing.T) {
+	// Runs through the real shell
+	token, err := Get(BackendCommand, "https://gitlab.example.com", "printf 'from-command\\n'")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if token != "from-command" {
+		t.Errorf("token = %q, want %q", token, "from-command")
+	}
+}
+
+func TestStore(t *testing.T) {
+	calls := stubCommand(t, "", nil)
+
+	if err := Store(BackendSecretService, "https://gitlab.example.com", "tok"); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if !strings.HasSuffix((*calls)[0], "<tok") {
+		t.Errorf("secret-tool should receive the token on stdin: %v", (*calls)[0])
+	}
+
+	// The keychain gets the token on stdin too, never as an argument visible in ps
+	if err := Store(BackendKeychain, "https://gitlab.example.com", `t"o\k`); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	want := `security -i <add-generic-password -U -s "glf" -a "https://gitlab.example.com" -w "t\"o\\k"`
+	if (*calls)[1] != want {
+		t.Errorf("keychain invocation = %q, want %q", (*calls)[1], want)
+	}
+
+	if err := Store(BackendCommand, "https://gitlab.example.com", "tok"); err == nil {
+		t.Error("expected error storing with read-only command backend")
+	}
+}
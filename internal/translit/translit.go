@@ -0,0 +1,98 @@
+// Package translit converts between Cyrillic and Latin text for searches in mixed-language teams:
+// keyboard layout switching (a query typed on the wrong layout) and Cyrillic → Latin transliteration
+package translit
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ruToEn maps the keys of the Russian ЙЦУКЕН layout to the same keys of the US QWERTY layout
+var ruToEn = map[rune]rune{
+	'й': 'q', 'ц': 'w', 'у': 'e', 'к': 'r', 'е': 't', 'н': 'y', 'г': 'u', 'ш': 'i', 'щ': 'o', 'з': 'p', 'х': '[', 'ъ': ']',
+	'ф': 'a', 'ы': 's', 'в': 'd', 'а': 'f', 'п': 'g', 'р': 'h', 'о': 'j', 'л': 'k', 'д': 'l', 'ж': ';', 'э': '\'',
+	'я': 'z', 'ч': 'x', 'с': 'c', 'м': 'v', 'и': 'b', 'т': 'n', 'ь': 'm', 'б': ',', 'ю': '.', 'ё': '`',
+}
+
+// enToRu is the reverse of ruToEn for letters (punctuation keys stay punctuation)
+var enToRu = func() map[rune]rune {
+	m := make(map[rune]rune, len(ruToEn))
+	for ru, en := range ruToEn {
+		if unicode.IsLetter(en) {
+			m[en] = ru
+		}
+	}
+	return m
+}()
+
+// latin transliterates Cyrillic letters (Russian, plus Ukrainian and Belarusian extras)
+// in the common passport-like style: ж → zh, х → kh, ц → ts, щ → shch, я → ya
+var latin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+}
+
+// HasCyrillic reports whether s contains a Cyrillic letter
+func HasCyrillic(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToLatin transliterates the Cyrillic letters of s to Latin ("Проект" → "proekt")
+// The result is lowercase; other characters are kept
+func ToLatin(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range strings.ToLower(s) {
+		if l, ok := latin[r]; ok {
+			b.WriteString(l)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// SwitchLayout retypes s on the other keyboard layout: Cyrillic letters become the Latin keys
+// they share on the keyboard ("фзш" → "api") and Latin letters the Cyrillic ones ("ghjtrn" → "проект")
+// The result is lowercase; other characters are kept
+func SwitchLayout(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range strings.ToLower(s) {
+		if en, ok := ruToEn[r]; ok {
+			b.WriteRune(en)
+		} else if ru, ok := enToRu[r]; ok {
+			b.WriteRune(ru)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Variants returns the alternative spellings of a query a mixed-language index may need:
+// the query retyped on the other keyboard layout and, for Cyrillic queries, its transliteration
+// Variants equal to the query (case-insensitively) are left out
+func Variants(query string) []string {
+	lower := strings.ToLower(query)
+	var variants []string
+	add := func(v string) {
+		if v != lower && !slices.Contains(variants, v) {
+			variants = append(variants, v)
+		}
+	}
+
+	add(SwitchLayout(query))
+	if HasCyrillic(query) {
+		add(ToLatin(query))
+	}
+	return variants
+}
@@ -0,0 +1,59 @@
+package translit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSwitchLayout(t *testing.T) {
+	tests := map[string]string{
+		"фзш":         "api",
+		"ФЗШ":         "api",
+		"ghjtrn":      "проект",
+		"фзш-пфеуцфн": "api-gateway",
+		"group/фзш":   "пкщгз/api",
+		"v1.2":        "м1.2",
+		"":            "",
+		"ёж":          "`;",
+	}
+	for in, want := range tests {
+		if got := SwitchLayout(in); got != want {
+			t.Errorf("SwitchLayout(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestToLatin(t *testing.T) {
+	tests := map[string]string{
+		"Проект":          "proekt",
+		"платежный шлюз":  "platezhnyy shlyuz",
+		"Щука и ёж":       "shchuka i ezh",
+		"api/авторизация": "api/avtorizatsiya",
+		"already latin":   "already latin",
+		"Київ":            "kiyiv",
+	}
+	for in, want := range tests {
+		if got := ToLatin(in); got != want {
+			t.Errorf("ToLatin(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestVariants(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"фзш", []string{"api", "fzsh"}},
+		{"proekt", []string{"зкщуле"}},
+		{"123", nil},
+	}
+	for _, tt := range tests {
+		if got := Variants(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Variants(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+	if !HasCyrillic("api-сервис") || HasCyrillic("api-service") {
+		t.Error("HasCyrillic misdetects")
+	}
+}
@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// syncTickMsg triggers a sync of a long-running finder (sync.auto: interval)
+// Ticks scheduled before the last sync finished have a lower version and are ignored
+type syncTickMsg struct {
+	version int
+}
+
+// SetAutoSync sets the automatic sync policy: onStart syncs when the finder starts,
+// a positive interval also syncs every interval after the last sync finished
+func (m *Model) SetAutoSync(onStart bool, interval time.Duration) {
+	m.autoSync = onStart
+	m.syncInterval = interval
+}
+
+// scheduleSyncTick schedules the next interval sync, replacing pending ones
+// Returns nil when syncing on an interval is disabled
+func (m *Model) scheduleSyncTick() tea.Cmd {
+	if m.syncInterval <= 0 || m.onSync == nil {
+		return nil
+	}
+	m.syncTick++
+	version := m.syncTick
+	return tea.Tick(m.syncInterval, func(_ time.Time) tea.Msg {
+		return syncTickMsg{version: version}
+	})
+}
+
+// startAutoSync starts a background sync unless one is running
+func (m *Model) startAutoSync() tea.Cmd {
+	if m.onSync == nil || m.syncing {
+		return nil
+	}
+	m.syncing = true
+	m.syncError = nil
+	// Close indexes to allow sync exclusive access
+	m.closeIndexesForSync()
+	return m.onSync()
+}
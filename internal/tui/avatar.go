@@ -0,0 +1,354 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	_ "image/gif" // Avatar formats accepted by GitLab
+	_ "image/jpeg"
+	"image/png"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/igusev/glf/internal/model"
+)
+
+// ImageProtocol is a terminal graphics protocol used to draw project avatars
+type ImageProtocol string
+
+// Supported image protocols; ImageNone draws colored initials instead
+const (
+	ImageNone   ImageProtocol = "none"
+	ImageKitty  ImageProtocol = "kitty"
+	ImageITerm2 ImageProtocol = "iterm2"
+	ImageSixel  ImageProtocol = "sixel"
+)
+
+const (
+	avatarCols   = 6  // Avatar width in terminal cells
+	avatarRows   = 3  // Avatar height in terminal cells (README preview header)
+	avatarPixels = 60 // Avatars are scaled to avatarPixels² before encoding (~6x3 cells of 10x20 px)
+)
+
+// initialsColors are the avatar backgrounds for colored initials (picked by project path)
+var initialsColors = []lipgloss.Color{"#6B4FBB", "#1F75CB", "#108548", "#C17D10", "#DD2B0E", "#0E7A8A", "#5E5E5E"}
+
+// AvatarFunc fetches the avatar image (PNG, JPEG or GIF) of a project live from GitLab
+type AvatarFunc func(projectPath string) ([]byte, error)
+
+// avatarLoadedMsg is sent when the avatar of a project has been fetched and encoded
+type avatarLoadedMsg struct {
+	projectPath string
+	image       string // Encoded escape sequence ("" = no avatar, initials are shown)
+}
+
+// ParseImageProtocol resolves the tui.image_protocol setting
+// "auto" (or empty) detects the protocol from the environment
+func ParseImageProtocol(name string, getenv func(string) string) (ImageProtocol, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "auto":
+		return DetectImageProtocol(getenv), nil
+	case "none", "initials":
+		return ImageNone, nil
+	case "kitty":
+		return ImageKitty, nil
+	case "iterm2", "iterm":
+		return ImageITerm2, nil
+	case "sixel":
+		return ImageSixel, nil
+	}
+	return ImageNone, fmt.Errorf("unknown image protocol %q (available: auto, kitty, iterm2, sixel, none)", name)
+}
+
+// DetectImageProtocol guesses the graphics protocol of the terminal from its environment
+// Terminals that can't be identified get colored initials
+func DetectImageProtocol(getenv func(string) string) ImageProtocol {
+	term := getenv("TERM")
+	program := getenv("TERM_PROGRAM")
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || program == "ghostty":
+		return ImageKitty
+	case program == "iTerm.app" || program == "WezTerm" || getenv("LC_TERMINAL") == "iTerm2":
+		return ImageITerm2
+	case term == "foot" || strings.HasPrefix(term, "mlterm") || strings.Contains(term, "sixel"):
+		return ImageSixel
+	}
+	return ImageNone
+}
+
+// SetAvatars shows project avatars in the README preview
+// fetch may be nil (offline): colored initials are shown instead of images
+func (m *Model) SetAvatars(fetch AvatarFunc, protocol ImageProtocol) {
+	m.avatars = true
+	m.fetchAvatar = fetch
+	m.imageProtocol = protocol
+}
+
+// avatarCmd fetches and encodes the avatar of project unless it is cached
+// Returns nil when only initials can be shown
+func (m *Model) avatarCmd(project model.Project) tea.Cmd {
+	if !m.avatars || m.fetchAvatar == nil || m.imageProtocol == ImageNone {
+		return nil
+	}
+	if _, ok := m.avatarCache[project.Path]; ok {
+		return nil
+	}
+
+	fetch := m.fetchAvatar
+	protocol := m.imageProtocol
+	path := project.Path
+	return func() tea.Msg {
+		data, err := fetch(path)
+		if err != nil {
+			return avatarLoadedMsg{projectPath: path}
+		}
+		encoded, err := encodeAvatar(data, protocol)
+		if err != nil {
+			return avatarLoadedMsg{projectPath: path}
+		}
+		return avatarLoadedMsg{projectPath: path, image: encoded}
+	}
+}
+
+// handleAvatarLoaded caches an encoded avatar ("" = fall back to initials)
+func (m *Model) handleAvatarLoaded(msg avatarLoadedMsg) {
+	if m.avatarCache == nil {
+		m.avatarCache = make(map[string]string)
+	}
+	m.avatarCache[msg.projectPath] = msg.image
+}
+
+// avatarShown reports whether the README preview currently draws an image
+// Images outlive the text around them (kitty), so leaving the preview clears the screen
+func (m Model) avatarShown() bool {
+	return m.inReadmeMode() && m.avatarCache[m.readmeProject.Path] != ""
+}
+
+// renderAvatarHeader renders the README preview header: the avatar (image or initials)
+// next to the project path, description and metadata, avatarRows lines
+func (m Model) renderAvatarHeader(project model.Project) string {
+	cells := renderInitials(project)
+	if image := m.avatarCache[project.Path]; image != "" {
+		blank := strings.Repeat(" ", avatarCols)
+		// Save/restore the cursor: terminals move it past the image differently
+		cells = []string{"\x1b7" + image + "\x1b8" + blank, blank, blank}
+	}
+
+	text := []string{
+		m.styles.Help.Render("README of " + project.Path),
+		"",
+		"",
+	}
+	if description := strings.TrimSpace(project.Description); description != "" {
+		text[1] = m.styles.Snippet.Render(truncateSnippet(description, max(m.width-avatarCols-6, 20)))
+	}
+	text[2] = m.styles.Help.Render(projectMetadata(project))
+
+	var b strings.Builder
+	for row := 0; row < avatarRows; row++ {
+		b.WriteString("  ")
+		b.WriteString(cells[row])
+		b.WriteString("  ")
+		b.WriteString(text[row])
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderInitials renders the colored initials avatar of a project (avatarRows lines of avatarCols cells)
+func renderInitials(project model.Project) []string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(project.Path))
+	style := lipgloss.NewStyle().
+		Background(initialsColors[hash.Sum32()%uint32(len(initialsColors))]).
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Bold(true).
+		Width(avatarCols).
+		Align(lipgloss.Center)
+
+	lines := make([]string, avatarRows)
+	for i := range lines {
+		text := ""
+		if i == avatarRows/2 {
+			text = projectInitials(project)
+		}
+		lines[i] = style.Render(text)
+	}
+	return lines
+}
+
+// projectInitials returns up to two initials of a project name ("api-gateway" → "AG")
+// Falls back to the last path segment when the project has no name
+func projectInitials(project model.Project) string {
+	name := project.Name
+	if strings.TrimSpace(name) == "" {
+		name = project.Path[strings.LastIndex(project.Path, "/")+1:]
+	}
+
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var initials []rune
+	for _, word := range words {
+		initials = append(initials, unicode.ToUpper([]rune(word)[0]))
+		if len(initials) == 2 {
+			break
+		}
+	}
+	if len(initials) == 0 {
+		return "?"
+	}
+	return string(initials)
+}
+
+// encodeAvatar decodes an avatar image and encodes it for protocol
+func encodeAvatar(data []byte, protocol ImageProtocol) (string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode avatar: %w", err)
+	}
+	img = scaleImage(img, avatarPixels, avatarPixels)
+
+	switch protocol {
+	case ImageKitty, ImageITerm2:
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return "", fmt.Errorf("failed to encode avatar: %w", err)
+		}
+		if protocol == ImageKitty {
+			return encodeKitty(buf.Bytes()), nil
+		}
+		return encodeITerm2(buf.Bytes()), nil
+	case ImageSixel:
+		return encodeSixel(img), nil
+	}
+	return "", fmt.Errorf("unsupported image protocol %q", protocol)
+}
+
+// scaleImage resizes img to width x height (nearest neighbor; avatars are tiny)
+func scaleImage(img image.Image, width, height int) image.Image {
+	src := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := src.Min.Y + y*src.Dy()/height
+		for x := 0; x < width; x++ {
+			sx := src.Min.X + x*src.Dx()/width
+			dst.Set(x, y, img.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+// encodeKitty encodes a PNG for the kitty graphics protocol, sized to the avatar cells
+// The payload is sent in 4096-byte chunks; C=1 keeps the cursor, q=2 suppresses replies
+func encodeKitty(pngData []byte) string {
+	const chunkSize = 4096
+	payload := base64.StdEncoding.EncodeToString(pngData)
+
+	var b strings.Builder
+	for start := 0; start < len(payload); start += chunkSize {
+		end := min(start+chunkSize, len(payload))
+		more := 0
+		if end < len(payload) {
+			more = 1
+		}
+		if start == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,c=%d,r=%d,C=1,q=2,m=%d;%s\x1b\\", avatarCols, avatarRows, more, payload[start:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, payload[start:end])
+		}
+	}
+	return b.String()
+}
+
+// encodeITerm2 encodes a PNG as an iTerm2 inline image, sized to the avatar cells
+func encodeITerm2(pngData []byte) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		len(pngData), avatarCols, avatarRows, base64.StdEncoding.EncodeToString(pngData))
+}
+
+// encodeSixel encodes an image as sixel graphics with the web-safe palette
+// Transparent pixels are left unpainted
+func encodeSixel(img image.Image) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	paletted := image.NewPaletted(bounds, palette.WebSafe)
+	draw.FloydSteinberg.Draw(paletted, bounds, img, bounds.Min)
+
+	opaque := func(x, y int) bool {
+		_, _, _, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+		return a >= 0x8000
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1bP0;1;0q") // P2=1: zero bits keep the background
+	fmt.Fprintf(&b, "\"1;1;%d;%d", width, height)
+
+	used := make(map[uint8]bool)
+	for _, idx := range paletted.Pix {
+		used[idx] = true
+	}
+	for idx := range palette.WebSafe {
+		if !used[uint8(idx)] {
+			continue
+		}
+		r, g, bl, _ := color.NRGBAModel.Convert(palette.WebSafe[idx]).RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", idx, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+
+	for y0 := 0; y0 < height; y0 += 6 {
+		first := true
+		for idx := range palette.WebSafe {
+			if !used[uint8(idx)] {
+				continue
+			}
+			row := make([]byte, width)
+			painted := false
+			for x := 0; x < width; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && y0+dy < height; dy++ {
+					if paletted.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+y0+dy) == uint8(idx) && opaque(x, y0+dy) {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = 63 + bits
+				painted = painted || bits != 0
+			}
+			if !painted {
+				continue
+			}
+			if !first {
+				b.WriteByte('$') // Back to the start of the band for the next color
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", idx)
+			writeSixelRow(&b, row)
+		}
+		b.WriteByte('-') // Next band
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// writeSixelRow writes sixel characters with run-length encoding ("!n<char>")
+func writeSixelRow(b *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if run := j - i; run > 3 {
+			fmt.Fprintf(b, "!%d%c", run, row[i])
+		} else {
+			b.Write(row[i:j])
+		}
+		i = j
+	}
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/igusev/glf/internal/model"
+)
+
+// testAvatarPNG returns a PNG with a red left half and a transparent right half
+func testAvatarPNG(t *testing.T, size int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size/2; x++ {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetectImageProtocol(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want ImageProtocol
+	}{
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, ImageKitty},
+		{"kitty window", map[string]string{"KITTY_WINDOW_ID": "1", "TERM": "xterm-256color"}, ImageKitty},
+		{"ghostty", map[string]string{"TERM_PROGRAM": "ghostty"}, ImageKitty},
+		{"iterm2", map[string]string{"TERM_PROGRAM": "iTerm.app"}, ImageITerm2},
+		{"wezterm", map[string]string{"TERM_PROGRAM": "WezTerm"}, ImageITerm2},
+		{"foot", map[string]string{"TERM": "foot"}, ImageSixel},
+		{"unknown", map[string]string{"TERM": "xterm-256color"}, ImageNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := DetectImageProtocol(getenv); got != tt.want {
+				t.Errorf("DetectImageProtocol() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseImageProtocol(t *testing.T) {
+	kittyEnv := func(key string) string {
+		if key == "TERM" {
+			return "xterm-kitty"
+		}
+		return ""
+	}
+	for name, want := range map[string]ImageProtocol{"": ImageKitty, "auto": ImageKitty, "Sixel": ImageSixel, "iterm": ImageITerm2, "none": ImageNone} {
+		if got, err := ParseImageProtocol(name, kittyEnv); err != nil || got != want {
+			t.Errorf("ParseImageProtocol(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseImageProtocol("ascii-art", kittyEnv); err == nil {
+		t.Error("Expected error for unknown protocol")
+	}
+}
+
+func TestProjectInitials(t *testing.T) {
+	tests := []struct {
+		project model.Project
+		want    string
+	}{
+		{model.Project{Path: "team/api-gateway", Name: "api-gateway"}, "AG"},
+		{model.Project{Path: "team/web", Name: "Web Shop Frontend"}, "WS"},
+		{model.Project{Path: "team/billing"}, "B"},
+		{model.Project{Path: "team/x", Name: "--"}, "?"},
+	}
+	for _, tt := range tests {
+		if got := projectInitials(tt.project); got != tt.want {
+			t.Errorf("projectInitials(%q) = %q, want %q", tt.project.Name, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeAvatar(t *testing.T) {
+	data := testAvatarPNG(t, 200)
+
+	kitty, err := encodeAvatar(data, ImageKitty)
+	if err != nil {
+		t.Fatalf("kitty: %v", err)
+	}
+	if !strings.HasPrefix(kitty, "\x1b_Ga=T,f=100,c=6,r=3,C=1,q=2,") || !strings.HasSuffix(kitty, "\x1b\\") {
+		t.Errorf("Unexpected kitty sequence %q", kitty)
+	}
+
+	iterm, err := encodeAvatar(data, ImageITerm2)
+	if err != nil {
+		t.Fatalf("iterm2: %v", err)
+	}
+	if !strings.HasPrefix(iterm, "\x1b]1337;File=inline=1;") || !strings.HasSuffix(iterm, "\a") {
+		t.Errorf("Unexpected iTerm2 sequence %q", iterm)
+	}
+
+	sixel, err := encodeAvatar(data, ImageSixel)
+	if err != nil {
+		t.Fatalf("sixel: %v", err)
+	}
+	if !strings.HasPrefix(sixel, "\x1bP0;1;0q\"1;1;60;60") || !strings.HasSuffix(sixel, "\x1b\\") {
+		t.Errorf("Unexpected sixel header/terminator in %q", sixel[:min(len(sixel), 40)])
+	}
+	// 60 rows are 10 sixel bands; the red half is a run-length encoded full band ("~" = all 6 bits)
+	if bands := strings.Count(sixel, "-"); bands != 10 {
+		t.Errorf("Expected 10 sixel bands, got %d", bands)
+	}
+	if !strings.Contains(sixel, "!30~") {
+		t.Error("Expected run-length encoded opaque half")
+	}
+
+	if _, err := encodeAvatar([]byte("not an image"), ImageKitty); err == nil {
+		t.Error("Expected error for invalid image data")
+	}
+}
+
+func TestEncodeKitty_Chunks(t *testing.T) {
+	payload := encodeKitty(bytes.Repeat([]byte{0xAB}, 6000)) // 8000 base64 bytes
+	if chunks := strings.Count(payload, "\x1b_G"); chunks != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", chunks)
+	}
+	if !strings.Contains(payload, ",m=1;") || !strings.Contains(payload, "\x1b_Gm=0;") {
+		t.Error("Expected continuation flags on the chunks")
+	}
+}
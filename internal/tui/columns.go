@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/igusev/glf/internal/model"
+)
+
+const (
+	// defaultSnippetWidth is the snippet length when the terminal width is unknown
+	defaultSnippetWidth = 60
+
+	// minNamespaceWidth is the narrowest a namespace is truncated to before the name is truncated too
+	minNamespaceWidth = 12
+
+	// displayDecoration is the width of the namespace brackets and separator: "[" + "] > "
+	displayDecoration = 5
+)
+
+// matchColumns is the width available to the parts of a result (0 = unlimited)
+type matchColumns struct {
+	line    int // First line: pin and star markers, namespace, name, counters, labels and scores
+	snippet int // Second line: the description snippet
+}
+
+// listColumns returns the columns of a result in a list of the given width, after the
+// cursor column, the line prefix (mark column and hidden indicators) and the scrollbar
+func listColumns(width, prefixWidth int) matchColumns {
+	if width <= 0 {
+		return matchColumns{}
+	}
+	avail := width - 2 // Cursor column and scrollbar
+	return matchColumns{
+		line:    max(1, avail-prefixWidth),
+		snippet: max(1, avail-5), // Snippet indentation
+	}
+}
+
+// snippetRunes returns the number of snippet runes kept before "..." (see truncateSnippet),
+// defaultSnippetWidth when the width is unlimited
+func (c matchColumns) snippetRunes() int {
+	if c.snippet <= 0 {
+		return defaultSnippetWidth
+	}
+	return max(1, c.snippet-3)
+}
+
+// layoutColumns splits width between the namespace and the name of a result ("[namespace] > name")
+// The name is kept whole when possible: the namespace gives up width first, down to
+// minNamespaceWidth, then the name is truncated too
+func layoutColumns(width, namespaceLen, nameLen int) (namespace, name int) {
+	avail := width - displayDecoration
+	if namespaceLen+nameLen <= avail {
+		return namespaceLen, nameLen
+	}
+	namespace = max(avail-nameLen, min(namespaceLen, minNamespaceWidth))
+	namespace = max(1, min(namespace, avail-1))
+	return namespace, max(1, avail-namespace)
+}
+
+// fitDisplayString returns the project's DisplayString fitted in width columns (0 = unlimited)
+// Long namespaces lose their middle groups first ("[company/…/team] > api"), then long names their end
+func fitDisplayString(p model.Project, width int) string {
+	display := p.DisplayString()
+	if width <= 0 || len([]rune(display)) <= width {
+		return display
+	}
+
+	slash := strings.LastIndex(p.Path, "/")
+	if slash < 0 {
+		return truncateEnd(p.Name, width)
+	}
+	namespace := p.Path[:slash]
+	nsWidth, nameWidth := layoutColumns(width, len([]rune(namespace)), len([]rune(p.Name)))
+	return "[" + truncateMiddle(namespace, nsWidth) + "] > " + truncateEnd(p.Name, nameWidth)
+}
+
+// truncateMiddle shortens a path to width runes by replacing its middle with "…"
+// Whole groups are dropped when possible, keeping the first and as many trailing ones as fit
+// ("company/…/team/service"); otherwise the middle runes are cut ("company-pl…ices")
+func truncateMiddle(path string, width int) string {
+	runes := []rune(path)
+	if len(runes) <= width {
+		return path
+	}
+	if width <= 1 {
+		return "…"
+	}
+
+	segments := strings.Split(path, "/")
+	for keep := len(segments) - 2; keep >= 1; keep-- {
+		candidate := segments[0] + "/…/" + strings.Join(segments[len(segments)-keep:], "/")
+		if len([]rune(candidate)) <= width {
+			return candidate
+		}
+	}
+
+	head := (width - 1) / 2
+	tail := width - 1 - head
+	return string(runes[:head]) + "…" + string(runes[len(runes)-tail:])
+}
+
+// truncateEnd shortens text to width runes, ending it with "…"
+func truncateEnd(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	if width <= 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/model"
+)
+
+func TestTruncateMiddle(t *testing.T) {
+	tests := []struct {
+		path  string
+		width int
+		want  string
+	}{
+		{"company/platform", 20, "company/platform"},
+		{"company/platform/backend/team", 22, "company/…/backend/team"},
+		{"company/platform/backend/team", 16, "company/…/team"},
+		{"company-platform-services", 12, "compa…rvices"},
+		{"company/platform", 1, "…"},
+	}
+	for _, tt := range tests {
+		if got := truncateMiddle(tt.path, tt.width); got != tt.want {
+			t.Errorf("truncateMiddle(%q, %d) = %q, want %q", tt.path, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestFitDisplayString(t *testing.T) {
+	p := model.Project{Path: "company/platform/backend/team/billing-service", Name: "billing-service"}
+
+	if got := fitDisplayString(p, 0); got != p.DisplayString() {
+		t.Errorf("fitDisplayString(unlimited) = %q, want %q", got, p.DisplayString())
+	}
+	// The name stays whole, the namespace loses its middle groups
+	if got, want := fitDisplayString(p, 40), "[company/…/team] > billing-service"; got != want {
+		t.Errorf("fitDisplayString(40) = %q, want %q", got, want)
+	}
+	// Too narrow for the whole name: both are truncated
+	if got, want := fitDisplayString(p, 24), "[compa…d/team] > billin…"; got != want {
+		t.Errorf("fitDisplayString(24) = %q, want %q", got, want)
+	}
+	// Projects without a namespace show their name only
+	if got, want := fitDisplayString(model.Project{Path: "tools", Name: "developer-tools"}, 10), "developer…"; got != want {
+		t.Errorf("fitDisplayString(no namespace) = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMatch_FitsWidth(t *testing.T) {
+	styles := NewColorScheme().GetStyles()
+	match := index.CombinedMatch{
+		Project: model.Project{
+			Path:        "company/platform/backend/team/billing-service",
+			Name:        "billing-service",
+			Description: strings.Repeat("Handles invoices and payments. ", 5),
+			OpenMRs:     3,
+		},
+		Source:  index.MatchSourceName,
+		Snippet: strings.Repeat("Handles invoices and payments. ", 5),
+	}
+
+	cols := listColumns(50, 1)
+	lines := strings.Split(renderMatch(match, styles, "", false, false, cols), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected a name line and a snippet line, got %q", lines)
+	}
+	if w := lipgloss.Width(lines[0]); w > cols.line {
+		t.Errorf("First line is %d wide, want at most %d: %q", w, cols.line, lines[0])
+	}
+	if !strings.Contains(lines[0], "billing-service") || !strings.Contains(lines[0], "!3") {
+		t.Errorf("Expected the full name and the counters, got %q", lines[0])
+	}
+	if w := lipgloss.Width(lines[1]); w > cols.snippet {
+		t.Errorf("Snippet is %d wide, want at most %d", w, cols.snippet)
+	}
+}
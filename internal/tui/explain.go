@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/history"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/search"
+)
+
+// scoreExplanation is the breakdown shown by the score popup (Ctrl+E with --scores)
+type scoreExplanation struct {
+	match   index.CombinedMatch
+	query   string             // Full-text part of the query the match was ranked for
+	fields  []index.FieldScore // Search score by field and matched term (nil for empty/regex queries)
+	err     error              // Error computing the field breakdown
+	history *history.Explanation
+}
+
+// inExplainMode reports whether the score popup is shown
+func (m Model) inExplainMode() bool {
+	return m.explain != nil
+}
+
+// enterExplainMode explains the score of the project under the cursor
+func (m *Model) enterExplainMode() {
+	if !m.showScores || len(m.filtered) == 0 || m.cursor >= len(m.filtered) {
+		return
+	}
+
+	query := strings.TrimSpace(m.textInput.Value())
+	exp := &scoreExplanation{match: m.filtered[m.cursor]}
+	if !m.regexMode {
+		exp.query = search.ParseQuery(query).SearchText()
+	}
+	if exp.query != "" && !exp.match.Remote && m.descIndex != nil {
+		exp.fields, exp.err = m.descIndex.ExplainScore(exp.query, exp.match.Project.Path)
+	}
+	if m.history != nil {
+		histExp := m.history.Explain(query, exp.match.Project.Key())
+		histExp.ProjectPath = exp.match.Project.Path
+		exp.history = &histExp
+	}
+	m.explain = exp
+}
+
+// updateExplain handles key presses in the score popup
+func (m Model) updateExplain(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		if m.history != nil {
+			_ = m.history.Save() // Silently fail - don't prevent quit
+		}
+		return m, tea.Quit
+
+	case "esc", "q", "ctrl+e", "enter":
+		m.explain = nil
+	}
+	return m, nil
+}
+
+// renderExplain renders the score popup
+func (m Model) renderExplain() string {
+	exp := m.explain
+	match := exp.match
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		b.WriteString(fmt.Sprintf("  "+format, args...))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.styles.Title.Render("  Score of " + match.Project.Path))
+	b.WriteString("\n\n")
+
+	if match.Remote {
+		line("Remote result: found by the GitLab search API, kept in API order (no local score)")
+		return m.withExplainFooter(&b)
+	}
+
+	multiplier := 1.0
+	if exp.query == "" {
+		if m.regexMode {
+			line("Regex match on the path: ranked by history and starred bonus only")
+		} else {
+			line("Empty query: ranked by history and starred bonus only")
+		}
+	} else {
+		multiplier = search.RelevanceMultiplier(match.SearchScore)
+		line("%-22s %8.3f", "Search (bleve)", match.SearchScore)
+		switch {
+		case exp.err != nil:
+			line("  %s", exp.err)
+		case len(exp.fields) == 0:
+			line("  no field matches")
+		}
+		for _, field := range exp.fields {
+			terms := make([]string, 0, len(field.Terms))
+			for _, term := range field.Terms {
+				terms = append(terms, fmt.Sprintf("%s (%s %.3f)", term.Term, termMatchKind(term.Term, exp.query), term.Score))
+			}
+			line("  %-20s %8.3f  %s", field.Field, field.Score, strings.Join(terms, ", "))
+		}
+		line("%-22s %8.3f  (query tokens starting distinct path segments)", "Path segment bonus", match.PathBonus)
+		line("%-22s %8.2f  (history and starred bonus are scaled by it)", "Relevance multiplier", multiplier)
+	}
+
+	b.WriteString("\n")
+	line("%-22s %8.2f  %d × %.2f", "History", float64(match.HistoryScore)*multiplier, match.HistoryScore, multiplier)
+	if h := exp.history; h != nil {
+		var global, byQuery int
+		for _, c := range h.Contributions {
+			if c.Query {
+				byQuery++
+			} else {
+				global++
+			}
+		}
+		line("  %-20s %8.2f  %d selections (%s)", "any query", h.GlobalScore, global, h.Algorithm)
+		if h.Query != "" {
+			line("  %-20s %8.2f  %d selections with %q", "this query", h.QueryScore, byQuery, h.Query)
+		}
+		if h.Capped {
+			line("  capped at %d (raw %.2f)", h.Score, h.RawScore)
+		}
+	}
+	line("%-22s %8.2f  %d × %.2f", "Starred bonus", float64(match.StarredBonus)*multiplier, match.StarredBonus, multiplier)
+	if match.ArchivedPenalty > 0 {
+		line("%-22s %8.2f  (scoring.archived_penalty)", "Archived penalty", -match.ArchivedPenalty)
+	}
+	if match.ForkPenalty > 0 {
+		line("%-22s %8.2f  (scoring.fork_penalty, fork of %s)", "Fork penalty", -match.ForkPenalty, match.Project.ForkedFrom)
+	}
+
+	b.WriteString("\n")
+	line("%-22s %8.2f", "Total", match.TotalScore)
+	if match.Pinned {
+		line("Pinned: kept at the top of results regardless of score")
+	}
+	if m.sortByMRs {
+		line("Results are sorted by open merge requests (ctrl+s), not by score")
+	}
+	return m.withExplainFooter(&b)
+}
+
+// withExplainFooter appends the key hint of the score popup
+func (m Model) withExplainFooter(b *strings.Builder) string {
+	b.WriteString("\n")
+	b.WriteString(m.styles.Help.Render("  esc: back to projects"))
+	return b.String()
+}
+
+// termMatchKind describes how an index term matched the query tokens
+// Anything that is neither the token nor an extension of it came from fuzzy matching or stemming
+func termMatchKind(term, query string) string {
+	kind := "fuzzy"
+	for _, token := range strings.Fields(strings.ToLower(query)) {
+		if term == token {
+			return "exact"
+		}
+		if strings.HasPrefix(term, token) {
+			kind = "prefix"
+		}
+	}
+	return kind
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/target"
+)
+
+// SetFileMode makes Enter open a file of the selected project instead of its root (--file)
+// With an empty filePath the TUI asks for the path after a project is selected
+func (m *Model) SetFileMode(enabled bool, filePath string) {
+	m.fileMode = enabled
+	m.filePath = filePath
+}
+
+// inFileMode reports whether the file path prompt is shown
+func (m Model) inFileMode() bool {
+	return m.fileProject != nil
+}
+
+// selectFileProject handles Enter on the project under the cursor in file mode:
+// opens the file given on the command line, or asks for the path (quits without results, like Enter)
+func (m *Model) selectFileProject() tea.Cmd {
+	if len(m.filtered) == 0 || m.cursor >= len(m.filtered) {
+		m.quitting = true
+		return tea.Quit
+	}
+	project := m.filtered[m.cursor].Project
+	if m.filePath != "" && m.selectFile(project, m.filePath) {
+		return tea.Quit
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "path/to/file (:line)"
+	ti.Focus()
+	ti.CharLimit = 256
+	ti.Width = 50
+	ti.Prompt = "file> "
+	ti.PromptStyle = m.styles.Prompt
+	m.fileInput = ti
+	m.fileErr = nil
+	m.fileProject = &project
+	return textinput.Blink
+}
+
+// selectFile selects the blob URL of filePath in project and records the selection
+// Returns false if no URL can be built (e.g., the path is empty)
+func (m *Model) selectFile(project model.Project, filePath string) bool {
+	var baseURL string
+	if m.config != nil {
+		baseURL = m.config.GitLab.URL
+	}
+	blobURL, err := target.BlobURL(baseURL, project.Path, project.DefaultBranch, filePath)
+	if err != nil {
+		m.fileErr = err
+		return false
+	}
+
+	m.selected = project.Path
+	m.selectedURL = blobURL
+	m.quitting = true
+	m.rememberQuery(m.textInput.Value())
+	if m.history != nil {
+		m.history.RecordSelectionWithQuery(strings.TrimSpace(m.textInput.Value()), project.Key())
+		_ = m.history.Save() // Silently fail - don't prevent selection
+	}
+	return true
+}
+
+// updateFile handles key presses in the file path prompt
+func (m Model) updateFile(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		if m.history != nil {
+			_ = m.history.Save() // Silently fail - don't prevent quit
+		}
+		return m, tea.Quit
+
+	case "esc":
+		m.fileProject = nil
+		m.fileErr = nil
+		return m, nil
+
+	case "enter":
+		if m.selectFile(*m.fileProject, m.fileInput.Value()) {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.fileInput, cmd = m.fileInput.Update(msg)
+	m.fileErr = nil
+	return m, cmd
+}
+
+// renderFile renders the file path prompt
+func (m Model) renderFile() string {
+	var b strings.Builder
+	branch := m.fileProject.DefaultBranch
+	if branch == "" {
+		branch = "default branch"
+	}
+	b.WriteString(m.styles.Help.Render("  Open a file of " + m.fileProject.Path + " (" + branch + ")"))
+	b.WriteString("\n\n  ")
+	b.WriteString(m.fileInput.View())
+	b.WriteString("\n")
+	if m.fileErr != nil {
+		b.WriteString(m.styles.Help.Render("  " + m.fileErr.Error()))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styles.Help.Render("  enter: open in browser • esc: back to projects"))
+	return b.String()
+}
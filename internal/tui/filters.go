@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/model"
+)
+
+// Dedicated "only" filters, toggled with Alt+A, Alt+G and Alt+S
+// Active filters are combined (AND) and listed in the header
+const (
+	filterArchived  = "archived"
+	filterNonMember = "non-member"
+	filterStarred   = "starred"
+)
+
+// toggleFilter switches one of the dedicated filters and refilters from the top
+func (m *Model) toggleFilter(name string) {
+	switch name {
+	case filterArchived:
+		m.onlyArchived = !m.onlyArchived
+	case filterNonMember:
+		m.onlyNonMember = !m.onlyNonMember
+	case filterStarred:
+		m.onlyStarred = !m.onlyStarred
+	}
+	m.emptyResultsCached = false
+	m.filter()
+	m.cursor = 0
+	m.viewportStart = 0
+}
+
+// activeFilters returns the names of the enabled dedicated filters (in header order)
+func (m Model) activeFilters() []string {
+	var active []string
+	if m.onlyArchived {
+		active = append(active, filterArchived)
+	}
+	if m.onlyNonMember {
+		active = append(active, filterNonMember)
+	}
+	if m.onlyStarred {
+		active = append(active, filterStarred)
+	}
+	return active
+}
+
+// matchesFilters reports whether a project passes the dedicated filters
+func (m Model) matchesFilters(match index.CombinedMatch) bool {
+	p := match.Project
+	return (!m.onlyArchived || p.Archived) &&
+		(!m.onlyNonMember || !p.Member) &&
+		(!m.onlyStarred || p.Starred)
+}
+
+// isHiddenMatch reports whether the hidden filter (Ctrl+H) removes a project
+// A dedicated filter for archived or non-member projects shows that kind even when hidden projects are not shown
+func (m Model) isHiddenMatch(match index.CombinedMatch) bool {
+	if m.showHidden {
+		return false
+	}
+	p := match.Project
+	return (m.config != nil && m.config.IsExcluded(p.Path)) ||
+		(p.Archived && !m.onlyArchived) ||
+		(!p.Member && !m.onlyNonMember)
+}
+
+// renderFilters renders the active dedicated filters for the header (empty if none)
+func (m Model) renderFilters() string {
+	active := m.activeFilters()
+	if len(active) == 0 {
+		return ""
+	}
+	return m.styles.Counter.Render("[" + strings.Join(active, "+") + " only]")
+}
+
+// pinLookup returns the search.ApplyPins lookup that adds pinned projects the query doesn't
+// match, unless a dedicated filter removes them
+func (m Model) pinLookup() func(path string) (model.Project, bool) {
+	return func(path string) (model.Project, bool) {
+		var project model.Project
+		found := false
+		if m.projects != nil {
+			for _, p := range m.projects {
+				if p.Path == path {
+					project, found = p, true
+					break
+				}
+			}
+		} else if m.descIndex != nil {
+			p, ok, err := m.descIndex.GetProject(path)
+			project, found = p, ok && err == nil
+		}
+		return project, found && m.matchesFilters(index.CombinedMatch{Project: project})
+	}
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/igusev/glf/internal/index"
+)
+
+// SetCollapseForks lists forks under their upstream result (search.collapse_forks);
+// Alt+F shows or hides the forks of the selected result
+func (m *Model) SetCollapseForks(collapse bool) {
+	m.collapseForks = collapse
+	m.emptyResultsCached = false
+	m.filter()
+}
+
+// expandForks inserts the forks of expanded results right after them
+func (m *Model) expandForks(matches []index.CombinedMatch) []index.CombinedMatch {
+	if len(m.expandedForks) == 0 {
+		return matches
+	}
+	result := make([]index.CombinedMatch, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, match)
+		if m.expandedForks[match.Project.Path] {
+			result = append(result, match.Forks...)
+		}
+	}
+	return result
+}
+
+// markForkRows records which of the shown results are forks listed under an expanded upstream
+// (rendered indented)
+func (m *Model) markForkRows() {
+	m.forkRows = nil
+	for _, match := range m.filtered {
+		if !m.expandedForks[match.Project.Path] {
+			continue
+		}
+		for _, fork := range match.Forks {
+			if m.forkRows == nil {
+				m.forkRows = make(map[string]bool)
+			}
+			m.forkRows[fork.Project.Path] = true
+		}
+	}
+}
+
+// toggleForks shows or hides the forks of the selected result (or of the upstream of the
+// selected fork) and moves the cursor to that result
+func (m *Model) toggleForks() {
+	if !m.collapseForks || m.groupsMode || m.cursor >= len(m.filtered) {
+		return
+	}
+	head := m.cursor
+	for head > 0 && m.forkRows[m.filtered[head].Project.Path] {
+		head-- // Forks follow their upstream
+	}
+	match := m.filtered[head]
+	if len(match.Forks) == 0 {
+		return
+	}
+
+	path := match.Project.Path
+	filtered := make([]index.CombinedMatch, 0, len(m.filtered)+len(match.Forks))
+	filtered = append(filtered, m.filtered[:head+1]...)
+	if m.expandedForks[path] {
+		delete(m.expandedForks, path)
+		filtered = append(filtered, m.filtered[min(len(m.filtered), head+1+len(match.Forks)):]...)
+	} else {
+		if m.expandedForks == nil {
+			m.expandedForks = make(map[string]bool)
+		}
+		m.expandedForks[path] = true
+		filtered = append(filtered, match.Forks...)
+		filtered = append(filtered, m.filtered[head+1:]...)
+	}
+	m.filtered = filtered
+	m.markForkRows()
+	if strings.TrimSpace(m.textInput.Value()) == "" && m.emptyResultsCached {
+		m.cachedEmptyResults = filtered
+	}
+
+	m.cursor = head
+	m.ensureCursorVisible(m.listHeight())
+}
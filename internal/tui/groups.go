@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/search"
+)
+
+// SetGroupIndex enables group search (Alt+O) using the synced group index
+// The model takes ownership of idx (closed by CloseIndex)
+func (m *Model) SetGroupIndex(idx *index.DescriptionIndex) {
+	m.groupIndex = idx
+}
+
+// SetGroupsMode switches between searching groups and projects
+func (m *Model) SetGroupsMode(enabled bool) {
+	m.groupsMode = enabled
+	m.emptyResultsCached = false
+	if enabled {
+		m.textInput.Placeholder = "Search groups..."
+	} else {
+		m.textInput.Placeholder = "Search projects..."
+	}
+	m.filter()
+}
+
+// SelectedIsGroup reports whether the selected path is a group (selected in groups mode)
+func (m Model) SelectedIsGroup() bool {
+	return m.selectedGroup
+}
+
+// projectOnlyKey reports whether a key acts on projects only and is ignored in groups mode
+// (issues, README, exclusions, pins, filters and sorting)
+func projectOnlyKey(key string) bool {
+	switch key {
+	case "tab", "alt+v", "ctrl+x", "ctrl+h", "alt+p", "alt+r", "alt+a", "alt+g", "alt+s", "ctrl+s", "ctrl+t", "alt+f", "ctrl+@", "alt+m", "alt+u", "alt+c":
+		return true
+	}
+	return false
+}
+
+// filterGroups searches the group index (groups mode)
+// Groups are never hidden, pinned or searched remotely
+func (m *Model) filterGroups(query string, historyScores map[string]int) {
+	m.hiddenMatches = 0
+	m.regexErr = nil
+	if m.groupIndex == nil {
+		m.filtered = nil
+		return
+	}
+
+	matches, err := search.CombinedSearchWithIndex(query, nil, historyScores, m.cacheDir, m.groupIndex)
+	if err != nil {
+		matches = nil
+	}
+	m.filtered = matches
+	m.restoreCursor()
+}
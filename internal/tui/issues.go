@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/search"
+)
+
+// IssuesFunc fetches the open issues of a project live from GitLab
+type IssuesFunc func(projectPath string) ([]model.Issue, error)
+
+// issuesLoadedMsg is sent when the issues of a project have been fetched
+type issuesLoadedMsg struct {
+	projectPath string
+	issues      []model.Issue
+	err         error
+}
+
+// SetIssuesFetcher enables issues mode (Tab on a project) using fn to fetch issues
+func (m *Model) SetIssuesFetcher(fn IssuesFunc) {
+	m.fetchIssues = fn
+}
+
+// inIssuesMode reports whether the issue list of a project is shown instead of projects
+func (m Model) inIssuesMode() bool {
+	return m.issuesProject != nil
+}
+
+// enterIssuesMode switches to the issue list of the project under the cursor
+// The project query is saved and restored when leaving issues mode
+func (m *Model) enterIssuesMode() tea.Cmd {
+	if m.fetchIssues == nil || len(m.filtered) == 0 || m.cursor >= len(m.filtered) {
+		return nil
+	}
+
+	project := m.filtered[m.cursor].Project
+	m.issuesProject = &project
+	m.issues = nil
+	m.filteredIssues = nil
+	m.issuesErr = nil
+	m.issuesLoading = true
+	m.issueCursor = 0
+	m.issueViewportStart = 0
+
+	m.projectQuery = m.textInput.Value()
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "Filter issues..."
+	m.textInput.Prompt = "#> "
+
+	fetchIssues := m.fetchIssues
+	path := project.Path
+	return func() tea.Msg {
+		issues, err := fetchIssues(path)
+		return issuesLoadedMsg{projectPath: path, issues: issues, err: err}
+	}
+}
+
+// exitIssuesMode returns to the project list with the previous query
+func (m *Model) exitIssuesMode() {
+	m.issuesProject = nil
+	m.issues = nil
+	m.filteredIssues = nil
+	m.issuesErr = nil
+	m.issuesLoading = false
+
+	m.textInput.SetValue(m.projectQuery)
+	m.textInput.CursorEnd()
+	m.textInput.Placeholder = "Search projects..."
+	m.updatePrompt()
+}
+
+// updateIssues handles key presses in issues mode
+func (m Model) updateIssues(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		if m.history != nil {
+			_ = m.history.Save() // Silently fail - don't prevent quit
+		}
+		return m, tea.Quit
+
+	case "esc", "tab", "shift+tab":
+		m.exitIssuesMode()
+
+	case "enter":
+		if len(m.filteredIssues) > 0 && m.issueCursor < len(m.filteredIssues) {
+			m.selected = m.issuesProject.Path
+			m.selectedURL = m.filteredIssues[m.issueCursor].WebURL
+
+			// Opening an issue counts as using the project
+			m.rememberQuery(m.projectQuery)
+			if m.history != nil {
+				query := strings.TrimSpace(m.projectQuery)
+				m.history.RecordSelectionWithQuery(query, m.issuesProject.Key())
+				_ = m.history.Save() // Silently fail - don't prevent selection
+			}
+			m.quitting = true
+			return m, tea.Quit
+		}
+
+	case "down", "ctrl+n":
+		if m.issueCursor < len(m.filteredIssues)-1 {
+			m.issueCursor++
+			if visible := m.listHeight(); m.issueCursor >= m.issueViewportStart+visible {
+				m.issueViewportStart = m.issueCursor - visible + 1
+			}
+		}
+
+	case "up", "ctrl+p":
+		if m.issueCursor > 0 {
+			m.issueCursor--
+			if m.issueCursor < m.issueViewportStart {
+				m.issueViewportStart = m.issueCursor
+			}
+		}
+
+	case "?":
+		m.showHelp = !m.showHelp
+
+	default:
+		prevValue := m.textInput.Value()
+		m.textInput, cmd = m.textInput.Update(msg)
+		// Issue lists are small: filter on every keystroke, no debounce
+		if m.textInput.Value() != prevValue {
+			m.filterIssues()
+		}
+	}
+
+	return m, cmd
+}
+
+// filterIssues applies the current query to the fetched issues
+func (m *Model) filterIssues() {
+	m.filteredIssues = search.FilterIssues(m.issues, m.textInput.Value())
+	m.issueCursor = 0
+	m.issueViewportStart = 0
+}
+
+// listHeight returns the number of lines available for the result list
+func (m Model) listHeight() int {
+	usedLines := 6 // Title, separator, empty, search, 2 empty
+	if m.showHelp {
+		usedLines += 3
+	}
+	if m.height-usedLines < 1 {
+		return 1
+	}
+	return m.height - usedLines
+}
+
+// issuesCount returns the header counter in issues mode (e.g., "3/12 issues")
+func (m Model) issuesCount() string {
+	return fmt.Sprintf("%d/%d issues", len(m.filteredIssues), len(m.issues))
+}
+
+// renderIssues renders the issue list of the selected project
+func (m Model) renderIssues() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.Help.Render("  Open issues of " + m.issuesProject.Path))
+	b.WriteString("\n")
+
+	switch {
+	case m.issuesLoading:
+		b.WriteString(m.styles.Help.Render("  Loading issues..."))
+		b.WriteString("\n")
+	case m.issuesErr != nil:
+		b.WriteString(m.styles.Help.Render("  " + m.issuesErr.Error()))
+		b.WriteString("\n")
+	case len(m.issues) == 0:
+		b.WriteString(m.styles.Help.Render("  No open issues"))
+		b.WriteString("\n")
+	}
+
+	// One line is used by the project header above
+	end := m.issueViewportStart + m.listHeight() - 1
+	if end > len(m.filteredIssues) {
+		end = len(m.filteredIssues)
+	}
+	query := strings.TrimSpace(m.textInput.Value())
+	for i := m.issueViewportStart; i < end; i++ {
+		issue := m.filteredIssues[i]
+
+		line := " " + renderFuzzyMatch(issue.DisplayString(), query, m.styles.Normal, m.styles.Highlight)
+		if len(issue.Labels) > 0 {
+			line += m.styles.Counter.Render(" ~" + strings.Join(issue.Labels, " ~"))
+		}
+
+		if i == m.issueCursor {
+			b.WriteString(m.styles.Cursor.Render("▌"))
+			b.WriteString(m.styles.Selected.Width(m.width - 2).Render(line))
+		} else {
+			b.WriteString(" ")
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+
+	if m.showHelp {
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.Help.Render("↑/↓: navigate • enter: open issue • esc/tab: back to projects • ?: toggle help"))
+	}
+
+	return b.String()
+}
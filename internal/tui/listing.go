@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/search"
+)
+
+// listGroup shows a "group/" query as a directory listing: the immediate subgroups and
+// projects of the group instead of ranked results
+// Returns false (showing nothing) when query is not a listing or no project lies under the group
+func (m *Model) listGroup(query string) bool {
+	group := search.GroupListingPrefix(query)
+	if group == "" || m.regexMode {
+		return false
+	}
+	projects := m.projects
+	if projects == nil && m.descIndex != nil {
+		all, err := m.descIndex.GetAllProjects()
+		if err != nil {
+			return false
+		}
+		projects = all
+	}
+	listing, ok := search.GroupListing(group, projects)
+	if !ok {
+		return false
+	}
+
+	// The dedicated and hidden filters apply as to search results
+	filtered := make([]index.CombinedMatch, 0, len(listing))
+	for _, match := range listing {
+		if !m.matchesFilters(match) {
+			continue
+		}
+		if m.isHiddenMatch(match) {
+			m.hiddenMatches++
+			continue
+		}
+		filtered = append(filtered, match)
+	}
+	m.filtered = filtered
+	m.forkRows = nil
+	m.restoreCursor()
+	return true
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/model"
+)
+
+// BulkAction is what to do with the marked projects when the TUI exits
+type BulkAction int
+
+const (
+	BulkNone      BulkAction = iota // Single selection (or nothing selected)
+	BulkOpen                        // Open every project in the browser (Enter with marks)
+	BulkPrintURLs                   // Print the project URLs (Alt+U)
+	BulkCopyClone                   // Copy "git clone" commands to the clipboard (Alt+C)
+)
+
+// markIndex returns the position of a project in the marked list, or -1
+func (m Model) markIndex(projectPath string) int {
+	for i, p := range m.marked {
+		if p.Path == projectPath {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleMark marks or unmarks the project under the cursor and moves to the next one
+func (m *Model) toggleMark() {
+	if len(m.filtered) == 0 || m.cursor >= len(m.filtered) {
+		return
+	}
+	project := m.filtered[m.cursor].Project
+	if i := m.markIndex(project.Path); i >= 0 {
+		m.marked = append(m.marked[:i], m.marked[i+1:]...)
+	} else {
+		m.marked = append(m.marked, project)
+	}
+	if m.cursor < len(m.filtered)-1 {
+		m.cursor++
+		m.ensureCursorVisible(m.listHeight())
+	}
+}
+
+// selectBulk ends the TUI with a bulk action on the marked projects
+// Without marks the action applies to the project under the cursor; does nothing without results
+func (m *Model) selectBulk(action BulkAction) tea.Cmd {
+	if len(m.marked) == 0 {
+		if len(m.filtered) == 0 || m.cursor >= len(m.filtered) {
+			return nil
+		}
+		m.marked = []model.Project{m.filtered[m.cursor].Project}
+	}
+	m.bulkAction = action
+	m.quitting = true
+
+	m.rememberQuery(m.textInput.Value())
+	if m.history != nil {
+		query := strings.TrimSpace(m.textInput.Value())
+		for _, p := range m.marked {
+			m.history.RecordSelectionWithQuery(query, p.Key())
+		}
+		_ = m.history.Save() // Silently fail - don't prevent selection
+	}
+	return tea.Quit
+}
+
+// Marked returns the projects of the bulk action, in the order they were marked
+func (m Model) Marked() []model.Project {
+	return m.marked
+}
+
+// BulkAction returns the bulk action chosen on exit (BulkNone for a single selection)
+func (m Model) BulkAction() BulkAction {
+	return m.bulkAction
+}
+
+// markedCount returns the header note for marked projects (e.g., ", 3 marked")
+func (m Model) markedCount() string {
+	if len(m.marked) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %d marked", len(m.marked))
+}
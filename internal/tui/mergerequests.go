@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/search"
+)
+
+// MergeRequestsModel is the TUI for --mrs: fuzzy search over the user's open merge requests
+type MergeRequestsModel struct {
+	textInput     textinput.Model      // Search input field
+	styles        Styles               // Pre-configured styles
+	colorScheme   *ColorScheme         // Adaptive color scheme
+	version       string               // Application version
+	mrs           []model.MergeRequest // All cached merge requests
+	filtered      []model.MergeRequest // Merge requests matching the query
+	selectedURL   string               // Selected merge request URL (when user presses Enter)
+	cursor        int                  // Current cursor position in filtered list
+	viewportStart int                  // Index of first visible item
+	width         int                  // Terminal width
+	height        int                  // Terminal height
+	quitting      bool                 // Whether user is quitting
+	showHelp      bool                 // Whether to show help text
+}
+
+// NewMergeRequests creates the merge request finder with an optional initial query
+func NewMergeRequests(mrs []model.MergeRequest, initialQuery string, version string) MergeRequestsModel {
+	colorScheme := NewColorScheme()
+	styles := colorScheme.GetStyles()
+
+	ti := textinput.New()
+	ti.Placeholder = "Search merge requests..."
+	ti.Focus()
+	ti.CharLimit = 156
+	ti.Width = 50
+	ti.Prompt = "!> "
+	ti.PromptStyle = styles.Prompt
+	if initialQuery != "" {
+		ti.SetValue(initialQuery)
+	}
+
+	m := MergeRequestsModel{
+		textInput:   ti,
+		styles:      styles,
+		colorScheme: colorScheme,
+		version:     version,
+		mrs:         mrs,
+	}
+	m.filter()
+	return m
+}
+
+// Init initializes the model (required by tea.Model interface)
+func (m MergeRequestsModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages and updates the model
+func (m MergeRequestsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "enter":
+			if len(m.filtered) > 0 && m.cursor < len(m.filtered) {
+				m.selectedURL = m.filtered[m.cursor].WebURL
+			}
+			m.quitting = true
+			return m, tea.Quit
+
+		case "down", "ctrl+n":
+			if m.cursor < len(m.filtered)-1 {
+				m.cursor++
+				if visible := m.listHeight(); m.cursor >= m.viewportStart+visible {
+					m.viewportStart = m.cursor - visible + 1
+				}
+			}
+
+		case "up", "ctrl+p":
+			if m.cursor > 0 {
+				m.cursor--
+				if m.cursor < m.viewportStart {
+					m.viewportStart = m.cursor
+				}
+			}
+
+		case "?":
+			m.showHelp = !m.showHelp
+
+		default:
+			prevValue := m.textInput.Value()
+			m.textInput, cmd = m.textInput.Update(msg)
+			// The list is small (at most a few hundred items): filter on every keystroke
+			if m.textInput.Value() != prevValue {
+				m.filter()
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, cmd
+}
+
+// filter applies the current query to the merge requests
+func (m *MergeRequestsModel) filter() {
+	m.filtered = search.FilterMergeRequests(m.mrs, m.textInput.Value())
+	m.cursor = 0
+	m.viewportStart = 0
+}
+
+// listHeight returns the number of lines available for the list
+func (m MergeRequestsModel) listHeight() int {
+	usedLines := 6 // Title, separator, empty, search, 2 empty
+	if m.showHelp {
+		usedLines += 3
+	}
+	if m.height-usedLines < 1 {
+		return 1
+	}
+	return m.height - usedLines
+}
+
+// View renders the TUI
+func (m MergeRequestsModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+
+	titleLeft := fmt.Sprintf("%s %s %s",
+		m.colorScheme.GitLabWave,
+		m.styles.Title.Render("glf"),
+		m.styles.Version.Render(m.version))
+	titleRight := m.styles.Count.Render(fmt.Sprintf("%d/%d merge requests", len(m.filtered), len(m.mrs)))
+
+	b.WriteString(titleLeft)
+	if spacing := m.width - lipgloss.Width(titleLeft) - lipgloss.Width(titleRight); spacing > 0 {
+		b.WriteString(strings.Repeat(" ", spacing))
+	} else {
+		b.WriteString(" ")
+	}
+	b.WriteString(titleRight)
+	b.WriteString("\n")
+
+	if m.width > 0 {
+		b.WriteString(m.styles.Help.Render(strings.Repeat("─", m.width)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.textInput.View())
+	b.WriteString("\n\n")
+
+	if len(m.mrs) == 0 {
+		b.WriteString(m.styles.Help.Render("  No open merge requests assigned to or created by you"))
+		b.WriteString("\n")
+	}
+
+	end := m.viewportStart + m.listHeight()
+	if end > len(m.filtered) {
+		end = len(m.filtered)
+	}
+	query := strings.TrimSpace(m.textInput.Value())
+	for i := m.viewportStart; i < end; i++ {
+		mr := m.filtered[i]
+
+		line := " "
+		if mr.Draft {
+			line += "[draft] "
+		}
+		line += renderFuzzyMatch(mr.DisplayString(), query, m.styles.Normal, m.styles.Highlight)
+		if len(mr.Labels) > 0 {
+			line += m.styles.Counter.Render(" ~" + strings.Join(mr.Labels, " ~"))
+		}
+
+		if i == m.cursor {
+			b.WriteString(m.styles.Cursor.Render("▌"))
+			b.WriteString(m.styles.Selected.Width(m.width - 2).Render(line))
+		} else {
+			b.WriteString(" ")
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+
+	if m.showHelp {
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.Help.Render("↑/↓: navigate • enter: open merge request • esc: quit • ?: toggle help"))
+	}
+
+	return b.String()
+}
+
+// SelectedURL returns the selected merge request URL (or empty string if none)
+func (m MergeRequestsModel) SelectedURL() string {
+	return m.selectedURL
+}
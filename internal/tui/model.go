@@ -1,19 +1,23 @@
 package tui
 
 import (
+	"context"
 	"fmt"
-	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/igusev/glf/internal/config"
 	"github.com/igusev/glf/internal/history"
 	"github.com/igusev/glf/internal/index"
 	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/paths"
 	"github.com/igusev/glf/internal/search"
 )
 
@@ -24,6 +28,8 @@ type SyncStartMsg struct{}
 type SyncCompleteMsg struct {
 	Err      error
 	Projects []model.Project
+	Added    int // Projects new to the index (shown in the sync toast)
+	Updated  int // Indexed projects whose name, description, topics or flags changed
 }
 
 // HistoryLoadedMsg is sent when history finishes loading
@@ -38,8 +44,19 @@ type debounceTickMsg struct {
 
 // indexReopenedMsg is sent when the index has been reopened after sync
 type indexReopenedMsg struct {
-	descIndex *index.DescriptionIndex
-	err       error
+	descIndex  *index.DescriptionIndex
+	groupIndex *index.DescriptionIndex // Reopened group index (nil if group search is disabled)
+	err        error
+}
+
+// RemoteSearchFunc performs a live GitLab project search (read-through fallback)
+type RemoteSearchFunc func(query string) ([]model.Project, error)
+
+// remoteResultsMsg is sent when a live GitLab search finishes
+type remoteResultsMsg struct {
+	query    string
+	projects []model.Project
+	err      error
 }
 
 // Model represents the TUI state
@@ -62,17 +79,99 @@ type Model struct {
 	onSync             func() tea.Cmd          // Callback to trigger sync
 	cursor             int                     // Current cursor position in filtered list
 	viewportStart      int                     // Index of first visible item in viewport
+	jumping            bool                    // Whether a result number is typed (Ctrl+G)
+	jumpInput          string                  // Result number typed so far (Ctrl+G)
 	width              int                     // Terminal width
 	height             int                     // Terminal height
 	filterVersion      int                     // Monotonic counter for keystroke debouncing
+	searchCancel       context.CancelFunc      // Cancels the running async search (nil = none)
+	searching          bool                    // Whether an async search is running
 	emptyResultsCached bool                    // Whether cachedEmptyResults is valid
 	quitting       bool                         // Whether user is quitting
 	syncing        bool                         // Whether sync is in progress
 	autoSync       bool                         // Whether to auto-sync on start
+	syncInterval   time.Duration                // Time between syncs after the last one finished (0 = no interval sync)
+	syncTick       int                          // Version of the pending interval sync tick (syncTickMsg)
 	historyLoading bool                         // Whether history is being loaded
 	showHidden     bool                         // Whether to show hidden projects (excluded, archived, non-member)
 	showScores     bool                         // Whether to show score breakdown
 	showHelp       bool                         // Whether to show help text
+	sortByMRs      bool                         // Whether to sort results by open merge requests (insights)
+	onlyArchived   bool                         // Whether to show only archived projects (Alt+A)
+	onlyNonMember  bool                         // Whether to show only projects the user is not a member of (Alt+G)
+	onlyStarred    bool                         // Whether to show only starred projects (Alt+S)
+	regexMode      bool                         // Whether the query is a regular expression matched against project paths
+	regexErr       error                        // Compile error of the current regex (regex mode only)
+	hiddenMatches  int                          // Matches removed by the hidden filter (shown as a hint when nothing else matches)
+	suggestions    []string                     // Closest project paths when nothing matches ("Did you mean")
+
+	remoteSearch    RemoteSearchFunc      // Live GitLab search used when a query has no local results (nil = disabled)
+	remoteQuery     string                // Query of the last remote search (avoids repeated API calls)
+	remoteResults   []index.CombinedMatch // Results of the last remote search
+	remoteSearching bool                  // Whether a remote search is in progress
+
+	fetchIssues        IssuesFunc     // Live issue fetcher for issues mode (nil = disabled)
+	issuesProject      *model.Project // Project whose issues are shown (nil = project list)
+	issues             []model.Issue  // Open issues of issuesProject
+	filteredIssues     []model.Issue  // Issues matching the current query
+	issuesLoading      bool           // Whether issues are being fetched
+	issuesErr          error          // Fetch error of the issue list
+	issueCursor        int            // Cursor position in filteredIssues
+	issueViewportStart int            // Index of first visible issue
+	projectQuery       string         // Project query saved while in issues mode
+	selectedURL        string         // Selected issue or file URL (empty when a project was selected)
+
+	fetchReadme    ReadmeFunc        // Live README fetcher for the README preview (nil = disabled)
+	readmeProject  *model.Project    // Project whose README is shown (nil = project list)
+	readmeViewport viewport.Model    // Scrollable rendered README
+	readmeLoading  bool              // Whether the README is being fetched
+	readmeErr      error             // Fetch error of the README
+	readmeCache    map[string]string // Fetched READMEs by project path (raw Markdown)
+
+	avatars       bool              // Whether the README preview shows project avatars (tui.avatars)
+	fetchAvatar   AvatarFunc        // Live avatar fetcher (nil = colored initials only)
+	imageProtocol ImageProtocol     // Terminal graphics protocol for avatar images
+	avatarCache   map[string]string // Encoded avatars by project path ("" = no image, initials shown)
+
+	groupIndex    *index.DescriptionIndex // Synced group index for group search (nil = disabled)
+	groupsMode    bool                    // Whether groups are searched instead of projects (Alt+O)
+	selectedGroup bool                    // Whether the selected path is a group
+
+	groupIndexClosed bool // Whether the group index was closed for a sync (reopened afterwards)
+
+	editEnabled   bool // Whether Alt+E opens the local clone in an editor (workspace_dir is set)
+	editRequested bool // Whether the selection should be opened in an editor instead of the browser
+
+	open          OpenFunc       // Opens a result in the browser without quitting (Ctrl+O; nil = disabled)
+	openPage      PageOpenFunc   // Opens a sub-page of a result without quitting (pageKeys; nil = disabled)
+	star          StarFunc       // Stars or unstars a project on GitLab (Ctrl+T; nil = disabled)
+	customOpeners []CustomOpener // User-defined openers bound to keys (openers in the config)
+
+	collapseForks bool            // Whether forks are listed under their upstream (search.collapse_forks)
+	expandedForks map[string]bool // Results whose collapsed forks are shown (Alt+F)
+	forkRows      map[string]bool // Shown forks listed under an expanded upstream (rendered indented)
+
+	explain *scoreExplanation // Score breakdown of a result (nil = project list), Ctrl+E with --scores
+
+	fileMode    bool            // Whether Enter opens a file of the selected project (--file)
+	filePath    string          // File to open from the command line ("" = ask after selection)
+	fileProject *model.Project  // Project whose file path is being entered (nil = project list)
+	fileInput   textinput.Model // File path input
+	fileErr     error           // Invalid file path entered
+
+	toast        string // Transient message above the search input (e.g., sync results; "" = none)
+	toastError   bool   // Whether the toast reports an error
+	toastVersion int    // Incremented per toast so older expiry ticks are ignored
+
+	marked     []model.Project // Projects marked for a bulk action (Ctrl+Space), in marking order
+	bulkAction BulkAction      // Bulk action chosen on exit (BulkNone = single selection)
+
+	restorePath string // Result to select once it appears (restored session; cleared by any key press)
+
+	queryHistory      []string // Past search queries, oldest first (Up/Down recall on an empty input)
+	queryHistoryLimit int      // Number of queries kept (tui.query_history; 0 = no recall)
+	queryRecall       int      // Recalled query counted from the newest (1 = newest; 0 = not recalling)
+	recordedQueries   []string // Queries of the selections made in this session
 }
 
 // New creates a new TUI model with the given projects and optional initial query
@@ -96,9 +195,14 @@ func New(projects []model.Project, initialQuery string, onSync func() tea.Cmd, c
 		ti.SetValue(initialQuery)
 	}
 
-	// Initialize history
-	historyPath := filepath.Join(cacheDir, "history.gob")
+	// Initialize history (per-user state, kept apart from a shared read-only cache)
+	stateDir := cacheDir
+	if cfg.Cache.StateDir != "" || cfg.Cache.IsReadOnly() {
+		stateDir = cfg.Cache.GetStateDir()
+	}
+	historyPath := paths.HistoryPath(stateDir)
 	hist := history.New(historyPath)
+	hist.SetSource(history.SourceTUI)
 
 	// Extract GitLab URL for display (remove protocol and trailing slash)
 	gitlabURL := cfg.GitLab.URL
@@ -134,6 +238,29 @@ func New(projects []model.Project, initialQuery string, onSync func() tea.Cmd, c
 	return m
 }
 
+// SetRemoteSearch enables the read-through fallback: queries with no local results
+// are searched on GitLab directly and shown as remote results
+func (m *Model) SetRemoteSearch(fn RemoteSearchFunc) {
+	m.remoteSearch = fn
+}
+
+// SetRegexMode sets whether the query is matched as a regular expression against project paths
+func (m *Model) SetRegexMode(enabled bool) {
+	m.regexMode = enabled
+	m.updatePrompt()
+	m.emptyResultsCached = false
+	m.filter()
+}
+
+// updatePrompt marks the input prompt in regex mode
+func (m *Model) updatePrompt() {
+	if m.regexMode {
+		m.textInput.Prompt = "re> "
+	} else {
+		m.textInput.Prompt = "> "
+	}
+}
+
 // autoSyncMsg is sent on startup to trigger auto-sync
 type autoSyncMsg struct{}
 
@@ -150,11 +277,16 @@ func (m Model) Init() tea.Cmd {
 		})
 	}
 
-	// If auto-sync is enabled, trigger it
+	// If auto-sync is enabled, trigger it; otherwise the first interval sync is due after an interval
 	if m.autoSync && m.onSync != nil {
 		cmds = append(cmds, func() tea.Msg {
 			return autoSyncMsg{}
 		})
+	} else if m.syncInterval > 0 && m.onSync != nil {
+		version := m.syncTick
+		cmds = append(cmds, tea.Tick(m.syncInterval, func(_ time.Time) tea.Msg {
+			return syncTickMsg{version: version}
+		}))
 	}
 
 	return tea.Batch(cmds...)
@@ -166,6 +298,25 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.restorePath = "" // The user takes over from the restored session
+		if m.inReadmeMode() {
+			return m.updateReadme(msg)
+		}
+		if m.inExplainMode() {
+			return m.updateExplain(msg)
+		}
+		if m.inFileMode() {
+			return m.updateFile(msg)
+		}
+		if m.inIssuesMode() {
+			return m.updateIssues(msg)
+		}
+		if m.jumping {
+			return m.updateJump(msg)
+		}
+		if m.groupsMode && projectOnlyKey(msg.String()) {
+			return m, nil
+		}
 		switch msg.String() {
 		case "ctrl+c", "esc":
 			m.quitting = true
@@ -183,24 +334,60 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.onSync != nil && !m.syncing {
 				m.syncing = true
 				m.syncError = nil
-				// Close index to allow sync exclusive access
-				if m.descIndex != nil {
-					_ = m.descIndex.Close()
-					m.descIndex = nil
-				}
+				// Close indexes to allow sync exclusive access
+				m.closeIndexesForSync()
 				return m, m.onSync()
 			}
 
-		case "enter":
+		case "alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9":
+			// Alt+1-9 select the numbered result on screen, like Enter on it
+			n, ok := hotkeyNumber(msg.String())
+			if !ok || m.viewportStart+n > len(m.filtered) {
+				break
+			}
+			m.cursor = m.viewportStart + n - 1
+			fallthrough
+
+		case "enter", "alt+e":
+			// Enter on a subgroup of a "group/" listing lists that subgroup
+			if msg.String() != "alt+e" && m.cursor < len(m.filtered) && m.filtered[m.cursor].Subgroup {
+				return m, m.setQuery(m.filtered[m.cursor].Project.Path + "/")
+			}
+
+			// Alt+E opens the local clone in an editor (projects only)
+			if msg.String() == "alt+e" {
+				if !m.editEnabled || m.groupsMode {
+					break
+				}
+				m.editRequested = true
+			} else if m.fileMode && !m.groupsMode {
+				// --file: open a file of the project instead of its root
+				cmd = m.selectFileProject()
+				return m, cmd
+			} else if len(m.marked) > 0 && !m.groupsMode && msg.String() == "enter" {
+				// Open every marked project
+				cmd = m.selectBulk(BulkOpen)
+				return m, cmd
+			}
+
 			// Select current project
 			if len(m.filtered) > 0 && m.cursor < len(m.filtered) {
 				selectedProject := m.filtered[m.cursor].Project
 				m.selected = selectedProject.Path
+				m.selectedGroup = m.groupsMode
+
+				// Remote results are injected into the index so they are found locally next time
+				if m.filtered[m.cursor].Remote && m.descIndex != nil {
+					if err := m.descIndex.AddBatch([]index.DescriptionDocument{index.NewDocument(selectedProject)}); err != nil {
+						_ = err // explicitly ignore error - next sync will add it anyway
+					}
+				}
 
 				// Record selection in history with query context for smart boosting
+				m.rememberQuery(m.textInput.Value())
 				if m.history != nil && m.selected != "" {
 					query := strings.TrimSpace(m.textInput.Value())
-					m.history.RecordSelectionWithQuery(query, m.selected)
+					m.history.RecordSelectionWithQuery(query, selectedProject.Key())
 					if err := m.history.Save(); err != nil {
 						// Silently fail - don't prevent selection
 						_ = err // explicitly ignore error
@@ -210,6 +397,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			return m, tea.Quit
 
+		case "ctrl+o":
+			// Open the selected result in the browser and keep the finder running
+			cmd = m.openInBackground(m.open, "")
+
+		case "ctrl+@", "alt+m":
+			// Mark/unmark for a bulk action (Ctrl+Space arrives as ctrl+@)
+			m.toggleMark()
+
+		case "alt+u":
+			// Print the URLs of the marked projects (or of the selected one)
+			if cmd = m.selectBulk(BulkPrintURLs); cmd != nil {
+				return m, cmd
+			}
+
+		case "alt+c":
+			// Copy clone commands of the marked projects (or of the selected one)
+			if cmd = m.selectBulk(BulkCopyClone); cmd != nil {
+				return m, cmd
+			}
+
+		case "tab":
+			// Two-level finder: browse the open issues of the selected project
+			cmd = m.enterIssuesMode()
+
+		case "alt+v":
+			// Preview the README of the selected project
+			cmd = m.enterReadmeMode()
+
 		case "ctrl+x":
 			// Toggle exclusion: exclude if visible, un-exclude if already excluded
 			if m.config != nil && len(m.filtered) > 0 && m.cursor < len(m.filtered) {
@@ -245,27 +460,96 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.viewportStart = 0
 
+		case "alt+p":
+			// Toggle pin: pinned projects stay at the top of results
+			if m.config != nil && len(m.filtered) > 0 && m.cursor < len(m.filtered) {
+				projectPath := m.filtered[m.cursor].Project.Path
+				if m.config.IsPinned(projectPath) {
+					if err := m.config.RemovePin(projectPath); err != nil {
+						_ = err // explicitly ignore error
+						// Silently fail - don't prevent UI operation
+					}
+				} else {
+					if err := m.config.AddPin(projectPath); err != nil {
+						_ = err // explicitly ignore error
+						// Silently fail - don't prevent UI operation
+					}
+				}
+				m.emptyResultsCached = false
+				m.filter()
+			}
+
+		case "alt+r":
+			// Toggle regex matching on project paths
+			m.SetRegexMode(!m.regexMode)
+			m.cursor = 0
+			m.viewportStart = 0
+
+		case "alt+a":
+			m.toggleFilter(filterArchived)
+
+		case "alt+g":
+			m.toggleFilter(filterNonMember)
+
+		case "alt+s":
+			m.toggleFilter(filterStarred)
+
+		case "ctrl+t":
+			// Star or unstar the selected project on GitLab
+			return m, m.toggleStar()
+
+		case "alt+f":
+			// Show or hide the forks collapsed under the selected result
+			m.toggleForks()
+
+		case "ctrl+s":
+			// Toggle sorting by open merge requests ("what needs review")
+			m.sortByMRs = !m.sortByMRs
+			m.emptyResultsCached = false
+			m.filter()
+			m.cursor = 0
+			m.viewportStart = 0
+
+		case "alt+o":
+			// Toggle group search (groups are synced into their own index)
+			if m.groupIndex != nil || m.groupsMode {
+				m.SetGroupsMode(!m.groupsMode)
+				m.cursor = 0
+				m.viewportStart = 0
+			}
+
 		case "?":
 			// Toggle help text
 			m.showHelp = !m.showHelp
 
+		case "ctrl+e":
+			// Explain the score of the selected result (--scores); otherwise end of line in the input
+			if m.showScores && !m.groupsMode {
+				m.enterExplainMode()
+			} else {
+				m.textInput, cmd = m.textInput.Update(msg)
+			}
+
 		case "down", "ctrl+n":
+			// Down steps back through recalled queries; Ctrl+N always moves the cursor
+			if msg.String() == "down" {
+				if recallCmd, ok := m.recallNewer(); ok {
+					return m, recallCmd
+				}
+			}
 			if m.cursor < len(m.filtered)-1 {
 				m.cursor++
 				// Adjust viewport if cursor scrolled below visible area
-				// Calculate available lines for the viewport
-				usedLines := 6 // Title, separator, empty, search, 2 empty
-				if m.showHelp {
-					usedLines += 3
-				}
-				maxAvailableLines := m.height - usedLines
-				if maxAvailableLines < 1 {
-					maxAvailableLines = 1
-				}
-				m.ensureCursorVisible(maxAvailableLines)
+				m.ensureCursorVisible(m.listHeight())
 			}
 
 		case "up", "ctrl+p":
+			// Up on an empty input recalls past queries; Ctrl+P always moves the cursor
+			if msg.String() == "up" {
+				if recallCmd, ok := m.recallOlder(); ok {
+					return m, recallCmd
+				}
+			}
 			if m.cursor > 0 {
 				m.cursor--
 				// Adjust viewport if cursor scrolled above visible area
@@ -275,12 +559,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 		default:
+			// PgUp/PgDn/Home/End and Ctrl+G move through long lists
+			if m.pagingKey(msg.String()) {
+				break
+			}
+
+			// Page keys open a sub-page of the result (container registry, packages, releases)
+			if p, ok := pageForKey(msg.String()); ok && m.openPage != nil {
+				return m, m.openPageInBackground(p)
+			}
+
+			// Keys bound to custom openers run them and keep the finder running
+			if o, ok := m.customOpener(msg.String()); ok {
+				return m, m.openInBackground(o.Open, o.Name)
+			}
+
 			// Update text input
 			prevValue := m.textInput.Value()
 			m.textInput, cmd = m.textInput.Update(msg)
 
 			// Only debounce filter if text actually changed
 			if m.textInput.Value() != prevValue {
+				m.cancelSearch() // Its results are stale now
 				m.cursor = 0
 				m.viewportStart = 0
 				m.filterVersion++
@@ -294,15 +594,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case autoSyncMsg:
 		// Trigger background sync on startup
-		if m.onSync != nil && !m.syncing {
-			m.syncing = true
-			m.syncError = nil
-			// Close index to allow sync exclusive access
-			if m.descIndex != nil {
-				_ = m.descIndex.Close()
-				m.descIndex = nil
+		if syncCmd := m.startAutoSync(); syncCmd != nil {
+			return m, syncCmd
+		}
+
+	case syncTickMsg:
+		// Interval sync (sync.auto: interval); a running sync reschedules it when it finishes
+		if msg.version == m.syncTick {
+			if syncCmd := m.startAutoSync(); syncCmd != nil {
+				return m, syncCmd
 			}
-			return m, m.onSync()
 		}
 
 	case SyncCompleteMsg:
@@ -314,25 +615,79 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.projects = msg.Projects
 			m.syncError = nil
 		}
-		// Reopen index after sync (regardless of success/failure)
+		toastCmd := m.showToast(syncToast(msg), msg.Err != nil)
+		tickCmd := m.scheduleSyncTick()
+		// Reopen index after sync (regardless of success/failure); the current query is re-run then
 		cacheDir := m.cacheDir
-		return m, func() tea.Msg {
-			indexPath := filepath.Join(cacheDir, "description.bleve")
+		reopenGroups := m.groupsMode || m.groupIndexClosed
+		return m, tea.Batch(toastCmd, tickCmd, func() tea.Msg {
+			indexPath := paths.IndexPath(cacheDir)
 			di, _, err := index.NewDescriptionIndexWithAutoRecreate(indexPath)
-			return indexReopenedMsg{descIndex: di, err: err}
+			msg := indexReopenedMsg{descIndex: di, err: err}
+			if groupIndexPath := paths.GroupIndexPath(cacheDir); reopenGroups && index.Exists(groupIndexPath) {
+				msg.groupIndex, _, _ = index.NewDescriptionIndexWithAutoRecreate(groupIndexPath)
+			}
+			return msg
+		})
+
+	case toastExpiredMsg:
+		if msg.version == m.toastVersion {
+			m.toast = ""
 		}
 
 	case indexReopenedMsg:
 		if msg.err == nil {
 			m.descIndex = msg.descIndex
 		}
+		if msg.groupIndex != nil {
+			m.groupIndex = msg.groupIndex
+			m.groupIndexClosed = false
+		}
 		m.filter()
 
 	case debounceTickMsg:
 		if msg.version == m.filterVersion {
-			m.filter()
+			cmd = m.searchCmd()
 		}
 
+	case searchResultsMsg:
+		cmd = m.handleSearchResults(msg)
+
+	case remoteResultsMsg:
+		if msg.query == m.remoteQuery {
+			m.remoteSearching = false
+			if msg.err != nil {
+				m.remoteResults = nil
+			} else {
+				m.remoteResults = search.RemoteMatches(msg.projects)
+			}
+			if strings.TrimSpace(m.textInput.Value()) == msg.query && len(m.filtered) == 0 {
+				m.filtered = m.remoteResults
+				m.cursor = 0
+				m.viewportStart = 0
+			}
+		}
+
+	case issuesLoadedMsg:
+		if m.inIssuesMode() && msg.projectPath == m.issuesProject.Path {
+			m.issuesLoading = false
+			m.issuesErr = msg.err
+			m.issues = msg.issues
+			m.filterIssues()
+		}
+
+	case readmeLoadedMsg:
+		m.handleReadmeLoaded(msg)
+
+	case openedMsg:
+		return m, m.handleOpened(msg)
+
+	case starredMsg:
+		return m, m.handleStarred(msg)
+
+	case avatarLoadedMsg:
+		m.handleAvatarLoaded(msg)
+
 	case HistoryLoadedMsg:
 		m.historyLoading = false
 		m.emptyResultsCached = false
@@ -345,6 +700,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.resizeReadme()
 	}
 
 	return m, cmd
@@ -354,57 +710,104 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) filter() {
 	query := strings.TrimSpace(m.textInput.Value())
 
-	// Get query-specific history scores if available (includes global + query-specific boost)
-	var historyScores map[string]int
-	if m.history != nil && !m.historyLoading {
-		historyScores = m.history.GetAllScoresForQuery(query)
-	} else {
-		historyScores = make(map[string]int)
-	}
+	historyScores := m.historyScores(query)
+
+	m.hiddenMatches = 0
+	m.regexErr = nil
+	m.suggestions = nil
 
 	// For empty queries, use cached results if available
 	if query == "" && m.emptyResultsCached {
 		m.filtered = m.cachedEmptyResults
+		m.markForkRows()
 		return
 	}
 
-	var allMatches []index.CombinedMatch
-	var err error
-	if m.descIndex != nil {
-		allMatches, err = search.CombinedSearchWithIndex(query, m.projects, historyScores, m.cacheDir, m.descIndex)
-	} else if m.syncing {
+	if m.groupsMode {
+		m.filterGroups(query, historyScores)
 		return
-	} else {
-		allMatches, err = search.CombinedSearch(query, m.projects, historyScores, m.cacheDir)
+	}
+
+	// "group/" lists the group like a directory instead of ranking
+	if m.listGroup(query) {
+		return
+	}
+
+	run := m.searcher()
+	if run == nil {
+		return
+	}
+	allMatches, err := run(context.Background(), query, historyScores)
+	m.applyMatches(query, allMatches, err)
+}
+
+// historyScores returns the history scores for query (global + query-specific boost)
+// Empty while history is loading
+func (m *Model) historyScores(query string) map[string]int {
+	if m.history != nil && !m.historyLoading {
+		return m.history.GetAllScoresForQuery(query)
+	}
+	return make(map[string]int)
+}
+
+// applyMatches shows the search results for query: applies the filters, hidden projects,
+// sorting and pins, and caches the results of the empty query
+func (m *Model) applyMatches(query string, allMatches []index.CombinedMatch, err error) {
+	m.hiddenMatches = 0
+	m.regexErr = nil
+	if m.regexMode {
+		m.regexErr = err
 	}
 	if err != nil {
 		allMatches = []index.CombinedMatch{}
 	}
 
-	// Apply hidden projects filter if needed (unless showHidden is true)
-	// Filter out: excluded, archived, and non-member projects
-	filtered := allMatches
-	if !m.showHidden {
-		temp := make([]index.CombinedMatch, 0, len(filtered))
-		for _, match := range filtered {
-			// Skip if excluded by config
-			if m.config != nil && m.config.IsExcluded(match.Project.Path) {
-				continue
-			}
-			// Skip if archived
-			if match.Project.Archived {
-				continue
-			}
-			// Skip if non-member (Member field is false)
-			if !match.Project.Member {
-				continue
-			}
-			temp = append(temp, match)
+	// Apply the dedicated filters (archived/non-member/starred only), then the
+	// hidden projects filter unless showHidden is true
+	// Hidden: excluded, archived, and non-member projects
+	filtered := make([]index.CombinedMatch, 0, len(allMatches))
+	for _, match := range allMatches {
+		if !m.matchesFilters(match) {
+			continue
 		}
-		filtered = temp
+		if m.isHiddenMatch(match) {
+			m.hiddenMatches++
+			continue
+		}
+		filtered = append(filtered, match)
+	}
+
+	// Sort by open merge requests, keeping relevance order among equal counts
+	if m.sortByMRs {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return filtered[i].Project.OpenMRs > filtered[j].Project.OpenMRs
+		})
+	}
+
+	// Pinned projects always come first, whether or not they match
+	if m.config != nil {
+		filtered = search.ApplyPins(filtered, m.config.PinnedPaths, m.pinLookup())
+	}
+
+	// Forks are listed under their upstream (after pins, so pinned forks stay listed)
+	if m.collapseForks {
+		filtered = m.expandForks(search.CollapseForks(filtered))
+	}
+
+	// No local results: reuse remote results already fetched for this query
+	if len(filtered) == 0 && query != "" && query == m.remoteQuery && m.remoteResults != nil {
+		filtered = m.remoteResults
 	}
 
 	m.filtered = filtered
+	m.markForkRows()
+	m.restoreCursor()
+
+	// Nothing matches at all: suggest the closest project paths
+	m.suggestions = nil
+	if len(filtered) == 0 && m.hiddenMatches == 0 && query != "" && !m.regexMode {
+		m.suggestions = search.Suggest(query, m.suggestionCandidates(), search.DefaultSuggestions)
+	}
 
 	if query == "" {
 		m.cachedEmptyResults = filtered
@@ -412,6 +815,56 @@ func (m *Model) filter() {
 	}
 }
 
+// suggestionCandidates returns the projects "Did you mean" suggestions are picked from:
+// the cached empty-query results when available, otherwise all indexed projects
+func (m *Model) suggestionCandidates() []model.Project {
+	if m.emptyResultsCached {
+		projects := make([]model.Project, len(m.cachedEmptyResults))
+		for i, match := range m.cachedEmptyResults {
+			projects[i] = match.Project
+		}
+		return projects
+	}
+	if m.projects != nil || m.descIndex == nil {
+		return m.projects
+	}
+	projects, err := m.descIndex.GetAllProjects()
+	if err != nil {
+		return nil
+	}
+	return projects
+}
+
+// hiddenMatchesHint returns the hint shown when every match is hidden by the filter
+func hiddenMatchesHint(visible, hidden int, showHidden bool) string {
+	if visible > 0 || hidden == 0 || showHidden {
+		return ""
+	}
+	if hidden == 1 {
+		return "1 hidden project matches — Ctrl+H to show"
+	}
+	return fmt.Sprintf("%d hidden projects match — Ctrl+H to show", hidden)
+}
+
+// remoteSearchCmd starts a live GitLab search when the current query has no local results
+// Returns nil when the fallback is disabled or the query was already searched remotely
+func (m *Model) remoteSearchCmd() tea.Cmd {
+	query := strings.TrimSpace(m.textInput.Value())
+	// Hidden local matches win: the project exists, it is just filtered out
+	if m.remoteSearch == nil || m.groupsMode || m.regexMode || query == "" || len(m.filtered) > 0 || m.hiddenMatches > 0 || query == m.remoteQuery {
+		return nil
+	}
+
+	m.remoteQuery = query
+	m.remoteResults = nil
+	m.remoteSearching = true
+	remoteSearch := m.remoteSearch
+	return func() tea.Msg {
+		projects, err := remoteSearch(query)
+		return remoteResultsMsg{query: query, projects: projects, err: err}
+	}
+}
+
 // ensureCursorVisible adjusts viewportStart if cursor is not visible in viewport
 func (m *Model) ensureCursorVisible(maxAvailableLines int) {
 	if len(m.filtered) == 0 {
@@ -428,10 +881,7 @@ func (m *Model) ensureCursorVisible(maxAvailableLines int) {
 	linesUsed := 0
 	visibleItems := 0
 	for i := m.viewportStart; i < len(m.filtered) && linesUsed < maxAvailableLines; i++ {
-		itemLines := 1
-		if m.filtered[i].Snippet != "" {
-			itemLines = 2
-		}
+		itemLines := matchLines(m.filtered[i])
 		if linesUsed+itemLines > maxAvailableLines {
 			break
 		}
@@ -441,23 +891,35 @@ func (m *Model) ensureCursorVisible(maxAvailableLines int) {
 
 	// If cursor is beyond visible items, adjust viewport down
 	if m.cursor >= m.viewportStart+visibleItems {
-		// Move viewport so cursor is at bottom of screen
-		m.viewportStart = m.cursor - visibleItems + 1
-		if m.viewportStart < 0 {
-			m.viewportStart = 0
+		// Move viewport so cursor is at bottom of screen: walk back from the cursor
+		// while the items still fit (jumps can cross items of different heights)
+		m.viewportStart = m.cursor
+		linesUsed = matchLines(m.filtered[m.cursor])
+		for m.viewportStart > 0 {
+			itemLines := matchLines(m.filtered[m.viewportStart-1])
+			if linesUsed+itemLines > maxAvailableLines {
+				break
+			}
+			linesUsed += itemLines
+			m.viewportStart--
 		}
 	}
 }
 
 // renderMatch renders a matched project with visual indicators and optional snippet
 // Uses pre-computed styles from the Styles struct to avoid per-render allocations
-func renderMatch(match index.CombinedMatch, s Styles, query string, showScores bool, isHidden bool) string {
+// The namespace, name and snippet are truncated to fit cols (see fitDisplayString)
+func renderMatch(match index.CombinedMatch, s Styles, query string, showScores bool, isHidden bool, cols matchColumns) string {
 	var result strings.Builder
 
 	style := lipgloss.NewStyle()
 	highlightStyle := s.Highlight
 	snippetStyle := s.Snippet
 
+	if match.Pinned {
+		result.WriteString(s.Pin.Render("📌 "))
+	}
+
 	if match.Project.Starred {
 		if isHidden {
 			style = s.HiddenStarredText
@@ -470,13 +932,20 @@ func renderMatch(match index.CombinedMatch, s Styles, query string, showScores b
 		}
 	}
 
-	displayStr := match.Project.DisplayString()
-
-	if match.Source&index.MatchSourceName != 0 {
-		result.WriteString(renderFuzzyMatch(displayStr, query, style, highlightStyle))
-	} else {
-		result.WriteString(style.Render(displayStr))
+	// Markers after the name are rendered first: the name gets the width they leave
+	var trailing strings.Builder
+	if match.Project.IsFork() {
+		trailing.WriteString(s.Fork.Render(" ⑂"))
 	}
+	if len(match.Forks) > 0 {
+		trailing.WriteString(s.Fork.Render(fmt.Sprintf(" +%d ⑂", len(match.Forks))))
+	}
+	if match.Subgroup {
+		trailing.WriteString(s.Counter.Render(" " + match.Project.Description)) // Projects below the subgroup
+	}
+	trailing.WriteString(renderActivity(match.Project, s, time.Now()))
+	trailing.WriteString(renderCounters(match.Project, s))
+	trailing.WriteString(renderLabels(match.Project, s))
 
 	if showScores {
 		var scoreStyle lipgloss.Style
@@ -489,19 +958,40 @@ func renderMatch(match index.CombinedMatch, s Styles, query string, showScores b
 		} else {
 			scoreStyle = s.ScoreText
 		}
+		scoreText := fmt.Sprintf(" [S:%.3f H:%d", match.SearchScore, match.HistoryScore)
+		if match.PathBonus > 0 {
+			scoreText += fmt.Sprintf(" P:%.2f", match.PathBonus)
+		}
 		if match.StarredBonus > 0 {
-			scoreText := fmt.Sprintf(" [S:%.3f H:%d St:%d T:%.2f]",
-				match.SearchScore, match.HistoryScore, match.StarredBonus, match.TotalScore)
-			result.WriteString(scoreStyle.Render(scoreText))
-		} else {
-			scoreText := fmt.Sprintf(" [S:%.3f H:%d T:%.2f]",
-				match.SearchScore, match.HistoryScore, match.TotalScore)
-			result.WriteString(scoreStyle.Render(scoreText))
+			scoreText += fmt.Sprintf(" St:%d", match.StarredBonus)
 		}
+		if match.ArchivedPenalty > 0 {
+			scoreText += fmt.Sprintf(" A:-%.0f", match.ArchivedPenalty)
+		}
+		if match.ForkPenalty > 0 {
+			scoreText += fmt.Sprintf(" F:-%.0f", match.ForkPenalty)
+		}
+		scoreText += fmt.Sprintf(" T:%.2f]", match.TotalScore)
+		trailing.WriteString(scoreStyle.Render(scoreText))
+	}
+
+	displayWidth := 0
+	if cols.line > 0 {
+		displayWidth = max(1, cols.line-lipgloss.Width(result.String())-lipgloss.Width(trailing.String()))
+	}
+	displayStr := fitDisplayString(match.Project, displayWidth)
+
+	// Query words are highlighted in any spelling the search matched (keyboard layout, transliteration)
+	terms := search.HighlightTerms(query)
+	if match.Source&index.MatchSourceName != 0 {
+		result.WriteString(renderHighlighted(displayStr, terms, style, highlightStyle))
+	} else {
+		result.WriteString(style.Render(displayStr))
 	}
+	result.WriteString(trailing.String())
 
 	if match.Snippet != "" {
-		snippet := truncateSnippet(match.Snippet, 60)
+		snippet := snippetWindow(match.Snippet, terms, cols.snippetRunes())
 		result.WriteString("\n")
 
 		if match.Project.Starred {
@@ -513,41 +1003,75 @@ func renderMatch(match index.CombinedMatch, s Styles, query string, showScores b
 		} else if isHidden {
 			snippetStyle = s.HiddenSnippet
 		}
-		result.WriteString(snippetStyle.Render(snippet))
+		result.WriteString(renderHighlighted(snippet, terms, snippetStyle, highlightStyle))
 	}
 
 	return result.String()
 }
 
-// renderFuzzyMatch performs substring highlighting on display string
-func renderFuzzyMatch(displayStr, query string, style lipgloss.Style, highlightStyle lipgloss.Style) string {
-	if query == "" {
-		return style.Render(displayStr)
+// renderActivity renders the activity indicator: ● active, ◐ quiet, ○ stale (see model.Activity)
+// Returns an empty string when the last activity is unknown
+func renderActivity(p model.Project, s Styles, now time.Time) string {
+	switch p.Activity(now) {
+	case model.ActivityActive:
+		return s.ActivityActive.Render(" ●")
+	case model.ActivityQuiet:
+		return s.ActivityQuiet.Render(" ◐")
+	case model.ActivityStale:
+		return s.ActivityStale.Render(" ○")
+	default:
+		return ""
+	}
+}
+
+// renderCounters renders open MR/issue counters (GitLab notation: !MRs #issues)
+// Returns an empty string when insights are not available for the project
+func renderCounters(p model.Project, s Styles) string {
+	var parts []string
+	if p.OpenMRs > 0 {
+		parts = append(parts, fmt.Sprintf("!%d", p.OpenMRs))
+	}
+	if p.OpenIssues > 0 {
+		parts = append(parts, fmt.Sprintf("#%d", p.OpenIssues))
 	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return s.Counter.Render(" " + strings.Join(parts, " "))
+}
 
-	// For multi-token queries, just use first token for highlighting
-	tokens := strings.Fields(query)
-	if len(tokens) == 0 {
-		return style.Render(displayStr)
+// renderLabels renders the project's labels from annotations.yaml (e.g. " [tier-1] [deprecated]")
+// Returns an empty string for projects without labels
+func renderLabels(p model.Project, s Styles) string {
+	labels := search.Labels(p.Path)
+	if len(labels) == 0 {
+		return ""
 	}
-	matchToken := tokens[0]
+	return s.Label.Render(" [" + strings.Join(labels, "] [") + "]")
+}
 
-	// Find substring match (case-insensitive)
-	lowerDisplay := strings.ToLower(displayStr)
-	lowerToken := strings.ToLower(matchToken)
+// renderFuzzyMatch performs substring highlighting on display string
+func renderFuzzyMatch(displayStr, query string, style lipgloss.Style, highlightStyle lipgloss.Style) string {
+	return renderHighlighted(displayStr, strings.Fields(query), style, highlightStyle)
+}
 
-	idx := strings.Index(lowerDisplay, lowerToken)
-	if idx < 0 {
+// renderHighlighted highlights every occurrence range of terms in text, as --vim reports them
+func renderHighlighted(text string, terms []string, style lipgloss.Style, highlightStyle lipgloss.Style) string {
+	ranges := search.MatchRanges(text, terms)
+	if len(ranges) == 0 {
 		// No match found - return unstyled
-		return style.Render(displayStr)
+		return style.Render(text)
 	}
 
-	// Highlight the matched substring
-	before := displayStr[:idx]
-	matched := displayStr[idx : idx+len(matchToken)]
-	after := displayStr[idx+len(matchToken):]
-
-	return style.Render(before) + highlightStyle.Render(matched) + style.Render(after)
+	var b strings.Builder
+	pos := 0
+	for _, r := range ranges {
+		b.WriteString(style.Render(text[pos:r.Start]))
+		b.WriteString(highlightStyle.Render(text[r.Start:r.End]))
+		pos = r.End
+	}
+	b.WriteString(style.Render(text[pos:]))
+	return b.String()
 }
 
 // View renders the TUI
@@ -561,7 +1085,7 @@ func (m Model) View() string {
 
 	// Status indicator: ○ idle, ● active (green) or error (red)
 	var statusIndicator string
-	if m.syncing || m.historyLoading {
+	if m.syncing || m.historyLoading || m.searching || m.remoteSearching || m.issuesLoading || m.readmeLoading {
 		statusIndicator = m.styles.StatusActive.Render("●")
 	} else if m.syncError != nil {
 		statusIndicator = m.styles.StatusError.Render("●")
@@ -574,11 +1098,23 @@ func (m Model) View() string {
 		m.colorScheme.GitLabWave,
 		m.styles.Title.Render("glf"),
 		m.styles.Version.Render(m.version))
+	if filters := m.renderFilters(); filters != "" && !m.inIssuesMode() {
+		titleLeft += " " + filters
+	}
 
 	// Project count (always shown)
-	projectCount := fmt.Sprintf("%d/%d projects",
+	projectCount := fmt.Sprintf("%d/%d projects%s",
 		len(m.filtered),
-		len(m.projects))
+		len(m.projects),
+		m.markedCount())
+	if len(m.filtered) > 0 && m.filtered[0].Remote {
+		projectCount = fmt.Sprintf("%d remote results", len(m.filtered))
+	}
+	if m.inIssuesMode() {
+		projectCount = m.issuesCount()
+	} else if m.groupsMode {
+		projectCount = fmt.Sprintf("%d groups", len(m.filtered))
+	}
 
 	// Additional info (for wider screens)
 	serverInfo := fmt.Sprintf("[ @%s on %s ]", m.username, m.gitlabURL)
@@ -634,11 +1170,33 @@ func (m Model) View() string {
 		b.WriteString("\n")
 	}
 
-	// Search input (fixed at top, after header)
+	// Search input (fixed at top, after header); the line above it shows toasts
+	if m.jumping {
+		b.WriteString(m.renderJump())
+	} else {
+		b.WriteString(m.renderToast())
+	}
 	b.WriteString("\n")
 	b.WriteString(m.textInput.View())
 	b.WriteString("\n\n")
 
+	if m.inReadmeMode() {
+		b.WriteString(m.renderReadme())
+		return b.String()
+	}
+	if m.inExplainMode() {
+		b.WriteString(m.renderExplain())
+		return b.String()
+	}
+	if m.inFileMode() {
+		b.WriteString(m.renderFile())
+		return b.String()
+	}
+	if m.inIssuesMode() {
+		b.WriteString(m.renderIssues())
+		return b.String()
+	}
+
 	// Calculate available lines for project list precisely
 	usedLines := 0
 	usedLines++    // Title line
@@ -662,15 +1220,22 @@ func (m Model) View() string {
 	// viewportStart is maintained in Update() when cursor moves
 	start := m.viewportStart
 
-	// Render visible projects, stopping when we run out of space
+	// Highlight the text part only (filter prefixes like group: are not in the name)
+	// Regex patterns are not highlighted
+	query := ""
+	if !m.regexMode {
+		query = search.ParseQuery(m.textInput.Value()).SearchText()
+	}
+
+	// Render visible projects only, stopping when we run out of space,
+	// so lists of any length render in constant time
+	var list strings.Builder
+	renderedItems := 0
 	for i := start; i < len(m.filtered); i++ {
 		match := m.filtered[i]
 
-		// Calculate how many lines this item will take
-		itemLines := 1 // Base line for project name
-		if match.Snippet != "" {
-			itemLines++ // Add snippet line
-		}
+		// Calculate how many lines this item will take (project name and optional snippet)
+		itemLines := matchLines(match)
 
 		// Check if we have room for this item
 		if renderedLines+itemLines > maxAvailableLines {
@@ -685,39 +1250,55 @@ func (m Model) View() string {
 		// Indicator (rendered separately to preserve its color)
 		if i == m.cursor {
 			// Selected item: orange indicator
-			b.WriteString(m.styles.Cursor.Render("▌"))
+			list.WriteString(m.styles.Cursor.Render("▌"))
 		} else {
 			// Normal item: space instead of indicator
-			b.WriteString(" ")
+			list.WriteString(" ")
 		}
 
-		// Render project name (with visual indicators and optional snippet)
-		query := strings.TrimSpace(m.textInput.Value())
-		projectContent := renderMatch(match, m.styles, query, m.showScores, isHidden)
+		// First line prefix: the Alt+1-9 number, the mark column (while projects are marked) and optional hidden project indicators
+		prefix := hotkeyLabel(i-start) + " "
+		if len(m.marked) > 0 {
+			if m.markIndex(match.Project.Path) >= 0 {
+				prefix += "✓ "
+			} else {
+				prefix += "  "
+			}
+		}
+		if m.forkRows[match.Project.Path] {
+			prefix += "↳ " // Fork listed under its upstream (Alt+F)
+		}
+		if match.Subgroup {
+			prefix += "▸ " // Subgroup of a "group/" listing (Enter lists it)
+		}
+		if match.Remote {
+			prefix += "[remote] " // Live GitLab result, not synced yet
+		} else if m.showHidden {
+			// Show visual indicators for different types of hidden projects
+			if isExcluded {
+				prefix += "[✕] " // Excluded by user (config)
+			} else if isArchived {
+				prefix += "[A] " // Archived
+			} else if isNonMember {
+				prefix += "[G] " // Non-member (guest - visible but not a member)
+			}
+		}
+
+		// Render project name (with visual indicators and optional snippet), fitted to the terminal width
+		cols := listColumns(m.width, lipgloss.Width(prefix))
+		projectContent := renderMatch(match, m.styles, query, m.showScores, isHidden, cols)
 
 		// Split content by lines to apply background to each line separately
 		lines := strings.Split(projectContent, "\n")
 		for lineIdx, line := range lines {
 			if lineIdx > 0 {
 				// For subsequent lines (snippets), add newline and spacing
-				b.WriteString("\n ")
+				list.WriteString("\n ")
 			}
 
 			// Build full line with prefix
 			var lineContent string
 			if lineIdx == 0 {
-				// First line: add space and optional hidden project indicators
-				prefix := " "
-				if m.showHidden {
-					// Show visual indicators for different types of hidden projects
-					if isExcluded {
-						prefix += "[✕] " // Excluded by user (config)
-					} else if isArchived {
-						prefix += "[A] " // Archived
-					} else if isNonMember {
-						prefix += "[G] " // Non-member (guest - visible but not a member)
-					}
-				}
 				lineContent = prefix + line
 			} else {
 				// Snippet lines: add indentation (1 space margin + 4 spaces indent)
@@ -728,17 +1309,43 @@ func (m Model) View() string {
 			if i == m.cursor {
 				// Apply background with width to fill the terminal
 				styledLine := m.styles.Selected.Width(m.width - 2).Render(lineContent) // -2 for cursor + initial space
-				b.WriteString(styledLine)
+				list.WriteString(styledLine)
 			} else if isHidden && m.showHidden {
-				b.WriteString(m.styles.Excluded.Render(lineContent))
+				list.WriteString(m.styles.Excluded.Render(lineContent))
 			} else {
-				b.WriteString(m.styles.Normal.Render(lineContent))
+				list.WriteString(m.styles.Normal.Render(lineContent))
 			}
 		}
-		b.WriteString("\n")
+		list.WriteString("\n")
 
 		// Update line counter
 		renderedLines += itemLines
+		renderedItems++
+	}
+
+	// The scrollbar shows the position in lists longer than the screen
+	if bar := scrollbar(renderedLines, len(m.filtered), start, renderedItems); bar != nil && m.width > 0 {
+		b.WriteString(m.withScrollbar(list.String(), bar))
+	} else {
+		b.WriteString(list.String())
+	}
+
+	// Regex mode: report patterns that don't compile
+	if m.regexErr != nil {
+		b.WriteString(m.styles.Help.Render("  " + m.regexErr.Error()))
+		b.WriteString("\n")
+	}
+
+	// Nothing visible but hidden projects match: say so instead of showing an empty list
+	if hint := hiddenMatchesHint(len(m.filtered), m.hiddenMatches, m.showHidden); hint != "" {
+		b.WriteString(m.styles.Help.Render("  " + hint))
+		b.WriteString("\n")
+	}
+
+	// Nothing matches at all: show the closest project paths
+	if hint := search.DidYouMean(m.suggestions); hint != "" && len(m.filtered) == 0 {
+		b.WriteString(m.styles.Help.Render("  " + hint))
+		b.WriteString("\n")
 	}
 
 	// Help text footer (only show if toggled with ?)
@@ -752,6 +1359,62 @@ func (m Model) View() string {
 		} else {
 			helpText = "↑/↓: navigate • enter: select • ctrl+x: exclude • ctrl+h: show hidden • ctrl+r: sync • ?: toggle help"
 		}
+		helpText += " • alt+1-9: select numbered result • pgup/pgdn/home/end: page • ctrl+g: jump to result"
+		helpText += " • alt+p: pin/unpin • alt+a/alt+g/alt+s: only archived/non-member/starred"
+		helpText += " • ctrl+space/alt+m: mark • alt+u: print URLs • alt+c: copy clone commands"
+		if len(m.queryHistory) > 0 {
+			helpText += " • ↑ on empty search: previous queries"
+		}
+		if m.open != nil {
+			helpText += " • ctrl+o: open and keep searching"
+		}
+		if m.star != nil {
+			helpText += " • ctrl+t: star/unstar"
+		}
+		if m.collapseForks {
+			helpText += " • alt+f: show/hide forks"
+		}
+		if m.openPage != nil {
+			for _, p := range pageKeys {
+				helpText += " • " + p.key + ": " + p.name
+			}
+		}
+		for _, o := range m.customOpeners {
+			helpText += " • " + o.Key + ": " + o.Name
+		}
+		if len(m.marked) > 0 {
+			helpText += " • enter: open marked"
+		}
+		if m.fetchIssues != nil {
+			helpText += " • tab: issues"
+		}
+		if m.fetchReadme != nil {
+			helpText += " • alt+v: README"
+		}
+		if m.editEnabled {
+			helpText += " • alt+e: open in editor"
+		}
+		if m.fileMode {
+			helpText += " • enter: open a file (--file)"
+		}
+		if m.showScores {
+			helpText += " • ctrl+e: explain score"
+		}
+		if m.regexMode {
+			helpText += " • alt+r: fuzzy search"
+		} else {
+			helpText += " • alt+r: regex"
+		}
+		if m.sortByMRs {
+			helpText += " • ctrl+s: sort by relevance"
+		} else {
+			helpText += " • ctrl+s: sort by open MRs"
+		}
+		if m.groupsMode {
+			helpText = "↑/↓: navigate • enter: open group • ctrl+r: sync • alt+o: search projects • ?: toggle help"
+		} else if m.groupIndex != nil {
+			helpText += " • alt+o: groups"
+		}
 		b.WriteString(m.styles.Help.Render(helpText))
 	}
 
@@ -763,11 +1426,66 @@ func (m Model) Selected() string {
 	return m.selected
 }
 
+// SelectedURL returns the selected issue or file URL (empty if a project was selected)
+func (m Model) SelectedURL() string {
+	return m.selectedURL
+}
+
+// SetEditEnabled enables Alt+E (open the local clone in an editor)
+func (m *Model) SetEditEnabled(enabled bool) {
+	m.editEnabled = enabled
+}
+
+// EditRequested reports whether the selected project should be opened in an editor (Alt+E)
+func (m Model) EditRequested() bool {
+	return m.editRequested && m.selected != ""
+}
+
 // CloseIndex closes the persistent Bleve index if it is open
 func (m Model) CloseIndex() {
 	if m.descIndex != nil {
 		_ = m.descIndex.Close()
 	}
+	if m.groupIndex != nil {
+		_ = m.groupIndex.Close()
+	}
+}
+
+// closeIndexesForSync closes the open indexes so the sync can open them exclusively
+// They are reopened when the sync completes (see indexReopenedMsg)
+func (m *Model) closeIndexesForSync() {
+	// A running search would use the closed index: cancel it and discard its results
+	m.cancelSearch()
+	m.filterVersion++
+	if m.descIndex != nil {
+		_ = m.descIndex.Close()
+		m.descIndex = nil
+	}
+	if m.groupIndex != nil {
+		_ = m.groupIndex.Close()
+		m.groupIndex = nil
+		m.groupIndexClosed = true
+	}
+}
+
+// snippetLead is how many runes of context a snippet keeps before a match it was shifted to
+const snippetLead = 20
+
+// snippetWindow fits a snippet into maxRunes (see truncateSnippet), starting it shortly before
+// the first matched term ("...") when that term would otherwise be cut off
+func snippetWindow(text string, terms []string, maxRunes int) string {
+	if ranges := search.MatchRanges(text, terms); len(ranges) > 0 && utf8.RuneCountInString(text[:ranges[0].End]) > maxRunes {
+		before := []rune(text[:ranges[0].Start])
+		start := max(0, len(before)-snippetLead)
+		for i := start; i < len(before); i++ {
+			if unicode.IsSpace(before[i]) {
+				start = i + 1 // Start at a word boundary
+				break
+			}
+		}
+		text = "..." + text[len(string(before[:start])):]
+	}
+	return truncateSnippet(text, maxRunes)
 }
 
 // truncateSnippet truncates text at word boundary respecting UTF-8
@@ -1,15 +1,27 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/igusev/glf/internal/annotations"
 	"github.com/igusev/glf/internal/config"
+	"github.com/igusev/glf/internal/history"
 	"github.com/igusev/glf/internal/index"
 	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/search"
 )
 
 func TestFormatNumber(t *testing.T) {
@@ -138,6 +150,28 @@ func TestTruncateSnippet(t *testing.T) {
 	}
 }
 
+func TestSnippetWindow(t *testing.T) {
+	text := "Internal tooling for the platform team, maintained by the infrastructure group: the ingress controller"
+
+	// An early match keeps the snippet's start
+	if got, want := snippetWindow(text, []string{"tooling"}, 40), truncateSnippet(text, 40); got != want {
+		t.Errorf("snippetWindow() = %q, want %q", got, want)
+	}
+	// A match past the width moves the snippet to it, at a word boundary
+	got := snippetWindow(text, []string{"INGRESS"}, 40)
+	if !strings.HasPrefix(got, "...") || !strings.Contains(got, "ingress") {
+		t.Errorf("Expected the snippet moved to the match, got %q", got)
+	}
+	if strings.HasPrefix(got, "...") && strings.HasPrefix(got[3:], " ") {
+		t.Errorf("Expected the snippet to start at a word, got %q", got)
+	}
+	// Multi-byte text is cut on rune boundaries
+	cyrillic := strings.Repeat("описание ", 10) + "платежный шлюз"
+	if got := snippetWindow(cyrillic, []string{"шлюз"}, 30); !utf8.ValidString(got) || !strings.Contains(got, "шлюз") {
+		t.Errorf("Expected valid UTF-8 containing the match, got %q", got)
+	}
+}
+
 func TestTruncateSnippet_RuneCount(t *testing.T) {
 	// Test that truncation respects rune count, not byte count
 	text := "🎉🎊🎈" // Each emoji is 4 bytes but 1 rune (3 total)
@@ -653,6 +687,41 @@ func TestUpdate_Selection(t *testing.T) {
 	}
 }
 
+// TestUpdate_AltDigit verifies that Alt+1-9 select the numbered result on screen
+func TestUpdate_AltDigit(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+	projects := make([]model.Project, 20)
+	for i := range projects {
+		projects[i] = model.Project{Path: fmt.Sprintf("test/project%02d", i), Name: "Project", Member: true}
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 16})
+	m = newModel.(Model)
+	m.viewportStart = 5 // Numbers count from the top of the screen
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}, Alt: true})
+	m = newModel.(Model)
+	if m.Selected() != "test/project06" {
+		t.Errorf("Alt+2 selected %q, want test/project06", m.Selected())
+	}
+	if !m.quitting || cmd == nil {
+		t.Error("Expected Alt+2 to select and quit like Enter")
+	}
+
+	// Numbers past the last result do nothing
+	m = New(projects[:3], "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'5'}, Alt: true})
+	m = newModel.(Model)
+	if m.Selected() != "" || m.quitting {
+		t.Errorf("Alt+5 with 3 results selected %q, want nothing", m.Selected())
+	}
+}
+
 // TestUpdate_WindowSize verifies window size handling
 func TestUpdate_WindowSize(t *testing.T) {
 	tempDir := t.TempDir()
@@ -821,10 +890,10 @@ func TestRenderFuzzyMatch(t *testing.T) {
 			expectMatch: true,
 		},
 		{
-			name:        "multi-token query uses first token",
+			name:        "multi-token query highlights every token",
 			displayStr:  "backend/api",
-			query:       "back frontend",
-			expectMatch: true, // Should match "back"
+			query:       "back api",
+			expectMatch: true, // Should match "back" and "api"
 		},
 		{
 			name:        "case insensitive match",
@@ -1071,6 +1140,49 @@ func TestUpdate_SyncCompleteMsg_Error(t *testing.T) {
 	}
 }
 
+// TestSyncToast verifies the transient toast shown when a sync finishes
+func TestSyncToast(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+	m := New(nil, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.width, m.height = 120, 30
+	m.syncing = true
+
+	projects := []model.Project{{Path: "test/project1", Name: "Project 1"}, {Path: "test/project2", Name: "Project 2"}}
+	newModel, _ := m.Update(SyncCompleteMsg{Projects: projects, Added: 12, Updated: 3})
+	m = newModel.(Model)
+	view := m.View()
+	if !strings.Contains(view, "Sync complete: +12 new, 3 updated") {
+		t.Errorf("Expected sync toast in view, got:\n%s", view)
+	}
+	if !strings.Contains(view, "/2 projects") {
+		t.Errorf("Expected the project count to be updated, got:\n%s", view)
+	}
+
+	// A second sync replaces the toast; the first one's expiry must not hide it
+	firstVersion := m.toastVersion
+	newModel, _ = m.Update(SyncCompleteMsg{Err: errors.New("network timeout")})
+	m = newModel.(Model)
+	newModel, _ = m.Update(toastExpiredMsg{version: firstVersion})
+	m = newModel.(Model)
+	if !strings.Contains(m.View(), "Sync failed: network timeout") {
+		t.Error("Expected the failure toast to survive the previous toast's expiry")
+	}
+
+	newModel, _ = m.Update(toastExpiredMsg{version: m.toastVersion})
+	m = newModel.(Model)
+	if strings.Contains(m.View(), "Sync failed") {
+		t.Error("Expected the toast to disappear after it expired")
+	}
+
+	if got := syncToast(SyncCompleteMsg{}); got != "Sync complete: no changes" {
+		t.Errorf("Unexpected toast for a sync without changes: %q", got)
+	}
+}
+
 // TestUpdate_HistoryLoadedMsg verifies history loaded message handling
 func TestUpdate_HistoryLoadedMsg(t *testing.T) {
 	tempDir := t.TempDir()
@@ -1295,6 +1407,53 @@ func TestUpdate_AutoSyncMsg(t *testing.T) {
 	}
 }
 
+// TestUpdate_SyncTick verifies interval syncs (sync.auto: interval)
+func TestUpdate_SyncTick(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+	projects := []model.Project{{Path: "test/project", Name: "Test"}}
+
+	syncs := 0
+	syncCallback := func() tea.Cmd {
+		syncs++
+		return func() tea.Msg {
+			return SyncCompleteMsg{Projects: projects}
+		}
+	}
+
+	m := New(projects, "", syncCallback, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.SetAutoSync(false, time.Minute)
+
+	// A finished sync schedules the next tick; older ticks are ignored
+	newModel, _ := m.Update(SyncCompleteMsg{Projects: projects})
+	m = newModel.(Model)
+	if m.syncTick != 1 {
+		t.Fatalf("Expected a scheduled tick, got version %d", m.syncTick)
+	}
+	newModel, _ = m.Update(syncTickMsg{version: 0})
+	m = newModel.(Model)
+	if m.syncing || syncs != 0 {
+		t.Error("Expected a stale tick to be ignored")
+	}
+
+	newModel, cmd := m.Update(syncTickMsg{version: 1})
+	m = newModel.(Model)
+	if !m.syncing || syncs != 1 || cmd == nil {
+		t.Errorf("Expected the tick to start a sync, got syncing %v and %d syncs", m.syncing, syncs)
+	}
+
+	// Disabled: no tick is scheduled
+	m.SetAutoSync(false, 0)
+	newModel, _ = m.Update(SyncCompleteMsg{Projects: projects})
+	m = newModel.(Model)
+	if m.syncTick != 1 {
+		t.Errorf("Expected no tick without an interval, got version %d", m.syncTick)
+	}
+}
+
 // TestRenderMatch verifies renderMatch function
 func TestRenderMatch(t *testing.T) {
 	cs := NewColorScheme()
@@ -1357,7 +1516,7 @@ func TestRenderMatch(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := renderMatch(tt.match, styles, tt.query, tt.showScores, false)
+			result := renderMatch(tt.match, styles, tt.query, tt.showScores, false, matchColumns{})
 
 			// Result should not be empty
 			if result == "" {
@@ -1378,3 +1537,1678 @@ func TestRenderMatch(t *testing.T) {
 		})
 	}
 }
+
+// TestUpdate_CtrlS_SortByMRs verifies that Ctrl+S orders results by open merge requests
+func TestUpdate_CtrlS_SortByMRs(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+
+	projects := []model.Project{
+		{Path: "team/quiet", Name: "quiet", Member: true},
+		{Path: "team/busy", Name: "busy", Member: true, OpenMRs: 9},
+		{Path: "team/some", Name: "some", Member: true, OpenMRs: 2},
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newModel.(Model)
+
+	if !m.sortByMRs {
+		t.Fatal("Expected sortByMRs to be true after Ctrl+S")
+	}
+	want := []string{"team/busy", "team/some", "team/quiet"}
+	for i, path := range want {
+		if m.filtered[i].Project.Path != path {
+			t.Errorf("filtered[%d] = %s, want %s", i, m.filtered[i].Project.Path, path)
+		}
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = newModel.(Model)
+	if m.sortByMRs {
+		t.Error("Expected sortByMRs to be false after second Ctrl+S")
+	}
+}
+
+func TestRenderCounters(t *testing.T) {
+	styles := NewColorScheme().GetStyles()
+
+	if got := renderCounters(model.Project{}, styles); got != "" {
+		t.Errorf("Expected no counters without insights, got %q", got)
+	}
+
+	got := renderCounters(model.Project{OpenMRs: 3, OpenIssues: 14}, styles)
+	if !strings.Contains(got, "!3") || !strings.Contains(got, "#14") {
+		t.Errorf("Expected !3 and #14 in %q", got)
+	}
+
+	got = renderCounters(model.Project{OpenIssues: 1}, styles)
+	if strings.Contains(got, "!") || !strings.Contains(got, "#1") {
+		t.Errorf("Expected only issue counter in %q", got)
+	}
+}
+
+func TestRenderLabels(t *testing.T) {
+	search.SetAnnotations(annotations.Annotations{"legacy": {"deprecated"}, "legacy/billing": {"tier-1"}})
+	t.Cleanup(func() { search.SetAnnotations(nil) })
+	styles := NewColorScheme().GetStyles()
+
+	if got := renderLabels(model.Project{Path: "platform/api"}, styles); got != "" {
+		t.Errorf("Expected no labels, got %q", got)
+	}
+	if got := renderLabels(model.Project{Path: "legacy/billing"}, styles); !strings.Contains(got, "[deprecated] [tier-1]") {
+		t.Errorf("Expected [deprecated] [tier-1] in %q", got)
+	}
+}
+
+func TestRenderMatch_Fork(t *testing.T) {
+	styles := NewColorScheme().GetStyles()
+	match := index.CombinedMatch{Project: model.Project{Path: "alice/api", Name: "api"}}
+	if strings.Contains(renderMatch(match, styles, "", false, false, matchColumns{}), "⑂") {
+		t.Error("Expected no fork mark for an upstream project")
+	}
+
+	match.Project.ForkedFrom = "platform/api"
+	match.ForkPenalty = 2
+	got := renderMatch(match, styles, "", true, false, matchColumns{})
+	if !strings.Contains(got, "⑂") || !strings.Contains(got, "F:-2") {
+		t.Errorf("Expected the fork mark and penalty, got %q", got)
+	}
+}
+
+func TestProjectMetadata_Fork(t *testing.T) {
+	got := projectMetadata(model.Project{Visibility: "public", ForkedFrom: "platform/api"})
+	if got != "public • fork of platform/api" {
+		t.Errorf("Unexpected metadata %q", got)
+	}
+}
+
+func TestRenderActivity(t *testing.T) {
+	styles := NewColorScheme().GetStyles()
+	now := time.Now()
+
+	if got := renderActivity(model.Project{}, styles, now); got != "" {
+		t.Errorf("Expected no indicator without a last activity, got %q", got)
+	}
+	tests := []struct {
+		lastActivity time.Time
+		indicator    string
+	}{
+		{now.Add(-time.Hour), "●"},
+		{now.AddDate(0, -3, 0), "◐"},
+		{now.AddDate(-2, 0, 0), "○"},
+	}
+	for _, tt := range tests {
+		got := renderActivity(model.Project{LastActivityAt: tt.lastActivity}, styles, now)
+		if !strings.Contains(got, tt.indicator) {
+			t.Errorf("Expected %s for activity at %v, got %q", tt.indicator, tt.lastActivity, got)
+		}
+	}
+}
+
+// TestRemoteFallback verifies that queries without local results are searched remotely
+func TestRemoteFallback(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+
+	projects := []model.Project{
+		{Path: "team/api", Name: "api", Member: true},
+	}
+
+	calls := 0
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.SetRemoteSearch(func(query string) ([]model.Project, error) {
+		calls++
+		return []model.Project{{Path: "team/" + query, Name: query, Member: true}}, nil
+	})
+	m.historyLoading = false
+	m.textInput.SetValue("zzzbrandnew")
+
+	m, cmd := searchNow(t, m)
+	if cmd == nil {
+		t.Fatal("Expected remote search command for query without local results")
+	}
+	if !m.remoteSearching {
+		t.Error("Expected remoteSearching to be true")
+	}
+
+	newModel, _ := m.Update(cmd())
+	m = newModel.(Model)
+
+	if len(m.filtered) != 1 || !m.filtered[0].Remote || m.filtered[0].Project.Path != "team/zzzbrandnew" {
+		t.Fatalf("Expected one remote result, got %+v", m.filtered)
+	}
+	if !strings.Contains(m.View(), "[remote]") {
+		t.Error("Expected remote results to be marked in the view")
+	}
+
+	// Same query again does not hit the API twice
+	m, cmd = searchNow(t, m)
+	if cmd != nil {
+		cmd()
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 remote call, got %d", calls)
+	}
+	if len(m.filtered) != 1 {
+		t.Errorf("Expected cached remote result to be kept, got %d", len(m.filtered))
+	}
+}
+
+// TestUpdate_AltP_TogglePin verifies pinning from the TUI moves the project to the top
+func TestUpdate_AltP_TogglePin(t *testing.T) {
+	tempDir := t.TempDir()
+	originalHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", originalHome)
+	os.Setenv("HOME", tempDir)
+
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+
+	projects := []model.Project{
+		{Path: "test/first", Name: "first", Member: true},
+		{Path: "test/second", Name: "second", Member: true},
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.cursor = 1
+
+	pinKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}, Alt: true}
+	newModel, _ := m.Update(pinKey)
+	m = newModel.(Model)
+
+	if !cfg.IsPinned("test/second") {
+		t.Fatal("Expected test/second to be pinned")
+	}
+	if m.filtered[0].Project.Path != "test/second" || !m.filtered[0].Pinned {
+		t.Errorf("Expected pinned project first, got %s", m.filtered[0].Project.Path)
+	}
+	if m.textInput.Value() != "" {
+		t.Errorf("Alt+P should not type into the search input, got %q", m.textInput.Value())
+	}
+
+	m.cursor = 0
+	newModel, _ = m.Update(pinKey)
+	m = newModel.(Model)
+	if cfg.IsPinned("test/second") {
+		t.Error("Expected test/second to be unpinned after second Alt+P")
+	}
+}
+
+// TestHiddenMatchesHint verifies the hint shown when only hidden projects match
+func TestHiddenMatchesHint(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+
+	projects := []model.Project{
+		{Path: "legacy/billing", Name: "billing", Archived: true, Member: true},
+		{Path: "other/billing-ui", Name: "billing-ui", Member: false},
+		{Path: "team/api", Name: "api", Member: true},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, p := range projects {
+		docs = append(docs, index.NewDocument(p))
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to index projects: %v", err)
+	}
+
+	remoteCalls := 0
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", descIndex)
+	m.SetRemoteSearch(func(query string) ([]model.Project, error) {
+		remoteCalls++
+		return nil, nil
+	})
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+	m.textInput.SetValue("billing")
+
+	m, cmd := searchNow(t, m)
+	if cmd != nil {
+		cmd()
+	}
+
+	if len(m.filtered) != 0 {
+		t.Fatalf("Expected no visible matches, got %d", len(m.filtered))
+	}
+	if m.hiddenMatches != 2 {
+		t.Errorf("Expected 2 hidden matches, got %d", m.hiddenMatches)
+	}
+	if !strings.Contains(m.View(), "2 hidden projects match — Ctrl+H to show") {
+		t.Error("Expected hidden matches hint in the view")
+	}
+	if remoteCalls != 0 {
+		t.Error("Expected no remote search when hidden local projects match")
+	}
+
+	// Showing hidden projects replaces the hint with the projects themselves
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlH})
+	m = newModel.(Model)
+	if len(m.filtered) != 2 {
+		t.Errorf("Expected 2 matches with hidden projects shown, got %d", len(m.filtered))
+	}
+	if strings.Contains(m.View(), "Ctrl+H to show") {
+		t.Error("Expected no hint while hidden projects are shown")
+	}
+}
+
+// TestAsyncSearch verifies that searches run in a command and stale results are discarded
+func TestAsyncSearch(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{
+		{Path: "team/api", Name: "api", Member: true},
+		{Path: "team/web", Name: "web", Member: true},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, p := range projects {
+		docs = append(docs, index.NewDocument(p))
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to index projects: %v", err)
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", descIndex)
+	m.historyLoading = false
+	m.filter()
+
+	// The debounce tick starts the search without blocking the update loop
+	m.textInput.SetValue("api")
+	newModel, searchAPI := m.Update(debounceTickMsg{version: m.filterVersion})
+	m = newModel.(Model)
+	if searchAPI == nil || !m.searching || len(m.filtered) != 2 {
+		t.Fatalf("Expected a running search and unchanged results, got searching=%v results=%d", m.searching, len(m.filtered))
+	}
+
+	// Typing on cancels it: its results are never shown
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = newModel.(Model)
+	stale := searchAPI()
+	if msg, ok := stale.(searchResultsMsg); !ok || !errors.Is(msg.err, context.Canceled) {
+		t.Errorf("Expected the canceled search to stop with context.Canceled, got %+v", stale)
+	}
+	newModel, _ = m.Update(stale)
+	m = newModel.(Model)
+	if len(m.filtered) != 2 {
+		t.Errorf("Expected stale results to be discarded, got %d results", len(m.filtered))
+	}
+
+	// Results of an older query are discarded even if the search completed
+	m.textInput.SetValue("web")
+	newModel, _ = m.Update(searchResultsMsg{version: m.filterVersion - 1, query: "api", matches: []index.CombinedMatch{{Project: projects[0]}}})
+	m = newModel.(Model)
+	if len(m.filtered) != 2 {
+		t.Errorf("Expected results of an older query to be discarded, got %d results", len(m.filtered))
+	}
+
+	m, _ = searchNow(t, m)
+	if m.searching || len(m.filtered) != 1 || m.filtered[0].Project.Path != "team/web" {
+		t.Errorf("Expected team/web after the search completed, got %+v", m.filtered)
+	}
+}
+
+func TestHiddenMatchesHintText(t *testing.T) {
+	tests := []struct {
+		visible, hidden int
+		showHidden      bool
+		want            string
+	}{
+		{0, 0, false, ""},
+		{1, 3, false, ""},
+		{0, 3, true, ""},
+		{0, 1, false, "1 hidden project matches — Ctrl+H to show"},
+		{0, 3, false, "3 hidden projects match — Ctrl+H to show"},
+	}
+	for _, tt := range tests {
+		if got := hiddenMatchesHint(tt.visible, tt.hidden, tt.showHidden); got != tt.want {
+			t.Errorf("hiddenMatchesHint(%d, %d, %v) = %q, want %q", tt.visible, tt.hidden, tt.showHidden, got, tt.want)
+		}
+	}
+}
+
+// TestUpdate_AltR_RegexMode verifies the regex toggle matches project paths with a regular expression
+func TestUpdate_AltR_RegexMode(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+	projects := []model.Project{
+		{Path: "platform/cert-operator", Name: "cert-operator", Member: true},
+		{Path: "platform/operator-docs", Name: "operator-docs", Member: true},
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.textInput.SetValue("-operator$")
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}, Alt: true})
+	m = newModel.(Model)
+
+	if !m.regexMode {
+		t.Fatal("Expected regex mode after Alt+R")
+	}
+	if m.textInput.Prompt != "re> " {
+		t.Errorf("Expected regex prompt, got %q", m.textInput.Prompt)
+	}
+	if len(m.filtered) != 1 || m.filtered[0].Project.Path != "platform/cert-operator" {
+		t.Errorf("Expected only platform/cert-operator, got %+v", m.filtered)
+	}
+
+	// Invalid patterns are reported instead of crashing
+	m.textInput.SetValue("operator(")
+	m.filter()
+	if m.regexErr == nil || !strings.Contains(m.View(), "invalid regex") {
+		t.Error("Expected invalid regex error in the view")
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}, Alt: true})
+	m = newModel.(Model)
+	if m.regexMode || m.textInput.Prompt != "> " {
+		t.Error("Expected Alt+R to switch back to fuzzy search")
+	}
+}
+
+func TestIssuesMode(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+	projects := []model.Project{{Path: "group/api", Name: "api", Member: true}}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.textInput.SetValue("ap")
+	m.width, m.height = 120, 30
+
+	var fetched string
+	m.SetIssuesFetcher(func(projectPath string) ([]model.Issue, error) {
+		fetched = projectPath
+		return []model.Issue{
+			{IID: 7, Title: "Fix login redirect", WebURL: "https://gitlab.example.com/group/api/-/issues/7"},
+			{IID: 3, Title: "Add rate limiting", Labels: []string{"backend"}, WebURL: "https://gitlab.example.com/group/api/-/issues/3"},
+		}, nil
+	})
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newModel.(Model)
+	if !m.inIssuesMode() || cmd == nil {
+		t.Fatal("Expected Tab to enter issues mode and fetch issues")
+	}
+	if m.textInput.Value() != "" {
+		t.Errorf("Expected empty issue query, got %q", m.textInput.Value())
+	}
+
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if fetched != "group/api" || len(m.filteredIssues) != 2 {
+		t.Fatalf("Expected 2 issues of group/api, got %d (fetched %q)", len(m.filteredIssues), fetched)
+	}
+	if !strings.Contains(m.View(), "#7 Fix login redirect") {
+		t.Error("Expected issues in the view")
+	}
+
+	// Fuzzy filter: "rlim" is a subsequence of "rate limiting"
+	for _, r := range "rlim" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(Model)
+	}
+	if len(m.filteredIssues) != 1 || m.filteredIssues[0].IID != 3 {
+		t.Fatalf("Expected only #3, got %+v", m.filteredIssues)
+	}
+
+	// Esc returns to the project list with the previous query
+	back, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	backModel := back.(Model)
+	if backModel.inIssuesMode() || backModel.quitting || backModel.textInput.Value() != "ap" {
+		t.Errorf("Expected Esc to return to projects with query %q, got %q", "ap", backModel.textInput.Value())
+	}
+
+	// Enter opens the issue
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.SelectedURL() != "https://gitlab.example.com/group/api/-/issues/3" || m.Selected() != "group/api" {
+		t.Errorf("Expected issue #3 to be selected, got %q (%q)", m.SelectedURL(), m.Selected())
+	}
+}
+
+func TestIssuesMode_Disabled(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{{Path: "group/api", Name: "api", Member: true}}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if newModel.(Model).inIssuesMode() {
+		t.Error("Expected Tab to do nothing without an issues fetcher")
+	}
+}
+
+func TestReadmeMode(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{{Path: "group/api", Name: "api", Member: true, DefaultBranch: "main", Visibility: "internal"}}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+
+	fetches := 0
+	m.SetReadmeFetcher(func(projectPath string) (string, error) {
+		fetches++
+		return "# API service\n\nHandles requests for " + projectPath, nil
+	})
+
+	alt := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}, Alt: true}
+	newModel, cmd := m.Update(alt)
+	m = newModel.(Model)
+	if !m.inReadmeMode() || cmd == nil {
+		t.Fatal("Expected Alt+V to open the README preview and fetch the README")
+	}
+	if !strings.Contains(m.View(), "Loading README") {
+		t.Error("Expected loading state in the view")
+	}
+
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	view := m.View()
+	if !strings.Contains(view, "README of group/api • main • internal") || !strings.Contains(view, "Handles requests for") {
+		t.Errorf("Expected rendered README in the view, got:\n%s", view)
+	}
+
+	// Esc returns to the project list
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.inReadmeMode() || m.quitting {
+		t.Fatal("Expected Esc to return to the project list")
+	}
+
+	// Reopening uses the cached README
+	newModel, cmd = m.Update(alt)
+	m = newModel.(Model)
+	if !m.inReadmeMode() || cmd != nil || fetches != 1 {
+		t.Errorf("Expected cached README on reopen, got %d fetches", fetches)
+	}
+}
+
+func TestReadmeMode_Disabled(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{{Path: "group/api", Name: "api", Member: true}}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}, Alt: true})
+	if newModel.(Model).inReadmeMode() {
+		t.Error("Expected Alt+V to do nothing without a README fetcher")
+	}
+}
+
+func TestReadmeMode_Avatars(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{{Path: "group/api-gateway", Name: "api-gateway", Description: "Edge routing", Member: true}}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+	m.SetReadmeFetcher(func(string) (string, error) { return "# Gateway", nil })
+	avatar := testAvatarPNG(t, 16)
+	m.SetAvatars(func(string) ([]byte, error) { return avatar, nil }, ImageKitty)
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}, Alt: true})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected README and avatar fetches")
+	}
+
+	// Initials are shown until the image arrives
+	view := m.View()
+	if !strings.Contains(view, "AG") || !strings.Contains(view, "Edge routing") {
+		t.Errorf("Expected initials and description in the header, got:\n%s", view)
+	}
+
+	for _, msg := range cmd().(tea.BatchMsg) {
+		if msg != nil {
+			newModel, _ = m.Update(msg())
+			m = newModel.(Model)
+		}
+	}
+	if !strings.Contains(m.View(), "\x1b_Ga=T") {
+		t.Error("Expected the kitty image in the header")
+	}
+
+	// Leaving the preview clears the screen so the image doesn't linger
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.inReadmeMode() || cmd == nil {
+		t.Fatal("Expected Esc to return to the project list and clear the screen")
+	}
+	if cmd() != tea.ClearScreen() {
+		t.Error("Expected a clear screen command")
+	}
+}
+
+func TestMergeRequestsModel(t *testing.T) {
+	mrs := []model.MergeRequest{
+		{IID: 12, ProjectPath: "group/api", Title: "Add retries", WebURL: "https://gitlab.example.com/group/api/-/merge_requests/12"},
+		{IID: 3, ProjectPath: "group/docs", Title: "Login flow", Draft: true, WebURL: "https://gitlab.example.com/group/docs/-/merge_requests/3"},
+	}
+
+	m := NewMergeRequests(mrs, "docs", "v1.0.0")
+	m.width, m.height = 120, 30
+	if len(m.filtered) != 1 || m.filtered[0].IID != 3 {
+		t.Fatalf("Expected initial query to match group/docs!3, got %+v", m.filtered)
+	}
+	if !strings.Contains(m.View(), "[draft]") {
+		t.Error("Expected draft marker in the view")
+	}
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(MergeRequestsModel)
+	if m.SelectedURL() != mrs[1].WebURL {
+		t.Errorf("Expected %q to be selected, got %q", mrs[1].WebURL, m.SelectedURL())
+	}
+}
+
+func TestUsersModel(t *testing.T) {
+	users := []model.User{
+		{Username: "jdoe", Name: "Jane Doe"},
+		{Username: "bsmith", Name: "Bob Smith"},
+	}
+
+	m := NewUsers(users, "@bob", "v1.0.0")
+	m.width, m.height = 120, 30
+	if len(m.filtered) != 1 || m.filtered[0].Username != "bsmith" {
+		t.Fatalf("Expected initial query to match @bsmith, got %+v", m.filtered)
+	}
+	if !strings.Contains(m.View(), "1/2 users") {
+		t.Error("Expected user count in the view")
+	}
+
+	// Alt+M opens the merge requests assigned to the user instead of the profile
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}, Alt: true})
+	m = newModel.(UsersModel)
+	if m.Selected() != "bsmith" || m.SelectedTarget() != "mrs" {
+		t.Errorf("Expected assigned MRs of bsmith, got %q (target %q)", m.Selected(), m.SelectedTarget())
+	}
+}
+
+func TestDedicatedFilters(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+
+	projects := []model.Project{
+		{Path: "legacy/billing", Name: "billing", Archived: true, Member: true, Starred: true},
+		{Path: "other/billing-ui", Name: "billing-ui", Member: false},
+		{Path: "team/api", Name: "api", Member: true, Starred: true},
+		{Path: "team/web", Name: "web", Member: true},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, p := range projects {
+		docs = append(docs, index.NewDocument(p))
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to index projects: %v", err)
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", descIndex)
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+	m.filter()
+
+	paths := func() []string {
+		var out []string
+		for _, match := range m.filtered {
+			out = append(out, match.Project.Path)
+		}
+		sort.Strings(out)
+		return out
+	}
+	press := func(r rune) {
+		newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: true})
+		m = newModel.(Model)
+	}
+
+	// Starred only: hidden projects stay hidden
+	press('s')
+	if got := paths(); !reflect.DeepEqual(got, []string{"team/api"}) {
+		t.Errorf("Starred only: got %v", got)
+	}
+	if !strings.Contains(m.View(), "[starred only]") {
+		t.Error("Expected active filter in the header")
+	}
+
+	// Archived + starred: archived projects are shown although hidden
+	press('a')
+	if got := paths(); !reflect.DeepEqual(got, []string{"legacy/billing"}) {
+		t.Errorf("Archived+starred only: got %v", got)
+	}
+	if !strings.Contains(m.View(), "[archived+starred only]") {
+		t.Error("Expected both active filters in the header")
+	}
+
+	// Non-member only
+	press('a')
+	press('s')
+	press('g')
+	if got := paths(); !reflect.DeepEqual(got, []string{"other/billing-ui"}) {
+		t.Errorf("Non-member only: got %v", got)
+	}
+
+	// All filters off: back to visible projects
+	press('g')
+	if got := paths(); !reflect.DeepEqual(got, []string{"team/api", "team/web"}) {
+		t.Errorf("No filters: got %v", got)
+	}
+	if strings.Contains(m.View(), "only]") {
+		t.Error("Expected no filter indicator without active filters")
+	}
+}
+
+func TestGroupsMode(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{{Path: "company/platform/api", Name: "api", Member: true}}
+
+	groupIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "groups.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create group index: %v", err)
+	}
+	if err := groupIndex.AddBatch([]index.DescriptionDocument{
+		index.NewGroupDocument(model.Group{ID: 1, Path: "company/platform", Name: "Platform"}),
+		index.NewGroupDocument(model.Group{ID: 2, Path: "company/billing", Name: "Billing"}),
+	}); err != nil {
+		t.Fatalf("Failed to index groups: %v", err)
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+	m.SetGroupIndex(groupIndex)
+	defer m.CloseIndex()
+
+	altO := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}, Alt: true}
+	newModel, _ := m.Update(altO)
+	m = newModel.(Model)
+	if !m.groupsMode {
+		t.Fatal("Expected Alt+O to switch to group search")
+	}
+	if len(m.filtered) != 2 {
+		t.Fatalf("Expected 2 groups, got %d", len(m.filtered))
+	}
+	if view := m.View(); !strings.Contains(view, "2 groups") || !strings.Contains(view, "Search groups") {
+		t.Errorf("Expected group count and placeholder in the view, got:\n%s", view)
+	}
+
+	// Project-only keys do nothing in groups mode
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = newModel.(Model)
+	if cmd != nil || m.inIssuesMode() {
+		t.Error("Expected Tab to be ignored in groups mode")
+	}
+
+	m.textInput.SetValue("billing")
+	m.filter()
+	if len(m.filtered) != 1 || m.filtered[0].Project.Path != "company/billing" {
+		t.Fatalf("Expected company/billing, got %+v", m.filtered)
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.Selected() != "company/billing" || !m.SelectedIsGroup() {
+		t.Errorf("Expected group company/billing to be selected, got %q (group=%v)", m.Selected(), m.SelectedIsGroup())
+	}
+}
+
+func TestGroupsMode_Disabled(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{{Path: "group/api", Name: "api", Member: true}}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}, Alt: true})
+	m = newModel.(Model)
+	if m.groupsMode {
+		t.Error("Expected Alt+O to do nothing without a group index")
+	}
+}
+
+func TestEditKey(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{{Path: "group/api", Name: "api", Member: true}}
+	altE := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}, Alt: true}
+
+	// Without workspace_dir Alt+E does nothing
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	newModel, cmd := m.Update(altE)
+	m = newModel.(Model)
+	if cmd != nil || m.Selected() != "" || m.EditRequested() {
+		t.Error("Expected Alt+E to be ignored when editing is disabled")
+	}
+
+	m.SetEditEnabled(true)
+	newModel, cmd = m.Update(altE)
+	m = newModel.(Model)
+	if cmd == nil || m.Selected() != "group/api" || !m.EditRequested() {
+		t.Errorf("Expected Alt+E to select group/api for editing, got %q (edit=%v)", m.Selected(), m.EditRequested())
+	}
+}
+
+func TestExplainScore(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{
+		{Path: "backend/api", Name: "api", Description: "REST API", Starred: true, Member: true},
+		{Path: "frontend/web", Name: "web", Member: true},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, p := range projects {
+		docs = append(docs, index.NewDocument(p))
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to index projects: %v", err)
+	}
+	ctrlE := tea.KeyMsg{Type: tea.KeyCtrlE}
+
+	// Without --scores Ctrl+E stays a text input key
+	m := New(projects, "api", nil, tempDir, cfg, false, false, "user", "v1.0.0", descIndex)
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+	newModel, _ := m.Update(ctrlE)
+	if newModel.(Model).inExplainMode() {
+		t.Fatal("Expected Ctrl+E to be ignored without --scores")
+	}
+
+	m = New(projects, "api", nil, tempDir, cfg, true, false, "user", "v1.0.0", descIndex)
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+	m, _ = searchNow(t, m)
+	if len(m.filtered) == 0 || m.filtered[0].Project.Path != "backend/api" {
+		t.Fatalf("Expected backend/api first, got %+v", m.filtered)
+	}
+
+	newModel, _ = m.Update(ctrlE)
+	m = newModel.(Model)
+	if !m.inExplainMode() {
+		t.Fatal("Expected Ctrl+E to open the score breakdown")
+	}
+	if m.explain.err != nil || len(m.explain.fields) == 0 {
+		t.Fatalf("Expected field scores, got %+v (err %v)", m.explain.fields, m.explain.err)
+	}
+	view := m.View()
+	for _, want := range []string{"Score of backend/api", "ProjectName", "api (exact", "Relevance multiplier", "Starred bonus", "Total"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the breakdown:\n%s", want, view)
+		}
+	}
+
+	// Typing is not forwarded to the query while the breakdown is shown
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	m = newModel.(Model)
+	if m.textInput.Value() != "api" {
+		t.Errorf("Expected the query to stay 'api', got %q", m.textInput.Value())
+	}
+
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = newModel.(Model)
+	if m.inExplainMode() || m.quitting {
+		t.Error("Expected Esc to close the breakdown without quitting")
+	}
+}
+
+func TestTermMatchKind(t *testing.T) {
+	tests := []struct {
+		term, query, want string
+	}{
+		{"api", "api", "exact"},
+		{"gateway", "api gate", "prefix"},
+		{"handler", "handlr", "fuzzy"},
+		{"api", "API gateway", "exact"},
+	}
+	for _, tt := range tests {
+		if got := termMatchKind(tt.term, tt.query); got != tt.want {
+			t.Errorf("termMatchKind(%q, %q) = %q, want %q", tt.term, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestFileMode(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"}, Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{{Path: "group/api", Name: "api", Member: true, DefaultBranch: "develop"}}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.SetFileMode(true, "")
+
+	// Enter asks for the file instead of selecting the project
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if !m.inFileMode() || m.Selected() != "" {
+		t.Fatal("Expected Enter to ask for a file path")
+	}
+	if view := m.View(); !strings.Contains(view, "Open a file of group/api (develop)") {
+		t.Errorf("Expected the file prompt in the view, got:\n%s", view)
+	}
+
+	// An empty path is rejected
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if !m.inFileMode() || m.fileErr == nil {
+		t.Fatal("Expected an error for an empty path")
+	}
+
+	for _, r := range "docs/setup.md:3" {
+		newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = newModel.(Model)
+	}
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || m.Selected() != "group/api" {
+		t.Fatalf("Expected group/api to be selected, got %q", m.Selected())
+	}
+	if want := "https://gitlab.example.com/group/api/-/blob/develop/docs/setup.md#L3"; m.SelectedURL() != want {
+		t.Errorf("SelectedURL() = %q, want %q", m.SelectedURL(), want)
+	}
+
+	// A path from the command line opens directly
+	m = New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.SetFileMode(true, "Makefile")
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.inFileMode() || m.SelectedURL() != "https://gitlab.example.com/group/api/-/blob/develop/Makefile" {
+		t.Errorf("Expected Makefile to be selected directly, got %q", m.SelectedURL())
+	}
+
+	// Esc in the prompt returns to the project list
+	m = New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.SetFileMode(true, "")
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	newModel, _ = newModel.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = newModel.(Model); m.inFileMode() || m.quitting {
+		t.Error("Expected Esc to return to the project list")
+	}
+}
+
+// TestMultiSelect verifies marking projects and the bulk actions on them
+func TestMultiSelect(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"}, Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{
+		{Path: "group/api", Name: "api", Member: true},
+		{Path: "group/web", Name: "web", Member: true},
+		{Path: "group/docs", Name: "docs", Member: true},
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+	if len(m.filtered) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(m.filtered))
+	}
+	first, second := m.filtered[0].Project.Path, m.filtered[1].Project.Path
+
+	// Ctrl+Space marks and moves down; Alt+M marks the next one too
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlAt})
+	newModel, _ = newModel.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}, Alt: true})
+	m = newModel.(Model)
+	if m.cursor != 2 || len(m.marked) != 2 {
+		t.Fatalf("Expected 2 marked projects and the cursor on the third, got %d marked, cursor %d", len(m.marked), m.cursor)
+	}
+	if view := m.View(); !strings.Contains(view, "2 marked") || !strings.Contains(view, "✓") {
+		t.Errorf("Expected marks in the view, got:\n%s", view)
+	}
+
+	// Marking again unmarks
+	m.cursor = 1
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlAt})
+	m = newModel.(Model)
+	if len(m.marked) != 1 || m.marked[0].Path != first {
+		t.Fatalf("Expected only %s to stay marked, got %+v", first, m.marked)
+	}
+	m.cursor = 1
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlAt})
+	m = newModel.(Model)
+
+	// Enter opens every marked project, in marking order
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if cmd == nil || m.BulkAction() != BulkOpen {
+		t.Fatalf("Expected Enter to quit with BulkOpen, got %v", m.BulkAction())
+	}
+	if marked := m.Marked(); len(marked) != 2 || marked[0].Path != first || marked[1].Path != second {
+		t.Errorf("Unexpected marked projects %+v", marked)
+	}
+
+	// Without marks, Alt+U and Alt+C act on the selected project
+	m = New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	newModel, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}, Alt: true})
+	m = newModel.(Model)
+	if cmd == nil || m.BulkAction() != BulkCopyClone || len(m.Marked()) != 1 || m.Marked()[0].Path != first {
+		t.Errorf("Expected Alt+C to copy the selected project, got %v %+v", m.BulkAction(), m.Marked())
+	}
+}
+
+// TestSessionRestore verifies that a saved session restores the query, toggles and selected result
+func TestSessionRestore(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"}, Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{
+		{Path: "group/api", Name: "api", Member: true, Starred: true},
+		{Path: "group/api-gateway", Name: "api-gateway", Member: true, Starred: true},
+		{Path: "group/api-docs", Name: "api-docs", Member: true},
+	}
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, p := range projects {
+		docs = append(docs, index.NewDocument(p))
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to index projects: %v", err)
+	}
+
+	m := New(projects, "api", nil, tempDir, cfg, false, false, "user", "v1.0.0", descIndex)
+	m.historyLoading = false
+	m.toggleFilter(filterStarred)
+	if len(m.filtered) != 2 {
+		t.Fatalf("Expected 2 starred results, got %d", len(m.filtered))
+	}
+	m.cursor = 1
+	session := m.Session()
+	if session.Query != "api" || !session.OnlyStarred || session.Selected != m.filtered[1].Project.Path {
+		t.Fatalf("Unexpected session %+v", session)
+	}
+
+	restored := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", descIndex)
+	restored.historyLoading = false
+	restored.RestoreSession(session)
+	if restored.textInput.Value() != "api" || !restored.onlyStarred {
+		t.Fatalf("Expected query and starred filter restored, got %q %v", restored.textInput.Value(), restored.onlyStarred)
+	}
+	if restored.cursor != 1 || restored.filtered[restored.cursor].Project.Path != session.Selected {
+		t.Errorf("Expected the cursor on %s, got %d", session.Selected, restored.cursor)
+	}
+
+	// Re-ranking keeps the restored selection until a key is pressed
+	restored.cursor = 0
+	restored.filter()
+	if restored.filtered[restored.cursor].Project.Path != session.Selected {
+		t.Error("Expected the restored selection to survive a re-run of the search")
+	}
+	newModel, _ := restored.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if restored = newModel.(Model); restored.restorePath != "" {
+		t.Error("Expected a key press to end the restore")
+	}
+}
+
+// searchNow delivers the debounce tick for the current query and then the async search results
+// Returns the model and the follow-up command (e.g., the remote search)
+func searchNow(t *testing.T, m Model) (Model, tea.Cmd) {
+	t.Helper()
+	newModel, cmd := m.Update(debounceTickMsg{version: m.filterVersion})
+	m = newModel.(Model)
+	if cmd == nil {
+		return m, nil
+	}
+	msg := cmd()
+	if _, ok := msg.(searchResultsMsg); !ok {
+		return m, func() tea.Msg { return msg }
+	}
+	newModel, cmd = m.Update(msg)
+	return newModel.(Model), cmd
+}
+
+func TestDidYouMeanSuggestions(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+
+	projects := []model.Project{
+		{Path: "web/frontend", Name: "frontend", Member: true},
+		{Path: "team/api", Name: "api", Member: true},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, p := range projects {
+		docs = append(docs, index.NewDocument(p))
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to index projects: %v", err)
+	}
+
+	m := New(nil, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", descIndex)
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+
+	// Two typos (adjacent keys): too far for the full-text search with search.fuzziness 1,
+	// close enough for a suggestion
+	index.SetFuzziness(1)
+	defer index.SetFuzziness(-1)
+	m.textInput.SetValue("grontebd")
+	m, _ = searchNow(t, m)
+	if len(m.filtered) != 0 {
+		t.Fatalf("Expected no matches, got %d", len(m.filtered))
+	}
+	if !strings.Contains(m.View(), "Did you mean: web/frontend?") {
+		t.Error("Expected a suggestion in the view")
+	}
+
+	// A query that matches clears the suggestion
+	m.textInput.SetValue("api")
+	m, _ = searchNow(t, m)
+	if len(m.suggestions) != 0 || strings.Contains(m.View(), "Did you mean") {
+		t.Errorf("Expected no suggestions with matches, got %v", m.suggestions)
+	}
+}
+
+func TestCtrlOOpensAndKeepsRunning(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{
+		{Path: "group/api", Name: "api", Member: true},
+		{Path: "group/web", Name: "web", Member: true},
+	}
+	ctrlO := tea.KeyMsg{Type: tea.KeyCtrlO}
+
+	// Without an opener Ctrl+O does nothing
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	if _, cmd := m.Update(ctrlO); cmd != nil {
+		t.Error("Expected Ctrl+O to be ignored without an opener")
+	}
+
+	var opened []string
+	m.SetOpener(func(path string, group bool) error {
+		opened = append(opened, path)
+		if path == m.filtered[1].Project.Path {
+			return errors.New("no browser")
+		}
+		return nil
+	})
+	first := m.filtered[0].Project.Path
+	newModel, cmd := m.Update(ctrlO)
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected Ctrl+O to return a command")
+	}
+	msg := cmd()
+	if len(opened) != 1 || opened[0] != first {
+		t.Fatalf("Expected %s to be opened, got %v", first, opened)
+	}
+	if m.quitting || m.Selected() != "" {
+		t.Error("Expected the TUI to keep running after Ctrl+O")
+	}
+	newModel, _ = m.Update(msg)
+	m = newModel.(Model)
+	if !strings.Contains(m.View(), "Opened "+first) {
+		t.Errorf("Expected an 'Opened' toast, got:\n%s", m.View())
+	}
+
+	// The cursor stays put, so the next candidate can be opened right away
+	newModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	newModel, cmd = newModel.(Model).Update(ctrlO)
+	m = newModel.(Model)
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if len(opened) != 2 || !strings.Contains(m.View(), "Failed to open") {
+		t.Errorf("Expected a failure toast for the second project, got %v:\n%s", opened, m.View())
+	}
+}
+
+func TestCustomOpeners(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{{Path: "group/api", Name: "api", Member: true}}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.width, m.height = 200, 30
+	var opened string
+	m.SetCustomOpeners([]CustomOpener{{Key: "alt+t", Name: "tmux", Open: func(path string, group bool) error {
+		opened = path
+		return nil
+	}}})
+
+	newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}, Alt: true})
+	m = newModel.(Model)
+	if cmd == nil {
+		t.Fatal("Expected Alt+T to run the opener")
+	}
+	newModel, _ = m.Update(cmd())
+	m = newModel.(Model)
+	if opened != "group/api" || m.quitting {
+		t.Errorf("Expected group/api to be opened with the TUI running, got %q (quitting=%v)", opened, m.quitting)
+	}
+	if !strings.Contains(m.View(), "Opened group/api with tmux") {
+		t.Errorf("Expected a toast naming the opener, got:\n%s", m.View())
+	}
+	if m.textInput.Value() != "" {
+		t.Errorf("Expected the key not to reach the prompt, got %q", m.textInput.Value())
+	}
+}
+
+func TestQueryHistoryRecall(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{
+		{Path: "group/api", Name: "api", Member: true},
+		{Path: "group/web", Name: "web", Member: true},
+	}
+	up := tea.KeyMsg{Type: tea.KeyUp}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	press := func(m Model, key tea.KeyMsg) Model {
+		newModel, _ := m.Update(key)
+		return newModel.(Model)
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.SetQueryHistory([]string{"old", "new"}, 100)
+
+	// Up on an empty input recalls the newest query, then older ones
+	m = press(m, up)
+	if got := m.textInput.Value(); got != "new" {
+		t.Fatalf("Expected 'new' after Up, got %q", got)
+	}
+	m = press(m, up)
+	if got := m.textInput.Value(); got != "old" {
+		t.Fatalf("Expected 'old' after a second Up, got %q", got)
+	}
+	m = press(m, up)
+	if got := m.textInput.Value(); got != "old" {
+		t.Errorf("Expected the oldest query to stay, got %q", got)
+	}
+
+	// Down walks back to the empty input
+	m = press(m, down)
+	if got := m.textInput.Value(); got != "new" {
+		t.Errorf("Expected 'new' after Down, got %q", got)
+	}
+	m = press(m, down)
+	if got := m.textInput.Value(); got != "" {
+		t.Errorf("Expected an empty input after the newest query, got %q", got)
+	}
+
+	// Ctrl+P moves the cursor and never recalls
+	m = press(m, tea.KeyMsg{Type: tea.KeyCtrlP})
+	if got := m.textInput.Value(); got != "" {
+		t.Errorf("Expected Ctrl+P not to recall, got %q", got)
+	}
+
+	// The query of a selection is recorded
+	m.rememberQuery(" api ")
+	if got := m.RecordedQueries(); len(got) != 1 || got[0] != "api" {
+		t.Errorf("Expected the recorded query, got %v", got)
+	}
+	m = press(m, up)
+	if got := m.textInput.Value(); got != "api" {
+		t.Errorf("Expected the recorded query to be recalled first, got %q", got)
+	}
+
+	// A limit of 0 disables recall and recording
+	m = New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.SetQueryHistory([]string{"old"}, 0)
+	m.rememberQuery("api")
+	m = press(m, up)
+	if m.textInput.Value() != "" || len(m.RecordedQueries()) != 0 {
+		t.Errorf("Expected no recall with a limit of 0, got %q, %v", m.textInput.Value(), m.RecordedQueries())
+	}
+
+	// Disabled history tracking (history.enabled: false) records no queries either
+	history.SetDefaultEnabled(false)
+	defer history.SetDefaultEnabled(true)
+	m = New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.SetQueryHistory([]string{"old"}, 100)
+	m.rememberQuery("api")
+	if got := m.RecordedQueries(); len(got) != 0 {
+		t.Errorf("Expected no recorded queries with history disabled, got %v", got)
+	}
+}
+
+func TestPageKeysOpenSubPages(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{{Path: "group/api", Name: "api", Member: true}}
+
+	// Without a page opener the help doesn't offer page keys
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	m.historyLoading = false
+	m.showHelp = true
+	if strings.Contains(m.View(), "alt+i") {
+		t.Error("Expected no page keys in the help without a page opener")
+	}
+
+	type opened struct {
+		path, page string
+		group      bool
+	}
+	var got []opened
+	m.SetPageOpener(func(path string, group bool, page string) error {
+		got = append(got, opened{path, page, group})
+		if page == "releases" {
+			return errors.New("no browser")
+		}
+		return nil
+	})
+	if view := m.View(); !strings.Contains(view, "alt+i: container registry") || !strings.Contains(view, "alt+l: releases") {
+		t.Errorf("Expected the page keys in the help, got:\n%s", view)
+	}
+
+	for _, tt := range []struct {
+		key   rune
+		page  string
+		toast string
+	}{
+		{'i', "registry", "Opened the container registry of group/api"},
+		{'k', "packages", "Opened the package registry of group/api"},
+		{'l', "releases", "Failed to open the releases of group/api"},
+		{'n', "environments", "Opened the environments of group/api"},
+	} {
+		newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tt.key}, Alt: true})
+		m = newModel.(Model)
+		if cmd == nil {
+			t.Fatalf("Expected alt+%c to return a command", tt.key)
+		}
+		newModel, _ = m.Update(cmd())
+		m = newModel.(Model)
+		if last := got[len(got)-1]; last.path != "group/api" || last.page != tt.page || last.group {
+			t.Errorf("alt+%c: expected %s of group/api, got %+v", tt.key, tt.page, last)
+		}
+		if m.quitting || m.textInput.Value() != "" {
+			t.Errorf("alt+%c: expected the TUI to keep running with an unchanged query", tt.key)
+		}
+		if !strings.Contains(m.View(), tt.toast) {
+			t.Errorf("alt+%c: expected toast %q, got:\n%s", tt.key, tt.toast, m.View())
+		}
+	}
+}
+
+// TestUpdate_Paging verifies PgUp/PgDn/Home/End and jumping to a result number (Ctrl+G)
+func TestUpdate_Paging(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		GitLab: config.GitLabConfig{URL: "https://gitlab.example.com"},
+		Cache:  config.CacheConfig{Dir: tempDir},
+	}
+	projects := make([]model.Project, 100)
+	for i := range projects {
+		projects[i] = model.Project{Path: fmt.Sprintf("test/project%03d", i), Name: "Project", Member: true}
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", nil)
+	newModel, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 16}) // 10 list lines
+	m = newModel.(Model)
+	press := func(msg tea.KeyMsg) {
+		t.Helper()
+		newModel, _ := m.Update(msg)
+		m = newModel.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyPgDown})
+	if m.cursor != 10 || m.viewportStart != 1 {
+		t.Errorf("PgDn: cursor %d, viewport %d, want 10 and 1", m.cursor, m.viewportStart)
+	}
+	press(tea.KeyMsg{Type: tea.KeyPgUp})
+	if m.cursor != 0 || m.viewportStart != 0 {
+		t.Errorf("PgUp: cursor %d, viewport %d, want 0 and 0", m.cursor, m.viewportStart)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnd})
+	if m.cursor != 99 || m.viewportStart != 90 {
+		t.Errorf("End: cursor %d, viewport %d, want 99 and 90", m.cursor, m.viewportStart)
+	}
+	if view := m.View(); !strings.Contains(view, "┃") {
+		t.Errorf("Expected a scrollbar thumb, got:\n%s", view)
+	}
+	press(tea.KeyMsg{Type: tea.KeyHome})
+	if m.cursor != 0 || m.viewportStart != 0 {
+		t.Errorf("Home: cursor %d, viewport %d, want 0 and 0", m.cursor, m.viewportStart)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlG})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'4'}})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}) // Ignored
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	if view := m.View(); !strings.Contains(view, "Jump to result (1-100): 42") {
+		t.Errorf("Expected the jump prompt, got:\n%s", view)
+	}
+	if m.textInput.Value() != "" {
+		t.Errorf("Expected the search input to stay empty, got %q", m.textInput.Value())
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.jumping || m.cursor != 41 {
+		t.Errorf("Jump: jumping %v, cursor %d, want false and 41", m.jumping, m.cursor)
+	}
+	if m.cursor < m.viewportStart || m.cursor >= m.viewportStart+m.visibleItems(m.viewportStart) {
+		t.Errorf("Expected cursor %d in view from %d", m.cursor, m.viewportStart)
+	}
+
+	// Esc cancels the jump without quitting
+	press(tea.KeyMsg{Type: tea.KeyCtrlG})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.jumping || m.quitting || m.cursor != 41 {
+		t.Errorf("Esc: jumping %v, quitting %v, cursor %d, want false, false and 41", m.jumping, m.quitting, m.cursor)
+	}
+}
+
+func TestScrollbar(t *testing.T) {
+	if bar := scrollbar(10, 10, 0, 10); bar != nil {
+		t.Errorf("Expected no scrollbar when every result is shown, got %v", bar)
+	}
+
+	tests := []struct {
+		start     int
+		wantThumb []int
+	}{
+		{start: 0, wantThumb: []int{0}},
+		{start: 50, wantThumb: []int{5}},
+		{start: 90, wantThumb: []int{9}}, // Last page: the thumb reaches the bottom
+	}
+	for _, tt := range tests {
+		bar := scrollbar(10, 100, tt.start, 10)
+		var thumb []int
+		for i, cell := range bar {
+			if cell {
+				thumb = append(thumb, i)
+			}
+		}
+		if len(bar) != 10 || !reflect.DeepEqual(thumb, tt.wantThumb) {
+			t.Errorf("scrollbar(start %d) thumb = %v (of %d), want %v", tt.start, thumb, len(bar), tt.wantThumb)
+		}
+	}
+}
+
+// TestUpdate_CtrlT_Star verifies that Ctrl+T stars the selected project and updates its index document
+func TestUpdate_CtrlT_Star(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{
+		{Path: "team/api", Name: "api", Member: true},
+		{Path: "team/web", Name: "web", Member: true},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, p := range projects {
+		docs = append(docs, index.NewDocument(p))
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to index projects: %v", err)
+	}
+
+	var calls []string
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", descIndex)
+	m.SetStarToggle(func(projectPath string, starred bool) error {
+		calls = append(calls, fmt.Sprintf("%s=%v", projectPath, starred))
+		if projectPath == "team/web" {
+			return errors.New("failed to star team/web: 403 Forbidden")
+		}
+		return nil
+	})
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+	m.cursor = slices.IndexFunc(m.filtered, func(match index.CombinedMatch) bool { return match.Project.Path == "team/api" })
+
+	press := func() {
+		t.Helper()
+		newModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+		m = newModel.(Model)
+		if cmd == nil {
+			t.Fatal("Expected a star command")
+		}
+		newModel, _ = m.Update(cmd())
+		m = newModel.(Model)
+	}
+
+	press()
+	if !m.filtered[m.cursor].Project.Starred {
+		t.Error("Expected the selected result to be starred")
+	}
+	if !strings.Contains(m.View(), "Starred team/api") {
+		t.Error("Expected a toast for the starred project")
+	}
+	p, found, err := descIndex.GetProject("team/api")
+	if err != nil || !found || !p.Starred {
+		t.Errorf("Expected the index document to be starred, got %+v (found %v, err %v)", p, found, err)
+	}
+
+	// Pressing again unstars it
+	press()
+	if p, _, _ := descIndex.GetProject("team/api"); p.Starred {
+		t.Error("Expected the index document to be unstarred")
+	}
+
+	// API errors leave the project unchanged
+	m.cursor = slices.IndexFunc(m.filtered, func(match index.CombinedMatch) bool { return match.Project.Path == "team/web" })
+	press()
+	if m.filtered[m.cursor].Project.Starred || !m.toastError {
+		t.Errorf("Expected an error toast and an unstarred project, got starred %v, toast %q", m.filtered[m.cursor].Project.Starred, m.toast)
+	}
+
+	want := []string{"team/api=true", "team/api=false", "team/web=true"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Star calls = %v, want %v", calls, want)
+	}
+}
+
+func TestUpdate_AltF_Forks(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{
+		{Path: "platform/auth", Name: "auth", Member: true},
+		{Path: "alice/auth", Name: "auth", ForkedFrom: "platform/auth"},
+		{Path: "bob/auth", Name: "auth", ForkedFrom: "platform/auth"},
+		{Path: "team/web", Name: "web", Member: true},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, p := range projects {
+		docs = append(docs, index.NewDocument(p))
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to index projects: %v", err)
+	}
+
+	m := New(projects, "auth", nil, tempDir, cfg, false, true, "user", "v1.0.0", descIndex)
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+	m.SetCollapseForks(true)
+
+	paths := func() []string {
+		var result []string
+		for _, match := range m.filtered {
+			result = append(result, match.Project.Path)
+		}
+		return result
+	}
+	if got := paths(); !slices.Equal(got, []string{"platform/auth"}) {
+		t.Fatalf("Expected the forks to be collapsed under platform/auth, got %v", got)
+	}
+	if !strings.Contains(m.View(), "+2 ⑂") {
+		t.Error("Expected the number of collapsed forks to be shown")
+	}
+
+	altF := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}, Alt: true}
+	newModel, _ := m.Update(altF)
+	m = newModel.(Model)
+	if got := paths(); len(got) != 3 || got[0] != "platform/auth" {
+		t.Fatalf("Expected the forks to be listed under platform/auth, got %v", got)
+	}
+	if !strings.Contains(m.View(), "↳ ") {
+		t.Error("Expected the forks to be indented")
+	}
+
+	// Alt+F on a fork hides the forks of its upstream and selects the upstream
+	m.cursor = 2
+	newModel, _ = m.Update(altF)
+	m = newModel.(Model)
+	if got := paths(); len(got) != 1 || m.cursor != 0 {
+		t.Errorf("Expected the forks to be hidden again with the upstream selected, got %v (cursor %d)", got, m.cursor)
+	}
+}
+
+func TestFilter_GroupListing(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{Cache: config.CacheConfig{Dir: tempDir}}
+	projects := []model.Project{
+		{Path: "backend/api", Name: "api", Member: true},
+		{Path: "backend/payments/billing", Name: "billing", Member: true},
+		{Path: "backend/payments/ledger", Name: "ledger", Member: true},
+		{Path: "backend/payments/internal/audit", Name: "audit", Member: true},
+		{Path: "backend/guest", Name: "guest"},
+	}
+
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	docs := make([]index.DescriptionDocument, 0, len(projects))
+	for _, p := range projects {
+		docs = append(docs, index.NewDocument(p))
+	}
+	if err := descIndex.AddBatch(docs); err != nil {
+		t.Fatalf("Failed to index projects: %v", err)
+	}
+
+	m := New(nil, "backend/", nil, tempDir, cfg, false, false, "user", "v1.0.0", descIndex)
+	m.historyLoading = false
+	m.width, m.height = 120, 30
+	m.filter()
+
+	paths := func() []string {
+		var result []string
+		for _, match := range m.filtered {
+			result = append(result, match.Project.Path)
+		}
+		return result
+	}
+	// Hidden (non-member) projects stay hidden in listings
+	if got := paths(); !slices.Equal(got, []string{"backend/payments", "backend/api"}) {
+		t.Fatalf("Expected the subgroup, then the projects of backend, got %v", got)
+	}
+	if m.hiddenMatches != 1 {
+		t.Errorf("Expected 1 hidden project, got %d", m.hiddenMatches)
+	}
+	if view := m.View(); !strings.Contains(view, "▸") || !strings.Contains(view, "3 projects") {
+		t.Error("Expected the subgroup row with its number of projects")
+	}
+
+	// Enter on a subgroup lists it instead of selecting it
+	newModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = newModel.(Model)
+	if m.textInput.Value() != "backend/payments/" || m.selected != "" {
+		t.Fatalf("Expected Enter to list the subgroup, got query %q and selection %q", m.textInput.Value(), m.selected)
+	}
+	if got := paths(); !slices.Equal(got, []string{"backend/payments/internal", "backend/payments/billing", "backend/payments/ledger"}) {
+		t.Errorf("Expected the listing of backend/payments, got %v", got)
+	}
+}
+
+func TestFilter_PinnedProjectsAlwaysListed(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := &config.Config{
+		Cache:       config.CacheConfig{Dir: tempDir},
+		PinnedPaths: []string{"team/web"},
+	}
+	projects := []model.Project{
+		{Path: "team/api", Name: "api", Member: true},
+		{Path: "team/web", Name: "web", Member: true},
+	}
+	descIndex, err := index.NewDescriptionIndex(filepath.Join(tempDir, "description.bleve"))
+	if err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+	defer descIndex.Close()
+	if err := descIndex.AddBatch([]index.DescriptionDocument{index.NewDocument(projects[0]), index.NewDocument(projects[1])}); err != nil {
+		t.Fatalf("Failed to index projects: %v", err)
+	}
+
+	m := New(projects, "", nil, tempDir, cfg, false, false, "user", "v1.0.0", descIndex)
+	m.historyLoading = false
+	m.textInput.SetValue("api")
+	m.filter()
+
+	if len(m.filtered) != 2 || m.filtered[0].Project.Path != "team/web" || !m.filtered[0].Pinned {
+		t.Fatalf("Expected the pinned project first although the query doesn't match it, got %v", m.filtered)
+	}
+	if m.filtered[1].Project.Path != "team/api" {
+		t.Errorf("Expected the match after the pin, got %s", m.filtered[1].Project.Path)
+	}
+
+	// Dedicated filters still apply to pinned projects
+	m.onlyStarred = true
+	m.filter()
+	if len(m.filtered) != 0 {
+		t.Errorf("Expected the starred filter to remove the pinned project, got %v", m.filtered)
+	}
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/index"
+)
+
+// OpenFunc opens a project (or a group, in groups mode) in the browser without leaving the TUI
+type OpenFunc func(path string, group bool) error
+
+// CustomOpener is a user-defined opener bound to a key (openers in the config)
+type CustomOpener struct {
+	Key  string   // Key running the opener (bubbletea key name, e.g. "alt+t")
+	Name string   // Opener name shown in the toast and the help
+	Open OpenFunc // Runs the opener's command
+}
+
+// openedMsg is sent when a result opened with Ctrl+O or a custom opener has been handed over
+type openedMsg struct {
+	path string
+	with string // Custom opener name ("" = browser)
+	page string // Sub-page opened in the browser (pageKeys; "" = the result itself)
+	err  error
+}
+
+// SetOpener enables Ctrl+O (open the selected result in the browser and keep the finder running)
+func (m *Model) SetOpener(fn OpenFunc) {
+	m.open = fn
+}
+
+// SetCustomOpeners binds user-defined openers to keys
+// Built-in keys win over openers bound to the same key
+func (m *Model) SetCustomOpeners(openers []CustomOpener) {
+	m.customOpeners = openers
+}
+
+// customOpener returns the custom opener bound to key
+func (m *Model) customOpener(key string) (CustomOpener, bool) {
+	for _, o := range m.customOpeners {
+		if o.Key == key {
+			return o, true
+		}
+	}
+	return CustomOpener{}, false
+}
+
+// openInBackground opens the result under the cursor with open (the browser unless with names
+// a custom opener) and keeps the TUI running
+// The selection is recorded in the history like Enter; the result list is not re-ranked,
+// so the cursor stays where it is for opening further candidates
+func (m *Model) openInBackground(open OpenFunc, with string) tea.Cmd {
+	if open == nil || len(m.filtered) == 0 || m.cursor >= len(m.filtered) {
+		return nil
+	}
+	match := m.filtered[m.cursor]
+	path := match.Project.Path
+	group := m.groupsMode
+
+	// Remote results are injected into the index so they are found locally next time
+	if match.Remote && m.descIndex != nil {
+		_ = m.descIndex.AddBatch([]index.DescriptionDocument{index.NewDocument(match.Project)}) // Next sync adds it anyway
+	}
+
+	m.rememberQuery(m.textInput.Value())
+	if m.history != nil {
+		m.history.RecordSelectionWithQuery(strings.TrimSpace(m.textInput.Value()), match.Project.Key())
+		_ = m.history.Save() // Silently fail - don't prevent opening
+		m.emptyResultsCached = false
+	}
+
+	return func() tea.Msg {
+		return openedMsg{path: path, with: with, err: open(path, group)}
+	}
+}
+
+// handleOpened reports a result opened with Ctrl+O, a custom opener or a page key in a toast
+func (m *Model) handleOpened(msg openedMsg) tea.Cmd {
+	if msg.page != "" {
+		if msg.err != nil {
+			return m.showToast("Failed to open the "+msg.page+" of "+msg.path+": "+msg.err.Error(), true)
+		}
+		return m.showToast("Opened the "+msg.page+" of "+msg.path, false)
+	}
+	with := ""
+	if msg.with != "" {
+		with = " with " + msg.with
+	}
+	if msg.err != nil {
+		return m.showToast("Failed to open "+msg.path+with+": "+msg.err.Error(), true)
+	}
+	return m.showToast("Opened "+msg.path+with, false)
+}
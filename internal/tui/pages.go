@@ -0,0 +1,59 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PageOpenFunc opens a sub-page (a --target name, e.g. "registry") of a project or group
+// in the browser without leaving the TUI
+type PageOpenFunc func(path string, group bool, page string) error
+
+// pageKey binds a key to a project sub-page
+type pageKey struct {
+	key  string // Key opening the page (bubbletea key name)
+	page string // Target name (see internal/target)
+	name string // Page name shown in the toast and the help
+}
+
+// pageKeys are the sub-pages opened straight from the result list
+var pageKeys = []pageKey{
+	{key: "alt+i", page: "registry", name: "container registry"},
+	{key: "alt+k", page: "packages", name: "package registry"},
+	{key: "alt+l", page: "releases", name: "releases"},
+	{key: "alt+n", page: "environments", name: "environments"},
+}
+
+// SetPageOpener enables the keys opening a sub-page of the selected result (pageKeys)
+func (m *Model) SetPageOpener(fn PageOpenFunc) {
+	m.openPage = fn
+}
+
+// pageForKey returns the sub-page bound to key
+func pageForKey(key string) (pageKey, bool) {
+	for _, p := range pageKeys {
+		if p.key == key {
+			return p, true
+		}
+	}
+	return pageKey{}, false
+}
+
+// openPageInBackground opens a sub-page of the result under the cursor and keeps the TUI running
+// Like Ctrl+O, the selection is recorded in the history
+func (m *Model) openPageInBackground(p pageKey) tea.Cmd {
+	if m.openPage == nil {
+		return nil
+	}
+	openPage := m.openPage
+	cmd := m.openInBackground(func(path string, group bool) error {
+		return openPage(path, group, p.page)
+	}, "")
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd().(openedMsg)
+		msg.page = p.name
+		return msg
+	}
+}
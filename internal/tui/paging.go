@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/igusev/glf/internal/index"
+)
+
+// maxJumpDigits caps the result number typed after Ctrl+G
+const maxJumpDigits = 9
+
+// matchLines returns the number of list lines a result takes (a second line for its snippet)
+func matchLines(match index.CombinedMatch) int {
+	if match.Snippet != "" {
+		return 2
+	}
+	return 1
+}
+
+// visibleItems returns how many results fit in the list when it starts at start (at least 1)
+func (m Model) visibleItems(start int) int {
+	maxLines := m.listHeight()
+	lines, items := 0, 0
+	for i := start; i < len(m.filtered); i++ {
+		lines += matchLines(m.filtered[i])
+		if lines > maxLines {
+			break
+		}
+		items++
+	}
+	return max(1, items)
+}
+
+// moveCursor moves the cursor to the result at index (clamped to the list) and scrolls it into view
+func (m *Model) moveCursor(index int) {
+	if len(m.filtered) == 0 {
+		return
+	}
+	m.cursor = min(max(index, 0), len(m.filtered)-1)
+	m.ensureCursorVisible(m.listHeight())
+}
+
+// hotkeyResults is how many results on screen are numbered for Alt+1-9
+const hotkeyResults = 9
+
+// hotkeyNumber returns the result number of an Alt+1-9 key (1-based, counted from the top of the screen)
+func hotkeyNumber(key string) (int, bool) {
+	digit, ok := strings.CutPrefix(key, "alt+")
+	if !ok || len(digit) != 1 || digit[0] < '1' || digit[0] > '9' {
+		return 0, false
+	}
+	return int(digit[0] - '0'), true
+}
+
+// hotkeyLabel returns the number shown before the row-th result on screen (0-based),
+// or a space for rows past hotkeyResults
+func hotkeyLabel(row int) string {
+	if row < 0 || row >= hotkeyResults {
+		return " "
+	}
+	return strconv.Itoa(row + 1)
+}
+
+// pagingKey handles the keys moving through long lists: PgUp/PgDn move by a screen,
+// Home/End go to the first/last result, Ctrl+G asks for a result number
+// Returns false for other keys
+func (m *Model) pagingKey(key string) bool {
+	switch key {
+	case "pgdown":
+		m.moveCursor(m.cursor + m.visibleItems(m.viewportStart))
+	case "pgup":
+		m.moveCursor(m.cursor - m.visibleItems(m.viewportStart))
+	case "home":
+		m.moveCursor(0)
+	case "end":
+		m.moveCursor(len(m.filtered) - 1)
+	case "ctrl+g":
+		if len(m.filtered) > 0 {
+			m.jumping = true
+			m.jumpInput = ""
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// updateJump handles key presses while a result number is typed (Ctrl+G)
+// Enter jumps to the result, Esc or Ctrl+G cancels
+func (m Model) updateJump(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "ctrl+c":
+		m.quitting = true
+		if m.history != nil {
+			_ = m.history.Save() // Silently fail - don't prevent quit
+		}
+		return m, tea.Quit
+
+	case "esc", "ctrl+g":
+		m.jumping = false
+
+	case "enter":
+		m.jumping = false
+		if n, err := strconv.Atoi(m.jumpInput); err == nil {
+			m.moveCursor(n - 1) // Results are numbered from 1
+		}
+
+	case "backspace":
+		if m.jumpInput != "" {
+			m.jumpInput = m.jumpInput[:len(m.jumpInput)-1]
+		}
+
+	default:
+		if len(key) == 1 && key[0] >= '0' && key[0] <= '9' && len(m.jumpInput) < maxJumpDigits {
+			m.jumpInput += key
+		}
+	}
+	return m, nil
+}
+
+// renderJump renders the result number prompt shown above the search input
+func (m Model) renderJump() string {
+	return m.styles.StatusActive.Render(fmt.Sprintf("  Jump to result (1-%d): %s", len(m.filtered), m.jumpInput)) +
+		m.styles.Cursor.Render("▏")
+}
+
+// scrollbar returns the scrollbar of a list of total results showing visible of them from start,
+// one cell per line of height: true for the thumb, false for the track
+// Returns nil when every result is shown
+func scrollbar(height, total, start, visible int) []bool {
+	if height < 1 || total <= visible {
+		return nil
+	}
+	thumb := min(max(1, height*visible/total), height)
+	pos := height * start / total
+	if start+visible >= total {
+		pos = height - thumb // The last result is shown: the thumb reaches the bottom
+	}
+	pos = min(pos, height-thumb)
+
+	cells := make([]bool, height)
+	for i := pos; i < pos+thumb; i++ {
+		cells[i] = true
+	}
+	return cells
+}
+
+// withScrollbar appends the scrollbar to the rendered list lines, in the last column of width
+func (m Model) withScrollbar(list string, bar []bool) string {
+	lines := strings.Split(list, "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		if i >= len(bar) {
+			break
+		}
+		b.WriteString(line)
+		if pad := m.width - 1 - lipgloss.Width(line); pad > 0 {
+			b.WriteString(strings.Repeat(" ", pad))
+		}
+		if bar[i] {
+			b.WriteString(m.styles.Cursor.Render("┃"))
+		} else {
+			b.WriteString(m.styles.Help.Render("│"))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
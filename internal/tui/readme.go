@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/igusev/glf/internal/model"
+)
+
+// ReadmeFunc fetches the README of a project (raw Markdown) live from GitLab
+type ReadmeFunc func(projectPath string) (string, error)
+
+// readmeLoadedMsg is sent when the README of a project has been fetched
+type readmeLoadedMsg struct {
+	projectPath string
+	content     string
+	err         error
+}
+
+// SetReadmeFetcher enables the README preview (Alt+V on a project) using fn to fetch READMEs
+func (m *Model) SetReadmeFetcher(fn ReadmeFunc) {
+	m.fetchReadme = fn
+}
+
+// inReadmeMode reports whether the README preview of a project is shown
+func (m Model) inReadmeMode() bool {
+	return m.readmeProject != nil
+}
+
+// enterReadmeMode shows the README of the project under the cursor
+// READMEs are fetched once per session and cached
+func (m *Model) enterReadmeMode() tea.Cmd {
+	if m.fetchReadme == nil || len(m.filtered) == 0 || m.cursor >= len(m.filtered) {
+		return nil
+	}
+
+	project := m.filtered[m.cursor].Project
+	m.readmeProject = &project
+	m.readmeErr = nil
+	m.readmeViewport = viewport.New(m.width, m.readmeHeight())
+	avatarCmd := m.avatarCmd(project)
+
+	if content, ok := m.readmeCache[project.Path]; ok {
+		m.readmeLoading = false
+		m.renderReadmeContent(content)
+		return avatarCmd
+	}
+
+	m.readmeLoading = true
+	fetchReadme := m.fetchReadme
+	path := project.Path
+	return tea.Batch(func() tea.Msg {
+		content, err := fetchReadme(path)
+		return readmeLoadedMsg{projectPath: path, content: content, err: err}
+	}, avatarCmd)
+}
+
+// exitReadmeMode returns to the project list
+func (m *Model) exitReadmeMode() {
+	m.readmeProject = nil
+	m.readmeErr = nil
+	m.readmeLoading = false
+}
+
+// updateReadme handles key presses in the README preview
+func (m Model) updateReadme(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		if m.history != nil {
+			_ = m.history.Save() // Silently fail - don't prevent quit
+		}
+		return m, tea.Quit
+
+	case "esc", "q", "alt+v":
+		imageShown := m.avatarShown()
+		m.exitReadmeMode()
+		if imageShown {
+			return m, tea.ClearScreen // Redraw from scratch: the avatar image would stay on screen
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.readmeViewport, cmd = m.readmeViewport.Update(msg)
+	return m, cmd
+}
+
+// handleReadmeLoaded stores a fetched README and renders it if its preview is still open
+func (m *Model) handleReadmeLoaded(msg readmeLoadedMsg) {
+	if msg.err == nil {
+		if m.readmeCache == nil {
+			m.readmeCache = make(map[string]string)
+		}
+		m.readmeCache[msg.projectPath] = msg.content
+	}
+	if !m.inReadmeMode() || m.readmeProject.Path != msg.projectPath {
+		return
+	}
+
+	m.readmeLoading = false
+	m.readmeErr = msg.err
+	if msg.err == nil {
+		m.renderReadmeContent(msg.content)
+	}
+}
+
+// resizeReadme fits the README viewport to the terminal and re-renders the content
+func (m *Model) resizeReadme() {
+	if !m.inReadmeMode() {
+		return
+	}
+	m.readmeViewport.Width = m.width
+	m.readmeViewport.Height = m.readmeHeight()
+	if content, ok := m.readmeCache[m.readmeProject.Path]; ok {
+		m.renderReadmeContent(content)
+	}
+}
+
+// renderReadmeContent renders Markdown into the README viewport
+// Falls back to the raw Markdown if rendering fails
+func (m *Model) renderReadmeContent(content string) {
+	if strings.TrimSpace(content) == "" {
+		m.readmeViewport.SetContent(m.styles.Help.Render("  The README is empty"))
+		return
+	}
+
+	style := styles.LightStyle
+	if lipgloss.HasDarkBackground() {
+		style = styles.DarkStyle
+	}
+	wrap := m.width - 4
+	if wrap < 20 {
+		wrap = 80
+	}
+
+	rendered := content
+	renderer, err := glamour.NewTermRenderer(glamour.WithStandardStyle(style), glamour.WithWordWrap(wrap))
+	if err == nil {
+		if out, renderErr := renderer.Render(content); renderErr == nil {
+			rendered = out
+		}
+	}
+	m.readmeViewport.SetContent(rendered)
+	m.readmeViewport.GotoTop()
+}
+
+// projectMetadata describes the default branch, visibility, last activity, upstream and former
+// paths of a project
+// Fields GitLab did not return are left out
+func projectMetadata(project model.Project) string {
+	var parts []string
+	if project.DefaultBranch != "" {
+		parts = append(parts, project.DefaultBranch)
+	}
+	if project.Visibility != "" {
+		parts = append(parts, project.Visibility)
+	}
+	if !project.LastActivityAt.IsZero() {
+		parts = append(parts, "last activity "+project.LastActivityAt.Local().Format("2006-01-02"))
+	}
+	if project.IsFork() {
+		parts = append(parts, "fork of "+project.ForkedFrom)
+	}
+	if len(project.FormerPaths) > 0 {
+		parts = append(parts, "formerly "+strings.Join(project.FormerPaths, ", "))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// readmeHeight returns the number of lines available for the README viewport
+func (m Model) readmeHeight() int {
+	// Header, separator, empty, search, 2 empty, project line, footer
+	height := m.height - 8
+	if m.avatars {
+		height -= avatarRows - 1 // The avatar header replaces the project line
+	}
+	if height < 1 {
+		return 1
+	}
+	return height
+}
+
+// renderReadme renders the README preview of the selected project
+func (m Model) renderReadme() string {
+	var b strings.Builder
+
+	if m.avatars {
+		b.WriteString(m.renderAvatarHeader(*m.readmeProject))
+	} else {
+		header := "  README of " + m.readmeProject.Path
+		if metadata := projectMetadata(*m.readmeProject); metadata != "" {
+			header += " • " + metadata
+		}
+		b.WriteString(m.styles.Help.Render(header))
+		b.WriteString("\n")
+	}
+
+	switch {
+	case m.readmeLoading:
+		b.WriteString(m.styles.Help.Render("  Loading README..."))
+		b.WriteString("\n")
+		return b.String()
+	case m.readmeErr != nil:
+		b.WriteString(m.styles.Help.Render("  " + m.readmeErr.Error()))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(m.readmeViewport.View())
+	b.WriteString("\n")
+	b.WriteString(m.styles.Help.Render(fmt.Sprintf("  ↑/↓/pgup/pgdn: scroll • esc: back to projects • %3.0f%%", m.readmeViewport.ScrollPercent()*100)))
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/cache"
+)
+
+// SetQueryHistory sets the past search queries (oldest first) recalled with Up/Down on an
+// empty input, and the number of queries kept (tui.query_history; 0 disables recall)
+func (m *Model) SetQueryHistory(queries []string, limit int) {
+	m.queryHistoryLimit = limit
+	m.queryHistory = nil
+	if limit > 0 {
+		m.queryHistory = queries[max(0, len(queries)-limit):]
+	}
+	m.queryRecall = 0
+}
+
+// RecordedQueries returns the queries of the selections made in this session, oldest first
+// (appended to the saved query history on exit)
+func (m Model) RecordedQueries() []string {
+	return m.recordedQueries
+}
+
+// rememberQuery adds the query of a selection to the query history
+// Nothing is recorded while history tracking is off (history.enabled)
+func (m *Model) rememberQuery(query string) {
+	if m.queryHistoryLimit <= 0 || strings.TrimSpace(query) == "" {
+		return
+	}
+	if m.history != nil && !m.history.Enabled() {
+		return
+	}
+	m.recordedQueries = append(m.recordedQueries, strings.TrimSpace(query))
+	m.queryHistory = cache.AppendQuery(m.queryHistory, query)
+	if len(m.queryHistory) > m.queryHistoryLimit {
+		m.queryHistory = m.queryHistory[len(m.queryHistory)-m.queryHistoryLimit:]
+	}
+	m.queryRecall = 0
+}
+
+// recalling reports whether the input holds a recalled query the user has not edited
+func (m Model) recalling() bool {
+	return m.queryRecall > 0 && m.queryRecall <= len(m.queryHistory) &&
+		m.textInput.Value() == m.queryHistory[len(m.queryHistory)-m.queryRecall]
+}
+
+// recallOlder handles Up: on an empty input with the cursor on the first result it recalls
+// the newest query, and while recalling the next older one
+// Returns false when Up should move the cursor instead
+func (m *Model) recallOlder() (tea.Cmd, bool) {
+	switch {
+	case m.recalling():
+		if m.queryRecall < len(m.queryHistory) {
+			m.queryRecall++
+		}
+	case m.textInput.Value() == "" && m.cursor == 0 && len(m.queryHistory) > 0:
+		m.queryRecall = 1
+	default:
+		return nil, false
+	}
+	return m.setQuery(m.queryHistory[len(m.queryHistory)-m.queryRecall]), true
+}
+
+// recallNewer handles Down while recalling: the next newer query, or the empty input after the newest
+// Returns false when Down should move the cursor instead
+func (m *Model) recallNewer() (tea.Cmd, bool) {
+	if !m.recalling() {
+		return nil, false
+	}
+	m.queryRecall--
+	if m.queryRecall == 0 {
+		return m.setQuery(""), true
+	}
+	return m.setQuery(m.queryHistory[len(m.queryHistory)-m.queryRecall]), true
+}
+
+// setQuery replaces the search input and searches it right away (no keystroke debounce)
+func (m *Model) setQuery(query string) tea.Cmd {
+	m.textInput.SetValue(query)
+	m.textInput.CursorEnd()
+	m.cancelSearch()
+	m.cursor = 0
+	m.viewportStart = 0
+	m.filterVersion++
+	return m.searchCmd()
+}
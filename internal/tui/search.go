@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/search"
+)
+
+// searchFunc runs a project search outside the update loop
+type searchFunc func(ctx context.Context, query string, historyScores map[string]int) ([]index.CombinedMatch, error)
+
+// searchResultsMsg is sent when an async search started by searchCmd finishes
+type searchResultsMsg struct {
+	version int    // filterVersion the search was started for (older results are stale)
+	query   string // Trimmed query that was searched
+	matches []index.CombinedMatch
+	err     error
+}
+
+// searcher returns the project search for the current mode, or nil while a sync holds the index
+// The function captures the state it needs, so it can run in a tea.Cmd
+func (m *Model) searcher() searchFunc {
+	projects, cacheDir, descIndex := m.projects, m.cacheDir, m.descIndex
+	switch {
+	case m.regexMode:
+		if descIndex == nil && m.syncing {
+			return nil
+		}
+		return func(_ context.Context, query string, historyScores map[string]int) ([]index.CombinedMatch, error) {
+			return search.RegexSearchWithIndex(query, projects, historyScores, cacheDir, descIndex)
+		}
+	case descIndex == nil && m.syncing:
+		return nil
+	default:
+		// Without an open index the search opens it from the cache directory
+		return func(ctx context.Context, query string, historyScores map[string]int) ([]index.CombinedMatch, error) {
+			return search.CombinedSearchContext(ctx, query, projects, historyScores, cacheDir, descIndex)
+		}
+	}
+}
+
+// searchCmd searches the current query in the background (after the keystroke debounce)
+// so typing stays responsive on big indexes; the previous search is canceled
+// Group search, "group/" listings and the cached empty query are cheap and run synchronously
+func (m *Model) searchCmd() tea.Cmd {
+	m.cancelSearch()
+	query := strings.TrimSpace(m.textInput.Value())
+	if m.groupsMode || (query == "" && m.emptyResultsCached) || search.GroupListingPrefix(query) != "" {
+		m.filter()
+		return m.remoteSearchCmd()
+	}
+
+	run := m.searcher()
+	if run == nil {
+		return nil
+	}
+	historyScores := m.historyScores(query)
+	ctx, cancel := context.WithCancel(context.Background())
+	m.searchCancel = cancel
+	m.searching = true
+	version := m.filterVersion
+	return func() tea.Msg {
+		matches, err := run(ctx, query, historyScores)
+		return searchResultsMsg{version: version, query: query, matches: matches, err: err}
+	}
+}
+
+// handleSearchResults shows the results of an async search unless the query changed since
+// Returns the remote search command for queries without local results
+func (m *Model) handleSearchResults(msg searchResultsMsg) tea.Cmd {
+	if msg.version != m.filterVersion || errors.Is(msg.err, context.Canceled) {
+		return nil
+	}
+	m.cancelSearch()
+	m.applyMatches(msg.query, msg.matches, msg.err)
+	return m.remoteSearchCmd()
+}
+
+// cancelSearch stops the running async search, if any
+func (m *Model) cancelSearch() {
+	if m.searchCancel != nil {
+		m.searchCancel()
+		m.searchCancel = nil
+	}
+	m.searching = false
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"time"
+
+	"github.com/igusev/glf/internal/cache"
+)
+
+// Session returns the state saved on exit for --resume: query, filter toggles and the result under the cursor
+func (m Model) Session() cache.Session {
+	query := m.textInput.Value()
+	if m.inIssuesMode() {
+		query = m.projectQuery // The input holds the issue query
+	}
+	session := cache.Session{
+		Query:         query,
+		GroupsMode:    m.groupsMode,
+		RegexMode:     m.regexMode,
+		ShowHidden:    m.showHidden,
+		OnlyArchived:  m.onlyArchived,
+		OnlyNonMember: m.onlyNonMember,
+		OnlyStarred:   m.onlyStarred,
+		SortByMRs:     m.sortByMRs,
+		SavedAt:       time.Now(),
+	}
+	if m.cursor < len(m.filtered) && !m.filtered[m.cursor].Remote {
+		session.Selected = m.filtered[m.cursor].Project.Path
+	}
+	return session
+}
+
+// RestoreSession applies a saved session before the TUI starts (--resume)
+// The cursor moves to the saved result when it shows up in the results; the group index
+// must be set first for a groups mode session
+func (m *Model) RestoreSession(session cache.Session) {
+	m.textInput.SetValue(session.Query)
+	m.textInput.CursorEnd()
+	m.showHidden = session.ShowHidden
+	m.onlyArchived = session.OnlyArchived
+	m.onlyNonMember = session.OnlyNonMember
+	m.onlyStarred = session.OnlyStarred
+	m.sortByMRs = session.SortByMRs
+	m.restorePath = session.Selected
+	m.cursor = 0
+	m.viewportStart = 0
+
+	if session.GroupsMode && m.groupIndex != nil {
+		m.SetGroupsMode(true)
+		return
+	}
+	m.SetRegexMode(session.RegexMode) // Re-runs the search
+}
+
+// restoreCursor moves the cursor to the result selected in the restored session
+// The path is kept until the user presses a key, so re-ranking after history loads keeps it selected
+func (m *Model) restoreCursor() {
+	if m.restorePath == "" {
+		return
+	}
+	for i, match := range m.filtered {
+		if match.Project.Path == m.restorePath {
+			m.cursor = i
+			m.ensureCursorVisible(m.listHeight())
+			return
+		}
+	}
+}
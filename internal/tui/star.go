@@ -0,0 +1,67 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/igusev/glf/internal/index"
+	"github.com/igusev/glf/internal/logger"
+	"github.com/igusev/glf/internal/model"
+)
+
+// StarFunc stars (starred = true) or unstars a project on GitLab
+type StarFunc func(projectPath string, starred bool) error
+
+// starredMsg is sent when starring or unstarring a project (Ctrl+T) has finished
+type starredMsg struct {
+	project model.Project // The project with its new starred state
+	err     error
+}
+
+// SetStarToggle enables Ctrl+T (star or unstar the selected project on GitLab)
+func (m *Model) SetStarToggle(fn StarFunc) {
+	m.star = fn
+}
+
+// toggleStar stars or unstars the project under the cursor in the background
+func (m *Model) toggleStar() tea.Cmd {
+	if m.star == nil || m.groupsMode || len(m.filtered) == 0 || m.cursor >= len(m.filtered) {
+		return nil
+	}
+	project := m.filtered[m.cursor].Project
+	project.Starred = !project.Starred
+	star := m.star
+	return func() tea.Msg {
+		return starredMsg{project: project, err: star(project.Path, project.Starred)}
+	}
+}
+
+// handleStarred applies a finished star toggle and reports it in a toast
+// The project's index document is updated right away, so the star bonus applies from the
+// next query instead of the next sync; the shown results keep their order
+func (m *Model) handleStarred(msg starredMsg) tea.Cmd {
+	p := msg.project
+	if msg.err != nil {
+		return m.showToast(msg.err.Error(), true)
+	}
+
+	if m.descIndex != nil {
+		if err := m.descIndex.AddBatch([]index.DescriptionDocument{index.NewDocument(p)}); err != nil {
+			logger.Debug("Failed to update the index document of %s: %v", p.Path, err) // Next sync updates it anyway
+		}
+	}
+	for i := range m.filtered {
+		if m.filtered[i].Project.Path == p.Path {
+			m.filtered[i].Project.Starred = p.Starred
+		}
+	}
+	for i := range m.projects {
+		if m.projects[i].Path == p.Path {
+			m.projects[i].Starred = p.Starred
+		}
+	}
+	m.emptyResultsCached = false
+
+	if p.Starred {
+		return m.showToast("Starred "+p.Path, false)
+	}
+	return m.showToast("Unstarred "+p.Path, false)
+}
@@ -295,6 +295,20 @@ func (cs *ColorScheme) GetStyles() Styles {
 			Foreground(lipgloss.AdaptiveColor{Light: "#B8B8B8", Dark: "#4A4A4A"}).Italic(true),
 		ScoreText: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241")),
+		Counter: lipgloss.NewStyle().
+			Foreground(cs.Version),
+		Pin: lipgloss.NewStyle().
+			Foreground(cs.Prompt),
+		Fork: lipgloss.NewStyle().
+			Foreground(cs.Version),
+		Label: lipgloss.NewStyle().
+			Foreground(cs.Prompt),
+		ActivityActive: lipgloss.NewStyle().
+			Foreground(cs.StatusActive),
+		ActivityQuiet: lipgloss.NewStyle().
+			Foreground(cs.Version),
+		ActivityStale: lipgloss.NewStyle().
+			Foreground(cs.Excluded),
 	}
 }
 
@@ -331,4 +345,11 @@ type Styles struct {
 	HiddenStarredSnippet   lipgloss.Style // Very muted pale gold snippet
 	HiddenSnippet          lipgloss.Style // Very muted snippet for hidden non-starred
 	ScoreText              lipgloss.Style // Gray score text (non-starred)
+	Counter                lipgloss.Style // Muted open MR/issue counters
+	Pin                    lipgloss.Style // Pinned project marker
+	Fork                   lipgloss.Style // Fork marker
+	Label                  lipgloss.Style // Labels from annotations.yaml
+	ActivityActive         lipgloss.Style // Activity indicator of recently active projects
+	ActivityQuiet          lipgloss.Style // Activity indicator of quiet projects
+	ActivityStale          lipgloss.Style // Activity indicator of stale projects
 }
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toastDuration is how long a toast stays above the search input
+const toastDuration = 4 * time.Second
+
+// toastExpiredMsg hides the toast it was scheduled for (newer toasts have a higher version)
+type toastExpiredMsg struct {
+	version int
+}
+
+// showToast shows a transient message above the search input
+func (m *Model) showToast(text string, isError bool) tea.Cmd {
+	m.toast = text
+	m.toastError = isError
+	m.toastVersion++
+	version := m.toastVersion
+	return tea.Tick(toastDuration, func(_ time.Time) tea.Msg {
+		return toastExpiredMsg{version: version}
+	})
+}
+
+// syncToast returns the toast shown when a sync finishes: "Sync complete: +12 new, 3 updated"
+func syncToast(msg SyncCompleteMsg) string {
+	if msg.Err != nil {
+		return "Sync failed: " + msg.Err.Error()
+	}
+	if msg.Added == 0 && msg.Updated == 0 {
+		return "Sync complete: no changes"
+	}
+	return fmt.Sprintf("Sync complete: +%d new, %d updated", msg.Added, msg.Updated)
+}
+
+// renderToast renders the toast line (empty when no toast is shown)
+func (m Model) renderToast() string {
+	if m.toast == "" {
+		return ""
+	}
+	style := m.styles.StatusActive
+	if m.toastError {
+		style = m.styles.StatusError
+	}
+	if m.width > 0 {
+		style = style.MaxWidth(m.width) // Long sync errors must not wrap into the list
+	}
+	return style.Render("  " + m.toast)
+}
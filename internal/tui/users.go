@@ -0,0 +1,206 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/igusev/glf/internal/model"
+	"github.com/igusev/glf/internal/search"
+)
+
+// UsersModel is the TUI for --users: fuzzy search over the instance's cached users
+type UsersModel struct {
+	textInput      textinput.Model // Search input field
+	styles         Styles          // Pre-configured styles
+	colorScheme    *ColorScheme    // Adaptive color scheme
+	version        string          // Application version
+	users          []model.User    // All cached users
+	filtered       []model.User    // Users matching the query
+	selected       string          // Selected username (when user presses Enter or Alt+M)
+	selectedTarget string          // Page to open for the selected user ("" = profile, "mrs" = assigned MRs)
+	cursor         int             // Current cursor position in filtered list
+	viewportStart  int             // Index of first visible item
+	width          int             // Terminal width
+	height         int             // Terminal height
+	quitting       bool            // Whether user is quitting
+	showHelp       bool            // Whether to show help text
+}
+
+// NewUsers creates the user finder with an optional initial query
+func NewUsers(users []model.User, initialQuery string, version string) UsersModel {
+	colorScheme := NewColorScheme()
+	styles := colorScheme.GetStyles()
+
+	ti := textinput.New()
+	ti.Placeholder = "Search users..."
+	ti.Focus()
+	ti.CharLimit = 156
+	ti.Width = 50
+	ti.Prompt = "@> "
+	ti.PromptStyle = styles.Prompt
+	if initialQuery != "" {
+		ti.SetValue(initialQuery)
+	}
+
+	m := UsersModel{
+		textInput:   ti,
+		styles:      styles,
+		colorScheme: colorScheme,
+		version:     version,
+		users:       users,
+	}
+	m.filter()
+	return m
+}
+
+// Init initializes the model (required by tea.Model interface)
+func (m UsersModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages and updates the model
+func (m UsersModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "enter", "alt+m":
+			if len(m.filtered) > 0 && m.cursor < len(m.filtered) {
+				m.selected = m.filtered[m.cursor].Username
+				if msg.String() == "alt+m" {
+					m.selectedTarget = "mrs"
+				}
+			}
+			m.quitting = true
+			return m, tea.Quit
+
+		case "down", "ctrl+n":
+			if m.cursor < len(m.filtered)-1 {
+				m.cursor++
+				if visible := m.listHeight(); m.cursor >= m.viewportStart+visible {
+					m.viewportStart = m.cursor - visible + 1
+				}
+			}
+
+		case "up", "ctrl+p":
+			if m.cursor > 0 {
+				m.cursor--
+				if m.cursor < m.viewportStart {
+					m.viewportStart = m.cursor
+				}
+			}
+
+		case "?":
+			m.showHelp = !m.showHelp
+
+		default:
+			prevValue := m.textInput.Value()
+			m.textInput, cmd = m.textInput.Update(msg)
+			// Substring matching over a few thousand users is fast enough for every keystroke
+			if m.textInput.Value() != prevValue {
+				m.filter()
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, cmd
+}
+
+// filter applies the current query to the users
+func (m *UsersModel) filter() {
+	m.filtered = search.FilterUsers(m.users, m.textInput.Value())
+	m.cursor = 0
+	m.viewportStart = 0
+}
+
+// listHeight returns the number of lines available for the list
+func (m UsersModel) listHeight() int {
+	usedLines := 6 // Title, separator, empty, search, 2 empty
+	if m.showHelp {
+		usedLines += 3
+	}
+	if m.height-usedLines < 1 {
+		return 1
+	}
+	return m.height - usedLines
+}
+
+// View renders the TUI
+func (m UsersModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+
+	titleLeft := fmt.Sprintf("%s %s %s",
+		m.colorScheme.GitLabWave,
+		m.styles.Title.Render("glf"),
+		m.styles.Version.Render(m.version))
+	titleRight := m.styles.Count.Render(fmt.Sprintf("%d/%d users", len(m.filtered), len(m.users)))
+
+	b.WriteString(titleLeft)
+	if spacing := m.width - lipgloss.Width(titleLeft) - lipgloss.Width(titleRight); spacing > 0 {
+		b.WriteString(strings.Repeat(" ", spacing))
+	} else {
+		b.WriteString(" ")
+	}
+	b.WriteString(titleRight)
+	b.WriteString("\n")
+
+	if m.width > 0 {
+		b.WriteString(m.styles.Help.Render(strings.Repeat("─", m.width)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.textInput.View())
+	b.WriteString("\n\n")
+
+	end := m.viewportStart + m.listHeight()
+	if end > len(m.filtered) {
+		end = len(m.filtered)
+	}
+	query := strings.TrimPrefix(strings.TrimSpace(m.textInput.Value()), "@")
+	for i := m.viewportStart; i < end; i++ {
+		line := " " + renderFuzzyMatch(m.filtered[i].DisplayString(), query, m.styles.Normal, m.styles.Highlight)
+
+		if i == m.cursor {
+			b.WriteString(m.styles.Cursor.Render("▌"))
+			b.WriteString(m.styles.Selected.Width(m.width - 2).Render(line))
+		} else {
+			b.WriteString(" ")
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+
+	if m.showHelp {
+		b.WriteString("\n\n")
+		b.WriteString(m.styles.Help.Render("↑/↓: navigate • enter: open profile • alt+m: assigned merge requests • esc: quit • ?: toggle help"))
+	}
+
+	return b.String()
+}
+
+// Selected returns the selected username (or empty string if none)
+func (m UsersModel) Selected() string {
+	return m.selected
+}
+
+// SelectedTarget returns the page to open for the selected user ("" = profile, "mrs" = assigned MRs)
+func (m UsersModel) SelectedTarget() string {
+	return m.selectedTarget
+}
@@ -0,0 +1,334 @@
+// Package update implements self-updating from GitHub releases
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the GitHub API endpoint for the latest glf release
+const DefaultAPIURL = "https://api.github.com/repos/igusev/glf/releases/latest"
+
+// checksumsFile is the goreleaser checksum file published with every release
+const checksumsFile = "checksums.txt"
+
+// maxBinarySize guards against decompressing unexpectedly large archives
+const maxBinarySize = 200 << 20
+
+// ErrDevBuild is returned when the running binary has no release version to compare against
+var ErrDevBuild = errors.New("development build: install a release binary to use --update")
+
+// Release is a published glf release
+type Release struct {
+	Version string  // Version without the "v" prefix (e.g., "1.4.0")
+	Assets  []Asset // Downloadable files
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string // File name (e.g., "glf-1.4.0-linux_amd64.tar.gz")
+	URL  string // Download URL
+}
+
+// Updater checks for and installs new releases
+type Updater struct {
+	APIURL string       // Latest release endpoint (DefaultAPIURL unless testing)
+	Client *http.Client // HTTP client for API and downloads
+	GOOS   string       // Target OS (runtime.GOOS unless testing)
+	GOARCH string       // Target architecture (runtime.GOARCH unless testing)
+	GOARM  string       // ARM version for GOARCH=arm (from build info)
+}
+
+// New creates an updater for the running platform
+func New(timeout time.Duration) *Updater {
+	return &Updater{
+		APIURL: DefaultAPIURL,
+		Client: &http.Client{Timeout: timeout},
+		GOOS:   runtime.GOOS,
+		GOARCH: runtime.GOARCH,
+		GOARM:  buildSetting("GOARM"),
+	}
+}
+
+// buildSetting returns a setting recorded in the binary's build info (empty if unknown)
+func buildSetting(key string) string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, s := range info.Settings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+// Latest fetches the latest published release
+func (u *Updater) Latest() (*Release, error) {
+	req, err := http.NewRequest(http.MethodGet, u.APIURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to check for updates: GitHub returned %s", resp.Status)
+	}
+
+	var payload struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+
+	release := &Release{Version: strings.TrimPrefix(payload.TagName, "v")}
+	for _, a := range payload.Assets {
+		release.Assets = append(release.Assets, Asset{Name: a.Name, URL: a.URL})
+	}
+	return release, nil
+}
+
+// IsNewer reports whether latest is a newer version than current
+// Versions are compared numerically by dot-separated components; a pre-release
+// ("1.4.0-rc1") is older than the release with the same number
+func IsNewer(current, latest string) bool {
+	return compareVersions(strings.TrimPrefix(latest, "v"), strings.TrimPrefix(current, "v")) > 0
+}
+
+// compareVersions compares two versions, returning -1, 0 or 1
+func compareVersions(a, b string) int {
+	aCore, aPre, _ := strings.Cut(a, "-")
+	bCore, bPre, _ := strings.Cut(b, "-")
+
+	aParts := strings.Split(aCore, ".")
+	bParts := strings.Split(bCore, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var x, y int
+		if i < len(aParts) {
+			x, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			y, _ = strconv.Atoi(bParts[i])
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre > bPre:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// ArchiveName returns the release archive name for the platform (matches .goreleaser.yml)
+func (u *Updater) ArchiveName(version string) string {
+	arch := u.GOARCH
+	if arch == "arm" && u.GOARM != "" {
+		arch += "v" + u.GOARM
+	}
+	ext := "tar.gz"
+	if u.GOOS == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("glf-%s-%s_%s.%s", version, u.GOOS, arch, ext)
+}
+
+// Install downloads the platform archive of release, verifies its SHA-256 checksum
+// against checksums.txt, and atomically replaces the binary at exePath
+func (u *Updater) Install(release *Release, exePath string) error {
+	archiveName := u.ArchiveName(release.Version)
+	archiveAsset, ok := findAsset(release.Assets, archiveName)
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s/%s (expected %s)", release.Version, u.GOOS, u.GOARCH, archiveName)
+	}
+	checksumsAsset, ok := findAsset(release.Assets, checksumsFile)
+	if !ok {
+		return fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.Version, checksumsFile)
+	}
+
+	checksums, err := u.download(checksumsAsset.URL)
+	if err != nil {
+		return err
+	}
+	expected, err := checksumFor(checksums, archiveName)
+	if err != nil {
+		return err
+	}
+
+	archive, err := u.download(archiveAsset.URL)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(archive)
+	if actual := hex.EncodeToString(sum[:]); actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", archiveName, expected, actual)
+	}
+
+	binary, err := extractBinary(archive, u.GOOS == "windows")
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", archiveName, err)
+	}
+
+	return replaceBinary(exePath, binary)
+}
+
+// findAsset looks up a release asset by name
+func findAsset(assets []Asset, name string) (Asset, bool) {
+	for _, a := range assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// download fetches a release asset into memory
+func (u *Updater) download(url string) ([]byte, error) {
+	resp, err := u.Client.Get(url) //nolint:gosec,noctx // URL comes from the GitHub release API
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBinarySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	return data, nil
+}
+
+// checksumFor finds the SHA-256 of name in a "<hex>  <name>" checksum file
+func checksumFor(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s in %s", name, checksumsFile)
+}
+
+// extractBinary returns the glf executable from a tar.gz (or zip on Windows) archive
+func extractBinary(archive []byte, isZip bool) ([]byte, error) {
+	if isZip {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if filepath.Base(f.Name) != "glf.exe" {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer func() { _ = rc.Close() }()
+			return io.ReadAll(io.LimitReader(rc, maxBinarySize))
+		}
+		return nil, errors.New("glf.exe not found in archive")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.New("glf binary not found in archive")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == "glf" {
+			return io.ReadAll(io.LimitReader(tr, maxBinarySize))
+		}
+	}
+}
+
+// replaceBinary atomically replaces exePath with binary
+// The new binary is written next to the old one and renamed over it, so an
+// interrupted update never leaves a partial executable. Windows cannot overwrite
+// a running executable, so the old one is moved aside first.
+func replaceBinary(exePath string, binary []byte) error {
+	dir := filepath.Dir(exePath)
+	tmp, err := os.CreateTemp(dir, ".glf-update-*")
+	if err != nil {
+		return fmt.Errorf("cannot write to %s (try running with sufficient permissions): %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // No-op after a successful rename
+
+	if _, err := tmp.Write(binary); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0755); err != nil { //nolint:gosec // Executables must be world-executable
+		return fmt.Errorf("failed to make new binary executable: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		oldPath := exePath + ".old"
+		_ = os.Remove(oldPath)
+		if err := os.Rename(exePath, oldPath); err != nil {
+			return fmt.Errorf("failed to move old binary aside: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, exePath); err != nil {
+		return fmt.Errorf("failed to replace binary: %w", err)
+	}
+	return nil
+}
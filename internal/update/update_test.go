@@ -0,0 +1,198 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"1.3.0", "1.4.0", true},
+		{"v1.3.0", "v1.3.1", true},
+		{"1.9.0", "1.10.0", true},
+		{"1.4.0", "1.4.0", false},
+		{"1.4.1", "1.4.0", false},
+		{"1.4.0-rc1", "1.4.0", true},
+		{"1.4.0", "1.4.0-rc1", false},
+		{"1.4", "1.4.1", true},
+	}
+	for _, tt := range tests {
+		if got := IsNewer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		goos, goarch, goarm string
+		want                string
+	}{
+		{"linux", "amd64", "", "glf-1.4.0-linux_amd64.tar.gz"},
+		{"darwin", "arm64", "", "glf-1.4.0-darwin_arm64.tar.gz"},
+		{"linux", "arm", "7", "glf-1.4.0-linux_armv7.tar.gz"},
+		{"windows", "amd64", "", "glf-1.4.0-windows_amd64.zip"},
+	}
+	for _, tt := range tests {
+		u := &Updater{GOOS: tt.goos, GOARCH: tt.goarch, GOARM: tt.goarm}
+		if got := u.ArchiveName("1.4.0"); got != tt.want {
+			t.Errorf("ArchiveName(%s/%s) = %q, want %q", tt.goos, tt.goarch, got, tt.want)
+		}
+	}
+}
+
+// tarGz builds a release archive containing README.md and the glf binary
+func tarGz(t *testing.T, binary []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range map[string][]byte{"README.md": []byte("readme"), "glf": binary} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// releaseServer serves a fake GitHub release API with the given archive and checksum
+func releaseServer(t *testing.T, archiveName string, archive []byte, checksum string) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases/latest":
+			fmt.Fprintf(w, `{"tag_name": "v1.4.0", "assets": [
+				{"name": %q, "browser_download_url": "%s/download/archive"},
+				{"name": "checksums.txt", "browser_download_url": "%s/download/checksums"}
+			]}`, archiveName, server.URL, server.URL)
+		case "/download/archive":
+			w.Write(archive)
+		case "/download/checksums":
+			fmt.Fprintf(w, "%s  glf-1.4.0-other_arch.tar.gz\n%s  %s\n", strings.Repeat("0", 64), checksum, archiveName)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestInstall(t *testing.T) {
+	newBinary := []byte("#!/bin/sh\necho glf 1.4.0\n")
+	archive := tarGz(t, newBinary)
+	sum := sha256.Sum256(archive)
+
+	u := &Updater{Client: http.DefaultClient, GOOS: "linux", GOARCH: "amd64"}
+	server := releaseServer(t, u.ArchiveName("1.4.0"), archive, hex.EncodeToString(sum[:]))
+	defer server.Close()
+	u.APIURL = server.URL + "/releases/latest"
+
+	release, err := u.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	if release.Version != "1.4.0" || len(release.Assets) != 2 {
+		t.Fatalf("Unexpected release: %+v", release)
+	}
+
+	exePath := filepath.Join(t.TempDir(), "glf")
+	if err := os.WriteFile(exePath, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := u.Install(release, exePath); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	got, err := os.ReadFile(exePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, newBinary) {
+		t.Errorf("Binary was not replaced, got %q", got)
+	}
+	info, _ := os.Stat(exePath)
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected executable binary, got mode %v", info.Mode())
+	}
+
+	// No temporary files are left behind
+	entries, _ := os.ReadDir(filepath.Dir(exePath))
+	if len(entries) != 1 {
+		t.Errorf("Expected only the binary in the directory, got %d entries", len(entries))
+	}
+}
+
+func TestInstall_ChecksumMismatch(t *testing.T) {
+	archive := tarGz(t, []byte("tampered"))
+
+	u := &Updater{Client: http.DefaultClient, GOOS: "linux", GOARCH: "amd64"}
+	server := releaseServer(t, u.ArchiveName("1.4.0"), archive, strings.Repeat("a", 64))
+	defer server.Close()
+	u.APIURL = server.URL + "/releases/latest"
+
+	release, err := u.Latest()
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+
+	exePath := filepath.Join(t.TempDir(), "glf")
+	if err := os.WriteFile(exePath, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err = u.Install(release, exePath)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Expected checksum mismatch, got %v", err)
+	}
+	if got, _ := os.ReadFile(exePath); string(got) != "old" {
+		t.Error("Binary must not change when verification fails")
+	}
+}
+
+func TestInstall_MissingPlatform(t *testing.T) {
+	u := &Updater{Client: http.DefaultClient, GOOS: "plan9", GOARCH: "amd64"}
+	release := &Release{Version: "1.4.0", Assets: []Asset{{Name: "checksums.txt"}}}
+	if err := u.Install(release, filepath.Join(t.TempDir(), "glf")); err == nil {
+		t.Error("Expected error for a platform without a release binary")
+	}
+}
+
+func TestExtractBinary_Zip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("glf.exe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("MZ binary"))
+	zw.Close()
+
+	got, err := extractBinary(buf.Bytes(), true)
+	if err != nil || string(got) != "MZ binary" {
+		t.Errorf("extractBinary(zip) = %q, %v", got, err)
+	}
+}
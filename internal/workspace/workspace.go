@@ -0,0 +1,179 @@
+// Package workspace maps GitLab project paths to local clones under a workspace directory
+package workspace
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Layout describes where clones live inside the workspace directory
+type Layout string
+
+const (
+	// LayoutNested mirrors the GitLab namespace: workspace_dir/group/subgroup/project (default)
+	LayoutNested Layout = "nested"
+	// LayoutFlat puts every clone directly in the workspace: workspace_dir/project
+	LayoutFlat Layout = "flat"
+)
+
+// maxScanDepth limits how deep Scan looks for clones below the workspace directory
+const maxScanDepth = 6
+
+// skipDirs are never descended into while scanning (dependency and build trees)
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"build":        true,
+	"dist":         true,
+}
+
+// ParseLayout validates a workspace_layout value; empty means LayoutNested
+func ParseLayout(name string) (Layout, error) {
+	switch Layout(strings.ToLower(strings.TrimSpace(name))) {
+	case "", LayoutNested:
+		return LayoutNested, nil
+	case LayoutFlat:
+		return LayoutFlat, nil
+	}
+	return "", fmt.Errorf("unknown workspace layout %q (expected nested or flat)", name)
+}
+
+// RemoteResolver maps a clone's origin URL to a GitLab project path
+// ok is false for remotes that don't belong to the configured GitLab instance
+type RemoteResolver func(remoteURL string) (projectPath string, ok bool)
+
+// Workspace finds the local clones of GitLab projects
+// Clones are looked up at their layout location first; clones elsewhere in the
+// workspace are found by scanning for repositories whose origin is the project
+type Workspace struct {
+	dir     string
+	layout  Layout
+	resolve RemoteResolver
+	clones  map[string]string // Scanned clones by project path (nil until the first scan)
+}
+
+// New creates a workspace rooted at dir
+// resolve may be nil, in which case only the layout location is checked
+func New(dir string, layout Layout, resolve RemoteResolver) *Workspace {
+	return &Workspace{dir: dir, layout: layout, resolve: resolve}
+}
+
+// Path returns where a project is cloned according to the layout (whether or not it exists)
+func (w *Workspace) Path(projectPath string) string {
+	if w.layout == LayoutFlat {
+		return filepath.Join(w.dir, path.Base(projectPath))
+	}
+	return filepath.Join(w.dir, filepath.FromSlash(projectPath))
+}
+
+// Find returns the local clone of a project
+// The layout location must be a clone of the project itself when a resolver is set
+// (a flat "api" directory may belong to another group's api)
+func (w *Workspace) Find(projectPath string) (string, bool) {
+	if dir := w.Path(projectPath); IsRepo(dir) && w.owns(dir, projectPath) {
+		return dir, true
+	}
+	if w.resolve == nil {
+		return "", false
+	}
+	if w.clones == nil {
+		w.clones = w.Scan()
+	}
+	dir, ok := w.clones[projectPath]
+	return dir, ok
+}
+
+// owns reports whether the clone in dir belongs to projectPath
+// Clones without a resolvable origin are trusted (their location is all we know)
+func (w *Workspace) owns(dir, projectPath string) bool {
+	if w.resolve == nil {
+		return true
+	}
+	remoteURL, err := OriginURL(dir)
+	if err != nil || remoteURL == "" {
+		return true
+	}
+	resolved, ok := w.resolve(remoteURL)
+	return !ok || resolved == projectPath
+}
+
+// Scan walks the workspace directory and maps project paths to clones by their origin remote
+// Repositories are not descended into; unreadable directories are skipped.
+// When a project is cloned more than once, the shallowest clone wins.
+func (w *Workspace) Scan() map[string]string {
+	clones := make(map[string]string)
+	if w.resolve == nil {
+		return clones
+	}
+
+	root := filepath.Clean(w.dir)
+	_ = filepath.WalkDir(root, func(dir string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return nil // Skip unreadable entries, keep walking
+		}
+		if dir != root {
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || skipDirs[name] {
+				return filepath.SkipDir
+			}
+			if strings.Count(strings.TrimPrefix(dir, root), string(filepath.Separator)) > maxScanDepth {
+				return filepath.SkipDir
+			}
+		}
+		if !IsRepo(dir) {
+			return nil
+		}
+
+		if remoteURL, err := OriginURL(dir); err == nil {
+			if projectPath, ok := w.resolve(remoteURL); ok {
+				if _, seen := clones[projectPath]; !seen {
+					clones[projectPath] = dir
+				}
+			}
+		}
+		return filepath.SkipDir
+	})
+	return clones
+}
+
+// IsRepo reports whether dir is a Git working tree (.git directory or worktree file)
+func IsRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// OriginURL reads the origin remote URL from a clone's .git/config without running git
+// Worktrees (a .git file) return an empty URL
+func OriginURL(dir string) (string, error) {
+	file, err := os.Open(filepath.Join(dir, ".git", "config")) // #nosec G304 -- path inside the user's workspace
+	if err != nil {
+		if info, statErr := os.Stat(filepath.Join(dir, ".git")); statErr == nil && !info.IsDir() {
+			return "", nil
+		}
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		if key, value, found := strings.Cut(line, "="); found && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", scanner.Err()
+}
@@ -0,0 +1,121 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeClone creates a fake clone at dir with the given origin URL (none if empty)
+func makeClone(t *testing.T, dir, origin string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "[core]\n\tbare = false\n"
+	if origin != "" {
+		config += "[remote \"upstream\"]\n\turl = git@other.com:fork.git\n[remote \"origin\"]\n\turl = " + origin + "\n\tfetch = +refs/heads/*:refs/remotes/origin/*\n"
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git", "config"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testResolver accepts git@gitlab.example.com remotes
+func testResolver(remoteURL string) (string, bool) {
+	rest, ok := strings.CutPrefix(remoteURL, "git@gitlab.example.com:")
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSuffix(rest, ".git"), true
+}
+
+func TestParseLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Layout
+		wantErr bool
+	}{
+		{"", LayoutNested, false},
+		{"nested", LayoutNested, false},
+		{"Flat", LayoutFlat, false},
+		{"tree", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLayout(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLayout(%q) = %q, %v; want %q (error %v)", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	nested := New("/src", LayoutNested, nil)
+	if got, want := nested.Path("company/platform/api"), filepath.Join("/src", "company", "platform", "api"); got != want {
+		t.Errorf("nested Path = %q, want %q", got, want)
+	}
+	flat := New("/src", LayoutFlat, nil)
+	if got, want := flat.Path("company/platform/api"), filepath.Join("/src", "api"); got != want {
+		t.Errorf("flat Path = %q, want %q", got, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	makeClone(t, filepath.Join(root, "company", "platform", "api"), "git@gitlab.example.com:company/platform/api.git")
+	makeClone(t, filepath.Join(root, "work", "web-app"), "git@gitlab.example.com:company/frontend/web.git")
+	makeClone(t, filepath.Join(root, "company", "billing"), "git@gitlab.example.com:company/payments.git") // Renamed on GitLab
+	makeClone(t, filepath.Join(root, "company", "notes"), "")                                              // No origin
+	makeClone(t, filepath.Join(root, "github", "tool"), "git@github.com:someone/tool.git")
+	makeClone(t, filepath.Join(root, "node_modules", "dep"), "git@gitlab.example.com:company/dep.git")
+
+	ws := New(root, LayoutNested, testResolver)
+	tests := []struct {
+		project string
+		want    string // Relative to root; empty = not found
+	}{
+		{"company/platform/api", "company/platform/api"}, // Layout location
+		{"company/frontend/web", "work/web-app"},         // Found by scan
+		{"company/payments", "company/billing"},          // Found by scan
+		{"company/billing", ""},                          // Layout location holds another project
+		{"company/notes", "company/notes"},               // No origin: location is trusted
+		{"someone/tool", ""},                             // Not on the configured GitLab
+		{"company/dep", ""},                              // Dependency trees are skipped
+	}
+	for _, tt := range tests {
+		got, ok := ws.Find(tt.project)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("Find(%q) = %q, expected no clone", tt.project, got)
+			}
+			continue
+		}
+		if want := filepath.Join(root, filepath.FromSlash(tt.want)); !ok || got != want {
+			t.Errorf("Find(%q) = %q, %v; want %q", tt.project, got, ok, want)
+		}
+	}
+
+	// Without a resolver only the layout location counts
+	if _, ok := New(root, LayoutNested, nil).Find("company/frontend/web"); ok {
+		t.Error("Expected no scan without a resolver")
+	}
+}
+
+func TestOriginURL(t *testing.T) {
+	dir := t.TempDir()
+	makeClone(t, dir, "https://gitlab.example.com/group/api.git")
+	got, err := OriginURL(dir)
+	if err != nil || got != "https://gitlab.example.com/group/api.git" {
+		t.Errorf("OriginURL = %q, %v", got, err)
+	}
+
+	// Worktrees have a .git file
+	worktree := t.TempDir()
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: /elsewhere\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := OriginURL(worktree); err != nil || got != "" {
+		t.Errorf("OriginURL(worktree) = %q, %v; want empty", got, err)
+	}
+}